@@ -0,0 +1,164 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdAuditList = &cobra.Command{
+	Use: "list",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := auditList(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "list audit events (all events for admins, organization events for organization owners)",
+}
+
+type auditListOptions struct {
+	orgname    string
+	actor      string
+	actions    []string
+	targetKind string
+	targetID   string
+	since      string
+	until      string
+	limit      uint
+}
+
+var auditListOpts auditListOptions
+
+func init() {
+	flags := cmdAuditList.Flags()
+
+	flags.StringVar(&auditListOpts.orgname, "orgname", "", "only list audit events of this organization")
+	flags.StringVar(&auditListOpts.actor, "actor", "", "filter audit events executed by this user")
+	flags.StringSliceVar(&auditListOpts.actions, "action", nil, "filter audit events matching the provided action. This option can be repeated multiple times")
+	flags.StringVar(&auditListOpts.targetKind, "targetkind", "", "filter audit events by target object kind")
+	flags.StringVar(&auditListOpts.targetID, "targetid", "", "filter audit events by target object id")
+	flags.StringVar(&auditListOpts.since, "since", "", "only list audit events created at or after this time (RFC3339)")
+	flags.StringVar(&auditListOpts.until, "until", "", "only list audit events created before this time (RFC3339)")
+	flags.UintVar(&auditListOpts.limit, "limit", 50, "max number of audit events to show")
+
+	cmdAudit.AddCommand(cmdAuditList)
+}
+
+func parseAuditTime(name, v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot parse %q", name)
+	}
+	return &t, nil
+}
+
+func printAuditEvents(auditEvents []*gwapitypes.AuditEventResponse) {
+	for _, e := range auditEvents {
+		target := e.TargetName
+		if e.TargetID != "" {
+			target = fmt.Sprintf("%s (%s)", e.TargetName, e.TargetID)
+		}
+		fmt.Printf("%s: Actor: %s, Action: %s, Target: %s %s", e.CreationTime.Format(time.RFC3339), e.ActorUserName, e.Action, e.TargetKind, target)
+		if len(e.Details) > 0 {
+			keys := make([]string, 0, len(e.Details))
+			for k := range e.Details {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			details := make([]string, len(keys))
+			for i, k := range keys {
+				details[i] = fmt.Sprintf("%s=%s", k, e.Details[k])
+			}
+			fmt.Printf(", Details: %s", strings.Join(details, " "))
+		}
+		if e.RequestMetadata.RemoteAddr != "" {
+			fmt.Printf(", RemoteAddr: %s", e.RequestMetadata.RemoteAddr)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func auditList(cmd *cobra.Command, args []string) error {
+	since, err := parseAuditTime("since", auditListOpts.since)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	until, err := parseAuditTime("until", auditListOpts.until)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	limit := int(auditListOpts.limit)
+	count := 0
+	var cursor string
+	for {
+		var opts *gwclient.AuditEventsOptions
+		if cursor == "" {
+			opts = &gwclient.AuditEventsOptions{
+				ListOptions:  &gwclient.ListOptions{SortDirection: gwapitypes.SortDirectionDesc},
+				ActorUserRef: auditListOpts.actor,
+				Actions:      auditListOpts.actions,
+				TargetKind:   auditListOpts.targetKind,
+				TargetID:     auditListOpts.targetID,
+				Since:        since,
+				Until:        until,
+			}
+		} else {
+			opts = &gwclient.AuditEventsOptions{ListOptions: &gwclient.ListOptions{Cursor: cursor}}
+		}
+		opts.Limit = limit - count
+
+		var auditEvents []*gwapitypes.AuditEventResponse
+		var resp *gwclient.Response
+		if auditListOpts.orgname != "" {
+			auditEvents, resp, err = gwClient.GetOrgAuditEvents(context.TODO(), auditListOpts.orgname, opts)
+		} else {
+			auditEvents, resp, err = gwClient.GetAuditEvents(context.TODO(), opts)
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		cursor = resp.Cursor
+
+		count += len(auditEvents)
+		if count >= limit {
+			cursor = ""
+		}
+
+		printAuditEvents(auditEvents)
+
+		if cursor == "" {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var cmdBackup = &cobra.Command{
+	Use: "backup",
+	Run: func(c *cobra.Command, args []string) {
+		if err := c.Help(); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "manage the services database backups",
+	Long: `manage the services database backups
+
+Only the configstore and runservice databases are backed up. The notification
+service database isn't backed up since it only contains the state of the
+notifications deliveries: a new empty database will deliver the notifications
+of the run events still available in the runservice.`,
+}
+
+type backupOptions struct {
+	servicename string
+}
+
+var backupOpts backupOptions
+
+func init() {
+	flags := cmdBackup.PersistentFlags()
+
+	flags.StringVar(&backupOpts.servicename, "service", "", "service name (configstore or runservice)")
+
+	if err := cmdBackup.MarkPersistentFlagRequired("service"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdAgola.AddCommand(cmdBackup)
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdBackupList = &cobra.Command{
+	Use: "list",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := backupList(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "list the service database backups",
+}
+
+func init() {
+	cmdBackup.AddCommand(cmdBackupList)
+}
+
+func printBackups(backups []*gwapitypes.BackupResponse) {
+	for _, b := range backups {
+		fmt.Printf("%s: Time: %s, Size: %d\n", b.ID, b.Time.Format(time.RFC3339), b.Size)
+	}
+}
+
+func backupList(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	backups, _, err := gwClient.GetBackups(context.TODO(), backupOpts.servicename)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get backups")
+	}
+	printBackups(backups)
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdBackupRestore = &cobra.Command{
+	Use: "restore",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := backupRestore(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "restore a service database backup",
+	Long: `restore a service database backup
+
+The service must be in maintenance mode and its database must be empty.`,
+}
+
+type backupRestoreOptions struct {
+	backupID string
+}
+
+var backupRestoreOpts backupRestoreOptions
+
+func init() {
+	flags := cmdBackupRestore.Flags()
+
+	flags.StringVar(&backupRestoreOpts.backupID, "id", "", "backup id")
+
+	if err := cmdBackupRestore.MarkFlagRequired("id"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdBackup.AddCommand(cmdBackupRestore)
+}
+
+func backupRestore(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	if _, err := gwClient.RestoreBackup(context.TODO(), backupOpts.servicename, backupRestoreOpts.backupID); err != nil {
+		return errors.Wrapf(err, "failed to restore backup %q", backupRestoreOpts.backupID)
+	}
+
+	return nil
+}
@@ -0,0 +1,285 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+
+	"github.com/ghodss/yaml"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	"agola.io/agola/internal/config"
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/agolagit"
+	"agola.io/agola/internal/runconfig"
+	itypes "agola.io/agola/internal/services/types"
+	"agola.io/agola/internal/util"
+	rstypes "agola.io/agola/services/runservice/types"
+	"agola.io/agola/services/types"
+)
+
+var cmdConfig = &cobra.Command{
+	Use:   "config",
+	Short: "run configuration",
+}
+
+func init() {
+	cmdAgola.AddCommand(cmdConfig)
+}
+
+// defaultConfigFiles are the run config files searched, in order, when no
+// config file is provided. They are the same files searched by the gateway.
+var defaultConfigFiles = []string{".agola/config.star", ".agola/config.jsonnet", ".agola/config.json", ".agola/config.yml"}
+
+type configContextOptions struct {
+	file          string
+	branch        string
+	tag           string
+	ref           string
+	pullRequestID string
+	commitSHA     string
+	prRefRegexes  []string
+	vars          []string
+	varFiles      []string
+
+	pullRequestLabels       []string
+	pullRequestTargetBranch string
+	pullRequestDraft        bool
+}
+
+func addConfigContextFlags(cmd *cobra.Command, o *configContextOptions) {
+	flags := cmd.Flags()
+
+	flags.StringVarP(&o.file, "file", "f", "", fmt.Sprintf("run config file. If not provided the first existing file in %q is used", defaultConfigFiles))
+	flags.StringVar(&o.branch, "branch", "master", "branch of the config context")
+	flags.StringVar(&o.tag, "tag", "", "tag of the config context")
+	flags.StringVar(&o.ref, "ref", "", `ref of the config context (i.e  "refs/heads/master" for a branch, "refs/tags/v1.0" for a tag)`)
+	flags.StringVar(&o.pullRequestID, "pull-request", "", `pull request id of the config context (the ref will be "refs/pull/$ID/head")`)
+	flags.StringVar(&o.commitSHA, "commit-sha", "", "commit sha of the config context")
+	flags.StringArrayVar(&o.pullRequestLabels, "pull-request-label", []string{}, "pull request label of the config context. This option can be repeated multiple times")
+	flags.StringVar(&o.pullRequestTargetBranch, "pull-request-target-branch", "", "pull request target branch of the config context")
+	flags.BoolVar(&o.pullRequestDraft, "pull-request-draft", false, "the config context pull request is a draft")
+	flags.StringArrayVar(&o.prRefRegexes, "pull-request-ref-regexes", []string{`refs/pull/(\d+)/head`, `refs/merge-requests/(\d+)/head`}, `regular expression to determine if a ref is a pull request`)
+	flags.StringArrayVar(&o.vars, "var", []string{}, `list of variables (name=value) used to generate the run tasks. This option can be repeated multiple times`)
+	flags.StringArrayVar(&o.varFiles, "var-file", []string{}, `yaml file containing the variables as a yaml/json map. This option can be repeated multiple times`)
+}
+
+// configRun is a config run with its generated run config tasks.
+type configRun struct {
+	Name string `json:"name"`
+	// Skip reports if the run won't be created since its when conditions don't
+	// match the config context
+	Skip  bool                     `json:"skip"`
+	Tasks []*rstypes.RunConfigTask `json:"tasks"`
+	Error string                   `json:"error,omitempty"`
+}
+
+// parsedConfig is a run config parsed using the provided config context.
+type parsedConfig struct {
+	filename      string
+	configContext *config.ConfigContext
+	variables     map[string]string
+	config        *config.Config
+}
+
+func (o *configContextOptions) configFile() (string, error) {
+	if o.file != "" {
+		return o.file, nil
+	}
+
+	for _, f := range defaultConfigFiles {
+		if _, err := os.Stat(f); err == nil {
+			return f, nil
+		}
+	}
+
+	return "", errors.Errorf("no run config file found in %q", defaultConfigFiles)
+}
+
+func (o *configContextOptions) genConfigContext(cmd *cobra.Command) (*config.ConfigContext, error) {
+	flags := cmd.Flags()
+
+	set := 0
+	for _, f := range []string{"branch", "tag", "ref", "pull-request"} {
+		if flags.Changed(f) {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.Errorf(`only one of "--branch", "--tag", "--ref" or "--pull-request" can be provided`)
+	}
+
+	ref := o.ref
+	switch {
+	case flags.Changed("tag"):
+		ref = "refs/tags/" + o.tag
+	case flags.Changed("pull-request"):
+		ref = fmt.Sprintf("refs/pull/%s/head", o.pullRequestID)
+	case !flags.Changed("ref"):
+		ref = "refs/heads/" + o.branch
+	}
+
+	prRefRegexes := []*regexp.Regexp{}
+	for _, res := range o.prRefRegexes {
+		re, err := regexp.Compile(res)
+		if err != nil {
+			return nil, errors.Wrapf(err, "wrong pull request regular expression %q", res)
+		}
+		prRefRegexes = append(prRefRegexes, re)
+	}
+
+	gitRefType, name, err := agolagit.New("", prRefRegexes).RefType(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get refType for ref %q", ref)
+	}
+
+	cc := &config.ConfigContext{
+		Ref:       ref,
+		CommitSHA: o.commitSHA,
+	}
+	switch gitRefType {
+	case gitsource.RefTypeBranch:
+		cc.RefType = itypes.RunRefTypeBranch
+		cc.Branch = name
+	case gitsource.RefTypeTag:
+		cc.RefType = itypes.RunRefTypeTag
+		cc.Tag = name
+	case gitsource.RefTypePullRequest:
+		cc.RefType = itypes.RunRefTypePullRequest
+		cc.PullRequestID = name
+		cc.PullRequestLabels = o.pullRequestLabels
+		cc.PullRequestTargetBranch = o.pullRequestTargetBranch
+		cc.PullRequestDraft = o.pullRequestDraft
+	}
+
+	return cc, nil
+}
+
+func (o *configContextOptions) genVariables() (map[string]string, error) {
+	variables := map[string]string{}
+
+	for _, varFile := range o.varFiles {
+		data, err := os.ReadFile(varFile)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if err := yaml.Unmarshal(data, &variables); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal values")
+		}
+	}
+
+	for _, variable := range o.vars {
+		varname, varvalue, err := parseVariable(variable)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		variables[varname] = varvalue
+	}
+
+	return variables, nil
+}
+
+// parseConfig parses the run config file using the config context defined by
+// the command flags.
+func (o *configContextOptions) parseConfig(cmd *cobra.Command) (*parsedConfig, error) {
+	filename, err := o.configFile()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var configFormat config.ConfigFormat
+	switch path.Ext(filename) {
+	case ".star":
+		configFormat = config.ConfigFormatStarlark
+	case ".jsonnet":
+		configFormat = config.ConfigFormatJsonnet
+	case ".json", ".yml", ".yaml":
+		configFormat = config.ConfigFormatJSON
+	default:
+		return nil, errors.Errorf("unknown run config file format for file %q", filename)
+	}
+
+	cc, err := o.genConfigContext(cmd)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	variables, err := o.genVariables()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	c, err := config.ParseConfigFile(filepath.Base(filename), data, configFormat, cc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", filename)
+	}
+
+	return &parsedConfig{
+		filename:      filename,
+		configContext: cc,
+		variables:     variables,
+		config:        c,
+	}, nil
+}
+
+// genConfigRuns generates the run config tasks of every config run like done
+// by the gateway and the runservice when creating a run. Errors in the run
+// config tasks are reported in the related configRun (they'll become run setup
+// errors).
+func genConfigRuns(pc *parsedConfig) []*configRun {
+	cc := pc.configContext
+
+	runs := []*configRun{}
+	for _, run := range pc.config.Runs {
+		cr := &configRun{
+			Name: run.Name,
+			Skip: !types.MatchWhen(run.When.ToWhen(), cc.RefType, cc.Branch, cc.Tag, cc.Ref, cc.WhenPullRequest()),
+		}
+
+		rcts := runconfig.GenRunConfigTasks(util.DefaultUUIDGenerator{}, pc.config, run.Name, pc.variables, cc.RefType, cc.Branch, cc.Tag, cc.Ref, cc.WhenPullRequest())
+		if err := runconfig.CheckRunConfigTasks(rcts); err != nil {
+			cr.Error = err.Error()
+		} else if err := runconfig.GenTasksLevels(rcts); err != nil {
+			cr.Error = err.Error()
+		}
+
+		for _, rct := range rcts {
+			cr.Tasks = append(cr.Tasks, rct)
+		}
+		slices.SortFunc(cr.Tasks, func(a, b *rstypes.RunConfigTask) int {
+			if n := cmp.Compare(a.Level, b.Level); n != 0 {
+				return n
+			}
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		runs = append(runs, cr)
+	}
+
+	return runs
+}
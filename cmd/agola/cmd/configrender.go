@@ -0,0 +1,168 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+var cmdConfigRender = &cobra.Command{
+	Use: "render",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := configRender(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "render the runs and tasks generated by a run config file using the provided config context",
+}
+
+type configRenderOptions struct {
+	configContextOptions
+	format string
+}
+
+var configRenderOpts configRenderOptions
+
+func init() {
+	flags := cmdConfigRender.Flags()
+
+	addConfigContextFlags(cmdConfigRender, &configRenderOpts.configContextOptions)
+	flags.StringVar(&configRenderOpts.format, "format", outputFormatText, `output format ("text" or "json")`)
+
+	cmdConfig.AddCommand(cmdConfigRender)
+}
+
+func configRender(cmd *cobra.Command, args []string) error {
+	if err := checkOutputFormat(configRenderOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+
+	pc, err := configRenderOpts.parseConfig(cmd)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	runs := genConfigRuns(pc)
+
+	if configRenderOpts.format == outputFormatJSON {
+		return printJSON(runs)
+	}
+
+	for _, run := range runs {
+		printConfigRun(os.Stdout, run)
+	}
+
+	return nil
+}
+
+func printConfigRun(w io.Writer, run *configRun) {
+	fmt.Fprintf(w, "Run: %s", run.Name)
+	if run.Skip {
+		fmt.Fprintf(w, " (skipped since its when conditions don't match)")
+	}
+	fmt.Fprintln(w)
+	if run.Error != "" {
+		fmt.Fprintf(w, "\tSetup error: %s\n", run.Error)
+	}
+
+	taskNames := map[string]string{}
+	for _, rct := range run.Tasks {
+		taskNames[rct.ID] = rct.Name
+	}
+
+	for _, rct := range run.Tasks {
+		fmt.Fprintf(w, "\tTask: %s, Level: %d", rct.Name, rct.Level)
+		if rct.Skip {
+			fmt.Fprintf(w, " (skipped since its when conditions don't match)")
+		}
+		fmt.Fprintln(w)
+
+		if rct.Runtime != nil {
+			images := []string{}
+			for _, c := range rct.Runtime.Containers {
+				images = append(images, c.Image)
+			}
+			fmt.Fprintf(w, "\t\tRuntime: %s, Containers: %s", rct.Runtime.Type, strings.Join(images, ", "))
+			if rct.Runtime.Arch != "" {
+				fmt.Fprintf(w, ", Arch: %s", rct.Runtime.Arch)
+			}
+			fmt.Fprintln(w)
+		}
+
+		depends := []string{}
+		for _, d := range rct.Depends {
+			depend := taskNames[d.TaskID]
+			if len(d.Conditions) > 0 {
+				conditions := []string{}
+				for _, c := range d.Conditions {
+					conditions = append(conditions, string(c))
+				}
+				depend += " (" + strings.Join(conditions, ", ") + ")"
+			}
+			depends = append(depends, depend)
+		}
+		slices.Sort(depends)
+		if len(depends) > 0 {
+			fmt.Fprintf(w, "\t\tDepends on: %s\n", strings.Join(depends, ", "))
+		}
+		if rct.NeedsApproval {
+			fmt.Fprintf(w, "\t\tNeeds approval\n")
+		}
+
+		envNames := []string{}
+		for name := range rct.Environment {
+			envNames = append(envNames, name)
+		}
+		slices.Sort(envNames)
+		for _, name := range envNames {
+			fmt.Fprintf(w, "\t\tEnv: %s=%s\n", name, rct.Environment[name])
+		}
+
+		for i, step := range rct.Steps {
+			switch s := step.(type) {
+			case *rstypes.RunStep:
+				fmt.Fprintf(w, "\t\tStep %d: %s %q: %s\n", i, s.Type, s.Name, commandSummary(s.Command))
+			case *rstypes.SaveToWorkspaceStep:
+				fmt.Fprintf(w, "\t\tStep %d: %s %q\n", i, s.Type, s.Name)
+			case *rstypes.RestoreWorkspaceStep:
+				fmt.Fprintf(w, "\t\tStep %d: %s %q: %s\n", i, s.Type, s.Name, s.DestDir)
+			case *rstypes.SaveCacheStep:
+				fmt.Fprintf(w, "\t\tStep %d: %s %q: %s\n", i, s.Type, s.Name, s.Key)
+			case *rstypes.RestoreCacheStep:
+				fmt.Fprintf(w, "\t\tStep %d: %s %q: %s\n", i, s.Type, s.Name, strings.Join(s.Keys, ", "))
+			}
+		}
+	}
+}
+
+// commandSummary returns the first non empty line of a multi line command.
+func commandSummary(command string) string {
+	lines := strings.Split(strings.TrimSpace(command), "\n")
+	if len(lines) == 1 {
+		return lines[0]
+	}
+
+	return lines[0] + " ..."
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	"agola.io/agola/internal/config"
+)
+
+var cmdConfigSchema = &cobra.Command{
+	Use: "schema",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := configSchema(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "print the run config JSON Schema (also served by the gateway at /api/v1alpha/schemas/runconfig.json)",
+}
+
+func init() {
+	cmdConfig.AddCommand(cmdConfigSchema)
+}
+
+func configSchema(cmd *cobra.Command, args []string) error {
+	_, err := os.Stdout.Write(config.Schema())
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+)
+
+var cmdConfigValidate = &cobra.Command{
+	Use: "validate",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := configValidate(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "validate a run config file locally using the provided config context",
+}
+
+type configValidateOptions struct {
+	configContextOptions
+}
+
+var configValidateOpts configValidateOptions
+
+func init() {
+	addConfigContextFlags(cmdConfigValidate, &configValidateOpts.configContextOptions)
+
+	cmdConfig.AddCommand(cmdConfigValidate)
+}
+
+func configValidate(cmd *cobra.Command, args []string) error {
+	pc, err := configValidateOpts.parseConfig(cmd)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	failed := false
+	for _, run := range genConfigRuns(pc) {
+		switch {
+		case run.Error != "":
+			failed = true
+			fmt.Printf("run %q: %s\n", run.Name, run.Error)
+		case run.Skip:
+			fmt.Printf("run %q: ok (skipped since its when conditions don't match)\n", run.Name)
+		default:
+			fmt.Printf("run %q: ok\n", run.Name)
+		}
+	}
+
+	if failed {
+		return errors.Errorf("%s: run config has errors", pc.filename)
+	}
+
+	return nil
+}
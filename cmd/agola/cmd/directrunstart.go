@@ -15,12 +15,16 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 	"unicode"
 
 	"github.com/ghodss/yaml"
@@ -33,6 +37,11 @@ import (
 	"agola.io/agola/internal/util"
 	gwapitypes "agola.io/agola/services/gateway/api/types"
 	gwclient "agola.io/agola/services/gateway/client"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+const (
+	directRunPollInterval = 2 * time.Second
 )
 
 var cmdDirectRunStart = &cobra.Command{
@@ -56,6 +65,9 @@ type directRunStartOptions struct {
 
 	vars     []string
 	varFiles []string
+
+	wait   bool
+	follow bool
 }
 
 var directRunStartOpts directRunStartOptions
@@ -71,6 +83,8 @@ func init() {
 	flags.StringArrayVar(&directRunStartOpts.prRefRegexes, "pull-request-ref-regexes", []string{`refs/pull/(\d+)/head`, `refs/merge-requests/(\d+)/head`}, `regular expression to determine if a ref is a pull request`)
 	flags.StringArrayVar(&directRunStartOpts.vars, "var", []string{}, `list of variables (name=value). This option can be repeated multiple times`)
 	flags.StringArrayVar(&directRunStartOpts.varFiles, "var-file", []string{}, `yaml file containing the variables as a yaml/json map. This option can be repeated multiple times`)
+	flags.BoolVar(&directRunStartOpts.wait, "wait", false, "wait for the created runs to finish and exit with a non zero status if a run isn't successful (1: failed, 2: stopped, 3: cancelled, 4: setup error)")
+	flags.BoolVar(&directRunStartOpts.follow, "follow", false, `stream the logs of the task steps of the created runs (implies "--wait")`)
 
 	cmdDirectRun.AddCommand(cmdDirectRunStart)
 }
@@ -214,9 +228,159 @@ func directRunStart(cmd *cobra.Command, args []string) error {
 		PullRequestRefRegexes: directRunStartOpts.prRefRegexes,
 		Variables:             variables,
 	}
-	if _, err := gwClient.UserCreateRun(context.TODO(), req); err != nil {
+	runs, _, err := gwClient.UserCreateRun(context.TODO(), req)
+	if err != nil {
 		return errors.WithStack(err)
 	}
 
+	if !directRunStartOpts.wait && !directRunStartOpts.follow {
+		return nil
+	}
+
+	exitCode := 0
+	for _, r := range runs {
+		log.Info().Msgf("waiting for run %d (%s)", r.Number, r.Name)
+
+		run, err := waitUserRun(context.TODO(), gwClient, user.ID, r.Number, directRunStartOpts.follow)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		log.Info().Msgf("run %d (%s) phase: %s, result: %s", run.Number, run.Name, run.Phase, run.Result)
+
+		if code := runExitCode(run); code > exitCode {
+			exitCode = code
+		}
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+
 	return nil
 }
+
+// runExitCode returns the process exit code matching the run phase and result.
+func runExitCode(run *gwapitypes.RunResponse) int {
+	switch run.Phase {
+	case rstypes.RunPhaseSetupError:
+		return 4
+	case rstypes.RunPhaseCancelled:
+		return 3
+	}
+
+	switch run.Result {
+	case rstypes.RunResultSuccess:
+		return 0
+	case rstypes.RunResultStopped:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// waitUserRun polls the user run until it's finished. When follow is true the
+// logs of every task step are streamed to stdout, prefixed with the task name,
+// as soon as the step is started.
+func waitUserRun(ctx context.Context, gwClient *gwclient.Client, userRef string, runNumber uint64, follow bool) (*gwapitypes.RunResponse, error) {
+	var wg sync.WaitGroup
+	var outMutex sync.Mutex
+	followedTasks := map[string]struct{}{}
+
+	for {
+		run, _, err := gwClient.GetUserRun(ctx, userRef, runNumber)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if follow {
+			for _, rt := range run.Tasks {
+				if _, ok := followedTasks[rt.ID]; ok {
+					continue
+				}
+				if rt.Status == rstypes.RunTaskStatusNotStarted || rt.Status == rstypes.RunTaskStatusSkipped || rt.Status == rstypes.RunTaskStatusCancelled {
+					continue
+				}
+				followedTasks[rt.ID] = struct{}{}
+
+				wg.Add(1)
+				go func(rt *gwapitypes.RunResponseTask) {
+					defer wg.Done()
+					if err := followUserRunTask(ctx, gwClient, userRef, runNumber, rt, &outMutex); err != nil {
+						log.Err(err).Msgf("failed to follow task %q logs", rt.Name)
+					}
+				}(rt)
+			}
+		}
+
+		if run.Phase.IsFinished() {
+			wg.Wait()
+			return run, nil
+		}
+
+		time.Sleep(directRunPollInterval)
+	}
+}
+
+// followUserRunTask streams, in order, the logs of all the started steps of a
+// run task.
+func followUserRunTask(ctx context.Context, gwClient *gwclient.Client, userRef string, runNumber uint64, rt *gwapitypes.RunResponseTask, outMutex *sync.Mutex) error {
+	step := 0
+	for {
+		task, _, err := gwClient.GetUserRunTask(ctx, userRef, runNumber, rt.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if step >= len(task.Steps) {
+			return nil
+		}
+
+		if task.Steps[step].Phase == rstypes.ExecutorTaskPhaseNotStarted {
+			// the remaining steps won't be executed
+			if task.Status.IsFinished() {
+				return nil
+			}
+			time.Sleep(directRunPollInterval)
+			continue
+		}
+
+		resp, err := gwClient.GetUserLogs(ctx, userRef, runNumber, rt.ID, false, step, true, nil)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get step %d log", step)
+		}
+		err = copyPrefixedLog(os.Stdout, resp.Body, fmt.Sprintf("[%s] ", task.Name), outMutex)
+		resp.Body.Close()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		step++
+	}
+}
+
+// copyPrefixedLog copies every log line from r to w adding the provided prefix.
+// Every line is written holding outMutex so lines of concurrent streams won't
+// be mixed.
+func copyPrefixedLog(w io.Writer, r io.Reader, prefix string, outMutex *sync.Mutex) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			if !strings.HasSuffix(line, "\n") {
+				line += "\n"
+			}
+			outMutex.Lock()
+			_, werr := io.WriteString(w, prefix+line)
+			outMutex.Unlock()
+			if werr != nil {
+				return errors.WithStack(werr)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+	}
+}
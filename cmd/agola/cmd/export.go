@@ -38,8 +38,10 @@ var cmdExport = &cobra.Command{
 }
 
 type exportOptions struct {
-	outFilePath string
-	servicename string
+	outFilePath  string
+	servicename  string
+	org          string
+	projectGroup string
 }
 
 var exportOpts exportOptions
@@ -49,14 +51,36 @@ func init() {
 
 	flags.StringVar(&exportOpts.servicename, "service", "", "service name")
 	flags.StringVar(&exportOpts.outFilePath, "out", "-", "output file path")
+	flags.StringVar(&exportOpts.org, "org", "", "export only the organization with this name or id, its projects and their runs")
+	flags.StringVar(&exportOpts.projectGroup, "projectgroup", "", "export only the project group with this path or id, its projects and their runs")
 
 	cmdAgola.AddCommand(cmdExport)
 }
 
 func export(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	scoped := flags.Changed("org") || flags.Changed("projectgroup")
+	if flags.Changed("org") && flags.Changed("projectgroup") {
+		return errors.Errorf(`only one of "--org" or "--projectgroup" can be provided`)
+	}
+	if scoped && flags.Changed("service") {
+		return errors.Errorf(`"--service" cannot be provided with "--org" or "--projectgroup"`)
+	}
+
+	var resp *gatewayclient.Response
+	var err error
+
 	gatewayclient := gatewayclient.NewClient(gatewayURL, token)
 
-	resp, err := gatewayclient.Export(context.TODO(), exportOpts.servicename)
+	switch {
+	case flags.Changed("org"):
+		resp, err = gatewayclient.ScopedExport(context.TODO(), "org", exportOpts.org)
+	case flags.Changed("projectgroup"):
+		resp, err = gatewayclient.ScopedExport(context.TODO(), "projectgroup", exportOpts.projectGroup)
+	default:
+		resp, err = gatewayclient.Export(context.TODO(), exportOpts.servicename)
+	}
 	if err != nil {
 		return errors.WithStack(err)
 	}
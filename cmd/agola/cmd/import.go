@@ -36,8 +36,10 @@ var cmdImport = &cobra.Command{
 }
 
 type importOptions struct {
-	inFilePath  string
-	servicename string
+	inFilePath         string
+	servicename        string
+	scoped             bool
+	parentProjectGroup string
 }
 
 var importOpts importOptions
@@ -47,11 +49,22 @@ func init() {
 
 	flags.StringVar(&importOpts.servicename, "service", "", "service name")
 	flags.StringVar(&importOpts.inFilePath, "in", "-", "input file path")
+	flags.BoolVar(&importOpts.scoped, "scoped", false, "import an organization or a project group exported with \"--org\" or \"--projectgroup\"")
+	flags.StringVar(&importOpts.parentProjectGroup, "parent-projectgroup", "", "path or id of the project group where an exported project group will be imported")
 
 	cmdAgola.AddCommand(cmdImport)
 }
 
 func imp(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	if importOpts.scoped && flags.Changed("service") {
+		return errors.Errorf(`"--service" cannot be provided with "--scoped"`)
+	}
+	if !importOpts.scoped && flags.Changed("parent-projectgroup") {
+		return errors.Errorf(`"--parent-projectgroup" requires "--scoped"`)
+	}
+
 	gatewayclient := gatewayclient.NewClient(gatewayURL, token)
 
 	var r *os.File
@@ -65,7 +78,12 @@ func imp(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	_, err := gatewayclient.Import(context.TODO(), importOpts.servicename, r)
+	var err error
+	if importOpts.scoped {
+		_, err = gatewayclient.ScopedImport(context.TODO(), importOpts.parentProjectGroup, r)
+	} else {
+		_, err = gatewayclient.Import(context.TODO(), importOpts.servicename, r)
+	}
 	if err != nil {
 		return errors.WithStack(err)
 	}
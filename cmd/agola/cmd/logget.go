@@ -47,6 +47,9 @@ type logGetOptions struct {
 	setup      bool
 	follow     bool
 	output     string
+	format     string
+	start      uint64
+	limit      uint64
 }
 
 var logGetOpts logGetOptions
@@ -63,6 +66,9 @@ func init() {
 	flags.BoolVar(&logGetOpts.setup, "setup", false, "Setup step")
 	flags.BoolVar(&logGetOpts.follow, "follow", false, "Follow log stream")
 	flags.StringVar(&logGetOpts.output, "output", "", "Write output to file")
+	flags.StringVar(&logGetOpts.format, "format", "text", `log format: "text" or "ndjson" (one json object per line with the line number, time and stream)`)
+	flags.Uint64Var(&logGetOpts.start, "start", 0, "number of the first line to get (zero based)")
+	flags.Uint64Var(&logGetOpts.limit, "limit", 0, "maximum number of lines to get (0 means no limit)")
 
 	if err := cmdLogGet.MarkFlagRequired("runnumber"); err != nil {
 		log.Fatal().Err(err).Send()
@@ -97,6 +103,9 @@ func logGet(cmd *cobra.Command, args []string) error {
 	if flags.Changed("follow") && flags.Changed("output") {
 		return errors.Errorf(`only one of "--follow" or "--output" can be provided`)
 	}
+	if logGetOpts.format != "text" && logGetOpts.format != "ndjson" {
+		return errors.Errorf("invalid log format %q", logGetOpts.format)
+	}
 
 	gwClient := gwclient.NewClient(gatewayURL, token)
 
@@ -135,12 +144,18 @@ func logGet(cmd *cobra.Command, args []string) error {
 
 	log.Info().Msg("getting log")
 
+	opts := &gwclient.GetLogsOptions{
+		Format: logGetOpts.format,
+		Start:  logGetOpts.start,
+		Limit:  logGetOpts.limit,
+	}
+
 	var resp *gwclient.Response
 	var err error
 	if isProject {
-		resp, err = gwClient.GetProjectLogs(context.TODO(), logGetOpts.projectRef, logGetOpts.runNumber, taskid, logGetOpts.setup, logGetOpts.step, logGetOpts.follow)
+		resp, err = gwClient.GetProjectLogs(context.TODO(), logGetOpts.projectRef, logGetOpts.runNumber, taskid, logGetOpts.setup, logGetOpts.step, logGetOpts.follow, opts)
 	} else {
-		resp, err = gwClient.GetUserLogs(context.TODO(), logGetOpts.username, logGetOpts.runNumber, taskid, logGetOpts.setup, logGetOpts.step, logGetOpts.follow)
+		resp, err = gwClient.GetUserLogs(context.TODO(), logGetOpts.username, logGetOpts.runNumber, taskid, logGetOpts.setup, logGetOpts.step, logGetOpts.follow, opts)
 	}
 	if err != nil {
 		return errors.Wrapf(err, "failed to get log")
@@ -48,7 +48,7 @@ func init() {
 
 	flags.StringVarP(&orgMemberAddOpts.orgname, "orgname", "n", "", "organization name")
 	flags.StringVar(&orgMemberAddOpts.username, "username", "", "user name")
-	flags.StringVarP(&orgMemberAddOpts.role, "role", "r", "member", "member role (owner or member)")
+	flags.StringVarP(&orgMemberAddOpts.role, "role", "r", "member", "member role (owner, admin, maintainer, developer, member or viewer)")
 
 	if err := cmdOrgMemberAdd.MarkFlagRequired("orgname"); err != nil {
 		log.Fatal().Err(err).Send()
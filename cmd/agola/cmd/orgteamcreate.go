@@ -0,0 +1,76 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdOrgTeamCreate = &cobra.Command{
+	Use:   "create",
+	Short: "create a team",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orgTeamCreate(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type orgTeamCreateOptions struct {
+	orgname string
+	name    string
+}
+
+var orgTeamCreateOpts orgTeamCreateOptions
+
+func init() {
+	flags := cmdOrgTeamCreate.Flags()
+
+	flags.StringVar(&orgTeamCreateOpts.orgname, "orgname", "", "organization name")
+	flags.StringVarP(&orgTeamCreateOpts.name, "name", "n", "", "team name")
+
+	if err := cmdOrgTeamCreate.MarkFlagRequired("orgname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamCreate.MarkFlagRequired("name"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdOrgTeam.AddCommand(cmdOrgTeamCreate)
+}
+
+func orgTeamCreate(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	req := &gwapitypes.CreateTeamRequest{
+		Name: orgTeamCreateOpts.name,
+	}
+
+	log.Info().Msgf("creating team %q in organization %q", orgTeamCreateOpts.name, orgTeamCreateOpts.orgname)
+	team, _, err := gwClient.CreateOrgTeam(context.TODO(), orgTeamCreateOpts.orgname, req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create team")
+	}
+	log.Info().Msgf("team %q created, ID: %q", team.Name, team.ID)
+
+	return nil
+}
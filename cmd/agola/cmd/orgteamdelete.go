@@ -0,0 +1,69 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdOrgTeamDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "delete a team",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orgTeamDelete(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type orgTeamDeleteOptions struct {
+	orgname string
+	name    string
+}
+
+var orgTeamDeleteOpts orgTeamDeleteOptions
+
+func init() {
+	flags := cmdOrgTeamDelete.Flags()
+
+	flags.StringVar(&orgTeamDeleteOpts.orgname, "orgname", "", "organization name")
+	flags.StringVarP(&orgTeamDeleteOpts.name, "name", "n", "", "team name")
+
+	if err := cmdOrgTeamDelete.MarkFlagRequired("orgname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamDelete.MarkFlagRequired("name"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdOrgTeam.AddCommand(cmdOrgTeamDelete)
+}
+
+func orgTeamDelete(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	log.Info().Msgf("deleting team %q from organization %q", orgTeamDeleteOpts.name, orgTeamDeleteOpts.orgname)
+	if _, err := gwClient.DeleteOrgTeam(context.TODO(), orgTeamDeleteOpts.orgname, orgTeamDeleteOpts.name); err != nil {
+		return errors.Wrapf(err, "failed to delete team")
+	}
+
+	return nil
+}
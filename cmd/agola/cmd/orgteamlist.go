@@ -0,0 +1,74 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdOrgTeamList = &cobra.Command{
+	Use:   "list",
+	Short: "list organization teams",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orgTeamList(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type orgTeamListOptions struct {
+	orgname string
+}
+
+var orgTeamListOpts orgTeamListOptions
+
+func init() {
+	flags := cmdOrgTeamList.Flags()
+
+	flags.StringVarP(&orgTeamListOpts.orgname, "orgname", "n", "", "organization name")
+
+	if err := cmdOrgTeamList.MarkFlagRequired("orgname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdOrgTeam.AddCommand(cmdOrgTeamList)
+}
+
+func orgTeamList(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	teams, _, err := gwClient.GetOrgTeams(context.TODO(), orgTeamListOpts.orgname)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get organization teams")
+	}
+
+	for _, team := range teams {
+		out, err := json.MarshalIndent(team, "", "\t")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		os.Stdout.Write(out)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdOrgTeamMemberAdd = &cobra.Command{
+	Use:   "add",
+	Short: "add an organization member to a team",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orgTeamMemberAdd(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type orgTeamMemberAddOptions struct {
+	orgname  string
+	teamname string
+	username string
+}
+
+var orgTeamMemberAddOpts orgTeamMemberAddOptions
+
+func init() {
+	flags := cmdOrgTeamMemberAdd.Flags()
+
+	flags.StringVar(&orgTeamMemberAddOpts.orgname, "orgname", "", "organization name")
+	flags.StringVarP(&orgTeamMemberAddOpts.teamname, "teamname", "t", "", "team name")
+	flags.StringVar(&orgTeamMemberAddOpts.username, "username", "", "user name")
+
+	if err := cmdOrgTeamMemberAdd.MarkFlagRequired("orgname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamMemberAdd.MarkFlagRequired("teamname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamMemberAdd.MarkFlagRequired("username"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdOrgTeamMember.AddCommand(cmdOrgTeamMemberAdd)
+}
+
+func orgTeamMemberAdd(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	log.Info().Msgf("adding member %q to team %q of organization %q", orgTeamMemberAddOpts.username, orgTeamMemberAddOpts.teamname, orgTeamMemberAddOpts.orgname)
+	if _, err := gwClient.AddOrgTeamMember(context.TODO(), orgTeamMemberAddOpts.orgname, orgTeamMemberAddOpts.teamname, orgTeamMemberAddOpts.username); err != nil {
+		return errors.Wrapf(err, "failed to add team member")
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdOrgTeamMemberList = &cobra.Command{
+	Use:   "list",
+	Short: "list team members",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orgTeamMemberList(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type orgTeamMemberListOptions struct {
+	orgname  string
+	teamname string
+}
+
+var orgTeamMemberListOpts orgTeamMemberListOptions
+
+func init() {
+	flags := cmdOrgTeamMemberList.Flags()
+
+	flags.StringVar(&orgTeamMemberListOpts.orgname, "orgname", "", "organization name")
+	flags.StringVarP(&orgTeamMemberListOpts.teamname, "teamname", "t", "", "team name")
+
+	if err := cmdOrgTeamMemberList.MarkFlagRequired("orgname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamMemberList.MarkFlagRequired("teamname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdOrgTeamMember.AddCommand(cmdOrgTeamMemberList)
+}
+
+func orgTeamMemberList(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	users, _, err := gwClient.GetOrgTeamMembers(context.TODO(), orgTeamMemberListOpts.orgname, orgTeamMemberListOpts.teamname)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get team members")
+	}
+
+	for _, user := range users {
+		out, err := json.MarshalIndent(user, "", "\t")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		os.Stdout.Write(out)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdOrgTeamMemberRemove = &cobra.Command{
+	Use:   "remove",
+	Short: "remove a member from a team",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := orgTeamMemberRemove(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type orgTeamMemberRemoveOptions struct {
+	orgname  string
+	teamname string
+	username string
+}
+
+var orgTeamMemberRemoveOpts orgTeamMemberRemoveOptions
+
+func init() {
+	flags := cmdOrgTeamMemberRemove.Flags()
+
+	flags.StringVar(&orgTeamMemberRemoveOpts.orgname, "orgname", "", "organization name")
+	flags.StringVarP(&orgTeamMemberRemoveOpts.teamname, "teamname", "t", "", "team name")
+	flags.StringVar(&orgTeamMemberRemoveOpts.username, "username", "", "user name")
+
+	if err := cmdOrgTeamMemberRemove.MarkFlagRequired("orgname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamMemberRemove.MarkFlagRequired("teamname"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdOrgTeamMemberRemove.MarkFlagRequired("username"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdOrgTeamMember.AddCommand(cmdOrgTeamMemberRemove)
+}
+
+func orgTeamMemberRemove(cmd *cobra.Command, args []string) error {
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	log.Info().Msgf("removing member %q from team %q of organization %q", orgTeamMemberRemoveOpts.username, orgTeamMemberRemoveOpts.teamname, orgTeamMemberRemoveOpts.orgname)
+	if _, err := gwClient.RemoveOrgTeamMember(context.TODO(), orgTeamMemberRemoveOpts.orgname, orgTeamMemberRemoveOpts.teamname, orgTeamMemberRemoveOpts.username); err != nil {
+		return errors.Wrapf(err, "failed to remove team member")
+	}
+
+	return nil
+}
@@ -43,6 +43,10 @@ type projectCreateOptions struct {
 	skipSSHHostKeyCheck bool
 	visibility          string
 	passVarsToForkedPR  bool
+	mirrorURL           string
+	mirrorSSHHostKey    string
+	mirrorBranches      []string
+	mirrorTags          []string
 }
 
 var projectCreateOpts projectCreateOptions
@@ -57,6 +61,10 @@ func init() {
 	flags.StringVar(&projectCreateOpts.parentPath, "parent", "", `parent project group path (i.e "org/org01" for root project group in org01, "user/user01/group01/subgroub01") or project group id where the project should be created`)
 	flags.StringVar(&projectCreateOpts.visibility, "visibility", "public", `project visibility (public or private)`)
 	flags.BoolVar(&projectCreateOpts.passVarsToForkedPR, "pass-vars-to-forked-pr", false, `pass variables to run even if triggered by PR from forked repo`)
+	flags.StringVar(&projectCreateOpts.mirrorURL, "mirror-url", "", "git repository url of a mirror project, periodically polled for new commits (alternative to --remote-source and --repo-path)")
+	flags.StringVar(&projectCreateOpts.mirrorSSHHostKey, "mirror-ssh-host-key", "", "mirror project git server ssh host key (in known_hosts format)")
+	flags.StringSliceVar(&projectCreateOpts.mirrorBranches, "mirror-branch", nil, "mirror project branches (glob patterns) for which runs are created. Can be repeated. Defaults to all the branches")
+	flags.StringSliceVar(&projectCreateOpts.mirrorTags, "mirror-tag", nil, "mirror project tags (glob patterns) for which runs are created. Can be repeated. Defaults to no tags")
 
 	if err := cmdProjectCreate.MarkFlagRequired("name"); err != nil {
 		log.Fatal().Err(err).Send()
@@ -64,12 +72,6 @@ func init() {
 	if err := cmdProjectCreate.MarkFlagRequired("parent"); err != nil {
 		log.Fatal().Err(err).Send()
 	}
-	if err := cmdProjectCreate.MarkFlagRequired("repo-path"); err != nil {
-		log.Fatal().Err(err).Send()
-	}
-	if err := cmdProjectCreate.MarkFlagRequired("remote-source"); err != nil {
-		log.Fatal().Err(err).Send()
-	}
 
 	cmdProject.AddCommand(cmdProjectCreate)
 }
@@ -92,6 +94,19 @@ func projectCreate(cmd *cobra.Command, args []string) error {
 		return errors.Errorf("invalid visibility %q", projectCreateOpts.visibility)
 	}
 
+	if projectCreateOpts.mirrorURL != "" {
+		if projectCreateOpts.remoteSourceName != "" || projectCreateOpts.repoPath != "" {
+			return errors.Errorf(`only one of "--mirror-url" or "--remote-source" and "--repo-path" can be provided`)
+		}
+	} else {
+		if projectCreateOpts.remoteSourceName == "" {
+			return errors.Errorf(`required flag "remote-source" not set`)
+		}
+		if projectCreateOpts.repoPath == "" {
+			return errors.Errorf(`required flag "repo-path" not set`)
+		}
+	}
+
 	req := &gwapitypes.CreateProjectRequest{
 		Name:                projectCreateOpts.name,
 		ParentRef:           projectCreateOpts.parentPath,
@@ -100,6 +115,10 @@ func projectCreate(cmd *cobra.Command, args []string) error {
 		RemoteSourceName:    projectCreateOpts.remoteSourceName,
 		SkipSSHHostKeyCheck: projectCreateOpts.skipSSHHostKeyCheck,
 		PassVarsToForkedPR:  projectCreateOpts.passVarsToForkedPR,
+		MirrorURL:           projectCreateOpts.mirrorURL,
+		MirrorSSHHostKey:    projectCreateOpts.mirrorSSHHostKey,
+		MirrorBranches:      projectCreateOpts.mirrorBranches,
+		MirrorTags:          projectCreateOpts.mirrorTags,
 	}
 
 	log.Info().Msg("creating project")
@@ -110,5 +129,13 @@ func projectCreate(cmd *cobra.Command, args []string) error {
 	}
 	log.Info().Msgf("project %s created, ID: %s", project.Name, project.ID)
 
+	if projectCreateOpts.mirrorURL != "" {
+		projectMirror, _, err := gwClient.GetProjectMirror(context.TODO(), project.ID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get project mirror")
+		}
+		log.Info().Msgf("authorize the project ssh public key on the git server to access the repository: %s", projectMirror.SSHPublicKey)
+	}
+
 	return nil
 }
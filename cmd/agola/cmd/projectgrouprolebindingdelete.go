@@ -0,0 +1,44 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var cmdProjectGroupRoleBindingDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "delete the role binding of a user or team on a project group",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := roleBindingDelete(cmd, "projectgroup", args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+func init() {
+	flags := cmdProjectGroupRoleBindingDelete.Flags()
+
+	flags.StringVar(&roleBindingDeleteOpts.parentRef, "projectgroup", "", "project group id or full path")
+	flags.StringVarP(&roleBindingDeleteOpts.username, "username", "n", "", "user name")
+	flags.StringVarP(&roleBindingDeleteOpts.teamname, "teamname", "t", "", "team name (only for organization owned projects and project groups)")
+
+	if err := cmdProjectGroupRoleBindingDelete.MarkFlagRequired("projectgroup"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdProjectGroupRoleBinding.AddCommand(cmdProjectGroupRoleBindingDelete)
+}
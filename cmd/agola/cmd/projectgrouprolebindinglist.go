@@ -0,0 +1,42 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var cmdProjectGroupRoleBindingList = &cobra.Command{
+	Use:   "list",
+	Short: "list project group role bindings",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := roleBindingList(cmd, "projectgroup", args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+func init() {
+	flags := cmdProjectGroupRoleBindingList.Flags()
+
+	flags.StringVar(&roleBindingListOpts.parentRef, "projectgroup", "", "project group id or full path")
+
+	if err := cmdProjectGroupRoleBindingList.MarkFlagRequired("projectgroup"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdProjectGroupRoleBinding.AddCommand(cmdProjectGroupRoleBindingList)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdProjectRoleBindingDelete = &cobra.Command{
+	Use:   "delete",
+	Short: "delete the role binding of a user or team on a project",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := roleBindingDelete(cmd, "project", args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type roleBindingDeleteOptions struct {
+	parentRef string
+	username  string
+	teamname  string
+}
+
+var roleBindingDeleteOpts roleBindingDeleteOptions
+
+func init() {
+	flags := cmdProjectRoleBindingDelete.Flags()
+
+	flags.StringVar(&roleBindingDeleteOpts.parentRef, "project", "", "project id or full path")
+	flags.StringVarP(&roleBindingDeleteOpts.username, "username", "n", "", "user name")
+	flags.StringVarP(&roleBindingDeleteOpts.teamname, "teamname", "t", "", "team name (only for organization owned projects and project groups)")
+
+	if err := cmdProjectRoleBindingDelete.MarkFlagRequired("project"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdProjectRoleBinding.AddCommand(cmdProjectRoleBindingDelete)
+}
+
+func roleBindingDelete(cmd *cobra.Command, ownertype string, args []string) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("username") && flags.Changed("teamname") {
+		return errors.Errorf(`only one of "--username" or "--teamname" can be provided`)
+	}
+	if !flags.Changed("username") && !flags.Changed("teamname") {
+		return errors.Errorf(`one of "--username" or "--teamname" must be provided`)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	var err error
+	switch ownertype {
+	case "project":
+		log.Info().Msg("deleting project role binding")
+		if flags.Changed("username") {
+			_, err = gwClient.DeleteProjectUserRoleBinding(context.TODO(), roleBindingDeleteOpts.parentRef, roleBindingDeleteOpts.username)
+		} else {
+			_, err = gwClient.DeleteProjectTeamRoleBinding(context.TODO(), roleBindingDeleteOpts.parentRef, roleBindingDeleteOpts.teamname)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete project role binding")
+		}
+		log.Info().Msg("project role binding deleted")
+	case "projectgroup":
+		log.Info().Msg("deleting project group role binding")
+		if flags.Changed("username") {
+			_, err = gwClient.DeleteProjectGroupUserRoleBinding(context.TODO(), roleBindingDeleteOpts.parentRef, roleBindingDeleteOpts.username)
+		} else {
+			_, err = gwClient.DeleteProjectGroupTeamRoleBinding(context.TODO(), roleBindingDeleteOpts.parentRef, roleBindingDeleteOpts.teamname)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete project group role binding")
+		}
+		log.Info().Msg("project group role binding deleted")
+	}
+
+	return nil
+}
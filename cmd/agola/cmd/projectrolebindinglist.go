@@ -0,0 +1,89 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdProjectRoleBindingList = &cobra.Command{
+	Use:   "list",
+	Short: "list project role bindings",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := roleBindingList(cmd, "project", args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type roleBindingListOptions struct {
+	parentRef string
+}
+
+var roleBindingListOpts roleBindingListOptions
+
+func init() {
+	flags := cmdProjectRoleBindingList.Flags()
+
+	flags.StringVar(&roleBindingListOpts.parentRef, "project", "", "project id or full path")
+
+	if err := cmdProjectRoleBindingList.MarkFlagRequired("project"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdProjectRoleBinding.AddCommand(cmdProjectRoleBindingList)
+}
+
+func roleBindingList(cmd *cobra.Command, ownertype string, args []string) error {
+	if err := printRoleBindings(ownertype, fmt.Sprintf("%s role bindings", ownertype), false); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := printRoleBindings(ownertype, "All role bindings (local and inherited)", true); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func printRoleBindings(ownertype, description string, tree bool) error {
+	var err error
+	var roleBindings []*gwapitypes.RoleBindingResponse
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	switch ownertype {
+	case "project":
+		roleBindings, _, err = gwClient.GetProjectRoleBindings(context.TODO(), roleBindingListOpts.parentRef, tree)
+	case "projectgroup":
+		roleBindings, _, err = gwClient.GetProjectGroupRoleBindings(context.TODO(), roleBindingListOpts.parentRef, tree)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to list %s role bindings", ownertype)
+	}
+	prettyJSON, err := json.MarshalIndent(roleBindings, "", "\t")
+	if err != nil {
+		return errors.Wrapf(err, "failed to convert %s role bindings to json", ownertype)
+	}
+	fmt.Printf("%s:\n%s\n", description, string(prettyJSON))
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdProjectRoleBindingSet = &cobra.Command{
+	Use:   "set",
+	Short: "set the role of a user or team on a project",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := roleBindingSet(cmd, "project", args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type roleBindingSetOptions struct {
+	parentRef string
+	username  string
+	teamname  string
+	role      string
+}
+
+var roleBindingSetOpts roleBindingSetOptions
+
+func init() {
+	flags := cmdProjectRoleBindingSet.Flags()
+
+	flags.StringVar(&roleBindingSetOpts.parentRef, "project", "", "project id or full path")
+	flags.StringVarP(&roleBindingSetOpts.username, "username", "n", "", "user name")
+	flags.StringVarP(&roleBindingSetOpts.teamname, "teamname", "t", "", "team name (only for organization owned projects and project groups)")
+	flags.StringVarP(&roleBindingSetOpts.role, "role", "r", "", "role (admin, maintainer, developer, member, viewer)")
+
+	if err := cmdProjectRoleBindingSet.MarkFlagRequired("project"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+	if err := cmdProjectRoleBindingSet.MarkFlagRequired("role"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	cmdProjectRoleBinding.AddCommand(cmdProjectRoleBindingSet)
+}
+
+func roleBindingSet(cmd *cobra.Command, ownertype string, args []string) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("username") && flags.Changed("teamname") {
+		return errors.Errorf(`only one of "--username" or "--teamname" can be provided`)
+	}
+	if !flags.Changed("username") && !flags.Changed("teamname") {
+		return errors.Errorf(`one of "--username" or "--teamname" must be provided`)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	req := &gwapitypes.SetRoleBindingRequest{
+		Role: gwapitypes.MemberRole(roleBindingSetOpts.role),
+	}
+
+	var err error
+	switch ownertype {
+	case "project":
+		if flags.Changed("username") {
+			log.Info().Msgf("setting project role binding for user %q", roleBindingSetOpts.username)
+			_, _, err = gwClient.SetProjectUserRoleBinding(context.TODO(), roleBindingSetOpts.parentRef, roleBindingSetOpts.username, req)
+		} else {
+			log.Info().Msgf("setting project role binding for team %q", roleBindingSetOpts.teamname)
+			_, _, err = gwClient.SetProjectTeamRoleBinding(context.TODO(), roleBindingSetOpts.parentRef, roleBindingSetOpts.teamname, req)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to set project role binding")
+		}
+		log.Info().Msg("project role binding set")
+	case "projectgroup":
+		if flags.Changed("username") {
+			log.Info().Msgf("setting project group role binding for user %q", roleBindingSetOpts.username)
+			_, _, err = gwClient.SetProjectGroupUserRoleBinding(context.TODO(), roleBindingSetOpts.parentRef, roleBindingSetOpts.username, req)
+		} else {
+			log.Info().Msgf("setting project group role binding for team %q", roleBindingSetOpts.teamname)
+			_, _, err = gwClient.SetProjectGroupTeamRoleBinding(context.TODO(), roleBindingSetOpts.parentRef, roleBindingSetOpts.teamname, req)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to set project group role binding")
+		}
+		log.Info().Msg("project group role binding set")
+	}
+
+	return nil
+}
@@ -15,7 +15,17 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
 	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
 )
 
 var cmdRun = &cobra.Command{
@@ -26,3 +36,87 @@ var cmdRun = &cobra.Command{
 func init() {
 	cmdAgola.AddCommand(cmdRun)
 }
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// runRefOptions are the options used to reference a project or user run
+type runRefOptions struct {
+	projectRef string
+	username   string
+	runNumber  uint64
+}
+
+func addRunRefFlags(cmd *cobra.Command, o *runRefOptions) {
+	flags := cmd.Flags()
+
+	flags.StringVar(&o.projectRef, "project", "", "project id or full path")
+	flags.StringVar(&o.username, "username", "", "user name for user direct runs")
+	flags.Uint64Var(&o.runNumber, "runnumber", 0, "run number")
+
+	if err := cmd.MarkFlagRequired("runnumber"); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+}
+
+func (o *runRefOptions) check(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	if flags.Changed("username") && flags.Changed("project") {
+		return errors.Errorf(`only one of "--username" or "--project" can be provided`)
+	}
+	if !flags.Changed("username") && !flags.Changed("project") {
+		return errors.Errorf(`one of "--username" or "--project" must be provided`)
+	}
+
+	return nil
+}
+
+func (o *runRefOptions) isProject() bool {
+	return o.projectRef != ""
+}
+
+func (o *runRefOptions) getRun(ctx context.Context, gwClient *gwclient.Client) (*gwapitypes.RunResponse, error) {
+	var run *gwapitypes.RunResponse
+	var err error
+	if o.isProject() {
+		run, _, err = gwClient.GetProjectRun(ctx, o.projectRef, o.runNumber)
+	} else {
+		run, _, err = gwClient.GetUserRun(ctx, o.username, o.runNumber)
+	}
+
+	return run, errors.WithStack(err)
+}
+
+func (o *runRefOptions) runAction(ctx context.Context, gwClient *gwclient.Client, req *gwapitypes.RunActionsRequest) (*gwapitypes.RunResponse, error) {
+	var run *gwapitypes.RunResponse
+	var err error
+	if o.isProject() {
+		run, _, err = gwClient.ProjectRunAction(ctx, o.projectRef, o.runNumber, req)
+	} else {
+		run, _, err = gwClient.UserRunAction(ctx, o.username, o.runNumber, req)
+	}
+
+	return run, errors.WithStack(err)
+}
+
+func checkOutputFormat(format string) error {
+	switch format {
+	case outputFormatText, outputFormatJSON:
+		return nil
+	default:
+		return errors.Errorf("unknown output format %q", format)
+	}
+}
+
+func printJSON(v any) error {
+	out, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(out))
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,149 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdRunGet = &cobra.Command{
+	Use: "get",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runGet(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "get a project or user run with its tasks graph",
+}
+
+type runGetOptions struct {
+	runRefOptions
+	format string
+}
+
+var runGetOpts runGetOptions
+
+func init() {
+	flags := cmdRunGet.Flags()
+
+	addRunRefFlags(cmdRunGet, &runGetOpts.runRefOptions)
+	flags.StringVar(&runGetOpts.format, "format", outputFormatText, `output format ("text" or "json")`)
+
+	cmdRun.AddCommand(cmdRunGet)
+}
+
+func runGet(cmd *cobra.Command, args []string) error {
+	if err := runGetOpts.check(cmd); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := checkOutputFormat(runGetOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	run, err := runGetOpts.getRun(context.TODO(), gwClient)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if runGetOpts.format == outputFormatJSON {
+		return printJSON(run)
+	}
+
+	printRunGraph(os.Stdout, run, time.Now())
+
+	return nil
+}
+
+// printRunGraph prints the run and its tasks ordered by level and name. Tasks
+// are indented by their level and report the tasks they depend on.
+func printRunGraph(w io.Writer, run *gwapitypes.RunResponse, now time.Time) {
+	fmt.Fprintf(w, "Run: %d, Name: %s, Phase: %s, Result: %s, Duration: %s\n", run.Number, run.Name, run.Phase, run.Result, formatDuration(run.StartTime, run.EndTime, now))
+	if run.Stopping {
+		fmt.Fprintf(w, "Stopping\n")
+	}
+	for _, setupError := range run.SetupErrors {
+		fmt.Fprintf(w, "Setup error: %s\n", setupError)
+	}
+
+	tasks := make([]*gwapitypes.RunResponseTask, 0, len(run.Tasks))
+	for _, task := range run.Tasks {
+		tasks = append(tasks, task)
+	}
+	slices.SortFunc(tasks, func(a, b *gwapitypes.RunResponseTask) int {
+		if n := cmp.Compare(a.Level, b.Level); n != 0 {
+			return n
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	if len(tasks) > 0 {
+		fmt.Fprintf(w, "Tasks:\n")
+	}
+	for _, task := range tasks {
+		indent := strings.Repeat("  ", task.Level)
+
+		status := string(task.Status)
+		if task.WaitingApproval {
+			status = "waiting approval"
+		}
+		if task.Timedout {
+			status += " (timed out)"
+		}
+
+		fmt.Fprintf(w, "\t%s%s, ID: %s, Status: %s, Duration: %s\n", indent, task.Name, task.ID, status, formatDuration(task.StartTime, task.EndTime, now))
+
+		depends := []string{}
+		for _, d := range task.Depends {
+			if dt, ok := run.Tasks[d.TaskID]; ok {
+				depends = append(depends, dt.Name)
+			} else {
+				depends = append(depends, d.TaskID)
+			}
+		}
+		slices.Sort(depends)
+		if len(depends) > 0 {
+			fmt.Fprintf(w, "\t%s  Depends on: %s\n", indent, strings.Join(depends, ", "))
+		}
+	}
+}
+
+// formatDuration returns the duration between start and end (or now if end is
+// nil) or "-" if not started.
+func formatDuration(start, end *time.Time, now time.Time) string {
+	if start == nil {
+		return "-"
+	}
+	if end != nil {
+		now = *end
+	}
+
+	return now.Sub(*start).Round(time.Second).String()
+}
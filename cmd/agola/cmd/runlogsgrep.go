@@ -0,0 +1,141 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdRunLogsGrep = &cobra.Command{
+	Use:   "grep PATTERN",
+	Short: "search a pattern in the step logs of a run",
+	Long: `search a pattern in the step logs of a run
+
+Only the logs already archived are searched. The matching lines are printed as
+"TASKNAME/STEP:LINE:TEXT" and the context lines as "TASKNAME/STEP-LINE-TEXT",
+where LINE is the zero based line number.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runLogsGrep(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+}
+
+type runLogsGrepOptions struct {
+	runRefOptions
+	tasknames  []string
+	regex      bool
+	ignoreCase bool
+	context    int
+	limit      int
+	format     string
+}
+
+var runLogsGrepOpts runLogsGrepOptions
+
+func init() {
+	flags := cmdRunLogsGrep.Flags()
+
+	addRunRefFlags(cmdRunLogsGrep, &runLogsGrepOpts.runRefOptions)
+	flags.StringSliceVar(&runLogsGrepOpts.tasknames, "taskname", nil, "search only the logs of the provided tasks (can be repeated)")
+	flags.BoolVarP(&runLogsGrepOpts.regex, "regex", "E", false, "the pattern is a regular expression")
+	flags.BoolVarP(&runLogsGrepOpts.ignoreCase, "ignore-case", "i", false, "ignore case distinctions")
+	flags.IntVarP(&runLogsGrepOpts.context, "context", "C", 0, "number of context lines to print before and after a matching line")
+	flags.IntVar(&runLogsGrepOpts.limit, "limit", 0, "maximum number of matches (0 means the server default)")
+	flags.StringVar(&runLogsGrepOpts.format, "format", outputFormatText, `output format ("text" or "json")`)
+
+	cmdRunLogs.AddCommand(cmdRunLogsGrep)
+}
+
+func runLogsGrep(cmd *cobra.Command, args []string) error {
+	if err := runLogsGrepOpts.check(cmd); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := checkOutputFormat(runLogsGrepOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	opts := &gwclient.SearchRunLogsOptions{
+		Regex:      runLogsGrepOpts.regex,
+		IgnoreCase: runLogsGrepOpts.ignoreCase,
+		Context:    runLogsGrepOpts.context,
+		Limit:      runLogsGrepOpts.limit,
+	}
+
+	if len(runLogsGrepOpts.tasknames) > 0 {
+		run, err := runLogsGrepOpts.getRun(context.TODO(), gwClient)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, name := range runLogsGrepOpts.tasknames {
+			var task *gwapitypes.RunResponseTask
+			for _, t := range run.Tasks {
+				if t.Name == name {
+					task = t
+					break
+				}
+			}
+			if task == nil {
+				return errors.Errorf("task %q not found in run %d", name, run.Number)
+			}
+			opts.TaskIDs = append(opts.TaskIDs, task.ID)
+		}
+	}
+
+	var res *gwapitypes.RunLogsSearchResponse
+	var err error
+	if runLogsGrepOpts.isProject() {
+		res, _, err = gwClient.SearchProjectRunLogs(context.TODO(), runLogsGrepOpts.projectRef, runLogsGrepOpts.runNumber, args[0], opts)
+	} else {
+		res, _, err = gwClient.SearchUserRunLogs(context.TODO(), runLogsGrepOpts.username, runLogsGrepOpts.runNumber, args[0], opts)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to search run logs")
+	}
+
+	if runLogsGrepOpts.format == outputFormatJSON {
+		return printJSON(res)
+	}
+
+	for i, m := range res.Matches {
+		// separate the groups of lines like grep does
+		if runLogsGrepOpts.context > 0 && i > 0 {
+			fmt.Println("--")
+		}
+		for _, l := range m.Before {
+			fmt.Printf("%s/%d-%d-%s\n", m.TaskName, m.Step, l.Number, l.Text)
+		}
+		fmt.Printf("%s/%d:%d:%s\n", m.TaskName, m.Step, m.Line.Number, m.Line.Text)
+		for _, l := range m.After {
+			fmt.Printf("%s/%d-%d-%s\n", m.TaskName, m.Step, l.Number, l.Text)
+		}
+	}
+	if res.Truncated {
+		log.Warn().Msgf("output truncated to %d matches", len(res.Matches))
+	}
+
+	return nil
+}
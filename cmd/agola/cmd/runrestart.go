@@ -0,0 +1,84 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdRunRestart = &cobra.Command{
+	Use: "restart",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runRestart(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "restart a finished project or user run creating a new run",
+}
+
+type runRestartOptions struct {
+	runRefOptions
+	fromFailed bool
+	format     string
+}
+
+var runRestartOpts runRestartOptions
+
+func init() {
+	flags := cmdRunRestart.Flags()
+
+	addRunRefFlags(cmdRunRestart, &runRestartOpts.runRefOptions)
+	flags.BoolVar(&runRestartOpts.fromFailed, "from-failed", false, "restart only the failed tasks (and their children) keeping the successful ones instead of restarting from scratch")
+	flags.StringVar(&runRestartOpts.format, "format", outputFormatText, `output format ("text" or "json")`)
+
+	cmdRun.AddCommand(cmdRunRestart)
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	if err := runRestartOpts.check(cmd); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := checkOutputFormat(runRestartOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	req := &gwapitypes.RunActionsRequest{
+		ActionType: gwapitypes.RunActionTypeRestart,
+		FromStart:  !runRestartOpts.fromFailed,
+	}
+
+	run, err := runRestartOpts.runAction(context.TODO(), gwClient, req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to restart run %d", runRestartOpts.runNumber)
+	}
+
+	if runRestartOpts.format == outputFormatJSON {
+		return printJSON(run)
+	}
+
+	fmt.Printf("run %d restarted as run %d\n", runRestartOpts.runNumber, run.Number)
+
+	return nil
+}
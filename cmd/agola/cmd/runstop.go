@@ -0,0 +1,81 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdRunStop = &cobra.Command{
+	Use: "stop",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runStop(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "stop a project or user run",
+}
+
+type runStopOptions struct {
+	runRefOptions
+	format string
+}
+
+var runStopOpts runStopOptions
+
+func init() {
+	flags := cmdRunStop.Flags()
+
+	addRunRefFlags(cmdRunStop, &runStopOpts.runRefOptions)
+	flags.StringVar(&runStopOpts.format, "format", outputFormatText, `output format ("text" or "json")`)
+
+	cmdRun.AddCommand(cmdRunStop)
+}
+
+func runStop(cmd *cobra.Command, args []string) error {
+	if err := runStopOpts.check(cmd); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := checkOutputFormat(runStopOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	req := &gwapitypes.RunActionsRequest{
+		ActionType: gwapitypes.RunActionTypeStop,
+	}
+
+	run, err := runStopOpts.runAction(context.TODO(), gwClient, req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to stop run %d", runStopOpts.runNumber)
+	}
+
+	if runStopOpts.format == outputFormatJSON {
+		return printJSON(run)
+	}
+
+	fmt.Printf("stopping run %d\n", run.Number)
+
+	return nil
+}
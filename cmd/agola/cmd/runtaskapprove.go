@@ -0,0 +1,123 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdRunTaskApprove = &cobra.Command{
+	Use: "approve",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runTaskApprove(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "approve a run task waiting for approval",
+}
+
+type runTaskApproveOptions struct {
+	runRefOptions
+	taskname string
+	taskid   string
+	format   string
+}
+
+var runTaskApproveOpts runTaskApproveOptions
+
+func init() {
+	flags := cmdRunTaskApprove.Flags()
+
+	addRunRefFlags(cmdRunTaskApprove, &runTaskApproveOpts.runRefOptions)
+	flags.StringVar(&runTaskApproveOpts.taskname, "taskname", "", "Task name")
+	flags.StringVar(&runTaskApproveOpts.taskid, "taskid", "", "Task Id")
+	flags.StringVar(&runTaskApproveOpts.format, "format", outputFormatText, `output format ("text" or "json")`)
+
+	cmdRunTask.AddCommand(cmdRunTaskApprove)
+}
+
+func runTaskApprove(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	if err := runTaskApproveOpts.check(cmd); err != nil {
+		return errors.WithStack(err)
+	}
+	if flags.Changed("taskname") && flags.Changed("taskid") {
+		return errors.Errorf(`only one of "--taskname" or "--taskid" can be provided`)
+	}
+	if !flags.Changed("taskname") && !flags.Changed("taskid") {
+		return errors.Errorf(`one of "--taskname" or "--taskid" must be provided`)
+	}
+	if err := checkOutputFormat(runTaskApproveOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	run, err := runTaskApproveOpts.getRun(context.TODO(), gwClient)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var task *gwapitypes.RunResponseTask
+	for _, t := range run.Tasks {
+		if (flags.Changed("taskid") && t.ID == runTaskApproveOpts.taskid) || (flags.Changed("taskname") && t.Name == runTaskApproveOpts.taskname) {
+			task = t
+			break
+		}
+	}
+	if task == nil {
+		return errors.Errorf("task not found in run %d", run.Number)
+	}
+
+	req := &gwapitypes.RunTaskActionsRequest{
+		ActionType: gwapitypes.RunTaskActionTypeApprove,
+	}
+	if runTaskApproveOpts.isProject() {
+		_, err = gwClient.ProjectRunTaskAction(context.TODO(), runTaskApproveOpts.projectRef, run.Number, task.ID, req)
+	} else {
+		_, err = gwClient.UserRunTaskAction(context.TODO(), runTaskApproveOpts.username, run.Number, task.ID, req)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to approve task %q", task.Name)
+	}
+
+	// get the updated run to report the task approval status
+	run, err = runTaskApproveOpts.getRun(context.TODO(), gwClient)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	task = run.Tasks[task.ID]
+
+	if runTaskApproveOpts.format == outputFormatJSON {
+		return printJSON(task)
+	}
+
+	if task.Approved {
+		fmt.Printf("task %q approved\n", task.Name)
+	} else {
+		fmt.Printf("task %q approval registered, waiting for other approvals\n", task.Name)
+	}
+
+	return nil
+}
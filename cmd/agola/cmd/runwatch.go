@@ -0,0 +1,179 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sorintlab/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	gwclient "agola.io/agola/services/gateway/client"
+)
+
+var cmdRunWatch = &cobra.Command{
+	Use: "watch",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runWatch(cmd, args); err != nil {
+			log.Fatal().Err(err).Send()
+		}
+	},
+	Short: "watch a project or user run refreshing its tasks graph until the run finishes",
+	Long: `watch a project or user run refreshing its tasks graph until the run finishes.
+
+The run is refreshed as soon as it changes using the gateway run events stream. When the stream isn't available the run is refreshed at every interval.`,
+}
+
+type runWatchOptions struct {
+	runRefOptions
+	interval time.Duration
+	format   string
+}
+
+var runWatchOpts runWatchOptions
+
+func init() {
+	flags := cmdRunWatch.Flags()
+
+	addRunRefFlags(cmdRunWatch, &runWatchOpts.runRefOptions)
+	flags.DurationVar(&runWatchOpts.interval, "interval", 2*time.Second, "refresh interval")
+	flags.StringVar(&runWatchOpts.format, "format", outputFormatText, `output format ("text" or "json"). With "json" a json document is printed on a single line every time the run changes`)
+
+	cmdRun.AddCommand(cmdRunWatch)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if err := runWatchOpts.check(cmd); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := checkOutputFormat(runWatchOpts.format); err != nil {
+		return errors.WithStack(err)
+	}
+	if runWatchOpts.interval <= 0 {
+		return errors.Errorf("refresh interval must be greater than zero")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gwClient := gwclient.NewClient(gatewayURL, token)
+
+	// when the output is a terminal redraw the run at every refresh to
+	// update the durations, otherwise print it only when it changes
+	isTerminal := runWatchOpts.format == outputFormatText && term.IsTerminal(int(os.Stdout.Fd()))
+
+	// refetch the run when it changes using the run events stream. If the
+	// stream isn't available fallback to refetch the run at every refresh.
+	// The stream is opened before getting the run to not miss any change.
+	changedCh := make(chan struct{}, 1)
+	var streaming atomic.Bool
+	if err := runWatchOpts.watchRunEvents(ctx, gwClient, changedCh, &streaming); err != nil {
+		log.Debug().Err(err).Msg("failed to open run events stream, falling back to polling")
+	}
+
+	var run *gwapitypes.RunResponse
+	var prevRunj []byte
+	refetch := true
+	for {
+		if refetch {
+			var err error
+			run, err = runWatchOpts.getRun(ctx, gwClient)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		runj, err := json.Marshal(run)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		changed := !bytes.Equal(runj, prevRunj)
+		prevRunj = runj
+
+		if isTerminal {
+			// clear the screen and move the cursor to the top left
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Every %s, last update: %s\n\n", runWatchOpts.interval, time.Now().Format(time.RFC3339))
+			printRunGraph(os.Stdout, run, time.Now())
+		} else if changed {
+			if runWatchOpts.format == outputFormatJSON {
+				fmt.Println(string(runj))
+			} else {
+				printRunGraph(os.Stdout, run, time.Now())
+				fmt.Println()
+			}
+		}
+
+		if run.Phase.IsFinished() {
+			return nil
+		}
+
+		timer := time.NewTimer(runWatchOpts.interval)
+		select {
+		case <-changedCh:
+			timer.Stop()
+			refetch = true
+		case <-timer.C:
+			refetch = !streaming.Load()
+		}
+	}
+}
+
+// watchRunEvents opens the run events stream and notifies changedCh when the
+// watched run changes. streaming is set to false when the stream ends.
+func (o *runRefOptions) watchRunEvents(ctx context.Context, gwClient *gwclient.Client, changedCh chan<- struct{}, streaming *atomic.Bool) error {
+	opts := &gwclient.GetRunEventsOptions{}
+	if o.isProject() {
+		opts.ProjectRefs = []string{o.projectRef}
+	} else {
+		opts.UserRefs = []string{o.username}
+	}
+
+	resp, err := gwClient.GetRunEvents(ctx, opts)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	streaming.Store(true)
+	go func() {
+		defer resp.Body.Close()
+		defer streaming.Store(false)
+
+		err := gwclient.ReadRunEvents(resp.Body, func(ev *gwapitypes.RunEventResponse) error {
+			if ev.Run.Number != o.runNumber {
+				return nil
+			}
+			select {
+			case changedCh <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+		if err != nil && ctx.Err() == nil {
+			log.Debug().Err(err).Msg("run events stream error, falling back to polling")
+		}
+	}()
+
+	return nil
+}
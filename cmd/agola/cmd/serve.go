@@ -32,6 +32,7 @@ import (
 	"agola.io/agola/internal/services/notification"
 	"agola.io/agola/internal/services/runservice"
 	"agola.io/agola/internal/services/scheduler"
+	"agola.io/agola/internal/tracing"
 )
 
 var (
@@ -105,6 +106,23 @@ func serve(cmd *cobra.Command, args []string) error {
 		return errors.Wrapf(err, "config error")
 	}
 
+	if c.Tracing.Enabled {
+		shutdownTracing, err := tracing.Setup(ctx, &tracing.Config{
+			Endpoint:    c.Tracing.Endpoint,
+			Insecure:    c.Tracing.Insecure,
+			ServiceName: c.Tracing.ServiceName,
+			SampleRatio: c.Tracing.SampleRatio,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to setup tracing")
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				log.Err(err).Msg("failed to shutdown tracing")
+			}
+		}()
+	}
+
 	var rs *runservice.Runservice
 	if isComponentEnabled("runservice") {
 		rs, err = runservice.NewRunservice(ctx, log.Logger, &c.Runservice)
@@ -105,5 +105,5 @@ func NewObjectStorage(ctx context.Context, c *config.ObjectStorage) (objectstora
 		}
 	}
 
-	return ost, nil
+	return objectstorage.NewTraced(ost, string(c.Type)), nil
 }
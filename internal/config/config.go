@@ -36,6 +36,8 @@ const (
 	maxStepNameLength = 100
 
 	defaultWorkingDir = "~/project"
+
+	defaultStarlarkFilename = "config.star"
 )
 
 type ConfigFormat int
@@ -128,11 +130,30 @@ type Task struct {
 	Depends              Depends                        `json:"depends"`
 	IgnoreFailure        bool                           `json:"ignore_failure"`
 	Approval             bool                           `json:"approval"`
+	ApprovalPolicy       *ApprovalPolicy                `json:"approval_policy"`
 	When                 *When                          `json:"when"`
 	DockerRegistriesAuth map[string]*DockerRegistryAuth `json:"docker_registries_auth"`
 	TaskTimeoutInterval  *types.Duration                `json:"task_timeout_interval"`
 }
 
+// ApprovalPolicy defines who and how many users must approve a task. Defining
+// an approval policy implies that the task requires approval.
+type ApprovalPolicy struct {
+	// MinApprovers is the minimum number of required approvals (default 1)
+	MinApprovers int `json:"min_approvers"`
+	// AllowedUsers, AllowedTeams and AllowedRoles limit the users that can
+	// approve the task. When none is defined every user that can execute task
+	// actions can approve it.
+	AllowedUsers []string `json:"allowed_users"`
+	AllowedTeams []string `json:"allowed_teams"`
+	AllowedRoles []string `json:"allowed_roles"`
+	// ForbidSelfApproval forbids the approval by the user that triggered the
+	// run or by the author of its commit or pull request
+	ForbidSelfApproval bool `json:"forbid_self_approval"`
+	// Expiry is the time after which an approval isn't considered anymore
+	Expiry *types.Duration `json:"expiry"`
+}
+
 type DependCondition string
 
 const (
@@ -473,6 +494,10 @@ type when struct {
 	Branch interface{} `json:"branch"`
 	Tag    interface{} `json:"tag"`
 	Ref    interface{} `json:"ref"`
+
+	PullRequestLabel        interface{} `json:"pull_request_label"`
+	PullRequestTargetBranch interface{} `json:"pull_request_target_branch"`
+	PullRequestDraft        *bool       `json:"pull_request_draft"`
 }
 
 func (w *When) ToWhen() *types.When {
@@ -508,6 +533,22 @@ func (w *When) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	if wi.PullRequestLabel != nil {
+		w.PullRequestLabel, err = parseWhenConditions(wi.PullRequestLabel)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	if wi.PullRequestTargetBranch != nil {
+		w.PullRequestTargetBranch, err = parseWhenConditions(wi.PullRequestTargetBranch)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	w.PullRequestDraft = wi.PullRequestDraft
+
 	return nil
 }
 
@@ -660,9 +701,33 @@ type ConfigContext struct {
 	Tag           string            `json:"tag"`
 	PullRequestID string            `json:"pull_request_id"`
 	CommitSHA     string            `json:"commit_sha"`
+
+	PullRequestLabels       []string `json:"pull_request_labels"`
+	PullRequestTargetBranch string   `json:"pull_request_target_branch"`
+	PullRequestDraft        bool     `json:"pull_request_draft"`
+}
+
+// WhenPullRequest returns the pull request data used to match the when
+// conditions. It returns nil when the context isn't a pull request.
+func (cc *ConfigContext) WhenPullRequest() *types.WhenPullRequest {
+	if cc.RefType != itypes.RunRefTypePullRequest {
+		return nil
+	}
+
+	return &types.WhenPullRequest{
+		Labels:       cc.PullRequestLabels,
+		TargetBranch: cc.PullRequestTargetBranch,
+		Draft:        cc.PullRequestDraft,
+	}
 }
 
 func ParseConfig(configData []byte, format ConfigFormat, configContext *ConfigContext) (*Config, error) {
+	return ParseConfigFile("", configData, format, configContext)
+}
+
+// ParseConfigFile is like ParseConfig but reports the provided file name in
+// the positions of the jsonnet and starlark errors.
+func ParseConfigFile(filename string, configData []byte, format ConfigFormat, configContext *ConfigContext) (*Config, error) {
 	// TODO(sgotti) execute jsonnet and starlark executor in a
 	// separate process to avoid issues with malformat config that
 	// could lead to infinite executions and memory exhaustion
@@ -670,14 +735,17 @@ func ParseConfig(configData []byte, format ConfigFormat, configContext *ConfigCo
 	case ConfigFormatJsonnet:
 		// Generate json from jsonnet
 		var err error
-		configData, err = execJsonnet(configData, configContext)
+		configData, err = execJsonnet(filename, configData, configContext)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to execute jsonnet")
 		}
 	case ConfigFormatStarlark:
 		// Generate json from starlark
+		if filename == "" {
+			filename = defaultStarlarkFilename
+		}
 		var err error
-		configData, err = execStarlark(configData, configContext)
+		configData, err = execStarlark(filename, configData, configContext)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to execute starlark")
 		}
@@ -689,13 +757,47 @@ func ParseConfig(configData []byte, format ConfigFormat, configContext *ConfigCo
 
 	config := DefaultConfig
 	if err := yaml.Unmarshal(configData, &config); err != nil {
+		// the unmarshal errors don't report where the error is, report the
+		// schema violations if any
+		if serr := validateSchema(configData); serr != nil {
+			return nil, errors.Wrapf(serr, "invalid config")
+		}
 		return nil, errors.Wrapf(err, "failed to unmarshal config")
 	}
 
-	return &config, checkConfig(&config)
+	return &config, checkConfig(configData, &config)
+}
+
+func checkApprovalPolicy(ap *ApprovalPolicy) error {
+	if ap == nil {
+		return nil
+	}
+
+	if ap.MinApprovers < 0 {
+		return errors.Errorf("min_approvers must be greater than or equal to 0")
+	}
+	for _, role := range ap.AllowedRoles {
+		if !isValidApprovalRole(role) {
+			return errors.Errorf("invalid allowed role %q", role)
+		}
+	}
+	if ap.Expiry != nil && ap.Expiry.Duration <= 0 {
+		return errors.Errorf("expiry must be greater than 0")
+	}
+
+	return nil
+}
+
+func isValidApprovalRole(role string) bool {
+	switch role {
+	case "owner", "admin", "maintainer", "developer", "member", "viewer":
+		return true
+	}
+
+	return false
 }
 
-func checkConfig(config *Config) error {
+func checkConfig(configData []byte, config *Config) error {
 	if len(config.Runs) == 0 {
 		return errors.Errorf("no runs defined")
 	}
@@ -765,6 +867,10 @@ func checkConfig(config *Config) error {
 					}
 				}
 			}
+
+			if err := checkApprovalPolicy(task.ApprovalPolicy); err != nil {
+				return errors.Wrapf(err, "task %q approval policy", task.Name)
+			}
 		}
 	}
 
@@ -912,6 +1018,11 @@ func checkConfig(config *Config) error {
 				task.WorkingDir = defaultWorkingDir
 			}
 
+			// an approval policy implies that the task requires approval
+			if task.ApprovalPolicy != nil {
+				task.Approval = true
+			}
+
 			// set task runtime type to pod if empty
 			r := task.Runtime
 			if r.Type == "" {
@@ -953,6 +1064,12 @@ func checkConfig(config *Config) error {
 		}
 	}
 
+	// report the schema violations also for the configs accepted by the
+	// unmarshalling and by the above checks
+	if err := validateSchema(configData); err != nil {
+		return errors.Wrapf(err, "invalid config")
+	}
+
 	return nil
 }
 
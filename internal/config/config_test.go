@@ -16,6 +16,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sorintlab/errors"
@@ -256,6 +257,39 @@ func TestParseConfig(t *testing.T) {
                 `,
 			err: errors.Errorf(`run "run01", task "task01", docker registries auth "index.docker.io" is empty`),
 		},
+		{
+			name: "test task approval policy invalid min approvers",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          type: pod
+                          containers:
+                            - image: alpine/git
+                        approval_policy:
+                          min_approvers: -1
+                `,
+			err: errors.Errorf(`task "task01" approval policy: min_approvers must be greater than or equal to 0`),
+		},
+		{
+			name: "test task approval policy invalid allowed role",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          type: pod
+                          containers:
+                            - image: alpine/git
+                        approval_policy:
+                          allowed_roles:
+                            - superuser
+                `,
+			err: errors.Errorf(`task "task01" approval policy: invalid allowed role "superuser"`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +312,53 @@ func TestParseConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfigFileErrorPosition(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		format   ConfigFormat
+		in       string
+		position string
+	}{
+		{
+			name:     "test jsonnet error position",
+			filename: "config.jsonnet",
+			format:   ConfigFormatJsonnet,
+			in: `function(ctx) {
+  runs: [ { name: unknown } ],
+}`,
+			position: "config.jsonnet:2:19-26",
+		},
+		{
+			name:     "test starlark error position",
+			filename: "myconfig.star",
+			format:   ConfigFormatStarlark,
+			in: `def main(ctx):
+    return { "runs": unknown }`,
+			position: "myconfig.star:2:22",
+		},
+		{
+			name:   "test starlark default file name",
+			format: ConfigFormatStarlark,
+			in: `def main(ctx):
+    return { "runs": unknown }`,
+			position: "config.star:2:22",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseConfigFile(tt.filename, []byte(tt.in), tt.format, &ConfigContext{})
+			assert.ErrorContains(t, err, tt.position)
+		})
+	}
+}
+
 func TestParseOutput(t *testing.T) {
 	t.Parallel()
 
@@ -362,6 +443,10 @@ func TestParseOutput(t *testing.T) {
                           ref:
                             include: master
                             exclude: [ /branch01/ , branch02 ]
+                          pull_request_label: [ run-e2e ]
+                          pull_request_target_branch:
+                            exclude: /release-.*/
+                          pull_request_draft: false
                         depends:
                           - task: task02
                             conditions:
@@ -545,6 +630,17 @@ func TestParseOutput(t *testing.T) {
 											{Type: types.WhenConditionTypeSimple, Match: "branch02"},
 										},
 									},
+									PullRequestLabel: &types.WhenConditions{
+										Include: []types.WhenCondition{
+											{Type: types.WhenConditionTypeSimple, Match: "run-e2e"},
+										},
+									},
+									PullRequestTargetBranch: &types.WhenConditions{
+										Exclude: []types.WhenCondition{
+											{Type: types.WhenConditionTypeRegExp, Match: "release-.*"},
+										},
+									},
+									PullRequestDraft: util.Ptr(false),
 								},
 								Depends: []*Depend{
 									{TaskName: "task02", Conditions: []DependCondition{DependConditionOnSuccess, DependConditionOnFailure}},
@@ -644,6 +740,60 @@ func TestParseOutput(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test task approval policy",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          type: pod
+                          containers:
+                            - image: image01
+                        approval_policy:
+                          min_approvers: 2
+                          allowed_users:
+                            - user01
+                          allowed_teams:
+                            - team01
+                          allowed_roles:
+                            - maintainer
+                          forbid_self_approval: true
+                          expiry: 24h
+                `,
+			out: &Config{
+				Runs: []*Run{
+					{
+						Name: "run01",
+						Tasks: []*Task{
+							{
+								Name: "task01",
+								Runtime: &Runtime{
+									Type: "pod",
+									Arch: "",
+									Containers: []*Container{
+										{
+											Image: "image01",
+										},
+									},
+								},
+								WorkingDir: defaultWorkingDir,
+								Approval:   true,
+								ApprovalPolicy: &ApprovalPolicy{
+									MinApprovers:       2,
+									AllowedUsers:       []string{"user01"},
+									AllowedTeams:       []string{"team01"},
+									AllowedRoles:       []string{"maintainer"},
+									ForbidSelfApproval: true,
+									Expiry:             &types.Duration{Duration: 24 * time.Hour},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -654,6 +804,9 @@ func TestParseOutput(t *testing.T) {
 			out, err := ParseConfig([]byte(tt.in), ConfigFormatJSON, &ConfigContext{})
 			testutil.NilError(t, err)
 
+			// valid configs must be valid also for the run config schema
+			testutil.NilError(t, validateSchema([]byte(tt.in)))
+
 			assert.DeepEqual(t, tt.out, out, cmp.Comparer(func(x, y *resource.Quantity) bool {
 				if x == nil && y == nil {
 					return true
@@ -21,15 +21,21 @@ import (
 	"github.com/sorintlab/errors"
 )
 
-func execJsonnet(configData []byte, configContext *ConfigContext) ([]byte, error) {
+func execJsonnet(filename string, configData []byte, configContext *ConfigContext) ([]byte, error) {
 	vm := jsonnet.MakeVM()
-	cj, err := json.Marshal(configContext)
+
+	// provide an empty list instead of null when there're no pull request labels
+	cc := *configContext
+	if cc.PullRequestLabels == nil {
+		cc.PullRequestLabels = []string{}
+	}
+	cj, err := json.Marshal(cc)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to marshal config context")
 	}
 
 	vm.TLACode("ctx", string(cj))
-	out, err := vm.EvaluateAnonymousSnippet("", string(configData))
+	out, err := vm.EvaluateAnonymousSnippet(filename, string(configData))
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to evaluate jsonnet config")
 	}
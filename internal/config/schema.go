@@ -0,0 +1,402 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ghodss/yaml"
+	"github.com/invopop/jsonschema"
+	jsonschemav "github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"github.com/sorintlab/errors"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/types"
+)
+
+//go:generate go run agola.io/agola/internal/generators/configschema -output runconfig.schema.json
+
+const (
+	// SchemaID is the id of the run config JSON Schema
+	SchemaID = "https://agola.io/schemas/runconfig.json"
+)
+
+//go:embed runconfig.schema.json
+var schemaData []byte
+
+var (
+	schemaErrorsPrinter = message.NewPrinter(language.English)
+
+	compiledSchema     *jsonschemav.Schema
+	compiledSchemaErr  error
+	compiledSchemaOnce sync.Once
+)
+
+// Schema returns the run config JSON Schema.
+func Schema() []byte {
+	return schemaData
+}
+
+// stepTypes are the step types with their config struct.
+var stepTypes = []struct {
+	name string
+	t    reflect.Type
+}{
+	{"clone", reflect.TypeOf(CloneStep{})},
+	{"run", reflect.TypeOf(RunStep{})},
+	{"save_to_workspace", reflect.TypeOf(SaveToWorkspaceStep{})},
+	{"restore_workspace", reflect.TypeOf(RestoreWorkspaceStep{})},
+	{"save_cache", reflect.TypeOf(SaveCacheStep{})},
+	{"restore_cache", reflect.TypeOf(RestoreCacheStep{})},
+}
+
+// GenerateSchema generates the run config JSON Schema from the config types.
+// The types with a custom json unmarshaller are mapped to a schema accepting
+// all the formats handled by their unmarshaller.
+func GenerateSchema() ([]byte, error) {
+	r := &jsonschema.Reflector{
+		Anonymous:                  true,
+		AllowAdditionalProperties:  true,
+		RequiredFromJSONSchemaTags: true,
+		Mapper:                     schemaMapper,
+	}
+
+	s := r.Reflect(&Config{})
+	s.ID = SchemaID
+	s.Title = "Agola run configuration"
+
+	for _, cs := range customSchemas {
+		s.Definitions[cs.name] = cs.schema()
+	}
+
+	// add the steps definitions referenced by the Steps schema
+	for _, st := range stepTypes {
+		ss := r.ReflectFromType(st.t)
+		for name, def := range ss.Definitions {
+			s.Definitions[name] = def
+		}
+	}
+
+	out, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return append(out, '\n'), nil
+}
+
+func stringEnum[T ~string](values ...T) *jsonschema.Schema {
+	s := &jsonschema.Schema{Type: "string"}
+	for _, v := range values {
+		s.Enum = append(s.Enum, string(v))
+	}
+	return s
+}
+
+func stringOrStringArray() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
+// customSchemas are the schemas of the types with a custom json unmarshaller.
+// They are added to the schema definitions with the provided name.
+var customSchemas = map[reflect.Type]struct {
+	name   string
+	schema func() *jsonschema.Schema
+}{
+	reflect.TypeOf(Steps{}):             {"Steps", stepsSchema},
+	reflect.TypeOf(Depends{}):           {"Depends", dependsSchema},
+	reflect.TypeOf(Value{}):             {"Value", valueSchema},
+	reflect.TypeOf(When{}):              {"When", whenSchema},
+	reflect.TypeOf(types.Duration{}):    {"Duration", durationSchema},
+	reflect.TypeOf(resource.Quantity{}): {"Quantity", quantitySchema},
+}
+
+func schemaMapper(t reflect.Type) *jsonschema.Schema {
+	if cs, ok := customSchemas[t]; ok {
+		return &jsonschema.Schema{Ref: "#/$defs/" + cs.name}
+	}
+
+	switch t {
+	case reflect.TypeOf(types.Arch("")):
+		return stringEnum(types.ValidArchs...)
+	case reflect.TypeOf(RuntimeType("")):
+		return stringEnum(RuntimeTypePod)
+	case reflect.TypeOf(DockerRegistryAuthType("")):
+		return stringEnum(DockerRegistryAuthTypeBasic, DockerRegistryAuthTypeEncodedAuth)
+	case reflect.TypeOf(DependCondition("")):
+		return stringEnum(DependConditionOnSuccess, DependConditionOnFailure, DependConditionOnSkipped)
+	}
+
+	return nil
+}
+
+// dependsSchema returns the schema of the task depends. A depend is defined
+// using the format "taskname", { task: "taskname", conditions: [ list of
+// conditions ] } or { "taskname": [ list of conditions ] }.
+func dependsSchema() *jsonschema.Schema {
+	conditions := &jsonschema.Schema{Type: "array", Items: stringEnum(DependConditionOnSuccess, DependConditionOnFailure, DependConditionOnSkipped)}
+	dependProps := jsonschema.NewProperties()
+	dependProps.Set("task", &jsonschema.Schema{Type: "string"})
+	dependProps.Set("conditions", conditions)
+	one := uint64(1)
+
+	return &jsonschema.Schema{
+		Type: "array",
+		Items: &jsonschema.Schema{
+			AnyOf: []*jsonschema.Schema{
+				{Type: "string"},
+				{Type: "object", Properties: dependProps, Required: []string{"task"}},
+				{Type: "object", MinProperties: &one, MaxProperties: &one, AdditionalProperties: conditions},
+			},
+		},
+	}
+}
+
+// valueSchema returns the schema of a value defined as a string or as {
+// from_variable: "variablename" }.
+func valueSchema() *jsonschema.Schema {
+	props := jsonschema.NewProperties()
+	props.Set("from_variable", &jsonschema.Schema{Type: "string"})
+
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "object", Properties: props, Required: []string{"from_variable"}},
+		},
+	}
+}
+
+// whenSchema returns the schema of the when conditions. Every condition could
+// be a string, a list of strings or an object with the include and exclude
+// conditions.
+func whenSchema() *jsonschema.Schema {
+	includeProps := jsonschema.NewProperties()
+	includeProps.Set("include", stringOrStringArray())
+	includeProps.Set("exclude", stringOrStringArray())
+	conditions := &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+			{Type: "object", Properties: includeProps, AdditionalProperties: jsonschema.FalseSchema},
+		},
+	}
+	props := jsonschema.NewProperties()
+	props.Set("branch", conditions)
+	props.Set("tag", conditions)
+	props.Set("ref", conditions)
+	props.Set("pull_request_label", conditions)
+	props.Set("pull_request_target_branch", conditions)
+	props.Set("pull_request_draft", &jsonschema.Schema{Type: "boolean"})
+
+	return &jsonschema.Schema{Type: "object", Properties: props}
+}
+
+func durationSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string", Description: `duration in go format (i.e. "1h10m")`},
+			{Type: "number", Description: "duration in nanoseconds"},
+		},
+	}
+}
+
+func quantitySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string", Description: `quantity (i.e. "512Mi")`},
+			{Type: "number"},
+		},
+	}
+}
+
+// stepsSchema returns the schema of the steps. A step is defined using the
+// format { type: "steptype", other step fields } or { "steptype": { other step
+// fields } } (a run step could also be defined as { "run": "command" }).
+func stepsSchema() *jsonschema.Schema {
+	stepNames := []string{}
+	typeConditions := []*jsonschema.Schema{}
+	shortProps := jsonschema.NewProperties()
+	for _, st := range stepTypes {
+		ref := &jsonschema.Schema{Ref: "#/$defs/" + st.t.Name()}
+
+		stepNames = append(stepNames, st.name)
+
+		typeProps := jsonschema.NewProperties()
+		typeProps.Set("type", &jsonschema.Schema{Const: st.name})
+		typeConditions = append(typeConditions, &jsonschema.Schema{
+			If:   &jsonschema.Schema{Properties: typeProps},
+			Then: ref,
+		})
+
+		// a step without fields could be defined as { "steptype": null }
+		if st.name == "run" {
+			shortProps.Set(st.name, &jsonschema.Schema{AnyOf: []*jsonschema.Schema{{Type: "null"}, {Type: "string"}, ref}})
+		} else {
+			shortProps.Set(st.name, &jsonschema.Schema{AnyOf: []*jsonschema.Schema{{Type: "null"}, ref}})
+		}
+	}
+
+	typeProps := jsonschema.NewProperties()
+	typeProps.Set("type", &jsonschema.Schema{Type: "string", Enum: toAnySlice(stepNames)})
+	one := uint64(1)
+
+	return &jsonschema.Schema{
+		Type: "array",
+		Items: &jsonschema.Schema{
+			Type: "object",
+			If:   &jsonschema.Schema{Required: []string{"type"}},
+			Then: &jsonschema.Schema{
+				Properties: typeProps,
+				AllOf:      typeConditions,
+			},
+			Else: &jsonschema.Schema{
+				MinProperties:        &one,
+				MaxProperties:        &one,
+				Properties:           shortProps,
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+		},
+	}
+}
+
+func toAnySlice(s []string) []any {
+	as := make([]any, len(s))
+	for i, v := range s {
+		as[i] = v
+	}
+	return as
+}
+
+func getCompiledSchema() (*jsonschemav.Schema, error) {
+	compiledSchemaOnce.Do(func() {
+		doc, err := jsonschemav.UnmarshalJSON(bytes.NewReader(schemaData))
+		if err != nil {
+			compiledSchemaErr = errors.WithStack(err)
+			return
+		}
+
+		c := jsonschemav.NewCompiler()
+		if err := c.AddResource(SchemaID, doc); err != nil {
+			compiledSchemaErr = errors.WithStack(err)
+			return
+		}
+		compiledSchema, compiledSchemaErr = c.Compile(SchemaID)
+	})
+
+	return compiledSchema, errors.WithStack(compiledSchemaErr)
+}
+
+// validateSchema validates the config data (in json or yaml format) against the
+// run config JSON Schema. It returns an error reporting the location and the
+// reason of every schema violation. Config data that isn't valid json or yaml
+// isn't reported.
+func validateSchema(configData []byte) error {
+	sch, err := getCompiledSchema()
+	if err != nil {
+		return errors.Wrapf(err, "failed to compile run config schema")
+	}
+
+	// syntax errors are already reported by the config unmarshalling
+	jsonData, err := yaml.YAMLToJSON(configData)
+	if err != nil {
+		return nil
+	}
+	inst, err := jsonschemav.UnmarshalJSON(bytes.NewReader(jsonData))
+	if err != nil {
+		return nil
+	}
+
+	err = sch.Validate(inst)
+	if err == nil {
+		return nil
+	}
+	var verr *jsonschemav.ValidationError
+	if !errors.As(err, &verr) {
+		return errors.WithStack(err)
+	}
+
+	errs := &util.Errors{}
+	collectValidationErrors(verr, errs)
+
+	return errs
+}
+
+// collectValidationErrors appends to errs the leaf validation errors since
+// they are the ones reporting the real schema violations.
+// When a value doesn't match any of the anyOf schemas only the errors of the
+// best matching schemas are reported: the ones with the deepest errors. If
+// the value type doesn't match any schema a single type error is reported.
+func collectValidationErrors(verr *jsonschemav.ValidationError, errs *util.Errors) {
+	if len(verr.Causes) == 0 {
+		errs.Append(errors.Errorf("/%s: %s", strings.Join(verr.InstanceLocation, "/"), verr.ErrorKind.LocalizedString(schemaErrorsPrinter)))
+		return
+	}
+
+	causes := verr.Causes
+	if _, ok := verr.ErrorKind.(*kind.AnyOf); ok {
+		want := []string{}
+		maxDepth := 0
+		best := []*jsonschemav.ValidationError{}
+		for _, cause := range causes {
+			if k, ok := cause.ErrorKind.(*kind.Type); ok {
+				want = append(want, k.Want...)
+				continue
+			}
+			depth := validationErrorDepth(cause)
+			if depth > maxDepth {
+				maxDepth = depth
+				best = best[:0]
+			}
+			if depth == maxDepth {
+				best = append(best, cause)
+			}
+		}
+
+		if len(best) == 0 {
+			errs.Append(errors.Errorf("/%s: got %s, want %s", strings.Join(verr.InstanceLocation, "/"), causes[0].ErrorKind.(*kind.Type).Got, strings.Join(want, " or ")))
+			return
+		}
+		causes = best
+	}
+
+	for _, cause := range causes {
+		collectValidationErrors(cause, errs)
+	}
+}
+
+// validationErrorDepth returns the max instance location depth of the leaf
+// validation errors.
+func validationErrorDepth(verr *jsonschemav.ValidationError) int {
+	depth := len(verr.InstanceLocation)
+	for _, cause := range verr.Causes {
+		depth = max(depth, validationErrorDepth(cause))
+	}
+
+	return depth
+}
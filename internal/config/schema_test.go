@@ -0,0 +1,147 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/sorintlab/errors"
+	"gotest.tools/v3/assert"
+
+	"agola.io/agola/internal/util"
+)
+
+func TestSchemaUpToDate(t *testing.T) {
+	t.Parallel()
+
+	data, err := GenerateSchema()
+	assert.NilError(t, err)
+
+	assert.Equal(t, string(data), string(Schema()), `run config schema is not up to date, run "go generate" in internal/config`)
+}
+
+func TestParseConfigSchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		err  error
+	}{
+		{
+			name: "test wrong steps type",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          containers:
+                            - image: image01
+                        steps: "make"
+                `,
+			err: &util.Errors{
+				Errs: []error{
+					errors.Errorf("/runs/0/tasks/0/steps: got string, want array"),
+				},
+			},
+		},
+		{
+			name: "test wrong step fields and depends",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          containers:
+                            - image: image01
+                        steps:
+                          - type: run
+                            command: 1
+                          - unknown: {}
+                        depends:
+                          - task02: [on_sucess]
+                `,
+			err: &util.Errors{
+				Errs: []error{
+					errors.Errorf("/runs/0/tasks/0/steps/0/command: got number, want string"),
+					errors.Errorf("/runs/0/tasks/0/steps/1: additional properties 'unknown' not allowed"),
+					errors.Errorf("/runs/0/tasks/0/depends/0/task02/0: value must be one of 'on_success', 'on_failure', 'on_skipped'"),
+				},
+			},
+		},
+		{
+			name: "test wrong value type",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          containers:
+                            - image: image01
+                        environment:
+                          ENV01: [value]
+                        steps:
+                          - run: make
+                `,
+			err: &util.Errors{
+				Errs: []error{
+					errors.Errorf("/runs/0/tasks/0/environment/ENV01: got array, want string or object"),
+				},
+			},
+		},
+		{
+			name: "test wrong depends condition accepted by unmarshalling",
+			in: `
+                runs:
+                  - name: run01
+                    tasks:
+                      - name: task01
+                        runtime:
+                          containers:
+                            - image: image01
+                        steps:
+                          - run: make
+                      - name: task02
+                        runtime:
+                          containers:
+                            - image: image01
+                        steps:
+                          - run: make
+                        depends:
+                          - task01: [on_sucess]
+                `,
+			err: &util.Errors{
+				Errs: []error{
+					errors.Errorf("/runs/0/tasks/1/depends/0/task01/0: value must be one of 'on_success', 'on_failure', 'on_skipped'"),
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseConfig([]byte(tt.in), ConfigFormatJSON, &ConfigContext{})
+			var errs *util.Errors
+			assert.Assert(t, errors.As(err, &errs), "unexpected error: %v", err)
+			assert.Assert(t, errs.Equal(tt.err), "got error: %v", err)
+		})
+	}
+}
@@ -44,6 +44,19 @@ func starlarkArgs(cc *ConfigContext) (starlark.Tuple, error) {
 	if err := d.SetKey(starlark.String("commit_sha"), starlark.String(cc.CommitSHA)); err != nil {
 		return nil, errors.WithStack(err)
 	}
+	labels := make([]starlark.Value, len(cc.PullRequestLabels))
+	for i, label := range cc.PullRequestLabels {
+		labels[i] = starlark.String(label)
+	}
+	if err := d.SetKey(starlark.String("pull_request_labels"), starlark.NewList(labels)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := d.SetKey(starlark.String("pull_request_target_branch"), starlark.String(cc.PullRequestTargetBranch)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := d.SetKey(starlark.String("pull_request_draft"), starlark.Bool(cc.PullRequestDraft)); err != nil {
+		return nil, errors.WithStack(err)
+	}
 
 	return []starlark.Value{d}, nil
 }
@@ -117,13 +130,13 @@ func starlarkJSON(out *bytes.Buffer, v starlark.Value) error {
 	return nil
 }
 
-func execStarlark(configData []byte, configContext *ConfigContext) ([]byte, error) {
+func execStarlark(filename string, configData []byte, configContext *ConfigContext) ([]byte, error) {
 	thread := &starlark.Thread{
 		Name: "agola-starlark",
 		// TODO(sgotti) redirect print to a logger?
 		Print: func(_ *starlark.Thread, msg string) {},
 	}
-	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, "config.star", configData, nil)
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, filename, configData, nil)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -0,0 +1,49 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command configschema generates the run config JSON Schema from the run
+// config types.
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/config"
+)
+
+var output string
+
+func init() {
+	flag.StringVar(&output, "output", "", "output file")
+}
+
+func main() {
+	flag.Parse()
+
+	if output == "" {
+		panic(errors.Errorf("output file not provided"))
+	}
+
+	data, err := config.GenerateSchema()
+	if err != nil {
+		panic(err)
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		panic(errors.WithStack(err))
+	}
+}
@@ -0,0 +1,744 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucketserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sorintlab/errors"
+	"golang.org/x/oauth2"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/httpclient"
+)
+
+const (
+	restAPIPath         = "/rest/api/1.0"
+	keysAPIPath         = "/rest/keys/1.0"
+	buildStatusAPIPath  = "/rest/build-status/1.0"
+	accessTokensAPIPath = "/rest/access-tokens/1.0"
+	oauth2APIPath       = "/rest/oauth2/latest"
+	whoamiPath          = "/plugins/servlet/applinks/whoami"
+
+	pageLimit = 100
+
+	webhookName = "agola"
+)
+
+var (
+	BitbucketServerOauth2Scopes = []string{"REPO_ADMIN"}
+
+	// permissions of the personal access token created for password auth
+	accessTokenPermissions = []string{"PROJECT_READ", "REPO_ADMIN"}
+
+	webhookEvents = []string{hookRefsChanged, hookPullRequestOpened, hookPullRequestFromRefUpdated, hookPullRequestMerged, hookPullRequestDeclined, hookPullRequestDeleted}
+
+	branchRefPrefix     = "refs/heads/"
+	tagRefPrefix        = "refs/tags/"
+	pullRequestRefRegex = regexp.MustCompile("refs/pull-requests/(.*)/from")
+	pullRequestRefFmt   = "refs/pull-requests/%s/from"
+)
+
+type Opts struct {
+	APIURL     string
+	SkipVerify bool
+	UserName   string
+	Password   string
+	Token      string
+}
+
+type Client struct {
+	client   *http.Client
+	APIURL   string
+	username string
+	password string
+	token    string
+}
+
+// fromCommitStatus converts a gitsource commit status to a bitbucket server build status state
+func fromCommitStatus(status gitsource.CommitStatus) string {
+	switch status {
+	case gitsource.CommitStatusPending:
+		return "INPROGRESS"
+	case gitsource.CommitStatusSuccess:
+		return "SUCCESSFUL"
+	case gitsource.CommitStatusError:
+		return "FAILED"
+	case gitsource.CommitStatusFailed:
+		return "FAILED"
+	default:
+		panic(errors.Errorf("unknown commit status %q", status))
+	}
+}
+
+// parseRepoPath returns the project key and the repository slug. Personal
+// repositories use the user slug prefixed with a "~" as project key.
+func parseRepoPath(repopath string) (string, string, error) {
+	parts := strings.Split(repopath, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("wrong bitbucket server repo path: %q", repopath)
+	}
+	return parts[0], parts[1], nil
+}
+
+func repoAPIPath(repopath string) (string, error) {
+	projectKey, repoSlug, err := parseRepoPath(repopath)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return fmt.Sprintf("/projects/%s/repos/%s", url.PathEscape(projectKey), url.PathEscape(repoSlug)), nil
+}
+
+func New(opts Opts) (*Client, error) {
+	if opts.APIURL == "" {
+		return nil, errors.Errorf("empty bitbucket server api url")
+	}
+
+	return &Client{
+		client: httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify}),
+		APIURL: strings.TrimSuffix(opts.APIURL, "/"),
+		token:  opts.Token,
+	}, nil
+}
+
+func NewWithBasicAuth(opts Opts) (*Client, error) {
+	if opts.APIURL == "" {
+		return nil, errors.Errorf("empty bitbucket server api url")
+	}
+
+	return &Client{
+		client:   httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify}),
+		APIURL:   strings.TrimSuffix(opts.APIURL, "/"),
+		username: opts.UserName,
+		password: opts.Password,
+	}, nil
+}
+
+func (c *Client) doRequest(method, p string, query url.Values, header http.Header, obj interface{}) (*http.Response, error) {
+	u, err := url.Parse(c.APIURL + p)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+
+	var body io.Reader
+	if obj != nil {
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if obj != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp, errors.WithStack(gitsource.ErrUnauthorized)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return resp, errors.WithStack(err)
+	}
+	errRes := &errorResponse{}
+	if err := json.Unmarshal(data, errRes); err != nil || len(errRes.Errors) == 0 {
+		return resp, gitsource.NewAPIError(resp.StatusCode, errors.Errorf("bitbucket server api error (status: %d)", resp.StatusCode))
+	}
+	messages := make([]string, len(errRes.Errors))
+	for i, e := range errRes.Errors {
+		messages[i] = e.Message
+	}
+
+	return resp, gitsource.NewAPIError(resp.StatusCode, errors.Errorf("bitbucket server api error (status: %d): %s", resp.StatusCode, strings.Join(messages, ", ")))
+}
+
+func (c *Client) getParsedResponse(method, p string, query url.Values, req interface{}, obj interface{}) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+
+	resp, err := c.doRequest(method, p, query, header, req)
+	if err != nil {
+		return resp, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if obj == nil || resp.StatusCode == http.StatusNoContent {
+		return resp, nil
+	}
+
+	d := json.NewDecoder(resp.Body)
+
+	return resp, errors.WithStack(d.Decode(obj))
+}
+
+// getAllPages fetches all the pages of a paged bitbucket server api
+func getAllPages[T any](c *Client, p string, query url.Values) ([]T, error) {
+	values := []T{}
+
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(pageLimit))
+
+	start := 0
+	for {
+		q.Set("start", strconv.Itoa(start))
+
+		res := &pagedResponse[T]{}
+		if _, err := c.getParsedResponse("GET", p, q, nil, res); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		values = append(values, res.Values...)
+
+		if res.IsLastPage || len(res.Values) == 0 {
+			break
+		}
+		start = res.NextPageStart
+	}
+
+	return values, nil
+}
+
+func isNotFound(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
+
+// currentUser returns the authenticated user. Bitbucket server doesn't
+// provide a rest api to get the current user so use the applinks whoami
+// servlet to get the user name and then get the user details
+func (c *Client) currentUser() (*user, error) {
+	resp, err := c.doRequest("GET", whoamiPath, nil, nil, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	name := strings.TrimSpace(string(data))
+	// anonymous access returns an empty user name
+	if name == "" {
+		return nil, errors.WithStack(gitsource.ErrUnauthorized)
+	}
+
+	users, err := getAllPages[*user](c, restAPIPath+"/users", url.Values{"filter": []string{name}})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get user %q", name)
+	}
+	for _, u := range users {
+		if u.Name == name {
+			return u, nil
+		}
+	}
+
+	return nil, errors.Errorf("user %q doesn't exist", name)
+}
+
+func (c *Client) CreateAccessToken(tokenName string) (string, error) {
+	u, err := c.currentUser()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	tokensPath := fmt.Sprintf("%s/users/%s", accessTokensAPIPath, url.PathEscape(u.Slug))
+
+	tokens, err := getAllPages[*accessToken](c, tokensPath, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list access tokens")
+	}
+
+	// remove existing access tokens with the same name
+	for _, token := range tokens {
+		if token.Name == tokenName {
+			if _, err := c.getParsedResponse("DELETE", fmt.Sprintf("%s/%s", tokensPath, url.PathEscape(token.ID)), nil, nil, nil); err != nil {
+				return "", errors.Wrapf(err, "failed to delete access token")
+			}
+		}
+	}
+
+	token := &accessToken{}
+	if _, err := c.getParsedResponse("PUT", tokensPath, nil, &accessToken{Name: tokenName, Permissions: accessTokenPermissions}, token); err != nil {
+		return "", errors.Wrapf(err, "failed to create access token")
+	}
+
+	return token.Token, nil
+}
+
+func (c *Client) GetUserInfo() (*gitsource.UserInfo, error) {
+	u, err := c.currentUser()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &gitsource.UserInfo{
+		ID:        strconv.FormatInt(u.ID, 10),
+		LoginName: u.Name,
+		Email:     u.EmailAddress,
+	}, nil
+}
+
+func (c *Client) GetRepoInfo(repopath string) (*gitsource.RepoInfo, error) {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	repo := &repository{}
+	if _, err := c.getParsedResponse("GET", restAPIPath+rp, nil, nil, repo); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	repoInfo := fromRepository(repo)
+
+	// an empty repository doesn't have a default branch
+	defaultBranch := &ref{}
+	resp, err := c.getParsedResponse("GET", restAPIPath+rp+"/branches/default", nil, nil, defaultBranch)
+	if err != nil && !isNotFound(resp) {
+		return nil, errors.WithStack(err)
+	}
+	repoInfo.DefaultBranch = defaultBranch.DisplayID
+
+	return repoInfo, nil
+}
+
+func (c *Client) GetFile(repopath, commit, file string) ([]byte, error) {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(file, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+
+	resp, err := c.doRequest("GET", restAPIPath+rp+"/raw/"+strings.Join(parts, "/"), url.Values{"at": []string{commit}}, nil, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	return data, errors.WithStack(err)
+}
+
+func (c *Client) listAccessKeys(repopath string) ([]*accessKey, error) {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	keys, err := getAllPages[*accessKey](c, keysAPIPath+rp+"/ssh", nil)
+	return keys, errors.WithStack(err)
+}
+
+func (c *Client) deleteAccessKey(repopath string, id int64) error {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = c.getParsedResponse("DELETE", fmt.Sprintf("%s%s/ssh/%d", keysAPIPath, rp, id), nil, nil, nil)
+	return errors.WithStack(err)
+}
+
+func (c *Client) CreateDeployKey(repopath, title, pubKey string, readonly bool) error {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	permission := "REPO_WRITE"
+	if readonly {
+		permission = "REPO_READ"
+	}
+
+	key := &accessKey{
+		Key: sshKey{
+			Text:  pubKey,
+			Label: title,
+		},
+		Permission: permission,
+	}
+	if _, err := c.getParsedResponse("POST", keysAPIPath+rp+"/ssh", nil, key, nil); err != nil {
+		return errors.Wrapf(err, "error creating deploy key")
+	}
+
+	return nil
+}
+
+func (c *Client) UpdateDeployKey(repopath, title, pubKey string, readonly bool) error {
+	keys, err := c.listAccessKeys(repopath)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving existing deploy keys")
+	}
+
+	// update the key only when the public key value has changed
+	for _, key := range keys {
+		if key.Key.Label == title {
+			if strings.TrimSpace(key.Key.Text) == strings.TrimSpace(pubKey) {
+				return nil
+			}
+			if err := c.deleteAccessKey(repopath, key.Key.ID); err != nil {
+				return errors.Wrapf(err, "error removing existing deploy key")
+			}
+		}
+	}
+
+	return errors.WithStack(c.CreateDeployKey(repopath, title, pubKey, readonly))
+}
+
+func (c *Client) DeleteDeployKey(repopath, title string) error {
+	keys, err := c.listAccessKeys(repopath)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving existing deploy keys")
+	}
+
+	for _, key := range keys {
+		if key.Key.Label == title {
+			if err := c.deleteAccessKey(repopath, key.Key.ID); err != nil {
+				return errors.Wrapf(err, "error removing existing deploy key")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) CreateRepoWebhook(repopath, url, secret string) error {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	hook := &webhook{
+		Name:   webhookName,
+		URL:    url,
+		Events: webhookEvents,
+		Active: true,
+	}
+	if secret != "" {
+		hook.Configuration = map[string]string{"secret": secret}
+	}
+
+	if _, err := c.getParsedResponse("POST", restAPIPath+rp+"/webhooks", nil, hook, nil); err != nil {
+		return errors.Wrapf(err, "error creating repository webhook")
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRepoWebhook(repopath, u string) error {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	hooks, err := getAllPages[*webhook](c, restAPIPath+rp+"/webhooks", nil)
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving repository webhooks")
+	}
+
+	// match the full url so we can have multiple webhooks for different agola
+	// projects
+	for _, hook := range hooks {
+		if hook.URL == u {
+			if _, err := c.getParsedResponse("DELETE", fmt.Sprintf("%s%s/webhooks/%d", restAPIPath, rp, hook.ID), nil, nil, nil); err != nil {
+				return errors.Wrapf(err, "error deleting existing repository webhook")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource.CommitStatus, targetURL, description, context string) (bool, error) {
+	if _, _, err := parseRepoPath(repopath); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	// build statuses are global to the commit and identified by their key
+	resp, err := c.getParsedResponse("POST", fmt.Sprintf("%s/commits/%s", buildStatusAPIPath, url.PathEscape(commitSHA)), nil, &buildStatus{
+		State:       fromCommitStatus(status),
+		Key:         context,
+		Name:        context,
+		URL:         targetURL,
+		Description: description,
+	}, nil)
+
+	var delivered bool
+	if resp != nil {
+		delivered = resp.StatusCode == http.StatusNoContent
+	}
+	return delivered, errors.WithStack(err)
+}
+
+func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
+	remoteRepos, err := getAllPages[*repository](c, restAPIPath+"/repos", url.Values{"permission": []string{"REPO_ADMIN"}})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	repos := make([]*gitsource.RepoInfo, 0, len(remoteRepos))
+	for _, repo := range remoteRepos {
+		repos = append(repos, fromRepository(repo))
+	}
+
+	return repos, nil
+}
+
+func fromRepository(repo *repository) *gitsource.RepoInfo {
+	repoInfo := &gitsource.RepoInfo{
+		ID:   strconv.FormatInt(repo.ID, 10),
+		Path: path.Join(repo.Project.Key, repo.Slug),
+	}
+	if len(repo.Links.Self) > 0 {
+		repoInfo.HTMLURL = strings.TrimSuffix(repo.Links.Self[0].Href, "/browse")
+	}
+	for _, l := range repo.Links.Clone {
+		switch l.Name {
+		case "ssh":
+			repoInfo.SSHCloneURL = l.Href
+		case "http":
+			repoInfo.HTTPCloneURL = l.Href
+		}
+	}
+
+	return repoInfo
+}
+
+// findRef finds the exact ref between the repository branches or tags
+func (c *Client) findRef(repopath, refKind, refName, name string) (*gitsource.Ref, error) {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	refs, err := getAllPages[*ref](c, restAPIPath+rp+"/"+refKind, url.Values{"filterText": []string{name}})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, r := range refs {
+		if r.ID == refName {
+			return &gitsource.Ref{
+				Ref:       r.ID,
+				CommitSHA: r.LatestCommit,
+			}, nil
+		}
+	}
+
+	return nil, errors.Errorf("no ref %q for repository %q", refName, repopath)
+}
+
+func (c *Client) GetRef(repopath, ref string) (*gitsource.Ref, error) {
+	refType, name, err := c.RefType(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch refType {
+	case gitsource.RefTypeBranch:
+		return c.findRef(repopath, "branches", ref, name)
+	case gitsource.RefTypeTag:
+		return c.findRef(repopath, "tags", ref, name)
+	case gitsource.RefTypePullRequest:
+		rp, err := repoAPIPath(repopath)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		pr := &pullRequest{}
+		if _, err := c.getParsedResponse("GET", fmt.Sprintf("%s%s/pull-requests/%s", restAPIPath, rp, url.PathEscape(name)), nil, nil, pr); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return &gitsource.Ref{
+			Ref:       ref,
+			CommitSHA: pr.FromRef.LatestCommit,
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported ref: %s", ref)
+	}
+}
+
+func (c *Client) RefType(ref string) (gitsource.RefType, string, error) {
+	switch {
+	case strings.HasPrefix(ref, branchRefPrefix):
+		return gitsource.RefTypeBranch, strings.TrimPrefix(ref, branchRefPrefix), nil
+
+	case strings.HasPrefix(ref, tagRefPrefix):
+		return gitsource.RefTypeTag, strings.TrimPrefix(ref, tagRefPrefix), nil
+
+	case pullRequestRefRegex.MatchString(ref):
+		m := pullRequestRefRegex.FindStringSubmatch(ref)
+		return gitsource.RefTypePullRequest, m[1], nil
+
+	default:
+		return -1, "", errors.Errorf("unsupported ref: %s", ref)
+	}
+}
+
+func (c *Client) GetCommit(repopath, commitSHA string) (*gitsource.Commit, error) {
+	rp, err := repoAPIPath(repopath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cm := &commit{}
+	if _, err := c.getParsedResponse("GET", fmt.Sprintf("%s%s/commits/%s", restAPIPath, rp, url.PathEscape(commitSHA)), nil, nil, cm); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &gitsource.Commit{
+		SHA:     cm.ID,
+		Message: cm.Message,
+	}, nil
+}
+
+func (c *Client) BranchRef(branch string) string {
+	return branchRefPrefix + branch
+}
+
+func (c *Client) TagRef(tag string) string {
+	return tagRefPrefix + tag
+}
+
+func (c *Client) PullRequestRef(prID string) string {
+	return fmt.Sprintf(pullRequestRefFmt, prID)
+}
+
+func (c *Client) CommitLink(repoInfo *gitsource.RepoInfo, commitSHA string) string {
+	return fmt.Sprintf("%s/commits/%s", repoInfo.HTMLURL, commitSHA)
+}
+
+func (c *Client) BranchLink(repoInfo *gitsource.RepoInfo, branch string) string {
+	return fmt.Sprintf("%s/browse?at=%s", repoInfo.HTMLURL, url.QueryEscape(branchRefPrefix+branch))
+}
+
+func (c *Client) TagLink(repoInfo *gitsource.RepoInfo, tag string) string {
+	return fmt.Sprintf("%s/browse?at=%s", repoInfo.HTMLURL, url.QueryEscape(tagRefPrefix+tag))
+}
+
+func (c *Client) PullRequestLink(repoInfo *gitsource.RepoInfo, prID string) string {
+	return fmt.Sprintf("%s/pull-requests/%s", repoInfo.HTMLURL, prID)
+}
+
+type Oauth2Opts struct {
+	APIURL         string
+	SkipVerify     bool
+	Oauth2ClientID string
+	Oauth2Secret   string
+}
+
+type Oauth2Client struct {
+	httpClient     *http.Client
+	APIURL         string
+	oauth2ClientID string
+	oauth2Secret   string
+}
+
+func NewOauth2Client(opts Oauth2Opts) (*Oauth2Client, error) {
+	httpClient := httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify})
+
+	return &Oauth2Client{
+		httpClient:     httpClient,
+		APIURL:         strings.TrimSuffix(opts.APIURL, "/"),
+		oauth2ClientID: opts.Oauth2ClientID,
+		oauth2Secret:   opts.Oauth2Secret,
+	}, nil
+}
+
+func (c *Oauth2Client) oauth2Config(callbackURL string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     c.oauth2ClientID,
+		ClientSecret: c.oauth2Secret,
+		Scopes:       BitbucketServerOauth2Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  fmt.Sprintf("%s%s/authorize", c.APIURL, oauth2APIPath),
+			TokenURL: fmt.Sprintf("%s%s/token", c.APIURL, oauth2APIPath),
+		},
+		RedirectURL: callbackURL,
+	}
+}
+
+func (c *Oauth2Client) GetOauth2AuthorizationURL(callbackURL, state string) (string, error) {
+	var config = c.oauth2Config(callbackURL)
+	return config.AuthCodeURL(state), nil
+}
+
+func (c *Oauth2Client) RequestOauth2Token(callbackURL, code string) (*oauth2.Token, error) {
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+
+	var config = c.oauth2Config(callbackURL)
+	token, err := config.Exchange(ctx, code)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot get oauth2 token")
+	}
+	return token, nil
+}
+
+func (c *Oauth2Client) RefreshOauth2Token(refreshToken string) (*oauth2.Token, error) {
+	ctx := context.TODO()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+
+	var config = c.oauth2Config("")
+	token := &oauth2.Token{RefreshToken: refreshToken}
+	ts := config.TokenSource(ctx, token)
+	ntoken, err := ts.Token()
+
+	return ntoken, errors.WithStack(err)
+}
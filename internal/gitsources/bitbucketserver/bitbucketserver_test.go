@@ -0,0 +1,748 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucketserver
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/types"
+)
+
+const (
+	testUserName = "User01"
+	testUserSlug = "user01"
+	testPassword = "password"
+	testToken    = "token01"
+	testRepoPath = "/projects/PRJ/repos/repo01"
+	testCommit   = "0123456789abcdef0123456789abcdef01234567"
+)
+
+// fakeServer is a minimal in memory bitbucket server implementation
+type fakeServer struct {
+	t *testing.T
+
+	mu            sync.Mutex
+	nextID        int64
+	accessKeys    []*accessKey
+	webhooks      []*webhook
+	accessTokens  []*accessToken
+	buildStatuses map[string][]*buildStatus
+
+	srv *httptest.Server
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{
+		t:             t,
+		nextID:        1,
+		buildStatuses: map[string][]*buildStatus{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.srv.Close)
+
+	return s
+}
+
+func (s *fakeServer) id() int64 {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *fakeServer) repo() *repository {
+	r := &repository{ID: 10, Slug: "repo01", Name: "Repo01", Project: project{ID: 1, Key: "PRJ"}}
+	r.Links.Self = []link{{Href: s.srv.URL + testRepoPath + "/browse"}}
+	r.Links.Clone = []link{
+		{Href: "ssh://git@localhost:7999/prj/repo01.git", Name: "ssh"},
+		{Href: s.srv.URL + "/scm/prj/repo01.git", Name: "http"},
+	}
+	return r
+}
+
+func (s *fakeServer) authenticated(r *http.Request) bool {
+	if r.Header.Get("Authorization") == "Bearer "+testToken {
+		return true
+	}
+	username, password, ok := r.BasicAuth()
+	return ok && username == testUserName && password == testPassword
+}
+
+func writePage[T any](w http.ResponseWriter, r *http.Request, values []T) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit == 0 {
+		limit = 25
+	}
+	end := min(start+limit, len(values))
+	if start > end {
+		start = end
+	}
+	writeJSON(w, http.StatusOK, &pagedResponse[T]{Values: values[start:end], IsLastPage: end == len(values), NextPageStart: end})
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	res := &errorResponse{}
+	res.Errors = append(res.Errors, struct {
+		Message       string `json:"message"`
+		ExceptionName string `json:"exceptionName"`
+	}{Message: msg})
+	writeJSON(w, code, res)
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := r.URL.Path
+
+	if p == whoamiPath {
+		if s.authenticated(r) {
+			fmt.Fprint(w, testUserName)
+		}
+		return
+	}
+
+	if !s.authenticated(r) {
+		writeError(w, http.StatusUnauthorized, "authentication failed")
+		return
+	}
+
+	decode := func(obj interface{}) {
+		if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+			s.t.Errorf("failed to decode request body: %v", err)
+		}
+	}
+
+	switch {
+	case r.Method == "GET" && p == restAPIPath+"/users":
+		users := []*user{}
+		if strings.Contains(testUserName, r.URL.Query().Get("filter")) {
+			users = append(users, &user{ID: 1, Name: testUserName, Slug: testUserSlug, EmailAddress: "user01@example.com"})
+		}
+		writePage(w, r, users)
+
+	case p == accessTokensAPIPath+"/users/"+testUserSlug:
+		switch r.Method {
+		case "GET":
+			writePage(w, r, s.accessTokens)
+		case "PUT":
+			token := &accessToken{}
+			decode(token)
+			token.ID = strconv.FormatInt(s.id(), 10)
+			token.Token = "secret" + token.ID
+			s.accessTokens = append(s.accessTokens, token)
+			writeJSON(w, http.StatusOK, token)
+		}
+
+	case r.Method == "DELETE" && strings.HasPrefix(p, accessTokensAPIPath+"/users/"+testUserSlug+"/"):
+		id := strings.TrimPrefix(p, accessTokensAPIPath+"/users/"+testUserSlug+"/")
+		tokens := []*accessToken{}
+		for _, token := range s.accessTokens {
+			if token.ID != id {
+				tokens = append(tokens, token)
+			}
+		}
+		s.accessTokens = tokens
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "GET" && p == restAPIPath+"/repos":
+		repos := []*repository{}
+		if r.URL.Query().Get("permission") == "REPO_ADMIN" {
+			for i := 0; i < 150; i++ {
+				repo := s.repo()
+				repo.ID = int64(i)
+				repo.Slug = fmt.Sprintf("repo%03d", i)
+				repos = append(repos, repo)
+			}
+		}
+		writePage(w, r, repos)
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath:
+		writeJSON(w, http.StatusOK, s.repo())
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath+"/branches/default":
+		writeJSON(w, http.StatusOK, &ref{ID: "refs/heads/master", DisplayID: "master", Type: "BRANCH", LatestCommit: testCommit})
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath+"/branches":
+		refs := []*ref{}
+		for _, name := range []string{"master", "master2"} {
+			if strings.Contains(name, r.URL.Query().Get("filterText")) {
+				refs = append(refs, &ref{ID: "refs/heads/" + name, DisplayID: name, Type: "BRANCH", LatestCommit: testCommit})
+			}
+		}
+		writePage(w, r, refs)
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath+"/tags":
+		refs := []*ref{}
+		if strings.Contains("v1.0", r.URL.Query().Get("filterText")) {
+			refs = append(refs, &ref{ID: "refs/tags/v1.0", DisplayID: "v1.0", Type: "TAG", LatestCommit: testCommit})
+		}
+		writePage(w, r, refs)
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath+"/pull-requests/1":
+		pr := &pullRequest{ID: 1, Title: "PR01", State: "OPEN", Open: true}
+		pr.FromRef = ref{ID: "refs/heads/feature", LatestCommit: testCommit}
+		writeJSON(w, http.StatusOK, pr)
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath+"/commits/"+testCommit:
+		writeJSON(w, http.StatusOK, &commit{ID: testCommit, Message: "commit message"})
+
+	case r.Method == "GET" && p == restAPIPath+testRepoPath+"/raw/dir/.agola/config.yml":
+		if r.URL.Query().Get("at") != testCommit {
+			writeError(w, http.StatusNotFound, "file not found")
+			return
+		}
+		fmt.Fprint(w, "version: v0\n")
+
+	case p == keysAPIPath+testRepoPath+"/ssh":
+		switch r.Method {
+		case "GET":
+			writePage(w, r, s.accessKeys)
+		case "POST":
+			key := &accessKey{}
+			decode(key)
+			key.Key.ID = s.id()
+			s.accessKeys = append(s.accessKeys, key)
+			writeJSON(w, http.StatusCreated, key)
+		}
+
+	case r.Method == "DELETE" && strings.HasPrefix(p, keysAPIPath+testRepoPath+"/ssh/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(p, keysAPIPath+testRepoPath+"/ssh/"), 10, 64)
+		keys := []*accessKey{}
+		for _, key := range s.accessKeys {
+			if key.Key.ID != id {
+				keys = append(keys, key)
+			}
+		}
+		s.accessKeys = keys
+		w.WriteHeader(http.StatusNoContent)
+
+	case p == restAPIPath+testRepoPath+"/webhooks":
+		switch r.Method {
+		case "GET":
+			writePage(w, r, s.webhooks)
+		case "POST":
+			hook := &webhook{}
+			decode(hook)
+			hook.ID = s.id()
+			s.webhooks = append(s.webhooks, hook)
+			writeJSON(w, http.StatusCreated, hook)
+		}
+
+	case r.Method == "DELETE" && strings.HasPrefix(p, restAPIPath+testRepoPath+"/webhooks/"):
+		id, _ := strconv.ParseInt(strings.TrimPrefix(p, restAPIPath+testRepoPath+"/webhooks/"), 10, 64)
+		hooks := []*webhook{}
+		for _, hook := range s.webhooks {
+			if hook.ID != id {
+				hooks = append(hooks, hook)
+			}
+		}
+		s.webhooks = hooks
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "POST" && strings.HasPrefix(p, buildStatusAPIPath+"/commits/"):
+		sha := strings.TrimPrefix(p, buildStatusAPIPath+"/commits/")
+		status := &buildStatus{}
+		decode(status)
+		if status.URL == "" {
+			writeError(w, http.StatusBadRequest, "url is required")
+			return
+		}
+		s.buildStatuses[sha] = append(s.buildStatuses[sha], status)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s not found", r.Method, p))
+	}
+}
+
+func newTestClient(t *testing.T, s *fakeServer) *Client {
+	c, err := New(Opts{APIURL: s.srv.URL, Token: testToken})
+	assert.NilError(t, err)
+	return c
+}
+
+func TestGetUserInfo(t *testing.T) {
+	s := newFakeServer(t)
+
+	c := newTestClient(t, s)
+	userInfo, err := c.GetUserInfo()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, userInfo, &gitsource.UserInfo{ID: "1", LoginName: testUserName, Email: "user01@example.com"})
+
+	c, err = NewWithBasicAuth(Opts{APIURL: s.srv.URL, UserName: testUserName, Password: testPassword})
+	assert.NilError(t, err)
+	_, err = c.GetUserInfo()
+	assert.NilError(t, err)
+
+	c, err = NewWithBasicAuth(Opts{APIURL: s.srv.URL, UserName: testUserName, Password: "wrong"})
+	assert.NilError(t, err)
+	_, err = c.GetUserInfo()
+	assert.ErrorIs(t, err, gitsource.ErrUnauthorized)
+}
+
+func TestCreateAccessToken(t *testing.T) {
+	s := newFakeServer(t)
+
+	c, err := NewWithBasicAuth(Opts{APIURL: s.srv.URL, UserName: testUserName, Password: testPassword})
+	assert.NilError(t, err)
+
+	token, err := c.CreateAccessToken("agola")
+	assert.NilError(t, err)
+	assert.Equal(t, token, "secret1")
+
+	// creating it again should replace the existing token
+	token, err = c.CreateAccessToken("agola")
+	assert.NilError(t, err)
+	assert.Equal(t, token, "secret2")
+
+	assert.Equal(t, len(s.accessTokens), 1)
+	assert.DeepEqual(t, s.accessTokens[0].Permissions, accessTokenPermissions)
+
+	c, err = NewWithBasicAuth(Opts{APIURL: s.srv.URL, UserName: testUserName, Password: "wrong"})
+	assert.NilError(t, err)
+	_, err = c.CreateAccessToken("agola")
+	assert.ErrorIs(t, err, gitsource.ErrUnauthorized)
+}
+
+func TestRepoInfo(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	repoInfo, err := c.GetRepoInfo("PRJ/repo01")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, repoInfo, &gitsource.RepoInfo{
+		ID:            "10",
+		Path:          "PRJ/repo01",
+		HTMLURL:       s.srv.URL + testRepoPath,
+		SSHCloneURL:   "ssh://git@localhost:7999/prj/repo01.git",
+		HTTPCloneURL:  s.srv.URL + "/scm/prj/repo01.git",
+		DefaultBranch: "master",
+	})
+
+	_, err = c.GetRepoInfo("PRJ/repo02")
+	assert.ErrorContains(t, err, "status: 404")
+
+	_, err = c.GetRepoInfo("repo01")
+	assert.ErrorContains(t, err, "wrong bitbucket server repo path")
+
+	repos, err := c.ListUserRepos()
+	assert.NilError(t, err)
+	assert.Equal(t, len(repos), 150)
+	assert.Equal(t, repos[149].Path, "PRJ/repo149")
+
+	data, err := c.GetFile("PRJ/repo01", testCommit, "dir/.agola/config.yml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "version: v0\n")
+
+	_, err = c.GetFile("PRJ/repo01", "master", "dir/.agola/config.yml")
+	assert.ErrorContains(t, err, "file not found")
+
+	cm, err := c.GetCommit("PRJ/repo01", testCommit)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cm, &gitsource.Commit{SHA: testCommit, Message: "commit message"})
+}
+
+func TestRefs(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	tests := []struct {
+		ref     string
+		refType gitsource.RefType
+		name    string
+		err     string
+	}{
+		{ref: c.BranchRef("master"), refType: gitsource.RefTypeBranch, name: "master"},
+		{ref: c.TagRef("v1.0"), refType: gitsource.RefTypeTag, name: "v1.0"},
+		{ref: c.PullRequestRef("1"), refType: gitsource.RefTypePullRequest, name: "1"},
+		{ref: c.BranchRef("mast"), refType: gitsource.RefTypeBranch, name: "mast", err: `no ref "refs/heads/mast"`},
+		{ref: "refs/notes/commits", err: "unsupported ref"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			refType, name, err := c.RefType(tt.ref)
+			if tt.err == "unsupported ref" {
+				assert.ErrorContains(t, err, tt.err)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, refType, tt.refType)
+				assert.Equal(t, name, tt.name)
+			}
+
+			ref, err := c.GetRef("PRJ/repo01", tt.ref)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, ref, &gitsource.Ref{Ref: tt.ref, CommitSHA: testCommit})
+		})
+	}
+
+	repoInfo := &gitsource.RepoInfo{HTMLURL: "https://bitbucket.example.com/projects/PRJ/repos/repo01"}
+	assert.Equal(t, c.CommitLink(repoInfo, testCommit), "https://bitbucket.example.com/projects/PRJ/repos/repo01/commits/"+testCommit)
+	assert.Equal(t, c.BranchLink(repoInfo, "feature/a"), "https://bitbucket.example.com/projects/PRJ/repos/repo01/browse?at=refs%2Fheads%2Ffeature%2Fa")
+	assert.Equal(t, c.TagLink(repoInfo, "v1.0"), "https://bitbucket.example.com/projects/PRJ/repos/repo01/browse?at=refs%2Ftags%2Fv1.0")
+	assert.Equal(t, c.PullRequestLink(repoInfo, "1"), "https://bitbucket.example.com/projects/PRJ/repos/repo01/pull-requests/1")
+}
+
+func TestDeployKeys(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	assert.NilError(t, c.CreateDeployKey("PRJ/repo01", "agola", "ssh-rsa key01", true))
+	assert.NilError(t, c.CreateDeployKey("PRJ/repo01", "other", "ssh-rsa key02", false))
+	assert.DeepEqual(t, s.accessKeys, []*accessKey{
+		{Key: sshKey{ID: 1, Text: "ssh-rsa key01", Label: "agola"}, Permission: "REPO_READ"},
+		{Key: sshKey{ID: 2, Text: "ssh-rsa key02", Label: "other"}, Permission: "REPO_WRITE"},
+	})
+
+	// same key, nothing should change
+	assert.NilError(t, c.UpdateDeployKey("PRJ/repo01", "agola", "ssh-rsa key01", true))
+	assert.Equal(t, s.accessKeys[0].Key.ID, int64(1))
+
+	assert.NilError(t, c.UpdateDeployKey("PRJ/repo01", "agola", "ssh-rsa key03", true))
+	assert.DeepEqual(t, s.accessKeys, []*accessKey{
+		{Key: sshKey{ID: 2, Text: "ssh-rsa key02", Label: "other"}, Permission: "REPO_WRITE"},
+		{Key: sshKey{ID: 3, Text: "ssh-rsa key03", Label: "agola"}, Permission: "REPO_READ"},
+	})
+
+	assert.NilError(t, c.DeleteDeployKey("PRJ/repo01", "agola"))
+	assert.DeepEqual(t, s.accessKeys, []*accessKey{
+		{Key: sshKey{ID: 2, Text: "ssh-rsa key02", Label: "other"}, Permission: "REPO_WRITE"},
+	})
+}
+
+func TestRepoWebhooks(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	assert.NilError(t, c.CreateRepoWebhook("PRJ/repo01", "http://agola/webhooks?projectid=01", "secret01"))
+	assert.NilError(t, c.CreateRepoWebhook("PRJ/repo01", "http://agola/webhooks?projectid=02", "secret02"))
+	assert.Equal(t, len(s.webhooks), 2)
+	assert.DeepEqual(t, s.webhooks[0], &webhook{
+		ID:            1,
+		Name:          webhookName,
+		URL:           "http://agola/webhooks?projectid=01",
+		Events:        []string{"repo:refs_changed", "pr:opened", "pr:from_ref_updated", "pr:merged", "pr:declined", "pr:deleted"},
+		Active:        true,
+		Configuration: map[string]string{"secret": "secret01"},
+	})
+
+	assert.NilError(t, c.DeleteRepoWebhook("PRJ/repo01", "http://agola/webhooks?projectid=01"))
+	assert.Equal(t, len(s.webhooks), 1)
+	assert.Equal(t, s.webhooks[0].URL, "http://agola/webhooks?projectid=02")
+}
+
+func TestCreateCommitStatus(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	delivered, err := c.CreateCommitStatus("PRJ/repo01", testCommit, gitsource.CommitStatusPending, "http://agola/run", "The run is queued", "agola/project01/Run")
+	assert.NilError(t, err)
+	assert.Assert(t, delivered)
+
+	delivered, err = c.CreateCommitStatus("PRJ/repo01", testCommit, gitsource.CommitStatusFailed, "http://agola/run", "The run failed", "agola/project01/Run")
+	assert.NilError(t, err)
+	assert.Assert(t, delivered)
+
+	assert.DeepEqual(t, s.buildStatuses[testCommit], []*buildStatus{
+		{State: "INPROGRESS", Key: "agola/project01/Run", Name: "agola/project01/Run", URL: "http://agola/run", Description: "The run is queued"},
+		{State: "FAILED", Key: "agola/project01/Run", Name: "agola/project01/Run", URL: "http://agola/run", Description: "The run failed"},
+	})
+
+	delivered, err = c.CreateCommitStatus("PRJ/repo01", testCommit, gitsource.CommitStatusSuccess, "", "", "agola/project01/Run")
+	assert.ErrorContains(t, err, "url is required")
+	assert.Assert(t, !delivered)
+}
+
+const refsChangedPayload = `{
+  "eventKey": "repo:refs_changed",
+  "actor": {"id": 1, "name": "User01", "slug": "user01"},
+  "repository": {"id": 10, "slug": "repo01", "project": {"key": "PRJ"}},
+  "changes": [
+    {"ref": {"id": "refs/heads/old", "displayId": "old", "type": "BRANCH"}, "refId": "refs/heads/old", "fromHash": "%[1]s", "toHash": "0000000000000000000000000000000000000000", "type": "DELETE"},
+    {"ref": {"id": "%[2]s", "type": "BRANCH"}, "refId": "%[2]s", "fromHash": "0000000000000000000000000000000000000000", "toHash": "%[1]s", "type": "ADD"}
+  ]
+}`
+
+const pullRequestPayload = `{
+  "eventKey": "pr:opened",
+  "actor": {"id": 1, "name": "User01", "slug": "user01"},
+  "pullRequest": {
+    "id": 1,
+    "title": "PR01",
+    "state": "%s",
+    "author": {"user": {"id": 2, "name": "User02", "slug": "user02"}},
+    "fromRef": {"id": "refs/heads/feature", "latestCommit": "%s", "repository": {"id": %d, "slug": "repo01", "project": {"key": "~USER02"}}},
+    "toRef": {"id": "refs/heads/master", "latestCommit": "1111111111111111111111111111111111111111", "repository": {"id": 10, "slug": "repo01", "project": {"key": "PRJ"}}}
+  }
+}`
+
+func webhookRequest(event, secret, payload string) *http.Request {
+	r := httptest.NewRequest("POST", "/webhooks", bytes.NewBufferString(payload))
+	r.Header.Set(hookEvent, event)
+	if secret != "" {
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write([]byte(payload))
+		r.Header.Set(signatureHeader, signaturePrefix+hex.EncodeToString(h.Sum(nil)))
+	}
+	return r
+}
+
+func TestParseWebhook(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	htmlURL := s.srv.URL + testRepoPath
+	sshURL := "ssh://git@localhost:7999/prj/repo01.git"
+
+	tests := []struct {
+		name          string
+		event         string
+		payload       string
+		signSecret    string
+		webhookSecret string
+		out           *types.WebhookData
+		err           string
+	}{
+		{
+			name:          "branch push",
+			event:         hookRefsChanged,
+			payload:       fmt.Sprintf(refsChangedPayload, testCommit, "refs/heads/master"),
+			signSecret:    "secret",
+			webhookSecret: "secret",
+			out: &types.WebhookData{
+				Event:      types.WebhookEventPush,
+				SSHURL:     sshURL,
+				CommitLink: htmlURL + "/commits/" + testCommit,
+				CommitSHA:  testCommit,
+				Ref:        "refs/heads/master",
+				Message:    "commit message",
+				Sender:     testUserName,
+				Branch:     "master",
+				BranchLink: htmlURL + "/browse?at=refs%2Fheads%2Fmaster",
+				Repo:       types.WebhookDataRepo{WebURL: htmlURL, Path: "PRJ/repo01"},
+			},
+		},
+		{
+			name:    "tag push",
+			event:   hookRefsChanged,
+			payload: fmt.Sprintf(refsChangedPayload, testCommit, "refs/tags/v1.0"),
+			out: &types.WebhookData{
+				Event:      types.WebhookEventTag,
+				SSHURL:     sshURL,
+				CommitLink: htmlURL + "/commits/" + testCommit,
+				CommitSHA:  testCommit,
+				Ref:        "refs/tags/v1.0",
+				Message:    "Tag v1.0",
+				Sender:     testUserName,
+				Tag:        "v1.0",
+				TagLink:    htmlURL + "/browse?at=refs%2Ftags%2Fv1.0",
+				Repo:       types.WebhookDataRepo{WebURL: htmlURL, Path: "PRJ/repo01"},
+			},
+		},
+		{
+			name:    "pull request opened from fork",
+			event:   hookPullRequestOpened,
+			payload: fmt.Sprintf(pullRequestPayload, "OPEN", testCommit, 20),
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          sshURL,
+				CommitLink:      htmlURL + "/commits/" + testCommit,
+				CommitSHA:       testCommit,
+				Ref:             "refs/pull-requests/1/from",
+				Message:         "PR01",
+				Sender:          testUserName,
+				Author:          "User02",
+				PullRequestID:   "1",
+				PullRequestLink: htmlURL + "/pull-requests/1",
+
+				PullRequestAction:       types.WebhookPullRequestActionOpened,
+				PullRequestTargetBranch: "master",
+
+				Repo: types.WebhookDataRepo{WebURL: htmlURL, Path: "PRJ/repo01"},
+			},
+		},
+		{
+			name:    "pull request updated from same repo",
+			event:   hookPullRequestFromRefUpdated,
+			payload: fmt.Sprintf(pullRequestPayload, "OPEN", testCommit, 10),
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          sshURL,
+				CommitLink:      htmlURL + "/commits/" + testCommit,
+				CommitSHA:       testCommit,
+				Ref:             "refs/pull-requests/1/from",
+				Message:         "PR01",
+				Sender:          testUserName,
+				Author:          "User02",
+				PullRequestID:   "1",
+				PullRequestLink: htmlURL + "/pull-requests/1",
+				PRFromSameRepo:  true,
+
+				PullRequestAction:       types.WebhookPullRequestActionSynchronized,
+				PullRequestTargetBranch: "master",
+
+				Repo: types.WebhookDataRepo{WebURL: htmlURL, Path: "PRJ/repo01"},
+			},
+		},
+		{
+			name:    "declined pull request",
+			event:   hookPullRequestFromRefUpdated,
+			payload: fmt.Sprintf(pullRequestPayload, "DECLINED", testCommit, 10),
+		},
+		{
+			name:    "pull request declined",
+			event:   hookPullRequestDeclined,
+			payload: fmt.Sprintf(pullRequestPayload, "DECLINED", testCommit, 10),
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          sshURL,
+				CommitLink:      htmlURL + "/commits/" + testCommit,
+				CommitSHA:       testCommit,
+				Ref:             "refs/pull-requests/1/from",
+				Message:         "PR01",
+				Sender:          testUserName,
+				Author:          "User02",
+				PullRequestID:   "1",
+				PullRequestLink: htmlURL + "/pull-requests/1",
+				PRFromSameRepo:  true,
+
+				PullRequestAction:       types.WebhookPullRequestActionClosed,
+				PullRequestTargetBranch: "master",
+
+				Repo: types.WebhookDataRepo{WebURL: htmlURL, Path: "PRJ/repo01"},
+			},
+		},
+		{
+			name:    "branch deletion",
+			event:   hookRefsChanged,
+			payload: `{"eventKey": "repo:refs_changed", "repository": {"slug": "repo01", "project": {"key": "PRJ"}}, "changes": [{"refId": "refs/heads/old", "type": "DELETE"}]}`,
+		},
+		{
+			name:          "ping",
+			event:         hookPing,
+			payload:       `{"test": true}`,
+			signSecret:    "secret",
+			webhookSecret: "secret",
+		},
+		{
+			name:          "wrong signature",
+			event:         hookRefsChanged,
+			payload:       fmt.Sprintf(refsChangedPayload, testCommit, "refs/heads/master"),
+			signSecret:    "wrong",
+			webhookSecret: "secret",
+			err:           "wrong webhook signature",
+		},
+		{
+			name:          "missing signature",
+			event:         hookRefsChanged,
+			payload:       fmt.Sprintf(refsChangedPayload, testCommit, "refs/heads/master"),
+			webhookSecret: "secret",
+			err:           "wrong webhook signature",
+		},
+		{
+			name:    "unknown event",
+			event:   "repo:modified",
+			payload: `{}`,
+			err:     `unknown webhook event type: "repo:modified"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			whd, err := c.ParseWebhook(webhookRequest(tt.event, tt.signSecret, tt.payload), tt.webhookSecret)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			if diff := gocmp.Diff(tt.out, whd); diff != "" {
+				t.Fatalf("webhook data mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOauth2Client(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != oauth2APIPath+"/token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var accessToken string
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "code01" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			accessToken = "access01"
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh01" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			accessToken = "access02"
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"access_token": accessToken, "refresh_token": "refresh01", "token_type": "bearer", "expires_in": 3600})
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewOauth2Client(Oauth2Opts{APIURL: srv.URL + "/", Oauth2ClientID: "client01", Oauth2Secret: "secret01"})
+	assert.NilError(t, err)
+
+	u, err := c.GetOauth2AuthorizationURL("http://agola/callback", "state01")
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(u, srv.URL+oauth2APIPath+"/authorize?"), u)
+	assert.Assert(t, strings.Contains(u, "scope=REPO_ADMIN"), u)
+	assert.Assert(t, strings.Contains(u, "state=state01"), u)
+
+	token, err := c.RequestOauth2Token("http://agola/callback", "code01")
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "access01")
+
+	token, err = c.RefreshOauth2Token(token.RefreshToken)
+	assert.NilError(t, err)
+	assert.Equal(t, token.AccessToken, "access02")
+}
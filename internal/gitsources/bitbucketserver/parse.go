@@ -0,0 +1,245 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucketserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/types"
+)
+
+const (
+	hookEvent       = "X-Event-Key"
+	signatureHeader = "X-Hub-Signature"
+	signaturePrefix = "sha256="
+
+	hookPing                      = "diagnostics:ping"
+	hookRefsChanged               = "repo:refs_changed"
+	hookPullRequestOpened         = "pr:opened"
+	hookPullRequestFromRefUpdated = "pr:from_ref_updated"
+	hookPullRequestMerged         = "pr:merged"
+	hookPullRequestDeclined       = "pr:declined"
+	hookPullRequestDeleted        = "pr:deleted"
+
+	refChangeTypeDelete = "DELETE"
+
+	prStateOpen = "OPEN"
+)
+
+// VerifyWebhook verifies the webhook signature using the webhook secret.
+func VerifyWebhook(header http.Header, body []byte, secret string) error {
+	if secret == "" {
+		return nil
+	}
+
+	signature := header.Get(signatureHeader)
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return errors.Errorf("wrong webhook signature")
+	}
+	ds, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return errors.Errorf("wrong webhook signature")
+	}
+	h := hmac.New(sha256.New, []byte(secret))
+	if _, err := h.Write(body); err != nil {
+		return errors.Errorf("failed to calculate webhook signature")
+	}
+	cs := h.Sum(nil)
+	if !hmac.Equal(cs, ds) {
+		return errors.Errorf("wrong webhook signature")
+	}
+
+	return nil
+}
+
+func (c *Client) ParseWebhook(r *http.Request, secret string) (*types.WebhookData, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, 10*1024*1024))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := VerifyWebhook(r.Header, data, secret); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var whd *types.WebhookData
+	switch r.Header.Get(hookEvent) {
+	case hookPing:
+		return nil, nil
+	case hookRefsChanged:
+		whd, err = parseRefsChangedHook(data)
+	case hookPullRequestOpened:
+		whd, err = parsePullRequestHook(data, types.WebhookPullRequestActionOpened)
+	case hookPullRequestFromRefUpdated:
+		whd, err = parsePullRequestHook(data, types.WebhookPullRequestActionSynchronized)
+	case hookPullRequestMerged:
+		whd, err = parsePullRequestHook(data, types.WebhookPullRequestActionMerged)
+	case hookPullRequestDeclined, hookPullRequestDeleted:
+		whd, err = parsePullRequestHook(data, types.WebhookPullRequestActionClosed)
+	default:
+		return nil, errors.Errorf("unknown webhook event type: %q", r.Header.Get(hookEvent))
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if whd == nil {
+		return nil, nil
+	}
+
+	if err := c.completeWebhookData(whd); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return whd, nil
+}
+
+// completeWebhookData populates the webhook data fields not provided by the
+// bitbucket server webhook payloads (repository urls, links and the commit
+// message)
+func (c *Client) completeWebhookData(whd *types.WebhookData) error {
+	repoInfo, err := c.GetRepoInfo(whd.Repo.Path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get repository %q", whd.Repo.Path)
+	}
+
+	whd.SSHURL = repoInfo.SSHCloneURL
+	whd.Repo.WebURL = repoInfo.HTMLURL
+	whd.CommitLink = c.CommitLink(repoInfo, whd.CommitSHA)
+
+	switch whd.Event {
+	case types.WebhookEventPush:
+		whd.BranchLink = c.BranchLink(repoInfo, whd.Branch)
+
+		commit, err := c.GetCommit(whd.Repo.Path, whd.CommitSHA)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get commit %q", whd.CommitSHA)
+		}
+		whd.Message = commit.Message
+	case types.WebhookEventTag:
+		whd.TagLink = c.TagLink(repoInfo, whd.Tag)
+	case types.WebhookEventPullRequest:
+		whd.PullRequestLink = c.PullRequestLink(repoInfo, whd.PullRequestID)
+	}
+
+	return nil
+}
+
+func parseRefsChangedHook(data []byte) (*types.WebhookData, error) {
+	hook := new(refsChangedHook)
+	err := json.Unmarshal(data, hook)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return webhookDataFromRefsChanged(hook)
+}
+
+func parsePullRequestHook(data []byte, action types.WebhookPullRequestAction) (*types.WebhookData, error) {
+	prhook := new(pullRequestHook)
+	err := json.Unmarshal(data, prhook)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// skip non open pull requests
+	if !action.IsClosed() && prhook.PullRequest.State != prStateOpen {
+		return nil, nil
+	}
+
+	return webhookDataFromPullRequest(prhook, action), nil
+}
+
+func webhookDataFromRefsChanged(hook *refsChangedHook) (*types.WebhookData, error) {
+	// a push can change multiple refs, use the first created or updated one.
+	// skip ref deletions
+	var change *refChange
+	for _, c := range hook.Changes {
+		if c.Type != refChangeTypeDelete {
+			change = &c
+			break
+		}
+	}
+	if change == nil {
+		return nil, nil
+	}
+
+	// common data
+	whd := &types.WebhookData{
+		CommitSHA: change.ToHash,
+		Ref:       change.RefID,
+		Sender:    hook.Actor.Name,
+
+		Repo: types.WebhookDataRepo{
+			Path: path.Join(hook.Repository.Project.Key, hook.Repository.Slug),
+		},
+	}
+
+	switch {
+	case strings.HasPrefix(change.RefID, branchRefPrefix):
+		whd.Event = types.WebhookEventPush
+		whd.Branch = strings.TrimPrefix(change.RefID, branchRefPrefix)
+	case strings.HasPrefix(change.RefID, tagRefPrefix):
+		whd.Event = types.WebhookEventTag
+		whd.Tag = strings.TrimPrefix(change.RefID, tagRefPrefix)
+		whd.Message = fmt.Sprintf("Tag %s", whd.Tag)
+	default:
+		// ignore received webhook since it doesn't have a ref we're interested in
+		return nil, errors.Errorf("unsupported webhook ref %q", change.RefID)
+	}
+
+	return whd, nil
+}
+
+// helper function that extracts the Build data from a bitbucket server pull request hook
+func webhookDataFromPullRequest(hook *pullRequestHook, action types.WebhookPullRequestAction) *types.WebhookData {
+	pr := hook.PullRequest
+	prID := strconv.FormatInt(pr.ID, 10)
+
+	var prFromSameRepo bool
+	if pr.FromRef.Repository != nil && pr.ToRef.Repository != nil {
+		prFromSameRepo = pr.FromRef.Repository.ID == pr.ToRef.Repository.ID
+	}
+
+	whd := &types.WebhookData{
+		Event:          types.WebhookEventPullRequest,
+		CommitSHA:      pr.FromRef.LatestCommit,
+		Ref:            fmt.Sprintf(pullRequestRefFmt, prID),
+		Message:        pr.Title,
+		Sender:         hook.Actor.Name,
+		Author:         pr.Author.User.Name,
+		PullRequestID:  prID,
+		PRFromSameRepo: prFromSameRepo,
+
+		PullRequestAction:       action,
+		PullRequestTargetBranch: strings.TrimPrefix(pr.ToRef.ID, branchRefPrefix),
+		PullRequestDraft:        pr.Draft,
+	}
+	if pr.ToRef.Repository != nil {
+		whd.Repo.Path = path.Join(pr.ToRef.Repository.Project.Key, pr.ToRef.Repository.Slug)
+	}
+
+	return whd
+}
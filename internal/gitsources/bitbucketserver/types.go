@@ -0,0 +1,144 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucketserver
+
+type errorResponse struct {
+	Errors []struct {
+		Message       string `json:"message"`
+		ExceptionName string `json:"exceptionName"`
+	} `json:"errors"`
+}
+
+type pagedResponse[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+type link struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+type user struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	EmailAddress string `json:"emailAddress"`
+	DisplayName  string `json:"displayName"`
+}
+
+type project struct {
+	ID   int64  `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type repository struct {
+	ID      int64   `json:"id"`
+	Slug    string  `json:"slug"`
+	Name    string  `json:"name"`
+	Project project `json:"project"`
+	Links   struct {
+		Clone []link `json:"clone"`
+		Self  []link `json:"self"`
+	} `json:"links"`
+}
+
+type ref struct {
+	ID           string      `json:"id"`
+	DisplayID    string      `json:"displayId"`
+	Type         string      `json:"type"`
+	LatestCommit string      `json:"latestCommit"`
+	Repository   *repository `json:"repository,omitempty"`
+}
+
+type commit struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId"`
+	Message   string `json:"message"`
+}
+
+type pullRequest struct {
+	ID      int64  `json:"id"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	Open    bool   `json:"open"`
+	Draft   bool   `json:"draft"`
+	FromRef ref    `json:"fromRef"`
+	ToRef   ref    `json:"toRef"`
+	Author  struct {
+		User user `json:"user"`
+	} `json:"author"`
+	Links struct {
+		Self []link `json:"self"`
+	} `json:"links"`
+}
+
+type sshKey struct {
+	ID    int64  `json:"id,omitempty"`
+	Text  string `json:"text"`
+	Label string `json:"label"`
+}
+
+type accessKey struct {
+	Key        sshKey `json:"key"`
+	Permission string `json:"permission"`
+}
+
+type webhook struct {
+	ID            int64             `json:"id,omitempty"`
+	Name          string            `json:"name"`
+	URL           string            `json:"url"`
+	Events        []string          `json:"events"`
+	Active        bool              `json:"active"`
+	Configuration map[string]string `json:"configuration,omitempty"`
+}
+
+type buildStatus struct {
+	State       string `json:"state"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type accessToken struct {
+	ID          string   `json:"id,omitempty"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions,omitempty"`
+	Token       string   `json:"token,omitempty"`
+}
+
+type refChange struct {
+	Ref      ref    `json:"ref"`
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	Type     string `json:"type"`
+}
+
+type refsChangedHook struct {
+	EventKey   string      `json:"eventKey"`
+	Actor      user        `json:"actor"`
+	Repository repository  `json:"repository"`
+	Changes    []refChange `json:"changes"`
+}
+
+type pullRequestHook struct {
+	EventKey    string      `json:"eventKey"`
+	Actor       user        `json:"actor"`
+	PullRequest pullRequest `json:"pullRequest"`
+}
@@ -0,0 +1,685 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gerrit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/httpclient"
+)
+
+const (
+	// authenticated rest api calls must be prefixed with /a
+	authPathPrefix = "/a"
+
+	// jsonMagicPrefix is the prefix gerrit adds to all the json responses to
+	// prevent XSSI
+	jsonMagicPrefix = ")]}'"
+
+	defaultSSHPort = 29418
+
+	verifiedLabel = "Verified"
+	// reviewTag marks the agola reviews as autogenerated so they can be
+	// filtered in the gerrit ui
+	reviewTag = "autogenerated:agola"
+
+	webhookRemotePrefix = "agola-"
+)
+
+var (
+	webhookEvents = []string{eventPatchSetCreated, eventRefUpdated, eventChangeAbandoned, eventChangeMerged, eventChangeRestored}
+
+	// internal gerrit projects that don't contain code
+	internalProjects = map[string]struct{}{"All-Projects": {}, "All-Users": {}}
+
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+	changeRefRegex  = regexp.MustCompile(`^refs/changes/\d{2}/(\d+)/(\d+)$`)
+)
+
+type Opts struct {
+	APIURL     string
+	SkipVerify bool
+	UserName   string
+	// Password is the user gerrit http password
+	Password string
+}
+
+type Client struct {
+	client   *http.Client
+	APIURL   string
+	username string
+	password string
+}
+
+// parsePullRequestID returns the change number and the patch set number of a
+// pull request id. Every gerrit change patch set is handled as a different
+// pull request with id "change/patchset".
+func parsePullRequestID(prID string) (string, string, error) {
+	parts := strings.Split(prID, "/")
+	if len(parts) != 2 {
+		return "", "", errors.Errorf("wrong gerrit change id %q", prID)
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return "", "", errors.Errorf("wrong gerrit change id %q", prID)
+		}
+	}
+	return parts[0], parts[1], nil
+}
+
+func pullRequestID(change int64, patchSet int) string {
+	return fmt.Sprintf("%d/%d", change, patchSet)
+}
+
+// changeRef returns the ref of a change patch set. The change refs are
+// sharded by the last two digits of the change number.
+func changeRef(change, patchSet string) string {
+	shard := change
+	if len(shard) < 2 {
+		shard = "0" + shard
+	}
+	return fmt.Sprintf("refs/changes/%s/%s/%s", shard[len(shard)-2:], change, patchSet)
+}
+
+func projectAPIPath(repopath string) string {
+	return "/projects/" + url.PathEscape(repopath)
+}
+
+func changeAPIPath(repopath, change string) string {
+	return "/changes/" + url.PathEscape(repopath+"~"+change)
+}
+
+func New(opts Opts) (*Client, error) {
+	if opts.APIURL == "" {
+		return nil, errors.Errorf("empty gerrit api url")
+	}
+
+	return &Client{
+		client:   httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify}),
+		APIURL:   strings.TrimSuffix(opts.APIURL, "/"),
+		username: opts.UserName,
+		password: opts.Password,
+	}, nil
+}
+
+// apiPath returns the rest api path, using the authenticated api when the
+// client has credentials
+func (c *Client) apiPath(p string) string {
+	if c.username != "" {
+		return authPathPrefix + p
+	}
+	return p
+}
+
+func (c *Client) doRequest(method, p string, query url.Values, header http.Header, body io.Reader) (*http.Response, error) {
+	u, err := url.Parse(c.APIURL + c.apiPath(p))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return resp, errors.WithStack(gitsource.ErrUnauthorized)
+	}
+
+	// gerrit errors are reported as plain text
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return resp, errors.WithStack(err)
+	}
+	if msg := strings.TrimSpace(string(data)); msg != "" {
+		return resp, gitsource.NewAPIError(resp.StatusCode, errors.Errorf("gerrit api error (status: %d): %s", resp.StatusCode, msg))
+	}
+
+	return resp, gitsource.NewAPIError(resp.StatusCode, errors.Errorf("gerrit api error (status: %d)", resp.StatusCode))
+}
+
+func (c *Client) getParsedResponse(method, p string, query url.Values, req interface{}, obj interface{}) (*http.Response, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		body = bytes.NewReader(data)
+		header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.doRequest(method, p, query, header, body)
+	if err != nil {
+		return resp, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if obj == nil || resp.StatusCode == http.StatusNoContent {
+		return resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, errors.WithStack(err)
+	}
+	data = bytes.TrimPrefix(data, []byte(jsonMagicPrefix))
+
+	return resp, errors.WithStack(json.Unmarshal(data, obj))
+}
+
+func isNotFound(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
+
+// CreateAccessToken returns the user http password since gerrit doesn't
+// provide personal access tokens. The http password is used as the linked
+// account access token.
+func (c *Client) CreateAccessToken(tokenName string) (string, error) {
+	// verify the provided credentials
+	if _, err := c.GetUserInfo(); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return c.password, nil
+}
+
+func (c *Client) GetUserInfo() (*gitsource.UserInfo, error) {
+	if c.username == "" {
+		return nil, errors.WithStack(gitsource.ErrUnauthorized)
+	}
+
+	account := &accountInfo{}
+	if _, err := c.getParsedResponse("GET", "/accounts/self", nil, nil, account); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &gitsource.UserInfo{
+		ID:        strconv.FormatInt(account.AccountID, 10),
+		LoginName: account.Username,
+		Email:     account.Email,
+	}, nil
+}
+
+// cloneURLs returns the project ssh and http clone urls. They are taken from
+// the server download schemes when available.
+func (c *Client) cloneURLs(repopath string) (string, string, error) {
+	info := &serverInfo{}
+	if _, err := c.getParsedResponse("GET", "/config/server/info", nil, nil, info); err != nil {
+		return "", "", errors.WithStack(err)
+	}
+
+	schemeURL := func(names ...string) string {
+		for _, name := range names {
+			if scheme, ok := info.Download.Schemes[name]; ok && scheme.URL != "" {
+				return strings.ReplaceAll(scheme.URL, "${project}", repopath)
+			}
+		}
+		return ""
+	}
+
+	sshURL := schemeURL("ssh")
+	if sshURL == "" {
+		u, err := url.Parse(c.APIURL)
+		if err != nil {
+			return "", "", errors.WithStack(err)
+		}
+		user := ""
+		if c.username != "" {
+			user = c.username + "@"
+		}
+		sshURL = fmt.Sprintf("ssh://%s%s:%d/%s", user, u.Hostname(), defaultSSHPort, repopath)
+	}
+
+	httpURL := schemeURL("http", "anonymous http")
+	if httpURL == "" {
+		httpURL = c.APIURL + "/" + repopath
+	}
+
+	return sshURL, httpURL, nil
+}
+
+func (c *Client) projectLink(repopath string) string {
+	return fmt.Sprintf("%s/admin/repos/%s", c.APIURL, repopath)
+}
+
+func (c *Client) GetRepoInfo(repopath string) (*gitsource.RepoInfo, error) {
+	project := &projectInfo{}
+	if _, err := c.getParsedResponse("GET", projectAPIPath(repopath), nil, nil, project); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// an empty project doesn't have a HEAD
+	var head string
+	resp, err := c.getParsedResponse("GET", projectAPIPath(repopath)+"/HEAD", nil, nil, &head)
+	if err != nil && !isNotFound(resp) {
+		return nil, errors.WithStack(err)
+	}
+
+	sshURL, httpURL, err := c.cloneURLs(project.Name)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &gitsource.RepoInfo{
+		ID:            project.ID,
+		Path:          project.Name,
+		HTMLURL:       c.projectLink(project.Name),
+		SSHCloneURL:   sshURL,
+		HTTPCloneURL:  httpURL,
+		DefaultBranch: strings.TrimPrefix(head, branchRefPrefix),
+	}, nil
+}
+
+func (c *Client) GetFile(repopath, commit, file string) ([]byte, error) {
+	p := fmt.Sprintf("%s/commits/%s/files/%s/content", projectAPIPath(repopath), url.PathEscape(commit), url.PathEscape(strings.TrimPrefix(file, "/")))
+	resp, err := c.doRequest("GET", p, nil, nil, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	// the file content is base64 encoded
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	return content, errors.WithStack(err)
+}
+
+func (c *Client) listSSHKeys() ([]*sshKeyInfo, error) {
+	keys := []*sshKeyInfo{}
+	_, err := c.getParsedResponse("GET", "/accounts/self/sshkeys", nil, nil, &keys)
+	return keys, errors.WithStack(err)
+}
+
+func (c *Client) deleteSSHKey(seq int64) error {
+	_, err := c.getParsedResponse("DELETE", fmt.Sprintf("/accounts/self/sshkeys/%d", seq), nil, nil, nil)
+	return errors.WithStack(err)
+}
+
+// CreateDeployKey adds the key to the user ssh keys using the title as key
+// comment. Gerrit doesn't provide repository deploy keys so the key has the
+// same permissions of the linked account user and readonly is ignored.
+func (c *Client) CreateDeployKey(repopath, title, pubKey string, readonly bool) error {
+	fields := strings.Fields(pubKey)
+	if len(fields) < 2 {
+		return errors.Errorf("wrong ssh public key")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain")
+	resp, err := c.doRequest("POST", "/accounts/self/sshkeys", nil, header, strings.NewReader(fmt.Sprintf("%s %s %s", fields[0], fields[1], title)))
+	if err != nil {
+		return errors.Wrapf(err, "error creating deploy key")
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (c *Client) UpdateDeployKey(repopath, title, pubKey string, readonly bool) error {
+	keys, err := c.listSSHKeys()
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving existing deploy keys")
+	}
+
+	fields := strings.Fields(pubKey)
+	if len(fields) < 2 {
+		return errors.Errorf("wrong ssh public key")
+	}
+
+	// update the key only when the public key value has changed
+	for _, key := range keys {
+		if key.Comment == title {
+			if key.EncodedKey == fields[1] {
+				return nil
+			}
+			if err := c.deleteSSHKey(key.Seq); err != nil {
+				return errors.Wrapf(err, "error removing existing deploy key")
+			}
+		}
+	}
+
+	return errors.WithStack(c.CreateDeployKey(repopath, title, pubKey, readonly))
+}
+
+func (c *Client) DeleteDeployKey(repopath, title string) error {
+	keys, err := c.listSSHKeys()
+	if err != nil {
+		return errors.Wrapf(err, "error retrieving existing deploy keys")
+	}
+
+	for _, key := range keys {
+		if key.Comment == title {
+			if err := c.deleteSSHKey(key.Seq); err != nil {
+				return errors.Wrapf(err, "error removing existing deploy key")
+			}
+		}
+	}
+
+	return nil
+}
+
+func webhooksAPIPath(repopath string) string {
+	return fmt.Sprintf("/config/server/webhooks~projects/%s/remotes", url.PathEscape(repopath))
+}
+
+// webhookRemoteName returns the webhooks plugin remote name for the webhook
+// url so we can have multiple webhooks for different agola projects
+func webhookRemoteName(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return webhookRemotePrefix + hex.EncodeToString(sum[:])[:16]
+}
+
+// CreateRepoWebhook creates a remote of the gerrit webhooks plugin. The
+// webhooks plugin doesn't sign the payloads so the secret is added as a token
+// to the webhook url.
+func (c *Client) CreateRepoWebhook(repopath, u, secret string) error {
+	webhookURL, err := gitsource.WebhookURLWithToken(u, secret)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	remote := &webhookRemote{
+		URL:    webhookURL,
+		Events: webhookEvents,
+	}
+	if _, err := c.getParsedResponse("PUT", webhooksAPIPath(repopath)+"/"+webhookRemoteName(u), nil, remote, nil); err != nil {
+		return errors.Wrapf(err, "error creating repository webhook")
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRepoWebhook(repopath, u string) error {
+	remotes := map[string]*webhookRemote{}
+	if _, err := c.getParsedResponse("GET", webhooksAPIPath(repopath)+"/", nil, nil, &remotes); err != nil {
+		return errors.Wrapf(err, "error retrieving repository webhooks")
+	}
+
+	for name, remote := range remotes {
+		remoteURL, err := gitsource.WebhookURLWithoutToken(remote.URL)
+		if err != nil {
+			continue
+		}
+		if remoteURL == u {
+			if _, err := c.getParsedResponse("DELETE", webhooksAPIPath(repopath)+"/"+url.PathEscape(name), nil, nil, nil); err != nil {
+				return errors.Wrapf(err, "error deleting existing repository webhook")
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateCommitStatus always fails since gerrit doesn't support commit
+// statuses. The run results are reported as change reviews.
+func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource.CommitStatus, targetURL, description, context string) (bool, error) {
+	return false, errors.Errorf("gerrit doesn't support commit statuses")
+}
+
+// CreateReview reviews the change patch set voting the Verified label
+func (c *Client) CreateReview(repopath, prID string, vote gitsource.ReviewVote, message string) (bool, error) {
+	change, patchSet, err := parsePullRequestID(prID)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	review := &reviewInput{
+		Message: message,
+		Labels:  map[string]int{verifiedLabel: int(vote)},
+		Tag:     reviewTag,
+	}
+	resp, err := c.getParsedResponse("POST", fmt.Sprintf("%s/revisions/%s/review", changeAPIPath(repopath, change), patchSet), nil, review, nil)
+
+	var delivered bool
+	if resp != nil {
+		delivered = resp.StatusCode == http.StatusOK
+	}
+	return delivered, errors.WithStack(err)
+}
+
+func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
+	projects := map[string]*projectInfo{}
+	if _, err := c.getParsedResponse("GET", "/projects/", url.Values{"type": []string{"CODE"}}, nil, &projects); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	names := make([]string, 0, len(projects))
+	for name := range projects {
+		if _, ok := internalProjects[name]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	repos := make([]*gitsource.RepoInfo, 0, len(names))
+	for _, name := range names {
+		repos = append(repos, &gitsource.RepoInfo{
+			ID:      projects[name].ID,
+			Path:    name,
+			HTMLURL: c.projectLink(name),
+		})
+	}
+
+	return repos, nil
+}
+
+// getChange returns the change with all its revisions
+func (c *Client) getChange(repopath, change string) (*changeInfo, error) {
+	ci := &changeInfo{}
+	if _, err := c.getParsedResponse("GET", changeAPIPath(repopath, change), url.Values{"o": []string{"ALL_REVISIONS"}}, nil, ci); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return ci, nil
+}
+
+// patchSetRevision returns the commit sha of a change patch set
+func patchSetRevision(ci *changeInfo, patchSet int) (string, error) {
+	for sha, rev := range ci.Revisions {
+		if rev.Number == patchSet {
+			return sha, nil
+		}
+	}
+
+	return "", errors.Errorf("no patch set %d for change %d", patchSet, ci.Number)
+}
+
+// changePullRequestIDs returns the pull request ids of all the change patch
+// sets ordered by patch set number
+func changePullRequestIDs(ci *changeInfo) []string {
+	patchSets := make([]int, 0, len(ci.Revisions))
+	for _, rev := range ci.Revisions {
+		patchSets = append(patchSets, rev.Number)
+	}
+	sort.Ints(patchSets)
+
+	prIDs := make([]string, 0, len(patchSets))
+	for _, patchSet := range patchSets {
+		prIDs = append(prIDs, pullRequestID(ci.Number, patchSet))
+	}
+
+	return prIDs
+}
+
+func (c *Client) getTag(repopath, tag string) (*tagInfo, error) {
+	ti := &tagInfo{}
+	if _, err := c.getParsedResponse("GET", fmt.Sprintf("%s/tags/%s", projectAPIPath(repopath), url.PathEscape(tag)), nil, nil, ti); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return ti, nil
+}
+
+func (c *Client) GetRef(repopath, ref string) (*gitsource.Ref, error) {
+	refType, name, err := c.RefType(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	switch refType {
+	case gitsource.RefTypeBranch:
+		branch := &branchInfo{}
+		if _, err := c.getParsedResponse("GET", fmt.Sprintf("%s/branches/%s", projectAPIPath(repopath), url.PathEscape(name)), nil, nil, branch); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return &gitsource.Ref{
+			Ref:       branch.Ref,
+			CommitSHA: branch.Revision,
+		}, nil
+	case gitsource.RefTypeTag:
+		tag, err := c.getTag(repopath, name)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		// annotated tags report the tagged commit as object
+		commitSHA := tag.Revision
+		if tag.Object != "" {
+			commitSHA = tag.Object
+		}
+
+		return &gitsource.Ref{
+			Ref:       tag.Ref,
+			CommitSHA: commitSHA,
+		}, nil
+	case gitsource.RefTypePullRequest:
+		change, patchSet, err := parsePullRequestID(name)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		ci, err := c.getChange(repopath, change)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		ps, _ := strconv.Atoi(patchSet)
+		commitSHA, err := patchSetRevision(ci, ps)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return &gitsource.Ref{
+			Ref:       ref,
+			CommitSHA: commitSHA,
+		}, nil
+	default:
+		return nil, errors.Errorf("unsupported ref: %s", ref)
+	}
+}
+
+func (c *Client) RefType(ref string) (gitsource.RefType, string, error) {
+	switch {
+	case strings.HasPrefix(ref, branchRefPrefix):
+		return gitsource.RefTypeBranch, strings.TrimPrefix(ref, branchRefPrefix), nil
+
+	case strings.HasPrefix(ref, tagRefPrefix):
+		return gitsource.RefTypeTag, strings.TrimPrefix(ref, tagRefPrefix), nil
+
+	case changeRefRegex.MatchString(ref):
+		m := changeRefRegex.FindStringSubmatch(ref)
+		return gitsource.RefTypePullRequest, m[1] + "/" + m[2], nil
+
+	default:
+		return -1, "", errors.Errorf("unsupported ref: %s", ref)
+	}
+}
+
+func (c *Client) GetCommit(repopath, commitSHA string) (*gitsource.Commit, error) {
+	commit := &commitInfo{}
+	if _, err := c.getParsedResponse("GET", fmt.Sprintf("%s/commits/%s", projectAPIPath(repopath), url.PathEscape(commitSHA)), nil, nil, commit); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &gitsource.Commit{
+		SHA:     commit.Commit,
+		Message: commit.Message,
+	}, nil
+}
+
+func (c *Client) BranchRef(branch string) string {
+	return branchRefPrefix + branch
+}
+
+func (c *Client) TagRef(tag string) string {
+	return tagRefPrefix + tag
+}
+
+func (c *Client) PullRequestRef(prID string) string {
+	change, patchSet, err := parsePullRequestID(prID)
+	if err != nil {
+		return ""
+	}
+	return changeRef(change, patchSet)
+}
+
+func (c *Client) CommitLink(repoInfo *gitsource.RepoInfo, commitSHA string) string {
+	return fmt.Sprintf("%s/q/%s", c.APIURL, commitSHA)
+}
+
+func (c *Client) BranchLink(repoInfo *gitsource.RepoInfo, branch string) string {
+	return fmt.Sprintf("%s/q/%s", c.APIURL, url.PathEscape(fmt.Sprintf("project:%s branch:%s", repoInfo.Path, branch)))
+}
+
+func (c *Client) TagLink(repoInfo *gitsource.RepoInfo, tag string) string {
+	return fmt.Sprintf("%s,tags", repoInfo.HTMLURL)
+}
+
+func (c *Client) PullRequestLink(repoInfo *gitsource.RepoInfo, prID string) string {
+	return fmt.Sprintf("%s/c/%s/+/%s", c.APIURL, repoInfo.Path, prID)
+}
@@ -0,0 +1,477 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gerrit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/types"
+)
+
+const (
+	testUserName    = "user01"
+	testPassword    = "password"
+	testProject     = "prj/repo01"
+	testProjectPath = "/a/projects/prj%2Frepo01"
+	testChangePath  = "/a/changes/prj%2Frepo01~12345"
+	testCommit      = "0123456789abcdef0123456789abcdef01234567"
+	testCommit2     = "1111111111111111111111111111111111111111"
+)
+
+// fakeServer serves the gerrit api endpoints used to verify and complete
+// the webhook data and to create reviews
+type fakeServer struct {
+	t *testing.T
+
+	mu           sync.Mutex
+	reviews      map[string][]*reviewInput
+	changeStatus string
+
+	srv *httptest.Server
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{
+		t:            t,
+		reviews:      map[string][]*reviewInput{},
+		changeStatus: "NEW",
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.srv.Close)
+
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, jsonMagicPrefix)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(code)
+	fmt.Fprintln(w, msg)
+}
+
+func (s *fakeServer) change() *changeInfo {
+	return &changeInfo{
+		ID:             "prj%2Frepo01~master~I0123",
+		Project:        testProject,
+		Branch:         "master",
+		ChangeID:       "I0123",
+		Subject:        "change subject",
+		Status:         s.changeStatus,
+		Number:         12345,
+		WorkInProgress: true,
+		Hashtags:       []string{"hashtag01"},
+		Revisions: map[string]*revisionInfo{
+			testCommit2: {Number: 1, Ref: "refs/changes/45/12345/1"},
+			testCommit:  {Number: 2, Ref: "refs/changes/45/12345/2"},
+		},
+	}
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := r.URL.EscapedPath()
+
+	username, password, ok := r.BasicAuth()
+	if !ok || username != testUserName || password != testPassword {
+		writeError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+
+	decode := func(obj interface{}) {
+		if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+			s.t.Errorf("failed to decode request body: %v", err)
+		}
+	}
+
+	switch {
+	case r.Method == "GET" && p == "/a/config/server/info":
+		info := &serverInfo{}
+		info.Download.Schemes = map[string]downloadSchemeInfo{
+			"ssh":  {URL: "ssh://user01@localhost:29418/${project}"},
+			"http": {URL: s.srv.URL + "/a/${project}"},
+		}
+		writeJSON(w, http.StatusOK, info)
+
+	case r.Method == "GET" && p == testProjectPath:
+		writeJSON(w, http.StatusOK, &projectInfo{ID: "prj%2Frepo01", Name: testProject})
+
+	case r.Method == "GET" && p == testProjectPath+"/HEAD":
+		writeJSON(w, http.StatusOK, "refs/heads/master")
+
+	case r.Method == "GET" && p == testProjectPath+"/branches/master":
+		writeJSON(w, http.StatusOK, &branchInfo{Ref: "refs/heads/master", Revision: testCommit})
+
+	case r.Method == "GET" && p == testProjectPath+"/tags/v1.0":
+		writeJSON(w, http.StatusOK, &tagInfo{Ref: "refs/tags/v1.0", Revision: testCommit2, Object: testCommit})
+
+	case r.Method == "GET" && p == testProjectPath+"/commits/"+testCommit:
+		writeJSON(w, http.StatusOK, &commitInfo{Commit: testCommit, Subject: "commit subject", Message: "commit message"})
+
+	case r.Method == "GET" && p == testChangePath:
+		if r.URL.Query().Get("o") != "ALL_REVISIONS" {
+			writeError(w, http.StatusBadRequest, "missing revisions option")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.change())
+
+	case r.Method == "POST" && strings.HasPrefix(p, testChangePath+"/revisions/") && strings.HasSuffix(p, "/review"):
+		patchSet := strings.TrimSuffix(strings.TrimPrefix(p, testChangePath+"/revisions/"), "/review")
+		review := &reviewInput{}
+		decode(review)
+		s.reviews[patchSet] = append(s.reviews[patchSet], review)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"labels": review.Labels})
+
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func newTestClient(t *testing.T, s *fakeServer) *Client {
+	c, err := New(Opts{APIURL: s.srv.URL, UserName: testUserName, Password: testPassword})
+	assert.NilError(t, err)
+	return c
+}
+
+func TestRefs(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	tests := []struct {
+		ref       string
+		refType   gitsource.RefType
+		name      string
+		commitSHA string
+		err       string
+	}{
+		{ref: c.BranchRef("master"), refType: gitsource.RefTypeBranch, name: "master", commitSHA: testCommit},
+		{ref: c.TagRef("v1.0"), refType: gitsource.RefTypeTag, name: "v1.0", commitSHA: testCommit},
+		{ref: c.PullRequestRef("12345/2"), refType: gitsource.RefTypePullRequest, name: "12345/2", commitSHA: testCommit},
+		{ref: c.PullRequestRef("12345/1"), refType: gitsource.RefTypePullRequest, name: "12345/1", commitSHA: testCommit2},
+		{ref: c.PullRequestRef("12345/3"), refType: gitsource.RefTypePullRequest, name: "12345/3", err: "no patch set 3 for change 12345"},
+		{ref: "refs/meta/config", err: "unsupported ref"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			refType, name, err := c.RefType(tt.ref)
+			if tt.err == "unsupported ref" {
+				assert.ErrorContains(t, err, tt.err)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, refType, tt.refType)
+				assert.Equal(t, name, tt.name)
+			}
+
+			ref, err := c.GetRef(testProject, tt.ref)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, ref, &gitsource.Ref{Ref: tt.ref, CommitSHA: tt.commitSHA})
+		})
+	}
+
+	assert.Equal(t, c.PullRequestRef("12345/2"), "refs/changes/45/12345/2")
+	assert.Equal(t, c.PullRequestRef("5/1"), "refs/changes/05/5/1")
+	assert.Equal(t, c.PullRequestRef("12345"), "")
+
+	c, err := New(Opts{APIURL: "https://gerrit.example.com"})
+	assert.NilError(t, err)
+	repoInfo := &gitsource.RepoInfo{Path: testProject, HTMLURL: "https://gerrit.example.com/admin/repos/prj/repo01"}
+	assert.Equal(t, c.CommitLink(repoInfo, testCommit), "https://gerrit.example.com/q/"+testCommit)
+	assert.Equal(t, c.BranchLink(repoInfo, "feature/a"), "https://gerrit.example.com/q/project:prj%2Frepo01%20branch:feature%2Fa")
+	assert.Equal(t, c.TagLink(repoInfo, "v1.0"), "https://gerrit.example.com/admin/repos/prj/repo01,tags")
+	assert.Equal(t, c.PullRequestLink(repoInfo, "12345/2"), "https://gerrit.example.com/c/prj/repo01/+/12345/2")
+}
+
+func TestCreateReview(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	delivered, err := c.CreateReview(testProject, "12345/2", gitsource.ReviewVoteNone, "run pending")
+	assert.NilError(t, err)
+	assert.Assert(t, delivered)
+
+	delivered, err = c.CreateReview(testProject, "12345/2", gitsource.ReviewVoteReject, "run failed")
+	assert.NilError(t, err)
+	assert.Assert(t, delivered)
+
+	assert.DeepEqual(t, s.reviews["2"], []*reviewInput{
+		{Message: "run pending", Labels: map[string]int{"Verified": 0}, Tag: "autogenerated:agola"},
+		{Message: "run failed", Labels: map[string]int{"Verified": -1}, Tag: "autogenerated:agola"},
+	})
+
+	_, err = c.CreateReview(testProject, "12345", gitsource.ReviewVoteApprove, "")
+	assert.ErrorContains(t, err, `wrong gerrit change id "12345"`)
+
+	delivered, err = c.CreateCommitStatus(testProject, testCommit, gitsource.CommitStatusSuccess, "http://agola/run", "", "agola/project01/run")
+	assert.ErrorContains(t, err, "gerrit doesn't support commit statuses")
+	assert.Assert(t, !delivered)
+}
+
+const patchSetCreatedPayload = `{
+  "type": "patchset-created",
+  "change": {"project": "prj/repo01", "branch": "master", "id": "I0123", "number": 12345, "subject": "event subject", "owner": {"name": "User 01", "username": "user01"}, "status": "NEW"},
+  "patchSet": {"number": %d, "revision": "%s", "ref": "refs/changes/45/12345/%[1]d", "uploader": {"name": "User 02", "username": "user02"}, "kind": "REWORK"},
+  "uploader": {"name": "User 02", "username": "user02"}
+}`
+
+const changeAbandonedPayload = `{
+  "type": "change-abandoned",
+  "change": {"project": "prj/repo01", "branch": "master", "id": "I0123", "number": 12345, "subject": "event subject", "status": "ABANDONED"},
+  "patchSet": {"number": 2, "revision": "` + testCommit + `", "ref": "refs/changes/45/12345/2"},
+  "abandoner": {"name": "User 01", "username": "user01"}
+}`
+
+const refUpdatedPayload = `{
+  "type": "ref-updated",
+  "submitter": {"name": "User 01", "username": "user01"},
+  "refUpdate": {"oldRev": "` + testCommit2 + `", "newRev": "%s", "refName": "%s", "project": "prj/repo01"}
+}`
+
+func TestParseWebhook(t *testing.T) {
+	s := newFakeServer(t)
+	c := newTestClient(t, s)
+
+	htmlURL := s.srv.URL + "/admin/repos/prj/repo01"
+	sshURL := "ssh://user01@localhost:29418/prj/repo01"
+
+	tests := []struct {
+		name         string
+		payload      string
+		changeStatus string
+		token        string
+		out          *types.WebhookData
+		err          string
+	}{
+		{
+			name:    "branch push",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "refs/heads/master"),
+			out: &types.WebhookData{
+				Event:      types.WebhookEventPush,
+				SSHURL:     sshURL,
+				CommitLink: s.srv.URL + "/q/" + testCommit,
+				CommitSHA:  testCommit,
+				Ref:        "refs/heads/master",
+				Message:    "commit message",
+				Sender:     "user01",
+				Branch:     "master",
+				BranchLink: s.srv.URL + "/q/project:prj%2Frepo01%20branch:master",
+				Repo:       types.WebhookDataRepo{WebURL: htmlURL, Path: testProject},
+			},
+		},
+		{
+			name:    "branch push without refs prefix",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "master"),
+			out: &types.WebhookData{
+				Event:      types.WebhookEventPush,
+				SSHURL:     sshURL,
+				CommitLink: s.srv.URL + "/q/" + testCommit,
+				CommitSHA:  testCommit,
+				Ref:        "refs/heads/master",
+				Message:    "commit message",
+				Sender:     "user01",
+				Branch:     "master",
+				BranchLink: s.srv.URL + "/q/project:prj%2Frepo01%20branch:master",
+				Repo:       types.WebhookDataRepo{WebURL: htmlURL, Path: testProject},
+			},
+		},
+		{
+			name:    "tag push",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit2, "refs/tags/v1.0"),
+			out: &types.WebhookData{
+				Event:      types.WebhookEventTag,
+				SSHURL:     sshURL,
+				CommitLink: s.srv.URL + "/q/" + testCommit,
+				CommitSHA:  testCommit,
+				Ref:        "refs/tags/v1.0",
+				Message:    "Tag v1.0",
+				Sender:     "user01",
+				Tag:        "v1.0",
+				TagLink:    htmlURL + ",tags",
+				Repo:       types.WebhookDataRepo{WebURL: htmlURL, Path: testProject},
+			},
+		},
+		{
+			name:    "change ref update",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "refs/changes/45/12345/2"),
+		},
+		{
+			name:    "branch deletion",
+			payload: fmt.Sprintf(refUpdatedPayload, nullRevision, "refs/heads/old"),
+		},
+		{
+			name:    "branch push with wrong revision",
+			payload: fmt.Sprintf(refUpdatedPayload, "2222222222222222222222222222222222222222", "refs/heads/master"),
+			err:     "doesn't match the event revision",
+		},
+		{
+			name:    "tag push with wrong revision",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "refs/tags/v1.0"),
+			err:     "doesn't match the event revision",
+		},
+		{
+			name:    "unknown branch",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "refs/heads/unknown"),
+			err:     "failed to get ref",
+		},
+		{
+			name:    "missing webhook token",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "refs/heads/master"),
+			token:   "none",
+			err:     "wrong webhook token",
+		},
+		{
+			name:    "wrong webhook token",
+			payload: fmt.Sprintf(refUpdatedPayload, testCommit, "refs/heads/master"),
+			token:   "othersecret",
+			err:     "wrong webhook token",
+		},
+		{
+			name:    "patch set created",
+			payload: fmt.Sprintf(patchSetCreatedPayload, 2, testCommit),
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          sshURL,
+				CommitLink:      s.srv.URL + "/q/" + testCommit,
+				CommitSHA:       testCommit,
+				Ref:             "refs/changes/45/12345/2",
+				Message:         "change subject",
+				Sender:          "user02",
+				Author:          "user01",
+				PullRequestID:   "12345/2",
+				PullRequestLink: s.srv.URL + "/c/prj/repo01/+/12345/2",
+
+				PullRequestAction:       types.WebhookPullRequestActionSynchronized,
+				PullRequestLabels:       []string{"hashtag01"},
+				PullRequestTargetBranch: "master",
+				PullRequestDraft:        true,
+
+				Repo: types.WebhookDataRepo{WebURL: htmlURL, Path: testProject},
+			},
+		},
+		{
+			name:    "first patch set created",
+			payload: fmt.Sprintf(patchSetCreatedPayload, 1, testCommit2),
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          sshURL,
+				CommitLink:      s.srv.URL + "/q/" + testCommit2,
+				CommitSHA:       testCommit2,
+				Ref:             "refs/changes/45/12345/1",
+				Message:         "change subject",
+				Sender:          "user02",
+				Author:          "user01",
+				PullRequestID:   "12345/1",
+				PullRequestLink: s.srv.URL + "/c/prj/repo01/+/12345/1",
+
+				PullRequestAction:       types.WebhookPullRequestActionOpened,
+				PullRequestLabels:       []string{"hashtag01"},
+				PullRequestTargetBranch: "master",
+				PullRequestDraft:        true,
+
+				Repo: types.WebhookDataRepo{WebURL: htmlURL, Path: testProject},
+			},
+		},
+		{
+			name:    "patch set with wrong revision",
+			payload: fmt.Sprintf(patchSetCreatedPayload, 2, testCommit2),
+			err:     "doesn't match the event revision",
+		},
+		{
+			name:         "patch set created on abandoned change",
+			payload:      fmt.Sprintf(patchSetCreatedPayload, 2, testCommit),
+			changeStatus: "ABANDONED",
+		},
+		{
+			name:         "change abandoned closes all the change patch sets",
+			payload:      changeAbandonedPayload,
+			changeStatus: "ABANDONED",
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          sshURL,
+				CommitLink:      s.srv.URL + "/q/" + testCommit,
+				CommitSHA:       testCommit,
+				Ref:             "refs/changes/45/12345/2",
+				Message:         "change subject",
+				Sender:          "user01",
+				PullRequestID:   "12345/2",
+				PullRequestLink: s.srv.URL + "/c/prj/repo01/+/12345/2",
+
+				PullRequestAction:       types.WebhookPullRequestActionClosed,
+				PullRequestLabels:       []string{"hashtag01"},
+				PullRequestTargetBranch: "master",
+				PullRequestDraft:        true,
+				ClosedPullRequestIDs:    []string{"12345/1", "12345/2"},
+
+				Repo: types.WebhookDataRepo{WebURL: htmlURL, Path: testProject},
+			},
+		},
+		{
+			name:    "unknown event",
+			payload: `{"type": "comment-added"}`,
+			err:     `unknown webhook event type: "comment-added"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.mu.Lock()
+			s.changeStatus = "NEW"
+			if tt.changeStatus != "" {
+				s.changeStatus = tt.changeStatus
+			}
+			s.mu.Unlock()
+
+			r := httptest.NewRequest("POST", "/webhooks", bytes.NewBufferString(tt.payload))
+			switch tt.token {
+			case "":
+				r.Header.Set(gitsource.WebhookTokenHashHeader, gitsource.WebhookTokenHash("secret01"))
+			case "none":
+			default:
+				r.Header.Set(gitsource.WebhookTokenHashHeader, gitsource.WebhookTokenHash(tt.token))
+			}
+			whd, err := c.ParseWebhook(r, "secret01")
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			if diff := gocmp.Diff(tt.out, whd); diff != "" {
+				t.Fatalf("webhook data mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gerrit
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/types"
+)
+
+const (
+	eventPatchSetCreated = "patchset-created"
+	eventRefUpdated      = "ref-updated"
+	eventChangeAbandoned = "change-abandoned"
+	eventChangeMerged    = "change-merged"
+	eventChangeRestored  = "change-restored"
+
+	changeStatusNew = "NEW"
+
+	nullRevision = "0000000000000000000000000000000000000000"
+)
+
+// VerifyWebhook verifies the webhook url token hash, saved by the gateway in
+// the webhook headers, using the webhook secret.
+func VerifyWebhook(header http.Header, secret string) error {
+	if secret == "" {
+		return errors.Errorf("empty webhook secret")
+	}
+	tokenHash := header.Get(gitsource.WebhookTokenHashHeader)
+	if subtle.ConstantTimeCompare([]byte(tokenHash), []byte(gitsource.WebhookTokenHash(secret))) != 1 {
+		return errors.Errorf("wrong webhook token")
+	}
+
+	return nil
+}
+
+// ParseWebhook parses the stream events sent by the gerrit webhooks plugin.
+// The webhooks plugin doesn't sign the payloads, so the secret is verified
+// against the webhook url token saved by the gateway. The event refs and
+// change patch sets are also verified using the gerrit api.
+func (c *Client) ParseWebhook(r *http.Request, secret string) (*types.WebhookData, error) {
+	if err := VerifyWebhook(r.Header, secret); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, 10*1024*1024))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ev := new(event)
+	if err := json.Unmarshal(data, ev); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var whd *types.WebhookData
+	switch ev.Type {
+	case eventRefUpdated:
+		whd, err = webhookDataFromRefUpdate(ev)
+	case eventPatchSetCreated:
+		action := types.WebhookPullRequestActionSynchronized
+		if ev.PatchSet != nil && ev.PatchSet.Number == 1 {
+			action = types.WebhookPullRequestActionOpened
+		}
+		whd, err = webhookDataFromChange(ev, action, ev.Uploader)
+	case eventChangeRestored:
+		whd, err = webhookDataFromChange(ev, types.WebhookPullRequestActionReopened, ev.Restorer)
+	case eventChangeAbandoned:
+		whd, err = webhookDataFromChange(ev, types.WebhookPullRequestActionClosed, ev.Abandoner)
+	case eventChangeMerged:
+		whd, err = webhookDataFromChange(ev, types.WebhookPullRequestActionMerged, ev.Submitter)
+	default:
+		return nil, errors.Errorf("unknown webhook event type: %q", ev.Type)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if whd == nil {
+		return nil, nil
+	}
+
+	whd, err = c.completeWebhookData(whd)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return whd, nil
+}
+
+// completeWebhookData verifies the webhook data using the gerrit api and
+// populates the fields not provided by the gerrit events (repository urls,
+// links, the commit message and the change details)
+func (c *Client) completeWebhookData(whd *types.WebhookData) (*types.WebhookData, error) {
+	repoInfo, err := c.GetRepoInfo(whd.Repo.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get project %q", whd.Repo.Path)
+	}
+
+	whd.SSHURL = repoInfo.SSHCloneURL
+	whd.Repo.WebURL = repoInfo.HTMLURL
+	whd.CommitLink = c.CommitLink(repoInfo, whd.CommitSHA)
+
+	switch whd.Event {
+	case types.WebhookEventPush:
+		whd.BranchLink = c.BranchLink(repoInfo, whd.Branch)
+
+		ref, err := c.GetRef(whd.Repo.Path, whd.Ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get ref %q", whd.Ref)
+		}
+		if ref.CommitSHA != whd.CommitSHA {
+			return nil, errors.Errorf("ref %q revision %q doesn't match the event revision %q", whd.Ref, ref.CommitSHA, whd.CommitSHA)
+		}
+
+		commit, err := c.GetCommit(whd.Repo.Path, whd.CommitSHA)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get commit %q", whd.CommitSHA)
+		}
+		whd.Message = commit.Message
+	case types.WebhookEventTag:
+		whd.TagLink = c.TagLink(repoInfo, whd.Tag)
+
+		// the event revision of an annotated tag is the tag object, so compare
+		// it with the tag revision and use the tagged commit
+		tag, err := c.getTag(whd.Repo.Path, whd.Tag)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get tag %q", whd.Tag)
+		}
+		if tag.Revision != whd.CommitSHA {
+			return nil, errors.Errorf("tag %q revision %q doesn't match the event revision %q", whd.Tag, tag.Revision, whd.CommitSHA)
+		}
+		if tag.Object != "" {
+			whd.CommitSHA = tag.Object
+			whd.CommitLink = c.CommitLink(repoInfo, whd.CommitSHA)
+		}
+	case types.WebhookEventPullRequest:
+		whd.PullRequestLink = c.PullRequestLink(repoInfo, whd.PullRequestID)
+
+		change, patchSet, err := parsePullRequestID(whd.PullRequestID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		ci, err := c.getChange(whd.Repo.Path, change)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get change %s", change)
+		}
+		ps, _ := strconv.Atoi(patchSet)
+		commitSHA, err := patchSetRevision(ci, ps)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if commitSHA != whd.CommitSHA {
+			return nil, errors.Errorf("change %s patch set %s revision %q doesn't match the event revision %q", change, patchSet, commitSHA, whd.CommitSHA)
+		}
+
+		// skip non open changes
+		if !whd.PullRequestAction.IsClosed() && ci.Status != changeStatusNew {
+			return nil, nil
+		}
+
+		whd.Message = ci.Subject
+		whd.PullRequestTargetBranch = ci.Branch
+		whd.PullRequestDraft = ci.WorkInProgress
+		whd.PullRequestLabels = ci.Hashtags
+
+		// closing a change closes all its patch sets
+		if whd.PullRequestAction.IsClosed() {
+			whd.ClosedPullRequestIDs = changePullRequestIDs(ci)
+		}
+	}
+
+	return whd, nil
+}
+
+func webhookDataFromRefUpdate(ev *event) (*types.WebhookData, error) {
+	ru := ev.RefUpdate
+	if ru == nil {
+		return nil, errors.Errorf("missing ref update")
+	}
+
+	// skip ref deletions
+	if ru.NewRev == nullRevision {
+		return nil, nil
+	}
+
+	whd := &types.WebhookData{
+		CommitSHA: ru.NewRev,
+		Ref:       ru.RefName,
+
+		Repo: types.WebhookDataRepo{
+			Path: ru.Project,
+		},
+	}
+	if ev.Submitter != nil {
+		whd.Sender = ev.Submitter.Username
+	}
+
+	// old gerrit versions report the branch name without the refs/heads
+	// prefix
+	if !strings.HasPrefix(ru.RefName, "refs/") {
+		whd.Ref = branchRefPrefix + ru.RefName
+	}
+
+	switch {
+	case strings.HasPrefix(whd.Ref, branchRefPrefix):
+		whd.Event = types.WebhookEventPush
+		whd.Branch = strings.TrimPrefix(whd.Ref, branchRefPrefix)
+	case strings.HasPrefix(whd.Ref, tagRefPrefix):
+		whd.Event = types.WebhookEventTag
+		whd.Tag = strings.TrimPrefix(whd.Ref, tagRefPrefix)
+		whd.Message = fmt.Sprintf("Tag %s", whd.Tag)
+	default:
+		// skip the gerrit internal refs updates (change refs, meta refs etc...)
+		return nil, nil
+	}
+
+	return whd, nil
+}
+
+// webhookDataFromChange extracts the webhook data from a gerrit change event.
+// Every change patch set is handled as a different pull request. Since any
+// registered user can upload a change, changes are handled like pull requests
+// from a forked repository.
+func webhookDataFromChange(ev *event, action types.WebhookPullRequestAction, sender *eventAccount) (*types.WebhookData, error) {
+	if ev.Change == nil || ev.PatchSet == nil {
+		return nil, errors.Errorf("missing change or patch set")
+	}
+
+	prID := pullRequestID(ev.Change.Number, ev.PatchSet.Number)
+
+	whd := &types.WebhookData{
+		Event:          types.WebhookEventPullRequest,
+		CommitSHA:      ev.PatchSet.Revision,
+		Ref:            changeRef(strconv.FormatInt(ev.Change.Number, 10), strconv.Itoa(ev.PatchSet.Number)),
+		Message:        ev.Change.Subject,
+		Author:         ev.Change.Owner.Username,
+		PullRequestID:  prID,
+		PRFromSameRepo: false,
+
+		PullRequestAction:       action,
+		PullRequestTargetBranch: ev.Change.Branch,
+		PullRequestDraft:        ev.Change.WIP,
+
+		Repo: types.WebhookDataRepo{
+			Path: ev.Change.Project,
+		},
+	}
+	if sender != nil {
+		whd.Sender = sender.Username
+	}
+
+	return whd, nil
+}
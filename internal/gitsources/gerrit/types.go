@@ -0,0 +1,143 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gerrit
+
+type accountInfo struct {
+	AccountID int64  `json:"_account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+}
+
+type projectInfo struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state,omitempty"`
+}
+
+type branchInfo struct {
+	Ref      string `json:"ref"`
+	Revision string `json:"revision"`
+}
+
+type tagInfo struct {
+	Ref      string `json:"ref"`
+	Revision string `json:"revision"`
+	// Object is the id of the tagged commit, only set for annotated tags
+	Object string `json:"object,omitempty"`
+}
+
+type commitInfo struct {
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+	Message string `json:"message"`
+}
+
+type revisionInfo struct {
+	Number int    `json:"_number"`
+	Ref    string `json:"ref"`
+}
+
+type changeInfo struct {
+	ID              string                   `json:"id"`
+	Project         string                   `json:"project"`
+	Branch          string                   `json:"branch"`
+	ChangeID        string                   `json:"change_id"`
+	Subject         string                   `json:"subject"`
+	Status          string                   `json:"status"`
+	Number          int64                    `json:"_number"`
+	WorkInProgress  bool                     `json:"work_in_progress,omitempty"`
+	Hashtags        []string                 `json:"hashtags,omitempty"`
+	CurrentRevision string                   `json:"current_revision,omitempty"`
+	Revisions       map[string]*revisionInfo `json:"revisions,omitempty"`
+}
+
+type sshKeyInfo struct {
+	Seq          int64  `json:"seq"`
+	SSHPublicKey string `json:"ssh_public_key"`
+	EncodedKey   string `json:"encoded_key"`
+	Algorithm    string `json:"algorithm"`
+	Comment      string `json:"comment,omitempty"`
+	Valid        bool   `json:"valid"`
+}
+
+type downloadSchemeInfo struct {
+	URL string `json:"url"`
+}
+
+type serverInfo struct {
+	Download struct {
+		Schemes map[string]downloadSchemeInfo `json:"schemes"`
+	} `json:"download"`
+}
+
+// webhookRemote is a remote of the gerrit webhooks plugin
+type webhookRemote struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+}
+
+type reviewInput struct {
+	Message string         `json:"message,omitempty"`
+	Labels  map[string]int `json:"labels,omitempty"`
+	Tag     string         `json:"tag,omitempty"`
+}
+
+// event types are the stream events sent by the gerrit webhooks plugin
+
+type eventAccount struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+type eventChange struct {
+	Project string       `json:"project"`
+	Branch  string       `json:"branch"`
+	ID      string       `json:"id"`
+	Number  int64        `json:"number"`
+	Subject string       `json:"subject"`
+	Owner   eventAccount `json:"owner"`
+	URL     string       `json:"url"`
+	Status  string       `json:"status"`
+	WIP     bool         `json:"wip,omitempty"`
+}
+
+type eventPatchSet struct {
+	Number   int          `json:"number"`
+	Revision string       `json:"revision"`
+	Ref      string       `json:"ref"`
+	Uploader eventAccount `json:"uploader"`
+	Kind     string       `json:"kind"`
+}
+
+type eventRefUpdate struct {
+	OldRev  string `json:"oldRev"`
+	NewRev  string `json:"newRev"`
+	RefName string `json:"refName"`
+	Project string `json:"project"`
+}
+
+type event struct {
+	Type      string          `json:"type"`
+	Change    *eventChange    `json:"change,omitempty"`
+	PatchSet  *eventPatchSet  `json:"patchSet,omitempty"`
+	RefUpdate *eventRefUpdate `json:"refUpdate,omitempty"`
+
+	Uploader  *eventAccount `json:"uploader,omitempty"`
+	Submitter *eventAccount `json:"submitter,omitempty"`
+	Abandoner *eventAccount `json:"abandoner,omitempty"`
+	Restorer  *eventAccount `json:"restorer,omitempty"`
+}
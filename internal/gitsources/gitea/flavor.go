@@ -0,0 +1,259 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+)
+
+// Flavor is the gitea compatible git server implementation
+type Flavor string
+
+const (
+	FlavorGitea   Flavor = "gitea"
+	FlavorForgejo Flavor = "forgejo"
+	FlavorGogs    Flavor = "gogs"
+)
+
+const (
+	// gogsGiteaVersion is the gitea version whose api is the closest to the
+	// gogs one. Setting it avoids the gitea sdk querying the version endpoint
+	// (not provided by gogs) and makes it use the old api paths (i.e. for raw
+	// files)
+	gogsGiteaVersion = "1.11.0"
+
+	// forgejoGiteaVersionSep separates the forgejo version from the gitea
+	// compatible version in the forgejo version (i.e. 7.0.0+gitea-1.22.0)
+	forgejoGiteaVersionSep = "+gitea-"
+
+	gogsSHAMediaType = "application/vnd.gogs.sha"
+
+	// hookTypeForgejo is the forgejo webhook type (not defined by the gitea sdk)
+	hookTypeForgejo gitea.HookType = "forgejo"
+
+	// forgejoVersionsCacheTTL is how long a detected forgejo version is
+	// cached, so server upgrades are detected
+	forgejoVersionsCacheTTL = 1 * time.Hour
+)
+
+type forgejoVersionsCacheEntry struct {
+	giteaVersion string
+	time         time.Time
+}
+
+// forgejoVersionsCache caches the gitea compatible versions of the forgejo
+// servers by api url, so they aren't requested at every client creation
+var forgejoVersionsCache = struct {
+	sync.Mutex
+	entries map[string]forgejoVersionsCacheEntry
+}{entries: map[string]forgejoVersionsCacheEntry{}}
+
+// capabilities reports the api features available on a git server flavor
+type capabilities struct {
+	// commitStatuses reports if the server supports commit statuses
+	commitStatuses bool
+	// gitAPI reports if the server provides the git refs and git commits apis
+	gitAPI bool
+	// paginatedRepos reports if the server paginates the user repos list
+	paginatedRepos bool
+	// deleteAccessToken reports if access tokens can be deleted using the api
+	deleteAccessToken bool
+	// pullRequestRefs reports if the pull requests head refs can be fetched using the api
+	pullRequestRefs bool
+	// pullRequestComments reports if the pull requests comments can be edited using the api
+	pullRequestComments bool
+}
+
+func flavorCapabilities(flavor Flavor) capabilities {
+	switch flavor {
+	case FlavorGogs:
+		return capabilities{}
+	default:
+		return capabilities{
+			commitStatuses:      true,
+			gitAPI:              true,
+			paginatedRepos:      true,
+			deleteAccessToken:   true,
+			pullRequestRefs:     true,
+			pullRequestComments: true,
+		}
+	}
+}
+
+// hookType returns the webhook type to use when creating a repository webhook
+func (f Flavor) hookType() gitea.HookType {
+	switch f {
+	case FlavorForgejo:
+		return hookTypeForgejo
+	case FlavorGogs:
+		return gitea.HookTypeGogs
+	default:
+		return gitea.HookTypeGitea
+	}
+}
+
+// hookHeaderPrefixes returns the webhook request header prefixes in order of
+// preference. Forgejo also sends the gitea headers for compatibility but
+// older releases only send them.
+func (f Flavor) hookHeaderPrefixes() []string {
+	switch f {
+	case FlavorForgejo:
+		return []string{"X-Forgejo-", "X-Gitea-"}
+	case FlavorGogs:
+		return []string{"X-Gogs-"}
+	default:
+		return []string{"X-Gitea-"}
+	}
+}
+
+// hookHeader returns the value of the first available webhook header with
+// the provided name (i.e. Event, Signature)
+func (f Flavor) hookHeader(header http.Header, name string) string {
+	for _, prefix := range f.hookHeaderPrefixes() {
+		if v := header.Get(prefix + name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// forgejoGiteaVersion returns the gitea version compatible with the forgejo
+// server api. The forgejo version isn't comparable with the gitea one and
+// would make the gitea sdk use apis not available on the server.
+// The version is requested with the client credentials and cached by api url.
+func (c *Client) forgejoGiteaVersion() (string, error) {
+	forgejoVersionsCache.Lock()
+	e, ok := forgejoVersionsCache.entries[c.APIURL]
+	forgejoVersionsCache.Unlock()
+	if ok && time.Since(e.time) < forgejoVersionsCacheTTL {
+		return e.giteaVersion, nil
+	}
+
+	giteaVersion, err := c.getForgejoGiteaVersion()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	forgejoVersionsCache.Lock()
+	forgejoVersionsCache.entries[c.APIURL] = forgejoVersionsCacheEntry{giteaVersion: giteaVersion, time: time.Now()}
+	forgejoVersionsCache.Unlock()
+
+	return giteaVersion, nil
+}
+
+func (c *Client) getForgejoGiteaVersion() (string, error) {
+	resp, err := c.doAPIRequest("GET", "/version", nil)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	var v struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	if _, giteaVersion, ok := strings.Cut(v.Version, forgejoGiteaVersionSep); ok {
+		return giteaVersion, nil
+	}
+
+	// older forgejo releases use the gitea version with a forgejo release suffix (i.e. 1.21.11-1)
+	giteaVersion, _, _ := strings.Cut(v.Version, "-")
+
+	return giteaVersion, nil
+}
+
+// doAPIRequest executes an api request without using the gitea sdk. It's
+// used for the gogs api endpoints that aren't compatible with the gitea sdk
+// ones and before the gitea sdk client is created.
+func (c *Client) doAPIRequest(method, p string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.APIURL+"/api/v1"+p, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "token "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return resp, gitsource.NewAPIError(resp.StatusCode, errors.Errorf("%s api error (status: %d): %s", c.flavor, resp.StatusCode, strings.TrimSpace(string(data))))
+	}
+
+	return resp, nil
+}
+
+// gogsRefCommitSHA returns the commit sha of a gogs branch or tag ref
+func (c *Client) gogsRefCommitSHA(owner, reponame, ref string) (string, error) {
+	header := http.Header{}
+	header.Set("Accept", gogsSHAMediaType)
+
+	resp, err := c.doAPIRequest("GET", fmt.Sprintf("/repos/%s/%s/commits/%s", url.PathEscape(owner), url.PathEscape(reponame), ref), header)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// gogsCommit returns a gogs commit. Gogs doesn't provide the git commits api
+// used by the gitea sdk.
+func (c *Client) gogsCommit(owner, reponame, commitSHA string) (*gitea.Commit, error) {
+	resp, err := c.doAPIRequest("GET", fmt.Sprintf("/repos/%s/%s/commits/%s", url.PathEscape(owner), url.PathEscape(reponame), url.PathEscape(commitSHA)), nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	commit := &gitea.Commit{}
+	if err := json.NewDecoder(resp.Body).Decode(commit); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return commit, nil
+}
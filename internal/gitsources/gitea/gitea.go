@@ -16,21 +16,19 @@ package gitea
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
-	"net"
 	"net/http"
 	"path"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/sorintlab/errors"
 	"golang.org/x/oauth2"
 
 	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/httpclient"
 )
 
 var (
@@ -43,21 +41,28 @@ var (
 	pullRequestRefFmt   = "refs/pull/%s/head"
 )
 
-type httpOpts struct {
-	SkipVerify bool
-}
-
 type Opts struct {
 	APIURL     string
 	SkipVerify bool
 	UserName   string
 	Password   string
 	Token      string
+	// Flavor is the gitea compatible server implementation. Defaults to gitea
+	Flavor Flavor
 }
 
 type Client struct {
 	client *gitea.Client
 	APIURL string
+
+	flavor Flavor
+	caps   capabilities
+
+	// used for the requests not covered by the gitea sdk
+	httpClient *http.Client
+	token      string
+	username   string
+	password   string
 }
 
 // fromCommitStatus converts a gitsource commit status to a gitea commit status
@@ -84,51 +89,62 @@ func parseRepoPath(repopath string) (string, string, error) {
 	return parts[0], parts[1], nil
 }
 
-func newHTTPClient(opts httpOpts) *http.Client {
-	// copied from net/http until it has a clone function: https://github.com/golang/go/issues/26013
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: opts.SkipVerify},
+func newClient(opts Opts, authOpt gitea.ClientOption, token, username, password string) (*Client, error) {
+	flavor := opts.Flavor
+	if flavor == "" {
+		flavor = FlavorGitea
+	}
+
+	c := &Client{
+		APIURL:     strings.TrimSuffix(opts.APIURL, "/"),
+		flavor:     flavor,
+		caps:       flavorCapabilities(flavor),
+		httpClient: httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify}),
+		token:      token,
+		username:   username,
+		password:   password,
+	}
+
+	clientOpts := []gitea.ClientOption{authOpt, gitea.SetHTTPClient(c.httpClient)}
+	switch flavor {
+	case FlavorGitea:
+	case FlavorForgejo:
+		giteaVersion, err := c.forgejoGiteaVersion()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to detect forgejo version")
+		}
+		clientOpts = append(clientOpts, gitea.SetGiteaVersion(giteaVersion))
+	case FlavorGogs:
+		clientOpts = append(clientOpts, gitea.SetGiteaVersion(gogsGiteaVersion))
+	default:
+		return nil, errors.Errorf("unknown gitea flavor %q", flavor)
 	}
 
-	return &http.Client{Transport: transport}
+	client, err := gitea.NewClient(opts.APIURL, clientOpts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s client", flavor)
+	}
+	c.client = client
+
+	return c, nil
 }
 
 func New(opts Opts) (*Client, error) {
-	httpClient := newHTTPClient(httpOpts{SkipVerify: opts.SkipVerify})
-
-	client, err := gitea.NewClient(opts.APIURL, gitea.SetToken(opts.Token), gitea.SetHTTPClient(httpClient))
+	c, err := newClient(opts, gitea.SetToken(opts.Token), opts.Token, "", "")
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create gitea client")
+		return nil, errors.WithStack(err)
 	}
 
-	return &Client{
-		client: client,
-		APIURL: opts.APIURL,
-	}, nil
+	return c, nil
 }
 
 func NewWithBasicAuth(opts Opts) (*Client, error) {
-	httpClient := newHTTPClient(httpOpts{SkipVerify: opts.SkipVerify})
-
-	client, err := gitea.NewClient(opts.APIURL, gitea.SetBasicAuth(opts.UserName, opts.Password), gitea.SetHTTPClient(httpClient))
+	c, err := newClient(opts, gitea.SetBasicAuth(opts.UserName, opts.Password), "", opts.UserName, opts.Password)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create gitea client")
+		return nil, errors.WithStack(err)
 	}
 
-	return &Client{
-		client: client,
-		APIURL: opts.APIURL,
-	}, nil
+	return c, nil
 }
 
 func (c *Client) CreateAccessToken(tokenName string) (string, error) {
@@ -152,6 +168,17 @@ func (c *Client) CreateAccessToken(tokenName string) (string, error) {
 
 	// remove existing access token
 	if hasAccessToken {
+		// gogs doesn't provide an api to delete access tokens but returns
+		// their value
+		if !c.caps.deleteAccessToken {
+			for _, token := range tokens {
+				if token.Name == tokenName && token.Token != "" {
+					return token.Token, nil
+				}
+			}
+			return "", errors.Errorf("access token %q already exists and cannot be removed on %s, remove it from the user settings", tokenName, c.flavor)
+		}
+
 		if _, err := c.client.DeleteAccessToken(tokenName); err != nil {
 			return "", errors.WithStack(err)
 		}
@@ -285,7 +312,7 @@ func (c *Client) CreateRepoWebhook(repopath, url, secret string) error {
 	}
 
 	opts := gitea.CreateHookOption{
-		Type: "gitea",
+		Type: c.flavor.hookType(),
 		Config: map[string]string{
 			"url":          url,
 			"content_type": "json",
@@ -330,6 +357,12 @@ func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource
 	if err != nil {
 		return false, errors.WithStack(err)
 	}
+
+	// commit statuses aren't supported, report them as not delivered
+	if !c.caps.commitStatuses {
+		return false, nil
+	}
+
 	_, resp, err := c.client.CreateStatus(owner, reponame, commitSHA, gitea.CreateStatusOption{
 		State:       fromCommitStatus(status),
 		TargetURL:   targetURL,
@@ -344,6 +377,59 @@ func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource
 	return delivered, errors.WithStack(err)
 }
 
+func (c *Client) UpdatePullRequestComment(repopath, prID, marker, body string) (bool, error) {
+	owner, reponame, err := parseRepoPath(repopath)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	index, err := strconv.ParseInt(prID, 10, 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "wrong pull request id %q", prID)
+	}
+
+	// pull request comments edit isn't supported, report them as not delivered
+	if !c.caps.pullRequestComments {
+		return false, nil
+	}
+
+	var commentID int64
+	page := 1
+	for commentID == 0 {
+		comments, _, err := c.client.ListIssueComments(owner, reponame, index, gitea.ListIssueCommentOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: 50,
+			},
+		})
+		if err != nil {
+			return false, errors.Wrapf(err, "error retrieving pull request comments")
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.Body, marker) {
+				commentID = comment.ID
+				break
+			}
+		}
+		if len(comments) == 0 {
+			break
+		}
+		page++
+	}
+
+	var resp *gitea.Response
+	if commentID != 0 {
+		_, resp, err = c.client.EditIssueComment(owner, reponame, commentID, gitea.EditIssueCommentOption{Body: body})
+	} else {
+		_, resp, err = c.client.CreateIssueComment(owner, reponame, index, gitea.CreateIssueCommentOption{Body: body})
+	}
+
+	var delivered bool
+	if resp != nil {
+		delivered = resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated
+	}
+	return delivered, errors.WithStack(err)
+}
+
 func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
 	page := 1
 	repos := []*gitsource.RepoInfo{}
@@ -369,8 +455,9 @@ func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
 			repos = append(repos, fromGiteaRepo(repo))
 		}
 
-		// Check if no more repos are available
-		if len(remoteRepos) == 0 {
+		// Check if no more repos are available. Gogs returns all the repos
+		// ignoring the page
+		if len(remoteRepos) == 0 || !c.caps.paginatedRepos {
 			break
 		} else {
 			page = page + 1
@@ -396,6 +483,10 @@ func (c *Client) GetRef(repopath, ref string) (*gitsource.Ref, error) {
 		return nil, errors.WithStack(err)
 	}
 
+	if !c.caps.gitAPI {
+		return c.getGogsRef(owner, reponame, ref)
+	}
+
 	remoteRefs, _, err := c.client.GetRepoRefs(owner, reponame, ref)
 	if err != nil {
 		return nil, errors.WithStack(err)
@@ -410,6 +501,26 @@ func (c *Client) GetRef(repopath, ref string) (*gitsource.Ref, error) {
 	return fromGiteaRef(remoteRefs[0])
 }
 
+func (c *Client) getGogsRef(owner, reponame, ref string) (*gitsource.Ref, error) {
+	refType, _, err := c.RefType(ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if refType == gitsource.RefTypePullRequest && !c.caps.pullRequestRefs {
+		return nil, errors.Errorf("pull request refs aren't supported on %s", c.flavor)
+	}
+
+	commitSHA, err := c.gogsRefCommitSHA(owner, reponame, ref)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &gitsource.Ref{
+		Ref:       ref,
+		CommitSHA: commitSHA,
+	}, nil
+}
+
 func fromGiteaRef(remoteRef *gitea.Reference) (*gitsource.Ref, error) {
 	t := remoteRef.Object.Type
 	switch t {
@@ -447,7 +558,12 @@ func (c *Client) GetCommit(repopath, commitSHA string) (*gitsource.Commit, error
 		return nil, errors.WithStack(err)
 	}
 
-	commit, _, err := c.client.GetSingleCommit(owner, reponame, commitSHA)
+	var commit *gitea.Commit
+	if c.caps.gitAPI {
+		commit, _, err = c.client.GetSingleCommit(owner, reponame, commitSHA)
+	} else {
+		commit, err = c.gogsCommit(owner, reponame, commitSHA)
+	}
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -475,10 +591,16 @@ func (c *Client) CommitLink(repoInfo *gitsource.RepoInfo, commitSHA string) stri
 }
 
 func (c *Client) BranchLink(repoInfo *gitsource.RepoInfo, branch string) string {
+	if c.flavor == FlavorGogs {
+		return fmt.Sprintf("%s/src/%s", repoInfo.HTMLURL, branch)
+	}
 	return fmt.Sprintf("%s/src/branch/%s", repoInfo.HTMLURL, branch)
 }
 
 func (c *Client) TagLink(repoInfo *gitsource.RepoInfo, tag string) string {
+	if c.flavor == FlavorGogs {
+		return fmt.Sprintf("%s/src/%s", repoInfo.HTMLURL, tag)
+	}
 	return fmt.Sprintf("%s/src/tag/%s", repoInfo.HTMLURL, tag)
 }
 
@@ -501,7 +623,7 @@ type Oauth2Client struct {
 }
 
 func NewOauth2Client(opts Oauth2Opts) (*Oauth2Client, error) {
-	httpClient := newHTTPClient(httpOpts{SkipVerify: opts.SkipVerify})
+	httpClient := httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify})
 
 	return &Oauth2Client{
 		httpClient:     httpClient,
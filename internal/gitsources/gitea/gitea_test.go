@@ -0,0 +1,427 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitea
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/types"
+)
+
+const testCommit = "0123456789abcdef0123456789abcdef01234567"
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+// newFakeServer returns a fake gitea compatible server reporting the provided version
+func newFakeServer(t *testing.T, version string) (*httptest.Server, *[]string) {
+	var requests []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.URL.Path {
+		case "/api/v1/version":
+			if version == "" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			writeJSON(w, map[string]string{"version": version})
+		case "/api/v1/repos/owner01/repo01/commits/refs/heads/master", "/api/v1/repos/user02/repo01/commits/refs/heads/feature":
+			if r.Header.Get("Accept") != gogsSHAMediaType {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, testCommit)
+		case "/api/v1/repos/owner01/repo01/commits/" + testCommit:
+			writeJSON(w, map[string]interface{}{"sha": testCommit, "commit": map[string]string{"message": "commit message"}})
+		case "/api/v1/user/repos":
+			writeJSON(w, []map[string]interface{}{
+				{"id": 1, "name": "repo01", "owner": map[string]string{"username": "owner01"}, "permissions": map[string]bool{"admin": true}},
+			})
+		case "/api/v1/repos/owner01/repo01/issues/2/comments", "/api/v1/repos/owner01/repo01/issues/3/comments":
+			if r.Method == "POST" {
+				w.WriteHeader(http.StatusCreated)
+				writeJSON(w, map[string]interface{}{"id": 12})
+				return
+			}
+			comments := []map[string]interface{}{}
+			if r.URL.Path == "/api/v1/repos/owner01/repo01/issues/2/comments" && r.URL.Query().Get("page") == "1" {
+				comments = append(comments, map[string]interface{}{"id": 10, "body": "comment"}, map[string]interface{}{"id": 11, "body": "<!-- marker -->\nold results"})
+			}
+			writeJSON(w, comments)
+		case "/api/v1/repos/owner01/repo01/issues/comments/11":
+			writeJSON(w, map[string]interface{}{"id": 11})
+		case "/api/v1/users/user01/tokens":
+			writeJSON(w, []map[string]interface{}{{"name": "agola-01", "sha1": "token01"}, {"name": "agola-02"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestForgejoGiteaVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		giteaVersion string
+	}{
+		{version: "7.0.0+gitea-1.22.0", giteaVersion: "1.22.0"},
+		{version: "9.0.1-1-abcdef+gitea-1.22.0", giteaVersion: "1.22.0"},
+		{version: "1.21.11-1", giteaVersion: "1.21.11"},
+		{version: "1.20.0", giteaVersion: "1.20.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			srv, requests := newFakeServer(t, tt.version)
+
+			// the version api is requested with the client credentials
+			_, err := New(Opts{APIURL: srv.URL, Flavor: FlavorForgejo})
+			assert.ErrorContains(t, err, "failed to detect forgejo version")
+
+			c, err := New(Opts{APIURL: srv.URL, Token: "token01", Flavor: FlavorForgejo})
+			assert.NilError(t, err)
+			assert.Equal(t, c.flavor, FlavorForgejo)
+
+			giteaVersion, err := c.forgejoGiteaVersion()
+			assert.NilError(t, err)
+			assert.Equal(t, giteaVersion, tt.giteaVersion)
+
+			// the detected version is cached by api url
+			_, err = NewWithBasicAuth(Opts{APIURL: srv.URL, UserName: "user01", Password: "password", Flavor: FlavorForgejo})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, *requests, []string{"GET /api/v1/version", "GET /api/v1/version"})
+		})
+	}
+}
+
+func TestGogs(t *testing.T) {
+	srv, requests := newFakeServer(t, "")
+
+	c, err := NewWithBasicAuth(Opts{APIURL: srv.URL, UserName: "user01", Password: "password", Flavor: FlavorGogs})
+	assert.NilError(t, err)
+	// gogs doesn't provide the version api
+	assert.Equal(t, len(*requests), 0)
+
+	delivered, err := c.CreateCommitStatus("owner01/repo01", testCommit, gitsource.CommitStatusSuccess, "http://agola/run", "", "agola")
+	assert.NilError(t, err)
+	assert.Assert(t, !delivered)
+
+	ref, err := c.GetRef("owner01/repo01", "refs/heads/master")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, ref, &gitsource.Ref{Ref: "refs/heads/master", CommitSHA: testCommit})
+
+	_, err = c.GetRef("owner01/repo01", "refs/pull/1/head")
+	assert.ErrorContains(t, err, "pull request refs aren't supported on gogs")
+
+	commit, err := c.GetCommit("owner01/repo01", testCommit)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, commit, &gitsource.Commit{SHA: testCommit, Message: "commit message"})
+
+	repos, err := c.ListUserRepos()
+	assert.NilError(t, err)
+	assert.Equal(t, len(repos), 1)
+
+	token, err := c.CreateAccessToken("agola-01")
+	assert.NilError(t, err)
+	assert.Equal(t, token, "token01")
+
+	_, err = c.CreateAccessToken("agola-02")
+	assert.ErrorContains(t, err, `access token "agola-02" already exists and cannot be removed on gogs`)
+
+	delivered, err = c.UpdatePullRequestComment("owner01/repo01", "2", "<!-- marker -->", "<!-- marker -->\nresults")
+	assert.NilError(t, err)
+	assert.Assert(t, !delivered)
+
+	repoInfo := &gitsource.RepoInfo{HTMLURL: "http://gogs/owner01/repo01"}
+	assert.Equal(t, c.BranchLink(repoInfo, "master"), "http://gogs/owner01/repo01/src/master")
+	assert.Equal(t, c.TagLink(repoInfo, "v1.0"), "http://gogs/owner01/repo01/src/v1.0")
+}
+
+func TestUpdatePullRequestComment(t *testing.T) {
+	tests := []struct {
+		name            string
+		prID            string
+		expectedRequest string
+	}{
+		{
+			name:            "update existing comment",
+			prID:            "2",
+			expectedRequest: "PATCH /api/v1/repos/owner01/repo01/issues/comments/11",
+		},
+		{
+			name:            "create comment",
+			prID:            "3",
+			expectedRequest: "POST /api/v1/repos/owner01/repo01/issues/3/comments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newFakeServer(t, "1.22.0")
+
+			c, err := New(Opts{APIURL: srv.URL, Token: "token01", Flavor: FlavorGitea})
+			assert.NilError(t, err)
+
+			delivered, err := c.UpdatePullRequestComment("owner01/repo01", tt.prID, "<!-- marker -->", "<!-- marker -->\nresults")
+			assert.NilError(t, err)
+			assert.Assert(t, delivered)
+			assert.Equal(t, (*requests)[len(*requests)-1], tt.expectedRequest)
+		})
+	}
+}
+
+const pushPayload = `{
+  "ref": "refs/heads/master",
+  "after": "` + testCommit + `",
+  "compare_url": "http://git/owner01/repo01/compare/a...b",
+  "commits": [{"id": "` + testCommit + `", "message": "commit message", "author": {"username": "user03"}}],
+  "repository": {"id": 1, "name": "repo01", "html_url": "http://git/owner01/repo01", "ssh_url": "ssh://git@git/owner01/repo01.git", "owner": {"username": "owner01"}},
+  "sender": {"login": "user01", "username": "user01"}
+}`
+
+const gogsPullRequestPayload = `{
+  "action": "opened",
+  "number": 2,
+  "pull_request": {
+    "id": 20,
+    "user": {"username": "user03"},
+    "title": "PR01",
+    "state": "open",
+    "html_url": "http://git/owner01/repo01/pulls/2",
+    "head_branch": "feature",
+    "head_repo": {"id": 2, "name": "repo01", "owner": {"username": "user02"}},
+    "base_branch": "master",
+    "base_repo": {"id": 1, "name": "repo01", "owner": {"username": "owner01"}}
+  },
+  "repository": {"id": 1, "name": "repo01", "html_url": "http://git/owner01/repo01", "ssh_url": "ssh://git@git/owner01/repo01.git", "owner": {"username": "owner01"}},
+  "sender": {"login": "user02", "username": "user02"}
+}`
+
+func pullRequestPayload(action, state string, merged bool) string {
+	return `{
+  "action": "` + action + `",
+  "number": 2,
+  "pull_request": {
+    "id": 20,
+    "user": {"username": "user03"},
+    "title": "PR01",
+    "state": "` + state + `",
+    "html_url": "http://git/owner01/repo01/pulls/2",
+    "merged": ` + strconv.FormatBool(merged) + `,
+    "draft": true,
+    "labels": [{"name": "bug"}, {"name": "run-e2e"}],
+    "base": {"ref": "master", "repo": {"id": 1, "html_url": "http://git/owner01/repo01"}},
+    "head": {"ref": "feature", "sha": "` + testCommit + `", "repo": {"id": 2, "html_url": "http://git/user02/repo01"}}
+  },
+  "repository": {"id": 1, "name": "repo01", "html_url": "http://git/owner01/repo01", "ssh_url": "ssh://git@git/owner01/repo01.git", "owner": {"username": "owner01"}},
+  "sender": {"login": "user02", "username": "user02"}
+}`
+}
+
+func webhookRequest(headerPrefix, event, secret, payload string) *http.Request {
+	r := httptest.NewRequest("POST", "/webhooks", bytes.NewBufferString(payload))
+	r.Header.Set(headerPrefix+hookEvent, event)
+	if secret != "" {
+		h := hmac.New(sha256.New, []byte(secret))
+		h.Write([]byte(payload))
+		r.Header.Set(headerPrefix+signatureHeader, hex.EncodeToString(h.Sum(nil)))
+	}
+	return r
+}
+
+func TestParseWebhook(t *testing.T) {
+	srv, _ := newFakeServer(t, "7.0.0+gitea-1.22.0")
+
+	pushData := &types.WebhookData{
+		Event:       types.WebhookEventPush,
+		SSHURL:      "ssh://git@git/owner01/repo01.git",
+		CompareLink: "http://git/owner01/repo01/compare/a...b",
+		CommitLink:  "http://git/owner01/repo01/commit/" + testCommit,
+		CommitSHA:   testCommit,
+		Ref:         "refs/heads/master",
+		Message:     "commit message",
+		Sender:      "user01",
+		Author:      "user03",
+		Branch:      "master",
+		BranchLink:  "http://git/owner01/repo01/src/branch/master",
+		Repo:        types.WebhookDataRepo{WebURL: "http://git/owner01/repo01", Path: "owner01/repo01"},
+	}
+	gogsPushData := *pushData
+	gogsPushData.BranchLink = "http://git/owner01/repo01/src/master"
+
+	labeledPullRequestData := types.WebhookData{
+		Event:           types.WebhookEventPullRequest,
+		SSHURL:          "ssh://git@git/owner01/repo01.git",
+		CommitLink:      "http://git/owner01/repo01/commit/" + testCommit,
+		CommitSHA:       testCommit,
+		Ref:             "refs/pull/2/head",
+		Message:         "PR01",
+		Sender:          "user02",
+		Author:          "user03",
+		PullRequestID:   "20",
+		PullRequestLink: "http://git/owner01/repo01/pulls/2",
+
+		PullRequestAction:       types.WebhookPullRequestActionLabeled,
+		PullRequestLabels:       []string{"bug", "run-e2e"},
+		PullRequestTargetBranch: "master",
+		PullRequestDraft:        true,
+
+		Repo: types.WebhookDataRepo{WebURL: "http://git/owner01/repo01", Path: "owner01/repo01"},
+	}
+	mergedPullRequestData := labeledPullRequestData
+	mergedPullRequestData.PullRequestAction = types.WebhookPullRequestActionMerged
+
+	tests := []struct {
+		name          string
+		flavor        Flavor
+		req           *http.Request
+		webhookSecret string
+		out           *types.WebhookData
+		err           string
+	}{
+		{
+			name:          "gitea push without signature",
+			flavor:        FlavorGitea,
+			req:           webhookRequest("X-Gitea-", hookPush, "", pushPayload),
+			webhookSecret: "secret",
+			out:           pushData,
+		},
+		{
+			name:          "forgejo push",
+			flavor:        FlavorForgejo,
+			req:           webhookRequest("X-Forgejo-", hookPush, "secret", pushPayload),
+			webhookSecret: "secret",
+			out:           pushData,
+		},
+		{
+			name:          "forgejo push with gitea headers",
+			flavor:        FlavorForgejo,
+			req:           webhookRequest("X-Gitea-", hookPush, "secret", pushPayload),
+			webhookSecret: "secret",
+			out:           pushData,
+		},
+		{
+			name:          "forgejo push without signature",
+			flavor:        FlavorForgejo,
+			req:           webhookRequest("X-Forgejo-", hookPush, "", pushPayload),
+			webhookSecret: "secret",
+			err:           "wrong webhook signature",
+		},
+		{
+			name:          "gogs push",
+			flavor:        FlavorGogs,
+			req:           webhookRequest("X-Gogs-", hookPush, "secret", pushPayload),
+			webhookSecret: "secret",
+			out:           &gogsPushData,
+		},
+		{
+			name:          "gogs push with wrong signature",
+			flavor:        FlavorGogs,
+			req:           webhookRequest("X-Gogs-", hookPush, "wrong", pushPayload),
+			webhookSecret: "secret",
+			err:           "wrong webhook signature",
+		},
+		{
+			name:   "gogs pull request",
+			flavor: FlavorGogs,
+			req:    webhookRequest("X-Gogs-", hookPullRequest, "", gogsPullRequestPayload),
+			out: &types.WebhookData{
+				Event:           types.WebhookEventPullRequest,
+				SSHURL:          "ssh://git@git/owner01/repo01.git",
+				CommitLink:      "http://git/owner01/repo01/commit/" + testCommit,
+				CommitSHA:       testCommit,
+				Ref:             "refs/pull/2/head",
+				Message:         "PR01",
+				Sender:          "user02",
+				Author:          "user03",
+				PullRequestID:   "20",
+				PullRequestLink: "http://git/owner01/repo01/pulls/2",
+
+				PullRequestAction:       types.WebhookPullRequestActionOpened,
+				PullRequestTargetBranch: "master",
+
+				Repo: types.WebhookDataRepo{WebURL: "http://git/owner01/repo01", Path: "owner01/repo01"},
+			},
+		},
+		{
+			name:   "gitea pull request labeled",
+			flavor: FlavorGitea,
+			req:    webhookRequest("X-Gitea-", hookPullRequest, "", pullRequestPayload("label_updated", "open", false)),
+			out:    &labeledPullRequestData,
+		},
+		{
+			name:   "gitea pull request merged",
+			flavor: FlavorGitea,
+			req:    webhookRequest("X-Gitea-", hookPullRequest, "", pullRequestPayload("closed", "closed", true)),
+			out:    &mergedPullRequestData,
+		},
+		{
+			name:   "gitea pull request edited",
+			flavor: FlavorGitea,
+			req:    webhookRequest("X-Gitea-", hookPullRequest, "", pullRequestPayload("edited", "open", false)),
+			out:    nil,
+		},
+		{
+			name:   "gitea closed pull request labeled",
+			flavor: FlavorGitea,
+			req:    webhookRequest("X-Gitea-", hookPullRequest, "", pullRequestPayload("label_updated", "closed", false)),
+			out:    nil,
+		},
+		{
+			name:   "gitea headers on gogs",
+			flavor: FlavorGogs,
+			req:    webhookRequest("X-Gitea-", hookPush, "", pushPayload),
+			err:    `unknown webhook event type: ""`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(Opts{APIURL: srv.URL, Token: "token01", Flavor: tt.flavor})
+			assert.NilError(t, err)
+
+			whd, err := c.ParseWebhook(tt.req, tt.webhookSecret)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, whd, tt.out)
+		})
+	}
+}
@@ -28,64 +28,85 @@ import (
 
 	"github.com/sorintlab/errors"
 
+	gitsource "agola.io/agola/internal/gitsources"
 	"agola.io/agola/internal/services/types"
 )
 
 const (
-	hookEvent       = "X-Gitea-Event"
-	signatureHeader = "X-Gitea-Signature"
+	// webhook header names without the flavor prefix (X-Gitea-, X-Forgejo-, X-Gogs-)
+	hookEvent       = "Event"
+	signatureHeader = "Signature"
 
 	hookPush        = "push"
 	hookPullRequest = "pull_request"
 
 	prStateOpen = "open"
 
-	prActionOpen = "opened"
-	prActionSync = "synchronized"
+	prActionOpen        = "opened"
+	prActionSync        = "synchronized"
+	prActionReopen      = "reopened"
+	prActionClose       = "closed"
+	prActionLabelUpdate = "label_updated"
+	prActionLabelClear  = "label_cleared"
 )
 
+// VerifyWebhook verifies the webhook signature of the provided flavor using
+// the webhook secret.
+func VerifyWebhook(flavor Flavor, header http.Header, body []byte, secret string) error {
+	signature := flavor.hookHeader(header, signatureHeader)
+	// old versions of gitea doesn't provide a signature
+	if secret == "" || (signature == "" && flavor == FlavorGitea) {
+		return nil
+	}
+
+	ds, err := hex.DecodeString(signature)
+	if err != nil {
+		return errors.Errorf("wrong webhook signature")
+	}
+	h := hmac.New(sha256.New, []byte(secret))
+	if _, err := h.Write(body); err != nil {
+		return errors.Errorf("failed to calculate webhook signature")
+	}
+	cs := h.Sum(nil)
+	if !hmac.Equal(cs, ds) {
+		return errors.Errorf("wrong webhook signature")
+	}
+
+	return nil
+}
+
 func (c *Client) ParseWebhook(r *http.Request, secret string) (*types.WebhookData, error) {
 	data, err := io.ReadAll(io.LimitReader(r.Body, 10*1024*1024))
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	// verify signature
-	signature := r.Header.Get(signatureHeader)
-	// old versions of gitea doesn't provide a signature
-	if secret != "" && signature != "" {
-		ds, err := hex.DecodeString(signature)
-		if err != nil {
-			return nil, errors.Errorf("wrong webhook signature")
-		}
-		h := hmac.New(sha256.New, []byte(secret))
-		if _, err := h.Write(data); err != nil {
-			return nil, errors.Errorf("failed to calculate webhook signature")
-		}
-		cs := h.Sum(nil)
-		if !hmac.Equal(cs, ds) {
-			return nil, errors.Errorf("wrong webhook signature")
-		}
+	if err := VerifyWebhook(c.flavor, r.Header, data, secret); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
-	switch r.Header.Get(hookEvent) {
+	event := c.flavor.hookHeader(r.Header, hookEvent)
+	switch event {
 	case hookPush:
-		return parsePushHook(data)
+		return c.parsePushHook(data)
 	case hookPullRequest:
+		if c.flavor == FlavorGogs {
+			return c.parseGogsPullRequestHook(data)
+		}
 		return parsePullRequestHook(data)
 	default:
-		return nil, errors.Errorf("unknown webhook event type: %q", r.Header.Get(hookEvent))
+		return nil, errors.Errorf("unknown webhook event type: %q", event)
 	}
 }
 
-func parsePushHook(data []byte) (*types.WebhookData, error) {
+func (c *Client) parsePushHook(data []byte) (*types.WebhookData, error) {
 	push := new(pushHook)
 	err := json.Unmarshal(data, push)
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	return webhookDataFromPush(push)
+	return c.webhookDataFromPush(push)
 }
 
 func parsePullRequestHook(data []byte) (*types.WebhookData, error) {
@@ -95,19 +116,71 @@ func parsePullRequestHook(data []byte) (*types.WebhookData, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	// skip non open pull requests
-	if prhook.PullRequest.State != prStateOpen {
+	action := pullRequestAction(prhook.Action, prhook.PullRequest.State, prhook.PullRequest.Merged)
+	if action == "" {
 		return nil, nil
 	}
-	// only accept actions that have new commits
-	if prhook.Action != prActionOpen && prhook.Action != prActionSync {
+
+	return webhookDataFromPullRequest(prhook, action), nil
+}
+
+func (c *Client) parseGogsPullRequestHook(data []byte) (*types.WebhookData, error) {
+	prhook := new(gogsPullRequestHook)
+	err := json.Unmarshal(data, prhook)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	action := pullRequestAction(prhook.Action, prhook.PullRequest.State, prhook.PullRequest.Merged)
+	if action == "" {
 		return nil, nil
 	}
 
-	return webhookDataFromPullRequest(prhook), nil
+	// gogs doesn't report the pull request head commit sha so get it from
+	// the head branch. Skip it for closed pull requests since the head
+	// branch could have been already removed
+	var headSHA string
+	if !action.IsClosed() {
+		headRepo := prhook.PullRequest.HeadRepo
+		headSHA, err = c.gogsRefCommitSHA(headRepo.Owner.Username, headRepo.Name, branchRefPrefix+prhook.PullRequest.HeadBranch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get pull request head commit")
+		}
+	}
+
+	return webhookDataFromGogsPullRequest(prhook, action, headSHA), nil
+}
+
+// pullRequestAction returns the pull request action of a pull request hook or
+// an empty action if the hook must be ignored
+func pullRequestAction(action, state string, merged bool) types.WebhookPullRequestAction {
+	if action == prActionClose {
+		if merged {
+			return types.WebhookPullRequestActionMerged
+		}
+		return types.WebhookPullRequestActionClosed
+	}
+
+	// skip non open pull requests
+	if state != prStateOpen {
+		return ""
+	}
+
+	switch action {
+	case prActionOpen:
+		return types.WebhookPullRequestActionOpened
+	case prActionSync:
+		return types.WebhookPullRequestActionSynchronized
+	case prActionReopen:
+		return types.WebhookPullRequestActionReopened
+	case prActionLabelUpdate, prActionLabelClear:
+		return types.WebhookPullRequestActionLabeled
+	}
+
+	return ""
 }
 
-func webhookDataFromPush(hook *pushHook) (*types.WebhookData, error) {
+func (c *Client) webhookDataFromPush(hook *pushHook) (*types.WebhookData, error) {
 	sender := hook.Sender.Username
 	if sender == "" {
 		sender = hook.Sender.Login
@@ -132,14 +205,19 @@ func webhookDataFromPush(hook *pushHook) (*types.WebhookData, error) {
 	case strings.HasPrefix(hook.Ref, "refs/heads/"):
 		whd.Event = types.WebhookEventPush
 		whd.Branch = strings.TrimPrefix(hook.Ref, "refs/heads/")
-		whd.BranchLink = fmt.Sprintf("%s/src/branch/%s", hook.Repo.URL, whd.Branch)
+		whd.BranchLink = c.BranchLink(&gitsource.RepoInfo{HTMLURL: hook.Repo.URL}, whd.Branch)
 		if len(hook.Commits) > 0 {
 			whd.Message = hook.Commits[0].Message
 		}
+		for _, commit := range hook.Commits {
+			if commit.ID == hook.After {
+				whd.Author = commit.Author.Username
+			}
+		}
 	case strings.HasPrefix(hook.Ref, "refs/tags/"):
 		whd.Event = types.WebhookEventTag
 		whd.Tag = strings.TrimPrefix(hook.Ref, "refs/tags/")
-		whd.TagLink = fmt.Sprintf("%s/src/tag/%s", hook.Repo.URL, whd.Tag)
+		whd.TagLink = c.TagLink(&gitsource.RepoInfo{HTMLURL: hook.Repo.URL}, whd.Tag)
 		whd.Message = fmt.Sprintf("Tag %s", whd.Tag)
 	default:
 		// ignore received webhook since it doesn't have a ref we're interested in
@@ -150,7 +228,7 @@ func webhookDataFromPush(hook *pushHook) (*types.WebhookData, error) {
 }
 
 // helper function that extracts the Build data from a Gitea pull_request hook
-func webhookDataFromPullRequest(hook *pullRequestHook) *types.WebhookData {
+func webhookDataFromPullRequest(hook *pullRequestHook, action types.WebhookPullRequestAction) *types.WebhookData {
 	sender := hook.Sender.Username
 	if sender == "" {
 		sender = hook.Sender.Login
@@ -166,15 +244,60 @@ func webhookDataFromPullRequest(hook *pullRequestHook) *types.WebhookData {
 		CommitLink:      fmt.Sprintf("%s/commit/%s", hook.Repo.URL, hook.PullRequest.Head.Sha),
 		Message:         hook.PullRequest.Title,
 		Sender:          sender,
+		Author:          hook.PullRequest.User.Username,
 		PullRequestID:   strconv.FormatInt(hook.PullRequest.ID, 10),
 		PullRequestLink: hook.PullRequest.URL,
 		PRFromSameRepo:  prFromSameRepo,
 
+		PullRequestAction:       action,
+		PullRequestTargetBranch: hook.PullRequest.Base.Ref,
+		PullRequestDraft:        hook.PullRequest.Draft,
+
 		Repo: types.WebhookDataRepo{
 			Path:   path.Join(hook.Repo.Owner.Username, hook.Repo.Name),
 			WebURL: hook.Repo.URL,
 		},
 	}
+	for _, label := range hook.PullRequest.Labels {
+		whd.PullRequestLabels = append(whd.PullRequestLabels, label.Name)
+	}
+
+	return whd
+}
+
+// helper function that extracts the Build data from a Gogs pull_request hook
+func webhookDataFromGogsPullRequest(hook *gogsPullRequestHook, action types.WebhookPullRequestAction, headSHA string) *types.WebhookData {
+	sender := hook.Sender.Username
+	if sender == "" {
+		sender = hook.Sender.Login
+	}
+
+	prFromSameRepo := hook.PullRequest.BaseRepo.ID == hook.PullRequest.HeadRepo.ID
+
+	whd := &types.WebhookData{
+		Event:           types.WebhookEventPullRequest,
+		CommitSHA:       headSHA,
+		SSHURL:          hook.Repo.SSHURL,
+		Ref:             fmt.Sprintf("refs/pull/%d/head", hook.Number),
+		CommitLink:      fmt.Sprintf("%s/commit/%s", hook.Repo.URL, headSHA),
+		Message:         hook.PullRequest.Title,
+		Sender:          sender,
+		Author:          hook.PullRequest.User.Username,
+		PullRequestID:   strconv.FormatInt(hook.PullRequest.ID, 10),
+		PullRequestLink: hook.PullRequest.URL,
+		PRFromSameRepo:  prFromSameRepo,
+
+		PullRequestAction:       action,
+		PullRequestTargetBranch: hook.PullRequest.BaseBranch,
+
+		Repo: types.WebhookDataRepo{
+			Path:   path.Join(hook.Repo.Owner.Username, hook.Repo.Name),
+			WebURL: hook.Repo.URL,
+		},
+	}
+	for _, label := range hook.PullRequest.Labels {
+		whd.PullRequestLabels = append(whd.PullRequestLabels, label.Name)
+	}
 
 	return whd
 }
@@ -47,6 +47,11 @@ type pushHook struct {
 		ID      string `json:"id"`
 		Message string `json:"message"`
 		URL     string `json:"url"`
+		Author  struct {
+			Name     string `json:"name"`
+			Email    string `json:"email"`
+			Username string `json:"username"`
+		} `json:"author"`
 	} `json:"commits"`
 
 	Sender struct {
@@ -77,7 +82,11 @@ type pullRequestHook struct {
 		Mergeable bool   `json:"mergeable"`
 		Merged    bool   `json:"merged"`
 		MergeBase string `json:"merge_base"`
-		Base      struct {
+		Draft     bool   `json:"draft"`
+		Labels    []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		Base struct {
 			Label string `json:"label"`
 			Ref   string `json:"ref"`
 			Sha   string `json:"sha"`
@@ -142,3 +151,54 @@ type pullRequestHook struct {
 		Avatar   string `json:"avatar_url"`
 	} `json:"sender"`
 }
+
+type gogsRepository struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	FullName string `json:"full_name"`
+	URL      string `json:"html_url"`
+	Private  bool   `json:"private"`
+	SSHURL   string `json:"ssh_url"`
+	Owner    struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"full_name"`
+		Email    string `json:"email"`
+		Avatar   string `json:"avatar_url"`
+	} `json:"owner"`
+}
+
+// gogsPullRequestHook is the gogs pull_request hook payload. It differs from
+// the gitea one since it doesn't provide the head and base commits.
+type gogsPullRequestHook struct {
+	Action      string `json:"action"`
+	Number      int64  `json:"number"`
+	PullRequest struct {
+		ID   int64 `json:"id"`
+		User struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+		Title      string         `json:"title"`
+		Body       string         `json:"body"`
+		State      string         `json:"state"`
+		URL        string         `json:"html_url"`
+		HeadBranch string         `json:"head_branch"`
+		HeadRepo   gogsRepository `json:"head_repo"`
+		BaseBranch string         `json:"base_branch"`
+		BaseRepo   gogsRepository `json:"base_repo"`
+		Merged     bool           `json:"merged"`
+		Labels     []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"pull_request"`
+	Repo   gogsRepository `json:"repository"`
+	Sender struct {
+		ID       int64  `json:"id"`
+		Login    string `json:"login"`
+		Username string `json:"username"`
+		Name     string `json:"full_name"`
+		Email    string `json:"email"`
+		Avatar   string `json:"avatar_url"`
+	} `json:"sender"`
+}
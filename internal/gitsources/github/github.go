@@ -16,10 +16,8 @@ package github
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"path"
@@ -33,6 +31,7 @@ import (
 	"golang.org/x/oauth2"
 
 	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/httpclient"
 )
 
 var (
@@ -51,10 +50,6 @@ const (
 	GitHubSSHHostKey = "github.com ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQCj7ndNxQowgcQnjshcLrqPEiiphnt+VTTvDP6mHBL9j1aNUkY4Ue1gvwnGLVlOhGeYrnZaMgRK6+PKCUXaDbC7qtbW8gIkhL7aGCsOr/C56SJMy/BCZfxd1nWzAOxSDPgVsmerOBYfNqltV9/hWCqBywINIR+5dIg6JTJ72pcEpEjcYgXkE2YEFXV1JHnsKgbLWNlhScqb2UmyRkQyytRLtL+38TGxkxCflmO+5Z8CSSNY7GidjMIZ7Q4zMjA2n1nGrlTDkzwDCsw+wqFPGQA179cnfGWOWRVruj16z6XyvxvjJwbz0wQZ75XK5tKSb7FNyeIEs4TT4jk+S4dhPeAUC5y+bDYirYgM4GC7uEnztnZyaVWQ7B381AK4Qdrwt51ZqExKbQpTUNn+EjqoTwvqNj4kqx5QUCI0ThS/YkOxJCXmPUWZbhjpCg56i+2aB6CmK2JGhn57K5mj0MNdBXA4/WnwH6XoPWJzK5Nyu2zB3nAZp+S5hpQs+p1vN1/wsjk="
 )
 
-type httpOpts struct {
-	SkipVerify bool
-}
-
 type Opts struct {
 	APIURL     string
 	WebURL     string
@@ -106,23 +101,6 @@ func (t *TokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	return t.rt.RoundTrip(r)
 }
 
-func newHTTPTransport(opts httpOpts) *http.Transport {
-	// copied from net/http until it has a clone function: https://github.com/golang/go/issues/26013
-	return &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: opts.SkipVerify},
-	}
-}
-
 func getURLs(apiURL, webURL string) (string, string) {
 	// TODO(sgotti) improve detection of public github url (handle also trailing slash)
 	isPublicGithub := apiURL == GitHubAPIURL
@@ -148,7 +126,7 @@ func getURLs(apiURL, webURL string) (string, string) {
 }
 
 func New(opts Opts) (*Client, error) {
-	httpTransport := newHTTPTransport(httpOpts{SkipVerify: opts.SkipVerify})
+	httpTransport := httpclient.NewTransport(httpclient.Opts{SkipVerify: opts.SkipVerify})
 	httpClient := &http.Client{Transport: &TokenTransport{token: opts.Token, rt: httpTransport}}
 
 	apiURL, webURL := getURLs(opts.APIURL, opts.WebURL)
@@ -355,6 +333,130 @@ func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource
 	return delivered, errors.WithStack(err)
 }
 
+// maxCheckRunAnnotations is the maximum number of annotations accepted by a
+// single check run create or update request
+const maxCheckRunAnnotations = 50
+
+// UpdateCheckRun creates or updates a check run using the checks api.
+// NOTE: the checks api is available only to github apps, when using an
+// oauth2 or personal access token the request will be rejected and the check
+// run won't be delivered.
+func (c *Client) UpdateCheckRun(repopath string, checkRun *gitsource.CheckRun) (bool, error) {
+	owner, reponame, err := parseRepoPath(repopath)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	checkRuns, _, err := c.client.Checks.ListCheckRunsForRef(context.TODO(), owner, reponame, checkRun.CommitSHA, &github.ListCheckRunsOptions{
+		CheckName: github.Ptr(checkRun.Name),
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "error retrieving existing check runs")
+	}
+
+	output := &github.CheckRunOutput{
+		Title:   github.Ptr(checkRun.Title),
+		Summary: github.Ptr(checkRun.Summary),
+	}
+	if checkRun.Text != "" {
+		output.Text = github.Ptr(checkRun.Text)
+	}
+	for i, a := range checkRun.Annotations {
+		if i >= maxCheckRunAnnotations {
+			break
+		}
+		output.Annotations = append(output.Annotations, &github.CheckRunAnnotation{
+			Path: github.Ptr(a.Path),
+			// annotations are on the whole file, github requires the lines
+			StartLine:       github.Ptr(1),
+			EndLine:         github.Ptr(1),
+			AnnotationLevel: github.Ptr(string(a.Level)),
+			Title:           github.Ptr(a.Title),
+			Message:         github.Ptr(a.Message),
+		})
+	}
+
+	var conclusion *string
+	var completedAt *github.Timestamp
+	if checkRun.Status == gitsource.CheckRunStatusCompleted {
+		conclusion = github.Ptr(string(checkRun.Conclusion))
+		completedAt = &github.Timestamp{Time: time.Now()}
+	}
+
+	var resp *github.Response
+	if checkRuns.GetTotal() > 0 && len(checkRuns.CheckRuns) > 0 {
+		_, resp, err = c.client.Checks.UpdateCheckRun(context.TODO(), owner, reponame, checkRuns.CheckRuns[0].GetID(), github.UpdateCheckRunOptions{
+			Name:        checkRun.Name,
+			DetailsURL:  github.Ptr(checkRun.DetailsURL),
+			ExternalID:  github.Ptr(checkRun.ExternalID),
+			Status:      github.Ptr(string(checkRun.Status)),
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			Output:      output,
+		})
+	} else {
+		_, resp, err = c.client.Checks.CreateCheckRun(context.TODO(), owner, reponame, github.CreateCheckRunOptions{
+			Name:        checkRun.Name,
+			HeadSHA:     checkRun.CommitSHA,
+			DetailsURL:  github.Ptr(checkRun.DetailsURL),
+			ExternalID:  github.Ptr(checkRun.ExternalID),
+			Status:      github.Ptr(string(checkRun.Status)),
+			Conclusion:  conclusion,
+			CompletedAt: completedAt,
+			Output:      output,
+		})
+	}
+
+	var delivered bool
+	if resp != nil {
+		delivered = resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated
+	}
+	return delivered, errors.WithStack(err)
+}
+
+func (c *Client) UpdatePullRequestComment(repopath, prID, marker, body string) (bool, error) {
+	owner, reponame, err := parseRepoPath(repopath)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	number, err := strconv.Atoi(prID)
+	if err != nil {
+		return false, errors.Wrapf(err, "wrong pull request id %q", prID)
+	}
+
+	var commentID int64
+	opt := &github.IssueListCommentsOptions{}
+	for commentID == 0 {
+		comments, resp, err := c.client.Issues.ListComments(context.TODO(), owner, reponame, number, opt)
+		if err != nil {
+			return false, errors.Wrapf(err, "error retrieving pull request comments")
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.GetBody(), marker) {
+				commentID = comment.GetID()
+				break
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	var resp *github.Response
+	if commentID != 0 {
+		_, resp, err = c.client.Issues.EditComment(context.TODO(), owner, reponame, commentID, &github.IssueComment{Body: github.Ptr(body)})
+	} else {
+		_, resp, err = c.client.Issues.CreateComment(context.TODO(), owner, reponame, number, &github.IssueComment{Body: github.Ptr(body)})
+	}
+
+	var delivered bool
+	if resp != nil {
+		delivered = resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated
+	}
+	return delivered, errors.WithStack(err)
+}
+
 func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
 	remoteRepos := []*github.Repository{}
 
@@ -504,7 +606,7 @@ type Oauth2Client struct {
 }
 
 func NewOauth2Client(opts Oauth2Opts) (*Oauth2Client, error) {
-	httpTransport := newHTTPTransport(httpOpts{SkipVerify: opts.SkipVerify})
+	httpTransport := httpclient.NewTransport(httpclient.Opts{SkipVerify: opts.SkipVerify})
 	httpClient := &http.Client{Transport: httpTransport}
 
 	apiURL, webURL := getURLs(opts.APIURL, opts.WebURL)
@@ -30,10 +30,31 @@ import (
 const (
 	prStateOpen = "open"
 
-	prActionOpen = "opened"
-	prActionSync = "synchronize"
+	prActionOpen    = "opened"
+	prActionSync    = "synchronize"
+	prActionReopen  = "reopened"
+	prActionClose   = "closed"
+	prActionLabel   = "labeled"
+	prActionUnlabel = "unlabeled"
 )
 
+// VerifyWebhook verifies the webhook signature using the webhook secret.
+func VerifyWebhook(header http.Header, body []byte, secret string) error {
+	if secret == "" {
+		return nil
+	}
+
+	signature := header.Get(github.SHA256SignatureHeader)
+	if signature == "" {
+		signature = header.Get(github.SHA1SignatureHeader)
+	}
+	if err := github.ValidateSignature(signature, body, []byte(secret)); err != nil {
+		return errors.Wrapf(err, "wrong webhook signature")
+	}
+
+	return nil
+}
+
 func (c *Client) ParseWebhook(r *http.Request, secret string) (*types.WebhookData, error) {
 	payload, err := github.ValidatePayload(r, []byte(secret))
 	if err != nil {
@@ -81,6 +102,7 @@ func webhookDataFromPush(hook *github.PushEvent) (*types.WebhookData, error) {
 		whd.Branch = strings.TrimPrefix(*hook.Ref, "refs/heads/")
 		whd.BranchLink = fmt.Sprintf("%s/tree/%s", *hook.Repo.HTMLURL, whd.Branch)
 		whd.Message = *hook.HeadCommit.Message
+		whd.Author = hook.HeadCommit.GetAuthor().GetLogin()
 
 	case strings.HasPrefix(*hook.Ref, "refs/tags/"):
 		whd.Event = types.WebhookEventTag
@@ -93,6 +115,7 @@ func webhookDataFromPush(hook *github.PushEvent) (*types.WebhookData, error) {
 		if hook.HeadCommit.ID != nil {
 			whd.CommitSHA = *hook.HeadCommit.ID
 		}
+		whd.Author = hook.HeadCommit.GetAuthor().GetLogin()
 
 	default:
 		// ignore received webhook since it doesn't have a ref we're interested in
@@ -103,12 +126,8 @@ func webhookDataFromPush(hook *github.PushEvent) (*types.WebhookData, error) {
 }
 
 func webhookDataFromPullRequest(hook *github.PullRequestEvent) (*types.WebhookData, error) {
-	// skip non open pull requests
-	if *hook.PullRequest.State != prStateOpen {
-		return nil, nil
-	}
-	// only accept actions that have new commits
-	if *hook.Action != prActionOpen && *hook.Action != prActionSync {
+	action := pullRequestAction(hook.GetAction(), hook.PullRequest.GetState(), hook.PullRequest.GetMerged())
+	if action == "" {
 		return nil, nil
 	}
 
@@ -126,15 +145,52 @@ func webhookDataFromPullRequest(hook *github.PullRequestEvent) (*types.WebhookDa
 		CommitLink:      fmt.Sprintf("%s/commit/%s", *hook.Repo.HTMLURL, *hook.PullRequest.Head.SHA),
 		Message:         *hook.PullRequest.Title,
 		Sender:          *sender,
+		Author:          hook.PullRequest.GetUser().GetLogin(),
 		PullRequestID:   strconv.Itoa(*hook.PullRequest.Number),
 		PullRequestLink: *hook.PullRequest.HTMLURL,
 		PRFromSameRepo:  prFromSameRepo,
 
+		PullRequestAction:       action,
+		PullRequestTargetBranch: hook.PullRequest.Base.GetRef(),
+		PullRequestDraft:        hook.PullRequest.GetDraft(),
+
 		Repo: types.WebhookDataRepo{
 			Path:   path.Join(*hook.Repo.Owner.Login, *hook.Repo.Name),
 			WebURL: *hook.Repo.HTMLURL,
 		},
 	}
+	for _, label := range hook.PullRequest.Labels {
+		whd.PullRequestLabels = append(whd.PullRequestLabels, label.GetName())
+	}
 
 	return whd, nil
 }
+
+// pullRequestAction returns the pull request action of a pull request event or
+// an empty action if the event must be ignored
+func pullRequestAction(action, state string, merged bool) types.WebhookPullRequestAction {
+	if action == prActionClose {
+		if merged {
+			return types.WebhookPullRequestActionMerged
+		}
+		return types.WebhookPullRequestActionClosed
+	}
+
+	// skip non open pull requests
+	if state != prStateOpen {
+		return ""
+	}
+
+	switch action {
+	case prActionOpen:
+		return types.WebhookPullRequestActionOpened
+	case prActionSync:
+		return types.WebhookPullRequestActionSynchronized
+	case prActionReopen:
+		return types.WebhookPullRequestActionReopened
+	case prActionLabel, prActionUnlabel:
+		return types.WebhookPullRequestActionLabeled
+	}
+
+	return ""
+}
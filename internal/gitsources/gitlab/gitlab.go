@@ -16,21 +16,19 @@ package gitlab
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/base64"
 	"fmt"
-	"net"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/sorintlab/errors"
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 	"golang.org/x/oauth2"
 
 	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/httpclient"
 )
 
 var (
@@ -42,10 +40,6 @@ var (
 	pullRequestRefFmt   = "refs/merge-requests/%s/head"
 )
 
-type httpOpts struct {
-	SkipVerify bool
-}
-
 type Opts struct {
 	APIURL     string
 	SkipVerify bool
@@ -73,27 +67,8 @@ func fromCommitStatus(status gitsource.CommitStatus) gitlab.BuildStateValue {
 	}
 }
 
-func newHTTPClient(opts httpOpts) *http.Client {
-	// copied from net/http until it has a clone function: https://github.com/golang/go/issues/26013
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   30 * time.Second,
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: opts.SkipVerify},
-	}
-
-	return &http.Client{Transport: transport}
-}
-
 func New(opts Opts) (*Client, error) {
-	httpClient := newHTTPClient(httpOpts{SkipVerify: opts.SkipVerify})
+	httpClient := httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify})
 
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: opts.Token},
@@ -244,6 +219,45 @@ func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource
 	return delivered, errors.WithStack(err)
 }
 
+func (c *Client) UpdatePullRequestComment(repopath, prID, marker, body string) (bool, error) {
+	mrID, err := strconv.Atoi(prID)
+	if err != nil {
+		return false, errors.Wrapf(err, "wrong merge request id %q", prID)
+	}
+
+	var noteID int
+	opts := &gitlab.ListMergeRequestNotesOptions{}
+	for noteID == 0 {
+		notes, resp, err := c.client.Notes.ListMergeRequestNotes(repopath, mrID, opts)
+		if err != nil {
+			return false, errors.Wrapf(err, "error retrieving merge request notes")
+		}
+		for _, note := range notes {
+			if !note.System && strings.Contains(note.Body, marker) {
+				noteID = note.ID
+				break
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	var resp *gitlab.Response
+	if noteID != 0 {
+		_, resp, err = c.client.Notes.UpdateMergeRequestNote(repopath, mrID, noteID, &gitlab.UpdateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
+	} else {
+		_, resp, err = c.client.Notes.CreateMergeRequestNote(repopath, mrID, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(body)})
+	}
+
+	var delivered bool
+	if resp != nil {
+		delivered = resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated
+	}
+	return delivered, errors.WithStack(err)
+}
+
 func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
 	// get only repos with permission greater or equal to maintainer
 	opts := &gitlab.ListProjectsOptions{MinAccessLevel: gitlab.Ptr(gitlab.MaintainerPermissions)}
@@ -376,7 +390,7 @@ type Oauth2Client struct {
 }
 
 func NewOauth2Client(opts Oauth2Opts) (*Oauth2Client, error) {
-	httpClient := newHTTPClient(httpOpts{SkipVerify: opts.SkipVerify})
+	httpClient := httpclient.New(httpclient.Opts{SkipVerify: opts.SkipVerify})
 
 	return &Oauth2Client{
 		httpClient:     httpClient,
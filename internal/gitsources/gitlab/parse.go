@@ -15,6 +15,7 @@
 package gitlab
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -34,21 +35,37 @@ const (
 	hookPush        = "Push Hook"
 	hookTagPush     = "Tag Push Hook"
 	hookPullRequest = "Merge Request Hook"
+
+	prStateOpen = "opened"
+
+	prActionOpen   = "open"
+	prActionReopen = "reopen"
+	prActionUpdate = "update"
+	prActionClose  = "close"
+	prActionMerge  = "merge"
 )
 
+// VerifyWebhook verifies the webhook token (gitlab doesn't sign the payload
+// but just returns the provided secret).
+func VerifyWebhook(header http.Header, secret string) error {
+	if secret == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(header.Get(tokenHeader)), []byte(secret)) != 1 {
+		return errors.Errorf("wrong webhook token")
+	}
+
+	return nil
+}
+
 func (c *Client) ParseWebhook(r *http.Request, secret string) (*types.WebhookData, error) {
 	data, err := io.ReadAll(io.LimitReader(r.Body, 10*1024*1024))
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 
-	// verify token (gitlab doesn't sign the payload but just returns the provided
-	// secret)
-	if secret != "" {
-		token := r.Header.Get(tokenHeader)
-		if token != secret {
-			return nil, errors.Errorf("wrong webhook token")
-		}
+	if err := VerifyWebhook(r.Header, secret); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	switch r.Header.Get(hookEvent) {
@@ -85,10 +102,46 @@ func parsePullRequestHook(data []byte) (*types.WebhookData, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	// TODO(sgotti) skip non open pull requests
-	// TODO(sgotti) only accept actions that have new commits
+	action := pullRequestAction(prhook)
+	if action == "" {
+		return nil, nil
+	}
+
+	return webhookDataFromPullRequest(prhook, action), nil
+}
+
+// pullRequestAction returns the pull request action of a merge request hook or
+// an empty action if the hook must be ignored
+func pullRequestAction(hook *pullRequestHook) types.WebhookPullRequestAction {
+	switch hook.ObjectAttributes.Action {
+	case prActionClose:
+		return types.WebhookPullRequestActionClosed
+	case prActionMerge:
+		return types.WebhookPullRequestActionMerged
+	}
+
+	// skip non open merge requests
+	if hook.ObjectAttributes.State != prStateOpen {
+		return ""
+	}
+
+	switch hook.ObjectAttributes.Action {
+	case prActionOpen:
+		return types.WebhookPullRequestActionOpened
+	case prActionReopen:
+		return types.WebhookPullRequestActionReopened
+	case prActionUpdate:
+		// an update has new commits when oldrev is set, otherwise only accept
+		// labels changes
+		if hook.ObjectAttributes.Oldrev != "" {
+			return types.WebhookPullRequestActionSynchronized
+		}
+		if hook.Changes.Labels != nil {
+			return types.WebhookPullRequestActionLabeled
+		}
+	}
 
-	return webhookDataFromPullRequest(prhook), nil
+	return ""
 }
 
 func webhookDataFromPush(hook *pushHook) (*types.WebhookData, error) {
@@ -133,7 +186,7 @@ func webhookDataFromPush(hook *pushHook) (*types.WebhookData, error) {
 }
 
 // helper function that extracts the Build data from a Gitea pull_request hook
-func webhookDataFromPullRequest(hook *pullRequestHook) *types.WebhookData {
+func webhookDataFromPullRequest(hook *pullRequestHook, action types.WebhookPullRequestAction) *types.WebhookData {
 	// TODO(sgotti) Use PR opener username or last commit user name?
 	sender := hook.User.Name
 	if sender == "" {
@@ -153,10 +206,18 @@ func webhookDataFromPullRequest(hook *pullRequestHook) *types.WebhookData {
 		PullRequestLink: hook.ObjectAttributes.URL,
 		PRFromSameRepo:  prFromSameRepo,
 
+		PullRequestAction:       action,
+		PullRequestTargetBranch: hook.ObjectAttributes.TargetBranch,
+		PullRequestDraft:        hook.ObjectAttributes.Draft || hook.ObjectAttributes.WorkInProgress,
+
 		Repo: types.WebhookDataRepo{
 			Path:   hook.Project.PathWithNamespace,
 			WebURL: hook.Project.WebURL,
 		},
 	}
+	for _, label := range hook.Labels {
+		whd.PullRequestLabels = append(whd.PullRequestLabels, label.Title)
+	}
+
 	return whd
 }
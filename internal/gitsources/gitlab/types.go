@@ -147,13 +147,21 @@ type pullRequestHook struct {
 				Email string `json:"email"`
 			} `json:"author"`
 		} `json:"last_commit"`
-		WorkInProgress bool `json:"work_in_progress"`
-		TotalTimeSpent int  `json:"total_time_spent"`
+		WorkInProgress bool   `json:"work_in_progress"`
+		Draft          bool   `json:"draft"`
+		TotalTimeSpent int    `json:"total_time_spent"`
+		Action         string `json:"action"`
+		Oldrev         string `json:"oldrev"`
 	} `json:"object_attributes"`
+	Labels  []label `json:"labels"`
 	Changes struct {
 		TotalTimeSpent struct {
 			Current int `json:"current"`
 		} `json:"total_time_spent"`
+		Labels *struct {
+			Previous []label `json:"previous"`
+			Current  []label `json:"current"`
+		} `json:"labels"`
 	} `json:"changes"`
 	Repository struct {
 		Name        string `json:"name"`
@@ -162,3 +170,8 @@ type pullRequestHook struct {
 		Homepage    string `json:"homepage"`
 	} `json:"repository"`
 }
+
+type label struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
@@ -0,0 +1,355 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gitmirror implements a git source for plain git repositories
+// without an API (cgit, gitolite, gerrit mirrors etc...). All the operations
+// are done using the git command line against the repository url.
+package gitmirror
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/types"
+	"agola.io/agola/internal/util"
+)
+
+var (
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+	// peeledRefSuffix is the suffix of the ls-remote entries reporting the
+	// commit pointed by an annotated tag
+	peeledRefSuffix = "^{}"
+
+	// sshConnectTimeout is the max time to wait for the ssh connection to the
+	// git server
+	sshConnectTimeout = 30
+	// sshServerAliveInterval is the interval of the ssh keepalive messages.
+	// The connection is closed after sshServerAliveCountMax unanswered
+	// messages
+	sshServerAliveInterval = 15
+	sshServerAliveCountMax = 4
+
+	// httpLowSpeedLimit and httpLowSpeedTime make git abort an http transfer
+	// slower than httpLowSpeedLimit bytes per second for httpLowSpeedTime
+	// seconds
+	httpLowSpeedLimit = 1000
+	httpLowSpeedTime  = 60
+)
+
+type Opts struct {
+	// URL is the git repository url. Both ssh (also in scp like syntax) and
+	// http(s) urls are supported.
+	URL string
+	// SSHPrivKey is the PEM encoded private key used to access ssh urls
+	SSHPrivKey string
+	// SSHHostKey contains the known_hosts lines of the git server
+	SSHHostKey          string
+	SkipSSHHostKeyCheck bool
+}
+
+// Client is a git source for a single plain git repository. The repopath
+// arguments of the GitSource methods are ignored.
+// It uses a temporary local bare repository to fetch the commits needed to
+// read files and commit information, so it must be closed when not needed
+// anymore.
+// The GitSource methods don't accept a context, so they run the git commands
+// with the context provided to New.
+type Client struct {
+	ctx context.Context
+	url string
+	dir string
+	git *util.Git
+
+	m sync.Mutex
+	// refs are the repository refs by commit sha reported by the last
+	// ListRefs. They're used to fetch the ref containing a commit since many
+	// git servers don't allow fetching unadvertised commits
+	refs map[string]string
+}
+
+func New(ctx context.Context, opts Opts) (*Client, error) {
+	if err := util.ValidateGitRemoteURL(opts.URL); err != nil {
+		return nil, errors.Wrapf(err, "invalid git repository url")
+	}
+	u, err := util.ParseGitURL(opts.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse git repository url %q", opts.URL)
+	}
+
+	dir, err := os.MkdirTemp("", "agola-gitmirror")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	c := &Client{
+		ctx:  ctx,
+		url:  opts.URL,
+		dir:  dir,
+		refs: map[string]string{},
+	}
+
+	env := []string{
+		// never ask for credentials
+		"GIT_TERMINAL_PROMPT=0",
+		fmt.Sprintf("GIT_HTTP_LOW_SPEED_LIMIT=%d", httpLowSpeedLimit),
+		fmt.Sprintf("GIT_HTTP_LOW_SPEED_TIME=%d", httpLowSpeedTime),
+	}
+	if u.Scheme == "ssh" {
+		sshCommand, err := c.setupSSH(opts)
+		if err != nil {
+			_ = os.RemoveAll(dir)
+			return nil, errors.WithStack(err)
+		}
+		env = append(env, "GIT_SSH_COMMAND="+sshCommand)
+	}
+
+	c.git = &util.Git{GitDir: filepath.Join(dir, "repo.git"), Env: env}
+
+	if _, err := c.git.Output(ctx, nil, "init", "--bare", "--quiet", c.git.GitDir); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, errors.Wrapf(err, "failed to init local repository")
+	}
+
+	return c, nil
+}
+
+// setupSSH writes the ssh private key and known hosts files and returns the
+// ssh command git should use
+func (c *Client) setupSSH(opts Opts) (string, error) {
+	keyPath := filepath.Join(c.dir, "id")
+	if err := os.WriteFile(keyPath, []byte(opts.SSHPrivKey), 0600); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	args := []string{"ssh", "-i", keyPath, "-o", "IdentitiesOnly=yes", "-o", "BatchMode=yes",
+		"-o", fmt.Sprintf("ConnectTimeout=%d", sshConnectTimeout),
+		"-o", fmt.Sprintf("ServerAliveInterval=%d", sshServerAliveInterval),
+		"-o", fmt.Sprintf("ServerAliveCountMax=%d", sshServerAliveCountMax),
+	}
+	if opts.SkipSSHHostKeyCheck {
+		args = append(args, "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null")
+	} else {
+		knownHostsPath := filepath.Join(c.dir, "known_hosts")
+		if err := os.WriteFile(knownHostsPath, []byte(opts.SSHHostKey+"\n"), 0600); err != nil {
+			return "", errors.WithStack(err)
+		}
+		args = append(args, "-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile="+knownHostsPath)
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+// Close removes the temporary local repository
+func (c *Client) Close() error {
+	return errors.WithStack(os.RemoveAll(c.dir))
+}
+
+// ListRefs returns the repository branches and tags with the commit sha they
+// point to. For annotated tags the tagged commit sha is returned.
+func (c *Client) ListRefs(ctx context.Context) (map[string]string, error) {
+	lines, err := c.git.OutputLines(ctx, nil, "ls-remote", "--heads", "--tags", "--", c.url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list remote refs")
+	}
+
+	refs := map[string]string{}
+	peeled := map[string]string{}
+	for _, line := range lines {
+		sha, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(ref, peeledRefSuffix) {
+			peeled[strings.TrimSuffix(ref, peeledRefSuffix)] = sha
+			continue
+		}
+		refs[ref] = sha
+	}
+	for ref, sha := range peeled {
+		refs[ref] = sha
+	}
+
+	c.m.Lock()
+	for ref, sha := range refs {
+		c.refs[sha] = ref
+	}
+	c.m.Unlock()
+
+	return refs, nil
+}
+
+// fetchCommit fetches the provided commit in the local repository if not
+// already available
+func (c *Client) fetchCommit(ctx context.Context, commitSHA string) error {
+	if _, err := c.git.Output(ctx, nil, "cat-file", "-e", commitSHA+"^{commit}"); err == nil {
+		return nil
+	}
+
+	c.m.Lock()
+	ref, ok := c.refs[commitSHA]
+	c.m.Unlock()
+
+	if ok {
+		if _, err := c.git.Output(ctx, nil, "fetch", "--quiet", "--depth", "1", "--", c.url, ref); err == nil {
+			if _, err := c.git.Output(ctx, nil, "cat-file", "-e", commitSHA+"^{commit}"); err == nil {
+				return nil
+			}
+		}
+	}
+
+	// fetch the commit directly, this requires the git server to allow
+	// fetching unadvertised objects
+	if _, err := c.git.Output(ctx, nil, "fetch", "--quiet", "--depth", "1", "--", c.url, commitSHA); err != nil {
+		return errors.Wrapf(err, "failed to fetch commit %q", commitSHA)
+	}
+
+	return nil
+}
+
+func (c *Client) GetRepoInfo(repopath string) (*gitsource.RepoInfo, error) {
+	repoInfo := &gitsource.RepoInfo{Path: repopath}
+
+	u, err := util.ParseGitURL(c.url)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if u.Scheme == "ssh" {
+		repoInfo.SSHCloneURL = c.url
+	} else {
+		repoInfo.HTTPCloneURL = c.url
+	}
+
+	return repoInfo, nil
+}
+
+func (c *Client) GetFile(repopath, commit, file string) ([]byte, error) {
+	if err := c.fetchCommit(c.ctx, commit); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	data, err := c.git.Output(c.ctx, nil, "show", fmt.Sprintf("%s:%s", commit, file))
+	return data, errors.WithStack(err)
+}
+
+func (c *Client) CreateDeployKey(repopath, title, pubKey string, readonly bool) error {
+	return nil
+}
+
+func (c *Client) DeleteDeployKey(repopath, title string) error {
+	return nil
+}
+
+func (c *Client) UpdateDeployKey(repopath, title, pubKey string, readonly bool) error {
+	return nil
+}
+
+func (c *Client) CreateRepoWebhook(repopath, url, secret string) error {
+	return nil
+}
+
+func (c *Client) ParseWebhook(r *http.Request, secret string) (*types.WebhookData, error) {
+	return nil, nil
+}
+
+func (c *Client) DeleteRepoWebhook(repopath, u string) error {
+	return nil
+}
+
+func (c *Client) CreateCommitStatus(repopath, commitSHA string, status gitsource.CommitStatus, targetURL, description, context string) (bool, error) {
+	return false, nil
+}
+
+func (c *Client) ListUserRepos() ([]*gitsource.RepoInfo, error) {
+	return nil, nil
+}
+
+func (c *Client) GetRef(repopath, ref string) (*gitsource.Ref, error) {
+	refs, err := c.ListRefs(c.ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sha, ok := refs[ref]
+	if !ok {
+		return nil, errors.Errorf("ref %q doesn't exist", ref)
+	}
+
+	return &gitsource.Ref{Ref: ref, CommitSHA: sha}, nil
+}
+
+func (c *Client) RefType(ref string) (gitsource.RefType, string, error) {
+	if strings.HasPrefix(ref, branchRefPrefix) {
+		return gitsource.RefTypeBranch, strings.TrimPrefix(ref, branchRefPrefix), nil
+	}
+
+	if strings.HasPrefix(ref, tagRefPrefix) {
+		return gitsource.RefTypeTag, strings.TrimPrefix(ref, tagRefPrefix), nil
+	}
+
+	return -1, "", errors.Errorf("unsupported ref: %s", ref)
+}
+
+func (c *Client) GetCommit(repopath, commitSHA string) (*gitsource.Commit, error) {
+	// only full commit shas are supported since we cannot resolve an
+	// abbreviated sha on the remote
+	if err := c.fetchCommit(c.ctx, commitSHA); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := c.git.Output(c.ctx, nil, "log", "-1", "--format=%H%n%B", commitSHA)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	sha, message, _ := strings.Cut(string(out), "\n")
+
+	return &gitsource.Commit{SHA: sha, Message: strings.TrimSpace(message)}, nil
+}
+
+func (c *Client) BranchRef(branch string) string {
+	return branchRefPrefix + branch
+}
+
+func (c *Client) TagRef(tag string) string {
+	return tagRefPrefix + tag
+}
+
+func (c *Client) PullRequestRef(prID string) string {
+	return ""
+}
+
+func (c *Client) CommitLink(repoInfo *gitsource.RepoInfo, commitSHA string) string {
+	return ""
+}
+
+func (c *Client) BranchLink(repoInfo *gitsource.RepoInfo, branch string) string {
+	return ""
+}
+
+func (c *Client) TagLink(repoInfo *gitsource.RepoInfo, tag string) string {
+	return ""
+}
+
+func (c *Client) PullRequestLink(repoInfo *gitsource.RepoInfo, prID string) string {
+	return ""
+}
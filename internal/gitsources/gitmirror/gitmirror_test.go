@@ -0,0 +1,169 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitmirror
+
+import (
+	"context"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	gitsource "agola.io/agola/internal/gitsources"
+)
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=user01", "GIT_AUTHOR_EMAIL=user01@example.com",
+		"GIT_COMMITTER_NAME=user01", "GIT_COMMITTER_EMAIL=user01@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	assert.NilError(t, err, "git %s: %s", strings.Join(args, " "), out)
+
+	return strings.TrimSpace(string(out))
+}
+
+// newTestRepo creates a git repository with a master branch, a feature
+// branch and an annotated tag on master. It returns the repository http url
+// served by git http-backend.
+func newTestRepo(t *testing.T) (string, string, string) {
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not available")
+	}
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "repo01")
+	assert.NilError(t, os.MkdirAll(dir, 0755))
+	git(t, dir, "init", "--quiet", "--initial-branch", "master")
+
+	assert.NilError(t, os.MkdirAll(filepath.Join(dir, ".agola"), 0755))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".agola", "config.yml"), []byte("version: v0\n"), 0644))
+	git(t, dir, "add", ".")
+	git(t, dir, "commit", "--quiet", "-m", "first commit")
+	masterSHA := git(t, dir, "rev-parse", "HEAD")
+	git(t, dir, "tag", "-a", "v1.0.0", "-m", "release v1.0.0")
+
+	git(t, dir, "checkout", "--quiet", "-b", "feature")
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, ".agola", "config.yml"), []byte("version: v1\n"), 0644))
+	git(t, dir, "commit", "--quiet", "-am", "feature commit\n\ncommit body")
+	featureSHA := git(t, dir, "rev-parse", "HEAD")
+
+	srv := httptest.NewServer(&cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env:  []string{"GIT_PROJECT_ROOT=" + root, "GIT_HTTP_EXPORT_ALL=1"},
+	})
+	t.Cleanup(srv.Close)
+
+	return srv.URL + "/repo01/.git", masterSHA, featureSHA
+}
+
+func TestListRefs(t *testing.T) {
+	u, masterSHA, featureSHA := newTestRepo(t)
+
+	c, err := New(context.Background(), Opts{URL: u})
+	assert.NilError(t, err)
+	defer c.Close()
+
+	refs, err := c.ListRefs(context.Background())
+	assert.NilError(t, err)
+
+	// the annotated tag reports the tagged commit
+	assert.DeepEqual(t, refs, map[string]string{
+		"refs/heads/master":  masterSHA,
+		"refs/heads/feature": featureSHA,
+		"refs/tags/v1.0.0":   masterSHA,
+	})
+
+	ref, err := c.GetRef("", "refs/heads/feature")
+	assert.NilError(t, err)
+	assert.Equal(t, ref.CommitSHA, featureSHA)
+
+	_, err = c.GetRef("", "refs/heads/notexistent")
+	assert.ErrorContains(t, err, "doesn't exist")
+}
+
+func TestListRefsCanceled(t *testing.T) {
+	u, _, _ := newTestRepo(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c, err := New(ctx, Opts{URL: u})
+	assert.NilError(t, err)
+	defer c.Close()
+
+	cancel()
+
+	_, err = c.ListRefs(ctx)
+	assert.Assert(t, err != nil)
+
+	// the GitSource methods use the context provided to New
+	_, err = c.GetRef("", "refs/heads/master")
+	assert.Assert(t, err != nil)
+}
+
+func TestGetFileAndCommit(t *testing.T) {
+	u, masterSHA, featureSHA := newTestRepo(t)
+
+	c, err := New(context.Background(), Opts{URL: u})
+	assert.NilError(t, err)
+	defer c.Close()
+
+	_, err = c.ListRefs(context.Background())
+	assert.NilError(t, err)
+
+	data, err := c.GetFile("", masterSHA, ".agola/config.yml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "version: v0\n")
+
+	data, err = c.GetFile("", featureSHA, ".agola/config.yml")
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), "version: v1\n")
+
+	_, err = c.GetFile("", featureSHA, ".agola/config.jsonnet")
+	assert.Assert(t, err != nil)
+
+	commit, err := c.GetCommit("", featureSHA)
+	assert.NilError(t, err)
+	assert.Equal(t, commit.SHA, featureSHA)
+	assert.Equal(t, commit.Message, "feature commit\n\ncommit body")
+}
+
+func TestRefType(t *testing.T) {
+	c := &Client{}
+
+	refType, name, err := c.RefType(c.BranchRef("release/1.0"))
+	assert.NilError(t, err)
+	assert.Equal(t, refType, gitsource.RefTypeBranch)
+	assert.Equal(t, name, "release/1.0")
+
+	refType, name, err = c.RefType(c.TagRef("v1.0.0"))
+	assert.NilError(t, err)
+	assert.Equal(t, refType, gitsource.RefTypeTag)
+	assert.Equal(t, name, "v1.0.0")
+
+	_, _, err = c.RefType("refs/changes/01/1/1")
+	assert.Assert(t, err != nil)
+}
@@ -15,6 +15,7 @@
 package gitsource
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/sorintlab/errors"
@@ -32,8 +33,73 @@ const (
 	CommitStatusFailed  CommitStatus = "failed"
 )
 
+type CheckRunStatus string
+
+const (
+	CheckRunStatusQueued     CheckRunStatus = "queued"
+	CheckRunStatusInProgress CheckRunStatus = "in_progress"
+	CheckRunStatusCompleted  CheckRunStatus = "completed"
+)
+
+type CheckRunConclusion string
+
+const (
+	CheckRunConclusionSuccess   CheckRunConclusion = "success"
+	CheckRunConclusionFailure   CheckRunConclusion = "failure"
+	CheckRunConclusionCancelled CheckRunConclusion = "cancelled"
+	CheckRunConclusionTimedOut  CheckRunConclusion = "timed_out"
+)
+
+type CheckRunAnnotationLevel string
+
+const (
+	CheckRunAnnotationLevelNotice  CheckRunAnnotationLevel = "notice"
+	CheckRunAnnotationLevelWarning CheckRunAnnotationLevel = "warning"
+	CheckRunAnnotationLevelFailure CheckRunAnnotationLevel = "failure"
+)
+
+// ReviewVote is the verification vote of a pull request review
+type ReviewVote int
+
+const (
+	ReviewVoteReject  ReviewVote = -1
+	ReviewVoteNone    ReviewVote = 0
+	ReviewVoteApprove ReviewVote = 1
+)
+
 var ErrUnauthorized = errors.New("unauthorized")
 
+// APIError is an error response of a git source api.
+type APIError struct {
+	StatusCode int
+	Err        error
+}
+
+func NewAPIError(statusCode int, err error) error {
+	return &APIError{StatusCode: statusCode, Err: err}
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// IsTemporaryError reports whether the error is caused by a network error or
+// by a git source api server error, so the failed operation could succeed if
+// retried.
+func IsTemporaryError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode/100 == 5 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 type GitSource interface {
 	GetRepoInfo(repopath string) (*RepoInfo, error)
 	GetFile(repopath, commit, file string) ([]byte, error)
@@ -74,6 +140,34 @@ type Oauth2Source interface {
 	UserSource
 }
 
+// CheckRunSource is a git source that can report the run results as check
+// runs with a summary and annotations
+type CheckRunSource interface {
+	// UpdateCheckRun creates the check run or updates the existing one with the
+	// same name on the check run commit. It returns false if the check run
+	// wasn't delivered.
+	UpdateCheckRun(repopath string, checkRun *CheckRun) (bool, error)
+}
+
+// PullRequestCommentSource is a git source that can report the run results in
+// a pull request comment
+type PullRequestCommentSource interface {
+	// UpdatePullRequestComment creates the pull request comment or updates the
+	// existing one containing the provided marker. The body must contain the
+	// marker to be found by the next updates. It returns false if the comment
+	// wasn't delivered.
+	UpdatePullRequestComment(repopath, prID, marker, body string) (bool, error)
+}
+
+// ReviewSource is a git source that doesn't support commit statuses and
+// reports the run results as pull request reviews with a verification vote
+type ReviewSource interface {
+	// CreateReview creates a review of the pull request with the provided
+	// verification vote and message. It returns false if the review wasn't
+	// delivered.
+	CreateReview(repopath, prID string, vote ReviewVote, message string) (bool, error)
+}
+
 type Oauth2Client interface {
 	// GetOauth2AuthorizationURL return the authorization request URL to the
 	// authorization server
@@ -116,3 +210,28 @@ type Commit struct {
 	SHA     string
 	Message string
 }
+
+type CheckRun struct {
+	// Name is the check run name, a check run with the same name on the same
+	// commit is updated
+	Name       string
+	CommitSHA  string
+	ExternalID string
+	DetailsURL string
+	Status     CheckRunStatus
+	// Conclusion is set only when the status is completed
+	Conclusion CheckRunConclusion
+
+	Title       string
+	Summary     string
+	Text        string
+	Annotations []*CheckRunAnnotation
+}
+
+// CheckRunAnnotation is a check run annotation on a repository file
+type CheckRunAnnotation struct {
+	Path    string
+	Level   CheckRunAnnotationLevel
+	Title   string
+	Message string
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitsource
+
+import (
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/sorintlab/errors"
+	"gotest.tools/v3/assert"
+)
+
+func TestIsTemporaryError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		out  bool
+	}{
+		{
+			name: "test server error",
+			err:  errors.WithStack(NewAPIError(http.StatusBadGateway, errors.New("api error"))),
+			out:  true,
+		},
+		{
+			name: "test rate limited",
+			err:  errors.Wrapf(NewAPIError(http.StatusTooManyRequests, errors.New("api error")), "failed to get commit"),
+			out:  true,
+		},
+		{
+			name: "test not found",
+			err:  errors.Wrapf(NewAPIError(http.StatusNotFound, errors.New("api error")), "failed to get commit"),
+			out:  false,
+		},
+		{
+			name: "test network error",
+			err:  errors.WithStack(&url.Error{Op: "Get", URL: "http://example.com", Err: syscall.ECONNREFUSED}),
+			out:  true,
+		},
+		{
+			name: "test wrong signature",
+			err:  errors.Errorf("wrong webhook signature"),
+			out:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, IsTemporaryError(tt.err), tt.out)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+)
+
+const (
+	// maxCacheEntries is the maximum number of cached responses
+	maxCacheEntries = 1000
+	// maxCachedBodySize is the maximum size of a cached response body, bigger
+	// responses aren't cached
+	maxCachedBodySize = 1024 * 1024
+)
+
+// cacheEntry is a cached response with its validators
+type cacheEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	// resp is the http/1.1 wire representation of the response
+	resp []byte
+}
+
+// cache is a lru cache of the http responses
+type cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	entries    map[string]*list.Element
+}
+
+func newCache(maxEntries int) *cache {
+	return &cache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		entries:    map[string]*list.Element{},
+	}
+}
+
+func (c *cache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(e)
+
+	return e.Value.(*cacheEntry), true
+}
+
+func (c *cache) add(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[entry.key]; ok {
+		e.Value = entry
+		c.ll.MoveToFront(e)
+		return
+	}
+
+	c.entries[entry.key] = c.ll.PushFront(entry)
+	if c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *cache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.ll.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// cacheTransport caches the GET responses providing an ETag or a
+// Last-Modified header and revalidates them using conditional requests. Not
+// modified responses usually aren't counted in the git sources rate limits.
+type cacheTransport struct {
+	cache *cache
+	rt    http.RoundTripper
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// only plain GET requests are cached, requests with their own validators
+	// or ranges are passed through
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		//nolint:wrapcheck
+		return t.rt.RoundTrip(req)
+	}
+
+	// the cache key includes the request credentials and the Accept header
+	// since the responses depend on them
+	key := authKey(req) + " " + req.URL.String() + " " + req.Header.Get("Accept")
+
+	entry, cached := t.cache.get(key)
+	if cached {
+		req = req.Clone(req.Context())
+		if entry.etag != "" {
+			req.Header.Set("If-None-Match", entry.etag)
+		}
+		if entry.lastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		cachedResp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(entry.resp)), req)
+		if err != nil {
+			// return the not modified response on a broken cache entry
+			t.cache.remove(key)
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		cachedResponsesTotal.WithLabelValues(req.URL.Host).Inc()
+		return cachedResp, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		if cached {
+			t.cache.remove(key)
+		}
+		return resp, nil
+	}
+
+	// read the body up to the max cached size, bigger responses are returned
+	// without caching them
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		//nolint:wrapcheck
+		return nil, err
+	}
+	if len(body) > maxCachedBodySize {
+		resp.Body = &multiReadCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		//nolint:wrapcheck
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.cache.add(&cacheEntry{
+		key:          key,
+		etag:         etag,
+		lastModified: lastModified,
+		resp:         dump,
+	})
+
+	return resp, nil
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpclient provides the http client shared by the git sources
+// clients. It honors the git sources api rate limits, backing off when they
+// are exceeded, and caches the GET responses revalidating them using
+// conditional requests.
+//
+// The rate limits and the cache are shared by all the clients created by the
+// package since the git source clients are usually created for every
+// operation.
+package httpclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type Opts struct {
+	SkipVerify bool
+}
+
+var (
+	defaultRateLimits = newRateLimits(zerolog.Nop())
+	defaultCache      = newCache(maxCacheEntries)
+)
+
+// SetLogger sets the logger used to report the rate limits warnings of all
+// the clients. Nothing is logged until it's set.
+func SetLogger(log zerolog.Logger) {
+	defaultRateLimits.setLogger(log)
+}
+
+// New returns a new http client using the transport returned by NewTransport
+func New(opts Opts) *http.Client {
+	return &http.Client{Transport: NewTransport(opts)}
+}
+
+// NewTransport returns a new http transport caching the GET responses and
+// honoring the git sources rate limits. The authentication headers must be set
+// before calling the transport since they are used to separate the cached
+// responses and the rate limits of different users.
+func NewTransport(opts Opts) http.RoundTripper {
+	// copied from net/http until it has a clone function: https://github.com/golang/go/issues/26013
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: opts.SkipVerify},
+	}
+
+	return &cacheTransport{
+		cache: defaultCache,
+		rt: &rateLimitTransport{
+			limits: defaultRateLimits,
+			rt:     transport,
+		},
+	}
+}
+
+// authKey returns the key identifying the request host and credentials
+func authKey(req *http.Request) string {
+	h := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\n" + req.Header.Get("Private-Token")))
+	return req.URL.Host + "/" + hex.EncodeToString(h[:8])
+}
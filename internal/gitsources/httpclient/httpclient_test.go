@@ -0,0 +1,275 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gotest.tools/v3/assert"
+)
+
+func get(t *testing.T, c *http.Client, u, token string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", u, nil)
+	assert.NilError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	resp, err := c.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestCache(t *testing.T) {
+	var mu sync.Mutex
+	var conditionalRequests int
+	content := "content01"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		etag := `"` + r.Header.Get("Authorization") + content + `"`
+		if r.Header.Get("If-None-Match") != "" {
+			conditionalRequests++
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = io.WriteString(w, r.Header.Get("Authorization")+content)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := New(Opts{})
+
+	status, body := get(t, c, srv.URL+"/file", "token01")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, "token token01content01")
+	assert.Equal(t, conditionalRequests, 0)
+
+	// the not modified response is served from the cache
+	status, body = get(t, c, srv.URL+"/file", "token01")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, "token token01content01")
+	assert.Equal(t, conditionalRequests, 1)
+
+	// the cache is shared between the clients but not between different credentials
+	status, body = get(t, New(Opts{}), srv.URL+"/file", "token02")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, "token token02content01")
+	assert.Equal(t, conditionalRequests, 1)
+
+	// a modified response replaces the cached one
+	mu.Lock()
+	content = "content02"
+	mu.Unlock()
+
+	status, body = get(t, c, srv.URL+"/file", "token01")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, "token token01content02")
+	assert.Equal(t, conditionalRequests, 2)
+
+	status, body = get(t, c, srv.URL+"/file", "token01")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, "token token01content02")
+	assert.Equal(t, conditionalRequests, 3)
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(2)
+
+	c.add(&cacheEntry{key: "key01"})
+	c.add(&cacheEntry{key: "key02"})
+	_, ok := c.get("key01")
+	assert.Assert(t, ok)
+
+	// key02 is the least recently used entry
+	c.add(&cacheEntry{key: "key03"})
+	_, ok = c.get("key02")
+	assert.Assert(t, !ok)
+	_, ok = c.get("key01")
+	assert.Assert(t, ok)
+	_, ok = c.get("key03")
+	assert.Assert(t, ok)
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         func(h http.Header)
+		status         int
+		rateLimited    int
+		expectedStatus int
+		expectedCalls  int
+	}{
+		{
+			name: "retry after",
+			header: func(h http.Header) {
+				h.Set("Retry-After", "0")
+			},
+			status:         http.StatusTooManyRequests,
+			rateLimited:    2,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+		},
+		{
+			name: "github rate limit exceeded",
+			header: func(h http.Header) {
+				h.Set("X-RateLimit-Limit", "5000")
+				h.Set("X-RateLimit-Remaining", "0")
+				h.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Unix(), 10))
+			},
+			status:         http.StatusForbidden,
+			rateLimited:    1,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			name: "forbidden",
+			header: func(h http.Header) {
+				h.Set("X-RateLimit-Limit", "5000")
+				h.Set("X-RateLimit-Remaining", "4000")
+			},
+			status:         http.StatusForbidden,
+			rateLimited:    1,
+			expectedStatus: http.StatusForbidden,
+			expectedCalls:  1,
+		},
+		{
+			name: "max retries",
+			header: func(h http.Header) {
+				h.Set("Retry-After", "0")
+			},
+			status:         http.StatusTooManyRequests,
+			rateLimited:    10,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  maxRateLimitRetries + 1,
+		},
+		{
+			name: "reset too far",
+			header: func(h http.Header) {
+				h.Set("Retry-After", "3600")
+			},
+			status:         http.StatusTooManyRequests,
+			rateLimited:    1,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= tt.rateLimited {
+					tt.header(w.Header())
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = io.WriteString(w, "ok")
+			}))
+			t.Cleanup(srv.Close)
+
+			status, _ := get(t, New(Opts{}), srv.URL, "token01")
+			assert.Equal(t, status, tt.expectedStatus)
+			assert.Equal(t, calls, tt.expectedCalls)
+		})
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	h := http.Header{}
+	_, ok := parseRateLimit(h)
+	assert.Assert(t, !ok)
+
+	// gitlab headers
+	h.Set("RateLimit-Limit", "600")
+	h.Set("RateLimit-Remaining", "10")
+	h.Set("RateLimit-Reset", "1700000000")
+	rl, ok := parseRateLimit(h)
+	assert.Assert(t, ok)
+	assert.Equal(t, *rl, rateLimit{limit: 600, remaining: 10, reset: time.Unix(1700000000, 0)})
+
+	now := time.Now()
+	h = http.Header{}
+	h.Set("Retry-After", now.Add(30*time.Second).UTC().Format(http.TimeFormat))
+	wait, ok := parseRetryAfter(h, now)
+	assert.Assert(t, ok)
+	assert.Assert(t, wait > 28*time.Second && wait <= 30*time.Second)
+}
+
+func TestRateLimitWait(t *testing.T) {
+	limits := newRateLimits(zerolog.Nop())
+	now := time.Now()
+
+	assert.Equal(t, limits.wait("key01", now), time.Duration(0))
+
+	limits.update("key01", "host01", &rateLimit{limit: 10, remaining: 0, reset: now.Add(10 * time.Second)}, now)
+	assert.Equal(t, limits.wait("key01", now), 10*time.Second)
+
+	// resets longer than the max wait don't block the requests
+	limits.update("key01", "host01", &rateLimit{limit: 10, remaining: 0, reset: now.Add(time.Hour)}, now)
+	assert.Equal(t, limits.wait("key01", now), time.Duration(0))
+
+	limits.update("key01", "host01", &rateLimit{limit: 10, remaining: 1, reset: now.Add(10 * time.Second)}, now)
+	assert.Equal(t, limits.wait("key01", now), time.Duration(0))
+}
+
+func TestRateLimitUpdate(t *testing.T) {
+	limits := newRateLimits(zerolog.Nop())
+	now := time.Now()
+
+	prev, remaining := limits.update("key01", "host01", &rateLimit{limit: 10, remaining: 5, reset: now.Add(time.Minute)}, now)
+	assert.Assert(t, prev == nil)
+	assert.Equal(t, remaining, 5)
+
+	// the host remaining requests are the lowest of its credentials
+	_, remaining = limits.update("key02", "host01", &rateLimit{limit: 10, remaining: 8, reset: now.Add(time.Minute)}, now)
+	assert.Equal(t, remaining, 5)
+	_, remaining = limits.update("key03", "host02", &rateLimit{limit: 10, remaining: 9}, now)
+	assert.Equal(t, remaining, 9)
+
+	prev, remaining = limits.update("key01", "host01", &rateLimit{limit: 10, remaining: 3, reset: now.Add(time.Minute)}, now)
+	assert.Equal(t, prev.remaining, 5)
+	assert.Equal(t, remaining, 3)
+
+	// the rate limits are removed after their reset or, without a reset,
+	// after their ttl
+	now = now.Add(2 * time.Minute)
+	prev, remaining = limits.update("key02", "host01", &rateLimit{limit: 10, remaining: 7, reset: now.Add(time.Minute)}, now)
+	assert.Assert(t, prev == nil)
+	assert.Equal(t, remaining, 7)
+	assert.Equal(t, len(limits.limits), 2)
+
+	now = now.Add(rateLimitTTL)
+	limits.update("key02", "host01", &rateLimit{limit: 10, remaining: 7}, now)
+	assert.Equal(t, len(limits.limits), 1)
+}
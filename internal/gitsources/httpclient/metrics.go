@@ -0,0 +1,49 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"agola.io/agola/internal/metrics"
+)
+
+const (
+	metricsSubsystem = "gitsource"
+)
+
+var (
+	rateLimitRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "rate_limit_remaining",
+		Help:      "Lowest remaining git source api requests in the current rate limit window between the credentials used with the host.",
+	}, []string{"host"})
+
+	rateLimitedRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "rate_limited_requests_total",
+		Help:      "Total number of git source api requests rejected by the rate limit.",
+	}, []string{"host"})
+
+	cachedResponsesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "cached_responses_total",
+		Help:      "Total number of git source api responses served from the cache after a not modified response.",
+	}, []string{"host"})
+)
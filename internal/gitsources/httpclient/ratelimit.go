@@ -0,0 +1,292 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+)
+
+const (
+	// maxRateLimitWait is the maximum time to wait for a rate limit reset
+	// before sending a request. When the wait is longer the request is sent
+	// (or the rate limited response returned) to not block the caller.
+	maxRateLimitWait = 1 * time.Minute
+	// maxRateLimitRetries is the maximum number of retries of a rate limited request
+	maxRateLimitRetries = 3
+	// minRateLimitBackoff is the backoff used when the rate limited response
+	// doesn't report when to retry. It's doubled at every retry.
+	minRateLimitBackoff = 1 * time.Second
+
+	// lowRateLimitRatio is the remaining requests ratio below which a warning is logged
+	lowRateLimitRatio = 0.1
+
+	// rateLimitTTL is how long a rate limit without a reset time is kept
+	rateLimitTTL = 1 * time.Hour
+)
+
+// rateLimit is a rate limit window reported by the git source
+type rateLimit struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// parseRateLimit parses the rate limit headers reported by github, gitea and
+// bitbucket server (X-RateLimit-*) and gitlab (RateLimit-*)
+func parseRateLimit(h http.Header) (*rateLimit, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remainingHeader := h.Get(prefix + "Remaining")
+		if remainingHeader == "" {
+			continue
+		}
+		remaining, err := strconv.Atoi(remainingHeader)
+		if err != nil {
+			return nil, false
+		}
+		rl := &rateLimit{remaining: remaining}
+		if limit, err := strconv.Atoi(h.Get(prefix + "Limit")); err == nil {
+			rl.limit = limit
+		}
+		if reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64); err == nil {
+			rl.reset = time.Unix(reset, 0)
+		}
+		return rl, true
+	}
+
+	return nil, false
+}
+
+// parseRetryAfter parses the Retry-After header in seconds or http date format
+func parseRetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now), true
+	}
+	return 0, false
+}
+
+// rateLimitEntry is the last rate limit reported for a host and credentials
+type rateLimitEntry struct {
+	*rateLimit
+	host    string
+	updated time.Time
+}
+
+// expired reports if the rate limit window is ended
+func (e *rateLimitEntry) expired(now time.Time) bool {
+	if e.reset.IsZero() {
+		return now.Sub(e.updated) > rateLimitTTL
+	}
+	return now.After(e.reset)
+}
+
+// rateLimits keeps the last rate limit reported for every host and credentials
+type rateLimits struct {
+	mu     sync.Mutex
+	log    zerolog.Logger
+	limits map[string]*rateLimitEntry
+}
+
+func newRateLimits(log zerolog.Logger) *rateLimits {
+	return &rateLimits{log: log, limits: map[string]*rateLimitEntry{}}
+}
+
+func (r *rateLimits) logger() zerolog.Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.log
+}
+
+func (r *rateLimits) setLogger(log zerolog.Logger) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.log = log
+}
+
+// update saves the reported rate limit, removing the expired ones. It returns
+// the previous rate limit and the lowest remaining requests of the host rate
+// limits.
+func (r *rateLimits) update(key, host string, rl *rateLimit, now time.Time) (*rateLimit, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var prev *rateLimit
+	if e, ok := r.limits[key]; ok && !e.expired(now) {
+		prev = e.rateLimit
+	}
+	r.limits[key] = &rateLimitEntry{rateLimit: rl, host: host, updated: now}
+
+	remaining := rl.remaining
+	for k, e := range r.limits {
+		if e.expired(now) {
+			delete(r.limits, k)
+			continue
+		}
+		if e.host == host {
+			remaining = min(remaining, e.remaining)
+		}
+	}
+
+	return prev, remaining
+}
+
+// wait returns the time to wait before sending a request when the rate limit
+// is exhausted
+func (r *rateLimits) wait(key string, now time.Time) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rl, ok := r.limits[key]
+	if !ok || rl.remaining > 0 || rl.reset.IsZero() {
+		return 0
+	}
+
+	wait := rl.reset.Sub(now)
+	if wait <= 0 || wait > maxRateLimitWait {
+		return 0
+	}
+	return wait
+}
+
+// rateLimitTransport waits for the rate limit reset when it's exhausted and
+// retries the rate limited requests with a backoff
+type rateLimitTransport struct {
+	limits *rateLimits
+	rt     http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := authKey(req)
+	host := req.URL.Host
+	log := t.limits.logger()
+
+	for attempt := 0; ; attempt++ {
+		if wait := t.limits.wait(key, time.Now()); wait > 0 {
+			log.Warn().Str("host", host).Msgf("git source rate limit exhausted, waiting %s for its reset", wait)
+			if err := sleep(req.Context(), wait); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+
+		resp, err := t.rt.RoundTrip(req)
+		if err != nil {
+			//nolint:wrapcheck
+			return nil, err
+		}
+
+		rl, hasRateLimit := parseRateLimit(resp.Header)
+		if hasRateLimit {
+			t.updateRateLimit(log, key, host, rl)
+		}
+
+		if !isRateLimited(resp, rl) {
+			return resp, nil
+		}
+		rateLimitedRequestsTotal.WithLabelValues(host).Inc()
+
+		if attempt >= maxRateLimitRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		wait := retryWait(resp, rl, attempt, time.Now())
+		if wait > maxRateLimitWait {
+			return resp, nil
+		}
+		log.Warn().Str("host", host).Msgf("git source request rate limited, retrying in %s", wait)
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func (t *rateLimitTransport) updateRateLimit(log zerolog.Logger, key, host string, rl *rateLimit) {
+	prev, hostRemaining := t.limits.update(key, host, rl, time.Now())
+
+	rateLimitRemaining.WithLabelValues(host).Set(float64(hostRemaining))
+
+	// log only when crossing the threshold to avoid logging at every request
+	threshold := int(float64(rl.limit) * lowRateLimitRatio)
+	if rl.limit > 0 && rl.remaining < threshold && (prev == nil || prev.remaining >= threshold || prev.reset != rl.reset) {
+		log.Warn().Str("host", host).Msgf("git source rate limit low: %d of %d requests remaining until %s", rl.remaining, rl.limit, rl.reset.Format(time.RFC3339))
+	}
+}
+
+// isRateLimited reports if the response was rejected by the rate limit.
+// github reports the rate limit errors also with a 403 status code.
+func isRateLimited(resp *http.Response, rl *rateLimit) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if _, ok := parseRetryAfter(resp.Header, time.Now()); ok {
+			return true
+		}
+		return rl != nil && rl.remaining == 0
+	default:
+		return false
+	}
+}
+
+// retryWait returns the time to wait before retrying a rate limited request
+func retryWait(resp *http.Response, rl *rateLimit, attempt int, now time.Time) time.Duration {
+	if wait, ok := parseRetryAfter(resp.Header, now); ok {
+		return max(wait, 0)
+	}
+	if rl != nil && rl.remaining == 0 && !rl.reset.IsZero() {
+		return max(rl.reset.Sub(now), 0)
+	}
+	return minRateLimitBackoff << attempt
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.WithStack(ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
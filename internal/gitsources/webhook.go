@@ -0,0 +1,120 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitsource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+
+	"github.com/sorintlab/errors"
+)
+
+const (
+	// WebhookTokenParam is the webhook url query parameter containing the
+	// project webhook secret for the git sources that don't sign the webhook
+	// payloads.
+	WebhookTokenParam = "webhooktoken"
+	// WebhookTokenHashHeader is the header where the gateway saves the hash of
+	// the webhook url token of a received webhook. Received webhooks headers
+	// with this name are always discarded so it can only be set by the
+	// gateway.
+	WebhookTokenHashHeader = "X-Agola-Webhook-Token-Hash"
+)
+
+// webhookDeliveryIDHeaders are the headers used by the supported git sources
+// to report the unique id of a webhook delivery. Git sources keep the same id
+// when redelivering a webhook.
+var webhookDeliveryIDHeaders = []string{
+	"X-GitHub-Delivery",
+	"X-Gitea-Delivery",
+	"X-Forgejo-Delivery",
+	"X-Gogs-Delivery",
+	"Idempotency-Key",
+	"X-Gitlab-Event-UUID",
+	"X-Request-Id",
+}
+
+// webhookEventHeaders are the headers used by the supported git sources to
+// report the webhook event type.
+var webhookEventHeaders = []string{
+	"X-GitHub-Event",
+	"X-Forgejo-Event",
+	"X-Gitea-Event",
+	"X-Gogs-Event",
+	"X-Gitlab-Event",
+	"X-Event-Key",
+}
+
+// WebhookDeliveryID returns the webhook delivery id reported by the git
+// source. If the git source doesn't report it, an id derived from the body sha256
+// is returned so redeliveries of the same payload get the same id.
+func WebhookDeliveryID(header http.Header, body []byte) string {
+	for _, h := range webhookDeliveryIDHeaders {
+		if v := header.Get(h); v != "" {
+			return v
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// WebhookEvent returns the webhook event type reported by the git source or
+// an empty string if unknown.
+func WebhookEvent(header http.Header) string {
+	for _, h := range webhookEventHeaders {
+		if v := header.Get(h); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// WebhookTokenHash returns the hash of a webhook url token.
+func WebhookTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// WebhookURLWithToken returns the webhook url with the provided token added
+// as a query parameter.
+func WebhookURLWithToken(u, token string) (string, error) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse webhook url %q", u)
+	}
+	q := pu.Query()
+	q.Set(WebhookTokenParam, token)
+	pu.RawQuery = q.Encode()
+
+	return pu.String(), nil
+}
+
+// WebhookURLWithoutToken returns the webhook url without the token query
+// parameter.
+func WebhookURLWithoutToken(u string) (string, error) {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse webhook url %q", u)
+	}
+	q := pu.Query()
+	q.Del(WebhookTokenParam)
+	pu.RawQuery = q.Encode()
+
+	return pu.String(), nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitsource
+
+import (
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWebhookDeliveryID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		out    string
+	}{
+		{
+			name:   "github delivery",
+			header: http.Header{"X-Github-Delivery": []string{"72d3162e-cc78-11e3-81ab-4c9367dc0958"}},
+			out:    "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+		},
+		{
+			name:   "gitea delivery",
+			header: http.Header{"X-Gitea-Delivery": []string{"f6266f16-1bf3-46a5-9ea4-602e06ead473"}},
+			out:    "f6266f16-1bf3-46a5-9ea4-602e06ead473",
+		},
+		{
+			name:   "gitlab idempotency key preferred over event uuid",
+			header: http.Header{"Idempotency-Key": []string{"key01"}, "X-Gitlab-Event-Uuid": []string{"uuid01"}},
+			out:    "key01",
+		},
+		{
+			name: "no delivery header",
+			body: []byte("body"),
+			out:  "sha256:230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			assert.Equal(t, WebhookDeliveryID(header, tt.body), tt.out)
+		})
+	}
+}
+
+func TestWebhookEvent(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Forgejo-Event", "push")
+	header.Set("X-Gitea-Event", "push")
+	assert.Equal(t, WebhookEvent(header), "push")
+	assert.Equal(t, WebhookEvent(http.Header{}), "")
+}
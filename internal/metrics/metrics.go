@@ -0,0 +1,116 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides the common prometheus metrics and http handlers
+// used by all the agola services.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sorintlab/errors"
+)
+
+const (
+	Namespace = "agola"
+
+	// MetricsPath is the path where the services expose their metrics
+	MetricsPath = "/metrics"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of http requests.",
+	}, []string{"service", "route", "method", "code"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of http requests in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "route", "method", "code"})
+)
+
+// Handler returns the http handler that exposes the metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// NewHTTPMiddleware returns a mux middleware that records the http requests
+// count and duration of the provided service. Requests are labeled with the
+// matched route path template to keep the labels cardinality bounded.
+func NewHTTPMiddleware(service string) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := "unknown"
+			if cr := mux.CurrentRoute(r); cr != nil {
+				if tpl, err := cr.GetPathTemplate(); err == nil {
+					route = tpl
+				}
+			}
+
+			start := time.Now()
+			sw := &statusResponseWriter{ResponseWriter: w, status: http.StatusOK}
+
+			h.ServeHTTP(sw, r)
+
+			code := strconv.Itoa(sw.status)
+			httpRequestsTotal.WithLabelValues(service, route, r.Method, code).Inc()
+			httpRequestDuration.WithLabelValues(service, route, r.Method, code).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// statusResponseWriter records the response status code.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+
+	return n, errors.WithStack(err)
+}
+
+// Flush implements http.Flusher since it's used by the streaming handlers.
+func (w *statusResponseWriter) Flush() {
+	if fl, ok := w.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+	}
+}
+
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
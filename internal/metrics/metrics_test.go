@@ -0,0 +1,57 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	apirouter := router.PathPrefix("/api").Subrouter()
+	apirouter.Use(NewHTTPMiddleware("test"))
+
+	apirouter.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}).Methods("POST")
+	apirouter.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("item"))
+	}).Methods("GET")
+
+	for _, path := range []string{"/api/items/1", "/api/items/2"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("POST", path, nil))
+		assert.Equal(t, rec.Code, http.StatusCreated)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/items/1", nil))
+	assert.Equal(t, rec.Code, http.StatusOK)
+
+	assert.Equal(t, testutil.ToFloat64(httpRequestsTotal.WithLabelValues("test", "/api/items/{id}", "POST", "201")), float64(2))
+	assert.Equal(t, testutil.ToFloat64(httpRequestsTotal.WithLabelValues("test", "/api/items/{id}", "GET", "200")), float64(1))
+
+	rec = httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", MetricsPath, nil))
+	assert.Equal(t, rec.Code, http.StatusOK)
+	assert.Assert(t, cmp.Contains(rec.Body.String(), `agola_http_requests_total{code="201",method="POST",route="/api/items/{id}",service="test"} 2`))
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstorage
+
+import (
+	"context"
+	"io"
+
+	"github.com/sorintlab/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("agola.io/agola/internal/objectstorage")
+
+// tracedObjStorage is an ObjStorage that creates a span for every object
+// storage operation.
+type tracedObjStorage struct {
+	ost         ObjStorage
+	storageType string
+}
+
+// NewTraced returns an ObjStorage that wraps the provided ObjStorage creating
+// a span for every operation.
+func NewTraced(ost ObjStorage, storageType string) ObjStorage {
+	return &tracedObjStorage{ost: ost, storageType: storageType}
+}
+
+func (t *tracedObjStorage) startSpan(ctx context.Context, op, p string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "objectstorage."+op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("objectstorage.type", t.storageType),
+		attribute.String("objectstorage.path", p),
+	))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil && !IsNotExist(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "operation failed")
+	}
+	span.End()
+}
+
+func (t *tracedObjStorage) Stat(ctx context.Context, p string) (*ObjectInfo, error) {
+	ctx, span := t.startSpan(ctx, "Stat", p)
+	oi, err := t.ost.Stat(ctx, p)
+	endSpan(span, err)
+
+	return oi, errors.WithStack(err)
+}
+
+func (t *tracedObjStorage) ReadObject(ctx context.Context, p string) (ReadSeekCloser, error) {
+	ctx, span := t.startSpan(ctx, "ReadObject", p)
+	rs, err := t.ost.ReadObject(ctx, p)
+	endSpan(span, err)
+
+	return rs, errors.WithStack(err)
+}
+
+func (t *tracedObjStorage) WriteObject(ctx context.Context, p string, data io.Reader, size int64, persist bool) error {
+	ctx, span := t.startSpan(ctx, "WriteObject", p)
+	span.SetAttributes(attribute.Int64("objectstorage.size", size))
+	err := t.ost.WriteObject(ctx, p, data, size, persist)
+	endSpan(span, err)
+
+	return errors.WithStack(err)
+}
+
+func (t *tracedObjStorage) DeleteObject(ctx context.Context, p string) error {
+	ctx, span := t.startSpan(ctx, "DeleteObject", p)
+	err := t.ost.DeleteObject(ctx, p)
+	endSpan(span, err)
+
+	return errors.WithStack(err)
+}
+
+// List isn't traced since the listing continues after the method returns.
+func (t *tracedObjStorage) List(ctx context.Context, prefix, startAfter string, recursive bool) <-chan ObjectInfo {
+	return t.ost.List(ctx, prefix, startAfter, recursive)
+}
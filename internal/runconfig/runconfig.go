@@ -16,6 +16,7 @@ package runconfig
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/sorintlab/errors"
@@ -73,7 +74,7 @@ func stepFromConfigStep(csi interface{}, variables map[string]string) interface{
 	switch cs := csi.(type) {
 	case *config.CloneStep:
 		// transform a "clone" step in a "run" step command
-		rs := &config.RunStep{}
+		rs := &rstypes.RunStep{}
 		rs.Type = "run"
 		rs.Name = "Clone repository and checkout code"
 		rs.Command = fmt.Sprintf(`
@@ -200,15 +201,34 @@ fi
 	}
 }
 
+func genApprovalPolicy(ap *config.ApprovalPolicy) *rstypes.ApprovalPolicy {
+	if ap == nil {
+		return nil
+	}
+
+	rap := &rstypes.ApprovalPolicy{
+		MinApprovers:       ap.MinApprovers,
+		AllowedUsers:       ap.AllowedUsers,
+		AllowedTeams:       ap.AllowedTeams,
+		AllowedRoles:       ap.AllowedRoles,
+		ForbidSelfApproval: ap.ForbidSelfApproval,
+	}
+	if ap.Expiry != nil {
+		rap.Expiry = ap.Expiry.Duration
+	}
+
+	return rap
+}
+
 // GenRunConfigTasks generates a run config tasks from a run in the config, expanding all the references to tasks
 // this functions assumes that the config is already checked for possible errors (i.e referenced task must exits)
-func GenRunConfigTasks(uuid util.UUIDGenerator, c *config.Config, runName string, variables map[string]string, refType itypes.RunRefType, branch, tag, ref string) map[string]*rstypes.RunConfigTask {
+func GenRunConfigTasks(uuid util.UUIDGenerator, c *config.Config, runName string, variables map[string]string, refType itypes.RunRefType, branch, tag, ref string, pullRequest *types.WhenPullRequest) map[string]*rstypes.RunConfigTask {
 	cr := c.Run(runName)
 
 	rcts := map[string]*rstypes.RunConfigTask{}
 
 	for _, ct := range cr.Tasks {
-		include := types.MatchWhen(ct.When.ToWhen(), refType, branch, tag, ref)
+		include := types.MatchWhen(ct.When.ToWhen(), refType, branch, tag, ref, pullRequest)
 
 		steps := make(rstypes.Steps, len(ct.Steps))
 		for i, cpts := range ct.Steps {
@@ -229,6 +249,7 @@ func GenRunConfigTasks(uuid util.UUIDGenerator, c *config.Config, runName string
 			IgnoreFailure:        ct.IgnoreFailure,
 			Skip:                 !include,
 			NeedsApproval:        ct.Approval,
+			ApprovalPolicy:       genApprovalPolicy(ct.ApprovalPolicy),
 			DockerRegistriesAuth: make(map[string]rstypes.DockerRegistryAuth),
 		}
 
@@ -326,6 +347,48 @@ func GenRunConfigTasks(uuid util.UUIDGenerator, c *config.Config, runName string
 	return rcts
 }
 
+// SetRunConfigTasksSecretValues sets, in every run config task, the provided
+// secret values used by the task (task, containers and steps environment and
+// docker registries auth) so they can be masked in the task logs.
+func SetRunConfigTasksSecretValues(rcts map[string]*rstypes.RunConfigTask, secretValues []string) {
+	for _, rct := range rcts {
+		values := map[string]struct{}{}
+		addEnvValues := func(env map[string]string) {
+			for _, v := range env {
+				values[v] = struct{}{}
+			}
+		}
+
+		addEnvValues(rct.Environment)
+		if rct.Runtime != nil {
+			for _, c := range rct.Runtime.Containers {
+				addEnvValues(c.Environment)
+			}
+		}
+		for _, s := range rct.Steps {
+			if rs, ok := s.(*rstypes.RunStep); ok {
+				addEnvValues(rs.Environment)
+			}
+		}
+		for _, auth := range rct.DockerRegistriesAuth {
+			values[auth.Username] = struct{}{}
+			values[auth.Password] = struct{}{}
+			values[auth.Auth] = struct{}{}
+		}
+
+		var taskSecretValues []string
+		for _, sv := range secretValues {
+			if sv == "" || slices.Contains(taskSecretValues, sv) {
+				continue
+			}
+			if _, ok := values[sv]; ok {
+				taskSecretValues = append(taskSecretValues, sv)
+			}
+		}
+		rct.SecretValues = taskSecretValues
+	}
+}
+
 func getRunConfigTaskByName(rcts map[string]*rstypes.RunConfigTask, name string) *rstypes.RunConfigTask {
 	for _, rct := range rcts {
 		if rct.Name == name {
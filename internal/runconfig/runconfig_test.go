@@ -1508,9 +1508,62 @@ func TestGenRunConfig(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			out := GenRunConfigTasks(uuid, tt.in, "run01", tt.variables, "", "", "", "")
+			out := GenRunConfigTasks(uuid, tt.in, "run01", tt.variables, "", "", "", "", nil)
 
 			assert.DeepEqual(t, tt.out, out)
 		})
 	}
 }
+
+func TestSetRunConfigTasksSecretValues(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           map[string]*rstypes.RunConfigTask
+		secretValues []string
+		out          map[string][]string
+	}{
+		{
+			name: "test secret values used by tasks",
+			in: map[string]*rstypes.RunConfigTask{
+				"task01": {
+					Environment: map[string]string{"ENV01": "secret01", "ENV02": "value02"},
+				},
+				"task02": {
+					Runtime: &rstypes.Runtime{
+						Containers: []*rstypes.Container{
+							{Environment: map[string]string{"ENV01": "secret02"}},
+						},
+					},
+					Steps: rstypes.Steps{
+						&rstypes.RunStep{Environment: map[string]string{"ENV01": "secret01"}},
+					},
+				},
+				"task03": {
+					DockerRegistriesAuth: map[string]rstypes.DockerRegistryAuth{
+						"registry01": {Username: "user01", Password: "secret03"},
+					},
+				},
+				"task04": {
+					Environment: map[string]string{"ENV01": "value01"},
+				},
+			},
+			secretValues: []string{"secret01", "secret02", "secret03", "secret01", ""},
+			out: map[string][]string{
+				"task01": {"secret01"},
+				"task02": {"secret01", "secret02"},
+				"task03": {"secret03"},
+				"task04": nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRunConfigTasksSecretValues(tt.in, tt.secretValues)
+
+			for id, rct := range tt.in {
+				assert.DeepEqual(t, tt.out[id], rct.SecretValues)
+			}
+		})
+	}
+}
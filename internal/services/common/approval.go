@@ -0,0 +1,129 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+const (
+	ApprovalsAnnotation = "approvals"
+)
+
+// RunTaskApproval is the approval of a run task by a user
+type RunTaskApproval struct {
+	UserID       string    `json:"user_id"`
+	UserName     string    `json:"user_name,omitempty"`
+	ApprovalTime time.Time `json:"approval_time"`
+}
+
+// IsExpired reports whether the approval is expired at the provided time
+// according to the approval policy.
+func (a *RunTaskApproval) IsExpired(policy *rstypes.ApprovalPolicy, now time.Time) bool {
+	if policy == nil || policy.Expiry == 0 {
+		return false
+	}
+	return a.ApprovalTime.Add(policy.Expiry).Before(now)
+}
+
+// GetRunTaskApprovals returns the approvals saved in the run task annotations.
+// Approvals saved only in the approvers annotation don't have a user name and
+// an approval time.
+func GetRunTaskApprovals(annotations map[string]string) ([]*RunTaskApproval, error) {
+	approvals := []*RunTaskApproval{}
+
+	if approvalsAnnotation, ok := annotations[ApprovalsAnnotation]; ok {
+		if err := json.Unmarshal([]byte(approvalsAnnotation), &approvals); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal run task approvals annotation")
+		}
+		return approvals, nil
+	}
+
+	if approversAnnotation, ok := annotations[ApproversAnnotation]; ok {
+		var approvers []string
+		if err := json.Unmarshal([]byte(approversAnnotation), &approvers); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal run task approvers annotation")
+		}
+		for _, approver := range approvers {
+			approvals = append(approvals, &RunTaskApproval{UserID: approver})
+		}
+	}
+
+	return approvals, nil
+}
+
+// SetRunTaskApprovals saves the approvals in the run task annotations. The
+// approvers annotation is kept updated with the approvers user ids.
+func SetRunTaskApprovals(annotations map[string]string, approvals []*RunTaskApproval) error {
+	approvers := make([]string, len(approvals))
+	for i, approval := range approvals {
+		approvers[i] = approval.UserID
+	}
+
+	approvalsj, err := json.Marshal(approvals)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal run task approvals annotation")
+	}
+	approversj, err := json.Marshal(approvers)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal run task approvers annotation")
+	}
+
+	annotations[ApprovalsAnnotation] = string(approvalsj)
+	annotations[ApproversAnnotation] = string(approversj)
+
+	return nil
+}
+
+// ValidRunTaskApprovals returns the approvals that are not expired at the
+// provided time.
+func ValidRunTaskApprovals(approvals []*RunTaskApproval, policy *rstypes.ApprovalPolicy, now time.Time) []*RunTaskApproval {
+	valid := []*RunTaskApproval{}
+	for _, approval := range approvals {
+		if approval.IsExpired(policy, now) {
+			continue
+		}
+		valid = append(valid, approval)
+	}
+
+	return valid
+}
+
+// IsRunSelfApprover reports whether a user approving a run task is the user
+// that triggered the run or, comparing the user remote source user names, the
+// webhook sender or the commit/pull request author of the run.
+func IsRunSelfApprover(userID, triggerUserID string, userRemoteUserNames, runRemoteUserNames []string) bool {
+	if triggerUserID != "" && triggerUserID == userID {
+		return true
+	}
+
+	for _, runRemoteUserName := range runRemoteUserNames {
+		if runRemoteUserName == "" {
+			continue
+		}
+		for _, userRemoteUserName := range userRemoteUserNames {
+			if userRemoteUserName == runRemoteUserName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"agola.io/agola/internal/testutil"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+func TestGetRunTaskApprovals(t *testing.T) {
+	approvalTime := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		out         []*RunTaskApproval
+	}{
+		{
+			name:        "test no approvals",
+			annotations: map[string]string{},
+			out:         []*RunTaskApproval{},
+		},
+		{
+			name:        "test approvers annotation only",
+			annotations: map[string]string{ApproversAnnotation: `["user01","user02"]`},
+			out:         []*RunTaskApproval{{UserID: "user01"}, {UserID: "user02"}},
+		},
+		{
+			name: "test approvals annotation",
+			annotations: map[string]string{
+				ApproversAnnotation: `["user01"]`,
+				ApprovalsAnnotation: `[{"user_id":"user01","user_name":"username01","approval_time":"2026-01-01T10:00:00Z"}]`,
+			},
+			out: []*RunTaskApproval{{UserID: "user01", UserName: "username01", ApprovalTime: approvalTime}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := GetRunTaskApprovals(tt.annotations)
+			testutil.NilError(t, err)
+
+			assert.DeepEqual(t, tt.out, out)
+		})
+	}
+}
+
+func TestSetRunTaskApprovals(t *testing.T) {
+	approvalTime := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	approvals := []*RunTaskApproval{
+		{UserID: "user01", UserName: "username01", ApprovalTime: approvalTime},
+		{UserID: "user02", UserName: "username02", ApprovalTime: approvalTime},
+	}
+
+	annotations := map[string]string{}
+	err := SetRunTaskApprovals(annotations, approvals)
+	testutil.NilError(t, err)
+
+	assert.Equal(t, annotations[ApproversAnnotation], `["user01","user02"]`)
+
+	out, err := GetRunTaskApprovals(annotations)
+	testutil.NilError(t, err)
+
+	assert.DeepEqual(t, approvals, out)
+}
+
+func TestValidRunTaskApprovals(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	approvals := []*RunTaskApproval{
+		{UserID: "user01", ApprovalTime: now.Add(-48 * time.Hour)},
+		{UserID: "user02", ApprovalTime: now.Add(-1 * time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		policy *rstypes.ApprovalPolicy
+		out    []*RunTaskApproval
+	}{
+		{
+			name:   "test no policy",
+			policy: nil,
+			out:    approvals,
+		},
+		{
+			name:   "test policy without expiry",
+			policy: &rstypes.ApprovalPolicy{MinApprovers: 2},
+			out:    approvals,
+		},
+		{
+			name:   "test policy with expiry",
+			policy: &rstypes.ApprovalPolicy{Expiry: 24 * time.Hour},
+			out:    []*RunTaskApproval{approvals[1]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := ValidRunTaskApprovals(approvals, tt.policy, now)
+
+			assert.DeepEqual(t, tt.out, out)
+		})
+	}
+}
+
+func TestIsRunSelfApprover(t *testing.T) {
+	tests := []struct {
+		name                string
+		triggerUserID       string
+		userRemoteUserNames []string
+		runRemoteUserNames  []string
+		out                 bool
+	}{
+		{
+			name: "test run without trigger user and remote users",
+			out:  false,
+		},
+		{
+			name:          "test user that triggered the run",
+			triggerUserID: "user01",
+			out:           true,
+		},
+		{
+			name:          "test run triggered by another user",
+			triggerUserID: "user02",
+			out:           false,
+		},
+		{
+			name:                "test webhook sender",
+			userRemoteUserNames: []string{"remoteuser01"},
+			runRemoteUserNames:  []string{"remoteuser01", "remoteuser02"},
+			out:                 true,
+		},
+		{
+			name:                "test commit author",
+			userRemoteUserNames: []string{"remoteuser02"},
+			runRemoteUserNames:  []string{"remoteuser01", "remoteuser02"},
+			out:                 true,
+		},
+		{
+			name:                "test commit author without webhook sender",
+			userRemoteUserNames: []string{"remoteuser02"},
+			runRemoteUserNames:  []string{"", "remoteuser02"},
+			out:                 true,
+		},
+		{
+			name:                "test empty remote user names",
+			userRemoteUserNames: []string{""},
+			runRemoteUserNames:  []string{"", ""},
+			out:                 false,
+		},
+		{
+			name:                "test other remote user",
+			userRemoteUserNames: []string{"remoteuser03"},
+			runRemoteUserNames:  []string{"remoteuser01", "remoteuser02"},
+			out:                 false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := IsRunSelfApprover("user01", tt.triggerUserID, tt.userRemoteUserNames, tt.runRemoteUserNames)
+
+			assert.Equal(t, tt.out, out)
+		})
+	}
+}
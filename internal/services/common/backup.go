@@ -0,0 +1,152 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/objectstorage"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg/manager"
+	"agola.io/agola/internal/util"
+)
+
+const (
+	backupsDir = "backups"
+
+	// backupIDFormat is the format of the backup id. It's the backup time and
+	// it's lexicographically sortable.
+	backupIDFormat = "20060102T150405.000000000Z"
+)
+
+type Backup struct {
+	ID   string
+	Time time.Time
+	Size int64
+}
+
+func backupPath(backupID string) string {
+	return path.Join(backupsDir, backupID)
+}
+
+func parseBackupID(backupID string) (time.Time, error) {
+	t, err := time.Parse(backupIDFormat, backupID)
+	if err != nil {
+		return time.Time{}, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("invalid backup id %q", backupID), serrors.InvalidBackupID())
+	}
+
+	return t, nil
+}
+
+// WriteBackup writes a backup of the objects of the provided kinds to the
+// object storage. The objects are exported in a single transaction, so the
+// backup is a consistent snapshot of the database taken without enabling the
+// maintenance mode. The export is written to a temporary file inside tmpDir
+// before being saved in the object storage.
+func WriteBackup(ctx context.Context, dbm *manager.DBManager, objectKinds []string, ost objectstorage.ObjStorage, tmpDir string) (*Backup, error) {
+	now := time.Now().UTC()
+	backupID := now.Format(backupIDFormat)
+
+	f, err := os.CreateTemp(tmpDir, "backup")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	if err := dbm.ExportToFile(ctx, objectKinds, f); err != nil {
+		return nil, errors.Wrap(err, "export db error")
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err := ost.WriteObject(ctx, backupPath(backupID), f, size, true); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &Backup{ID: backupID, Time: now, Size: size}, nil
+}
+
+// GetBackups returns the backups saved in the object storage ordered from the
+// oldest to the newest.
+func GetBackups(ctx context.Context, ost objectstorage.ObjStorage) ([]*Backup, error) {
+	backups := []*Backup{}
+	for object := range ost.List(ctx, backupsDir+"/", "", true) {
+		if object.Err != nil {
+			return nil, errors.WithStack(object.Err)
+		}
+
+		backupID := strings.TrimPrefix(object.Path, backupsDir+"/")
+		t, err := parseBackupID(backupID)
+		if err != nil {
+			// ignore unknown objects
+			continue
+		}
+
+		backups = append(backups, &Backup{ID: backupID, Time: t, Size: object.Size})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].ID < backups[j].ID })
+
+	return backups, nil
+}
+
+// ReadBackup returns the content of a backup.
+func ReadBackup(ctx context.Context, ost objectstorage.ObjStorage, backupID string) (io.ReadCloser, error) {
+	if _, err := parseBackupID(backupID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	r, err := ost.ReadObject(ctx, backupPath(backupID))
+	if err != nil {
+		if objectstorage.IsNotExist(err) {
+			return nil, util.NewAPIErrorWrap(util.ErrNotExist, err, util.WithAPIErrorMsgf("backup %q doesn't exist", backupID), serrors.BackupDoesNotExist())
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return r, nil
+}
+
+// DeleteOldBackups removes the backups exceeding the provided retention,
+// starting from the oldest one.
+func DeleteOldBackups(ctx context.Context, ost objectstorage.ObjStorage, retention int) error {
+	backups, err := GetBackups(ctx, ost)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for i := 0; i < len(backups)-retention; i++ {
+		if err := ost.DeleteObject(ctx, backupPath(backups[i].ID)); err != nil && !objectstorage.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
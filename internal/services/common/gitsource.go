@@ -15,20 +15,37 @@
 package common
 
 import (
+	"net/http"
+
 	"github.com/sorintlab/errors"
 
 	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/bitbucketserver"
+	"agola.io/agola/internal/gitsources/gerrit"
 	"agola.io/agola/internal/gitsources/gitea"
 	"agola.io/agola/internal/gitsources/github"
 	"agola.io/agola/internal/gitsources/gitlab"
 	cstypes "agola.io/agola/services/configstore/types"
 )
 
+// giteaFlavor returns the gitea compatible implementation of the remote source
+func giteaFlavor(rs *cstypes.RemoteSource) gitea.Flavor {
+	switch rs.Type {
+	case cstypes.RemoteSourceTypeForgejo:
+		return gitea.FlavorForgejo
+	case cstypes.RemoteSourceTypeGogs:
+		return gitea.FlavorGogs
+	default:
+		return gitea.FlavorGitea
+	}
+}
+
 func newGitea(rs *cstypes.RemoteSource, accessToken string) (*gitea.Client, error) {
 	c, err := gitea.New(gitea.Opts{
 		APIURL:     rs.APIURL,
 		SkipVerify: rs.SkipVerify,
 		Token:      accessToken,
+		Flavor:     giteaFlavor(rs),
 	})
 
 	return c, errors.WithStack(err)
@@ -40,6 +57,7 @@ func newGiteaWithBasicAuth(rs *cstypes.RemoteSource, username, password string)
 		SkipVerify: rs.SkipVerify,
 		UserName:   username,
 		Password:   password,
+		Flavor:     giteaFlavor(rs),
 	})
 
 	return c, errors.WithStack(err)
@@ -98,6 +116,51 @@ func newGithubOauth2Client(rs *cstypes.RemoteSource) (*github.Oauth2Client, erro
 	return c, errors.WithStack(err)
 }
 
+func newBitbucketServer(rs *cstypes.RemoteSource, accessToken string) (*bitbucketserver.Client, error) {
+	c, err := bitbucketserver.New(bitbucketserver.Opts{
+		APIURL:     rs.APIURL,
+		SkipVerify: rs.SkipVerify,
+		Token:      accessToken,
+	})
+
+	return c, errors.WithStack(err)
+}
+
+func newBitbucketServerWithBasicAuth(rs *cstypes.RemoteSource, username, password string) (*bitbucketserver.Client, error) {
+	c, err := bitbucketserver.NewWithBasicAuth(bitbucketserver.Opts{
+		APIURL:     rs.APIURL,
+		SkipVerify: rs.SkipVerify,
+		UserName:   username,
+		Password:   password,
+	})
+
+	return c, errors.WithStack(err)
+}
+
+func newBitbucketServerOauth2Client(rs *cstypes.RemoteSource) (*bitbucketserver.Oauth2Client, error) {
+	c, err := bitbucketserver.NewOauth2Client(bitbucketserver.Oauth2Opts{
+		APIURL:         rs.APIURL,
+		SkipVerify:     rs.SkipVerify,
+		Oauth2ClientID: rs.Oauth2ClientID,
+		Oauth2Secret:   rs.Oauth2ClientSecret,
+	})
+
+	return c, errors.WithStack(err)
+}
+
+// newGerrit creates a gerrit client. Gerrit uses the user name and http
+// password (saved as access token) for basic auth.
+func newGerrit(rs *cstypes.RemoteSource, username, password string) (*gerrit.Client, error) {
+	c, err := gerrit.New(gerrit.Opts{
+		APIURL:     rs.APIURL,
+		SkipVerify: rs.SkipVerify,
+		UserName:   username,
+		Password:   password,
+	})
+
+	return c, errors.WithStack(err)
+}
+
 func GetAccessToken(rs *cstypes.RemoteSource, userAccessToken, oauth2AccessToken string) (string, error) {
 	switch rs.AuthType {
 	case cstypes.RemoteSourceAuthTypePassword:
@@ -110,24 +173,29 @@ func GetAccessToken(rs *cstypes.RemoteSource, userAccessToken, oauth2AccessToken
 }
 
 func GetGitSource(rs *cstypes.RemoteSource, la *cstypes.LinkedAccount) (gitsource.GitSource, error) {
-	var accessToken string
+	var accessToken, remoteUserName string
 	if la != nil {
 		var err error
 		accessToken, err = GetAccessToken(rs, la.UserAccessToken, la.Oauth2AccessToken)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		remoteUserName = la.RemoteUserName
 	}
 
 	var gitSource gitsource.GitSource
 	var err error
 	switch rs.Type {
-	case cstypes.RemoteSourceTypeGitea:
+	case cstypes.RemoteSourceTypeGitea, cstypes.RemoteSourceTypeForgejo, cstypes.RemoteSourceTypeGogs:
 		gitSource, err = newGitea(rs, accessToken)
 	case cstypes.RemoteSourceTypeGitlab:
 		gitSource, err = newGitlab(rs, accessToken)
 	case cstypes.RemoteSourceTypeGithub:
 		gitSource, err = newGithub(rs, accessToken)
+	case cstypes.RemoteSourceTypeBitbucketServer:
+		gitSource, err = newBitbucketServer(rs, accessToken)
+	case cstypes.RemoteSourceTypeGerrit:
+		gitSource, err = newGerrit(rs, remoteUserName, accessToken)
 	default:
 		return nil, errors.Errorf("remote source %s isn't a valid git source", rs.Name)
 	}
@@ -135,7 +203,36 @@ func GetGitSource(rs *cstypes.RemoteSource, la *cstypes.LinkedAccount) (gitsourc
 	return gitSource, errors.WithStack(err)
 }
 
-func GetAccessTokenUserSource(rs *cstypes.RemoteSource, accessToken string) (gitsource.UserSource, error) {
+// VerifyWebhook verifies the signature or the token of a webhook received from
+// the remote source using the project webhook secret. It doesn't call the
+// remote source api.
+func VerifyWebhook(rs *cstypes.RemoteSource, header http.Header, body []byte, secret string) error {
+	var err error
+	switch rs.Type {
+	case cstypes.RemoteSourceTypeGitea, cstypes.RemoteSourceTypeForgejo, cstypes.RemoteSourceTypeGogs:
+		err = gitea.VerifyWebhook(giteaFlavor(rs), header, body, secret)
+	case cstypes.RemoteSourceTypeGitlab:
+		err = gitlab.VerifyWebhook(header, secret)
+	case cstypes.RemoteSourceTypeGithub:
+		err = github.VerifyWebhook(header, body, secret)
+	case cstypes.RemoteSourceTypeBitbucketServer:
+		err = bitbucketserver.VerifyWebhook(header, body, secret)
+	case cstypes.RemoteSourceTypeGerrit:
+		err = gerrit.VerifyWebhook(header, secret)
+	default:
+		return errors.Errorf("remote source %s isn't a valid git source", rs.Name)
+	}
+
+	return errors.WithStack(err)
+}
+
+func GetAccessTokenUserSource(rs *cstypes.RemoteSource, remoteUserName, accessToken string) (gitsource.UserSource, error) {
+	// gerrit access tokens are http passwords that require the user name
+	if rs.Type == cstypes.RemoteSourceTypeGerrit {
+		userSource, err := newGerrit(rs, remoteUserName, accessToken)
+		return userSource, errors.WithStack(err)
+	}
+
 	var userSource gitsource.UserSource
 	var err error
 	switch rs.AuthType {
@@ -167,12 +264,14 @@ func GetOauth2Source(rs *cstypes.RemoteSource, accessToken string) (gitsource.Oa
 	var oauth2Source gitsource.Oauth2Source
 	var err error
 	switch rs.Type {
-	case cstypes.RemoteSourceTypeGitea:
+	case cstypes.RemoteSourceTypeGitea, cstypes.RemoteSourceTypeForgejo, cstypes.RemoteSourceTypeGogs:
 		oauth2Source, err = newGitea(rs, accessToken)
 	case cstypes.RemoteSourceTypeGitlab:
 		oauth2Source, err = newGitlab(rs, accessToken)
 	case cstypes.RemoteSourceTypeGithub:
 		oauth2Source, err = newGithub(rs, accessToken)
+	case cstypes.RemoteSourceTypeBitbucketServer:
+		oauth2Source, err = newBitbucketServer(rs, accessToken)
 	default:
 		return nil, errors.Errorf("remote source %s isn't a valid oauth2 source", rs.Name)
 	}
@@ -184,8 +283,12 @@ func GetPasswordSource(rs *cstypes.RemoteSource, username, password string) (git
 	var passwordSource gitsource.PasswordSource
 	var err error
 	switch rs.Type {
-	case cstypes.RemoteSourceTypeGitea:
+	case cstypes.RemoteSourceTypeGitea, cstypes.RemoteSourceTypeForgejo, cstypes.RemoteSourceTypeGogs:
 		passwordSource, err = newGiteaWithBasicAuth(rs, username, password)
+	case cstypes.RemoteSourceTypeBitbucketServer:
+		passwordSource, err = newBitbucketServerWithBasicAuth(rs, username, password)
+	case cstypes.RemoteSourceTypeGerrit:
+		passwordSource, err = newGerrit(rs, username, password)
 	default:
 		return nil, errors.Errorf("remote source %s isn't a valid password source", rs.Name)
 	}
@@ -197,12 +300,14 @@ func GetOauth2Client(rs *cstypes.RemoteSource) (gitsource.Oauth2Client, error) {
 	var oauth2Client gitsource.Oauth2Client
 	var err error
 	switch rs.Type {
-	case cstypes.RemoteSourceTypeGitea:
+	case cstypes.RemoteSourceTypeGitea, cstypes.RemoteSourceTypeForgejo:
 		oauth2Client, err = newGiteaOauth2Client(rs)
 	case cstypes.RemoteSourceTypeGitlab:
 		oauth2Client, err = newGitlabOauth2Client(rs)
 	case cstypes.RemoteSourceTypeGithub:
 		oauth2Client, err = newGithubOauth2Client(rs)
+	case cstypes.RemoteSourceTypeBitbucketServer:
+		oauth2Client, err = newBitbucketServerOauth2Client(rs)
 	default:
 		return nil, errors.Errorf("remote source %s isn't a valid oauth2 source", rs.Name)
 	}
@@ -0,0 +1,120 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/testutil"
+	cstypes "agola.io/agola/services/configstore/types"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	secret := "secret01"
+	body := []byte(`{"ref": "refs/heads/master"}`)
+
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	signature := hex.EncodeToString(h.Sum(nil))
+
+	tests := []struct {
+		name   string
+		rsType cstypes.RemoteSourceType
+		header http.Header
+		err    bool
+	}{
+		{
+			name:   "test signed gitea webhook",
+			rsType: cstypes.RemoteSourceTypeGitea,
+			header: http.Header{"X-Gitea-Signature": []string{signature}},
+		},
+		{
+			name:   "test gitea webhook with wrong signature",
+			rsType: cstypes.RemoteSourceTypeGitea,
+			header: http.Header{"X-Gitea-Signature": []string{hex.EncodeToString([]byte("wrong"))}},
+			err:    true,
+		},
+		{
+			name:   "test unsigned forgejo webhook",
+			rsType: cstypes.RemoteSourceTypeForgejo,
+			header: http.Header{},
+			err:    true,
+		},
+		{
+			name:   "test signed github webhook",
+			rsType: cstypes.RemoteSourceTypeGithub,
+			header: http.Header{"X-Hub-Signature-256": []string{"sha256=" + signature}},
+		},
+		{
+			name:   "test unsigned github webhook",
+			rsType: cstypes.RemoteSourceTypeGithub,
+			header: http.Header{},
+			err:    true,
+		},
+		{
+			name:   "test signed bitbucket server webhook",
+			rsType: cstypes.RemoteSourceTypeBitbucketServer,
+			header: http.Header{"X-Hub-Signature": []string{"sha256=" + signature}},
+		},
+		{
+			name:   "test unsigned bitbucket server webhook",
+			rsType: cstypes.RemoteSourceTypeBitbucketServer,
+			header: http.Header{},
+			err:    true,
+		},
+		{
+			name:   "test gitlab webhook with token",
+			rsType: cstypes.RemoteSourceTypeGitlab,
+			header: http.Header{"X-Gitlab-Token": []string{secret}},
+		},
+		{
+			name:   "test gitlab webhook without token",
+			rsType: cstypes.RemoteSourceTypeGitlab,
+			header: http.Header{},
+			err:    true,
+		},
+		{
+			name:   "test gerrit webhook with token",
+			rsType: cstypes.RemoteSourceTypeGerrit,
+			header: http.Header{gitsource.WebhookTokenHashHeader: []string{gitsource.WebhookTokenHash(secret)}},
+		},
+		{
+			name:   "test gerrit webhook without token",
+			rsType: cstypes.RemoteSourceTypeGerrit,
+			header: http.Header{},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs := &cstypes.RemoteSource{Name: "rs01", Type: tt.rsType}
+
+			err := VerifyWebhook(rs, tt.header, body, secret)
+			if tt.err {
+				assert.Assert(t, err != nil)
+				return
+			}
+			testutil.NilError(t, err)
+		})
+	}
+}
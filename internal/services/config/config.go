@@ -47,6 +47,10 @@ type Config struct {
 	Configstore  Configstore  `yaml:"configstore"`
 	Gitserver    Gitserver    `yaml:"gitserver"`
 
+	// Tracing defines the OpenTelemetry tracing configuration of all the
+	// services
+	Tracing Tracing `yaml:"tracing"`
+
 	// Global urls to avoid repeating them for every service
 
 	// APIExposedURL is the gateway API exposed url i.e. https://myagola.example.com
@@ -107,6 +111,15 @@ type Gateway struct {
 	AdminToken string `yaml:"adminToken"`
 
 	OrganizationMemberAddingMode OrganizationMemberAddingMode `yaml:"organizationMemberAddingMode"`
+
+	// MirrorPollInterval is the interval between two polls of a mirror project
+	// repository
+	MirrorPollInterval time.Duration `yaml:"mirrorPollInterval"`
+
+	// MetricsListenAddress is the http listen address where the gateway
+	// metrics are exposed. It should not be publicly reachable. If empty the
+	// gateway metrics aren't exposed.
+	MetricsListenAddress string `yaml:"metricsListenAddress"`
 }
 
 type Scheduler struct {
@@ -140,6 +153,13 @@ type Notification struct {
 
 	RunWebhookExpireInterval   time.Duration `yaml:"runWebhookExpireInterval"`
 	CommitStatusExpireInterval time.Duration `yaml:"commitStatusExpireInterval"`
+
+	// CheckRuns enables reporting the run results also as check runs on the git
+	// sources supporting them
+	CheckRuns bool `yaml:"checkRuns"`
+	// PullRequestComments enables reporting the pull request runs results in a
+	// pull request comment on the git sources supporting them
+	PullRequestComments bool `yaml:"pullRequestComments"`
 }
 
 type Runservice struct {
@@ -160,6 +180,8 @@ type Runservice struct {
 	RunCacheExpireInterval     time.Duration `yaml:"runCacheExpireInterval"`
 	RunWorkspaceExpireInterval time.Duration `yaml:"runWorkspaceExpireInterval"`
 	RunLogExpireInterval       time.Duration `yaml:"runLogExpireInterval"`
+
+	Backup Backup `yaml:"backup"`
 }
 
 type Executor struct {
@@ -233,6 +255,27 @@ type Configstore struct {
 	APIToken string `yaml:"apiToken"`
 
 	ObjectStorage ObjectStorage `yaml:"objectStorage"`
+
+	// ReceivedWebhookExpireInterval is the time after which processed
+	// received webhooks are removed
+	ReceivedWebhookExpireInterval time.Duration `yaml:"receivedWebhookExpireInterval"`
+
+	Backup Backup `yaml:"backup"`
+}
+
+// Backup configures the periodic backups of a service database to the
+// service object storage.
+// It's available only for the configstore and the runservice. The
+// notification service database isn't backed up since it only contains the
+// state of the notifications deliveries, which is rebuilt from the run events
+// still available in the runservice.
+type Backup struct {
+	// Enabled enables the periodic backups
+	Enabled bool `yaml:"enabled"`
+	// Interval is the time between two backups
+	Interval time.Duration `yaml:"interval"`
+	// Retention is the number of backups to keep. Older backups are removed
+	Retention int `yaml:"retention"`
 }
 
 type Gitserver struct {
@@ -269,6 +312,22 @@ type Web struct {
 	AllowedOrigins []string `yaml:"allowedOrigins"`
 }
 
+type Tracing struct {
+	// Enabled enables the export of the traces
+	Enabled bool `yaml:"enabled"`
+
+	// Endpoint is the OTLP/HTTP collector endpoint (host:port) i.e. localhost:4318
+	Endpoint string `yaml:"endpoint"`
+	// Insecure disables TLS when connecting to the collector
+	Insecure bool `yaml:"insecure"`
+
+	// ServiceName is the service name reported in the traces
+	ServiceName string `yaml:"serviceName"`
+
+	// SampleRatio is the ratio of the sampled traces (from 0 to 1)
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
 type DB struct {
 	Type       sql.Type `yaml:"type"`
 	ConnString string   `yaml:"connString"`
@@ -345,6 +404,11 @@ func (vt OrganizationMemberAddingMode) IsValid() bool {
 	return false
 }
 
+var defaultBackup = Backup{
+	Interval:  24 * time.Hour,
+	Retention: 7,
+}
+
 var defaultConfig = func() *Config {
 	return &Config{
 		ID: "agola",
@@ -356,11 +420,13 @@ var defaultConfig = func() *Config {
 				Duration: 12 * time.Hour,
 			},
 			OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+			MirrorPollInterval:           1 * time.Minute,
 		},
 		Runservice: Runservice{
 			RunCacheExpireInterval:     7 * 24 * time.Hour,
 			RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 			RunLogExpireInterval:       30 * 24 * time.Hour,
+			Backup:                     defaultBackup,
 		},
 		Executor: Executor{
 			InitImage: InitImage{
@@ -368,6 +434,10 @@ var defaultConfig = func() *Config {
 			},
 			ActiveTasksLimit: 2,
 		},
+		Configstore: Configstore{
+			ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+			Backup:                        defaultBackup,
+		},
 		Gitserver: Gitserver{
 			RepositoryCleanupInterval:    24 * time.Hour,
 			RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
@@ -376,6 +446,10 @@ var defaultConfig = func() *Config {
 			RunWebhookExpireInterval:   7 * 24 * time.Hour,
 			CommitStatusExpireInterval: 7 * 24 * time.Hour,
 		},
+		Tracing: Tracing{
+			ServiceName: "agola",
+			SampleRatio: 1,
+		},
 	}
 }
 
@@ -557,6 +631,34 @@ func validateInitImage(i *InitImage) error {
 	return nil
 }
 
+func validateBackup(b *Backup) error {
+	if !b.Enabled {
+		return nil
+	}
+	if b.Interval <= 0 {
+		return errors.Errorf("interval must be greater than 0")
+	}
+	if b.Retention <= 0 {
+		return errors.Errorf("retention must be greater than 0")
+	}
+
+	return nil
+}
+
+func validateTracing(t *Tracing) error {
+	if !t.Enabled {
+		return nil
+	}
+	if t.Endpoint == "" {
+		return errors.Errorf("endpoint is empty")
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return errors.Errorf("sampleRatio must be between 0 and 1")
+	}
+
+	return nil
+}
+
 func Validate(c *Config, componentsNames []string) error {
 	// Global
 	if len(c.ID) > maxIDLength {
@@ -565,6 +667,9 @@ func Validate(c *Config, componentsNames []string) error {
 	if !util.ValidateName(c.ID) {
 		return errors.Errorf("invalid id")
 	}
+	if err := validateTracing(&c.Tracing); err != nil {
+		return errors.Wrapf(err, "tracing configuration error")
+	}
 
 	// Gateway
 	if isComponentEnabled(componentsNames, "gateway") {
@@ -602,6 +707,9 @@ func Validate(c *Config, componentsNames []string) error {
 		if err := validateWeb(&c.Configstore.Web); err != nil {
 			return errors.Wrapf(err, "configstore web configuration error")
 		}
+		if err := validateBackup(&c.Configstore.Backup); err != nil {
+			return errors.Wrapf(err, "configstore backup configuration error")
+		}
 	}
 
 	// Runservice
@@ -615,6 +723,9 @@ func Validate(c *Config, componentsNames []string) error {
 		if err := validateWeb(&c.Runservice.Web); err != nil {
 			return errors.Wrapf(err, "runservice web configuration error")
 		}
+		if err := validateBackup(&c.Runservice.Backup); err != nil {
+			return errors.Wrapf(err, "runservice backup configuration error")
+		}
 	}
 
 	// Executor
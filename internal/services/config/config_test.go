@@ -117,6 +117,7 @@ gitserver:
 					CookieSigning:                CookieSigning{Duration: 12 * time.Hour, Key: "supersecretsigningkey"},
 					AdminToken:                   "admintoken",
 					OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+					MirrorPollInterval:           1 * time.Minute,
 				},
 				Scheduler: Scheduler{RunserviceURL: "http://localhost:4000"},
 				Notification: Notification{
@@ -135,6 +136,7 @@ gitserver:
 					RunCacheExpireInterval:     7 * 24 * time.Hour,
 					RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 					RunLogExpireInterval:       30 * 24 * time.Hour,
+					Backup:                     Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Executor: Executor{
 					DataDir:                   "/data/agola/executor",
@@ -147,10 +149,12 @@ gitserver:
 					AllowPrivilegedContainers: true,
 				},
 				Configstore: Configstore{
-					DataDir:       "/data/agola/configstore",
-					DB:            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
-					Web:           Web{ListenAddress: ":4002"},
-					ObjectStorage: ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					DataDir:                       "/data/agola/configstore",
+					DB:                            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
+					Web:                           Web{ListenAddress: ":4002"},
+					ObjectStorage:                 ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+					Backup:                        Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Gitserver: Gitserver{
 					DataDir:                      "/data/agola/gitserver",
@@ -158,6 +162,10 @@ gitserver:
 					RepositoryCleanupInterval:    24 * time.Hour,
 					RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
 				},
+				Tracing: Tracing{
+					ServiceName: "agola",
+					SampleRatio: 1,
+				},
 			},
 		},
 		{
@@ -231,6 +239,7 @@ gitserver:
 					CookieSigning:                CookieSigning{Duration: 12 * time.Hour, Key: "supersecretsigningkey"},
 					AdminToken:                   "admintoken",
 					OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+					MirrorPollInterval:           1 * time.Minute,
 				},
 				Scheduler: Scheduler{RunserviceURL: "http://localhost:4000"},
 				Notification: Notification{
@@ -249,6 +258,7 @@ gitserver:
 					RunCacheExpireInterval:     7 * 24 * time.Hour,
 					RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 					RunLogExpireInterval:       30 * 24 * time.Hour,
+					Backup:                     Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Executor: Executor{
 					InitImage: InitImage{
@@ -257,10 +267,12 @@ gitserver:
 					ActiveTasksLimit: 2,
 				},
 				Configstore: Configstore{
-					DataDir:       "/data/agola/configstore",
-					DB:            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
-					Web:           Web{ListenAddress: ":4002"},
-					ObjectStorage: ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					DataDir:                       "/data/agola/configstore",
+					DB:                            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
+					Web:                           Web{ListenAddress: ":4002"},
+					ObjectStorage:                 ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+					Backup:                        Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Gitserver: Gitserver{
 					DataDir:                      "/data/agola/gitserver",
@@ -268,6 +280,10 @@ gitserver:
 					RepositoryCleanupInterval:    24 * time.Hour,
 					RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
 				},
+				Tracing: Tracing{
+					ServiceName: "agola",
+					SampleRatio: 1,
+				},
 			},
 		},
 		{
@@ -323,6 +339,7 @@ gitserver:
 					CookieSigning:                CookieSigning{Duration: 12 * time.Hour, Key: "supersecretsigningkey"},
 					AdminToken:                   "admintoken",
 					OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+					MirrorPollInterval:           1 * time.Minute,
 				},
 				Scheduler: Scheduler{RunserviceURL: "http://localhost:4000"},
 				Notification: Notification{
@@ -337,12 +354,21 @@ gitserver:
 					RunCacheExpireInterval:     7 * 24 * time.Hour,
 					RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 					RunLogExpireInterval:       30 * 24 * time.Hour,
+					Backup:                     Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Executor: Executor{InitImage: InitImage{Image: "busybox:stable"}, ActiveTasksLimit: 2},
+				Configstore: Configstore{
+					ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+					Backup:                        Backup{Interval: 24 * time.Hour, Retention: 7},
+				},
 				Gitserver: Gitserver{
 					RepositoryCleanupInterval:    24 * time.Hour,
 					RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
 				},
+				Tracing: Tracing{
+					ServiceName: "agola",
+					SampleRatio: 1,
+				},
 			},
 		},
 		{
@@ -387,6 +413,35 @@ gitserver:
 `,
 			err: errors.Errorf("gitserver dataDir is empty"),
 		},
+		{
+			name:     "test config with tracing enabled without endpoint",
+			services: []string{"gitserver"},
+			in: `
+gitserver:
+  dataDir: /data/agola/gitserver
+
+tracing:
+  enabled: true
+`,
+			err: errors.Errorf("tracing configuration error: endpoint is empty"),
+		},
+		{
+			name:     "test config with configstore backup enabled without retention",
+			services: []string{"configstore"},
+			in: `
+configstore:
+  dataDir: /data/agola/configstore
+  db:
+    type: sqlite3
+    connString: /data/agola/configstore/db
+  web:
+    listenAddress: ":4002"
+  backup:
+    enabled: true
+    retention: 0
+`,
+			err: errors.Errorf("configstore backup configuration error: retention must be greater than 0"),
+		},
 
 		{
 			name:     "test config with global urls",
@@ -471,6 +526,7 @@ gitserver:
 					CookieSigning:                CookieSigning{Duration: 12 * time.Hour, Key: "supersecretsigningkey"},
 					AdminToken:                   "admintoken",
 					OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+					MirrorPollInterval:           1 * time.Minute,
 				},
 				Scheduler: Scheduler{RunserviceURL: "http://localhost:4000"},
 				Notification: Notification{
@@ -489,6 +545,7 @@ gitserver:
 					RunCacheExpireInterval:     7 * 24 * time.Hour,
 					RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 					RunLogExpireInterval:       30 * 24 * time.Hour,
+					Backup:                     Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Executor: Executor{
 					DataDir:                   "/data/agola/executor",
@@ -501,10 +558,12 @@ gitserver:
 					AllowPrivilegedContainers: true,
 				},
 				Configstore: Configstore{
-					DataDir:       "/data/agola/configstore",
-					DB:            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
-					Web:           Web{ListenAddress: ":4002"},
-					ObjectStorage: ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					DataDir:                       "/data/agola/configstore",
+					DB:                            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
+					Web:                           Web{ListenAddress: ":4002"},
+					ObjectStorage:                 ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+					Backup:                        Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Gitserver: Gitserver{
 					DataDir:                      "/data/agola/gitserver",
@@ -512,6 +571,10 @@ gitserver:
 					RepositoryCleanupInterval:    24 * time.Hour,
 					RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
 				},
+				Tracing: Tracing{
+					ServiceName: "agola",
+					SampleRatio: 1,
+				},
 			},
 		},
 		{
@@ -616,6 +679,7 @@ gitserver:
 					CookieSigning:                CookieSigning{Duration: 12 * time.Hour, Key: "supersecretsigningkey"},
 					AdminToken:                   "admintoken",
 					OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+					MirrorPollInterval:           1 * time.Minute,
 				},
 				Scheduler: Scheduler{
 					RunserviceURL:      "http://localhost:4000",
@@ -643,6 +707,7 @@ gitserver:
 					RunCacheExpireInterval:     7 * 24 * time.Hour,
 					RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 					RunLogExpireInterval:       30 * 24 * time.Hour,
+					Backup:                     Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Executor: Executor{
 					DataDir:                   "/data/agola/executor",
@@ -657,11 +722,13 @@ gitserver:
 					AllowPrivilegedContainers: true,
 				},
 				Configstore: Configstore{
-					DataDir:       "/data/agola/configstore",
-					DB:            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
-					Web:           Web{ListenAddress: ":4002"},
-					APIToken:      "internalservicesapitoken",
-					ObjectStorage: ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					DataDir:                       "/data/agola/configstore",
+					DB:                            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
+					Web:                           Web{ListenAddress: ":4002"},
+					APIToken:                      "internalservicesapitoken",
+					ObjectStorage:                 ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+					Backup:                        Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Gitserver: Gitserver{
 					DataDir:                      "/data/agola/gitserver",
@@ -670,6 +737,10 @@ gitserver:
 					RepositoryCleanupInterval:    24 * time.Hour,
 					RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
 				},
+				Tracing: Tracing{
+					ServiceName: "agola",
+					SampleRatio: 1,
+				},
 			},
 		},
 		{
@@ -779,6 +850,7 @@ gitserver:
 					CookieSigning:                CookieSigning{Duration: 12 * time.Hour, Key: "supersecretsigningkey"},
 					AdminToken:                   "admintoken",
 					OrganizationMemberAddingMode: defaultOrganizationMemberAddingMode,
+					MirrorPollInterval:           1 * time.Minute,
 				},
 				Scheduler: Scheduler{
 					RunserviceURL:      "http://localhost:4000",
@@ -806,6 +878,7 @@ gitserver:
 					RunCacheExpireInterval:     7 * 24 * time.Hour,
 					RunWorkspaceExpireInterval: 7 * 24 * time.Hour,
 					RunLogExpireInterval:       30 * 24 * time.Hour,
+					Backup:                     Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Executor: Executor{
 					DataDir:                   "/data/agola/executor",
@@ -820,11 +893,13 @@ gitserver:
 					AllowPrivilegedContainers: true,
 				},
 				Configstore: Configstore{
-					DataDir:       "/data/agola/configstore",
-					DB:            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
-					Web:           Web{ListenAddress: ":4002"},
-					APIToken:      "configstoreapitoken",
-					ObjectStorage: ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					DataDir:                       "/data/agola/configstore",
+					DB:                            DB{Type: "sqlite3", ConnString: "/data/agola/configstore/db"},
+					Web:                           Web{ListenAddress: ":4002"},
+					APIToken:                      "configstoreapitoken",
+					ObjectStorage:                 ObjectStorage{Type: "posix", Path: "/agola/configstore/ost"},
+					ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
+					Backup:                        Backup{Interval: 24 * time.Hour, Retention: 7},
 				},
 				Gitserver: Gitserver{
 					DataDir:                      "/data/agola/gitserver",
@@ -833,6 +908,10 @@ gitserver:
 					RepositoryCleanupInterval:    24 * time.Hour,
 					RepositoryRefsExpireInterval: 30 * 24 * time.Hour,
 				},
+				Tracing: Tracing{
+					ServiceName: "agola",
+					SampleRatio: 1,
+				},
 			},
 		},
 	}
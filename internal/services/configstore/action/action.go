@@ -22,6 +22,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/sorintlab/errors"
 
+	"agola.io/agola/internal/objectstorage"
 	"agola.io/agola/internal/services/configstore/common"
 	"agola.io/agola/internal/services/configstore/db"
 	serrors "agola.io/agola/internal/services/errors"
@@ -34,15 +35,17 @@ import (
 type ActionHandler struct {
 	log                  zerolog.Logger
 	d                    *db.DB
+	ost                  objectstorage.ObjStorage
 	lf                   lock.LockFactory
 	maintenanceMode      bool
 	maintenanceModeMutex sync.Mutex
 }
 
-func NewActionHandler(log zerolog.Logger, d *db.DB, lf lock.LockFactory) *ActionHandler {
+func NewActionHandler(log zerolog.Logger, d *db.DB, ost objectstorage.ObjStorage, lf lock.LockFactory) *ActionHandler {
 	return &ActionHandler{
 		log:             log,
 		d:               d,
+		ost:             ost,
 		lf:              lf,
 		maintenanceMode: false,
 	}
@@ -0,0 +1,177 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/db"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
+)
+
+type CreateAuditEventRequest struct {
+	ActorUserID   string
+	ActorUserName string
+
+	Action types.AuditAction
+
+	TargetKind types.ObjectKind
+	TargetID   string
+	TargetName string
+
+	// ScopeKind and ScopeID define the object used to find the organization
+	// owning the target
+	ScopeKind types.ObjectKind
+	ScopeID   string
+
+	Details         map[string]string
+	RequestMetadata types.AuditEventRequestMetadata
+}
+
+// getScopeOrganizationID returns the id of the organization owning the
+// provided object or an empty string if it isn't owned by an organization.
+func (h *ActionHandler) getScopeOrganizationID(tx *sql.Tx, kind types.ObjectKind, id string) (string, error) {
+	switch kind {
+	case types.ObjectKindOrg:
+		return id, nil
+	case types.ObjectKindProjectGroup, types.ObjectKindProject:
+		root, err := h.getRootParent(tx, kind, id)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if root.Kind == types.ObjectKindOrg {
+			return root.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (h *ActionHandler) CreateAuditEvent(ctx context.Context, req *CreateAuditEventRequest) (*types.AuditEvent, error) {
+	if req.Action == "" {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("audit event action required"), serrors.InvalidAuditAction())
+	}
+
+	var auditEvent *types.AuditEvent
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		orgID, err := h.getScopeOrganizationID(tx, req.ScopeKind, req.ScopeID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		auditEvent = types.NewAuditEvent(tx)
+		auditEvent.ActorUserID = req.ActorUserID
+		auditEvent.ActorUserName = req.ActorUserName
+		auditEvent.Action = req.Action
+		auditEvent.TargetKind = req.TargetKind
+		auditEvent.TargetID = req.TargetID
+		auditEvent.TargetName = req.TargetName
+		auditEvent.OrganizationID = orgID
+		auditEvent.Details = req.Details
+		auditEvent.RequestMetadata = req.RequestMetadata
+
+		if err := h.d.InsertAuditEvent(tx, auditEvent); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return auditEvent, nil
+}
+
+type GetAuditEventsRequest struct {
+	OrgRef string
+
+	ActorUserID string
+	Actions     []types.AuditAction
+	TargetKind  types.ObjectKind
+	TargetID    string
+	Since       *time.Time
+	Until       *time.Time
+
+	StartSequence uint64
+
+	Limit         int
+	SortDirection types.SortDirection
+}
+
+type GetAuditEventsResponse struct {
+	AuditEvents []*types.AuditEvent
+
+	HasMore bool
+}
+
+func (h *ActionHandler) GetAuditEvents(ctx context.Context, req *GetAuditEventsRequest) (*GetAuditEventsResponse, error) {
+	limit := req.Limit
+	if limit > 0 {
+		limit += 1
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = types.SortDirectionAsc
+	}
+
+	var auditEvents []*types.AuditEvent
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		filter := &db.AuditEventsFilter{
+			ActorUserID: req.ActorUserID,
+			Actions:     req.Actions,
+			TargetKind:  req.TargetKind,
+			TargetID:    req.TargetID,
+			Since:       req.Since,
+			Until:       req.Until,
+		}
+
+		if req.OrgRef != "" {
+			org, err := h.GetOrgByRef(tx, req.OrgRef)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if org == nil {
+				return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("org %q doesn't exist", req.OrgRef), serrors.OrganizationDoesNotExist())
+			}
+			filter.OrganizationID = org.ID
+		}
+
+		var err error
+		auditEvents, err = h.d.GetAuditEvents(tx, filter, req.StartSequence, limit, req.SortDirection)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var hasMore bool
+	if req.Limit > 0 {
+		hasMore = len(auditEvents) > req.Limit
+		if hasMore {
+			auditEvents = auditEvents[0:req.Limit]
+		}
+	}
+
+	return &GetAuditEventsResponse{
+		AuditEvents: auditEvents,
+		HasMore:     hasMore,
+	}, nil
+}
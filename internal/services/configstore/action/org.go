@@ -306,6 +306,22 @@ func (h *ActionHandler) DeleteOrg(ctx context.Context, orgRef string) error {
 			return errors.WithStack(err)
 		}
 
+		teams, err := h.d.GetOrgTeams(tx, org.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, team := range teams {
+			if err := h.d.DeleteRoleBindingsBySubject(tx, types.ObjectKindTeam, team.ID); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if err := h.d.DeleteTeamMembersByOrgID(tx, org.ID); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := h.d.DeleteTeamsByOrgID(tx, org.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
 		if err := h.d.DeleteOrgInvitationsByOrgID(tx, org.ID); err != nil {
 			return errors.WithStack(err)
 		}
@@ -322,6 +338,11 @@ func (h *ActionHandler) DeleteOrg(ctx context.Context, orgRef string) error {
 			}
 
 			for _, project := range projects {
+				err = h.d.DeleteProjectMirrorByProjectID(tx, project.ID)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
 				err = h.d.DeleteProject(tx, project.ID)
 				if err != nil {
 					return errors.WithStack(err)
@@ -450,6 +471,11 @@ func (h *ActionHandler) RemoveOrgMember(ctx context.Context, orgRef, userRef str
 			return errors.WithStack(err)
 		}
 
+		// remove the user from all the org teams
+		if err := h.d.DeleteTeamMembersByOrgUserID(tx, org.ID, user.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
 		return nil
 	})
 	if err != nil {
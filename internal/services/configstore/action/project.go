@@ -98,6 +98,19 @@ func (h *ActionHandler) ValidateProjectReq(ctx context.Context, req *CreateUpdat
 			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty remote repository path"))
 		}
 	}
+	if req.RemoteRepositoryConfigType == types.RemoteRepositoryConfigTypeMirror {
+		if req.MirrorURL == "" {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty mirror url"), serrors.InvalidProjectMirror())
+		}
+		if err := util.ValidateGitRemoteURL(req.MirrorURL); err != nil {
+			return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("invalid mirror url %q", req.MirrorURL), serrors.InvalidProjectMirror())
+		}
+		for _, pattern := range append(append([]string{}, req.MirrorBranches...), req.MirrorTags...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid mirror ref pattern %q", pattern), serrors.InvalidProjectMirror())
+			}
+		}
+	}
 	return nil
 }
 
@@ -150,6 +163,12 @@ type CreateUpdateProjectRequest struct {
 	DefaultBranch              string
 	// MembersCanPerformRunActions defines if project organization members can restart/stop/cancel a project run
 	MembersCanPerformRunActions bool
+
+	// mirror projects fields
+	MirrorURL        string
+	MirrorSSHHostKey string
+	MirrorBranches   []string
+	MirrorTags       []string
 }
 
 func (h *ActionHandler) CreateProject(ctx context.Context, req *CreateUpdateProjectRequest) (*GetProjectResponse, error) {
@@ -240,6 +259,10 @@ func (h *ActionHandler) CreateProject(ctx context.Context, req *CreateUpdateProj
 			return errors.WithStack(err)
 		}
 
+		if err := h.updateProjectMirror(tx, project, req); err != nil {
+			return errors.WithStack(err)
+		}
+
 		projectDynamicData, err = h.projectDynamicData(tx, project)
 
 		return errors.WithStack(err)
@@ -360,6 +383,10 @@ func (h *ActionHandler) UpdateProject(ctx context.Context, curProjectRef string,
 			return errors.WithStack(err)
 		}
 
+		if err := h.updateProjectMirror(tx, project, req); err != nil {
+			return errors.WithStack(err)
+		}
+
 		projectDynamicData, err = h.projectDynamicData(tx, project)
 
 		return errors.WithStack(err)
@@ -385,6 +412,10 @@ func (h *ActionHandler) DeleteProject(ctx context.Context, projectRef string) er
 			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project %q doesn't exist", projectRef), serrors.ProjectDoesNotExist())
 		}
 
+		if err := h.d.DeleteProjectMirrorByProjectID(tx, project.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
 		// TODO(sgotti) implement childs garbage collection
 		if err := h.d.DeleteProject(tx, project.ID); err != nil {
 			return errors.WithStack(err)
@@ -0,0 +1,181 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
+)
+
+// updateProjectMirror creates, updates or deletes the project mirror based on
+// the project remote repository config type.
+func (h *ActionHandler) updateProjectMirror(tx *sql.Tx, project *types.Project, req *CreateUpdateProjectRequest) error {
+	projectMirror, err := h.d.GetProjectMirrorByProjectID(tx, project.ID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if project.RemoteRepositoryConfigType != types.RemoteRepositoryConfigTypeMirror {
+		if projectMirror == nil {
+			return nil
+		}
+		return errors.WithStack(h.d.DeleteProjectMirror(tx, projectMirror.ID))
+	}
+
+	if projectMirror == nil {
+		projectMirror = types.NewProjectMirror(tx)
+		projectMirror.ProjectID = project.ID
+		projectMirror.NextPollTime = time.Now()
+	}
+
+	// a different repository: forget the current refs so the next poll will
+	// only record them without creating runs for all of them
+	if projectMirror.URL != req.MirrorURL {
+		projectMirror.Refs = nil
+		projectMirror.NextPollTime = time.Now()
+	}
+
+	projectMirror.URL = req.MirrorURL
+	projectMirror.SSHHostKey = req.MirrorSSHHostKey
+	projectMirror.Branches = req.MirrorBranches
+	projectMirror.Tags = req.MirrorTags
+
+	return errors.WithStack(h.d.InsertOrUpdateProjectMirror(tx, projectMirror))
+}
+
+func (h *ActionHandler) GetProjectMirror(ctx context.Context, projectRef string) (*types.ProjectMirror, error) {
+	var projectMirror *types.ProjectMirror
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		project, err := h.GetProjectByRef(tx, projectRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if project == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project %q doesn't exist", projectRef), serrors.ProjectDoesNotExist())
+		}
+
+		projectMirror, err = h.d.GetProjectMirrorByProjectID(tx, project.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if projectMirror == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project %q isn't a mirror project", projectRef), serrors.ProjectMirrorDoesNotExist())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return projectMirror, nil
+}
+
+// ClaimProjectMirror claims the next project mirror to poll for the provided
+// claim duration and returns it. After the claim expires the project mirror
+// could be claimed again (i.e. when the poller crashed). It returns nil if
+// there's no project mirror to poll.
+func (h *ActionHandler) ClaimProjectMirror(ctx context.Context, claimDuration time.Duration) (*types.ProjectMirror, error) {
+	var projectMirror *types.ProjectMirror
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+
+		var err error
+		projectMirror, err = h.d.GetNextProjectMirrorToPoll(tx, now)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if projectMirror == nil {
+			return nil
+		}
+
+		projectMirror.PollCount++
+		projectMirror.NextPollTime = now.Add(claimDuration)
+
+		if err := h.d.UpdateProjectMirror(tx, projectMirror); err != nil {
+			// claimed by someone else
+			if errors.Is(err, sqlg.ErrConcurrent) {
+				projectMirror = nil
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return projectMirror, nil
+}
+
+type UpdateProjectMirrorPollResultRequest struct {
+	// PollCount must match the project mirror poll count. It's used to
+	// detect that the claim expired and the project mirror was claimed again.
+	PollCount uint64
+
+	// Refs are the polled refs. When nil the current refs are kept (i.e.
+	// when the refs couldn't be listed).
+	Refs         map[string]string
+	PollError    string
+	NextPollTime time.Time
+}
+
+// UpdateProjectMirrorPollResult saves the result of a project mirror poll.
+func (h *ActionHandler) UpdateProjectMirrorPollResult(ctx context.Context, projectMirrorID string, req *UpdateProjectMirrorPollResultRequest) (*types.ProjectMirror, error) {
+	var projectMirror *types.ProjectMirror
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		projectMirror, err = h.d.GetProjectMirrorByID(tx, projectMirrorID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if projectMirror == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project mirror %q doesn't exist", projectMirrorID), serrors.ProjectMirrorDoesNotExist())
+		}
+
+		if projectMirror.PollCount != req.PollCount {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project mirror %q isn't claimed by this poll", projectMirrorID), serrors.ProjectMirrorNotClaimed())
+		}
+
+		now := time.Now()
+		projectMirror.LastPollTime = &now
+		projectMirror.LastPollError = req.PollError
+		projectMirror.NextPollTime = req.NextPollTime
+		if req.Refs != nil {
+			projectMirror.Refs = req.Refs
+		}
+
+		if err := h.d.UpdateProjectMirror(tx, projectMirror); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return projectMirror, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
+)
+
+type CreateReceivedWebhookRequest struct {
+	ProjectRef string
+	DeliveryID string
+	Event      string
+	Header     map[string][]string
+	Body       []byte
+}
+
+// CreateReceivedWebhook queues a received webhook for processing. If a
+// webhook with the same delivery id was already received for the project it
+// isn't queued again and the existing one is returned.
+func (h *ActionHandler) CreateReceivedWebhook(ctx context.Context, req *CreateReceivedWebhookRequest) (*types.ReceivedWebhook, error) {
+	if req.DeliveryID == "" {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("received webhook delivery id required"), serrors.InvalidReceivedWebhookDelivery())
+	}
+
+	var receivedWebhook *types.ReceivedWebhook
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		project, err := h.GetProjectByRef(tx, req.ProjectRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if project == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project %q doesn't exist", req.ProjectRef), serrors.ProjectDoesNotExist())
+		}
+
+		receivedWebhook, err = h.d.GetReceivedWebhookByDeliveryID(tx, project.ID, req.DeliveryID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if receivedWebhook != nil {
+			return nil
+		}
+
+		receivedWebhook = types.NewReceivedWebhook(tx)
+		receivedWebhook.ProjectID = project.ID
+		receivedWebhook.DeliveryID = req.DeliveryID
+		receivedWebhook.Event = req.Event
+		receivedWebhook.Header = req.Header
+		receivedWebhook.Body = req.Body
+		if receivedWebhook.Body == nil {
+			receivedWebhook.Body = []byte{}
+		}
+		receivedWebhook.Status = types.ReceivedWebhookStatusPending
+		receivedWebhook.NextAttemptTime = time.Now()
+
+		if err := h.d.InsertReceivedWebhook(tx, receivedWebhook); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return receivedWebhook, nil
+}
+
+type GetProjectReceivedWebhooksRequest struct {
+	ProjectRef string
+	Statuses   []types.ReceivedWebhookStatus
+
+	StartSequence uint64
+
+	Limit         int
+	SortDirection types.SortDirection
+}
+
+type GetProjectReceivedWebhooksResponse struct {
+	ReceivedWebhooks []*types.ReceivedWebhook
+
+	HasMore bool
+}
+
+func (h *ActionHandler) GetProjectReceivedWebhooks(ctx context.Context, req *GetProjectReceivedWebhooksRequest) (*GetProjectReceivedWebhooksResponse, error) {
+	for _, s := range req.Statuses {
+		if !types.IsValidReceivedWebhookStatus(s) {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid received webhook status %q", s), serrors.InvalidReceivedWebhookStatus())
+		}
+	}
+
+	limit := req.Limit
+	if limit > 0 {
+		limit += 1
+	}
+	if req.SortDirection == "" {
+		req.SortDirection = types.SortDirectionAsc
+	}
+
+	var receivedWebhooks []*types.ReceivedWebhook
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		project, err := h.GetProjectByRef(tx, req.ProjectRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if project == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project %q doesn't exist", req.ProjectRef), serrors.ProjectDoesNotExist())
+		}
+
+		receivedWebhooks, err = h.d.GetProjectReceivedWebhooks(tx, project.ID, req.Statuses, req.StartSequence, limit, req.SortDirection)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var hasMore bool
+	if req.Limit > 0 {
+		hasMore = len(receivedWebhooks) > req.Limit
+		if hasMore {
+			receivedWebhooks = receivedWebhooks[0:req.Limit]
+		}
+	}
+
+	return &GetProjectReceivedWebhooksResponse{
+		ReceivedWebhooks: receivedWebhooks,
+		HasMore:          hasMore,
+	}, nil
+}
+
+// ClaimReceivedWebhook marks the next received webhook to process as
+// processing for the provided claim duration and returns it. After the claim
+// expires the webhook could be claimed again (i.e. when the worker processing
+// it crashed). It returns nil if there's no webhook to process.
+func (h *ActionHandler) ClaimReceivedWebhook(ctx context.Context, claimDuration time.Duration) (*types.ReceivedWebhook, error) {
+	var receivedWebhook *types.ReceivedWebhook
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+
+		var err error
+		receivedWebhook, err = h.d.GetNextReceivedWebhookToProcess(tx, now)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if receivedWebhook == nil {
+			return nil
+		}
+
+		receivedWebhook.Status = types.ReceivedWebhookStatusProcessing
+		receivedWebhook.Attempts++
+		receivedWebhook.NextAttemptTime = now.Add(claimDuration)
+
+		if err := h.d.UpdateReceivedWebhook(tx, receivedWebhook); err != nil {
+			// claimed by someone else
+			if errors.Is(err, sqlg.ErrConcurrent) {
+				receivedWebhook = nil
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return receivedWebhook, nil
+}
+
+type UpdateReceivedWebhookResultRequest struct {
+	// Attempts must match the received webhook attempts. It's used to
+	// detect that the claim expired and the webhook was claimed again.
+	Attempts int
+
+	Status          types.ReceivedWebhookStatus
+	NextAttemptTime time.Time
+	Outcome         types.ReceivedWebhookOutcome
+	OutcomeMessage  string
+	RunNumbers      []uint64
+}
+
+// UpdateReceivedWebhookResult saves the result of a received webhook
+// processing. The status could be set back to pending to retry the processing
+// after the provided next attempt time.
+func (h *ActionHandler) UpdateReceivedWebhookResult(ctx context.Context, receivedWebhookID string, req *UpdateReceivedWebhookResultRequest) (*types.ReceivedWebhook, error) {
+	switch req.Status {
+	case types.ReceivedWebhookStatusPending, types.ReceivedWebhookStatusProcessed, types.ReceivedWebhookStatusFailed:
+	default:
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid received webhook status %q", req.Status), serrors.InvalidReceivedWebhookStatus())
+	}
+	if !types.IsValidReceivedWebhookOutcome(req.Outcome) {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid received webhook outcome %q", req.Outcome))
+	}
+
+	var receivedWebhook *types.ReceivedWebhook
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		receivedWebhook, err = h.d.GetReceivedWebhook(tx, receivedWebhookID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if receivedWebhook == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("received webhook %q doesn't exist", receivedWebhookID), serrors.ReceivedWebhookDoesNotExist())
+		}
+
+		if receivedWebhook.Status != types.ReceivedWebhookStatusProcessing || receivedWebhook.Attempts != req.Attempts {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("received webhook %q isn't claimed by this attempt", receivedWebhookID), serrors.ReceivedWebhookNotProcessing())
+		}
+
+		receivedWebhook.Status = req.Status
+		receivedWebhook.NextAttemptTime = req.NextAttemptTime
+		receivedWebhook.Outcome = req.Outcome
+		receivedWebhook.OutcomeMessage = req.OutcomeMessage
+		receivedWebhook.RunNumbers = req.RunNumbers
+
+		if err := h.d.UpdateReceivedWebhook(tx, receivedWebhook); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return receivedWebhook, nil
+}
+
+// DeleteExpiredReceivedWebhooks removes the processed and failed received
+// webhooks not updated since the provided expire interval.
+func (h *ActionHandler) DeleteExpiredReceivedWebhooks(ctx context.Context, expireInterval time.Duration) error {
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		return errors.WithStack(h.d.DeleteReceivedWebhooksBefore(tx, time.Now().Add(-expireInterval)))
+	})
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,389 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
+)
+
+// getParent returns the parent of the provided project group or project.
+func (h *ActionHandler) getParent(tx *sql.Tx, kind types.ObjectKind, id string) (*types.Parent, error) {
+	switch kind {
+	case types.ObjectKindProjectGroup:
+		projectGroup, err := h.GetProjectGroupByRef(tx, id)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if projectGroup == nil {
+			return nil, errors.Errorf("projectgroup with id %q doesn't exist", id)
+		}
+		return &projectGroup.Parent, nil
+	case types.ObjectKindProject:
+		project, err := h.GetProjectByRef(tx, id)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if project == nil {
+			return nil, errors.Errorf("project with id %q doesn't exist", id)
+		}
+		return &project.Parent, nil
+	}
+
+	return nil, errors.Errorf("object kind %q doesn't have a parent", kind)
+}
+
+func (h *ActionHandler) GetRoleBindingsTree(tx *sql.Tx, parentKind types.ObjectKind, parentID string) ([]*types.RoleBinding, error) {
+	allRoleBindings := []*types.RoleBinding{}
+
+	for parentKind == types.ObjectKindProjectGroup || parentKind == types.ObjectKindProject {
+		roleBindings, err := h.d.GetRoleBindings(tx, parentID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get role bindings for %s %q", parentKind, parentID)
+		}
+		allRoleBindings = append(allRoleBindings, roleBindings...)
+
+		parent, err := h.getParent(tx, parentKind, parentID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		parentKind = parent.Kind
+		parentID = parent.ID
+	}
+
+	return allRoleBindings, nil
+}
+
+type GetRoleBindingsResponse struct {
+	RoleBindings []*types.RoleBinding
+	ParentPaths  map[string]string
+	SubjectNames map[string]string
+}
+
+func (h *ActionHandler) GetRoleBindings(ctx context.Context, parentKind types.ObjectKind, parentRef string, tree bool) (*GetRoleBindingsResponse, error) {
+	var roleBindings []*types.RoleBinding
+	parentPaths := map[string]string{}
+	subjectNames := map[string]string{}
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		parentID, err := h.ResolveObjectID(tx, parentKind, parentRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if tree {
+			roleBindings, err = h.GetRoleBindingsTree(tx, parentKind, parentID)
+		} else {
+			roleBindings, err = h.d.GetRoleBindings(tx, parentID)
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		// populate role bindings parent paths and subject names
+		for _, rb := range roleBindings {
+			pp, err := h.GetPath(tx, rb.Parent.Kind, rb.Parent.ID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			parentPaths[rb.ID] = pp
+
+			name, err := h.getSubjectName(tx, rb.Subject)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			subjectNames[rb.ID] = name
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &GetRoleBindingsResponse{
+		RoleBindings: roleBindings,
+		ParentPaths:  parentPaths,
+		SubjectNames: subjectNames,
+	}, nil
+}
+
+func (h *ActionHandler) getSubjectName(tx *sql.Tx, subject types.Subject) (string, error) {
+	switch subject.Kind {
+	case types.ObjectKindUser:
+		user, err := h.d.GetUserByID(tx, subject.ID)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		// the user could have been removed
+		if user == nil {
+			return "", nil
+		}
+		return user.Name, nil
+	case types.ObjectKindTeam:
+		team, err := h.d.GetTeamByID(tx, subject.ID)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		// the team could have been removed
+		if team == nil {
+			return "", nil
+		}
+		return team.Name, nil
+	}
+
+	return "", errors.Errorf("unknown subject kind %q", subject.Kind)
+}
+
+// getRootParent returns the user or organization owning the provided project
+// group or project.
+func (h *ActionHandler) getRootParent(tx *sql.Tx, kind types.ObjectKind, id string) (*types.Parent, error) {
+	parent := &types.Parent{Kind: kind, ID: id}
+	for parent.Kind == types.ObjectKindProjectGroup || parent.Kind == types.ObjectKindProject {
+		var err error
+		parent, err = h.getParent(tx, parent.Kind, parent.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	return parent, nil
+}
+
+// resolveSubjectID returns the id of the subject referenced by subjectRef.
+// Teams are resolved inside the organization owning the role binding parent.
+func (h *ActionHandler) resolveSubjectID(tx *sql.Tx, parentKind types.ObjectKind, parentID string, subjectKind types.ObjectKind, subjectRef string) (string, error) {
+	switch subjectKind {
+	case types.ObjectKindUser:
+		user, err := h.GetUserByRef(tx, subjectRef)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if user == nil {
+			return "", util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("user %q doesn't exist", subjectRef), serrors.UserDoesNotExist())
+		}
+		return user.ID, nil
+	case types.ObjectKindTeam:
+		root, err := h.getRootParent(tx, parentKind, parentID)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if root.Kind != types.ObjectKindOrg {
+			return "", util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("team role bindings can only be defined on organization project groups and projects"))
+		}
+		team, err := h.GetTeamByRef(tx, root.ID, subjectRef)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if team == nil {
+			return "", util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("team %q doesn't exist", subjectRef), serrors.TeamDoesNotExist())
+		}
+		return team.ID, nil
+	}
+
+	return "", util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid role binding subject kind %q", subjectKind))
+}
+
+type SetRoleBindingRequest struct {
+	Parent types.Parent
+
+	SubjectKind types.ObjectKind
+	SubjectRef  string
+
+	Role types.MemberRole
+}
+
+// SetRoleBinding creates or updates the role binding of a subject on a
+// project group or project.
+func (h *ActionHandler) SetRoleBinding(ctx context.Context, req *SetRoleBindingRequest) (*types.RoleBinding, error) {
+	if req.Parent.Kind != types.ObjectKindProject && req.Parent.Kind != types.ObjectKindProjectGroup {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid role binding parent kind %q", req.Parent.Kind))
+	}
+	if !types.IsValidMemberRole(req.Role) {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid role %q", req.Role), serrors.InvalidRole())
+	}
+	// owner is a role reserved to organization owners
+	if req.Role == types.MemberRoleOwner {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("role %q cannot be assigned to project groups or projects", req.Role), serrors.InvalidRole())
+	}
+
+	var roleBinding *types.RoleBinding
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		parentID, err := h.ResolveObjectID(tx, req.Parent.Kind, req.Parent.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		subjectID, err := h.resolveSubjectID(tx, req.Parent.Kind, parentID, req.SubjectKind, req.SubjectRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		roleBinding, err = h.d.GetRoleBindingBySubject(tx, parentID, req.SubjectKind, subjectID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		// update if role changed
+		if roleBinding != nil {
+			if roleBinding.Role == req.Role {
+				return nil
+			}
+			roleBinding.Role = req.Role
+		} else {
+			roleBinding = types.NewRoleBinding(tx)
+			roleBinding.Parent = types.Parent{Kind: req.Parent.Kind, ID: parentID}
+			roleBinding.Subject = types.Subject{Kind: req.SubjectKind, ID: subjectID}
+			roleBinding.Role = req.Role
+		}
+
+		if err := h.d.InsertOrUpdateRoleBinding(tx, roleBinding); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return roleBinding, errors.WithStack(err)
+}
+
+func (h *ActionHandler) DeleteRoleBinding(ctx context.Context, parentKind types.ObjectKind, parentRef string, subjectKind types.ObjectKind, subjectRef string) error {
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		parentID, err := h.ResolveObjectID(tx, parentKind, parentRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		subjectID, err := h.resolveSubjectID(tx, parentKind, parentID, subjectKind, subjectRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		roleBinding, err := h.d.GetRoleBindingBySubject(tx, parentID, subjectKind, subjectID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if roleBinding == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("role binding for %s %q doesn't exist", subjectKind, subjectRef), serrors.RoleBindingDoesNotExist())
+		}
+
+		if err := h.d.DeleteRoleBinding(tx, roleBinding.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+
+	return errors.WithStack(err)
+}
+
+// getUserRole returns the effective role of a user on a project group or
+// project. It's the highest role between the role bindings of the user and of
+// its teams defined on the object and on all its parent project groups, the
+// organization member role if the object is owned by an organization and the
+// owner role if the object is owned by the user itself.
+func (h *ActionHandler) getUserRole(tx *sql.Tx, parentKind types.ObjectKind, parentID, userID string) (types.MemberRole, error) {
+	var role types.MemberRole
+
+	teams, err := h.d.GetUserTeams(tx, userID)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	teamIDs := map[string]struct{}{}
+	for _, team := range teams {
+		teamIDs[team.ID] = struct{}{}
+	}
+
+	for parentKind == types.ObjectKindProjectGroup || parentKind == types.ObjectKindProject {
+		roleBindings, err := h.d.GetRoleBindings(tx, parentID)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		for _, roleBinding := range roleBindings {
+			switch roleBinding.Subject.Kind {
+			case types.ObjectKindUser:
+				if roleBinding.Subject.ID != userID {
+					continue
+				}
+			case types.ObjectKindTeam:
+				if _, ok := teamIDs[roleBinding.Subject.ID]; !ok {
+					continue
+				}
+			default:
+				continue
+			}
+			role = types.MaxMemberRole(role, roleBinding.Role)
+		}
+
+		parent, err := h.getParent(tx, parentKind, parentID)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		parentKind = parent.Kind
+		parentID = parent.ID
+	}
+
+	switch parentKind {
+	case types.ObjectKindUser:
+		if parentID == userID {
+			role = types.MemberRoleOwner
+		}
+	case types.ObjectKindOrg:
+		orgMember, err := h.d.GetOrgMemberByOrgUserID(tx, parentID, userID)
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		if orgMember != nil {
+			role = types.MaxMemberRole(role, orgMember.MemberRole)
+		}
+	}
+
+	return role, nil
+}
+
+// GetUserRole returns the effective role of a user on a project group or
+// project. An empty role is returned if the user has no role on it.
+func (h *ActionHandler) GetUserRole(ctx context.Context, parentKind types.ObjectKind, parentRef, userRef string) (types.MemberRole, error) {
+	var role types.MemberRole
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		parentID, err := h.ResolveObjectID(tx, parentKind, parentRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		user, err := h.GetUserByRef(tx, userRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if user == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("user %q doesn't exist", userRef), serrors.UserDoesNotExist())
+		}
+
+		role, err = h.getUserRole(tx, parentKind, parentID, user.ID)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return role, nil
+}
@@ -0,0 +1,747 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
+)
+
+// scopedExportKinds are the object kinds that can be part of a scoped export.
+var scopedExportKinds = map[string]struct{}{
+	"RemoteSource":  {},
+	"LinkedAccount": {},
+	"Organization":  {},
+	"Team":          {},
+	"ProjectGroup":  {},
+	"Project":       {},
+	"Secret":        {},
+	"Variable":      {},
+	"ProjectMirror": {},
+	"RoleBinding":   {},
+}
+
+type ScopedExportRequest struct {
+	// Kind is the kind of the exported object. It can be an organization or
+	// a project group.
+	Kind types.ObjectKind
+	Ref  string
+}
+
+// GetScopedExportObjects returns the objects of an organization or a project
+// group: all its project groups, projects, secrets, variables and project
+// mirrors. For an organization also its teams and the role bindings of these
+// teams are returned.
+// Organization members, team members and invitations reference installation
+// specific users and aren't exported.
+// The remote sources and linked accounts referenced by the projects are
+// returned without their credentials since they are only used to resolve the
+// project references when importing.
+func (h *ActionHandler) GetScopedExportObjects(ctx context.Context, req *ScopedExportRequest) ([]sqlg.Object, error) {
+	var objs []sqlg.Object
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		objs, err = h.getScopedExportObjects(tx, req)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return objs, nil
+}
+
+func (h *ActionHandler) getScopedExportObjects(tx *sql.Tx, req *ScopedExportRequest) ([]sqlg.Object, error) {
+	var org *types.Organization
+	var rootProjectGroup *types.ProjectGroup
+
+	switch req.Kind {
+	case types.ObjectKindOrg:
+		var err error
+		org, err = h.GetOrgByRef(tx, req.Ref)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if org == nil {
+			return nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("org %q doesn't exist", req.Ref), serrors.OrganizationDoesNotExist())
+		}
+
+		rootProjectGroup, err = h.GetProjectGroupByRef(tx, path.Join("org", org.Name))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if rootProjectGroup == nil {
+			return nil, errors.Errorf("org %q root project group doesn't exist", org.Name)
+		}
+
+	case types.ObjectKindProjectGroup:
+		var err error
+		rootProjectGroup, err = h.GetProjectGroupByRef(tx, req.Ref)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if rootProjectGroup == nil {
+			return nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project group %q doesn't exist", req.Ref), serrors.ProjectGroupDoesNotExist())
+		}
+		if rootProjectGroup.Parent.Kind != types.ObjectKindProjectGroup {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project group %q is a root project group", req.Ref), serrors.InvalidExportScope())
+		}
+
+	default:
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid export scope kind %q", req.Kind), serrors.InvalidExportScope())
+	}
+
+	subgroups, err := h.getAllProjectGroupSubgroups(tx, rootProjectGroup.ID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	projectGroups := append([]*types.ProjectGroup{rootProjectGroup}, subgroups...)
+
+	var projects []*types.Project
+	for _, projectGroup := range projectGroups {
+		pgProjects, err := h.d.GetProjectGroupProjects(tx, projectGroup.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		projects = append(projects, pgProjects...)
+	}
+
+	var parentIDs []string
+	if org != nil {
+		parentIDs = append(parentIDs, org.ID)
+	}
+	for _, projectGroup := range projectGroups {
+		parentIDs = append(parentIDs, projectGroup.ID)
+	}
+	for _, project := range projects {
+		parentIDs = append(parentIDs, project.ID)
+	}
+
+	var secrets []*types.Secret
+	var variables []*types.Variable
+	for _, parentID := range parentIDs {
+		parentSecrets, err := h.d.GetSecrets(tx, parentID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		secrets = append(secrets, parentSecrets...)
+
+		parentVariables, err := h.d.GetVariables(tx, parentID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		variables = append(variables, parentVariables...)
+	}
+
+	var projectMirrors []*types.ProjectMirror
+	for _, project := range projects {
+		projectMirror, err := h.d.GetProjectMirrorByProjectID(tx, project.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if projectMirror != nil {
+			projectMirrors = append(projectMirrors, projectMirror)
+		}
+	}
+
+	var remoteSources []*types.RemoteSource
+	var linkedAccounts []*types.LinkedAccount
+	remoteSourcesIDs := map[string]struct{}{}
+	linkedAccountsIDs := map[string]struct{}{}
+	for _, project := range projects {
+		if project.RemoteSourceID != "" {
+			if _, ok := remoteSourcesIDs[project.RemoteSourceID]; !ok {
+				remoteSource, err := h.d.GetRemoteSourceByID(tx, project.RemoteSourceID)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				if remoteSource == nil {
+					return nil, errors.Errorf("remote source %q referenced by project %q doesn't exist", project.RemoteSourceID, project.ID)
+				}
+				remoteSource.Oauth2ClientSecret = ""
+
+				remoteSources = append(remoteSources, remoteSource)
+				remoteSourcesIDs[remoteSource.ID] = struct{}{}
+			}
+		}
+		if project.LinkedAccountID != "" {
+			if _, ok := linkedAccountsIDs[project.LinkedAccountID]; !ok {
+				linkedAccount, err := h.d.GetLinkedAccount(tx, project.LinkedAccountID)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+				if linkedAccount == nil {
+					return nil, errors.Errorf("linked account %q referenced by project %q doesn't exist", project.LinkedAccountID, project.ID)
+				}
+				linkedAccount.UserAccessToken = ""
+				linkedAccount.Oauth2AccessToken = ""
+				linkedAccount.Oauth2RefreshToken = ""
+
+				linkedAccounts = append(linkedAccounts, linkedAccount)
+				linkedAccountsIDs[linkedAccount.ID] = struct{}{}
+			}
+		}
+	}
+
+	var teams []*types.Team
+	var roleBindings []*types.RoleBinding
+	if org != nil {
+		var err error
+		teams, err = h.d.GetOrgTeams(tx, org.ID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		teamsIDs := map[string]struct{}{}
+		for _, team := range teams {
+			teamsIDs[team.ID] = struct{}{}
+		}
+
+		for _, parentID := range parentIDs {
+			parentRoleBindings, err := h.d.GetRoleBindings(tx, parentID)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			for _, roleBinding := range parentRoleBindings {
+				if roleBinding.Subject.Kind != types.ObjectKindTeam {
+					continue
+				}
+				if _, ok := teamsIDs[roleBinding.Subject.ID]; !ok {
+					continue
+				}
+				roleBindings = append(roleBindings, roleBinding)
+			}
+		}
+	}
+
+	// keep referenced objects before the objects referencing them
+	var objs []sqlg.Object
+	for _, o := range remoteSources {
+		objs = append(objs, o)
+	}
+	for _, o := range linkedAccounts {
+		objs = append(objs, o)
+	}
+	if org != nil {
+		objs = append(objs, org)
+	}
+	for _, o := range teams {
+		objs = append(objs, o)
+	}
+	for _, o := range projectGroups {
+		objs = append(objs, o)
+	}
+	for _, o := range projects {
+		objs = append(objs, o)
+	}
+	for _, o := range secrets {
+		objs = append(objs, o)
+	}
+	for _, o := range variables {
+		objs = append(objs, o)
+	}
+	for _, o := range projectMirrors {
+		objs = append(objs, o)
+	}
+	for _, o := range roleBindings {
+		objs = append(objs, o)
+	}
+
+	return objs, nil
+}
+
+// WriteExportObjects writes the provided objects using the export format.
+func (h *ActionHandler) WriteExportObjects(objs []sqlg.Object, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := json.NewEncoder(bw)
+
+	for _, obj := range objs {
+		if err := h.d.ObjectToExportJSON(obj, e); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return errors.WithStack(bw.Flush())
+}
+
+type ScopedImportRequest struct {
+	// ParentRef is the project group where an exported project group will be
+	// imported. It must be empty when importing an organization.
+	ParentRef string
+	// CreatorUserID is the user that will be the owner of an imported
+	// organization.
+	CreatorUserID string
+}
+
+type ScopedImportResponse struct {
+	// Kind and ID are the kind and the ID of the imported organization or
+	// project group.
+	Kind types.ObjectKind
+	ID   string
+	// ProjectIDs maps the exported projects IDs to the imported projects IDs.
+	ProjectIDs map[string]string
+}
+
+type scopedImportObjects struct {
+	remoteSources  map[string]*types.RemoteSource
+	linkedAccounts map[string]*types.LinkedAccount
+	org            *types.Organization
+	teams          []*types.Team
+	projectGroups  []*types.ProjectGroup
+	projects       []*types.Project
+	secrets        []*types.Secret
+	variables      []*types.Variable
+	projectMirrors []*types.ProjectMirror
+	roleBindings   []*types.RoleBinding
+}
+
+func (h *ActionHandler) decodeScopedImport(r io.Reader) (*scopedImportObjects, error) {
+	objs := &scopedImportObjects{
+		remoteSources:  map[string]*types.RemoteSource{},
+		linkedAccounts: map[string]*types.LinkedAccount{},
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var jobj json.RawMessage
+
+		err := dec.Decode(&jobj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+
+		var om struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+		}
+		if err := json.Unmarshal(jobj, &om); err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+		if _, ok := scopedExportKinds[om.ExportMeta.Kind]; !ok {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("unexpected object kind %q in scoped export", om.ExportMeta.Kind), serrors.InvalidExportData())
+		}
+
+		obj, err := h.d.UnmarshalExportObject(jobj)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+
+		switch o := obj.(type) {
+		case *types.RemoteSource:
+			objs.remoteSources[o.ID] = o
+		case *types.LinkedAccount:
+			objs.linkedAccounts[o.ID] = o
+		case *types.Organization:
+			if objs.org != nil {
+				return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("scoped export contains multiple organizations"), serrors.InvalidExportData())
+			}
+			objs.org = o
+		case *types.Team:
+			objs.teams = append(objs.teams, o)
+		case *types.ProjectGroup:
+			objs.projectGroups = append(objs.projectGroups, o)
+		case *types.Project:
+			objs.projects = append(objs.projects, o)
+		case *types.Secret:
+			objs.secrets = append(objs.secrets, o)
+		case *types.Variable:
+			objs.variables = append(objs.variables, o)
+		case *types.ProjectMirror:
+			objs.projectMirrors = append(objs.projectMirrors, o)
+		case *types.RoleBinding:
+			objs.roleBindings = append(objs.roleBindings, o)
+		}
+	}
+
+	return objs, nil
+}
+
+// ImportScoped imports the objects exported by GetScopedExportObjects.
+// All the imported objects get new IDs. The import fails without changes if
+// the imported organization or the imported project group conflict with
+// existing ones or if the remote sources and linked accounts referenced by
+// the projects don't exist in this installation. Remote sources are matched
+// by name and linked accounts by remote source and remote user id.
+func (h *ActionHandler) ImportScoped(ctx context.Context, req *ScopedImportRequest, r io.Reader) (*ScopedImportResponse, error) {
+	objs, err := h.decodeScopedImport(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if objs.org != nil && req.ParentRef != "" {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("a parent project group cannot be provided when importing an organization"), serrors.InvalidExportScope())
+	}
+	if objs.org == nil && req.ParentRef == "" {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("a parent project group is required when importing a project group"), serrors.InvalidExportScope())
+	}
+
+	// generate the new objects ids
+	ids := map[string]string{}
+	teamsIDs := map[string]struct{}{}
+	projectGroupsIDs := map[string]struct{}{}
+	projectsIDs := map[string]struct{}{}
+	if objs.org != nil {
+		ids[objs.org.ID] = uuid.Must(uuid.NewV4()).String()
+	}
+	for _, team := range objs.teams {
+		ids[team.ID] = uuid.Must(uuid.NewV4()).String()
+		teamsIDs[team.ID] = struct{}{}
+	}
+	for _, projectGroup := range objs.projectGroups {
+		ids[projectGroup.ID] = uuid.Must(uuid.NewV4()).String()
+		projectGroupsIDs[projectGroup.ID] = struct{}{}
+	}
+	for _, project := range objs.projects {
+		ids[project.ID] = uuid.Must(uuid.NewV4()).String()
+		projectsIDs[project.ID] = struct{}{}
+	}
+
+	var rootProjectGroup *types.ProjectGroup
+	for _, projectGroup := range objs.projectGroups {
+		isRoot := false
+		switch projectGroup.Parent.Kind {
+		case types.ObjectKindOrg:
+			if objs.org == nil || projectGroup.Parent.ID != objs.org.ID {
+				return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project group %q parent org doesn't exist", projectGroup.ID), serrors.InvalidExportData())
+			}
+			isRoot = true
+		case types.ObjectKindProjectGroup:
+			if _, ok := projectGroupsIDs[projectGroup.Parent.ID]; !ok {
+				isRoot = objs.org == nil
+				if !isRoot {
+					return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project group %q parent project group doesn't exist", projectGroup.ID), serrors.InvalidExportData())
+				}
+			}
+		default:
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project group %q has a wrong parent kind %q", projectGroup.ID, projectGroup.Parent.Kind), serrors.InvalidExportData())
+		}
+
+		if isRoot {
+			if rootProjectGroup != nil {
+				return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("scoped export contains multiple root project groups"), serrors.InvalidExportData())
+			}
+			rootProjectGroup = projectGroup
+		}
+	}
+	if rootProjectGroup == nil {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("scoped export doesn't contain a root project group"), serrors.InvalidExportData())
+	}
+
+	isParent := func(parent types.Parent) bool {
+		switch parent.Kind {
+		case types.ObjectKindOrg:
+			return objs.org != nil && parent.ID == objs.org.ID
+		case types.ObjectKindProjectGroup:
+			_, ok := projectGroupsIDs[parent.ID]
+			return ok
+		case types.ObjectKindProject:
+			_, ok := projectsIDs[parent.ID]
+			return ok
+		}
+		return false
+	}
+
+	for _, project := range objs.projects {
+		if project.Parent.Kind != types.ObjectKindProjectGroup || !isParent(project.Parent) {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q parent project group doesn't exist", project.ID), serrors.InvalidExportData())
+		}
+		if project.RemoteSourceID != "" {
+			if _, ok := objs.remoteSources[project.RemoteSourceID]; !ok {
+				return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q remote source doesn't exist", project.ID), serrors.InvalidExportData())
+			}
+		}
+		if project.LinkedAccountID != "" {
+			if _, ok := objs.linkedAccounts[project.LinkedAccountID]; !ok {
+				return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q linked account doesn't exist", project.ID), serrors.InvalidExportData())
+			}
+		}
+	}
+	for _, secret := range objs.secrets {
+		if secret.Parent.Kind == types.ObjectKindOrg || !isParent(secret.Parent) {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("secret %q parent doesn't exist", secret.ID), serrors.InvalidExportData())
+		}
+	}
+	for _, variable := range objs.variables {
+		if variable.Parent.Kind == types.ObjectKindOrg || !isParent(variable.Parent) {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("variable %q parent doesn't exist", variable.ID), serrors.InvalidExportData())
+		}
+	}
+	for _, projectMirror := range objs.projectMirrors {
+		if _, ok := projectsIDs[projectMirror.ProjectID]; !ok {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project mirror %q project doesn't exist", projectMirror.ID), serrors.InvalidExportData())
+		}
+	}
+	for _, team := range objs.teams {
+		if objs.org == nil || team.OrganizationID != objs.org.ID {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("team %q org doesn't exist", team.ID), serrors.InvalidExportData())
+		}
+	}
+	for _, roleBinding := range objs.roleBindings {
+		_, isTeam := teamsIDs[roleBinding.Subject.ID]
+		if !isParent(roleBinding.Parent) || roleBinding.Subject.Kind != types.ObjectKindTeam || !isTeam {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("role binding %q parent or subject doesn't exist", roleBinding.ID), serrors.InvalidExportData())
+		}
+	}
+
+	// remap the objects ids. The transaction function could be retried so do
+	// it outside the transaction keeping the references that must be
+	// resolved inside it.
+	projectIDs := map[string]string{}
+	projectsRemoteSources := map[string]string{}
+	projectsLinkedAccounts := map[string]string{}
+	if objs.org != nil {
+		objs.org.ID = ids[objs.org.ID]
+	}
+	for _, team := range objs.teams {
+		team.ID = ids[team.ID]
+		team.OrganizationID = ids[team.OrganizationID]
+	}
+	for _, projectGroup := range objs.projectGroups {
+		projectGroup.ID = ids[projectGroup.ID]
+		if projectGroup != rootProjectGroup {
+			projectGroup.Parent.ID = ids[projectGroup.Parent.ID]
+		}
+	}
+	for _, project := range objs.projects {
+		projectIDs[project.ID] = ids[project.ID]
+		project.ID = ids[project.ID]
+		project.Parent.ID = ids[project.Parent.ID]
+		projectsRemoteSources[project.ID] = project.RemoteSourceID
+		projectsLinkedAccounts[project.ID] = project.LinkedAccountID
+	}
+	for _, secret := range objs.secrets {
+		secret.ID = uuid.Must(uuid.NewV4()).String()
+		secret.Parent.ID = ids[secret.Parent.ID]
+	}
+	for _, variable := range objs.variables {
+		variable.ID = uuid.Must(uuid.NewV4()).String()
+		variable.Parent.ID = ids[variable.Parent.ID]
+	}
+	for _, projectMirror := range objs.projectMirrors {
+		projectMirror.ID = uuid.Must(uuid.NewV4()).String()
+		projectMirror.ProjectID = ids[projectMirror.ProjectID]
+	}
+	for _, roleBinding := range objs.roleBindings {
+		roleBinding.ID = uuid.Must(uuid.NewV4()).String()
+		roleBinding.Parent.ID = ids[roleBinding.Parent.ID]
+		roleBinding.Subject.ID = ids[roleBinding.Subject.ID]
+	}
+
+	err = h.d.Do(ctx, func(tx *sql.Tx) error {
+		// resolve the referenced remote sources and linked accounts
+		remoteSourcesIDs := map[string]string{}
+		for _, remoteSource := range objs.remoteSources {
+			curRemoteSource, err := h.d.GetRemoteSourceByName(tx, remoteSource.Name)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if curRemoteSource == nil {
+				return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("remote source %q doesn't exist", remoteSource.Name), serrors.RemoteSourceDoesNotExist())
+			}
+			remoteSourcesIDs[remoteSource.ID] = curRemoteSource.ID
+		}
+		linkedAccountsIDs := map[string]string{}
+		for _, linkedAccount := range objs.linkedAccounts {
+			remoteSourceID, ok := remoteSourcesIDs[linkedAccount.RemoteSourceID]
+			if !ok {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("linked account %q remote source doesn't exist", linkedAccount.ID), serrors.InvalidExportData())
+			}
+			curLinkedAccount, err := h.d.GetLinkedAccountByRemoteUserIDandSource(tx, linkedAccount.RemoteUserID, remoteSourceID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if curLinkedAccount == nil {
+				return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("linked account for remote user %q on remote source %q doesn't exist", linkedAccount.RemoteUserName, objs.remoteSources[linkedAccount.RemoteSourceID].Name), serrors.LinkedAccountDoesNotExist())
+			}
+			linkedAccountsIDs[linkedAccount.ID] = curLinkedAccount.ID
+		}
+
+		// check conflicts and set the root project group parent
+		var creatorUser *types.User
+		if objs.org != nil {
+			curOrg, err := h.d.GetOrgByName(tx, objs.org.Name)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if curOrg != nil {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("org %q already exists", objs.org.Name), serrors.OrganizationAlreadyExists())
+			}
+			if req.CreatorUserID != "" {
+				creatorUser, err = h.GetUserByRef(tx, req.CreatorUserID)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if creatorUser == nil {
+					return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("creator user %q doesn't exist", req.CreatorUserID), serrors.CreatorUserDoesNotExist())
+				}
+			}
+
+			rootProjectGroup.Parent.ID = objs.org.ID
+		} else {
+			parentProjectGroup, err := h.GetProjectGroupByRef(tx, req.ParentRef)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if parentProjectGroup == nil {
+				return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project group %q doesn't exist", req.ParentRef), serrors.ParentProjectGroupDoesNotExist())
+			}
+
+			curProjectGroup, err := h.d.GetProjectGroupByName(tx, parentProjectGroup.ID, rootProjectGroup.Name)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if curProjectGroup != nil {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project group with name %q, in project group %q already exists", rootProjectGroup.Name, req.ParentRef), serrors.ProjectGroupAlreadyExists())
+			}
+			curProject, err := h.d.GetProjectByName(tx, parentProjectGroup.ID, rootProjectGroup.Name)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if curProject != nil {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project with name %q, in project group %q already exists", rootProjectGroup.Name, req.ParentRef), serrors.ProjectAlreadyExists())
+			}
+
+			rootProjectGroup.Parent.ID = parentProjectGroup.ID
+		}
+
+		var insertObjs []sqlg.Object
+
+		if objs.org != nil {
+			objs.org.CreatorUserID = ""
+			if creatorUser != nil {
+				objs.org.CreatorUserID = creatorUser.ID
+			}
+			insertObjs = append(insertObjs, objs.org)
+		}
+		for _, team := range objs.teams {
+			insertObjs = append(insertObjs, team)
+		}
+		for _, projectGroup := range objs.projectGroups {
+			insertObjs = append(insertObjs, projectGroup)
+		}
+		for _, project := range objs.projects {
+			project.RemoteSourceID = remoteSourcesIDs[projectsRemoteSources[project.ID]]
+			project.LinkedAccountID = linkedAccountsIDs[projectsLinkedAccounts[project.ID]]
+			insertObjs = append(insertObjs, project)
+		}
+		for _, secret := range objs.secrets {
+			insertObjs = append(insertObjs, secret)
+		}
+		for _, variable := range objs.variables {
+			insertObjs = append(insertObjs, variable)
+		}
+		for _, projectMirror := range objs.projectMirrors {
+			insertObjs = append(insertObjs, projectMirror)
+		}
+		for _, roleBinding := range objs.roleBindings {
+			insertObjs = append(insertObjs, roleBinding)
+		}
+
+		for _, obj := range insertObjs {
+			if err := h.d.InsertRawObject(tx, obj); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		if objs.org != nil && creatorUser != nil {
+			orgmember := types.NewOrganizationMember(tx)
+			orgmember.OrganizationID = objs.org.ID
+			orgmember.UserID = creatorUser.ID
+			orgmember.MemberRole = types.MemberRoleOwner
+
+			if err := h.d.InsertOrganizationMember(tx, orgmember); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res := &ScopedImportResponse{Kind: types.ObjectKindProjectGroup, ID: rootProjectGroup.ID, ProjectIDs: projectIDs}
+	if objs.org != nil {
+		res.Kind = types.ObjectKindOrg
+		res.ID = objs.org.ID
+	}
+
+	return res, nil
+}
+
+// DeleteScopedImport deletes an organization or a project group with all the
+// objects that are part of its scoped export. It's used to roll back a scoped
+// import when the import of the related objects of other services fails.
+// The referenced remote sources and linked accounts aren't deleted.
+func (h *ActionHandler) DeleteScopedImport(ctx context.Context, req *ScopedExportRequest) error {
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		objs, err := h.getScopedExportObjects(tx, req)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		// delete the objects referencing other objects first
+		for i := len(objs) - 1; i >= 0; i-- {
+			switch o := objs[i].(type) {
+			case *types.RoleBinding:
+				err = h.d.DeleteRoleBinding(tx, o.ID)
+			case *types.ProjectMirror:
+				err = h.d.DeleteProjectMirror(tx, o.ID)
+			case *types.Variable:
+				err = h.d.DeleteVariable(tx, o.ID)
+			case *types.Secret:
+				err = h.d.DeleteSecret(tx, o.ID)
+			case *types.Project:
+				err = h.d.DeleteProject(tx, o.ID)
+			case *types.ProjectGroup:
+				err = h.d.DeleteProjectGroup(tx, o.ID)
+			case *types.Team:
+				err = h.d.DeleteTeam(tx, o.ID)
+			case *types.Organization:
+				if err := h.d.DeleteOrgMembersByOrgID(tx, o.ID); err != nil {
+					return errors.WithStack(err)
+				}
+				if err := h.d.DeleteTeamMembersByOrgID(tx, o.ID); err != nil {
+					return errors.WithStack(err)
+				}
+				if err := h.d.DeleteOrgInvitationsByOrgID(tx, o.ID); err != nil {
+					return errors.WithStack(err)
+				}
+				err = h.d.DeleteOrganization(tx, o.ID)
+			}
+			if err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		return nil
+	})
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,329 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/common"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
+)
+
+func (h *ActionHandler) GetTeamByRef(tx *sql.Tx, orgID, teamRef string) (*types.Team, error) {
+	refType, err := common.ParseNameRef(teamRef)
+	if err != nil {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("wrong team ref: %q", teamRef), serrors.InvalidRef())
+	}
+
+	var team *types.Team
+	switch refType {
+	case common.RefTypeID:
+		team, err = h.d.GetTeamByID(tx, teamRef)
+		// the team must belong to the provided org
+		if team != nil && team.OrganizationID != orgID {
+			team = nil
+		}
+	case common.RefTypeName:
+		team, err = h.d.GetTeamByName(tx, orgID, teamRef)
+	}
+	return team, errors.WithStack(err)
+}
+
+// getOrgTeam returns the org and the team referenced by orgRef and teamRef or
+// an error if one of them doesn't exist.
+func (h *ActionHandler) getOrgTeam(tx *sql.Tx, orgRef, teamRef string) (*types.Organization, *types.Team, error) {
+	org, err := h.GetOrgByRef(tx, orgRef)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	if org == nil {
+		return nil, nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("org %q doesn't exist", orgRef), serrors.OrganizationDoesNotExist())
+	}
+
+	team, err := h.GetTeamByRef(tx, org.ID, teamRef)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	if team == nil {
+		return nil, nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("team %q doesn't exist in org %q", teamRef, orgRef), serrors.TeamDoesNotExist())
+	}
+
+	return org, team, nil
+}
+
+func (h *ActionHandler) GetOrgTeams(ctx context.Context, orgRef string) ([]*types.Team, error) {
+	var teams []*types.Team
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		org, err := h.GetOrgByRef(tx, orgRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if org == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("org %q doesn't exist", orgRef), serrors.OrganizationDoesNotExist())
+		}
+
+		teams, err = h.d.GetOrgTeams(tx, org.ID)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return teams, nil
+}
+
+func (h *ActionHandler) GetTeam(ctx context.Context, orgRef, teamRef string) (*types.Team, error) {
+	var team *types.Team
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		_, team, err = h.getOrgTeam(tx, orgRef, teamRef)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return team, nil
+}
+
+type CreateTeamRequest struct {
+	OrgRef string
+	Name   string
+}
+
+func (h *ActionHandler) CreateTeam(ctx context.Context, req *CreateTeamRequest) (*types.Team, error) {
+	if req.Name == "" {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("team name required"), serrors.InvalidTeamName())
+	}
+	if !util.ValidateName(req.Name) {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid team name %q", req.Name), serrors.InvalidTeamName())
+	}
+
+	var team *types.Team
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		org, err := h.GetOrgByRef(tx, req.OrgRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if org == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("org %q doesn't exist", req.OrgRef), serrors.OrganizationDoesNotExist())
+		}
+
+		// check duplicate team name
+		t, err := h.d.GetTeamByName(tx, org.ID, req.Name)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if t != nil {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("team %q already exists", t.Name), serrors.TeamAlreadyExists())
+		}
+
+		team = types.NewTeam(tx)
+		team.OrganizationID = org.ID
+		team.Name = req.Name
+
+		if err := h.d.InsertTeam(tx, team); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return team, nil
+}
+
+type UpdateTeamRequest struct {
+	OrgRef  string
+	TeamRef string
+
+	Name string
+}
+
+func (h *ActionHandler) UpdateTeam(ctx context.Context, req *UpdateTeamRequest) (*types.Team, error) {
+	if !util.ValidateName(req.Name) {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid team name %q", req.Name), serrors.InvalidTeamName())
+	}
+
+	var team *types.Team
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		var org *types.Organization
+		org, team, err = h.getOrgTeam(tx, req.OrgRef, req.TeamRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if team.Name == req.Name {
+			return nil
+		}
+
+		// check duplicate team name
+		t, err := h.d.GetTeamByName(tx, org.ID, req.Name)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if t != nil {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("team %q already exists", t.Name), serrors.TeamAlreadyExists())
+		}
+
+		team.Name = req.Name
+
+		if err := h.d.UpdateTeam(tx, team); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return team, nil
+}
+
+func (h *ActionHandler) DeleteTeam(ctx context.Context, orgRef, teamRef string) error {
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		_, team, err := h.getOrgTeam(tx, orgRef, teamRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := h.d.DeleteTeamMembersByTeamID(tx, team.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := h.d.DeleteRoleBindingsBySubject(tx, types.ObjectKindTeam, team.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := h.d.DeleteTeam(tx, team.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+
+	return errors.WithStack(err)
+}
+
+func (h *ActionHandler) GetTeamMembers(ctx context.Context, orgRef, teamRef string) ([]*types.User, error) {
+	var users []*types.User
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		_, team, err := h.getOrgTeam(tx, orgRef, teamRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		users, err = h.d.GetTeamUsers(tx, team.ID)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return users, nil
+}
+
+// AddTeamMember adds an org member to a team.
+func (h *ActionHandler) AddTeamMember(ctx context.Context, orgRef, teamRef, userRef string) (*types.TeamMember, error) {
+	var teamMember *types.TeamMember
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		org, team, err := h.getOrgTeam(tx, orgRef, teamRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		user, err := h.GetUserByRef(tx, userRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if user == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("user %q doesn't exist", userRef), serrors.UserDoesNotExist())
+		}
+
+		// only org members can be team members
+		orgMember, err := h.d.GetOrgMemberByOrgUserID(tx, org.ID, user.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if orgMember == nil {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("user %q isn't a member of org %q", userRef, orgRef), serrors.UserNotOrgMember())
+		}
+
+		teamMember, err = h.d.GetTeamMember(tx, team.ID, user.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if teamMember != nil {
+			return nil
+		}
+
+		teamMember = types.NewTeamMember(tx)
+		teamMember.TeamID = team.ID
+		teamMember.UserID = user.ID
+
+		if err := h.d.InsertTeamMember(tx, teamMember); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return teamMember, nil
+}
+
+func (h *ActionHandler) RemoveTeamMember(ctx context.Context, orgRef, teamRef, userRef string) error {
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		_, team, err := h.getOrgTeam(tx, orgRef, teamRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		user, err := h.GetUserByRef(tx, userRef)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if user == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("user %q doesn't exist", userRef), serrors.UserDoesNotExist())
+		}
+
+		teamMember, err := h.d.GetTeamMember(tx, team.ID, user.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if teamMember == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("teammember for team %q, user %q doesn't exist", teamRef, userRef), serrors.TeamMemberDoesNotExist())
+		}
+
+		if err := h.d.DeleteTeamMember(tx, teamMember.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	})
+
+	return errors.WithStack(err)
+}
@@ -281,6 +281,14 @@ func (h *ActionHandler) DeleteUser(ctx context.Context, userRef string) error {
 			return errors.WithStack(err)
 		}
 
+		if err := h.d.DeleteTeamMembersByUserID(tx, user.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := h.d.DeleteRoleBindingsBySubject(tx, types.ObjectKindUser, user.ID); err != nil {
+			return errors.WithStack(err)
+		}
+
 		if err := h.d.DeleteLinkedAccountsByUserID(tx, user.ID); err != nil {
 			return errors.WithStack(err)
 		}
@@ -0,0 +1,172 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/action"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/types"
+)
+
+type AuditEventsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewAuditEventsHandler(log zerolog.Logger, ah *action.ActionHandler) *AuditEventsHandler {
+	return &AuditEventsHandler{log: log, ah: ah}
+}
+
+func (h *AuditEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("cannot parse %s", name), serrors.InvalidTimeRange())
+	}
+
+	return &t, nil
+}
+
+func (h *AuditEventsHandler) do(w http.ResponseWriter, r *http.Request) ([]*types.AuditEvent, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	startSequenceStr := query.Get("startsequence")
+	var startSequence uint64
+	if startSequenceStr != "" {
+		var err error
+		startSequence, err = strconv.ParseUint(startSequenceStr, 10, 64)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("cannot parse startsequence"), serrors.InvalidStartSequence())
+		}
+	}
+
+	since, err := parseTimeParam(query, "since")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	until, err := parseTimeParam(query, "until")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var actions []types.AuditAction
+	for _, a := range query["action"] {
+		actions = append(actions, types.AuditAction(a))
+	}
+
+	ropts, err := parseRequestOptions(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	areq := &action.GetAuditEventsRequest{
+		OrgRef:        query.Get("orgref"),
+		ActorUserID:   query.Get("actoruserid"),
+		Actions:       actions,
+		TargetKind:    types.ObjectKind(query.Get("targetkind")),
+		TargetID:      query.Get("targetid"),
+		Since:         since,
+		Until:         until,
+		StartSequence: startSequence,
+		Limit:         ropts.Limit,
+		SortDirection: ropts.SortDirection,
+	}
+	ares, err := h.ah.GetAuditEvents(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	addHasMoreHeader(w, ares.HasMore)
+
+	return ares.AuditEvents, nil
+}
+
+type CreateAuditEventHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewCreateAuditEventHandler(log zerolog.Logger, ah *action.ActionHandler) *CreateAuditEventHandler {
+	return &CreateAuditEventHandler{log: log, ah: ah}
+}
+
+func (h *CreateAuditEventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusCreated, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *CreateAuditEventHandler) do(r *http.Request) (*types.AuditEvent, error) {
+	ctx := r.Context()
+
+	var req *csapitypes.CreateAuditEventRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.CreateAuditEventRequest{
+		ActorUserID:     req.ActorUserID,
+		ActorUserName:   req.ActorUserName,
+		Action:          req.Action,
+		TargetKind:      req.TargetKind,
+		TargetID:        req.TargetID,
+		TargetName:      req.TargetName,
+		ScopeKind:       req.ScopeKind,
+		ScopeID:         req.ScopeID,
+		Details:         req.Details,
+		RequestMetadata: req.RequestMetadata,
+	}
+	auditEvent, err := h.ah.CreateAuditEvent(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return auditEvent, nil
+}
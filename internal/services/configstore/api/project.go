@@ -143,6 +143,10 @@ func (h *CreateProjectHandler) do(r *http.Request) (*csapitypes.Project, error)
 		PassVarsToForkedPR:          req.PassVarsToForkedPR,
 		DefaultBranch:               req.DefaultBranch,
 		MembersCanPerformRunActions: req.MembersCanPerformRunActions,
+		MirrorURL:                   req.MirrorURL,
+		MirrorSSHHostKey:            req.MirrorSSHHostKey,
+		MirrorBranches:              req.MirrorBranches,
+		MirrorTags:                  req.MirrorTags,
 	}
 
 	res, err := h.ah.CreateProject(ctx, areq)
@@ -208,6 +212,10 @@ func (h *UpdateProjectHandler) do(r *http.Request) (*csapitypes.Project, error)
 		PassVarsToForkedPR:          req.PassVarsToForkedPR,
 		DefaultBranch:               req.DefaultBranch,
 		MembersCanPerformRunActions: req.MembersCanPerformRunActions,
+		MirrorURL:                   req.MirrorURL,
+		MirrorSSHHostKey:            req.MirrorSSHHostKey,
+		MirrorBranches:              req.MirrorBranches,
+		MirrorTags:                  req.MirrorTags,
 	}
 
 	res, err := h.ah.UpdateProject(ctx, projectRef, areq)
@@ -0,0 +1,157 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/action"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/types"
+)
+
+type ProjectMirrorHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewProjectMirrorHandler(log zerolog.Logger, ah *action.ActionHandler) *ProjectMirrorHandler {
+	return &ProjectMirrorHandler{log: log, ah: ah}
+}
+
+func (h *ProjectMirrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ProjectMirrorHandler) do(r *http.Request) (*types.ProjectMirror, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	projectRef, err := url.PathUnescape(vars["projectref"])
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	projectMirror, err := h.ah.GetProjectMirror(ctx, projectRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return projectMirror, nil
+}
+
+type ClaimProjectMirrorHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewClaimProjectMirrorHandler(log zerolog.Logger, ah *action.ActionHandler) *ClaimProjectMirrorHandler {
+	return &ClaimProjectMirrorHandler{log: log, ah: ah}
+}
+
+func (h *ClaimProjectMirrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	// a null response means there's no project mirror to poll
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ClaimProjectMirrorHandler) do(r *http.Request) (*types.ProjectMirror, error) {
+	ctx := r.Context()
+
+	var req *csapitypes.ClaimProjectMirrorRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+	if req.ClaimDuration <= 0 {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("claim duration must be greater than zero"))
+	}
+
+	projectMirror, err := h.ah.ClaimProjectMirror(ctx, req.ClaimDuration)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return projectMirror, nil
+}
+
+type UpdateProjectMirrorPollResultHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewUpdateProjectMirrorPollResultHandler(log zerolog.Logger, ah *action.ActionHandler) *UpdateProjectMirrorPollResultHandler {
+	return &UpdateProjectMirrorPollResultHandler{log: log, ah: ah}
+}
+
+func (h *UpdateProjectMirrorPollResultHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *UpdateProjectMirrorPollResultHandler) do(r *http.Request) (*types.ProjectMirror, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	projectMirrorID := vars["projectmirrorid"]
+
+	var req *csapitypes.UpdateProjectMirrorPollResultRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.UpdateProjectMirrorPollResultRequest{
+		PollCount:    req.PollCount,
+		Refs:         req.Refs,
+		PollError:    req.PollError,
+		NextPollTime: req.NextPollTime,
+	}
+	projectMirror, err := h.ah.UpdateProjectMirrorPollResult(ctx, projectMirrorID, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return projectMirror, nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/action"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/types"
+)
+
+type CreateReceivedWebhookHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewCreateReceivedWebhookHandler(log zerolog.Logger, ah *action.ActionHandler) *CreateReceivedWebhookHandler {
+	return &CreateReceivedWebhookHandler{log: log, ah: ah}
+}
+
+func (h *CreateReceivedWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusCreated, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *CreateReceivedWebhookHandler) do(r *http.Request) (*types.ReceivedWebhook, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	projectRef, err := url.PathUnescape(vars["projectref"])
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	var req *csapitypes.CreateReceivedWebhookRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.CreateReceivedWebhookRequest{
+		ProjectRef: projectRef,
+		DeliveryID: req.DeliveryID,
+		Event:      req.Event,
+		Header:     req.Header,
+		Body:       req.Body,
+	}
+	receivedWebhook, err := h.ah.CreateReceivedWebhook(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return receivedWebhook, nil
+}
+
+type ProjectReceivedWebhooksHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewProjectReceivedWebhooksHandler(log zerolog.Logger, ah *action.ActionHandler) *ProjectReceivedWebhooksHandler {
+	return &ProjectReceivedWebhooksHandler{log: log, ah: ah}
+}
+
+func (h *ProjectReceivedWebhooksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ProjectReceivedWebhooksHandler) do(w http.ResponseWriter, r *http.Request) ([]*types.ReceivedWebhook, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	projectRef, err := url.PathUnescape(vars["projectref"])
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	startSequenceStr := query.Get("startsequence")
+	var startSequence uint64
+	if startSequenceStr != "" {
+		var err error
+		startSequence, err = strconv.ParseUint(startSequenceStr, 10, 64)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("cannot parse startsequence"), serrors.InvalidStartSequence())
+		}
+	}
+
+	var statuses []types.ReceivedWebhookStatus
+	for _, s := range query["status"] {
+		statuses = append(statuses, types.ReceivedWebhookStatus(s))
+	}
+
+	ropts, err := parseRequestOptions(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	areq := &action.GetProjectReceivedWebhooksRequest{
+		ProjectRef:    projectRef,
+		Statuses:      statuses,
+		StartSequence: startSequence,
+		Limit:         ropts.Limit,
+		SortDirection: ropts.SortDirection,
+	}
+	ares, err := h.ah.GetProjectReceivedWebhooks(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	addHasMoreHeader(w, ares.HasMore)
+
+	return ares.ReceivedWebhooks, nil
+}
+
+type ClaimReceivedWebhookHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewClaimReceivedWebhookHandler(log zerolog.Logger, ah *action.ActionHandler) *ClaimReceivedWebhookHandler {
+	return &ClaimReceivedWebhookHandler{log: log, ah: ah}
+}
+
+func (h *ClaimReceivedWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	// a null response means there's no received webhook to process
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ClaimReceivedWebhookHandler) do(r *http.Request) (*types.ReceivedWebhook, error) {
+	ctx := r.Context()
+
+	var req *csapitypes.ClaimReceivedWebhookRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+	if req.ClaimDuration <= 0 {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("claim duration must be greater than zero"))
+	}
+
+	receivedWebhook, err := h.ah.ClaimReceivedWebhook(ctx, req.ClaimDuration)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return receivedWebhook, nil
+}
+
+type UpdateReceivedWebhookResultHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewUpdateReceivedWebhookResultHandler(log zerolog.Logger, ah *action.ActionHandler) *UpdateReceivedWebhookResultHandler {
+	return &UpdateReceivedWebhookResultHandler{log: log, ah: ah}
+}
+
+func (h *UpdateReceivedWebhookResultHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *UpdateReceivedWebhookResultHandler) do(r *http.Request) (*types.ReceivedWebhook, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+
+	receivedWebhookID := vars["receivedwebhookid"]
+
+	var req *csapitypes.UpdateReceivedWebhookResultRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.UpdateReceivedWebhookResultRequest{
+		Attempts:        req.Attempts,
+		Status:          req.Status,
+		NextAttemptTime: req.NextAttemptTime,
+		Outcome:         req.Outcome,
+		OutcomeMessage:  req.OutcomeMessage,
+		RunNumbers:      req.RunNumbers,
+	}
+	receivedWebhook, err := h.ah.UpdateReceivedWebhookResult(ctx, receivedWebhookID, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return receivedWebhook, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/action"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/types"
+)
+
+type RoleBindingsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRoleBindingsHandler(log zerolog.Logger, ah *action.ActionHandler) *RoleBindingsHandler {
+	return &RoleBindingsHandler{log: log, ah: ah}
+}
+
+func (h *RoleBindingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *RoleBindingsHandler) do(r *http.Request) ([]*csapitypes.RoleBinding, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	_, tree := query["tree"]
+
+	parentKind, parentRef, err := GetObjectKindRef(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res, err := h.ah.GetRoleBindings(ctx, parentKind, parentRef, tree)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resRoleBindings := make([]*csapitypes.RoleBinding, len(res.RoleBindings))
+	for i, rb := range res.RoleBindings {
+		resRoleBindings[i] = &csapitypes.RoleBinding{RoleBinding: rb, ParentPath: res.ParentPaths[rb.ID], SubjectName: res.SubjectNames[rb.ID]}
+	}
+
+	return resRoleBindings, nil
+}
+
+type SetRoleBindingHandler struct {
+	log         zerolog.Logger
+	ah          *action.ActionHandler
+	subjectKind types.ObjectKind
+}
+
+func NewSetRoleBindingHandler(log zerolog.Logger, ah *action.ActionHandler, subjectKind types.ObjectKind) *SetRoleBindingHandler {
+	return &SetRoleBindingHandler{log: log, ah: ah, subjectKind: subjectKind}
+}
+
+func (h *SetRoleBindingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *SetRoleBindingHandler) do(r *http.Request) (*types.RoleBinding, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	subjectRef := vars["subjectref"]
+
+	parentKind, parentRef, err := GetObjectKindRef(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var req *csapitypes.SetRoleBindingRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.SetRoleBindingRequest{
+		Parent: types.Parent{
+			Kind: parentKind,
+			ID:   parentRef,
+		},
+		SubjectKind: h.subjectKind,
+		SubjectRef:  subjectRef,
+		Role:        req.Role,
+	}
+
+	roleBinding, err := h.ah.SetRoleBinding(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return roleBinding, nil
+}
+
+type DeleteRoleBindingHandler struct {
+	log         zerolog.Logger
+	ah          *action.ActionHandler
+	subjectKind types.ObjectKind
+}
+
+func NewDeleteRoleBindingHandler(log zerolog.Logger, ah *action.ActionHandler, subjectKind types.ObjectKind) *DeleteRoleBindingHandler {
+	return &DeleteRoleBindingHandler{log: log, ah: ah, subjectKind: subjectKind}
+}
+
+func (h *DeleteRoleBindingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *DeleteRoleBindingHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	subjectRef := vars["subjectref"]
+
+	parentKind, parentRef, err := GetObjectKindRef(r)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = h.ah.DeleteRoleBinding(ctx, parentKind, parentRef, h.subjectKind, subjectRef)
+	return errors.WithStack(err)
+}
+
+type UserRoleHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewUserRoleHandler(log zerolog.Logger, ah *action.ActionHandler) *UserRoleHandler {
+	return &UserRoleHandler{log: log, ah: ah}
+}
+
+func (h *UserRoleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *UserRoleHandler) do(r *http.Request) (*csapitypes.UserRoleResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	userRef := vars["userref"]
+
+	parentKind, parentRef, err := GetObjectKindRef(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	role, err := h.ah.GetUserRole(ctx, parentKind, parentRef, userRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &csapitypes.UserRoleResponse{Role: role}, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/action"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/types"
+)
+
+type ScopedExportHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewScopedExportHandler(log zerolog.Logger, ah *action.ActionHandler) *ScopedExportHandler {
+	return &ScopedExportHandler{log: log, ah: ah}
+}
+
+func (h *ScopedExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+}
+
+func (h *ScopedExportHandler) do(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	req := &action.ScopedExportRequest{
+		Kind: types.ObjectKind(query.Get("kind")),
+		Ref:  query.Get("ref"),
+	}
+
+	objs, err := h.ah.GetScopedExportObjects(ctx, req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := h.ah.WriteExportObjects(objs, w); err != nil {
+		h.log.Err(err).Send()
+		// since we already answered with a 200 we cannot return another error code
+		// So abort the connection and the client will detect the missing ending chunk
+		// and consider this an error
+		//
+		// this is the way to force close a request without logging the panic
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
+type ScopedImportHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewScopedImportHandler(log zerolog.Logger, ah *action.ActionHandler) *ScopedImportHandler {
+	return &ScopedImportHandler{log: log, ah: ah}
+}
+
+func (h *ScopedImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ScopedImportHandler) do(r *http.Request) (*csapitypes.ScopedImportResponse, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	req := &action.ScopedImportRequest{
+		ParentRef:     query.Get("parentref"),
+		CreatorUserID: query.Get("creatoruserid"),
+	}
+
+	ares, err := h.ah.ImportScoped(ctx, req, r.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &csapitypes.ScopedImportResponse{Kind: ares.Kind, ID: ares.ID, ProjectIDs: ares.ProjectIDs}, nil
+}
+
+type DeleteScopedImportHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewDeleteScopedImportHandler(log zerolog.Logger, ah *action.ActionHandler) *DeleteScopedImportHandler {
+	return &DeleteScopedImportHandler{log: log, ah: ah}
+}
+
+func (h *DeleteScopedImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *DeleteScopedImportHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	req := &action.ScopedExportRequest{
+		Kind: types.ObjectKind(query.Get("kind")),
+		Ref:  query.Get("ref"),
+	}
+
+	return errors.WithStack(h.ah.DeleteScopedImport(ctx, req))
+}
@@ -0,0 +1,328 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/configstore/action"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/types"
+)
+
+type TeamsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewTeamsHandler(log zerolog.Logger, ah *action.ActionHandler) *TeamsHandler {
+	return &TeamsHandler{log: log, ah: ah}
+}
+
+func (h *TeamsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *TeamsHandler) do(r *http.Request) ([]*types.Team, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+
+	teams, err := h.ah.GetOrgTeams(ctx, orgRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return teams, nil
+}
+
+type TeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *TeamHandler {
+	return &TeamHandler{log: log, ah: ah}
+}
+
+func (h *TeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *TeamHandler) do(r *http.Request) (*types.Team, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	team, err := h.ah.GetTeam(ctx, orgRef, teamRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return team, nil
+}
+
+type CreateTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewCreateTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *CreateTeamHandler {
+	return &CreateTeamHandler{log: log, ah: ah}
+}
+
+func (h *CreateTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusCreated, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *CreateTeamHandler) do(r *http.Request) (*types.Team, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+
+	var req *csapitypes.CreateTeamRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.CreateTeamRequest{
+		OrgRef: orgRef,
+		Name:   req.Name,
+	}
+	team, err := h.ah.CreateTeam(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return team, nil
+}
+
+type UpdateTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewUpdateTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *UpdateTeamHandler {
+	return &UpdateTeamHandler{log: log, ah: ah}
+}
+
+func (h *UpdateTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *UpdateTeamHandler) do(r *http.Request) (*types.Team, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	var req *csapitypes.UpdateTeamRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.UpdateTeamRequest{
+		OrgRef:  orgRef,
+		TeamRef: teamRef,
+		Name:    req.Name,
+	}
+	team, err := h.ah.UpdateTeam(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return team, nil
+}
+
+type DeleteTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewDeleteTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *DeleteTeamHandler {
+	return &DeleteTeamHandler{log: log, ah: ah}
+}
+
+func (h *DeleteTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *DeleteTeamHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	if err := h.ah.DeleteTeam(ctx, orgRef, teamRef); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+type TeamMembersHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewTeamMembersHandler(log zerolog.Logger, ah *action.ActionHandler) *TeamMembersHandler {
+	return &TeamMembersHandler{log: log, ah: ah}
+}
+
+func (h *TeamMembersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *TeamMembersHandler) do(r *http.Request) ([]*types.User, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	users, err := h.ah.GetTeamMembers(ctx, orgRef, teamRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return users, nil
+}
+
+type AddTeamMemberHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewAddTeamMemberHandler(log zerolog.Logger, ah *action.ActionHandler) *AddTeamMemberHandler {
+	return &AddTeamMemberHandler{log: log, ah: ah}
+}
+
+func (h *AddTeamMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusCreated, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *AddTeamMemberHandler) do(r *http.Request) (*types.TeamMember, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+	userRef := vars["userref"]
+
+	teamMember, err := h.ah.AddTeamMember(ctx, orgRef, teamRef, userRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return teamMember, nil
+}
+
+type RemoveTeamMemberHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRemoveTeamMemberHandler(log zerolog.Logger, ah *action.ActionHandler) *RemoveTeamMemberHandler {
+	return &RemoveTeamMemberHandler{log: log, ah: ah}
+}
+
+func (h *RemoveTeamMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *RemoveTeamMemberHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+	userRef := vars["userref"]
+
+	if err := h.ah.RemoveTeamMember(ctx, orgRef, teamRef, userRef); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/common"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/lock"
+	"agola.io/agola/internal/sqlg/manager"
+)
+
+const (
+	backupLockKey = "backup"
+
+	backupCheckInterval = 1 * time.Minute
+)
+
+func (s *Configstore) backupLoop(ctx context.Context) {
+	s.log.Debug().Msg("backupLoop")
+
+	for {
+		if err := s.backup(ctx); err != nil {
+			s.log.Warn().Err(err).Msg("backup error")
+		}
+
+		sleepCh := time.NewTimer(backupCheckInterval).C
+		select {
+		case <-ctx.Done():
+			return
+		case <-sleepCh:
+		}
+	}
+}
+
+func (s *Configstore) backup(ctx context.Context) error {
+	l := s.lf.NewLock(backupLockKey)
+	if err := l.TryLock(ctx); err != nil {
+		if errors.Is(err, lock.ErrLocked) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	defer func() { _ = l.Unlock() }()
+
+	backups, err := common.GetBackups(ctx, s.ost)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// take a new backup only when the last one is older than the backup interval
+	if len(backups) > 0 && time.Since(backups[len(backups)-1].Time) < s.c.Backup.Interval {
+		return nil
+	}
+
+	dbm := manager.NewDBManager(s.log, s.d, s.lf)
+	backup, err := common.WriteBackup(ctx, dbm, sqlg.ObjectNames(s.d.ObjectsInfo()), s.ost, s.c.DataDir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	s.log.Info().Msgf("created backup %q", backup.ID)
+
+	return errors.WithStack(common.DeleteOldBackups(ctx, s.ost, s.c.Backup.Retention))
+}
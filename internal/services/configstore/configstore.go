@@ -29,6 +29,7 @@ import (
 	"github.com/sorintlab/errors"
 
 	scommon "agola.io/agola/internal/common"
+	"agola.io/agola/internal/metrics"
 	"agola.io/agola/internal/objectstorage"
 	"agola.io/agola/internal/services/common"
 	"agola.io/agola/internal/services/config"
@@ -39,7 +40,9 @@ import (
 	"agola.io/agola/internal/sqlg/lock"
 	"agola.io/agola/internal/sqlg/manager"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
+	"agola.io/agola/services/configstore/types"
 )
 
 func (s *Configstore) maintenanceModeWatcherLoop(ctx context.Context, runCtxCancel context.CancelFunc, maintenanceMode bool) {
@@ -141,7 +144,7 @@ func NewConfigstore(ctx context.Context, log zerolog.Logger, c *config.Configsto
 		return nil, errors.Wrap(err, "failed to setup db")
 	}
 
-	ah := action.NewActionHandler(log, d, lf)
+	ah := action.NewActionHandler(log, d, ost, lf)
 	cs.ah = ah
 
 	return cs, nil
@@ -152,6 +155,11 @@ func (s *Configstore) setupDefaultRouter() http.Handler {
 	maintenanceModeHandler := api.NewMaintenanceModeHandler(s.log, s.ah)
 	exportHandler := api.NewExportHandler(s.log, s.ah)
 	importHandler := api.NewImportHandler(s.log, s.ah)
+	backupsHandler := api.NewBackupsHandler(s.log, s.ah)
+	restoreBackupHandler := api.NewRestoreBackupHandler(s.log, s.ah)
+	scopedExportHandler := api.NewScopedExportHandler(s.log, s.ah)
+	scopedImportHandler := api.NewScopedImportHandler(s.log, s.ah)
+	deleteScopedImportHandler := api.NewDeleteScopedImportHandler(s.log, s.ah)
 
 	projectGroupHandler := api.NewProjectGroupHandler(s.log, s.ah)
 	projectGroupSubgroupsHandler := api.NewProjectGroupSubgroupsHandler(s.log, s.ah)
@@ -206,6 +214,15 @@ func (s *Configstore) setupDefaultRouter() http.Handler {
 	addOrgMemberHandler := api.NewAddOrgMemberHandler(s.log, s.ah)
 	removeOrgMemberHandler := api.NewRemoveOrgMemberHandler(s.log, s.ah)
 
+	teamsHandler := api.NewTeamsHandler(s.log, s.ah)
+	teamHandler := api.NewTeamHandler(s.log, s.ah)
+	createTeamHandler := api.NewCreateTeamHandler(s.log, s.ah)
+	updateTeamHandler := api.NewUpdateTeamHandler(s.log, s.ah)
+	deleteTeamHandler := api.NewDeleteTeamHandler(s.log, s.ah)
+	teamMembersHandler := api.NewTeamMembersHandler(s.log, s.ah)
+	addTeamMemberHandler := api.NewAddTeamMemberHandler(s.log, s.ah)
+	removeTeamMemberHandler := api.NewRemoveTeamMemberHandler(s.log, s.ah)
+
 	remoteSourceHandler := api.NewRemoteSourceHandler(s.log, s.ah)
 	remoteSourcesHandler := api.NewRemoteSourcesHandler(s.log, s.ah)
 	createRemoteSourceHandler := api.NewCreateRemoteSourceHandler(s.log, s.ah)
@@ -218,11 +235,34 @@ func (s *Configstore) setupDefaultRouter() http.Handler {
 	deleteOrgInvitationHandler := api.NewDeleteOrgInvitationHandler(s.log, s.ah)
 	orgInvitationHandler := api.NewOrgInvitationHandler(s.log, s.ah)
 
+	roleBindingsHandler := api.NewRoleBindingsHandler(s.log, s.ah)
+	setUserRoleBindingHandler := api.NewSetRoleBindingHandler(s.log, s.ah, types.ObjectKindUser)
+	deleteUserRoleBindingHandler := api.NewDeleteRoleBindingHandler(s.log, s.ah, types.ObjectKindUser)
+	setTeamRoleBindingHandler := api.NewSetRoleBindingHandler(s.log, s.ah, types.ObjectKindTeam)
+	deleteTeamRoleBindingHandler := api.NewDeleteRoleBindingHandler(s.log, s.ah, types.ObjectKindTeam)
+
+	auditEventsHandler := api.NewAuditEventsHandler(s.log, s.ah)
+	createAuditEventHandler := api.NewCreateAuditEventHandler(s.log, s.ah)
+
+	projectReceivedWebhooksHandler := api.NewProjectReceivedWebhooksHandler(s.log, s.ah)
+	createReceivedWebhookHandler := api.NewCreateReceivedWebhookHandler(s.log, s.ah)
+	claimReceivedWebhookHandler := api.NewClaimReceivedWebhookHandler(s.log, s.ah)
+	updateReceivedWebhookResultHandler := api.NewUpdateReceivedWebhookResultHandler(s.log, s.ah)
+
+	projectMirrorHandler := api.NewProjectMirrorHandler(s.log, s.ah)
+	claimProjectMirrorHandler := api.NewClaimProjectMirrorHandler(s.log, s.ah)
+	updateProjectMirrorPollResultHandler := api.NewUpdateProjectMirrorPollResultHandler(s.log, s.ah)
+
+	userRoleHandler := api.NewUserRoleHandler(s.log, s.ah)
+
 	authHandler := handlers.NewInternalAuthChecker(s.log, s.c.APIToken)
 
 	router := mux.NewRouter()
+	router.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
 	apirouter := router.PathPrefix("/api/v1alpha").Subrouter().UseEncodedPath()
 
+	apirouter.Use(tracing.NewHTTPMiddleware("configstore"))
+	apirouter.Use(metrics.NewHTTPMiddleware("configstore"))
 	apirouter.Use(authHandler)
 
 	apirouter.Handle("/projectgroups/{projectgroupref}", projectGroupHandler).Methods("GET")
@@ -255,6 +295,19 @@ func (s *Configstore) setupDefaultRouter() http.Handler {
 	apirouter.Handle("/projectgroups/{projectgroupref}/variables/{variablename}", deleteVariableHandler).Methods("DELETE")
 	apirouter.Handle("/projects/{projectref}/variables/{variablename}", deleteVariableHandler).Methods("DELETE")
 
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings", roleBindingsHandler).Methods("GET")
+	apirouter.Handle("/projects/{projectref}/rolebindings", roleBindingsHandler).Methods("GET")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/users/{subjectref}", setUserRoleBindingHandler).Methods("PUT")
+	apirouter.Handle("/projects/{projectref}/rolebindings/users/{subjectref}", setUserRoleBindingHandler).Methods("PUT")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/users/{subjectref}", deleteUserRoleBindingHandler).Methods("DELETE")
+	apirouter.Handle("/projects/{projectref}/rolebindings/users/{subjectref}", deleteUserRoleBindingHandler).Methods("DELETE")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/teams/{subjectref}", setTeamRoleBindingHandler).Methods("PUT")
+	apirouter.Handle("/projects/{projectref}/rolebindings/teams/{subjectref}", setTeamRoleBindingHandler).Methods("PUT")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/teams/{subjectref}", deleteTeamRoleBindingHandler).Methods("DELETE")
+	apirouter.Handle("/projects/{projectref}/rolebindings/teams/{subjectref}", deleteTeamRoleBindingHandler).Methods("DELETE")
+	apirouter.Handle("/projectgroups/{projectgroupref}/userroles/{userref}", userRoleHandler).Methods("GET")
+	apirouter.Handle("/projects/{projectref}/userroles/{userref}", userRoleHandler).Methods("GET")
+
 	apirouter.Handle("/users/{userref}", userHandler).Methods("GET")
 	apirouter.Handle("/users", usersHandler).Methods("GET")
 	apirouter.Handle("/users", createUserHandler).Methods("POST")
@@ -281,6 +334,15 @@ func (s *Configstore) setupDefaultRouter() http.Handler {
 	apirouter.Handle("/orgs/{orgref}/members", orgMembersHandler).Methods("GET")
 	apirouter.Handle("/orgs/{orgref}/members/{userref}", addOrgMemberHandler).Methods("PUT")
 	apirouter.Handle("/orgs/{orgref}/members/{userref}", removeOrgMemberHandler).Methods("DELETE")
+
+	apirouter.Handle("/orgs/{orgref}/teams", teamsHandler).Methods("GET")
+	apirouter.Handle("/orgs/{orgref}/teams", createTeamHandler).Methods("POST")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}", teamHandler).Methods("GET")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}", updateTeamHandler).Methods("PUT")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}", deleteTeamHandler).Methods("DELETE")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}/members", teamMembersHandler).Methods("GET")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}/members/{userref}", addTeamMemberHandler).Methods("PUT")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}/members/{userref}", removeTeamMemberHandler).Methods("DELETE")
 	apirouter.Handle("/orgs/{orgref}/invitations", orgInvitationsHandler).Methods("GET")
 	apirouter.Handle("/orgs/{orgref}/invitations", createOrgInvitationHandler).Methods("POST")
 	apirouter.Handle("/orgs/{orgref}/invitations/{userref}", orgInvitationHandler).Methods("GET")
@@ -295,11 +357,28 @@ func (s *Configstore) setupDefaultRouter() http.Handler {
 
 	apirouter.Handle("/linkedaccounts", linkedAccountsHandler).Methods("GET")
 
+	apirouter.Handle("/auditevents", auditEventsHandler).Methods("GET")
+	apirouter.Handle("/auditevents", createAuditEventHandler).Methods("POST")
+
+	apirouter.Handle("/projects/{projectref}/receivedwebhooks", projectReceivedWebhooksHandler).Methods("GET")
+	apirouter.Handle("/projects/{projectref}/receivedwebhooks", createReceivedWebhookHandler).Methods("POST")
+	apirouter.Handle("/receivedwebhooks/claim", claimReceivedWebhookHandler).Methods("POST")
+	apirouter.Handle("/receivedwebhooks/{receivedwebhookid}/result", updateReceivedWebhookResultHandler).Methods("PUT")
+
+	apirouter.Handle("/projects/{projectref}/mirror", projectMirrorHandler).Methods("GET")
+	apirouter.Handle("/projectmirrors/claim", claimProjectMirrorHandler).Methods("POST")
+	apirouter.Handle("/projectmirrors/{projectmirrorid}/pollresult", updateProjectMirrorPollResultHandler).Methods("PUT")
+
 	apirouter.Handle("/maintenance", maintenanceStatusHandler).Methods("GET")
 	apirouter.Handle("/maintenance", maintenanceModeHandler).Methods("PUT", "DELETE")
 
 	apirouter.Handle("/export", exportHandler).Methods("GET")
 	apirouter.Handle("/import", importHandler).Methods("POST")
+	apirouter.Handle("/backups", backupsHandler).Methods("GET")
+	apirouter.Handle("/backups/{backupid}/restore", restoreBackupHandler).Methods("POST")
+	apirouter.Handle("/scopedexport", scopedExportHandler).Methods("GET")
+	apirouter.Handle("/scopedimport", scopedImportHandler).Methods("POST")
+	apirouter.Handle("/scopedimport", deleteScopedImportHandler).Methods("DELETE")
 
 	mainrouter := mux.NewRouter()
 	mainrouter.PathPrefix("/").Handler(router)
@@ -312,15 +391,23 @@ func (s *Configstore) setupMaintenanceRouter() http.Handler {
 	maintenanceModeHandler := api.NewMaintenanceModeHandler(s.log, s.ah)
 	exportHandler := api.NewExportHandler(s.log, s.ah)
 	importHandler := api.NewImportHandler(s.log, s.ah)
+	backupsHandler := api.NewBackupsHandler(s.log, s.ah)
+	restoreBackupHandler := api.NewRestoreBackupHandler(s.log, s.ah)
 
 	router := mux.NewRouter()
+	router.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
 	apirouter := router.PathPrefix("/api/v1alpha").Subrouter().UseEncodedPath()
 
+	apirouter.Use(tracing.NewHTTPMiddleware("configstore"))
+	apirouter.Use(metrics.NewHTTPMiddleware("configstore"))
+
 	apirouter.Handle("/maintenance", maintenanceStatusHandler).Methods("GET")
 	apirouter.Handle("/maintenance", maintenanceModeHandler).Methods("PUT", "DELETE")
 
 	apirouter.Handle("/export", exportHandler).Methods("GET")
 	apirouter.Handle("/import", importHandler).Methods("POST")
+	apirouter.Handle("/backups", backupsHandler).Methods("GET")
+	apirouter.Handle("/backups/{backupid}/restore", restoreBackupHandler).Methods("POST")
 
 	mainrouter := mux.NewRouter()
 	mainrouter.PathPrefix("/").Handler(router)
@@ -377,6 +464,10 @@ func (s *Configstore) run(ctx context.Context) error {
 		mainrouter = s.setupDefaultRouter()
 
 		util.GoWait(&wg, func() { s.maintenanceModeWatcherLoop(ctx, cancel, s.maintenanceMode) })
+		util.GoWait(&wg, func() { s.receivedWebhooksCleanerLoop(ctx, s.c.ReceivedWebhookExpireInterval) })
+		if s.c.Backup.Enabled {
+			util.GoWait(&wg, func() { s.backupLoop(ctx) })
+		}
 
 		// TODO(sgotti) wait for all goroutines exiting
 	}
@@ -32,10 +32,12 @@ import (
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
 
+	"agola.io/agola/internal/services/common"
 	"agola.io/agola/internal/services/config"
 	"agola.io/agola/internal/services/configstore/action"
 	serrors "agola.io/agola/internal/services/errors"
 	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/manager"
 	"agola.io/agola/internal/sqlg/sql"
 	"agola.io/agola/internal/testutil"
 	"agola.io/agola/internal/util"
@@ -152,6 +154,28 @@ func getVariables(ctx context.Context, cs *Configstore) ([]*types.Variable, erro
 	return variables, errors.WithStack(err)
 }
 
+func getTeams(ctx context.Context, cs *Configstore) ([]*types.Team, error) {
+	var teams []*types.Team
+	err := cs.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		teams, err = cs.d.GetAllTeams(tx)
+		return errors.WithStack(err)
+	})
+
+	return teams, errors.WithStack(err)
+}
+
+func getTeamMembers(ctx context.Context, cs *Configstore) ([]*types.TeamMember, error) {
+	var teamMembers []*types.TeamMember
+	err := cs.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		teamMembers, err = cs.d.GetAllTeamMembers(tx)
+		return errors.WithStack(err)
+	})
+
+	return teamMembers, errors.WithStack(err)
+}
+
 func cmpDiffObject(x, y interface{}) cmp.Comparison {
 	// Since postgres has microsecond time precision while go has nanosecond time precision we should check times with a microsecond margin
 	return cmp.DeepEqual(x, y, cmpopts.IgnoreFields(sqlg.ObjectMeta{}, "TxID"), cmpopts.EquateApproxTime(1*time.Microsecond))
@@ -176,6 +200,8 @@ func TestExportImport(t *testing.T) {
 	var expectedProjectsCount int
 	var expectedSecretsCount int
 	var expectedVariablesCount int
+	var expectedTeamsCount int
+	var expectedTeamMembersCount int
 
 	_, err := cs.ah.CreateRemoteSource(ctx, &action.CreateUpdateRemoteSourceRequest{Name: "rs01", Type: types.RemoteSourceTypeGitea, AuthType: types.RemoteSourceAuthTypePassword, APIURL: "http://example.com"})
 	testutil.NilError(t, err)
@@ -232,6 +258,19 @@ func TestExportImport(t *testing.T) {
 
 	expectedVariablesCount++
 
+	_, err = cs.ah.AddOrgMember(ctx, org.ID, user.ID, types.MemberRoleMember)
+	testutil.NilError(t, err)
+
+	_, err = cs.ah.CreateTeam(ctx, &action.CreateTeamRequest{OrgRef: org.ID, Name: "team01"})
+	testutil.NilError(t, err)
+
+	expectedTeamsCount++
+
+	_, err = cs.ah.AddTeamMember(ctx, org.ID, "team01", user.ID)
+	testutil.NilError(t, err)
+
+	expectedTeamMembersCount++
+
 	remoteSources, err := getRemoteSources(ctx, cs)
 	testutil.NilError(t, err)
 
@@ -253,6 +292,12 @@ func TestExportImport(t *testing.T) {
 	variables, err := getVariables(ctx, cs)
 	testutil.NilError(t, err)
 
+	teams, err := getTeams(ctx, cs)
+	testutil.NilError(t, err)
+
+	teamMembers, err := getTeamMembers(ctx, cs)
+	testutil.NilError(t, err)
+
 	assert.Assert(t, cmp.Len(remoteSources, expectedRemoteSourcesCount))
 	assert.Assert(t, cmp.Len(users, expectedUsersCount))
 	assert.Assert(t, cmp.Len(orgs, expectedOrgsCount))
@@ -260,6 +305,8 @@ func TestExportImport(t *testing.T) {
 	assert.Assert(t, cmp.Len(projects, expectedProjectsCount))
 	assert.Assert(t, cmp.Len(secrets, expectedSecretsCount))
 	assert.Assert(t, cmp.Len(variables, expectedVariablesCount))
+	assert.Assert(t, cmp.Len(teams, expectedTeamsCount))
+	assert.Assert(t, cmp.Len(teamMembers, expectedTeamMembersCount))
 
 	var export bytes.Buffer
 	err = cs.ah.Export(ctx, &export)
@@ -313,6 +360,12 @@ func TestExportImport(t *testing.T) {
 	newVariables, err := getVariables(ctx, cs)
 	testutil.NilError(t, err)
 
+	newTeams, err := getTeams(ctx, cs)
+	testutil.NilError(t, err)
+
+	newTeamMembers, err := getTeamMembers(ctx, cs)
+	testutil.NilError(t, err)
+
 	assert.Assert(t, cmpDiffObject(remoteSources, newRemoteSources))
 	assert.Assert(t, cmpDiffObject(users, newUsers))
 	assert.Assert(t, cmpDiffObject(orgs, newOrgs))
@@ -320,6 +373,255 @@ func TestExportImport(t *testing.T) {
 	assert.Assert(t, cmpDiffObject(projects, newProjects))
 	assert.Assert(t, cmpDiffObject(secrets, newSecrets))
 	assert.Assert(t, cmpDiffObject(variables, newVariables))
+	assert.Assert(t, cmpDiffObject(teams, newTeams))
+	assert.Assert(t, cmpDiffObject(teamMembers, newTeamMembers))
+}
+
+func TestScopedExportImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, t.TempDir())
+	cs2 := setupConfigstore(ctx, t, log, t.TempDir())
+
+	t.Logf("starting cs")
+	go func() { _ = cs.Run(ctx) }()
+	go func() { _ = cs2.Run(ctx) }()
+
+	org, err := cs.ah.CreateOrg(ctx, &action.CreateOrgRequest{Name: "org01", Visibility: types.VisibilityPublic})
+	testutil.NilError(t, err)
+
+	_, err = cs.ah.CreateProjectGroup(ctx, &action.CreateUpdateProjectGroupRequest{Name: "projectgroup01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("org", org.Name)}, Visibility: types.VisibilityPublic})
+	testutil.NilError(t, err)
+
+	project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("org", org.Name, "projectgroup01")}, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+	testutil.NilError(t, err)
+
+	_, err = cs.ah.CreateSecret(ctx, &action.CreateUpdateSecretRequest{Name: "secret01", Parent: types.Parent{Kind: types.ObjectKindProject, ID: path.Join("org", org.Name, "projectgroup01", "project01")}, Type: types.SecretTypeInternal, Data: map[string]string{"secretvar01": "secretval01"}})
+	testutil.NilError(t, err)
+
+	_, err = cs.ah.CreateVariable(ctx, &action.CreateUpdateVariableRequest{Name: "variable01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("org", org.Name, "projectgroup01")}, Values: []types.VariableValue{{SecretName: "secret01", SecretVar: "secretvar01"}}})
+	testutil.NilError(t, err)
+
+	_, err = cs.ah.CreateTeam(ctx, &action.CreateTeamRequest{OrgRef: org.ID, Name: "team01"})
+	testutil.NilError(t, err)
+
+	user, err := cs2.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+
+	t.Run("test org scoped export and import", func(t *testing.T) {
+		objs, err := cs.ah.GetScopedExportObjects(ctx, &action.ScopedExportRequest{Kind: types.ObjectKindOrg, Ref: org.Name})
+		testutil.NilError(t, err)
+
+		var export bytes.Buffer
+		err = cs.ah.WriteExportObjects(objs, &export)
+		testutil.NilError(t, err)
+
+		res, err := cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{CreatorUserID: user.ID}, bytes.NewReader(export.Bytes()))
+		testutil.NilError(t, err)
+
+		newOrg, err := cs2.ah.GetOrg(ctx, org.Name)
+		testutil.NilError(t, err)
+		assert.Assert(t, newOrg.ID != org.ID)
+
+		newProject, err := cs2.ah.GetProject(ctx, path.Join("org", org.Name, "projectgroup01", "project01"))
+		testutil.NilError(t, err)
+		assert.Assert(t, newProject.Project.ID != project.Project.ID)
+		assert.Equal(t, res.ProjectIDs[project.Project.ID], newProject.Project.ID)
+
+		secrets, err := cs2.ah.GetSecrets(ctx, types.ObjectKindProject, newProject.Project.ID, false)
+		testutil.NilError(t, err)
+		assert.Assert(t, cmp.Len(secrets.Secrets, 1))
+		assert.Equal(t, secrets.Secrets[0].Data["secretvar01"], "secretval01")
+
+		variables, err := cs2.ah.GetVariables(ctx, types.ObjectKindProjectGroup, path.Join("org", org.Name, "projectgroup01"), false)
+		testutil.NilError(t, err)
+		assert.Assert(t, cmp.Len(variables.Variables, 1))
+
+		teams, err := cs2.ah.GetOrgTeams(ctx, newOrg.ID)
+		testutil.NilError(t, err)
+		assert.Assert(t, cmp.Len(teams, 1))
+		assert.Equal(t, teams[0].Name, "team01")
+
+		members, err := cs2.ah.GetOrgMembers(ctx, &action.GetOrgMembersRequest{OrgRef: newOrg.ID})
+		testutil.NilError(t, err)
+		assert.Assert(t, cmp.Len(members.OrgMembers, 1))
+		assert.Equal(t, members.OrgMembers[0].User.ID, user.ID)
+		assert.Equal(t, members.OrgMembers[0].Role, types.MemberRoleOwner)
+
+		// importing again the same org must fail
+		_, err = cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{CreatorUserID: user.ID}, bytes.NewReader(export.Bytes()))
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+
+		// deleting the imported org must delete all its imported objects
+		assert.Equal(t, res.Kind, types.ObjectKindOrg)
+		assert.Equal(t, res.ID, newOrg.ID)
+		err = cs2.ah.DeleteScopedImport(ctx, &action.ScopedExportRequest{Kind: res.Kind, Ref: res.ID})
+		testutil.NilError(t, err)
+
+		_, err = cs2.ah.GetOrg(ctx, org.Name)
+		assert.Assert(t, util.APIErrorIs(err, util.ErrNotExist))
+
+		err = cs2.d.Do(ctx, func(tx *sql.Tx) error {
+			secrets, err := cs2.d.GetSecrets(tx, newProject.Project.ID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			assert.Assert(t, cmp.Len(secrets, 0))
+
+			teams, err := cs2.d.GetOrgTeams(tx, newOrg.ID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			assert.Assert(t, cmp.Len(teams, 0))
+
+			return nil
+		})
+		testutil.NilError(t, err)
+
+		// the org can be imported again
+		_, err = cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{CreatorUserID: user.ID}, bytes.NewReader(export.Bytes()))
+		testutil.NilError(t, err)
+	})
+
+	t.Run("test project group scoped export and import", func(t *testing.T) {
+		objs, err := cs.ah.GetScopedExportObjects(ctx, &action.ScopedExportRequest{Kind: types.ObjectKindProjectGroup, Ref: path.Join("org", org.Name, "projectgroup01")})
+		testutil.NilError(t, err)
+
+		var export bytes.Buffer
+		err = cs.ah.WriteExportObjects(objs, &export)
+		testutil.NilError(t, err)
+
+		// a parent project group is required
+		_, err = cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{CreatorUserID: user.ID}, bytes.NewReader(export.Bytes()))
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+
+		res, err := cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{ParentRef: path.Join("user", user.Name)}, bytes.NewReader(export.Bytes()))
+		testutil.NilError(t, err)
+
+		newProject, err := cs2.ah.GetProject(ctx, path.Join("user", user.Name, "projectgroup01", "project01"))
+		testutil.NilError(t, err)
+		assert.Equal(t, res.ProjectIDs[project.Project.ID], newProject.Project.ID)
+
+		secrets, err := cs2.ah.GetSecrets(ctx, types.ObjectKindProject, newProject.Project.ID, false)
+		testutil.NilError(t, err)
+		assert.Assert(t, cmp.Len(secrets.Secrets, 1))
+
+		// importing again the same project group must fail
+		_, err = cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{ParentRef: path.Join("user", user.Name)}, bytes.NewReader(export.Bytes()))
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+
+		// deleting the imported project group must delete all its imported objects
+		assert.Equal(t, res.Kind, types.ObjectKindProjectGroup)
+		err = cs2.ah.DeleteScopedImport(ctx, &action.ScopedExportRequest{Kind: res.Kind, Ref: res.ID})
+		testutil.NilError(t, err)
+
+		_, err = cs2.ah.GetProject(ctx, newProject.Project.ID)
+		assert.Assert(t, util.APIErrorIs(err, util.ErrNotExist))
+
+		// the project group can be imported again
+		_, err = cs2.ah.ImportScoped(ctx, &action.ScopedImportRequest{ParentRef: path.Join("user", user.Name)}, bytes.NewReader(export.Bytes()))
+		testutil.NilError(t, err)
+	})
+}
+
+func TestBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, dir)
+	cs.c.Backup = config.Backup{Enabled: true, Interval: 1 * time.Hour, Retention: 2}
+
+	user, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+
+	_, err = cs.ah.CreateOrg(ctx, &action.CreateOrgRequest{Name: "org01", Visibility: types.VisibilityPublic, CreatorUserID: user.ID})
+	testutil.NilError(t, err)
+
+	users, err := getUsers(ctx, cs)
+	testutil.NilError(t, err)
+
+	orgs, err := getOrgs(ctx, cs)
+	testutil.NilError(t, err)
+
+	projectGroups, err := getProjectGroups(ctx, cs)
+	testutil.NilError(t, err)
+
+	err = cs.backup(ctx)
+	testutil.NilError(t, err)
+
+	// a new backup isn't taken before the backup interval
+	err = cs.backup(ctx)
+	testutil.NilError(t, err)
+
+	backups, err := cs.ah.GetBackups(ctx)
+	testutil.NilError(t, err)
+	assert.Assert(t, cmp.Len(backups, 1))
+
+	backupID := backups[0].ID
+
+	// create more backups and check that only the last ones are kept
+	dbm := manager.NewDBManager(log, cs.d, cs.lf)
+	for i := 0; i < 2; i++ {
+		_, err := common.WriteBackup(ctx, dbm, sqlg.ObjectNames(cs.d.ObjectsInfo()), cs.ost, cs.c.DataDir)
+		testutil.NilError(t, err)
+	}
+
+	err = common.DeleteOldBackups(ctx, cs.ost, cs.c.Backup.Retention)
+	testutil.NilError(t, err)
+
+	backups, err = cs.ah.GetBackups(ctx)
+	testutil.NilError(t, err)
+	assert.Assert(t, cmp.Len(backups, 2))
+	assert.Assert(t, backups[0].ID > backupID)
+
+	backupID = backups[1].ID
+
+	err = cs.ah.RestoreBackup(ctx, backupID)
+	assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest), "expected bad request error, got: %v", err)
+
+	cs.ah.SetMaintenanceMode(true)
+
+	// restore in a not empty database must fail
+	err = cs.ah.RestoreBackup(ctx, backupID)
+	assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest), "expected bad request error, got: %v", err)
+
+	err = cs.ah.RestoreBackup(ctx, "notexistingbackup")
+	assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest), "expected bad request error, got: %v", err)
+
+	// recreate an empty database
+	err = dbm.Drop(ctx)
+	testutil.NilError(t, err)
+	err = dbm.Setup(ctx)
+	testutil.NilError(t, err)
+	err = dbm.Create(ctx, cs.d.DDL(), cs.d.Version())
+	testutil.NilError(t, err)
+
+	err = cs.ah.RestoreBackup(ctx, time.Now().UTC().Format("20060102T150405.000000000Z"))
+	assert.Assert(t, util.APIErrorIs(err, util.ErrNotExist), "expected not exist error, got: %v", err)
+
+	err = cs.ah.RestoreBackup(ctx, backupID)
+	testutil.NilError(t, err)
+
+	cs.ah.SetMaintenanceMode(false)
+
+	newUsers, err := getUsers(ctx, cs)
+	testutil.NilError(t, err)
+
+	newOrgs, err := getOrgs(ctx, cs)
+	testutil.NilError(t, err)
+
+	newProjectGroups, err := getProjectGroups(ctx, cs)
+	testutil.NilError(t, err)
+
+	assert.Assert(t, cmpDiffObject(users, newUsers))
+	assert.Assert(t, cmpDiffObject(orgs, newOrgs))
+	assert.Assert(t, cmpDiffObject(projectGroups, newProjectGroups))
 }
 
 func TestUser(t *testing.T) {
@@ -1955,3 +2257,503 @@ func TestOrgInvitation(t *testing.T) {
 		})
 	}
 }
+
+func TestRoleBindings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, dir)
+
+	t.Logf("starting cs")
+	go func() { _ = cs.Run(ctx) }()
+
+	owner, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+	user, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user02"})
+	testutil.NilError(t, err)
+
+	org, err := cs.ah.CreateOrg(ctx, &action.CreateOrgRequest{Name: "org01", Visibility: types.VisibilityPublic, CreatorUserID: owner.ID})
+	testutil.NilError(t, err)
+
+	pg, err := cs.ah.CreateProjectGroup(ctx, &action.CreateUpdateProjectGroupRequest{Name: "projectgroup01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("org", org.Name)}, Visibility: types.VisibilityPublic})
+	testutil.NilError(t, err)
+	project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: pg.ProjectGroup.ID}, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+	testutil.NilError(t, err)
+
+	t.Run("test user without role bindings has no role", func(t *testing.T) {
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRole(""))
+	})
+
+	t.Run("test org owner has owner role", func(t *testing.T) {
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, owner.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRoleOwner)
+	})
+
+	t.Run("test org member role is inherited", func(t *testing.T) {
+		_, err := cs.ah.AddOrgMember(ctx, org.ID, user.ID, types.MemberRoleViewer)
+		testutil.NilError(t, err)
+
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRoleViewer)
+	})
+
+	t.Run("test project group role binding is inherited by projects", func(t *testing.T) {
+		_, err := cs.ah.SetRoleBinding(ctx, &action.SetRoleBindingRequest{Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: pg.ProjectGroup.ID}, SubjectKind: types.ObjectKindUser, SubjectRef: user.Name, Role: types.MemberRoleMaintainer})
+		testutil.NilError(t, err)
+
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRoleMaintainer)
+	})
+
+	t.Run("test lower project role binding doesn't override inherited role", func(t *testing.T) {
+		_, err := cs.ah.SetRoleBinding(ctx, &action.SetRoleBindingRequest{Parent: types.Parent{Kind: types.ObjectKindProject, ID: project.Project.ID}, SubjectKind: types.ObjectKindUser, SubjectRef: user.Name, Role: types.MemberRoleDeveloper})
+		testutil.NilError(t, err)
+
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRoleMaintainer)
+	})
+
+	t.Run("test project role bindings tree", func(t *testing.T) {
+		res, err := cs.ah.GetRoleBindings(ctx, types.ObjectKindProject, project.Project.ID, true)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, len(res.RoleBindings), 2)
+		for _, rb := range res.RoleBindings {
+			assert.Equal(t, res.SubjectNames[rb.ID], user.Name)
+		}
+	})
+
+	t.Run("test owner role cannot be assigned with a role binding", func(t *testing.T) {
+		expectedErr := util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("role %q cannot be assigned to project groups or projects", types.MemberRoleOwner), serrors.InvalidRole())
+		_, err := cs.ah.SetRoleBinding(ctx, &action.SetRoleBindingRequest{Parent: types.Parent{Kind: types.ObjectKindProject, ID: project.Project.ID}, SubjectKind: types.ObjectKindUser, SubjectRef: user.Name, Role: types.MemberRoleOwner})
+		assert.Error(t, err, expectedErr.Error())
+	})
+
+	t.Run("test delete project group role binding", func(t *testing.T) {
+		err := cs.ah.DeleteRoleBinding(ctx, types.ObjectKindProjectGroup, pg.ProjectGroup.ID, types.ObjectKindUser, user.Name)
+		testutil.NilError(t, err)
+
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRoleDeveloper)
+	})
+
+	t.Run("test role bindings are removed when the user is deleted", func(t *testing.T) {
+		err := cs.ah.DeleteUser(ctx, user.ID)
+		testutil.NilError(t, err)
+
+		res, err := cs.ah.GetRoleBindings(ctx, types.ObjectKindProject, project.Project.ID, true)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, len(res.RoleBindings), 0)
+	})
+}
+
+func TestTeams(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, dir)
+
+	t.Logf("starting cs")
+	go func() { _ = cs.Run(ctx) }()
+
+	owner, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+	user, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user02"})
+	testutil.NilError(t, err)
+
+	org, err := cs.ah.CreateOrg(ctx, &action.CreateOrgRequest{Name: "org01", Visibility: types.VisibilityPublic, CreatorUserID: owner.ID})
+	testutil.NilError(t, err)
+
+	project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("org", org.Name)}, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+	testutil.NilError(t, err)
+
+	team, err := cs.ah.CreateTeam(ctx, &action.CreateTeamRequest{OrgRef: org.Name, Name: "team01"})
+	testutil.NilError(t, err)
+
+	t.Run("test create duplicated team", func(t *testing.T) {
+		expectedErr := util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("team %q already exists", "team01"), serrors.TeamAlreadyExists())
+		_, err := cs.ah.CreateTeam(ctx, &action.CreateTeamRequest{OrgRef: org.Name, Name: "team01"})
+		assert.Error(t, err, expectedErr.Error())
+	})
+
+	t.Run("test add user not org member to team", func(t *testing.T) {
+		expectedErr := util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("user %q isn't a member of org %q", user.Name, org.Name), serrors.UserNotOrgMember())
+		_, err := cs.ah.AddTeamMember(ctx, org.Name, team.Name, user.Name)
+		assert.Error(t, err, expectedErr.Error())
+	})
+
+	t.Run("test team role binding is applied to team members", func(t *testing.T) {
+		_, err := cs.ah.AddOrgMember(ctx, org.Name, user.Name, types.MemberRoleViewer)
+		testutil.NilError(t, err)
+		_, err = cs.ah.AddTeamMember(ctx, org.Name, team.Name, user.Name)
+		testutil.NilError(t, err)
+
+		_, err = cs.ah.SetRoleBinding(ctx, &action.SetRoleBindingRequest{Parent: types.Parent{Kind: types.ObjectKindProject, ID: project.Project.ID}, SubjectKind: types.ObjectKindTeam, SubjectRef: team.Name, Role: types.MemberRoleDeveloper})
+		testutil.NilError(t, err)
+
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRoleDeveloper)
+	})
+
+	t.Run("test removed org member is removed from org teams", func(t *testing.T) {
+		err := cs.ah.RemoveOrgMember(ctx, org.Name, user.Name)
+		testutil.NilError(t, err)
+
+		users, err := cs.ah.GetTeamMembers(ctx, org.Name, team.Name)
+		testutil.NilError(t, err)
+		assert.Equal(t, len(users), 0)
+
+		role, err := cs.ah.GetUserRole(ctx, types.ObjectKindProject, project.Project.ID, user.ID)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, role, types.MemberRole(""))
+	})
+
+	t.Run("test delete team removes its role bindings", func(t *testing.T) {
+		err := cs.ah.DeleteTeam(ctx, org.Name, team.Name)
+		testutil.NilError(t, err)
+
+		res, err := cs.ah.GetRoleBindings(ctx, types.ObjectKindProject, project.Project.ID, false)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, len(res.RoleBindings), 0)
+	})
+}
+
+func TestAuditEvents(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, dir)
+
+	t.Logf("starting cs")
+	go func() { _ = cs.Run(ctx) }()
+
+	user, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+
+	org, err := cs.ah.CreateOrg(ctx, &action.CreateOrgRequest{Name: "org01", Visibility: types.VisibilityPublic, CreatorUserID: user.ID})
+	testutil.NilError(t, err)
+
+	project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("org", org.Name)}, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+	testutil.NilError(t, err)
+
+	userProject, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project02", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("user", user.Name)}, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+	testutil.NilError(t, err)
+
+	reqs := []*action.CreateAuditEventRequest{
+		{ActorUserID: user.ID, ActorUserName: user.Name, Action: types.AuditActionOrgCreate, TargetKind: types.ObjectKindOrg, TargetID: org.ID, TargetName: org.Name, ScopeKind: types.ObjectKindOrg, ScopeID: org.ID},
+		{ActorUserID: user.ID, ActorUserName: user.Name, Action: types.AuditActionSecretCreate, TargetKind: types.ObjectKindSecret, TargetName: "secret01", ScopeKind: types.ObjectKindProject, ScopeID: project.Project.ID},
+		{ActorUserID: user.ID, ActorUserName: user.Name, Action: types.AuditActionSecretDelete, TargetKind: types.ObjectKindSecret, TargetName: "secret01", ScopeKind: types.ObjectKindProject, ScopeID: project.Project.ID},
+		{ActorUserID: user.ID, ActorUserName: user.Name, Action: types.AuditActionVariableCreate, TargetKind: types.ObjectKindVariable, TargetName: "variable01", ScopeKind: types.ObjectKindProject, ScopeID: userProject.Project.ID},
+	}
+	for _, req := range reqs {
+		_, err := cs.ah.CreateAuditEvent(ctx, req)
+		testutil.NilError(t, err)
+	}
+
+	t.Run("test organization is resolved from scope", func(t *testing.T) {
+		res, err := cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 4)
+
+		expectedOrgIDs := []string{org.ID, org.ID, org.ID, ""}
+		for i, auditEvent := range res.AuditEvents {
+			assert.Equal(t, auditEvent.OrganizationID, expectedOrgIDs[i])
+			assert.Equal(t, auditEvent.Sequence, uint64(i+1))
+		}
+	})
+
+	t.Run("test get org audit events", func(t *testing.T) {
+		res, err := cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{OrgRef: org.Name})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 3)
+	})
+
+	t.Run("test get audit events filtered by action", func(t *testing.T) {
+		res, err := cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{Actions: []types.AuditAction{types.AuditActionSecretCreate, types.AuditActionSecretDelete}})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 2)
+		for _, auditEvent := range res.AuditEvents {
+			assert.Equal(t, auditEvent.TargetName, "secret01")
+		}
+	})
+
+	t.Run("test get audit events with time range", func(t *testing.T) {
+		until := time.Now().Add(-1 * time.Hour)
+		res, err := cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{Until: &until})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 0)
+
+		since := time.Now().Add(-1 * time.Hour)
+		res, err = cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{Since: &since})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 4)
+	})
+
+	t.Run("test get audit events pagination", func(t *testing.T) {
+		res, err := cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{Limit: 3, SortDirection: types.SortDirectionDesc})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 3)
+		assert.Equal(t, res.HasMore, true)
+		assert.Equal(t, res.AuditEvents[0].Sequence, uint64(4))
+
+		res, err = cs.ah.GetAuditEvents(ctx, &action.GetAuditEventsRequest{StartSequence: res.AuditEvents[2].Sequence, Limit: 3, SortDirection: types.SortDirectionDesc})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.AuditEvents), 1)
+		assert.Equal(t, res.HasMore, false)
+		assert.Equal(t, res.AuditEvents[0].Sequence, uint64(1))
+	})
+}
+
+func TestReceivedWebhooks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, dir)
+
+	t.Logf("starting cs")
+	go func() { _ = cs.Run(ctx) }()
+
+	user, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+
+	project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("user", user.Name)}, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+	testutil.NilError(t, err)
+
+	t.Run("test received webhooks with the same delivery id are deduplicated", func(t *testing.T) {
+		rw1, err := cs.ah.CreateReceivedWebhook(ctx, &action.CreateReceivedWebhookRequest{ProjectRef: project.Project.ID, DeliveryID: "delivery01", Event: "push", Body: []byte("body01")})
+		testutil.NilError(t, err)
+		assert.Equal(t, rw1.Status, types.ReceivedWebhookStatusPending)
+
+		rw2, err := cs.ah.CreateReceivedWebhook(ctx, &action.CreateReceivedWebhookRequest{ProjectRef: project.Project.ID, DeliveryID: "delivery01", Event: "push", Body: []byte("body01")})
+		testutil.NilError(t, err)
+		assert.Equal(t, rw2.ID, rw1.ID)
+
+		_, err = cs.ah.CreateReceivedWebhook(ctx, &action.CreateReceivedWebhookRequest{ProjectRef: project.Project.ID, DeliveryID: "delivery02", Event: "push", Body: []byte("body02")})
+		testutil.NilError(t, err)
+
+		res, err := cs.ah.GetProjectReceivedWebhooks(ctx, &action.GetProjectReceivedWebhooksRequest{ProjectRef: project.Project.ID})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.ReceivedWebhooks), 2)
+	})
+
+	t.Run("test create received webhook without delivery id", func(t *testing.T) {
+		_, err := cs.ah.CreateReceivedWebhook(ctx, &action.CreateReceivedWebhookRequest{ProjectRef: project.Project.ID})
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+	})
+
+	t.Run("test claim and process received webhooks", func(t *testing.T) {
+		rw, err := cs.ah.ClaimReceivedWebhook(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Equal(t, rw.DeliveryID, "delivery01")
+		assert.Equal(t, rw.Status, types.ReceivedWebhookStatusProcessing)
+		assert.Equal(t, rw.Attempts, 1)
+
+		// received webhooks of the same project are processed in order
+		rwn, err := cs.ah.ClaimReceivedWebhook(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Assert(t, rwn == nil)
+
+		// retry it
+		_, err = cs.ah.UpdateReceivedWebhookResult(ctx, rw.ID, &action.UpdateReceivedWebhookResultRequest{Attempts: rw.Attempts, Status: types.ReceivedWebhookStatusPending, NextAttemptTime: time.Now(), OutcomeMessage: "temporary error"})
+		testutil.NilError(t, err)
+
+		// a result for a previous attempt is rejected
+		_, err = cs.ah.UpdateReceivedWebhookResult(ctx, rw.ID, &action.UpdateReceivedWebhookResultRequest{Attempts: rw.Attempts, Status: types.ReceivedWebhookStatusProcessed})
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+
+		rw, err = cs.ah.ClaimReceivedWebhook(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Equal(t, rw.DeliveryID, "delivery01")
+		assert.Equal(t, rw.Attempts, 2)
+
+		rw, err = cs.ah.UpdateReceivedWebhookResult(ctx, rw.ID, &action.UpdateReceivedWebhookResultRequest{Attempts: rw.Attempts, Status: types.ReceivedWebhookStatusProcessed, Outcome: types.ReceivedWebhookOutcomeRunsCreated, RunNumbers: []uint64{1, 2}})
+		testutil.NilError(t, err)
+		assert.DeepEqual(t, rw.RunNumbers, []uint64{1, 2})
+
+		rw2, err := cs.ah.ClaimReceivedWebhook(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Equal(t, rw2.DeliveryID, "delivery02")
+
+		_, err = cs.ah.UpdateReceivedWebhookResult(ctx, rw2.ID, &action.UpdateReceivedWebhookResultRequest{Attempts: rw2.Attempts, Status: types.ReceivedWebhookStatusProcessed, Outcome: types.ReceivedWebhookOutcomeSkipped, OutcomeMessage: "when didn't match"})
+		testutil.NilError(t, err)
+
+		// nothing left to process
+		rw3, err := cs.ah.ClaimReceivedWebhook(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Assert(t, rw3 == nil)
+
+		res, err := cs.ah.GetProjectReceivedWebhooks(ctx, &action.GetProjectReceivedWebhooksRequest{ProjectRef: project.Project.ID, Statuses: []types.ReceivedWebhookStatus{types.ReceivedWebhookStatusProcessed}})
+		testutil.NilError(t, err)
+		assert.Equal(t, len(res.ReceivedWebhooks), 2)
+		assert.Equal(t, res.ReceivedWebhooks[0].Outcome, types.ReceivedWebhookOutcomeRunsCreated)
+		assert.Equal(t, res.ReceivedWebhooks[1].Outcome, types.ReceivedWebhookOutcomeSkipped)
+	})
+
+	t.Run("test expired claim is claimed again", func(t *testing.T) {
+		rw, err := cs.ah.CreateReceivedWebhook(ctx, &action.CreateReceivedWebhookRequest{ProjectRef: project.Project.ID, DeliveryID: "delivery03", Event: "push"})
+		testutil.NilError(t, err)
+
+		rw1, err := cs.ah.ClaimReceivedWebhook(ctx, time.Millisecond)
+		testutil.NilError(t, err)
+		assert.Equal(t, rw1.ID, rw.ID)
+
+		time.Sleep(10 * time.Millisecond)
+
+		rw2, err := cs.ah.ClaimReceivedWebhook(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Equal(t, rw2.ID, rw.ID)
+		assert.Equal(t, rw2.Attempts, 2)
+	})
+
+	t.Run("test delete expired received webhooks", func(t *testing.T) {
+		err := cs.ah.DeleteExpiredReceivedWebhooks(ctx, -time.Hour)
+		testutil.NilError(t, err)
+
+		res, err := cs.ah.GetProjectReceivedWebhooks(ctx, &action.GetProjectReceivedWebhooksRequest{ProjectRef: project.Project.ID})
+		testutil.NilError(t, err)
+		// only the processed received webhooks are removed
+		assert.Equal(t, len(res.ReceivedWebhooks), 1)
+	})
+}
+
+func TestProjectMirrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	cs := setupConfigstore(ctx, t, log, dir)
+
+	t.Logf("starting cs")
+	go func() { _ = cs.Run(ctx) }()
+
+	user, err := cs.ah.CreateUser(ctx, &action.CreateUserRequest{UserName: "user01"})
+	testutil.NilError(t, err)
+
+	parent := types.Parent{Kind: types.ObjectKindProjectGroup, ID: path.Join("user", user.Name)}
+
+	t.Run("test create mirror project without url", func(t *testing.T) {
+		_, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: parent, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeMirror})
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+	})
+
+	t.Run("test create mirror project with invalid branch pattern", func(t *testing.T) {
+		_, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: parent, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeMirror, MirrorURL: "ssh://git@example.com/repo.git", MirrorBranches: []string{"release-["}})
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+	})
+
+	project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project01", Parent: parent, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeMirror, MirrorURL: "ssh://git@example.com/repo.git", MirrorBranches: []string{"master"}})
+	testutil.NilError(t, err)
+
+	t.Run("test get project mirror", func(t *testing.T) {
+		pm, err := cs.ah.GetProjectMirror(ctx, project.Project.ID)
+		testutil.NilError(t, err)
+		assert.Equal(t, pm.URL, "ssh://git@example.com/repo.git")
+		assert.DeepEqual(t, pm.Branches, []string{"master"})
+		assert.Assert(t, pm.LastPollTime == nil)
+	})
+
+	t.Run("test claim and update project mirror poll result", func(t *testing.T) {
+		pm, err := cs.ah.ClaimProjectMirror(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Equal(t, pm.ProjectID, project.Project.ID)
+		assert.Equal(t, pm.PollCount, uint64(1))
+
+		// already claimed
+		pmn, err := cs.ah.ClaimProjectMirror(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Assert(t, pmn == nil)
+
+		refs := map[string]string{"refs/heads/master": "0b6f2e3c1a"}
+		pm, err = cs.ah.UpdateProjectMirrorPollResult(ctx, pm.ID, &action.UpdateProjectMirrorPollResultRequest{PollCount: pm.PollCount, Refs: refs, NextPollTime: time.Now()})
+		testutil.NilError(t, err)
+		assert.DeepEqual(t, pm.Refs, refs)
+		assert.Assert(t, pm.LastPollTime != nil)
+
+		pm, err = cs.ah.ClaimProjectMirror(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Equal(t, pm.PollCount, uint64(2))
+
+		// a result for a previous claim is rejected
+		_, err = cs.ah.UpdateProjectMirrorPollResult(ctx, pm.ID, &action.UpdateProjectMirrorPollResultRequest{PollCount: 1, NextPollTime: time.Now()})
+		assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest))
+
+		// on poll error the refs are kept
+		pm, err = cs.ah.UpdateProjectMirrorPollResult(ctx, pm.ID, &action.UpdateProjectMirrorPollResultRequest{PollCount: pm.PollCount, PollError: "connection refused", NextPollTime: time.Now().Add(time.Hour)})
+		testutil.NilError(t, err)
+		assert.DeepEqual(t, pm.Refs, refs)
+		assert.Equal(t, pm.LastPollError, "connection refused")
+
+		// nothing to poll
+		pmn, err = cs.ah.ClaimProjectMirror(ctx, time.Minute)
+		testutil.NilError(t, err)
+		assert.Assert(t, pmn == nil)
+	})
+
+	t.Run("test update project mirror url resets refs", func(t *testing.T) {
+		_, err := cs.ah.UpdateProject(ctx, project.Project.ID, &action.CreateUpdateProjectRequest{Name: "project01", Parent: parent, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeMirror, MirrorURL: "ssh://git@example.com/repo02.git"})
+		testutil.NilError(t, err)
+
+		pm, err := cs.ah.GetProjectMirror(ctx, project.Project.ID)
+		testutil.NilError(t, err)
+		assert.Equal(t, pm.URL, "ssh://git@example.com/repo02.git")
+		assert.Assert(t, pm.Refs == nil)
+	})
+
+	t.Run("test project mirror removed when project isn't a mirror anymore", func(t *testing.T) {
+		_, err := cs.ah.UpdateProject(ctx, project.Project.ID, &action.CreateUpdateProjectRequest{Name: "project01", Parent: parent, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeManual})
+		testutil.NilError(t, err)
+
+		_, err = cs.ah.GetProjectMirror(ctx, project.Project.ID)
+		assert.Assert(t, util.APIErrorIs(err, util.ErrNotExist))
+	})
+
+	t.Run("test project mirror removed with project", func(t *testing.T) {
+		project, err := cs.ah.CreateProject(ctx, &action.CreateUpdateProjectRequest{Name: "project02", Parent: parent, Visibility: types.VisibilityPublic, RemoteRepositoryConfigType: types.RemoteRepositoryConfigTypeMirror, MirrorURL: "ssh://git@example.com/repo.git"})
+		testutil.NilError(t, err)
+
+		err = cs.ah.DeleteProject(ctx, project.Project.ID)
+		testutil.NilError(t, err)
+
+		err = cs.d.Do(ctx, func(tx *sql.Tx) error {
+			pm, err := cs.d.GetProjectMirrorByProjectID(tx, project.Project.ID)
+			if err != nil {
+				return err
+			}
+			assert.Assert(t, pm == nil)
+			return nil
+		})
+		testutil.NilError(t, err)
+	})
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	stdsql "database/sql"
+	"time"
 
 	sq "github.com/huandu/go-sqlbuilder"
 	"github.com/rs/zerolog"
@@ -765,6 +766,362 @@ func (d *DB) GetVariables(tx *sql.Tx, parentID string) ([]*types.Variable, error
 	return variables, errors.WithStack(err)
 }
 
+func (d *DB) GetRoleBindings(tx *sql.Tx, parentID string) ([]*types.RoleBinding, error) {
+	q := roleBindingSelect()
+	q.Where(q.E("parent_id", parentID))
+	roleBindings, _, err := d.fetchRoleBindings(tx, q)
+	return roleBindings, errors.WithStack(err)
+}
+
+func (d *DB) GetRoleBindingBySubject(tx *sql.Tx, parentID string, subjectKind types.ObjectKind, subjectID string) (*types.RoleBinding, error) {
+	q := roleBindingSelect()
+	q.Where(q.E("parent_id", parentID), q.E("subject_kind", subjectKind), q.E("subject_id", subjectID))
+	roleBindings, _, err := d.fetchRoleBindings(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(roleBindings)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) DeleteRoleBindingsBySubject(tx *sql.Tx, subjectKind types.ObjectKind, subjectID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("rolebinding").Where(q.E("subject_kind", subjectKind), q.E("subject_id", subjectID))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete rolebinding")
+	}
+
+	return nil
+}
+
+func (d *DB) GetTeamByID(tx *sql.Tx, teamID string) (*types.Team, error) {
+	q := teamSelect()
+	q.Where(q.E("id", teamID))
+	teams, _, err := d.fetchTeams(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(teams)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) GetTeamByName(tx *sql.Tx, orgID, name string) (*types.Team, error) {
+	q := teamSelect()
+	q.Where(q.E("organization_id", orgID), q.E("name", name))
+	teams, _, err := d.fetchTeams(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(teams)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) GetOrgTeams(tx *sql.Tx, orgID string) ([]*types.Team, error) {
+	q := teamSelect()
+	q.Where(q.E("organization_id", orgID))
+	q.OrderBy("name")
+	teams, _, err := d.fetchTeams(tx, q)
+	return teams, errors.WithStack(err)
+}
+
+func (d *DB) GetUserTeams(tx *sql.Tx, userID string) ([]*types.Team, error) {
+	q := teamSelect()
+	q = q.Join("teammember", "teammember.team_id = team.id")
+	q = q.Where(q.E("teammember.user_id", userID))
+	teams, _, err := d.fetchTeams(tx, q)
+	return teams, errors.WithStack(err)
+}
+
+func (d *DB) DeleteTeamsByOrgID(tx *sql.Tx, orgID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("team").Where(q.E("organization_id", orgID))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete team")
+	}
+
+	return nil
+}
+
+func (d *DB) GetTeamMember(tx *sql.Tx, teamID, userID string) (*types.TeamMember, error) {
+	q := teamMemberSelect()
+	q.Where(q.E("team_id", teamID), q.E("user_id", userID))
+	teamMembers, _, err := d.fetchTeamMembers(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(teamMembers)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) GetTeamUsers(tx *sql.Tx, teamID string) ([]*types.User, error) {
+	q := userSelect()
+	q = q.Join("teammember", "teammember.user_id = user_t.id")
+	q = q.Where(q.E("teammember.team_id", teamID))
+	q = q.OrderBy("user_t.name")
+	users, _, err := d.fetchUsers(tx, q)
+	return users, errors.WithStack(err)
+}
+
+func (d *DB) DeleteTeamMembersByTeamID(tx *sql.Tx, teamID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("teammember").Where(q.E("team_id", teamID))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete teammember")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteTeamMembersByOrgID(tx *sql.Tx, orgID string) error {
+	tq := sq.NewSelectBuilder().Select("id").From("team")
+	tq.Where(tq.E("organization_id", orgID))
+
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("teammember").Where(q.In("team_id", tq))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete teammember")
+	}
+
+	return nil
+}
+
+// DeleteTeamMembersByOrgUserID removes the user from all the teams of the
+// provided organization.
+func (d *DB) DeleteTeamMembersByOrgUserID(tx *sql.Tx, orgID, userID string) error {
+	tq := sq.NewSelectBuilder().Select("id").From("team")
+	tq.Where(tq.E("organization_id", orgID))
+
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("teammember").Where(q.E("user_id", userID), q.In("team_id", tq))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete teammember")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteTeamMembersByUserID(tx *sql.Tx, userID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("teammember").Where(q.E("user_id", userID))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete teammember")
+	}
+
+	return nil
+}
+
+type AuditEventsFilter struct {
+	ActorUserID    string
+	Actions        []types.AuditAction
+	TargetKind     types.ObjectKind
+	TargetID       string
+	OrganizationID string
+	Since          *time.Time
+	Until          *time.Time
+}
+
+func (d *DB) GetAuditEvents(tx *sql.Tx, filter *AuditEventsFilter, afterSequence uint64, limit int, sortDirection types.SortDirection) ([]*types.AuditEvent, error) {
+	q := auditEventSelect().OrderBy("sequence")
+
+	if filter.ActorUserID != "" {
+		q.Where(q.E("actor_user_id", filter.ActorUserID))
+	}
+	if len(filter.Actions) > 0 {
+		q.Where(q.In("action", sq.Flatten(filter.Actions)...))
+	}
+	if filter.TargetKind != "" {
+		q.Where(q.E("target_kind", filter.TargetKind))
+	}
+	if filter.TargetID != "" {
+		q.Where(q.E("target_id", filter.TargetID))
+	}
+	if filter.OrganizationID != "" {
+		q.Where(q.E("organization_id", filter.OrganizationID))
+	}
+	if filter.Since != nil {
+		q.Where(q.GE("creation_time", *filter.Since))
+	}
+	if filter.Until != nil {
+		q.Where(q.L("creation_time", *filter.Until))
+	}
+
+	switch sortDirection {
+	case types.SortDirectionAsc:
+		q.Asc()
+	case types.SortDirectionDesc:
+		q.Desc()
+	}
+	if afterSequence > 0 {
+		switch sortDirection {
+		case types.SortDirectionAsc:
+			q.Where(q.G("sequence", afterSequence))
+		case types.SortDirectionDesc:
+			q.Where(q.L("sequence", afterSequence))
+		}
+	}
+
+	if limit > 0 {
+		q.Limit(limit)
+	}
+
+	auditEvents, _, err := d.fetchAuditEvents(tx, q)
+	return auditEvents, errors.WithStack(err)
+}
+
+func (d *DB) GetReceivedWebhook(tx *sql.Tx, receivedWebhookID string) (*types.ReceivedWebhook, error) {
+	q := receivedWebhookSelect()
+	q.Where(q.E("id", receivedWebhookID))
+	receivedWebhooks, _, err := d.fetchReceivedWebhooks(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(receivedWebhooks)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) GetReceivedWebhookByDeliveryID(tx *sql.Tx, projectID, deliveryID string) (*types.ReceivedWebhook, error) {
+	q := receivedWebhookSelect()
+	q.Where(q.E("project_id", projectID), q.E("delivery_id", deliveryID))
+	receivedWebhooks, _, err := d.fetchReceivedWebhooks(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(receivedWebhooks)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) GetProjectReceivedWebhooks(tx *sql.Tx, projectID string, statuses []types.ReceivedWebhookStatus, afterSequence uint64, limit int, sortDirection types.SortDirection) ([]*types.ReceivedWebhook, error) {
+	q := receivedWebhookSelect().OrderBy("sequence")
+	q.Where(q.E("project_id", projectID))
+	if len(statuses) > 0 {
+		q.Where(q.In("status", sq.Flatten(statuses)...))
+	}
+
+	switch sortDirection {
+	case types.SortDirectionAsc:
+		q.Asc()
+	case types.SortDirectionDesc:
+		q.Desc()
+	}
+	if afterSequence > 0 {
+		switch sortDirection {
+		case types.SortDirectionAsc:
+			q.Where(q.G("sequence", afterSequence))
+		case types.SortDirectionDesc:
+			q.Where(q.L("sequence", afterSequence))
+		}
+	}
+
+	if limit > 0 {
+		q.Limit(limit)
+	}
+
+	receivedWebhooks, _, err := d.fetchReceivedWebhooks(tx, q)
+	return receivedWebhooks, errors.WithStack(err)
+}
+
+// GetNextReceivedWebhookToProcess returns the oldest received webhook that is
+// pending or whose processing claim expired and that could be processed at
+// the provided time.
+// Received webhooks of the same project are processed in order: a received
+// webhook isn't returned while an older one of the same project is still
+// pending or processing.
+func (d *DB) GetNextReceivedWebhookToProcess(tx *sql.Tx, now time.Time) (*types.ReceivedWebhook, error) {
+	pq := sq.NewSelectBuilder().Select("1").From("receivedwebhook AS prev")
+	pq.Where(
+		"prev.project_id = receivedwebhook.project_id",
+		"prev.sequence < receivedwebhook.sequence",
+		pq.In("prev.status", types.ReceivedWebhookStatusPending, types.ReceivedWebhookStatusProcessing),
+	)
+
+	q := receivedWebhookSelect().OrderBy("sequence").Asc()
+	q.Where(
+		q.In("status", types.ReceivedWebhookStatusPending, types.ReceivedWebhookStatusProcessing),
+		q.LE("next_attempt_time", now),
+		q.NotExists(pq),
+	)
+	q.Limit(1)
+
+	receivedWebhooks, _, err := d.fetchReceivedWebhooks(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(receivedWebhooks)
+	return out, errors.WithStack(err)
+}
+
+// DeleteReceivedWebhooksBefore deletes the processed and failed received
+// webhooks last updated before the provided time.
+func (d *DB) DeleteReceivedWebhooksBefore(tx *sql.Tx, before time.Time) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("receivedwebhook").Where(
+		q.In("status", types.ReceivedWebhookStatusProcessed, types.ReceivedWebhookStatusFailed),
+		q.L("update_time", before),
+	)
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete receivedwebhook")
+	}
+
+	return nil
+}
+
+func (d *DB) GetProjectMirrorByID(tx *sql.Tx, projectMirrorID string) (*types.ProjectMirror, error) {
+	q := projectMirrorSelect()
+	q.Where(q.E("id", projectMirrorID))
+	projectMirrors, _, err := d.fetchProjectMirrors(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(projectMirrors)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) GetProjectMirrorByProjectID(tx *sql.Tx, projectID string) (*types.ProjectMirror, error) {
+	q := projectMirrorSelect()
+	q.Where(q.E("project_id", projectID))
+	projectMirrors, _, err := d.fetchProjectMirrors(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(projectMirrors)
+	return out, errors.WithStack(err)
+}
+
+// GetNextProjectMirrorToPoll returns the project mirror with the oldest next
+// poll time that could be polled at the provided time.
+func (d *DB) GetNextProjectMirrorToPoll(tx *sql.Tx, now time.Time) (*types.ProjectMirror, error) {
+	q := projectMirrorSelect().OrderBy("next_poll_time").Asc()
+	q.Where(q.LE("next_poll_time", now))
+	q.Limit(1)
+
+	projectMirrors, _, err := d.fetchProjectMirrors(tx, q)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := mustSingleRow(projectMirrors)
+	return out, errors.WithStack(err)
+}
+
+func (d *DB) DeleteProjectMirrorByProjectID(tx *sql.Tx, projectID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("projectmirror").Where(q.E("project_id", projectID))
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete projectmirror")
+	}
+
+	return nil
+}
+
 // Test only functions
 func (d *DB) GetAllProjects(tx *sql.Tx) ([]*types.Project, error) {
 	q := projectSelect()
@@ -798,6 +1155,22 @@ func (d *DB) GetAllVariables(tx *sql.Tx) ([]*types.Variable, error) {
 	return variables, errors.WithStack(err)
 }
 
+func (d *DB) GetAllTeams(tx *sql.Tx) ([]*types.Team, error) {
+	q := teamSelect()
+	q.OrderBy("id")
+	teams, _, err := d.fetchTeams(tx, q)
+
+	return teams, errors.WithStack(err)
+}
+
+func (d *DB) GetAllTeamMembers(tx *sql.Tx) ([]*types.TeamMember, error) {
+	q := teamMemberSelect()
+	q.OrderBy("id")
+	teamMembers, _, err := d.fetchTeamMembers(tx, q)
+
+	return teamMembers, errors.WithStack(err)
+}
+
 func (d *DB) GetOrgInvitations(tx *sql.Tx, orgID string) ([]*types.OrgInvitation, error) {
 	q := orgInvitationSelect()
 	q.Where(q.E("organization_id", orgID))
@@ -16,8 +16,18 @@ var DDLPostgres = []string{
 	"create table if not exists secret (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, name varchar NOT NULL, parent_kind varchar NOT NULL, parent_id varchar NOT NULL, type varchar NOT NULL, data jsonb NOT NULL, secret_provider_id varchar NOT NULL, path varchar NOT NULL, PRIMARY KEY (id))",
 	"create table if not exists variable (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, name varchar NOT NULL, parent_kind varchar NOT NULL, parent_id varchar NOT NULL, variable_values jsonb NOT NULL, PRIMARY KEY (id))",
 	"create table if not exists orginvitation (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, user_id varchar NOT NULL, organization_id varchar NOT NULL, role varchar NOT NULL, PRIMARY KEY (id), foreign key (user_id) references user_t(id), foreign key (organization_id) references organization(id))",
+	"create table if not exists rolebinding (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, parent_kind varchar NOT NULL, parent_id varchar NOT NULL, subject_kind varchar NOT NULL, subject_id varchar NOT NULL, role varchar NOT NULL, PRIMARY KEY (id))",
+	"create table if not exists team (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, organization_id varchar NOT NULL, name varchar NOT NULL, PRIMARY KEY (id), foreign key (organization_id) references organization(id))",
+	"create table if not exists teammember (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, team_id varchar NOT NULL, user_id varchar NOT NULL, PRIMARY KEY (id), foreign key (team_id) references team(id), foreign key (user_id) references user_t(id))",
+	"create table if not exists auditevent (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, sequence bigint generated by default as identity NOT NULL UNIQUE, actor_user_id varchar NOT NULL, actor_user_name varchar NOT NULL, action varchar NOT NULL, target_kind varchar NOT NULL, target_id varchar NOT NULL, target_name varchar NOT NULL, organization_id varchar NOT NULL, details jsonb NOT NULL, request_metadata jsonb NOT NULL, PRIMARY KEY (id))",
+	"create table if not exists receivedwebhook (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, sequence bigint generated by default as identity NOT NULL UNIQUE, project_id varchar NOT NULL, delivery_id varchar NOT NULL, event varchar NOT NULL, header jsonb NOT NULL, body bytea NOT NULL, status varchar NOT NULL, attempts bigint NOT NULL, next_attempt_time timestamptz NOT NULL, outcome varchar NOT NULL, outcome_message varchar NOT NULL, run_numbers jsonb NOT NULL, PRIMARY KEY (id), unique (project_id, delivery_id))",
+	"create table if not exists projectmirror (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamptz NOT NULL, update_time timestamptz NOT NULL, project_id varchar NOT NULL, url varchar NOT NULL, ssh_host_key varchar NOT NULL, branches jsonb NOT NULL, tags jsonb NOT NULL, refs jsonb NOT NULL, poll_count bigint NOT NULL, next_poll_time timestamptz NOT NULL, last_poll_time timestamptz, last_poll_error varchar NOT NULL, PRIMARY KEY (id), unique (project_id))",
 
 	// indexes
+	"create index if not exists auditevent_organization_id_idx on auditevent(organization_id)",
+	"create index if not exists receivedwebhook_project_id_idx on receivedwebhook(project_id)",
+	"create index if not exists receivedwebhook_status_idx on receivedwebhook(status, next_attempt_time)",
+	"create index if not exists projectmirror_next_poll_time_idx on projectmirror(next_poll_time)",
 }
 var DDLSqlite3 = []string{
 	"create table if not exists remotesource (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, name varchar NOT NULL, apiurl varchar NOT NULL, skip_verify integer NOT NULL, type varchar NOT NULL, auth_type varchar NOT NULL, oauth2_client_id varchar NOT NULL, oauth2_client_secret varchar NOT NULL, ssh_host_key varchar NOT NULL, skip_ssh_host_key_check integer NOT NULL, registration_enabled integer NOT NULL, login_enabled integer NOT NULL, PRIMARY KEY (id))",
@@ -31,9 +41,29 @@ var DDLSqlite3 = []string{
 	"create table if not exists secret (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, name varchar NOT NULL, parent_kind varchar NOT NULL, parent_id varchar NOT NULL, type varchar NOT NULL, data text NOT NULL, secret_provider_id varchar NOT NULL, path varchar NOT NULL, PRIMARY KEY (id))",
 	"create table if not exists variable (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, name varchar NOT NULL, parent_kind varchar NOT NULL, parent_id varchar NOT NULL, variable_values text NOT NULL, PRIMARY KEY (id))",
 	"create table if not exists orginvitation (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, user_id varchar NOT NULL, organization_id varchar NOT NULL, role varchar NOT NULL, PRIMARY KEY (id), foreign key (user_id) references user_t(id), foreign key (organization_id) references organization(id))",
+	"create table if not exists rolebinding (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, parent_kind varchar NOT NULL, parent_id varchar NOT NULL, subject_kind varchar NOT NULL, subject_id varchar NOT NULL, role varchar NOT NULL, PRIMARY KEY (id))",
+	"create table if not exists team (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, organization_id varchar NOT NULL, name varchar NOT NULL, PRIMARY KEY (id), foreign key (organization_id) references organization(id))",
+	"create table if not exists teammember (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, team_id varchar NOT NULL, user_id varchar NOT NULL, PRIMARY KEY (id), foreign key (team_id) references team(id), foreign key (user_id) references user_t(id))",
+	"create table if not exists auditevent (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, sequence integer NOT NULL UNIQUE, actor_user_id varchar NOT NULL, actor_user_name varchar NOT NULL, action varchar NOT NULL, target_kind varchar NOT NULL, target_id varchar NOT NULL, target_name varchar NOT NULL, organization_id varchar NOT NULL, details text NOT NULL, request_metadata text NOT NULL, PRIMARY KEY (id))",
+	"create table if not exists receivedwebhook (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, sequence integer NOT NULL UNIQUE, project_id varchar NOT NULL, delivery_id varchar NOT NULL, event varchar NOT NULL, header text NOT NULL, body blob NOT NULL, status varchar NOT NULL, attempts bigint NOT NULL, next_attempt_time timestamp NOT NULL, outcome varchar NOT NULL, outcome_message varchar NOT NULL, run_numbers text NOT NULL, PRIMARY KEY (id), unique (project_id, delivery_id))",
+	"create table if not exists projectmirror (id varchar NOT NULL, revision bigint NOT NULL, creation_time timestamp NOT NULL, update_time timestamp NOT NULL, project_id varchar NOT NULL, url varchar NOT NULL, ssh_host_key varchar NOT NULL, branches text NOT NULL, tags text NOT NULL, refs text NOT NULL, poll_count bigint NOT NULL, next_poll_time timestamp NOT NULL, last_poll_time timestamp, last_poll_error varchar NOT NULL, PRIMARY KEY (id), unique (project_id))",
 
 	// indexes
+	"create index if not exists auditevent_organization_id_idx on auditevent(organization_id)",
+	"create index if not exists receivedwebhook_project_id_idx on receivedwebhook(project_id)",
+	"create index if not exists receivedwebhook_status_idx on receivedwebhook(status, next_attempt_time)",
+	"create index if not exists projectmirror_next_poll_time_idx on projectmirror(next_poll_time)",
 }
 
 var Sequences = []sqlg.Sequence {
+	{
+		Name:   "auditevent_sequence_seq",
+		Table:  "auditevent",
+		Column: "sequence",
+	},
+	{
+		Name:   "receivedwebhook_sequence_seq",
+		Table:  "receivedwebhook",
+		Column: "sequence",
+	},
 }
@@ -1479,6 +1479,820 @@ func (d *DB) insertRawOrgInvitation(tx *sql.Tx, v *types.OrgInvitation) error {
 	return nil
 }
 
+var (
+	roleBindingSelectColumns = func(additionalCols ...string) []string {
+		columns := []string{"rolebinding.id", "rolebinding.revision", "rolebinding.creation_time", "rolebinding.update_time", "rolebinding.parent_kind", "rolebinding.parent_id", "rolebinding.subject_kind", "rolebinding.subject_id", "rolebinding.role"}
+		columns = append(columns, additionalCols...)
+
+		return columns
+	}
+
+	roleBindingSelect = func(additionalCols ...string) *sq.SelectBuilder {
+		return sq.NewSelectBuilder().Select(roleBindingSelectColumns(additionalCols...)...).From("rolebinding")
+	}
+)
+
+func (d *DB) InsertOrUpdateRoleBinding(tx *sql.Tx, v *types.RoleBinding) error {
+	var err error
+	if v.Revision == 0 {
+		err = d.InsertRoleBinding(tx, v)
+	} else {
+		err = d.UpdateRoleBinding(tx, v)
+	}
+
+	return errors.WithStack(err)
+}
+
+func (d *DB) InsertRoleBinding(tx *sql.Tx, v *types.RoleBinding) error {
+	if v.Revision != 0 {
+		return errors.Errorf("expected revision 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not created by this transaction")
+	}
+
+	v.Revision = 1
+
+	now := time.Now()
+	v.CreationTime = now
+	v.UpdateTime = now
+
+	var err error
+
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawRoleBindingPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRoleBindingSqlite3(tx, v);
+	}
+
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert rolebinding")
+	}
+
+	return nil
+}
+
+func (d *DB) UpdateRoleBinding(tx *sql.Tx, v *types.RoleBinding) error {
+	if v.Revision < 1 {
+		return errors.Errorf("expected revision > 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not fetched by this transaction")
+	}
+
+	curRevision := v.Revision
+	v.Revision++
+
+	v.UpdateTime = time.Now()
+
+	var res stdsql.Result
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		res, err = d.updateRoleBindingPostgres(tx, curRevision, v);
+	case sql.Sqlite3:
+		res, err = d.updateRoleBindingSqlite3(tx, curRevision, v);
+	}
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update rolebinding")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update rolebinding")
+	}
+
+	if rows != 1 {
+		v.Revision = curRevision
+		return sqlg.ErrConcurrent
+	}
+
+	return nil
+}
+
+func (d *DB) deleteRoleBinding(tx *sql.Tx, roleBindingID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("rolebinding").Where(q.E("id", roleBindingID))
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete roleBinding")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteRoleBinding(tx *sql.Tx, id string) error {
+	return d.deleteRoleBinding(tx, id)
+}
+
+// insertRawRoleBinding should be used only for import.
+// * It won't update object times.
+// * It will insert values for sequences.
+func (d *DB) insertRawRoleBinding(tx *sql.Tx, v *types.RoleBinding) error {
+	v.Revision = 1
+
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawRoleBindingPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRawRoleBindingSqlite3(tx, v);
+	}
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert rolebinding")
+	}
+
+	return nil
+}
+
+var (
+	teamSelectColumns = func(additionalCols ...string) []string {
+		columns := []string{"team.id", "team.revision", "team.creation_time", "team.update_time", "team.organization_id", "team.name"}
+		columns = append(columns, additionalCols...)
+
+		return columns
+	}
+
+	teamSelect = func(additionalCols ...string) *sq.SelectBuilder {
+		return sq.NewSelectBuilder().Select(teamSelectColumns(additionalCols...)...).From("team")
+	}
+)
+
+func (d *DB) InsertOrUpdateTeam(tx *sql.Tx, v *types.Team) error {
+	var err error
+	if v.Revision == 0 {
+		err = d.InsertTeam(tx, v)
+	} else {
+		err = d.UpdateTeam(tx, v)
+	}
+
+	return errors.WithStack(err)
+}
+
+func (d *DB) InsertTeam(tx *sql.Tx, v *types.Team) error {
+	if v.Revision != 0 {
+		return errors.Errorf("expected revision 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not created by this transaction")
+	}
+
+	v.Revision = 1
+
+	now := time.Now()
+	v.CreationTime = now
+	v.UpdateTime = now
+
+	var err error
+
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawTeamPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertTeamSqlite3(tx, v);
+	}
+
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert team")
+	}
+
+	return nil
+}
+
+func (d *DB) UpdateTeam(tx *sql.Tx, v *types.Team) error {
+	if v.Revision < 1 {
+		return errors.Errorf("expected revision > 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not fetched by this transaction")
+	}
+
+	curRevision := v.Revision
+	v.Revision++
+
+	v.UpdateTime = time.Now()
+
+	var res stdsql.Result
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		res, err = d.updateTeamPostgres(tx, curRevision, v);
+	case sql.Sqlite3:
+		res, err = d.updateTeamSqlite3(tx, curRevision, v);
+	}
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update team")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update team")
+	}
+
+	if rows != 1 {
+		v.Revision = curRevision
+		return sqlg.ErrConcurrent
+	}
+
+	return nil
+}
+
+func (d *DB) deleteTeam(tx *sql.Tx, teamID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("team").Where(q.E("id", teamID))
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete team")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteTeam(tx *sql.Tx, id string) error {
+	return d.deleteTeam(tx, id)
+}
+
+// insertRawTeam should be used only for import.
+// * It won't update object times.
+// * It will insert values for sequences.
+func (d *DB) insertRawTeam(tx *sql.Tx, v *types.Team) error {
+	v.Revision = 1
+
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawTeamPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRawTeamSqlite3(tx, v);
+	}
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert team")
+	}
+
+	return nil
+}
+
+var (
+	teamMemberSelectColumns = func(additionalCols ...string) []string {
+		columns := []string{"teammember.id", "teammember.revision", "teammember.creation_time", "teammember.update_time", "teammember.team_id", "teammember.user_id"}
+		columns = append(columns, additionalCols...)
+
+		return columns
+	}
+
+	teamMemberSelect = func(additionalCols ...string) *sq.SelectBuilder {
+		return sq.NewSelectBuilder().Select(teamMemberSelectColumns(additionalCols...)...).From("teammember")
+	}
+)
+
+func (d *DB) InsertOrUpdateTeamMember(tx *sql.Tx, v *types.TeamMember) error {
+	var err error
+	if v.Revision == 0 {
+		err = d.InsertTeamMember(tx, v)
+	} else {
+		err = d.UpdateTeamMember(tx, v)
+	}
+
+	return errors.WithStack(err)
+}
+
+func (d *DB) InsertTeamMember(tx *sql.Tx, v *types.TeamMember) error {
+	if v.Revision != 0 {
+		return errors.Errorf("expected revision 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not created by this transaction")
+	}
+
+	v.Revision = 1
+
+	now := time.Now()
+	v.CreationTime = now
+	v.UpdateTime = now
+
+	var err error
+
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawTeamMemberPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertTeamMemberSqlite3(tx, v);
+	}
+
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert teammember")
+	}
+
+	return nil
+}
+
+func (d *DB) UpdateTeamMember(tx *sql.Tx, v *types.TeamMember) error {
+	if v.Revision < 1 {
+		return errors.Errorf("expected revision > 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not fetched by this transaction")
+	}
+
+	curRevision := v.Revision
+	v.Revision++
+
+	v.UpdateTime = time.Now()
+
+	var res stdsql.Result
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		res, err = d.updateTeamMemberPostgres(tx, curRevision, v);
+	case sql.Sqlite3:
+		res, err = d.updateTeamMemberSqlite3(tx, curRevision, v);
+	}
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update teammember")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update teammember")
+	}
+
+	if rows != 1 {
+		v.Revision = curRevision
+		return sqlg.ErrConcurrent
+	}
+
+	return nil
+}
+
+func (d *DB) deleteTeamMember(tx *sql.Tx, teamMemberID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("teammember").Where(q.E("id", teamMemberID))
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete teamMember")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteTeamMember(tx *sql.Tx, id string) error {
+	return d.deleteTeamMember(tx, id)
+}
+
+// insertRawTeamMember should be used only for import.
+// * It won't update object times.
+// * It will insert values for sequences.
+func (d *DB) insertRawTeamMember(tx *sql.Tx, v *types.TeamMember) error {
+	v.Revision = 1
+
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawTeamMemberPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRawTeamMemberSqlite3(tx, v);
+	}
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert teammember")
+	}
+
+	return nil
+}
+
+var (
+	auditEventSelectColumns = func(additionalCols ...string) []string {
+		columns := []string{"auditevent.id", "auditevent.revision", "auditevent.creation_time", "auditevent.update_time", "auditevent.sequence", "auditevent.actor_user_id", "auditevent.actor_user_name", "auditevent.action", "auditevent.target_kind", "auditevent.target_id", "auditevent.target_name", "auditevent.organization_id", "auditevent.details", "auditevent.request_metadata"}
+		columns = append(columns, additionalCols...)
+
+		return columns
+	}
+
+	auditEventSelect = func(additionalCols ...string) *sq.SelectBuilder {
+		return sq.NewSelectBuilder().Select(auditEventSelectColumns(additionalCols...)...).From("auditevent")
+	}
+)
+
+func (d *DB) InsertOrUpdateAuditEvent(tx *sql.Tx, v *types.AuditEvent) error {
+	var err error
+	if v.Revision == 0 {
+		err = d.InsertAuditEvent(tx, v)
+	} else {
+		err = d.UpdateAuditEvent(tx, v)
+	}
+
+	return errors.WithStack(err)
+}
+
+func (d *DB) InsertAuditEvent(tx *sql.Tx, v *types.AuditEvent) error {
+	if v.Revision != 0 {
+		return errors.Errorf("expected revision 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not created by this transaction")
+	}
+
+	v.Revision = 1
+
+	now := time.Now()
+	v.CreationTime = now
+	v.UpdateTime = now
+
+	var err error
+	var nextSeq uint64
+
+	nextSeq, err = d.nextSequence(tx, "auditevent_sequence_seq")
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to create next sequence for auditevent_sequence_seq")
+	}
+	v.Sequence = nextSeq
+
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawAuditEventPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertAuditEventSqlite3(tx, v);
+	}
+
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert auditevent")
+	}
+
+	return nil
+}
+
+func (d *DB) UpdateAuditEvent(tx *sql.Tx, v *types.AuditEvent) error {
+	if v.Revision < 1 {
+		return errors.Errorf("expected revision > 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not fetched by this transaction")
+	}
+
+	curRevision := v.Revision
+	v.Revision++
+
+	v.UpdateTime = time.Now()
+
+	var res stdsql.Result
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		res, err = d.updateAuditEventPostgres(tx, curRevision, v);
+	case sql.Sqlite3:
+		res, err = d.updateAuditEventSqlite3(tx, curRevision, v);
+	}
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update auditevent")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update auditevent")
+	}
+
+	if rows != 1 {
+		v.Revision = curRevision
+		return sqlg.ErrConcurrent
+	}
+
+	return nil
+}
+
+func (d *DB) deleteAuditEvent(tx *sql.Tx, auditEventID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("auditevent").Where(q.E("id", auditEventID))
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete auditEvent")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteAuditEvent(tx *sql.Tx, id string) error {
+	return d.deleteAuditEvent(tx, id)
+}
+
+// insertRawAuditEvent should be used only for import.
+// * It won't update object times.
+// * It will insert values for sequences.
+func (d *DB) insertRawAuditEvent(tx *sql.Tx, v *types.AuditEvent) error {
+	v.Revision = 1
+
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawAuditEventPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRawAuditEventSqlite3(tx, v);
+	}
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert auditevent")
+	}
+
+	return nil
+}
+
+var (
+	receivedWebhookSelectColumns = func(additionalCols ...string) []string {
+		columns := []string{"receivedwebhook.id", "receivedwebhook.revision", "receivedwebhook.creation_time", "receivedwebhook.update_time", "receivedwebhook.sequence", "receivedwebhook.project_id", "receivedwebhook.delivery_id", "receivedwebhook.event", "receivedwebhook.header", "receivedwebhook.body", "receivedwebhook.status", "receivedwebhook.attempts", "receivedwebhook.next_attempt_time", "receivedwebhook.outcome", "receivedwebhook.outcome_message", "receivedwebhook.run_numbers"}
+		columns = append(columns, additionalCols...)
+
+		return columns
+	}
+
+	receivedWebhookSelect = func(additionalCols ...string) *sq.SelectBuilder {
+		return sq.NewSelectBuilder().Select(receivedWebhookSelectColumns(additionalCols...)...).From("receivedwebhook")
+	}
+)
+
+func (d *DB) InsertOrUpdateReceivedWebhook(tx *sql.Tx, v *types.ReceivedWebhook) error {
+	var err error
+	if v.Revision == 0 {
+		err = d.InsertReceivedWebhook(tx, v)
+	} else {
+		err = d.UpdateReceivedWebhook(tx, v)
+	}
+
+	return errors.WithStack(err)
+}
+
+func (d *DB) InsertReceivedWebhook(tx *sql.Tx, v *types.ReceivedWebhook) error {
+	if v.Revision != 0 {
+		return errors.Errorf("expected revision 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not created by this transaction")
+	}
+
+	v.Revision = 1
+
+	now := time.Now()
+	v.CreationTime = now
+	v.UpdateTime = now
+
+	var err error
+	var nextSeq uint64
+
+	nextSeq, err = d.nextSequence(tx, "receivedwebhook_sequence_seq")
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to create next sequence for receivedwebhook_sequence_seq")
+	}
+	v.Sequence = nextSeq
+
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawReceivedWebhookPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertReceivedWebhookSqlite3(tx, v);
+	}
+
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert receivedwebhook")
+	}
+
+	return nil
+}
+
+func (d *DB) UpdateReceivedWebhook(tx *sql.Tx, v *types.ReceivedWebhook) error {
+	if v.Revision < 1 {
+		return errors.Errorf("expected revision > 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not fetched by this transaction")
+	}
+
+	curRevision := v.Revision
+	v.Revision++
+
+	v.UpdateTime = time.Now()
+
+	var res stdsql.Result
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		res, err = d.updateReceivedWebhookPostgres(tx, curRevision, v);
+	case sql.Sqlite3:
+		res, err = d.updateReceivedWebhookSqlite3(tx, curRevision, v);
+	}
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update receivedwebhook")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update receivedwebhook")
+	}
+
+	if rows != 1 {
+		v.Revision = curRevision
+		return sqlg.ErrConcurrent
+	}
+
+	return nil
+}
+
+func (d *DB) deleteReceivedWebhook(tx *sql.Tx, receivedWebhookID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("receivedwebhook").Where(q.E("id", receivedWebhookID))
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete receivedWebhook")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteReceivedWebhook(tx *sql.Tx, id string) error {
+	return d.deleteReceivedWebhook(tx, id)
+}
+
+// insertRawReceivedWebhook should be used only for import.
+// * It won't update object times.
+// * It will insert values for sequences.
+func (d *DB) insertRawReceivedWebhook(tx *sql.Tx, v *types.ReceivedWebhook) error {
+	v.Revision = 1
+
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawReceivedWebhookPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRawReceivedWebhookSqlite3(tx, v);
+	}
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert receivedwebhook")
+	}
+
+	return nil
+}
+
+var (
+	projectMirrorSelectColumns = func(additionalCols ...string) []string {
+		columns := []string{"projectmirror.id", "projectmirror.revision", "projectmirror.creation_time", "projectmirror.update_time", "projectmirror.project_id", "projectmirror.url", "projectmirror.ssh_host_key", "projectmirror.branches", "projectmirror.tags", "projectmirror.refs", "projectmirror.poll_count", "projectmirror.next_poll_time", "projectmirror.last_poll_time", "projectmirror.last_poll_error"}
+		columns = append(columns, additionalCols...)
+
+		return columns
+	}
+
+	projectMirrorSelect = func(additionalCols ...string) *sq.SelectBuilder {
+		return sq.NewSelectBuilder().Select(projectMirrorSelectColumns(additionalCols...)...).From("projectmirror")
+	}
+)
+
+func (d *DB) InsertOrUpdateProjectMirror(tx *sql.Tx, v *types.ProjectMirror) error {
+	var err error
+	if v.Revision == 0 {
+		err = d.InsertProjectMirror(tx, v)
+	} else {
+		err = d.UpdateProjectMirror(tx, v)
+	}
+
+	return errors.WithStack(err)
+}
+
+func (d *DB) InsertProjectMirror(tx *sql.Tx, v *types.ProjectMirror) error {
+	if v.Revision != 0 {
+		return errors.Errorf("expected revision 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not created by this transaction")
+	}
+
+	v.Revision = 1
+
+	now := time.Now()
+	v.CreationTime = now
+	v.UpdateTime = now
+
+	var err error
+
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawProjectMirrorPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertProjectMirrorSqlite3(tx, v);
+	}
+
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert projectmirror")
+	}
+
+	return nil
+}
+
+func (d *DB) UpdateProjectMirror(tx *sql.Tx, v *types.ProjectMirror) error {
+	if v.Revision < 1 {
+		return errors.Errorf("expected revision > 0 got %d", v.Revision)
+	}
+
+	if v.TxID != tx.ID() {
+		return errors.Errorf("object was not fetched by this transaction")
+	}
+
+	curRevision := v.Revision
+	v.Revision++
+
+	v.UpdateTime = time.Now()
+
+	var res stdsql.Result
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		res, err = d.updateProjectMirrorPostgres(tx, curRevision, v);
+	case sql.Sqlite3:
+		res, err = d.updateProjectMirrorSqlite3(tx, curRevision, v);
+	}
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update projectmirror")
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		v.Revision = curRevision
+		return errors.Wrap(err, "failed to update projectmirror")
+	}
+
+	if rows != 1 {
+		v.Revision = curRevision
+		return sqlg.ErrConcurrent
+	}
+
+	return nil
+}
+
+func (d *DB) deleteProjectMirror(tx *sql.Tx, projectMirrorID string) error {
+	q := sq.NewDeleteBuilder()
+	q.DeleteFrom("projectmirror").Where(q.E("id", projectMirrorID))
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to delete projectMirror")
+	}
+
+	return nil
+}
+
+func (d *DB) DeleteProjectMirror(tx *sql.Tx, id string) error {
+	return d.deleteProjectMirror(tx, id)
+}
+
+// insertRawProjectMirror should be used only for import.
+// * It won't update object times.
+// * It will insert values for sequences.
+func (d *DB) insertRawProjectMirror(tx *sql.Tx, v *types.ProjectMirror) error {
+	v.Revision = 1
+
+	var err error
+	switch d.DBType() {
+	case sql.Postgres:
+		err = d.insertRawProjectMirrorPostgres(tx, v);
+	case sql.Sqlite3:
+		err = d.insertRawProjectMirrorSqlite3(tx, v);
+	}
+	if err != nil {
+		v.Revision = 0
+		return errors.Wrap(err, "failed to insert projectmirror")
+	}
+
+	return nil
+}
+
 func (d *DB) UnmarshalExportObject(data []byte) (sqlg.Object, error) {
 	type exportObjectExportMeta struct {
 		ExportMeta sqlg.ExportMeta `json:"exportMeta"`
@@ -1514,6 +2328,18 @@ func (d *DB) UnmarshalExportObject(data []byte) (sqlg.Object, error) {
 		obj = &types.Variable{}
 	case "OrgInvitation":
 		obj = &types.OrgInvitation{}
+	case "RoleBinding":
+		obj = &types.RoleBinding{}
+	case "Team":
+		obj = &types.Team{}
+	case "TeamMember":
+		obj = &types.TeamMember{}
+	case "AuditEvent":
+		obj = &types.AuditEvent{}
+	case "ReceivedWebhook":
+		obj = &types.ReceivedWebhook{}
+	case "ProjectMirror":
+		obj = &types.ProjectMirror{}
 
 	default:
 		panic(errors.Errorf("unknown object kind %q, data: %s", om.ExportMeta.Kind, data))
@@ -1550,6 +2376,18 @@ func (d *DB) InsertRawObject(tx *sql.Tx, obj sqlg.Object) error {
 		return d.insertRawVariable(tx, o)
 	case *types.OrgInvitation:
 		return d.insertRawOrgInvitation(tx, o)
+	case *types.RoleBinding:
+		return d.insertRawRoleBinding(tx, o)
+	case *types.Team:
+		return d.insertRawTeam(tx, o)
+	case *types.TeamMember:
+		return d.insertRawTeamMember(tx, o)
+	case *types.AuditEvent:
+		return d.insertRawAuditEvent(tx, o)
+	case *types.ReceivedWebhook:
+		return d.insertRawReceivedWebhook(tx, o)
+	case *types.ProjectMirror:
+		return d.insertRawProjectMirror(tx, o)
 
 	default:
 		panic(errors.Errorf("unknown object type %T", obj))
@@ -1580,6 +2418,18 @@ func (d *DB) SelectObject(kind string) *sq.SelectBuilder {
 		return variableSelect()
 	case "OrgInvitation":
 		return orgInvitationSelect()
+	case "RoleBinding":
+		return roleBindingSelect()
+	case "Team":
+		return teamSelect()
+	case "TeamMember":
+		return teamMemberSelect()
+	case "AuditEvent":
+		return auditEventSelect()
+	case "ReceivedWebhook":
+		return receivedWebhookSelect()
+	case "ProjectMirror":
+		return projectMirrorSelect()
 
 	default:
 		panic(errors.Errorf("unknown object kind %q", kind))
@@ -1719,6 +2569,78 @@ func (d *DB) FetchObjects(tx *sql.Tx, kind string, q sq.Builder) ([]sqlg.Object,
 		        objs[i] = fobj
 		}
 
+		return objs, nil
+	case "RoleBinding":
+		fobjs, _, err := d.fetchRoleBindings(tx, q)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		objs := make([]sqlg.Object, len(fobjs))
+		for i, fobj := range fobjs {
+		        objs[i] = fobj
+		}
+
+		return objs, nil
+	case "Team":
+		fobjs, _, err := d.fetchTeams(tx, q)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		objs := make([]sqlg.Object, len(fobjs))
+		for i, fobj := range fobjs {
+		        objs[i] = fobj
+		}
+
+		return objs, nil
+	case "TeamMember":
+		fobjs, _, err := d.fetchTeamMembers(tx, q)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		objs := make([]sqlg.Object, len(fobjs))
+		for i, fobj := range fobjs {
+		        objs[i] = fobj
+		}
+
+		return objs, nil
+	case "AuditEvent":
+		fobjs, _, err := d.fetchAuditEvents(tx, q)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		objs := make([]sqlg.Object, len(fobjs))
+		for i, fobj := range fobjs {
+		        objs[i] = fobj
+		}
+
+		return objs, nil
+	case "ReceivedWebhook":
+		fobjs, _, err := d.fetchReceivedWebhooks(tx, q)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		objs := make([]sqlg.Object, len(fobjs))
+		for i, fobj := range fobjs {
+		        objs[i] = fobj
+		}
+
+		return objs, nil
+	case "ProjectMirror":
+		fobjs, _, err := d.fetchProjectMirrors(tx, q)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		objs := make([]sqlg.Object, len(fobjs))
+		for i, fobj := range fobjs {
+		        objs[i] = fobj
+		}
+
 		return objs, nil
 
 	default:
@@ -1859,6 +2781,78 @@ func (d *DB) ObjectToExportJSON(obj sqlg.Object, e *json.Encoder) error {
 			return errors.WithStack(err)
 		}
 
+		return nil
+	case *types.RoleBinding:
+		type exportObject struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+			*types.RoleBinding
+		}
+
+		if err := e.Encode(&exportObject{ExportMeta: sqlg.ExportMeta{ Kind: "RoleBinding" }, RoleBinding: o}); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	case *types.Team:
+		type exportObject struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+			*types.Team
+		}
+
+		if err := e.Encode(&exportObject{ExportMeta: sqlg.ExportMeta{ Kind: "Team" }, Team: o}); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	case *types.TeamMember:
+		type exportObject struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+			*types.TeamMember
+		}
+
+		if err := e.Encode(&exportObject{ExportMeta: sqlg.ExportMeta{ Kind: "TeamMember" }, TeamMember: o}); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	case *types.AuditEvent:
+		type exportObject struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+			*types.AuditEvent
+		}
+
+		if err := e.Encode(&exportObject{ExportMeta: sqlg.ExportMeta{ Kind: "AuditEvent" }, AuditEvent: o}); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	case *types.ReceivedWebhook:
+		type exportObject struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+			*types.ReceivedWebhook
+		}
+
+		if err := e.Encode(&exportObject{ExportMeta: sqlg.ExportMeta{ Kind: "ReceivedWebhook" }, ReceivedWebhook: o}); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return nil
+	case *types.ProjectMirror:
+		type exportObject struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+			*types.ProjectMirror
+		}
+
+		if err := e.Encode(&exportObject{ExportMeta: sqlg.ExportMeta{ Kind: "ProjectMirror" }, ProjectMirror: o}); err != nil {
+			return errors.WithStack(err)
+		}
+
 		return nil
 
 	default:
@@ -1963,11 +2957,29 @@ func (d *DB) PopulateSequences(tx *sql.Tx) error {
 }
 
 func (d *DB) populateSequencesPostgres(tx *sql.Tx) error {
+	var q string
+	q = "SELECT setval('auditevent_sequence_seq', (SELECT COALESCE(MAX(sequence), 1) FROM auditevent));"
+	if _, err := tx.Exec(q); err != nil {
+		return errors.Wrap(err, "failed to update sequence auditevent_sequence_seq")
+	}
+	q = "SELECT setval('receivedwebhook_sequence_seq', (SELECT COALESCE(MAX(sequence), 1) FROM receivedwebhook));"
+	if _, err := tx.Exec(q); err != nil {
+		return errors.Wrap(err, "failed to update sequence receivedwebhook_sequence_seq")
+	}
 
 	return nil
 }
 
 func (d *DB) populateSequencesSqlite3(tx *sql.Tx) error {
+	var q string
+	q = "INSERT INTO sequence_t (name, value) VALUES ('auditevent_sequence_seq', (SELECT COALESCE(MAX(sequence), 1) FROM auditevent));"
+	if _, err := tx.Exec(q); err != nil {
+		return errors.Wrap(err, "failed to update sequence for auditevent_sequence_seq")
+	}
+	q = "INSERT INTO sequence_t (name, value) VALUES ('receivedwebhook_sequence_seq', (SELECT COALESCE(MAX(sequence), 1) FROM receivedwebhook));"
+	if _, err := tx.Exec(q); err != nil {
+		return errors.Wrap(err, "failed to update sequence for receivedwebhook_sequence_seq")
+	}
 
 	return nil
 }
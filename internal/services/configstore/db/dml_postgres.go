@@ -543,3 +543,363 @@ func (d *DB) insertRawOrgInvitationPostgres(tx *sql.Tx, orginvitation *types.Org
 
 	return nil
 }
+var (
+	roleBindingInsertPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inParentKind types.ObjectKind, inParentID string, inSubjectKind types.ObjectKind, inSubjectID string, inRole types.MemberRole) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("rolebinding").Cols("id", "revision", "creation_time", "update_time", "parent_kind", "parent_id", "subject_kind", "subject_id", "role").Values(inID, inRevision, inCreationTime, inUpdateTime, inParentKind, inParentID, inSubjectKind, inSubjectID, inRole)
+	}
+	roleBindingUpdatePostgres = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inParentKind types.ObjectKind, inParentID string, inSubjectKind types.ObjectKind, inSubjectID string, inRole types.MemberRole) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("rolebinding").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("parent_kind", inParentKind), ub.Assign("parent_id", inParentID), ub.Assign("subject_kind", inSubjectKind), ub.Assign("subject_id", inSubjectID), ub.Assign("role", inRole)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	roleBindingInsertRawPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inParentKind types.ObjectKind, inParentID string, inSubjectKind types.ObjectKind, inSubjectID string, inRole types.MemberRole) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("rolebinding").Cols("id", "revision", "creation_time", "update_time", "parent_kind", "parent_id", "subject_kind", "subject_id", "role").SQL("OVERRIDING SYSTEM VALUE").Values(inID, inRevision, inCreationTime, inUpdateTime, inParentKind, inParentID, inSubjectKind, inSubjectID, inRole)
+	}
+)
+
+func (d *DB) insertRoleBindingPostgres(tx *sql.Tx, rolebinding *types.RoleBinding) error {
+	q := roleBindingInsertPostgres(rolebinding.ID, rolebinding.Revision, rolebinding.CreationTime, rolebinding.UpdateTime, rolebinding.Parent.Kind, rolebinding.Parent.ID, rolebinding.Subject.Kind, rolebinding.Subject.ID, rolebinding.Role)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert roleBinding")
+	}
+
+	return nil
+}
+
+func (d *DB) updateRoleBindingPostgres(tx *sql.Tx, curRevision uint64, rolebinding *types.RoleBinding) (stdsql.Result, error) {
+	q := roleBindingUpdatePostgres(curRevision, rolebinding.ID, rolebinding.Revision, rolebinding.CreationTime, rolebinding.UpdateTime, rolebinding.Parent.Kind, rolebinding.Parent.ID, rolebinding.Subject.Kind, rolebinding.Subject.ID, rolebinding.Role)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update roleBinding")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawRoleBindingPostgres(tx *sql.Tx, rolebinding *types.RoleBinding) error {
+	q := roleBindingInsertRawPostgres(rolebinding.ID, rolebinding.Revision, rolebinding.CreationTime, rolebinding.UpdateTime, rolebinding.Parent.Kind, rolebinding.Parent.ID, rolebinding.Subject.Kind, rolebinding.Subject.ID, rolebinding.Role)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert roleBinding")
+	}
+
+	return nil
+}
+var (
+	teamInsertPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inOrganizationID string, inName string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("team").Cols("id", "revision", "creation_time", "update_time", "organization_id", "name").Values(inID, inRevision, inCreationTime, inUpdateTime, inOrganizationID, inName)
+	}
+	teamUpdatePostgres = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inOrganizationID string, inName string) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("team").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("organization_id", inOrganizationID), ub.Assign("name", inName)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	teamInsertRawPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inOrganizationID string, inName string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("team").Cols("id", "revision", "creation_time", "update_time", "organization_id", "name").SQL("OVERRIDING SYSTEM VALUE").Values(inID, inRevision, inCreationTime, inUpdateTime, inOrganizationID, inName)
+	}
+)
+
+func (d *DB) insertTeamPostgres(tx *sql.Tx, team *types.Team) error {
+	q := teamInsertPostgres(team.ID, team.Revision, team.CreationTime, team.UpdateTime, team.OrganizationID, team.Name)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert team")
+	}
+
+	return nil
+}
+
+func (d *DB) updateTeamPostgres(tx *sql.Tx, curRevision uint64, team *types.Team) (stdsql.Result, error) {
+	q := teamUpdatePostgres(curRevision, team.ID, team.Revision, team.CreationTime, team.UpdateTime, team.OrganizationID, team.Name)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update team")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawTeamPostgres(tx *sql.Tx, team *types.Team) error {
+	q := teamInsertRawPostgres(team.ID, team.Revision, team.CreationTime, team.UpdateTime, team.OrganizationID, team.Name)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert team")
+	}
+
+	return nil
+}
+var (
+	teamMemberInsertPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inTeamID string, inUserID string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("teammember").Cols("id", "revision", "creation_time", "update_time", "team_id", "user_id").Values(inID, inRevision, inCreationTime, inUpdateTime, inTeamID, inUserID)
+	}
+	teamMemberUpdatePostgres = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inTeamID string, inUserID string) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("teammember").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("team_id", inTeamID), ub.Assign("user_id", inUserID)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	teamMemberInsertRawPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inTeamID string, inUserID string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("teammember").Cols("id", "revision", "creation_time", "update_time", "team_id", "user_id").SQL("OVERRIDING SYSTEM VALUE").Values(inID, inRevision, inCreationTime, inUpdateTime, inTeamID, inUserID)
+	}
+)
+
+func (d *DB) insertTeamMemberPostgres(tx *sql.Tx, teammember *types.TeamMember) error {
+	q := teamMemberInsertPostgres(teammember.ID, teammember.Revision, teammember.CreationTime, teammember.UpdateTime, teammember.TeamID, teammember.UserID)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert teamMember")
+	}
+
+	return nil
+}
+
+func (d *DB) updateTeamMemberPostgres(tx *sql.Tx, curRevision uint64, teammember *types.TeamMember) (stdsql.Result, error) {
+	q := teamMemberUpdatePostgres(curRevision, teammember.ID, teammember.Revision, teammember.CreationTime, teammember.UpdateTime, teammember.TeamID, teammember.UserID)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update teamMember")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawTeamMemberPostgres(tx *sql.Tx, teammember *types.TeamMember) error {
+	q := teamMemberInsertRawPostgres(teammember.ID, teammember.Revision, teammember.CreationTime, teammember.UpdateTime, teammember.TeamID, teammember.UserID)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert teamMember")
+	}
+
+	return nil
+}
+var (
+	auditEventInsertPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inActorUserID string, inActorUserName string, inAction types.AuditAction, inTargetKind types.ObjectKind, inTargetID string, inTargetName string, inOrganizationID string, inDetails []byte, inRequestMetadata []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("auditevent").Cols("id", "revision", "creation_time", "update_time", "actor_user_id", "actor_user_name", "action", "target_kind", "target_id", "target_name", "organization_id", "details", "request_metadata").Values(inID, inRevision, inCreationTime, inUpdateTime, inActorUserID, inActorUserName, inAction, inTargetKind, inTargetID, inTargetName, inOrganizationID, inDetails, inRequestMetadata)
+	}
+	auditEventUpdatePostgres = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inActorUserID string, inActorUserName string, inAction types.AuditAction, inTargetKind types.ObjectKind, inTargetID string, inTargetName string, inOrganizationID string, inDetails []byte, inRequestMetadata []byte) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("auditevent").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("actor_user_id", inActorUserID), ub.Assign("actor_user_name", inActorUserName), ub.Assign("action", inAction), ub.Assign("target_kind", inTargetKind), ub.Assign("target_id", inTargetID), ub.Assign("target_name", inTargetName), ub.Assign("organization_id", inOrganizationID), ub.Assign("details", inDetails), ub.Assign("request_metadata", inRequestMetadata)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	auditEventInsertRawPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inSequence uint64, inActorUserID string, inActorUserName string, inAction types.AuditAction, inTargetKind types.ObjectKind, inTargetID string, inTargetName string, inOrganizationID string, inDetails []byte, inRequestMetadata []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("auditevent").Cols("id", "revision", "creation_time", "update_time", "sequence", "actor_user_id", "actor_user_name", "action", "target_kind", "target_id", "target_name", "organization_id", "details", "request_metadata").SQL("OVERRIDING SYSTEM VALUE").Values(inID, inRevision, inCreationTime, inUpdateTime, inSequence, inActorUserID, inActorUserName, inAction, inTargetKind, inTargetID, inTargetName, inOrganizationID, inDetails, inRequestMetadata)
+	}
+)
+
+func (d *DB) insertAuditEventPostgres(tx *sql.Tx, auditevent *types.AuditEvent) error {
+	inDetailsJSON, err := json.Marshal(auditevent.Details)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.Details")
+	}
+	inRequestMetadataJSON, err := json.Marshal(auditevent.RequestMetadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.RequestMetadata")
+	}
+	q := auditEventInsertPostgres(auditevent.ID, auditevent.Revision, auditevent.CreationTime, auditevent.UpdateTime, auditevent.ActorUserID, auditevent.ActorUserName, auditevent.Action, auditevent.TargetKind, auditevent.TargetID, auditevent.TargetName, auditevent.OrganizationID, inDetailsJSON, inRequestMetadataJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert auditEvent")
+	}
+
+	return nil
+}
+
+func (d *DB) updateAuditEventPostgres(tx *sql.Tx, curRevision uint64, auditevent *types.AuditEvent) (stdsql.Result, error) {
+	inDetailsJSON, err := json.Marshal(auditevent.Details)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal auditevent.Details")
+	}
+	inRequestMetadataJSON, err := json.Marshal(auditevent.RequestMetadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal auditevent.RequestMetadata")
+	}
+	q := auditEventUpdatePostgres(curRevision, auditevent.ID, auditevent.Revision, auditevent.CreationTime, auditevent.UpdateTime, auditevent.ActorUserID, auditevent.ActorUserName, auditevent.Action, auditevent.TargetKind, auditevent.TargetID, auditevent.TargetName, auditevent.OrganizationID, inDetailsJSON, inRequestMetadataJSON)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update auditEvent")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawAuditEventPostgres(tx *sql.Tx, auditevent *types.AuditEvent) error {
+	inDetailsJSON, err := json.Marshal(auditevent.Details)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.Details")
+	}
+	inRequestMetadataJSON, err := json.Marshal(auditevent.RequestMetadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.RequestMetadata")
+	}
+	q := auditEventInsertRawPostgres(auditevent.ID, auditevent.Revision, auditevent.CreationTime, auditevent.UpdateTime, auditevent.Sequence, auditevent.ActorUserID, auditevent.ActorUserName, auditevent.Action, auditevent.TargetKind, auditevent.TargetID, auditevent.TargetName, auditevent.OrganizationID, inDetailsJSON, inRequestMetadataJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert auditEvent")
+	}
+
+	return nil
+}
+var (
+	receivedWebhookInsertPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inDeliveryID string, inEvent string, inHeader []byte, inBody []byte, inStatus types.ReceivedWebhookStatus, inAttempts int, inNextAttemptTime time.Time, inOutcome types.ReceivedWebhookOutcome, inOutcomeMessage string, inRunNumbers []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("receivedwebhook").Cols("id", "revision", "creation_time", "update_time", "project_id", "delivery_id", "event", "header", "body", "status", "attempts", "next_attempt_time", "outcome", "outcome_message", "run_numbers").Values(inID, inRevision, inCreationTime, inUpdateTime, inProjectID, inDeliveryID, inEvent, inHeader, inBody, inStatus, inAttempts, inNextAttemptTime, inOutcome, inOutcomeMessage, inRunNumbers)
+	}
+	receivedWebhookUpdatePostgres = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inDeliveryID string, inEvent string, inHeader []byte, inBody []byte, inStatus types.ReceivedWebhookStatus, inAttempts int, inNextAttemptTime time.Time, inOutcome types.ReceivedWebhookOutcome, inOutcomeMessage string, inRunNumbers []byte) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("receivedwebhook").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("project_id", inProjectID), ub.Assign("delivery_id", inDeliveryID), ub.Assign("event", inEvent), ub.Assign("header", inHeader), ub.Assign("body", inBody), ub.Assign("status", inStatus), ub.Assign("attempts", inAttempts), ub.Assign("next_attempt_time", inNextAttemptTime), ub.Assign("outcome", inOutcome), ub.Assign("outcome_message", inOutcomeMessage), ub.Assign("run_numbers", inRunNumbers)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	receivedWebhookInsertRawPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inSequence uint64, inProjectID string, inDeliveryID string, inEvent string, inHeader []byte, inBody []byte, inStatus types.ReceivedWebhookStatus, inAttempts int, inNextAttemptTime time.Time, inOutcome types.ReceivedWebhookOutcome, inOutcomeMessage string, inRunNumbers []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("receivedwebhook").Cols("id", "revision", "creation_time", "update_time", "sequence", "project_id", "delivery_id", "event", "header", "body", "status", "attempts", "next_attempt_time", "outcome", "outcome_message", "run_numbers").SQL("OVERRIDING SYSTEM VALUE").Values(inID, inRevision, inCreationTime, inUpdateTime, inSequence, inProjectID, inDeliveryID, inEvent, inHeader, inBody, inStatus, inAttempts, inNextAttemptTime, inOutcome, inOutcomeMessage, inRunNumbers)
+	}
+)
+
+func (d *DB) insertReceivedWebhookPostgres(tx *sql.Tx, receivedwebhook *types.ReceivedWebhook) error {
+	inHeaderJSON, err := json.Marshal(receivedwebhook.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.Header")
+	}
+	inRunNumbersJSON, err := json.Marshal(receivedwebhook.RunNumbers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.RunNumbers")
+	}
+	q := receivedWebhookInsertPostgres(receivedwebhook.ID, receivedwebhook.Revision, receivedwebhook.CreationTime, receivedwebhook.UpdateTime, receivedwebhook.ProjectID, receivedwebhook.DeliveryID, receivedwebhook.Event, inHeaderJSON, receivedwebhook.Body, receivedwebhook.Status, receivedwebhook.Attempts, receivedwebhook.NextAttemptTime, receivedwebhook.Outcome, receivedwebhook.OutcomeMessage, inRunNumbersJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert receivedWebhook")
+	}
+
+	return nil
+}
+
+func (d *DB) updateReceivedWebhookPostgres(tx *sql.Tx, curRevision uint64, receivedwebhook *types.ReceivedWebhook) (stdsql.Result, error) {
+	inHeaderJSON, err := json.Marshal(receivedwebhook.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal receivedwebhook.Header")
+	}
+	inRunNumbersJSON, err := json.Marshal(receivedwebhook.RunNumbers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal receivedwebhook.RunNumbers")
+	}
+	q := receivedWebhookUpdatePostgres(curRevision, receivedwebhook.ID, receivedwebhook.Revision, receivedwebhook.CreationTime, receivedwebhook.UpdateTime, receivedwebhook.ProjectID, receivedwebhook.DeliveryID, receivedwebhook.Event, inHeaderJSON, receivedwebhook.Body, receivedwebhook.Status, receivedwebhook.Attempts, receivedwebhook.NextAttemptTime, receivedwebhook.Outcome, receivedwebhook.OutcomeMessage, inRunNumbersJSON)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update receivedWebhook")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawReceivedWebhookPostgres(tx *sql.Tx, receivedwebhook *types.ReceivedWebhook) error {
+	inHeaderJSON, err := json.Marshal(receivedwebhook.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.Header")
+	}
+	inRunNumbersJSON, err := json.Marshal(receivedwebhook.RunNumbers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.RunNumbers")
+	}
+	q := receivedWebhookInsertRawPostgres(receivedwebhook.ID, receivedwebhook.Revision, receivedwebhook.CreationTime, receivedwebhook.UpdateTime, receivedwebhook.Sequence, receivedwebhook.ProjectID, receivedwebhook.DeliveryID, receivedwebhook.Event, inHeaderJSON, receivedwebhook.Body, receivedwebhook.Status, receivedwebhook.Attempts, receivedwebhook.NextAttemptTime, receivedwebhook.Outcome, receivedwebhook.OutcomeMessage, inRunNumbersJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert receivedWebhook")
+	}
+
+	return nil
+}
+var (
+	projectMirrorInsertPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inURL string, inSSHHostKey string, inBranches []byte, inTags []byte, inRefs []byte, inPollCount uint64, inNextPollTime time.Time, inLastPollTime *time.Time, inLastPollError string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("projectmirror").Cols("id", "revision", "creation_time", "update_time", "project_id", "url", "ssh_host_key", "branches", "tags", "refs", "poll_count", "next_poll_time", "last_poll_time", "last_poll_error").Values(inID, inRevision, inCreationTime, inUpdateTime, inProjectID, inURL, inSSHHostKey, inBranches, inTags, inRefs, inPollCount, inNextPollTime, inLastPollTime, inLastPollError)
+	}
+	projectMirrorUpdatePostgres = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inURL string, inSSHHostKey string, inBranches []byte, inTags []byte, inRefs []byte, inPollCount uint64, inNextPollTime time.Time, inLastPollTime *time.Time, inLastPollError string) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("projectmirror").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("project_id", inProjectID), ub.Assign("url", inURL), ub.Assign("ssh_host_key", inSSHHostKey), ub.Assign("branches", inBranches), ub.Assign("tags", inTags), ub.Assign("refs", inRefs), ub.Assign("poll_count", inPollCount), ub.Assign("next_poll_time", inNextPollTime), ub.Assign("last_poll_time", inLastPollTime), ub.Assign("last_poll_error", inLastPollError)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	projectMirrorInsertRawPostgres = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inURL string, inSSHHostKey string, inBranches []byte, inTags []byte, inRefs []byte, inPollCount uint64, inNextPollTime time.Time, inLastPollTime *time.Time, inLastPollError string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("projectmirror").Cols("id", "revision", "creation_time", "update_time", "project_id", "url", "ssh_host_key", "branches", "tags", "refs", "poll_count", "next_poll_time", "last_poll_time", "last_poll_error").SQL("OVERRIDING SYSTEM VALUE").Values(inID, inRevision, inCreationTime, inUpdateTime, inProjectID, inURL, inSSHHostKey, inBranches, inTags, inRefs, inPollCount, inNextPollTime, inLastPollTime, inLastPollError)
+	}
+)
+
+func (d *DB) insertProjectMirrorPostgres(tx *sql.Tx, projectmirror *types.ProjectMirror) error {
+	inBranchesJSON, err := json.Marshal(projectmirror.Branches)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Branches")
+	}
+	inTagsJSON, err := json.Marshal(projectmirror.Tags)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Tags")
+	}
+	inRefsJSON, err := json.Marshal(projectmirror.Refs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Refs")
+	}
+	q := projectMirrorInsertPostgres(projectmirror.ID, projectmirror.Revision, projectmirror.CreationTime, projectmirror.UpdateTime, projectmirror.ProjectID, projectmirror.URL, projectmirror.SSHHostKey, inBranchesJSON, inTagsJSON, inRefsJSON, projectmirror.PollCount, projectmirror.NextPollTime, projectmirror.LastPollTime, projectmirror.LastPollError)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert projectMirror")
+	}
+
+	return nil
+}
+
+func (d *DB) updateProjectMirrorPostgres(tx *sql.Tx, curRevision uint64, projectmirror *types.ProjectMirror) (stdsql.Result, error) {
+	inBranchesJSON, err := json.Marshal(projectmirror.Branches)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal projectmirror.Branches")
+	}
+	inTagsJSON, err := json.Marshal(projectmirror.Tags)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal projectmirror.Tags")
+	}
+	inRefsJSON, err := json.Marshal(projectmirror.Refs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal projectmirror.Refs")
+	}
+	q := projectMirrorUpdatePostgres(curRevision, projectmirror.ID, projectmirror.Revision, projectmirror.CreationTime, projectmirror.UpdateTime, projectmirror.ProjectID, projectmirror.URL, projectmirror.SSHHostKey, inBranchesJSON, inTagsJSON, inRefsJSON, projectmirror.PollCount, projectmirror.NextPollTime, projectmirror.LastPollTime, projectmirror.LastPollError)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update projectMirror")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawProjectMirrorPostgres(tx *sql.Tx, projectmirror *types.ProjectMirror) error {
+	inBranchesJSON, err := json.Marshal(projectmirror.Branches)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Branches")
+	}
+	inTagsJSON, err := json.Marshal(projectmirror.Tags)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Tags")
+	}
+	inRefsJSON, err := json.Marshal(projectmirror.Refs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Refs")
+	}
+	q := projectMirrorInsertRawPostgres(projectmirror.ID, projectmirror.Revision, projectmirror.CreationTime, projectmirror.UpdateTime, projectmirror.ProjectID, projectmirror.URL, projectmirror.SSHHostKey, inBranchesJSON, inTagsJSON, inRefsJSON, projectmirror.PollCount, projectmirror.NextPollTime, projectmirror.LastPollTime, projectmirror.LastPollError)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert projectMirror")
+	}
+
+	return nil
+}
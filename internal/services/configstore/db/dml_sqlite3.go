@@ -543,3 +543,363 @@ func (d *DB) insertRawOrgInvitationSqlite3(tx *sql.Tx, orginvitation *types.OrgI
 
 	return nil
 }
+var (
+	roleBindingInsertSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inParentKind types.ObjectKind, inParentID string, inSubjectKind types.ObjectKind, inSubjectID string, inRole types.MemberRole) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("rolebinding").Cols("id", "revision", "creation_time", "update_time", "parent_kind", "parent_id", "subject_kind", "subject_id", "role").Values(inID, inRevision, inCreationTime, inUpdateTime, inParentKind, inParentID, inSubjectKind, inSubjectID, inRole)
+	}
+	roleBindingUpdateSqlite3 = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inParentKind types.ObjectKind, inParentID string, inSubjectKind types.ObjectKind, inSubjectID string, inRole types.MemberRole) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("rolebinding").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("parent_kind", inParentKind), ub.Assign("parent_id", inParentID), ub.Assign("subject_kind", inSubjectKind), ub.Assign("subject_id", inSubjectID), ub.Assign("role", inRole)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	roleBindingInsertRawSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inParentKind types.ObjectKind, inParentID string, inSubjectKind types.ObjectKind, inSubjectID string, inRole types.MemberRole) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("rolebinding").Cols("id", "revision", "creation_time", "update_time", "parent_kind", "parent_id", "subject_kind", "subject_id", "role").SQL("").Values(inID, inRevision, inCreationTime, inUpdateTime, inParentKind, inParentID, inSubjectKind, inSubjectID, inRole)
+	}
+)
+
+func (d *DB) insertRoleBindingSqlite3(tx *sql.Tx, rolebinding *types.RoleBinding) error {
+	q := roleBindingInsertSqlite3(rolebinding.ID, rolebinding.Revision, rolebinding.CreationTime, rolebinding.UpdateTime, rolebinding.Parent.Kind, rolebinding.Parent.ID, rolebinding.Subject.Kind, rolebinding.Subject.ID, rolebinding.Role)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert roleBinding")
+	}
+
+	return nil
+}
+
+func (d *DB) updateRoleBindingSqlite3(tx *sql.Tx, curRevision uint64, rolebinding *types.RoleBinding) (stdsql.Result, error) {
+	q := roleBindingUpdateSqlite3(curRevision, rolebinding.ID, rolebinding.Revision, rolebinding.CreationTime, rolebinding.UpdateTime, rolebinding.Parent.Kind, rolebinding.Parent.ID, rolebinding.Subject.Kind, rolebinding.Subject.ID, rolebinding.Role)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update roleBinding")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawRoleBindingSqlite3(tx *sql.Tx, rolebinding *types.RoleBinding) error {
+	q := roleBindingInsertRawSqlite3(rolebinding.ID, rolebinding.Revision, rolebinding.CreationTime, rolebinding.UpdateTime, rolebinding.Parent.Kind, rolebinding.Parent.ID, rolebinding.Subject.Kind, rolebinding.Subject.ID, rolebinding.Role)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert roleBinding")
+	}
+
+	return nil
+}
+var (
+	teamInsertSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inOrganizationID string, inName string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("team").Cols("id", "revision", "creation_time", "update_time", "organization_id", "name").Values(inID, inRevision, inCreationTime, inUpdateTime, inOrganizationID, inName)
+	}
+	teamUpdateSqlite3 = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inOrganizationID string, inName string) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("team").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("organization_id", inOrganizationID), ub.Assign("name", inName)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	teamInsertRawSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inOrganizationID string, inName string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("team").Cols("id", "revision", "creation_time", "update_time", "organization_id", "name").SQL("").Values(inID, inRevision, inCreationTime, inUpdateTime, inOrganizationID, inName)
+	}
+)
+
+func (d *DB) insertTeamSqlite3(tx *sql.Tx, team *types.Team) error {
+	q := teamInsertSqlite3(team.ID, team.Revision, team.CreationTime, team.UpdateTime, team.OrganizationID, team.Name)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert team")
+	}
+
+	return nil
+}
+
+func (d *DB) updateTeamSqlite3(tx *sql.Tx, curRevision uint64, team *types.Team) (stdsql.Result, error) {
+	q := teamUpdateSqlite3(curRevision, team.ID, team.Revision, team.CreationTime, team.UpdateTime, team.OrganizationID, team.Name)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update team")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawTeamSqlite3(tx *sql.Tx, team *types.Team) error {
+	q := teamInsertRawSqlite3(team.ID, team.Revision, team.CreationTime, team.UpdateTime, team.OrganizationID, team.Name)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert team")
+	}
+
+	return nil
+}
+var (
+	teamMemberInsertSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inTeamID string, inUserID string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("teammember").Cols("id", "revision", "creation_time", "update_time", "team_id", "user_id").Values(inID, inRevision, inCreationTime, inUpdateTime, inTeamID, inUserID)
+	}
+	teamMemberUpdateSqlite3 = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inTeamID string, inUserID string) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("teammember").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("team_id", inTeamID), ub.Assign("user_id", inUserID)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	teamMemberInsertRawSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inTeamID string, inUserID string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("teammember").Cols("id", "revision", "creation_time", "update_time", "team_id", "user_id").SQL("").Values(inID, inRevision, inCreationTime, inUpdateTime, inTeamID, inUserID)
+	}
+)
+
+func (d *DB) insertTeamMemberSqlite3(tx *sql.Tx, teammember *types.TeamMember) error {
+	q := teamMemberInsertSqlite3(teammember.ID, teammember.Revision, teammember.CreationTime, teammember.UpdateTime, teammember.TeamID, teammember.UserID)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert teamMember")
+	}
+
+	return nil
+}
+
+func (d *DB) updateTeamMemberSqlite3(tx *sql.Tx, curRevision uint64, teammember *types.TeamMember) (stdsql.Result, error) {
+	q := teamMemberUpdateSqlite3(curRevision, teammember.ID, teammember.Revision, teammember.CreationTime, teammember.UpdateTime, teammember.TeamID, teammember.UserID)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update teamMember")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawTeamMemberSqlite3(tx *sql.Tx, teammember *types.TeamMember) error {
+	q := teamMemberInsertRawSqlite3(teammember.ID, teammember.Revision, teammember.CreationTime, teammember.UpdateTime, teammember.TeamID, teammember.UserID)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert teamMember")
+	}
+
+	return nil
+}
+var (
+	auditEventInsertSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inSequence uint64, inActorUserID string, inActorUserName string, inAction types.AuditAction, inTargetKind types.ObjectKind, inTargetID string, inTargetName string, inOrganizationID string, inDetails []byte, inRequestMetadata []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("auditevent").Cols("id", "revision", "creation_time", "update_time", "sequence", "actor_user_id", "actor_user_name", "action", "target_kind", "target_id", "target_name", "organization_id", "details", "request_metadata").Values(inID, inRevision, inCreationTime, inUpdateTime, inSequence, inActorUserID, inActorUserName, inAction, inTargetKind, inTargetID, inTargetName, inOrganizationID, inDetails, inRequestMetadata)
+	}
+	auditEventUpdateSqlite3 = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inActorUserID string, inActorUserName string, inAction types.AuditAction, inTargetKind types.ObjectKind, inTargetID string, inTargetName string, inOrganizationID string, inDetails []byte, inRequestMetadata []byte) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("auditevent").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("actor_user_id", inActorUserID), ub.Assign("actor_user_name", inActorUserName), ub.Assign("action", inAction), ub.Assign("target_kind", inTargetKind), ub.Assign("target_id", inTargetID), ub.Assign("target_name", inTargetName), ub.Assign("organization_id", inOrganizationID), ub.Assign("details", inDetails), ub.Assign("request_metadata", inRequestMetadata)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	auditEventInsertRawSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inSequence uint64, inActorUserID string, inActorUserName string, inAction types.AuditAction, inTargetKind types.ObjectKind, inTargetID string, inTargetName string, inOrganizationID string, inDetails []byte, inRequestMetadata []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("auditevent").Cols("id", "revision", "creation_time", "update_time", "sequence", "actor_user_id", "actor_user_name", "action", "target_kind", "target_id", "target_name", "organization_id", "details", "request_metadata").SQL("").Values(inID, inRevision, inCreationTime, inUpdateTime, inSequence, inActorUserID, inActorUserName, inAction, inTargetKind, inTargetID, inTargetName, inOrganizationID, inDetails, inRequestMetadata)
+	}
+)
+
+func (d *DB) insertAuditEventSqlite3(tx *sql.Tx, auditevent *types.AuditEvent) error {
+	inDetailsJSON, err := json.Marshal(auditevent.Details)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.Details")
+	}
+	inRequestMetadataJSON, err := json.Marshal(auditevent.RequestMetadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.RequestMetadata")
+	}
+	q := auditEventInsertSqlite3(auditevent.ID, auditevent.Revision, auditevent.CreationTime, auditevent.UpdateTime, auditevent.Sequence, auditevent.ActorUserID, auditevent.ActorUserName, auditevent.Action, auditevent.TargetKind, auditevent.TargetID, auditevent.TargetName, auditevent.OrganizationID, inDetailsJSON, inRequestMetadataJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert auditEvent")
+	}
+
+	return nil
+}
+
+func (d *DB) updateAuditEventSqlite3(tx *sql.Tx, curRevision uint64, auditevent *types.AuditEvent) (stdsql.Result, error) {
+	inDetailsJSON, err := json.Marshal(auditevent.Details)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal auditevent.Details")
+	}
+	inRequestMetadataJSON, err := json.Marshal(auditevent.RequestMetadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal auditevent.RequestMetadata")
+	}
+	q := auditEventUpdateSqlite3(curRevision, auditevent.ID, auditevent.Revision, auditevent.CreationTime, auditevent.UpdateTime, auditevent.ActorUserID, auditevent.ActorUserName, auditevent.Action, auditevent.TargetKind, auditevent.TargetID, auditevent.TargetName, auditevent.OrganizationID, inDetailsJSON, inRequestMetadataJSON)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update auditEvent")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawAuditEventSqlite3(tx *sql.Tx, auditevent *types.AuditEvent) error {
+	inDetailsJSON, err := json.Marshal(auditevent.Details)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.Details")
+	}
+	inRequestMetadataJSON, err := json.Marshal(auditevent.RequestMetadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal auditevent.RequestMetadata")
+	}
+	q := auditEventInsertRawSqlite3(auditevent.ID, auditevent.Revision, auditevent.CreationTime, auditevent.UpdateTime, auditevent.Sequence, auditevent.ActorUserID, auditevent.ActorUserName, auditevent.Action, auditevent.TargetKind, auditevent.TargetID, auditevent.TargetName, auditevent.OrganizationID, inDetailsJSON, inRequestMetadataJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert auditEvent")
+	}
+
+	return nil
+}
+var (
+	receivedWebhookInsertSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inSequence uint64, inProjectID string, inDeliveryID string, inEvent string, inHeader []byte, inBody []byte, inStatus types.ReceivedWebhookStatus, inAttempts int, inNextAttemptTime time.Time, inOutcome types.ReceivedWebhookOutcome, inOutcomeMessage string, inRunNumbers []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("receivedwebhook").Cols("id", "revision", "creation_time", "update_time", "sequence", "project_id", "delivery_id", "event", "header", "body", "status", "attempts", "next_attempt_time", "outcome", "outcome_message", "run_numbers").Values(inID, inRevision, inCreationTime, inUpdateTime, inSequence, inProjectID, inDeliveryID, inEvent, inHeader, inBody, inStatus, inAttempts, inNextAttemptTime, inOutcome, inOutcomeMessage, inRunNumbers)
+	}
+	receivedWebhookUpdateSqlite3 = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inDeliveryID string, inEvent string, inHeader []byte, inBody []byte, inStatus types.ReceivedWebhookStatus, inAttempts int, inNextAttemptTime time.Time, inOutcome types.ReceivedWebhookOutcome, inOutcomeMessage string, inRunNumbers []byte) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("receivedwebhook").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("project_id", inProjectID), ub.Assign("delivery_id", inDeliveryID), ub.Assign("event", inEvent), ub.Assign("header", inHeader), ub.Assign("body", inBody), ub.Assign("status", inStatus), ub.Assign("attempts", inAttempts), ub.Assign("next_attempt_time", inNextAttemptTime), ub.Assign("outcome", inOutcome), ub.Assign("outcome_message", inOutcomeMessage), ub.Assign("run_numbers", inRunNumbers)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	receivedWebhookInsertRawSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inSequence uint64, inProjectID string, inDeliveryID string, inEvent string, inHeader []byte, inBody []byte, inStatus types.ReceivedWebhookStatus, inAttempts int, inNextAttemptTime time.Time, inOutcome types.ReceivedWebhookOutcome, inOutcomeMessage string, inRunNumbers []byte) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("receivedwebhook").Cols("id", "revision", "creation_time", "update_time", "sequence", "project_id", "delivery_id", "event", "header", "body", "status", "attempts", "next_attempt_time", "outcome", "outcome_message", "run_numbers").SQL("").Values(inID, inRevision, inCreationTime, inUpdateTime, inSequence, inProjectID, inDeliveryID, inEvent, inHeader, inBody, inStatus, inAttempts, inNextAttemptTime, inOutcome, inOutcomeMessage, inRunNumbers)
+	}
+)
+
+func (d *DB) insertReceivedWebhookSqlite3(tx *sql.Tx, receivedwebhook *types.ReceivedWebhook) error {
+	inHeaderJSON, err := json.Marshal(receivedwebhook.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.Header")
+	}
+	inRunNumbersJSON, err := json.Marshal(receivedwebhook.RunNumbers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.RunNumbers")
+	}
+	q := receivedWebhookInsertSqlite3(receivedwebhook.ID, receivedwebhook.Revision, receivedwebhook.CreationTime, receivedwebhook.UpdateTime, receivedwebhook.Sequence, receivedwebhook.ProjectID, receivedwebhook.DeliveryID, receivedwebhook.Event, inHeaderJSON, receivedwebhook.Body, receivedwebhook.Status, receivedwebhook.Attempts, receivedwebhook.NextAttemptTime, receivedwebhook.Outcome, receivedwebhook.OutcomeMessage, inRunNumbersJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert receivedWebhook")
+	}
+
+	return nil
+}
+
+func (d *DB) updateReceivedWebhookSqlite3(tx *sql.Tx, curRevision uint64, receivedwebhook *types.ReceivedWebhook) (stdsql.Result, error) {
+	inHeaderJSON, err := json.Marshal(receivedwebhook.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal receivedwebhook.Header")
+	}
+	inRunNumbersJSON, err := json.Marshal(receivedwebhook.RunNumbers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal receivedwebhook.RunNumbers")
+	}
+	q := receivedWebhookUpdateSqlite3(curRevision, receivedwebhook.ID, receivedwebhook.Revision, receivedwebhook.CreationTime, receivedwebhook.UpdateTime, receivedwebhook.ProjectID, receivedwebhook.DeliveryID, receivedwebhook.Event, inHeaderJSON, receivedwebhook.Body, receivedwebhook.Status, receivedwebhook.Attempts, receivedwebhook.NextAttemptTime, receivedwebhook.Outcome, receivedwebhook.OutcomeMessage, inRunNumbersJSON)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update receivedWebhook")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawReceivedWebhookSqlite3(tx *sql.Tx, receivedwebhook *types.ReceivedWebhook) error {
+	inHeaderJSON, err := json.Marshal(receivedwebhook.Header)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.Header")
+	}
+	inRunNumbersJSON, err := json.Marshal(receivedwebhook.RunNumbers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal receivedwebhook.RunNumbers")
+	}
+	q := receivedWebhookInsertRawSqlite3(receivedwebhook.ID, receivedwebhook.Revision, receivedwebhook.CreationTime, receivedwebhook.UpdateTime, receivedwebhook.Sequence, receivedwebhook.ProjectID, receivedwebhook.DeliveryID, receivedwebhook.Event, inHeaderJSON, receivedwebhook.Body, receivedwebhook.Status, receivedwebhook.Attempts, receivedwebhook.NextAttemptTime, receivedwebhook.Outcome, receivedwebhook.OutcomeMessage, inRunNumbersJSON)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert receivedWebhook")
+	}
+
+	return nil
+}
+var (
+	projectMirrorInsertSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inURL string, inSSHHostKey string, inBranches []byte, inTags []byte, inRefs []byte, inPollCount uint64, inNextPollTime time.Time, inLastPollTime *time.Time, inLastPollError string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("projectmirror").Cols("id", "revision", "creation_time", "update_time", "project_id", "url", "ssh_host_key", "branches", "tags", "refs", "poll_count", "next_poll_time", "last_poll_time", "last_poll_error").Values(inID, inRevision, inCreationTime, inUpdateTime, inProjectID, inURL, inSSHHostKey, inBranches, inTags, inRefs, inPollCount, inNextPollTime, inLastPollTime, inLastPollError)
+	}
+	projectMirrorUpdateSqlite3 = func(curRevision uint64, inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inURL string, inSSHHostKey string, inBranches []byte, inTags []byte, inRefs []byte, inPollCount uint64, inNextPollTime time.Time, inLastPollTime *time.Time, inLastPollError string) *sq.UpdateBuilder {
+		ub:= sq.NewUpdateBuilder()
+		return ub.Update("projectmirror").Set(ub.Assign("id", inID), ub.Assign("revision", inRevision), ub.Assign("creation_time", inCreationTime), ub.Assign("update_time", inUpdateTime), ub.Assign("project_id", inProjectID), ub.Assign("url", inURL), ub.Assign("ssh_host_key", inSSHHostKey), ub.Assign("branches", inBranches), ub.Assign("tags", inTags), ub.Assign("refs", inRefs), ub.Assign("poll_count", inPollCount), ub.Assign("next_poll_time", inNextPollTime), ub.Assign("last_poll_time", inLastPollTime), ub.Assign("last_poll_error", inLastPollError)).Where(ub.E("id", inID), ub.E("revision", curRevision))
+	}
+
+	projectMirrorInsertRawSqlite3 = func(inID string, inRevision uint64, inCreationTime time.Time, inUpdateTime time.Time, inProjectID string, inURL string, inSSHHostKey string, inBranches []byte, inTags []byte, inRefs []byte, inPollCount uint64, inNextPollTime time.Time, inLastPollTime *time.Time, inLastPollError string) *sq.InsertBuilder {
+		ib:= sq.NewInsertBuilder()
+		return ib.InsertInto("projectmirror").Cols("id", "revision", "creation_time", "update_time", "project_id", "url", "ssh_host_key", "branches", "tags", "refs", "poll_count", "next_poll_time", "last_poll_time", "last_poll_error").SQL("").Values(inID, inRevision, inCreationTime, inUpdateTime, inProjectID, inURL, inSSHHostKey, inBranches, inTags, inRefs, inPollCount, inNextPollTime, inLastPollTime, inLastPollError)
+	}
+)
+
+func (d *DB) insertProjectMirrorSqlite3(tx *sql.Tx, projectmirror *types.ProjectMirror) error {
+	inBranchesJSON, err := json.Marshal(projectmirror.Branches)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Branches")
+	}
+	inTagsJSON, err := json.Marshal(projectmirror.Tags)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Tags")
+	}
+	inRefsJSON, err := json.Marshal(projectmirror.Refs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Refs")
+	}
+	q := projectMirrorInsertSqlite3(projectmirror.ID, projectmirror.Revision, projectmirror.CreationTime, projectmirror.UpdateTime, projectmirror.ProjectID, projectmirror.URL, projectmirror.SSHHostKey, inBranchesJSON, inTagsJSON, inRefsJSON, projectmirror.PollCount, projectmirror.NextPollTime, projectmirror.LastPollTime, projectmirror.LastPollError)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert projectMirror")
+	}
+
+	return nil
+}
+
+func (d *DB) updateProjectMirrorSqlite3(tx *sql.Tx, curRevision uint64, projectmirror *types.ProjectMirror) (stdsql.Result, error) {
+	inBranchesJSON, err := json.Marshal(projectmirror.Branches)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal projectmirror.Branches")
+	}
+	inTagsJSON, err := json.Marshal(projectmirror.Tags)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal projectmirror.Tags")
+	}
+	inRefsJSON, err := json.Marshal(projectmirror.Refs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal projectmirror.Refs")
+	}
+	q := projectMirrorUpdateSqlite3(curRevision, projectmirror.ID, projectmirror.Revision, projectmirror.CreationTime, projectmirror.UpdateTime, projectmirror.ProjectID, projectmirror.URL, projectmirror.SSHHostKey, inBranchesJSON, inTagsJSON, inRefsJSON, projectmirror.PollCount, projectmirror.NextPollTime, projectmirror.LastPollTime, projectmirror.LastPollError)
+
+	res, err := d.exec(tx, q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update projectMirror")
+	}
+
+	return res, nil
+}
+
+func (d *DB) insertRawProjectMirrorSqlite3(tx *sql.Tx, projectmirror *types.ProjectMirror) error {
+	inBranchesJSON, err := json.Marshal(projectmirror.Branches)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Branches")
+	}
+	inTagsJSON, err := json.Marshal(projectmirror.Tags)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Tags")
+	}
+	inRefsJSON, err := json.Marshal(projectmirror.Refs)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal projectmirror.Refs")
+	}
+	q := projectMirrorInsertRawSqlite3(projectmirror.ID, projectmirror.Revision, projectmirror.CreationTime, projectmirror.UpdateTime, projectmirror.ProjectID, projectmirror.URL, projectmirror.SSHHostKey, inBranchesJSON, inTagsJSON, inRefsJSON, projectmirror.PollCount, projectmirror.NextPollTime, projectmirror.LastPollTime, projectmirror.LastPollError)
+
+	if _, err := d.exec(tx, q); err != nil {
+		return errors.Wrap(err, "failed to insert projectMirror")
+	}
+
+	return nil
+}
@@ -1260,3 +1260,727 @@ func (d *DB) OrgInvitationFromArray(a []any, txID string) (*types.OrgInvitation,
 
 	return v, v.ID, nil
 }
+
+func (d *DB) fetchRoleBindings(tx *sql.Tx, q sq.Builder) ([]*types.RoleBinding, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanRoleBindings(rows, tx.ID(), 0)
+}
+
+func (d *DB) fetchRoleBindingsSkipLastFields(tx *sql.Tx, q sq.Builder, skipFieldsCount uint) ([]*types.RoleBinding, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanRoleBindings(rows, tx.ID(), skipFieldsCount)
+}
+
+func (d *DB) scanRoleBinding(rows *stdsql.Rows, skipFieldsCount uint) (*types.RoleBinding, string, error) {
+
+	v := &types.RoleBinding{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+
+	fields := []any{&v.ID, &v.Revision, &v.CreationTime, &v.UpdateTime, &v.Parent.Kind, &v.Parent.ID, &v.Subject.Kind, &v.Subject.ID, &v.Role}
+
+	for i := uint(0); i < skipFieldsCount; i++ {
+		fields = append(fields, new(any))
+	}
+
+	if err := rows.Scan(fields...); err != nil {
+		return nil, "", errors.Wrap(err, "failed to scan row")
+	}
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+
+	return v, v.ID, nil
+}
+
+func (d *DB) scanRoleBindings(rows *stdsql.Rows, txID string, skipFieldsCount uint) ([]*types.RoleBinding, []string, error) {
+	vs := []*types.RoleBinding{}
+	ids := []string{}
+	for rows.Next() {
+		v, id, err := d.scanRoleBinding(rows, skipFieldsCount)
+		if err != nil {
+			rows.Close()
+			return nil, nil, errors.WithStack(err)
+		}
+		v.TxID = txID
+		vs = append(vs, v)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return vs, ids, nil
+}
+
+func (d *DB) RoleBindingArray() []any {
+	a := []any{}
+	a = append(a, new(string))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(time.Time))
+	a = append(a, new(types.ObjectKind))
+	a = append(a, new(string))
+	a = append(a, new(types.ObjectKind))
+	a = append(a, new(string))
+	a = append(a, new(types.MemberRole))
+
+	return a
+}
+
+func (d *DB) RoleBindingFromArray(a []any, txID string) (*types.RoleBinding, string, error) {
+	v := &types.RoleBinding{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+	v.ID = *a[0].(*string)
+	v.Revision = *a[1].(*uint64)
+	v.CreationTime = *a[2].(*time.Time)
+	v.UpdateTime = *a[3].(*time.Time)
+	v.Parent.Kind = *a[4].(*types.ObjectKind)
+	v.Parent.ID = *a[5].(*string)
+	v.Subject.Kind = *a[6].(*types.ObjectKind)
+	v.Subject.ID = *a[7].(*string)
+	v.Role = *a[8].(*types.MemberRole)
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+
+	v.TxID = txID
+
+	return v, v.ID, nil
+}
+
+func (d *DB) fetchTeams(tx *sql.Tx, q sq.Builder) ([]*types.Team, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanTeams(rows, tx.ID(), 0)
+}
+
+func (d *DB) fetchTeamsSkipLastFields(tx *sql.Tx, q sq.Builder, skipFieldsCount uint) ([]*types.Team, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanTeams(rows, tx.ID(), skipFieldsCount)
+}
+
+func (d *DB) scanTeam(rows *stdsql.Rows, skipFieldsCount uint) (*types.Team, string, error) {
+
+	v := &types.Team{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+
+	fields := []any{&v.ID, &v.Revision, &v.CreationTime, &v.UpdateTime, &v.OrganizationID, &v.Name}
+
+	for i := uint(0); i < skipFieldsCount; i++ {
+		fields = append(fields, new(any))
+	}
+
+	if err := rows.Scan(fields...); err != nil {
+		return nil, "", errors.Wrap(err, "failed to scan row")
+	}
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+
+	return v, v.ID, nil
+}
+
+func (d *DB) scanTeams(rows *stdsql.Rows, txID string, skipFieldsCount uint) ([]*types.Team, []string, error) {
+	vs := []*types.Team{}
+	ids := []string{}
+	for rows.Next() {
+		v, id, err := d.scanTeam(rows, skipFieldsCount)
+		if err != nil {
+			rows.Close()
+			return nil, nil, errors.WithStack(err)
+		}
+		v.TxID = txID
+		vs = append(vs, v)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return vs, ids, nil
+}
+
+func (d *DB) TeamArray() []any {
+	a := []any{}
+	a = append(a, new(string))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(time.Time))
+	a = append(a, new(string))
+	a = append(a, new(string))
+
+	return a
+}
+
+func (d *DB) TeamFromArray(a []any, txID string) (*types.Team, string, error) {
+	v := &types.Team{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+	v.ID = *a[0].(*string)
+	v.Revision = *a[1].(*uint64)
+	v.CreationTime = *a[2].(*time.Time)
+	v.UpdateTime = *a[3].(*time.Time)
+	v.OrganizationID = *a[4].(*string)
+	v.Name = *a[5].(*string)
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+
+	v.TxID = txID
+
+	return v, v.ID, nil
+}
+
+func (d *DB) fetchTeamMembers(tx *sql.Tx, q sq.Builder) ([]*types.TeamMember, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanTeamMembers(rows, tx.ID(), 0)
+}
+
+func (d *DB) fetchTeamMembersSkipLastFields(tx *sql.Tx, q sq.Builder, skipFieldsCount uint) ([]*types.TeamMember, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanTeamMembers(rows, tx.ID(), skipFieldsCount)
+}
+
+func (d *DB) scanTeamMember(rows *stdsql.Rows, skipFieldsCount uint) (*types.TeamMember, string, error) {
+
+	v := &types.TeamMember{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+
+	fields := []any{&v.ID, &v.Revision, &v.CreationTime, &v.UpdateTime, &v.TeamID, &v.UserID}
+
+	for i := uint(0); i < skipFieldsCount; i++ {
+		fields = append(fields, new(any))
+	}
+
+	if err := rows.Scan(fields...); err != nil {
+		return nil, "", errors.Wrap(err, "failed to scan row")
+	}
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+
+	return v, v.ID, nil
+}
+
+func (d *DB) scanTeamMembers(rows *stdsql.Rows, txID string, skipFieldsCount uint) ([]*types.TeamMember, []string, error) {
+	vs := []*types.TeamMember{}
+	ids := []string{}
+	for rows.Next() {
+		v, id, err := d.scanTeamMember(rows, skipFieldsCount)
+		if err != nil {
+			rows.Close()
+			return nil, nil, errors.WithStack(err)
+		}
+		v.TxID = txID
+		vs = append(vs, v)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return vs, ids, nil
+}
+
+func (d *DB) TeamMemberArray() []any {
+	a := []any{}
+	a = append(a, new(string))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(time.Time))
+	a = append(a, new(string))
+	a = append(a, new(string))
+
+	return a
+}
+
+func (d *DB) TeamMemberFromArray(a []any, txID string) (*types.TeamMember, string, error) {
+	v := &types.TeamMember{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+	v.ID = *a[0].(*string)
+	v.Revision = *a[1].(*uint64)
+	v.CreationTime = *a[2].(*time.Time)
+	v.UpdateTime = *a[3].(*time.Time)
+	v.TeamID = *a[4].(*string)
+	v.UserID = *a[5].(*string)
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+
+	v.TxID = txID
+
+	return v, v.ID, nil
+}
+
+func (d *DB) fetchAuditEvents(tx *sql.Tx, q sq.Builder) ([]*types.AuditEvent, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanAuditEvents(rows, tx.ID(), 0)
+}
+
+func (d *DB) fetchAuditEventsSkipLastFields(tx *sql.Tx, q sq.Builder, skipFieldsCount uint) ([]*types.AuditEvent, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanAuditEvents(rows, tx.ID(), skipFieldsCount)
+}
+
+func (d *DB) scanAuditEvent(rows *stdsql.Rows, skipFieldsCount uint) (*types.AuditEvent, string, error) {
+	var inDetailsJSON []byte
+	var inRequestMetadataJSON []byte
+
+	v := &types.AuditEvent{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+
+	fields := []any{&v.ID, &v.Revision, &v.CreationTime, &v.UpdateTime, &v.Sequence, &v.ActorUserID, &v.ActorUserName, &v.Action, &v.TargetKind, &v.TargetID, &v.TargetName, &v.OrganizationID, &inDetailsJSON, &inRequestMetadataJSON}
+
+	for i := uint(0); i < skipFieldsCount; i++ {
+		fields = append(fields, new(any))
+	}
+
+	if err := rows.Scan(fields...); err != nil {
+		return nil, "", errors.Wrap(err, "failed to scan row")
+	}
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+	if err := json.Unmarshal(inDetailsJSON, &v.Details); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.Details")
+	}
+	if err := json.Unmarshal(inRequestMetadataJSON, &v.RequestMetadata); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.RequestMetadata")
+	}
+
+	return v, v.ID, nil
+}
+
+func (d *DB) scanAuditEvents(rows *stdsql.Rows, txID string, skipFieldsCount uint) ([]*types.AuditEvent, []string, error) {
+	vs := []*types.AuditEvent{}
+	ids := []string{}
+	for rows.Next() {
+		v, id, err := d.scanAuditEvent(rows, skipFieldsCount)
+		if err != nil {
+			rows.Close()
+			return nil, nil, errors.WithStack(err)
+		}
+		v.TxID = txID
+		vs = append(vs, v)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return vs, ids, nil
+}
+
+func (d *DB) AuditEventArray() []any {
+	a := []any{}
+	a = append(a, new(string))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(time.Time))
+	a = append(a, new(uint64))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new(types.AuditAction))
+	a = append(a, new(types.ObjectKind))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new([]byte))
+	a = append(a, new([]byte))
+
+	return a
+}
+
+func (d *DB) AuditEventFromArray(a []any, txID string) (*types.AuditEvent, string, error) {
+	v := &types.AuditEvent{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+	v.ID = *a[0].(*string)
+	v.Revision = *a[1].(*uint64)
+	v.CreationTime = *a[2].(*time.Time)
+	v.UpdateTime = *a[3].(*time.Time)
+	v.Sequence = *a[4].(*uint64)
+	v.ActorUserID = *a[5].(*string)
+	v.ActorUserName = *a[6].(*string)
+	v.Action = *a[7].(*types.AuditAction)
+	v.TargetKind = *a[8].(*types.ObjectKind)
+	v.TargetID = *a[9].(*string)
+	v.TargetName = *a[10].(*string)
+	v.OrganizationID = *a[11].(*string)
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+	if err := json.Unmarshal(a[12].([]byte), &v.Details); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.Details")
+	}
+	if err := json.Unmarshal(a[13].([]byte), &v.RequestMetadata); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.RequestMetadata")
+	}
+
+	v.TxID = txID
+
+	return v, v.ID, nil
+}
+
+func (d *DB) fetchReceivedWebhooks(tx *sql.Tx, q sq.Builder) ([]*types.ReceivedWebhook, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanReceivedWebhooks(rows, tx.ID(), 0)
+}
+
+func (d *DB) fetchReceivedWebhooksSkipLastFields(tx *sql.Tx, q sq.Builder, skipFieldsCount uint) ([]*types.ReceivedWebhook, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanReceivedWebhooks(rows, tx.ID(), skipFieldsCount)
+}
+
+func (d *DB) scanReceivedWebhook(rows *stdsql.Rows, skipFieldsCount uint) (*types.ReceivedWebhook, string, error) {
+	var inHeaderJSON []byte
+	var inRunNumbersJSON []byte
+
+	v := &types.ReceivedWebhook{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+
+	fields := []any{&v.ID, &v.Revision, &v.CreationTime, &v.UpdateTime, &v.Sequence, &v.ProjectID, &v.DeliveryID, &v.Event, &inHeaderJSON, &v.Body, &v.Status, &v.Attempts, &v.NextAttemptTime, &v.Outcome, &v.OutcomeMessage, &inRunNumbersJSON}
+
+	for i := uint(0); i < skipFieldsCount; i++ {
+		fields = append(fields, new(any))
+	}
+
+	if err := rows.Scan(fields...); err != nil {
+		return nil, "", errors.Wrap(err, "failed to scan row")
+	}
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+	if err := json.Unmarshal(inHeaderJSON, &v.Header); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.Header")
+	}
+	if err := json.Unmarshal(inRunNumbersJSON, &v.RunNumbers); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.RunNumbers")
+	}
+
+	return v, v.ID, nil
+}
+
+func (d *DB) scanReceivedWebhooks(rows *stdsql.Rows, txID string, skipFieldsCount uint) ([]*types.ReceivedWebhook, []string, error) {
+	vs := []*types.ReceivedWebhook{}
+	ids := []string{}
+	for rows.Next() {
+		v, id, err := d.scanReceivedWebhook(rows, skipFieldsCount)
+		if err != nil {
+			rows.Close()
+			return nil, nil, errors.WithStack(err)
+		}
+		v.TxID = txID
+		vs = append(vs, v)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return vs, ids, nil
+}
+
+func (d *DB) ReceivedWebhookArray() []any {
+	a := []any{}
+	a = append(a, new(string))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(time.Time))
+	a = append(a, new(uint64))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new([]byte))
+	a = append(a, new([]byte))
+	a = append(a, new(types.ReceivedWebhookStatus))
+	a = append(a, new(int))
+	a = append(a, new(time.Time))
+	a = append(a, new(types.ReceivedWebhookOutcome))
+	a = append(a, new(string))
+	a = append(a, new([]byte))
+
+	return a
+}
+
+func (d *DB) ReceivedWebhookFromArray(a []any, txID string) (*types.ReceivedWebhook, string, error) {
+	v := &types.ReceivedWebhook{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+	v.ID = *a[0].(*string)
+	v.Revision = *a[1].(*uint64)
+	v.CreationTime = *a[2].(*time.Time)
+	v.UpdateTime = *a[3].(*time.Time)
+	v.Sequence = *a[4].(*uint64)
+	v.ProjectID = *a[5].(*string)
+	v.DeliveryID = *a[6].(*string)
+	v.Event = *a[7].(*string)
+	v.Body = *a[9].(*[]byte)
+	v.Status = *a[10].(*types.ReceivedWebhookStatus)
+	v.Attempts = *a[11].(*int)
+	v.NextAttemptTime = *a[12].(*time.Time)
+	v.Outcome = *a[13].(*types.ReceivedWebhookOutcome)
+	v.OutcomeMessage = *a[14].(*string)
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+	if err := json.Unmarshal(a[8].([]byte), &v.Header); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.Header")
+	}
+	if err := json.Unmarshal(a[15].([]byte), &v.RunNumbers); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.RunNumbers")
+	}
+
+	v.TxID = txID
+
+	return v, v.ID, nil
+}
+
+func (d *DB) fetchProjectMirrors(tx *sql.Tx, q sq.Builder) ([]*types.ProjectMirror, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanProjectMirrors(rows, tx.ID(), 0)
+}
+
+func (d *DB) fetchProjectMirrorsSkipLastFields(tx *sql.Tx, q sq.Builder, skipFieldsCount uint) ([]*types.ProjectMirror, []string, error) {
+	rows, err := d.query(tx, q)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	defer rows.Close()
+
+	return d.scanProjectMirrors(rows, tx.ID(), skipFieldsCount)
+}
+
+func (d *DB) scanProjectMirror(rows *stdsql.Rows, skipFieldsCount uint) (*types.ProjectMirror, string, error) {
+	var inBranchesJSON []byte
+	var inTagsJSON []byte
+	var inRefsJSON []byte
+
+	v := &types.ProjectMirror{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+
+	fields := []any{&v.ID, &v.Revision, &v.CreationTime, &v.UpdateTime, &v.ProjectID, &v.URL, &v.SSHHostKey, &inBranchesJSON, &inTagsJSON, &inRefsJSON, &v.PollCount, &v.NextPollTime, &v.LastPollTime, &v.LastPollError}
+
+	for i := uint(0); i < skipFieldsCount; i++ {
+		fields = append(fields, new(any))
+	}
+
+	if err := rows.Scan(fields...); err != nil {
+		return nil, "", errors.Wrap(err, "failed to scan row")
+	}
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+	if err := json.Unmarshal(inBranchesJSON, &v.Branches); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.Branches")
+	}
+	if err := json.Unmarshal(inTagsJSON, &v.Tags); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.Tags")
+	}
+	if err := json.Unmarshal(inRefsJSON, &v.Refs); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.Refs")
+	}
+
+	return v, v.ID, nil
+}
+
+func (d *DB) scanProjectMirrors(rows *stdsql.Rows, txID string, skipFieldsCount uint) ([]*types.ProjectMirror, []string, error) {
+	vs := []*types.ProjectMirror{}
+	ids := []string{}
+	for rows.Next() {
+		v, id, err := d.scanProjectMirror(rows, skipFieldsCount)
+		if err != nil {
+			rows.Close()
+			return nil, nil, errors.WithStack(err)
+		}
+		v.TxID = txID
+		vs = append(vs, v)
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	return vs, ids, nil
+}
+
+func (d *DB) ProjectMirrorArray() []any {
+	a := []any{}
+	a = append(a, new(string))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(time.Time))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new(string))
+	a = append(a, new([]byte))
+	a = append(a, new([]byte))
+	a = append(a, new([]byte))
+	a = append(a, new(uint64))
+	a = append(a, new(time.Time))
+	a = append(a, new(*time.Time))
+	a = append(a, new(string))
+
+	return a
+}
+
+func (d *DB) ProjectMirrorFromArray(a []any, txID string) (*types.ProjectMirror, string, error) {
+	v := &types.ProjectMirror{}
+
+	var vi any = v
+	if x, ok := vi.(sqlg.Initer); ok {
+		x.Init()
+	}
+	v.ID = *a[0].(*string)
+	v.Revision = *a[1].(*uint64)
+	v.CreationTime = *a[2].(*time.Time)
+	v.UpdateTime = *a[3].(*time.Time)
+	v.ProjectID = *a[4].(*string)
+	v.URL = *a[5].(*string)
+	v.SSHHostKey = *a[6].(*string)
+	v.PollCount = *a[10].(*uint64)
+	v.NextPollTime = *a[11].(*time.Time)
+	v.LastPollTime = *a[12].(**time.Time)
+	v.LastPollError = *a[13].(*string)
+
+	if x, ok := vi.(sqlg.PreJSONSetupper); ok {
+		if err := x.PreJSON(); err != nil {
+			return nil, "", errors.Wrap(err, "prejson error")
+		}
+	}
+	if err := json.Unmarshal(a[7].([]byte), &v.Branches); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.Branches")
+	}
+	if err := json.Unmarshal(a[8].([]byte), &v.Tags); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.Tags")
+	}
+	if err := json.Unmarshal(a[9].([]byte), &v.Refs); err != nil {
+		return nil, "", errors.Wrap(err, "failed to unmarshal v.v.Refs")
+	}
+
+	v.TxID = txID
+
+	return v, v.ID, nil
+}
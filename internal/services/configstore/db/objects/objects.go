@@ -5,7 +5,7 @@ import (
 )
 
 const (
-	Version = uint(3)
+	Version = uint(8)
 )
 
 const TypesImport = "agola.io/agola/services/configstore/types"
@@ -138,4 +138,92 @@ var ObjectsInfo = []sqlg.ObjectInfo{
 			"foreign key (organization_id) references organization(id)",
 		},
 	},
+	{Name: "RoleBinding", Table: "rolebinding",
+		Fields: []sqlg.ObjectField{
+			{Name: "Parent.Kind", ColName: "parent_kind", Type: "types.ObjectKind", BaseType: "string"},
+			{Name: "Parent.ID", ColName: "parent_id", Type: "string"},
+			{Name: "Subject.Kind", ColName: "subject_kind", Type: "types.ObjectKind", BaseType: "string"},
+			{Name: "Subject.ID", ColName: "subject_id", Type: "string"},
+			{Name: "Role", Type: "types.MemberRole", BaseType: "string"},
+		},
+	},
+	{Name: "Team", Table: "team",
+		Fields: []sqlg.ObjectField{
+			{Name: "OrganizationID", Type: "string"},
+			{Name: "Name", Type: "string"},
+		},
+		Constraints: []string{
+			"foreign key (organization_id) references organization(id)",
+		},
+	},
+	{Name: "TeamMember", Table: "teammember",
+		Fields: []sqlg.ObjectField{
+			{Name: "TeamID", Type: "string"},
+			{Name: "UserID", Type: "string"},
+		},
+		Constraints: []string{
+			"foreign key (team_id) references team(id)",
+			"foreign key (user_id) references user_t(id)",
+		},
+	},
+	{Name: "AuditEvent", Table: "auditevent",
+		Fields: []sqlg.ObjectField{
+			{Name: "Sequence", Type: "uint64", Sequence: true},
+			{Name: "ActorUserID", Type: "string"},
+			{Name: "ActorUserName", Type: "string"},
+			{Name: "Action", Type: "types.AuditAction", BaseType: "string"},
+			{Name: "TargetKind", Type: "types.ObjectKind", BaseType: "string"},
+			{Name: "TargetID", Type: "string"},
+			{Name: "TargetName", Type: "string"},
+			{Name: "OrganizationID", Type: "string"},
+			{Name: "Details", Type: "map[string]string", JSON: true},
+			{Name: "RequestMetadata", Type: "types.AuditEventRequestMetadata", JSON: true},
+		},
+		Indexes: []string{
+			"create index if not exists auditevent_organization_id_idx on auditevent(organization_id)",
+		},
+	},
+	{Name: "ReceivedWebhook", Table: "receivedwebhook",
+		Fields: []sqlg.ObjectField{
+			{Name: "Sequence", Type: "uint64", Sequence: true},
+			{Name: "ProjectID", Type: "string"},
+			{Name: "DeliveryID", Type: "string"},
+			{Name: "Event", Type: "string"},
+			{Name: "Header", Type: "map[string][]string", JSON: true},
+			{Name: "Body", Type: "[]byte"},
+			{Name: "Status", Type: "types.ReceivedWebhookStatus", BaseType: "string"},
+			{Name: "Attempts", Type: "int"},
+			{Name: "NextAttemptTime", Type: "time.Time"},
+			{Name: "Outcome", Type: "types.ReceivedWebhookOutcome", BaseType: "string"},
+			{Name: "OutcomeMessage", Type: "string"},
+			{Name: "RunNumbers", Type: "[]uint64", JSON: true},
+		},
+		Constraints: []string{
+			"unique (project_id, delivery_id)",
+		},
+		Indexes: []string{
+			"create index if not exists receivedwebhook_project_id_idx on receivedwebhook(project_id)",
+			"create index if not exists receivedwebhook_status_idx on receivedwebhook(status, next_attempt_time)",
+		},
+	},
+	{Name: "ProjectMirror", Table: "projectmirror",
+		Fields: []sqlg.ObjectField{
+			{Name: "ProjectID", Type: "string"},
+			{Name: "URL", Type: "string"},
+			{Name: "SSHHostKey", Type: "string"},
+			{Name: "Branches", Type: "[]string", JSON: true},
+			{Name: "Tags", Type: "[]string", JSON: true},
+			{Name: "Refs", Type: "map[string]string", JSON: true},
+			{Name: "PollCount", Type: "uint64"},
+			{Name: "NextPollTime", Type: "time.Time"},
+			{Name: "LastPollTime", Type: "time.Time", Nullable: true},
+			{Name: "LastPollError", Type: "string"},
+		},
+		Constraints: []string{
+			"unique (project_id)",
+		},
+		Indexes: []string{
+			"create index if not exists projectmirror_next_poll_time_idx on projectmirror(next_poll_time)",
+		},
+	},
 }
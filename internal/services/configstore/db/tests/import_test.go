@@ -9,7 +9,10 @@ import (
 func TestImportExport(t *testing.T) {
 	log := testutil.NewLogger(t)
 
-	seqs := map[string]uint64{}
+	seqs := map[string]uint64{
+		"auditevent_sequence_seq":      3,
+		"receivedwebhook_sequence_seq": 1,
+	}
 
 	testutil.TestImportExport(t, "import.jsonc", newSetupDBFn(log), seqs)
 }
@@ -36,6 +36,11 @@ var importFixtures = testutil.DataFixtures{
 	1: "dbv1.jsonc",
 	2: "dbv2.jsonc",
 	3: "dbv3.jsonc",
+	4: "dbv4.jsonc",
+	5: "dbv5.jsonc",
+	6: "dbv6.jsonc",
+	7: "dbv7.jsonc",
+	8: "dbv8.jsonc",
 }
 
 func TestCreate(t *testing.T) {
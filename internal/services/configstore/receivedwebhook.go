@@ -0,0 +1,65 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/sqlg/lock"
+)
+
+const (
+	receivedWebhooksCleanerLockKey = "receivedwebhookscleaner"
+
+	receivedWebhooksCleanerInterval = 1 * time.Hour
+)
+
+func (s *Configstore) receivedWebhooksCleanerLoop(ctx context.Context, receivedWebhookExpireInterval time.Duration) {
+	s.log.Debug().Msg("receivedWebhooksCleanerLoop")
+
+	for {
+		if err := s.receivedWebhooksCleaner(ctx, receivedWebhookExpireInterval); err != nil {
+			s.log.Warn().Err(err).Msg("receivedWebhooksCleaner error")
+		}
+
+		sleepCh := time.NewTimer(receivedWebhooksCleanerInterval).C
+		select {
+		case <-ctx.Done():
+			return
+		case <-sleepCh:
+		}
+	}
+}
+
+func (s *Configstore) receivedWebhooksCleaner(ctx context.Context, receivedWebhookExpireInterval time.Duration) error {
+	// keep received webhooks forever when no expire interval is defined
+	if receivedWebhookExpireInterval <= 0 {
+		return nil
+	}
+
+	l := s.lf.NewLock(receivedWebhooksCleanerLockKey)
+	if err := l.TryLock(ctx); err != nil {
+		if errors.Is(err, lock.ErrLocked) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	defer func() { _ = l.Unlock() }()
+
+	return errors.WithStack(s.ah.DeleteExpiredReceivedWebhooks(ctx, receivedWebhookExpireInterval))
+}
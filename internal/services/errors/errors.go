@@ -189,6 +189,38 @@ func OrgMemberDoesNotExist() util.APIErrorOption {
 	return detailedErrorOption(apierrors.ErrorCodeOrgMemberDoesNotExist)
 }
 
+func RoleBindingDoesNotExist() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeRoleBindingDoesNotExist)
+}
+
+func TeamDoesNotExist() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeTeamDoesNotExist)
+}
+
+func TeamAlreadyExists() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeTeamAlreadyExists)
+}
+
+func InvalidTeamName() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidTeamName)
+}
+
+func TeamMemberDoesNotExist() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeTeamMemberDoesNotExist)
+}
+
+func UserNotOrgMember() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeUserNotOrgMember)
+}
+
+func InvalidAuditAction() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidAuditAction)
+}
+
+func InvalidTimeRange() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidTimeRange)
+}
+
 func InvitationDoesNotExist() util.APIErrorOption {
 	return detailedErrorOption(apierrors.ErrorCodeInvitationDoesNotExist)
 }
@@ -241,6 +273,14 @@ func RunTaskAlreadyApproved() util.APIErrorOption {
 	return detailedErrorOption(apierrors.ErrorCodeRunTaskAlreadyApproved)
 }
 
+func RunTaskApproverNotAllowed() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeRunTaskApproverNotAllowed)
+}
+
+func RunTaskSelfApproval() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeRunTaskSelfApproval)
+}
+
 func InvalidDeliveryStatus() util.APIErrorOption {
 	return detailedErrorOption(apierrors.ErrorCodeInvalidDeliveryStatus)
 }
@@ -268,3 +308,55 @@ func RunWebhookDeliveryDoesNotExist() util.APIErrorOption {
 func RunWebhookDeliveryAlreadyInProgress() util.APIErrorOption {
 	return detailedErrorOption(apierrors.ErrorCodeRunWebhookDeliveryAlreadyInProgress)
 }
+
+func ReceivedWebhookDoesNotExist() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeReceivedWebhookDoesNotExist)
+}
+
+func ReceivedWebhookNotProcessing() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeReceivedWebhookNotProcessing)
+}
+
+func InvalidReceivedWebhookStatus() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidReceivedWebhookStatus)
+}
+
+func InvalidReceivedWebhookDelivery() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidReceivedWebhookDelivery)
+}
+
+func ProjectMirrorDoesNotExist() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeProjectMirrorDoesNotExist)
+}
+
+func ProjectMirrorNotClaimed() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeProjectMirrorNotClaimed)
+}
+
+func InvalidProjectMirror() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidProjectMirror)
+}
+
+func InvalidExportScope() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidExportScope)
+}
+
+func InvalidExportData() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidExportData)
+}
+
+func RunAlreadyExists() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeRunAlreadyExists)
+}
+
+func BackupDoesNotExist() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeBackupDoesNotExist)
+}
+
+func InvalidBackupID() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeInvalidBackupID)
+}
+
+func DatabaseNotEmpty() util.APIErrorOption {
+	return detailedErrorOption(apierrors.ErrorCodeDatabaseNotEmpty)
+}
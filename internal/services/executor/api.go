@@ -16,6 +16,7 @@ package executor
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/sorintlab/errors"
 
+	"agola.io/agola/internal/tasklog"
 	"agola.io/agola/internal/util"
 	rsapitypes "agola.io/agola/services/runservice/api/types"
 )
@@ -110,24 +112,29 @@ func (h *logsHandler) do(w http.ResponseWriter, r *http.Request) error {
 		follow = true
 	}
 
-	if err := h.readTaskLogs(taskID, setup, step, w, follow); err != nil {
+	opts, err := tasklog.ParseCopyOptions(q)
+	if err != nil {
+		return util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	if err := h.readTaskLogs(r.Context(), taskID, setup, step, w, follow, opts); err != nil {
 		return errors.WithStack(err)
 	}
 
 	return nil
 }
 
-func (h *logsHandler) readTaskLogs(taskID string, setup bool, step int, w http.ResponseWriter, follow bool) error {
+func (h *logsHandler) readTaskLogs(ctx context.Context, taskID string, setup bool, step int, w http.ResponseWriter, follow bool, opts *tasklog.CopyOptions) error {
 	var logPath string
 	if setup {
 		logPath = h.e.setupLogPath(taskID)
 	} else {
 		logPath = h.e.stepLogPath(taskID, step)
 	}
-	return h.readLogs(taskID, setup, step, logPath, w, follow)
+	return h.readLogs(ctx, taskID, step, logPath, w, follow, opts)
 }
 
-func (h *logsHandler) readLogs(taskID string, setup bool, step int, logPath string, w http.ResponseWriter, follow bool) error {
+func (h *logsHandler) readLogs(ctx context.Context, taskID string, step int, logPath string, w http.ResponseWriter, follow bool, opts *tasklog.CopyOptions) error {
 	f, err := os.Open(logPath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -141,11 +148,11 @@ func (h *logsHandler) readLogs(taskID string, setup bool, step int, logPath stri
 
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Content-Type", opts.Format.ContentType())
 
-	buf := make([]byte, 4096)
-
-	// if not following return the Content-Length
-	if !follow {
+	// if not following and requesting the whole structured log return the
+	// Content-Length
+	if !follow && opts.Format == tasklog.FormatNDJSON && opts.Start == 0 && opts.Limit == 0 {
 		fi, err := f.Stat()
 		if err != nil {
 			return errors.WithStack(err)
@@ -156,52 +163,60 @@ func (h *logsHandler) readLogs(taskID string, setup bool, step int, logPath stri
 	// write and flush the headers so the client will receive the response
 	// header also if there're currently no lines to send
 	w.WriteHeader(http.StatusOK)
-	var flusher http.Flusher
 	if fl, ok := w.(http.Flusher); ok {
-		flusher = fl
-	}
-	if flusher != nil {
-		flusher.Flush()
+		fl.Flush()
 	}
 
-	stop := false
-	flushstop := false
-	for {
-		if stop {
-			return nil
-		}
-		n, err := f.Read(buf)
-		if err != nil {
-			if err != io.EOF {
-				return errors.WithStack(err)
-			}
-			if !flushstop && follow {
-				if _, err := f.Seek(-int64(n), io.SeekCurrent); err != nil {
-					return errors.Wrapf(err, "failed to seek in log file %q", logPath)
-				}
-				// check if the step is finished, if so flush until EOF and stop
+	var r io.Reader = f
+	if follow {
+		r = &followReader{
+			ctx: ctx,
+			f:   f,
+			finished: func() bool {
+				// check if the step is finished, if so read until EOF and stop
 				rt, ok := h.e.runningTasks.get(taskID)
 				if !ok {
-					flushstop = true
-				} else {
-					rt.Lock()
-					if rt.et.Status.Steps[step].Phase.IsFinished() {
-						flushstop = true
-					}
-					rt.Unlock()
+					return true
 				}
-				// TODO(sgotti) use ionotify/fswatcher?
-				time.Sleep(500 * time.Millisecond)
-				continue
-			} else {
-				stop = true
-			}
+				rt.Lock()
+				defer rt.Unlock()
+				return rt.et.Status.Steps[step].Phase.IsFinished()
+			},
 		}
-		if _, err := w.Write(buf[:n]); err != nil {
-			return errors.WithStack(err)
+	}
+
+	return errors.WithStack(tasklog.Copy(w, r, opts))
+}
+
+// followReader reads a log file that is being written. When reaching the end
+// of the file it waits for new data until the log is finished.
+type followReader struct {
+	ctx      context.Context
+	f        *os.File
+	finished func() bool
+
+	stop bool
+}
+
+func (r *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.f.Read(p)
+		if n > 0 || !errors.Is(err, io.EOF) || r.stop {
+			// return io.EOF unwrapped as required by the io.Reader interface
+			//nolint:wrapcheck
+			return n, err
 		}
-		if flusher != nil {
-			flusher.Flush()
+
+		// read until EOF one more time after the log is finished
+		if r.stop = r.finished(); r.stop {
+			continue
+		}
+
+		// TODO(sgotti) use ionotify/fswatcher?
+		select {
+		case <-r.ctx.Done():
+			return 0, errors.WithStack(r.ctx.Err())
+		case <-time.After(500 * time.Millisecond):
 		}
 	}
 }
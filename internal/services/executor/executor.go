@@ -35,12 +35,17 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sorintlab/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"agola.io/agola/internal/common"
+	"agola.io/agola/internal/metrics"
 	"agola.io/agola/internal/services/config"
 	"agola.io/agola/internal/services/executor/driver"
 	"agola.io/agola/internal/services/executor/registry"
 	"agola.io/agola/internal/services/handlers"
+	"agola.io/agola/internal/tasklog"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
 	rsapitypes "agola.io/agola/services/runservice/api/types"
 	rsclient "agola.io/agola/services/runservice/client"
@@ -113,14 +118,24 @@ func (e *Executor) createFile(ctx context.Context, pod driver.Pod, command, user
 }
 
 func (e *Executor) doRunStep(ctx context.Context, s *types.RunStep, et *rsapitypes.ExecutorTask, pod driver.Pod, logPath string) (int, error) {
-	if err := os.MkdirAll(filepath.Dir(logPath), 0770); err != nil {
-		return -1, errors.WithStack(err)
-	}
-	outf, err := os.Create(logPath)
+	lf, err := createLogFile(logPath)
 	if err != nil {
 		return -1, errors.WithStack(err)
 	}
-	defer outf.Close()
+	defer lf.Close()
+
+	// mask the secret values in the step log. When not using a tty, stdout and
+	// stderr are recorded as different streams.
+	var outf, errf *maskWriter
+	if *s.Tty {
+		outf = newMaskWriter(lf.Stream(tasklog.StreamCombined), et.Spec.SecretValues)
+		errf = outf
+	} else {
+		outf = newMaskWriter(lf.Stream(tasklog.StreamStdout), et.Spec.SecretValues)
+		errf = newMaskWriter(lf.Stream(tasklog.StreamStderr), et.Spec.SecretValues)
+		defer func() { _ = errf.Flush() }()
+	}
+	defer func() { _ = outf.Flush() }()
 
 	// TODO(sgotti) this line is used only for old runconfig versions that don't
 	// set a task default shell in the runconfig
@@ -134,7 +149,7 @@ func (e *Executor) doRunStep(ctx context.Context, s *types.RunStep, et *rsapityp
 
 	var cmd []string
 	if s.Command != "" {
-		filename, err := e.createFile(ctx, pod, s.Command, stepUser(et), outf)
+		filename, err := e.createFile(ctx, pod, s.Command, stepUser(et), errf)
 		if err != nil {
 			return -1, errors.Wrapf(err, "create file err")
 		}
@@ -160,9 +175,9 @@ func (e *Executor) doRunStep(ctx context.Context, s *types.RunStep, et *rsapityp
 		environment[envName] = envValue
 	}
 
-	workingDir, err = e.expandDir(ctx, et, pod, outf, workingDir)
+	workingDir, err = e.expandDir(ctx, et, pod, errf, workingDir)
 	if err != nil {
-		_, _ = fmt.Fprintf(outf, "failed to expand working dir %q. Error: %s\n", workingDir, err)
+		_, _ = fmt.Fprintf(errf, "failed to expand working dir %q. Error: %s\n", workingDir, err)
 		return -1, errors.WithStack(err)
 	}
 
@@ -173,7 +188,7 @@ func (e *Executor) doRunStep(ctx context.Context, s *types.RunStep, et *rsapityp
 		User:        stepUser(et),
 		AttachStdin: true,
 		Stdout:      outf,
-		Stderr:      outf,
+		Stderr:      errf,
 		Tty:         *s.Tty,
 	}
 
@@ -193,14 +208,12 @@ func (e *Executor) doRunStep(ctx context.Context, s *types.RunStep, et *rsapityp
 func (e *Executor) doSaveToWorkspaceStep(ctx context.Context, s *types.SaveToWorkspaceStep, et *rsapitypes.ExecutorTask, pod driver.Pod, logPath string, archivePath string) (int, error) {
 	cmd := []string{toolboxContainerPath, "archive"}
 
-	if err := os.MkdirAll(filepath.Dir(logPath), 0770); err != nil {
-		return -1, errors.WithStack(err)
-	}
-	logf, err := os.Create(logPath)
+	lf, err := createLogFile(logPath)
 	if err != nil {
 		return -1, errors.WithStack(err)
 	}
-	defer logf.Close()
+	defer lf.Close()
+	logf := lf.Stream(tasklog.StreamCombined)
 
 	if err := os.MkdirAll(filepath.Dir(archivePath), 0770); err != nil {
 		return -1, errors.WithStack(err)
@@ -425,14 +438,12 @@ func (e *Executor) unarchive(ctx context.Context, et *rsapitypes.ExecutorTask, s
 }
 
 func (e *Executor) doRestoreWorkspaceStep(ctx context.Context, s *types.RestoreWorkspaceStep, et *rsapitypes.ExecutorTask, pod driver.Pod, logPath string) (int, error) {
-	if err := os.MkdirAll(filepath.Dir(logPath), 0770); err != nil {
-		return -1, errors.WithStack(err)
-	}
-	logf, err := os.Create(logPath)
+	lf, err := createLogFile(logPath)
 	if err != nil {
 		return -1, errors.WithStack(err)
 	}
-	defer logf.Close()
+	defer lf.Close()
+	logf := lf.Stream(tasklog.StreamCombined)
 
 	for _, op := range et.Spec.WorkspaceOperations {
 		e.log.Debug().Msgf("unarchiving workspace for taskID: %s, step: %d", op.TaskID, op.Step)
@@ -454,14 +465,12 @@ func (e *Executor) doRestoreWorkspaceStep(ctx context.Context, s *types.RestoreW
 }
 
 func (e *Executor) doSaveCacheStep(ctx context.Context, s *types.SaveCacheStep, et *rsapitypes.ExecutorTask, pod driver.Pod, logPath string, archivePath string) (int, error) {
-	if err := os.MkdirAll(filepath.Dir(logPath), 0770); err != nil {
-		return -1, errors.WithStack(err)
-	}
-	logf, err := os.Create(logPath)
+	lf, err := createLogFile(logPath)
 	if err != nil {
 		return -1, errors.WithStack(err)
 	}
-	defer logf.Close()
+	defer lf.Close()
+	logf := lf.Stream(tasklog.StreamCombined)
 
 	save := false
 
@@ -605,14 +614,12 @@ func (e *Executor) SendCache(ctx context.Context, fullCacheKey, cacheArchivePath
 }
 
 func (e *Executor) doRestoreCacheStep(ctx context.Context, s *types.RestoreCacheStep, et *rsapitypes.ExecutorTask, pod driver.Pod, logPath string) (int, error) {
-	if err := os.MkdirAll(filepath.Dir(logPath), 0770); err != nil {
-		return -1, errors.WithStack(err)
-	}
-	logf, err := os.Create(logPath)
+	lf, err := createLogFile(logPath)
 	if err != nil {
 		return -1, errors.WithStack(err)
 	}
-	defer logf.Close()
+	defer lf.Close()
+	logf := lf.Stream(tasklog.StreamCombined)
 
 	fmt.Fprintf(logf, "restoring cache: %s\n", util.Dump(s))
 	for _, cacheKeyTemplate := range s.Keys {
@@ -676,6 +683,50 @@ func (e *Executor) stepLogPath(taskID string, stepID int) string {
 	return filepath.Join(e.taskLogsPath(taskID), "steps", fmt.Sprintf("%d.log", stepID))
 }
 
+// logFile is a task log file written using the structured log format.
+type logFile struct {
+	f       *os.File
+	w       *tasklog.Writer
+	streams map[tasklog.Stream]*tasklog.StreamWriter
+}
+
+func createLogFile(logPath string) (*logFile, error) {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0770); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	f, err := os.Create(logPath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &logFile{
+		f:       f,
+		w:       tasklog.NewWriter(f),
+		streams: map[tasklog.Stream]*tasklog.StreamWriter{},
+	}, nil
+}
+
+// Stream returns the writer for the provided stream.
+func (l *logFile) Stream(stream tasklog.Stream) *tasklog.StreamWriter {
+	sw, ok := l.streams[stream]
+	if !ok {
+		sw = l.w.Stream(stream)
+		l.streams[stream] = sw
+	}
+	return sw
+}
+
+// Close flushes the streams unterminated lines and closes the log file.
+func (l *logFile) Close() error {
+	for _, sw := range l.streams {
+		if err := sw.Flush(); err != nil {
+			l.f.Close()
+			return errors.WithStack(err)
+		}
+	}
+	return errors.WithStack(l.f.Close())
+}
+
 func (e *Executor) archivePath(taskID string, stepID int) string {
 	return filepath.Join(e.taskPath(taskID), "archives", fmt.Sprintf("%d.tar", stepID))
 }
@@ -688,6 +739,9 @@ func (e *Executor) sendExecutorStatus(ctx context.Context) error {
 
 	activeTasks := e.runningTasks.len()
 
+	activeTasksGauge.WithLabelValues(e.id).Set(float64(activeTasks))
+	activeTasksLimitGauge.WithLabelValues(e.id).Set(float64(e.c.ActiveTasksLimit))
+
 	archs, err := e.driver.Archs(ctx)
 	if err != nil {
 		return errors.WithStack(err)
@@ -752,7 +806,17 @@ func (e *Executor) executeTask(rt *runningTask) {
 	// have an in progress running task
 
 	rt.Lock()
-	ctx := rt.ctx
+	// the task span is a child of the run span
+	ctx, span := tracing.Tracer().Start(tracing.ContextWithTraceParent(rt.ctx, rt.et.Spec.TraceParent), "task "+rt.et.Spec.TaskName, trace.WithAttributes(
+		attribute.String("agola.executor.id", e.id),
+		attribute.String("agola.task.id", rt.et.ID),
+	))
+	defer func() {
+		rt.Lock()
+		phase := rt.et.Status.Phase
+		rt.Unlock()
+		tracing.EndSpan(span, string(phase), phase != types.ExecutorTaskPhaseSuccess)
+	}()
 
 	// wait for context to be done and then stop the pod if running
 	go func() {
@@ -781,7 +845,10 @@ func (e *Executor) executeTask(rt *runningTask) {
 	}
 
 	rt.Unlock()
-	if err := e.setupTask(ctx, rt); err != nil {
+	setupCtx, setupSpan := tracing.Tracer().Start(ctx, "setup")
+	err := e.setupTask(setupCtx, rt)
+	tracing.EndSpan(setupSpan, "", err != nil)
+	if err != nil {
 		rt.Lock()
 		e.log.Err(err).Send()
 		et.Status.Phase = types.ExecutorTaskPhaseFailed
@@ -805,7 +872,7 @@ func (e *Executor) executeTask(rt *runningTask) {
 
 	rt.Unlock()
 
-	_, err := e.executeTaskSteps(ctx, rt, rt.pod)
+	_, err = e.executeTaskSteps(ctx, rt, rt.pod)
 
 	rt.Lock()
 	if err != nil {
@@ -843,11 +910,12 @@ func (e *Executor) setupTask(ctx context.Context, rt *runningTask) error {
 	if err := os.MkdirAll(filepath.Dir(setupLogPath), 0770); err != nil {
 		return errors.WithStack(err)
 	}
-	outf, err := os.Create(setupLogPath)
+	lf, err := createLogFile(setupLogPath)
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	defer outf.Close()
+	defer lf.Close()
+	outf := lf.Stream(tasklog.StreamCombined)
 
 	// error out if privileged containers are required but not allowed
 	requiresPrivilegedContainers := false
@@ -858,7 +926,7 @@ func (e *Executor) setupTask(ctx context.Context, rt *runningTask) error {
 		}
 	}
 	if requiresPrivilegedContainers && !e.c.AllowPrivilegedContainers {
-		_, _ = outf.WriteString("Executor doesn't allow executing privileged containers.\n")
+		_, _ = io.WriteString(outf, "Executor doesn't allow executing privileged containers.\n")
 		return errors.Errorf("executor doesn't allow executing privileged containers")
 	}
 
@@ -921,7 +989,7 @@ func (e *Executor) setupTask(ctx context.Context, rt *runningTask) error {
 		podConfig.Containers[i] = containerConfig
 	}
 
-	_, _ = outf.WriteString("Starting pod.\n")
+	_, _ = io.WriteString(outf, "Starting pod.\n")
 	podCtx, cancel := context.WithTimeout(ctx, podCreationTimeout)
 	defer cancel()
 	pod, err := e.driver.NewPod(podCtx, podConfig, outf)
@@ -929,7 +997,7 @@ func (e *Executor) setupTask(ctx context.Context, rt *runningTask) error {
 		_, _ = fmt.Fprintf(outf, "Pod failed to start. Error: %s\n", err)
 		return errors.WithStack(err)
 	}
-	_, _ = outf.WriteString("Pod started.\n")
+	_, _ = io.WriteString(outf, "Pod started.\n")
 
 	if et.Spec.WorkingDir != "" {
 		_, _ = fmt.Fprintf(outf, "Creating working dir %q.\n", et.Spec.WorkingDir)
@@ -953,6 +1021,8 @@ func (e *Executor) executeTaskSteps(ctx context.Context, rt *runningTask, pod dr
 		}
 		rt.Unlock()
 
+		stepCtx, stepSpan := tracing.Tracer().Start(ctx, fmt.Sprintf("step %d", i), trace.WithAttributes(attribute.Int("agola.step.number", i)))
+
 		var err error
 		var exitCode int
 		var stepName string
@@ -961,31 +1031,32 @@ func (e *Executor) executeTaskSteps(ctx context.Context, rt *runningTask, pod dr
 		case *types.RunStep:
 			e.log.Debug().Msgf("run step: %s", util.Dump(s))
 			stepName = s.Name
-			exitCode, err = e.doRunStep(ctx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i))
+			exitCode, err = e.doRunStep(stepCtx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i))
 
 		case *types.SaveToWorkspaceStep:
 			e.log.Debug().Msgf("save to workspace step: %s", util.Dump(s))
 			stepName = s.Name
 			archivePath := e.archivePath(rt.et.ID, i)
-			exitCode, err = e.doSaveToWorkspaceStep(ctx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i), archivePath)
+			exitCode, err = e.doSaveToWorkspaceStep(stepCtx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i), archivePath)
 
 		case *types.RestoreWorkspaceStep:
 			e.log.Debug().Msgf("restore workspace step: %s", util.Dump(s))
 			stepName = s.Name
-			exitCode, err = e.doRestoreWorkspaceStep(ctx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i))
+			exitCode, err = e.doRestoreWorkspaceStep(stepCtx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i))
 
 		case *types.SaveCacheStep:
 			e.log.Debug().Msgf("save cache step: %s", util.Dump(s))
 			stepName = s.Name
 			archivePath := e.archivePath(rt.et.ID, i)
-			exitCode, err = e.doSaveCacheStep(ctx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i), archivePath)
+			exitCode, err = e.doSaveCacheStep(stepCtx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i), archivePath)
 
 		case *types.RestoreCacheStep:
 			e.log.Debug().Msgf("restore cache step: %s", util.Dump(s))
 			stepName = s.Name
-			exitCode, err = e.doRestoreCacheStep(ctx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i))
+			exitCode, err = e.doRestoreCacheStep(stepCtx, s, rt.et, pod, e.stepLogPath(rt.et.ID, i))
 
 		default:
+			tracing.EndSpan(stepSpan, "", true)
 			return i, errors.Errorf("unknown step type: %s", util.Dump(s))
 		}
 
@@ -1015,6 +1086,9 @@ func (e *Executor) executeTaskSteps(ctx context.Context, rt *runningTask, pod dr
 			rt.et.Status.Steps[i].ExitStatus = util.Ptr(exitCode)
 		}
 
+		stepSpan.SetAttributes(attribute.String("agola.step.name", stepName), attribute.Int("agola.step.exit_code", exitCode))
+		tracing.EndSpan(stepSpan, string(rt.et.Status.Steps[i].Phase), serr != nil)
+
 		if err := e.sendExecutorTaskStatus(ctx, rt.et); err != nil {
 			e.log.Err(err).Send()
 		}
@@ -1531,8 +1605,11 @@ func (e *Executor) Run(ctx context.Context) error {
 	authHandler := handlers.NewInternalAuthChecker(e.log, e.c.APIToken)
 
 	router := mux.NewRouter()
+	router.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
 	apirouter := router.PathPrefix("/api/v1alpha").Subrouter()
 
+	apirouter.Use(tracing.NewHTTPMiddleware("executor"))
+	apirouter.Use(metrics.NewHTTPMiddleware("executor"))
 	apirouter.Use(authHandler)
 
 	apirouter.Handle("/executor", schedulerHandler).Methods("POST")
@@ -1550,7 +1627,7 @@ func (e *Executor) Run(ctx context.Context) error {
 
 	httpServer := http.Server{
 		Addr:    e.listenAddress,
-		Handler: apirouter,
+		Handler: router,
 	}
 	lerrCh := make(chan error)
 	go func() {
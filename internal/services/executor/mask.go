@@ -0,0 +1,157 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/sorintlab/errors"
+)
+
+const (
+	secretMask = "***"
+
+	// minSecretMaskLength is the minimum length of a secret value to be
+	// masked. Shorter values would mask too much unrelated log content.
+	minSecretMaskLength = 4
+)
+
+// secretMaskValues returns the values to mask for the provided secret values:
+// the values, their base64 and url encoded variants and, for multiline values,
+// every single line.
+func secretMaskValues(secretValues []string) [][]byte {
+	values := []string{}
+	add := func(v string) {
+		if len(v) < minSecretMaskLength || slices.Contains(values, v) {
+			return
+		}
+		values = append(values, v)
+	}
+
+	for _, sv := range secretValues {
+		variants := []string{sv}
+		if strings.Contains(sv, "\n") {
+			for _, line := range strings.Split(sv, "\n") {
+				variants = append(variants, strings.TrimSpace(line))
+			}
+		}
+
+		for _, v := range variants {
+			add(v)
+			add(base64.StdEncoding.EncodeToString([]byte(v)))
+			add(base64.RawStdEncoding.EncodeToString([]byte(v)))
+			add(base64.URLEncoding.EncodeToString([]byte(v)))
+			add(base64.RawURLEncoding.EncodeToString([]byte(v)))
+			add(url.QueryEscape(v))
+			add(url.PathEscape(v))
+		}
+	}
+
+	// match longer values first
+	slices.SortStableFunc(values, func(a, b string) int { return len(b) - len(a) })
+
+	mvalues := make([][]byte, len(values))
+	for i, v := range values {
+		mvalues[i] = []byte(v)
+	}
+
+	return mvalues
+}
+
+// maskWriter is an io.Writer that replaces the secret values written to it
+// with a mask before writing them to the underlying writer.
+// Since a secret value could be split across multiple writes, the data that
+// could be the start of a secret value is kept until the next write or Flush.
+type maskWriter struct {
+	w      io.Writer
+	values [][]byte
+
+	m   sync.Mutex
+	buf []byte
+}
+
+func newMaskWriter(w io.Writer, secretValues []string) *maskWriter {
+	return &maskWriter{
+		w:      w,
+		values: secretMaskValues(secretValues),
+	}
+}
+
+func (mw *maskWriter) Write(p []byte) (int, error) {
+	mw.m.Lock()
+	defer mw.m.Unlock()
+
+	if len(mw.values) == 0 {
+		n, err := mw.w.Write(p)
+		return n, errors.WithStack(err)
+	}
+
+	mw.buf = append(mw.buf, p...)
+	if err := mw.mask(false); err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the remaining buffered data.
+func (mw *maskWriter) Flush() error {
+	mw.m.Lock()
+	defer mw.m.Unlock()
+
+	return errors.WithStack(mw.mask(true))
+}
+
+// mask writes the masked buffered data to the underlying writer. If final is
+// false the data at the end of the buffer that is the start of a secret value
+// is kept in the buffer.
+func (mw *maskWriter) mask(final bool) error {
+	out := make([]byte, 0, len(mw.buf))
+
+	i := 0
+loop:
+	for i < len(mw.buf) {
+		rest := mw.buf[i:]
+		// values are sorted by length so a longer value that could match with
+		// the next writes takes precedence over a shorter matching value
+		for _, v := range mw.values {
+			if bytes.HasPrefix(rest, v) {
+				out = append(out, secretMask...)
+				i += len(v)
+				continue loop
+			}
+			if !final && len(rest) < len(v) && bytes.HasPrefix(v, rest) {
+				break loop
+			}
+		}
+		out = append(out, mw.buf[i])
+		i++
+	}
+
+	mw.buf = append(mw.buf[:0], mw.buf[i:]...)
+
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := mw.w.Write(out)
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"agola.io/agola/internal/testutil"
+)
+
+func TestMaskWriter(t *testing.T) {
+	tests := []struct {
+		name         string
+		secretValues []string
+		writes       []string
+		out          string
+	}{
+		{
+			name:   "test no secret values",
+			writes: []string{"hello ", "world\n"},
+			out:    "hello world\n",
+		},
+		{
+			name:         "test secret value in a single write",
+			secretValues: []string{"supersecret"},
+			writes:       []string{"the password is supersecret\n"},
+			out:          "the password is ***\n",
+		},
+		{
+			name:         "test multiple occurrences of a secret value",
+			secretValues: []string{"supersecret"},
+			writes:       []string{"supersecret supersecret\nsupersecret"},
+			out:          "*** ***\n***",
+		},
+		{
+			name:         "test secret value split across writes",
+			secretValues: []string{"supersecret"},
+			writes:       []string{"the password is sup", "ers", "ecret\n"},
+			out:          "the password is ***\n",
+		},
+		{
+			name:         "test secret value split across single byte writes",
+			secretValues: []string{"supersecret"},
+			writes:       []string{"s", "u", "p", "e", "r", "s", "e", "c", "r", "e", "t", "\n"},
+			out:          "***\n",
+		},
+		{
+			name:         "test partial secret value at the end of the stream",
+			secretValues: []string{"supersecret"},
+			writes:       []string{"the password is not super"},
+			out:          "the password is not super",
+		},
+		{
+			name:         "test partial secret value followed by other data",
+			secretValues: []string{"supersecret"},
+			writes:       []string{"supers", "tar\n"},
+			out:          "superstar\n",
+		},
+		{
+			name:         "test multiple secret values",
+			secretValues: []string{"supersecret", "anothersecret"},
+			writes:       []string{"supersecret and anoth", "ersecret\n"},
+			out:          "*** and ***\n",
+		},
+		{
+			name:         "test longer secret value containing a shorter one",
+			secretValues: []string{"secret", "secretpassword"},
+			writes:       []string{"secret", "password secret\n"},
+			out:          "*** ***\n",
+		},
+		{
+			name:         "test base64 encoded secret value",
+			secretValues: []string{"supersecret"},
+			writes:       []string{base64.StdEncoding.EncodeToString([]byte("supersecret")) + "\n"},
+			out:          "***\n",
+		},
+		{
+			name:         "test url encoded secret value",
+			secretValues: []string{"super secret&"},
+			writes:       []string{"token=" + url.QueryEscape("super secret&") + "\n"},
+			out:          "token=***\n",
+		},
+		{
+			name:         "test multiline secret value lines",
+			secretValues: []string{"firstline\nsecondline\n"},
+			writes:       []string{"secondline\n"},
+			out:          "***\n",
+		},
+		{
+			name:         "test too short secret value",
+			secretValues: []string{"abc"},
+			writes:       []string{"abcdef\n"},
+			out:          "abcdef\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			mw := newMaskWriter(&buf, tt.secretValues)
+
+			for _, w := range tt.writes {
+				n, err := mw.Write([]byte(w))
+				testutil.NilError(t, err)
+				assert.Equal(t, n, len(w))
+			}
+			testutil.NilError(t, mw.Flush())
+
+			assert.Equal(t, buf.String(), tt.out)
+		})
+	}
+}
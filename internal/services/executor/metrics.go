@@ -0,0 +1,42 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"agola.io/agola/internal/metrics"
+)
+
+const (
+	metricsSubsystem = "executor"
+)
+
+var (
+	activeTasksGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "active_tasks",
+		Help:      "Number of tasks currently executed by the executor.",
+	}, []string{"executor_id"})
+
+	activeTasksLimitGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "active_tasks_limit",
+		Help:      "Maximum number of tasks that the executor can execute concurrently.",
+	}, []string{"executor_id"})
+)
@@ -0,0 +1,200 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	scommon "agola.io/agola/internal/services/common"
+	"agola.io/agola/internal/services/gateway/common"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/client"
+	cstypes "agola.io/agola/services/configstore/types"
+)
+
+type auditEvent struct {
+	action cstypes.AuditAction
+
+	targetKind cstypes.ObjectKind
+	targetID   string
+	targetName string
+
+	// scopeKind and scopeID are used by the configstore to find the
+	// organization owning the target
+	scopeKind cstypes.ObjectKind
+	scopeID   string
+
+	details map[string]string
+}
+
+// newRunAuditEvent creates an audit event for an action on a run. Project runs
+// are scoped to their project.
+func newRunAuditEvent(action cstypes.AuditAction, groupType scommon.GroupType, groupID, runID string, runNumber uint64, details map[string]string) *auditEvent {
+	ev := &auditEvent{
+		action:     action,
+		targetKind: cstypes.AuditTargetKindRun,
+		targetID:   runID,
+		targetName: strconv.FormatUint(runNumber, 10),
+		details:    details,
+	}
+	if groupType == scommon.GroupTypeProject {
+		ev.scopeKind = cstypes.ObjectKindProject
+		ev.scopeID = groupID
+	}
+
+	return ev
+}
+
+// recordAuditEvent saves an audit event for an action executed by the
+// authenticated user. Since the audited action has already been executed,
+// errors are only logged.
+func (h *ActionHandler) recordAuditEvent(ctx context.Context, ev *auditEvent) {
+	actorUserName := common.CurrentUserName(ctx)
+	if actorUserName == "" && common.IsUserAdmin(ctx) {
+		// request authenticated with the admin token
+		actorUserName = "admin"
+	}
+
+	req := &csapitypes.CreateAuditEventRequest{
+		ActorUserID:   common.CurrentUserID(ctx),
+		ActorUserName: actorUserName,
+		Action:        ev.action,
+		TargetKind:    ev.targetKind,
+		TargetID:      ev.targetID,
+		TargetName:    ev.targetName,
+		ScopeKind:     ev.scopeKind,
+		ScopeID:       ev.scopeID,
+		Details:       ev.details,
+		RequestMetadata: cstypes.AuditEventRequestMetadata{
+			RemoteAddr: common.RemoteAddr(ctx),
+			UserAgent:  common.UserAgent(ctx),
+		},
+	}
+
+	if _, _, err := h.configstoreClient.CreateAuditEvent(ctx, req); err != nil {
+		h.log.Err(err).Msgf("failed to record audit event %q", ev.action)
+	}
+}
+
+type GetAuditEventsRequest struct {
+	OrgRef string
+
+	ActorUserRef string
+	Actions      []string
+	TargetKind   string
+	TargetID     string
+	Since        *time.Time
+	Until        *time.Time
+
+	Cursor string
+
+	Limit         int
+	SortDirection SortDirection
+}
+
+type GetAuditEventsResponse struct {
+	AuditEvents []*cstypes.AuditEvent
+	Cursor      string
+}
+
+// GetAuditEvents returns the audit events. All the audit events are available
+// only to admins while organization owners can get the audit events of their
+// organizations.
+func (h *ActionHandler) GetAuditEvents(ctx context.Context, req *GetAuditEventsRequest) (*GetAuditEventsResponse, error) {
+	if req.OrgRef == "" {
+		if !common.IsUserAdmin(ctx) {
+			return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+		}
+	} else {
+		org, _, err := h.configstoreClient.GetOrg(ctx, req.OrgRef)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err)
+		}
+		isOrgOwner, err := h.IsAuthUserOrgOwner(ctx, org.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine ownership")
+		}
+		if !isOrgOwner {
+			return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+		}
+	}
+
+	inCursor := &AuditEventsCursor{
+		ActorUserRef: req.ActorUserRef,
+		Actions:      req.Actions,
+		TargetKind:   req.TargetKind,
+		TargetID:     req.TargetID,
+		Since:        req.Since,
+		Until:        req.Until,
+	}
+	sortDirection := req.SortDirection
+	if req.Cursor != "" {
+		if err := UnmarshalCursor(req.Cursor, inCursor); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		sortDirection = inCursor.SortDirection
+	}
+	if sortDirection == "" {
+		sortDirection = SortDirectionAsc
+	}
+
+	opts := &client.GetAuditEventsOptions{
+		ListOptions:   &client.ListOptions{Limit: req.Limit, SortDirection: cstypes.SortDirection(sortDirection)},
+		StartSequence: inCursor.StartSequence,
+		OrgRef:        req.OrgRef,
+		TargetKind:    cstypes.ObjectKind(inCursor.TargetKind),
+		TargetID:      inCursor.TargetID,
+		Since:         inCursor.Since,
+		Until:         inCursor.Until,
+	}
+	for _, a := range inCursor.Actions {
+		opts.Actions = append(opts.Actions, cstypes.AuditAction(a))
+	}
+	if inCursor.ActorUserRef != "" {
+		user, _, err := h.configstoreClient.GetUser(ctx, inCursor.ActorUserRef)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err)
+		}
+		opts.ActorUserID = user.ID
+	}
+
+	auditEvents, resp, err := h.configstoreClient.GetAuditEvents(ctx, opts)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	var outCursor string
+	if resp.HasMore && len(auditEvents) > 0 {
+		outC := *inCursor
+		outC.StartSequence = auditEvents[len(auditEvents)-1].Sequence
+		outC.SortDirection = sortDirection
+		outCursor, err = MarshalCursor(&outC)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	res := &GetAuditEventsResponse{
+		AuditEvents: auditEvents,
+		Cursor:      outCursor,
+	}
+
+	return res, nil
+}
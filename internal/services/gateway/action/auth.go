@@ -52,38 +52,97 @@ func (h *ActionHandler) IsAuthUserOrgOwner(ctx context.Context, orgID string) (b
 	return false, nil
 }
 
-func (h *ActionHandler) IsAuthUserProjectOwner(ctx context.Context, ownerType cstypes.ObjectKind, ownerID string) (bool, error) {
+// GetAuthUserRole returns the effective role of the authenticated user on the
+// provided organization, project group or project. An empty role is returned
+// if the user doesn't have any role on it.
+func (h *ActionHandler) GetAuthUserRole(ctx context.Context, kind cstypes.ObjectKind, ref string) (cstypes.MemberRole, error) {
 	isAdmin := common.IsUserAdmin(ctx)
 	if isAdmin {
-		return true, nil
+		return cstypes.MemberRoleOwner, nil
 	}
 
 	userID := common.CurrentUserID(ctx)
 	if userID == "" {
-		return false, nil
+		return "", nil
 	}
 
-	if ownerType == cstypes.ObjectKindUser {
-		if userID == ownerID {
-			return true, nil
-		}
+	var userRole *csapitypes.UserRoleResponse
+	var err error
+	switch kind {
+	case cstypes.ObjectKindOrg:
+		return h.getOrgMemberRole(ctx, ref, userID)
+	case cstypes.ObjectKindProjectGroup:
+		userRole, _, err = h.configstoreClient.GetProjectGroupUserRole(ctx, ref, userID)
+	case cstypes.ObjectKindProject:
+		userRole, _, err = h.configstoreClient.GetProjectUserRole(ctx, ref, userID)
+	default:
+		return "", errors.Errorf("unsupported object kind %q", kind)
+	}
+	if err != nil {
+		return "", APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to get user role"))
 	}
 
-	if ownerType == cstypes.ObjectKindOrg {
-		userOrg, _, err := h.configstoreClient.GetUserOrg(ctx, userID, ownerID)
-		if err != nil {
-			if util.RemoteErrorIs(err, util.ErrNotExist) {
-				return false, nil
-			}
-			return false, APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to get user org"))
+	return userRole.Role, nil
+}
+
+// AuthUserHasRole reports whether the effective role of the authenticated user
+// on the provided organization, project group or project includes the provided
+// role.
+func (h *ActionHandler) AuthUserHasRole(ctx context.Context, kind cstypes.ObjectKind, ref string, role cstypes.MemberRole) (bool, error) {
+	userRole, err := h.GetAuthUserRole(ctx, kind, ref)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return userRole.Includes(role), nil
+}
+
+// getOrgMemberRole returns the role of the user in the organization or an
+// empty role if the user isn't an organization member.
+func (h *ActionHandler) getOrgMemberRole(ctx context.Context, orgRef, userRef string) (cstypes.MemberRole, error) {
+	userOrg, _, err := h.configstoreClient.GetUserOrg(ctx, userRef, orgRef)
+	if err != nil {
+		if util.RemoteErrorIs(err, util.ErrNotExist) {
+			return "", nil
 		}
+		return "", APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to get user org"))
+	}
 
-		if userOrg.Role == cstypes.MemberRoleOwner {
-			return true, nil
+	return userOrg.Role, nil
+}
+
+// checkAuthUserOrgAdmin verifies that the authenticated user is an admin of
+// the organization. memberRoles are the organization member roles assigned or
+// revoked by the action: only the organization owners can manage the owner
+// role.
+func (h *ActionHandler) checkAuthUserOrgAdmin(ctx context.Context, orgID string, memberRoles ...cstypes.MemberRole) error {
+	requiredRole := cstypes.MemberRoleAdmin
+	for _, memberRole := range memberRoles {
+		if memberRole == cstypes.MemberRoleOwner {
+			requiredRole = cstypes.MemberRoleOwner
 		}
 	}
 
-	return false, nil
+	hasRole, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindOrg, orgID, requiredRole)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine permissions")
+	}
+	if !hasRole {
+		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	}
+
+	return nil
+}
+
+// isAuthUser reports whether the authenticated user is the provided user or an
+// admin.
+func isAuthUser(ctx context.Context, userID string) bool {
+	if common.IsUserAdmin(ctx) {
+		return true
+	}
+
+	curUserID := common.CurrentUserID(ctx)
+	return curUserID != "" && curUserID == userID
 }
 
 func (h *ActionHandler) IsAuthUserMember(ctx context.Context, ownerType cstypes.ObjectKind, ownerID string) (bool, error) {
@@ -120,67 +179,52 @@ func (h *ActionHandler) IsAuthUserMember(ctx context.Context, ownerType cstypes.
 }
 
 func (h *ActionHandler) IsAuthUserVariableOwner(ctx context.Context, parentType cstypes.ObjectKind, parentRef string) (bool, error) {
-	var ownerType cstypes.ObjectKind
-	var ownerID string
-	switch parentType {
-	case cstypes.ObjectKindProjectGroup:
-		pg, _, err := h.configstoreClient.GetProjectGroup(ctx, parentRef)
-		if err != nil {
-			return false, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project group %q", parentRef))
-		}
-		ownerType = pg.OwnerType
-		ownerID = pg.OwnerID
-	case cstypes.ObjectKindProject:
-		p, _, err := h.configstoreClient.GetProject(ctx, parentRef)
-		if err != nil {
-			return false, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project  %q", parentRef))
-		}
-		ownerType = p.OwnerType
-		ownerID = p.OwnerID
-	}
-
-	return h.IsAuthUserProjectOwner(ctx, ownerType, ownerID)
+	return h.AuthUserHasRole(ctx, parentType, parentRef, cstypes.MemberRoleMaintainer)
 }
 
 func (h *ActionHandler) CanAuthUserGetRun(ctx context.Context, groupType scommon.GroupType, ref string) (bool, string, error) {
-	var visibility cstypes.Visibility
-	var ownerType cstypes.ObjectKind
-	var refID string
-	var ownerID string
 	switch groupType {
 	case scommon.GroupTypeProject:
 		p, _, err := h.configstoreClient.GetProject(ctx, ref)
 		if err != nil {
 			return false, "", APIErrorFromRemoteError(err)
 		}
-		refID = p.ID
-		ownerID = p.OwnerID
-		ownerType = p.OwnerType
-		visibility = p.GlobalVisibility
+
+		canGetRun, err := h.canAuthUserGetProjectRun(ctx, p)
+		if err != nil {
+			return false, "", errors.WithStack(err)
+		}
+		if !canGetRun {
+			return false, "", nil
+		}
+		return true, p.ID, nil
 	case scommon.GroupTypeUser:
 		u, _, err := h.configstoreClient.GetUser(ctx, ref)
 		if err != nil {
 			return false, "", APIErrorFromRemoteError(err)
 		}
 
-		// user direct runs
-		refID = u.ID
-		ownerType = cstypes.ObjectKindUser
-		ownerID = u.ID
-		visibility = cstypes.VisibilityPrivate
+		// user direct runs are private
+		if !isAuthUser(ctx, u.ID) {
+			return false, "", nil
+		}
+		return true, u.ID, nil
 	}
 
-	if visibility == cstypes.VisibilityPublic {
-		return true, refID, nil
+	return false, "", nil
+}
+
+func (h *ActionHandler) canAuthUserGetProjectRun(ctx context.Context, p *csapitypes.Project) (bool, error) {
+	if p.GlobalVisibility == cstypes.VisibilityPublic {
+		return true, nil
 	}
-	isProjectMember, err := h.IsAuthUserMember(ctx, ownerType, ownerID)
+
+	isViewer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleViewer)
 	if err != nil {
-		return false, "", errors.Wrapf(err, "failed to determine ownership")
-	}
-	if !isProjectMember {
-		return false, "", nil
+		return false, errors.Wrapf(err, "failed to determine permissions")
 	}
-	return true, refID, nil
+
+	return isViewer, nil
 }
 
 type actionType string
@@ -192,49 +236,43 @@ const (
 )
 
 func (h *ActionHandler) CanAuthUserDoRunActions(ctx context.Context, groupType scommon.GroupType, ref string, actionType actionType) (bool, string, error) {
-	var ownerType cstypes.ObjectKind
-	var refID string
-	var ownerID string
-	var p *csapitypes.Project
 	switch groupType {
 	case scommon.GroupTypeProject:
-		var err error
-		p, _, err = h.configstoreClient.GetProject(ctx, ref)
+		p, _, err := h.configstoreClient.GetProject(ctx, ref)
 		if err != nil {
 			return false, "", APIErrorFromRemoteError(err)
 		}
-		refID = p.ID
-		ownerType = p.OwnerType
-		ownerID = p.OwnerID
-	case scommon.GroupTypeUser:
-		u, _, err := h.configstoreClient.GetUser(ctx, ref)
+
+		userRole, err := h.GetAuthUserRole(ctx, cstypes.ObjectKindProject, p.ID)
 		if err != nil {
-			return false, "", APIErrorFromRemoteError(err)
+			return false, "", errors.Wrapf(err, "failed to determine permissions")
 		}
 
-		// user direct runs
-		refID = u.ID
-		ownerType = cstypes.ObjectKindUser
-		ownerID = u.ID
-	}
+		requiredRole := cstypes.MemberRoleDeveloper
+		if actionType == actionTypeDeleteLogs {
+			requiredRole = cstypes.MemberRoleMaintainer
+		}
+		if userRole.Includes(requiredRole) {
+			return true, p.ID, nil
+		}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, ownerType, ownerID)
-	if err != nil {
-		return false, "", errors.Wrapf(err, "failed to determine ownership")
-	}
-	if isProjectOwner {
-		return true, refID, nil
-	}
+		// keep honoring the project option that lets plain members perform run actions
+		if actionType == actionTypeRunAction && p.MembersCanPerformRunActions && userRole.Includes(cstypes.MemberRoleMember) {
+			return true, p.ID, nil
+		}
 
-	if actionType == actionTypeRunAction && ownerType == cstypes.ObjectKindOrg {
-		userID := common.CurrentUserID(ctx)
-		isUserOrgMember, err := h.IsUserOrgMember(ctx, userID, ownerID)
+		return false, "", nil
+	case scommon.GroupTypeUser:
+		u, _, err := h.configstoreClient.GetUser(ctx, ref)
 		if err != nil {
-			return false, "", errors.Wrapf(err, "failed to determine ownership")
+			return false, "", APIErrorFromRemoteError(err)
 		}
-		if isUserOrgMember && p.MembersCanPerformRunActions {
-			return true, refID, nil
+
+		// user direct runs
+		if !isAuthUser(ctx, u.ID) {
+			return false, "", nil
 		}
+		return true, u.ID, nil
 	}
 
 	return false, "", nil
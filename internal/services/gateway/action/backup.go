@@ -0,0 +1,82 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"time"
+
+	"agola.io/agola/internal/services/gateway/common"
+	"agola.io/agola/internal/util"
+)
+
+type Backup struct {
+	ID   string
+	Time time.Time
+	Size int64
+}
+
+func (h *ActionHandler) GetBackups(ctx context.Context, serviceName string) ([]*Backup, error) {
+	if !common.IsUserAdmin(ctx) {
+		return nil, util.NewAPIError(util.ErrUnauthorized, util.WithAPIErrorMsg("user not admin"))
+	}
+
+	var backups []*Backup
+	switch serviceName {
+	case ConfigstoreService:
+		csBackups, _, err := h.configstoreClient.GetBackups(ctx)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err)
+		}
+		for _, b := range csBackups {
+			backups = append(backups, &Backup{ID: b.ID, Time: b.Time, Size: b.Size})
+		}
+	case RunserviceService:
+		rsBackups, _, err := h.runserviceClient.GetBackups(ctx)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err)
+		}
+		for _, b := range rsBackups {
+			backups = append(backups, &Backup{ID: b.ID, Time: b.Time, Size: b.Size})
+		}
+	default:
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid service name %q", serviceName))
+	}
+
+	return backups, nil
+}
+
+// RestoreBackup restores a service database backup. The service must be in
+// maintenance mode and its database must be empty.
+func (h *ActionHandler) RestoreBackup(ctx context.Context, serviceName, backupID string) error {
+	if !common.IsUserAdmin(ctx) {
+		return util.NewAPIError(util.ErrUnauthorized, util.WithAPIErrorMsg("user not admin"))
+	}
+
+	var err error
+	switch serviceName {
+	case ConfigstoreService:
+		_, err = h.configstoreClient.RestoreBackup(ctx, backupID)
+	case RunserviceService:
+		_, err = h.runserviceClient.RestoreBackup(ctx, backupID)
+	default:
+		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid service name %q", serviceName))
+	}
+	if err != nil {
+		return APIErrorFromRemoteError(err)
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/sorintlab/errors"
 
 	"agola.io/agola/internal/util"
+	cstypes "agola.io/agola/services/configstore/types"
 	"agola.io/agola/services/notification/client"
 	nstypes "agola.io/agola/services/notification/types"
 )
@@ -44,11 +45,11 @@ func (h *ActionHandler) GetProjectCommitStatusDeliveries(ctx context.Context, re
 	if err != nil {
 		return nil, APIErrorFromRemoteError(err)
 	}
-	isUserOwner, err := h.IsAuthUserProjectOwner(ctx, project.OwnerType, project.OwnerID)
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, project.ID, cstypes.MemberRoleMaintainer)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isUserOwner {
+	if !isMaintainer {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
@@ -103,11 +104,11 @@ func (h *ActionHandler) ProjectCommitStatusRedelivery(ctx context.Context, req *
 	if err != nil {
 		return APIErrorFromRemoteError(err)
 	}
-	isUserOwner, err := h.IsAuthUserProjectOwner(ctx, project.OwnerType, project.OwnerID)
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, project.ID, cstypes.MemberRoleMaintainer)
 	if err != nil {
 		return errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isUserOwner {
+	if !isMaintainer {
 		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
@@ -3,6 +3,7 @@ package action
 import (
 	"encoding/base64"
 	"encoding/json"
+	"time"
 
 	"github.com/sorintlab/errors"
 
@@ -54,3 +55,16 @@ type GroupRunsCursor struct {
 	PhaseFilter  []string
 	ResultFilter []string
 }
+
+type AuditEventsCursor struct {
+	StartSequence uint64
+
+	SortDirection SortDirection
+
+	ActorUserRef string
+	Actions      []string
+	TargetKind   string
+	TargetID     string
+	Since        *time.Time
+	Until        *time.Time
+}
@@ -215,6 +215,8 @@ func (h *ActionHandler) CreateOrg(ctx context.Context, req *CreateOrgRequest) (*
 	}
 	h.log.Info().Msgf("organization %s created, ID: %s", org.Name, org.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionOrgCreate, targetKind: cstypes.ObjectKindOrg, targetID: org.ID, targetName: org.Name, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID})
+
 	return org, nil
 }
 
@@ -251,6 +253,8 @@ func (h *ActionHandler) UpdateOrg(ctx context.Context, orgRef string, req *Updat
 	}
 	h.log.Info().Msgf("organization %s updated, ID: %s", org.Name, org.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionOrgUpdate, targetKind: cstypes.ObjectKindOrg, targetID: org.ID, targetName: org.Name, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID, details: map[string]string{"visibility": string(org.Visibility)}})
+
 	return org, nil
 }
 
@@ -271,6 +275,9 @@ func (h *ActionHandler) DeleteOrg(ctx context.Context, orgRef string) error {
 	if _, err := h.configstoreClient.DeleteOrg(ctx, orgRef); err != nil {
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete org"))
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionOrgDelete, targetKind: cstypes.ObjectKindOrg, targetID: org.ID, targetName: org.Name, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID})
+
 	return nil
 }
 
@@ -294,12 +301,12 @@ func (h *ActionHandler) AddOrgMember(ctx context.Context, orgRef, userRef string
 		return nil, APIErrorFromRemoteError(err)
 	}
 
-	isOrgOwner, err := h.IsAuthUserOrgOwner(ctx, org.ID)
+	curRole, err := h.getOrgMemberRole(ctx, org.ID, user.ID)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.WithStack(err)
 	}
-	if !isOrgOwner {
-		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	if err := h.checkAuthUserOrgAdmin(ctx, org.ID, role, curRole); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	orgmember, _, err := h.configstoreClient.AddOrgMember(ctx, orgRef, userRef, role)
@@ -307,6 +314,8 @@ func (h *ActionHandler) AddOrgMember(ctx context.Context, orgRef, userRef string
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to add/update organization member"))
 	}
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionOrgMemberAdd, targetKind: cstypes.ObjectKindUser, targetID: user.ID, targetName: user.Name, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID, details: map[string]string{"role": string(role)}})
+
 	return &AddOrgMemberResponse{
 		OrganizationMember: orgmember,
 		Org:                org,
@@ -320,18 +329,20 @@ func (h *ActionHandler) RemoveOrgMember(ctx context.Context, orgRef, userRef str
 		return APIErrorFromRemoteError(err)
 	}
 
-	isOrgOwner, err := h.IsAuthUserOrgOwner(ctx, org.ID)
+	curRole, err := h.getOrgMemberRole(ctx, org.ID, userRef)
 	if err != nil {
-		return errors.Wrapf(err, "failed to determine ownership")
+		return errors.WithStack(err)
 	}
-	if !isOrgOwner {
-		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	if err := h.checkAuthUserOrgAdmin(ctx, org.ID, curRole); err != nil {
+		return errors.WithStack(err)
 	}
 
 	if _, err = h.configstoreClient.RemoveOrgMember(ctx, orgRef, userRef); err != nil {
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to remove organization member"))
 	}
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionOrgMemberRemove, targetKind: cstypes.ObjectKindUser, targetName: userRef, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID})
+
 	return nil
 }
 
@@ -350,12 +361,8 @@ func (h *ActionHandler) GetOrgInvitations(ctx context.Context, orgRef string, li
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get org %s", orgRef))
 	}
 
-	isOrgOwner, err := h.IsAuthUserOrgOwner(ctx, org.ID)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
-	}
-	if !isOrgOwner {
-		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	if err := h.checkAuthUserOrgAdmin(ctx, org.ID); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	orgInvitations, _, err := h.configstoreClient.GetOrgInvitations(ctx, orgRef, limit)
@@ -395,12 +402,8 @@ func (h *ActionHandler) CreateOrgInvitation(ctx context.Context, req *CreateOrgI
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get org %s", req.OrganizationRef))
 	}
 
-	isOrgOwner, err := h.IsAuthUserOrgOwner(ctx, org.ID)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
-	}
-	if !isOrgOwner {
-		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	if err := h.checkAuthUserOrgAdmin(ctx, org.ID, req.Role); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	isOrgMember, err := h.IsUserOrgMember(ctx, req.UserRef, req.OrganizationRef)
@@ -498,12 +501,8 @@ func (h *ActionHandler) DeleteOrgInvitation(ctx context.Context, orgRef string,
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get org %s", orgInvitation.OrganizationID))
 	}
 
-	isOrgOwner, err := h.IsAuthUserOrgOwner(ctx, org.ID)
-	if err != nil {
-		return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to determine ownership"))
-	}
-	if !isOrgOwner {
-		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user is not owner"))
+	if err := h.checkAuthUserOrgAdmin(ctx, org.ID, orgInvitation.Role); err != nil {
+		return errors.WithStack(err)
 	}
 
 	_, err = h.configstoreClient.DeleteOrgInvitation(ctx, orgRef, userRef)
@@ -41,11 +41,11 @@ func (h *ActionHandler) GetProject(ctx context.Context, projectRef string) (*csa
 		return project, nil
 	}
 
-	isMember, err := h.IsAuthUserMember(ctx, project.OwnerType, project.OwnerID)
+	isViewer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, project.ID, cstypes.MemberRoleViewer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isMember {
+	if !isViewer {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
@@ -61,6 +61,14 @@ type CreateProjectRequest struct {
 	SkipSSHHostKeyCheck         bool
 	PassVarsToForkedPR          bool
 	MembersCanPerformRunActions bool
+
+	// MirrorURL is the plain git repository url of a mirror project. When
+	// provided a mirror project, polling the repository for new commits, is
+	// created instead of a remote source project.
+	MirrorURL        string
+	MirrorSSHHostKey string
+	MirrorBranches   []string
+	MirrorTags       []string
 }
 
 func (h *ActionHandler) CreateProject(ctx context.Context, req *CreateProjectRequest) (*csapitypes.Project, error) {
@@ -88,22 +96,31 @@ func (h *ActionHandler) CreateProject(ctx context.Context, req *CreateProjectReq
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get parent project group %q", req.ParentRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, pg.OwnerType, pg.OwnerID)
+	isAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, pg.ID, cstypes.MemberRoleAdmin)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isAdmin {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
 	if !util.ValidateName(req.Name) {
 		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid project name %q", req.Name), serrors.InvalidProjectName())
 	}
-	if req.RemoteSourceName == "" {
-		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty remote source name"), serrors.InvalidRemoteSourceName())
-	}
-	if req.RepoPath == "" {
-		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty remote repo path"), serrors.InvalidRepoPath())
+	if req.MirrorURL != "" {
+		if req.RemoteSourceName != "" || req.RepoPath != "" {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("remote source and mirror url cannot be both provided"), serrors.InvalidProjectMirror())
+		}
+		if err := util.ValidateGitRemoteURL(req.MirrorURL); err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("invalid mirror url %q", req.MirrorURL), serrors.InvalidProjectMirror())
+		}
+	} else {
+		if req.RemoteSourceName == "" {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty remote source name"), serrors.InvalidRemoteSourceName())
+		}
+		if req.RepoPath == "" {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty remote repo path"), serrors.InvalidRepoPath())
+		}
 	}
 
 	projectPath := path.Join(pg.Path, req.Name)
@@ -115,6 +132,10 @@ func (h *ActionHandler) CreateProject(ctx context.Context, req *CreateProjectReq
 		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q already exists", projectPath), serrors.ProjectAlreadyExists())
 	}
 
+	if req.MirrorURL != "" {
+		return h.createMirrorProject(ctx, req, parentRef)
+	}
+
 	gitSource, rs, la, err := h.getUserGitSource(ctx, req.RemoteSourceName, curUserID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create gitsource client")
@@ -173,6 +194,8 @@ func (h *ActionHandler) CreateProject(ctx context.Context, req *CreateProjectReq
 		return nil, errors.Wrapf(serr, "failed to setup git source repo")
 	}
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectCreate, targetKind: cstypes.ObjectKindProject, targetID: rp.ID, targetName: rp.Path, scopeKind: cstypes.ObjectKindProject, scopeID: rp.ID})
+
 	return rp, nil
 }
 
@@ -191,14 +214,25 @@ func (h *ActionHandler) UpdateProject(ctx context.Context, projectRef string, re
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleMaintainer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isMaintainer {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
+	// moving to another project group requires the admin role on it
+	if req.ParentRef != nil {
+		isParentAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, *req.ParentRef, cstypes.MemberRoleAdmin)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine permissions")
+		}
+		if !isParentAdmin {
+			return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+		}
+	}
+
 	if req.Name != nil {
 		p.Name = *req.Name
 	}
@@ -231,6 +265,18 @@ func (h *ActionHandler) UpdateProject(ctx context.Context, projectRef string, re
 		MembersCanPerformRunActions: p.MembersCanPerformRunActions,
 	}
 
+	// keep the current mirror config
+	if p.RemoteRepositoryConfigType == cstypes.RemoteRepositoryConfigTypeMirror {
+		pm, _, err := h.configstoreClient.GetProjectMirror(ctx, p.ID)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q mirror", p.ID))
+		}
+		creq.MirrorURL = pm.URL
+		creq.MirrorSSHHostKey = pm.SSHHostKey
+		creq.MirrorBranches = pm.Branches
+		creq.MirrorTags = pm.Tags
+	}
+
 	h.log.Info().Msg("updating project")
 	rp, _, err := h.configstoreClient.UpdateProject(ctx, p.ID, creq)
 	if err != nil {
@@ -238,6 +284,8 @@ func (h *ActionHandler) UpdateProject(ctx context.Context, projectRef string, re
 	}
 	h.log.Info().Msgf("project %s updated, ID: %s", p.Name, p.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectUpdate, targetKind: cstypes.ObjectKindProject, targetID: rp.ID, targetName: rp.Path, scopeKind: cstypes.ObjectKindProject, scopeID: rp.ID})
+
 	return rp, nil
 }
 
@@ -252,14 +300,18 @@ func (h *ActionHandler) ProjectUpdateRepoLinkedAccount(ctx context.Context, proj
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleMaintainer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isMaintainer {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
+	if p.RemoteRepositoryConfigType != cstypes.RemoteRepositoryConfigTypeRemoteSource {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q isn't a remote source project", projectRef))
+	}
+
 	gitsource, _, la, err := h.getUserGitSource(ctx, p.RemoteSourceID, curUserID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create gitsource client")
@@ -381,14 +433,18 @@ func (h *ActionHandler) ReconfigProject(ctx context.Context, projectRef string)
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleMaintainer)
 	if err != nil {
-		return errors.Wrapf(err, "failed to determine ownership")
+		return errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isMaintainer {
 		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
+	if p.RemoteRepositoryConfigType != cstypes.RemoteRepositoryConfigTypeRemoteSource {
+		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q isn't a remote source project", projectRef))
+	}
+
 	user, rs, la, err := h.getRemoteRepoAccessData(ctx, p.LinkedAccountID)
 	if err != nil {
 		return errors.Wrapf(err, "failed to get remote repo access data")
@@ -405,21 +461,26 @@ func (h *ActionHandler) DeleteProject(ctx context.Context, projectRef string) er
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleAdmin)
 	if err != nil {
-		return errors.Wrapf(err, "failed to determine ownership")
+		return errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isAdmin {
 		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
 	// get data needed for repo cleanup
 	// we'll log but ignore errors
-	canDoRepCleanup := true
-	user, rs, la, err := h.getRemoteRepoAccessData(ctx, p.LinkedAccountID)
-	if err != nil {
-		canDoRepCleanup = false
-		h.log.Err(err).Msgf("failed to get remote repo access data: %+v", err)
+	canDoRepCleanup := p.RemoteRepositoryConfigType == cstypes.RemoteRepositoryConfigTypeRemoteSource
+	var user *cstypes.User
+	var rs *cstypes.RemoteSource
+	var la *cstypes.LinkedAccount
+	if canDoRepCleanup {
+		user, rs, la, err = h.getRemoteRepoAccessData(ctx, p.LinkedAccountID)
+		if err != nil {
+			canDoRepCleanup = false
+			h.log.Err(err).Msgf("failed to get remote repo access data: %+v", err)
+		}
 	}
 
 	h.log.Info().Msgf("deleting project with ID: %q", p.ID)
@@ -427,6 +488,8 @@ func (h *ActionHandler) DeleteProject(ctx context.Context, projectRef string) er
 		return APIErrorFromRemoteError(err)
 	}
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectDelete, targetKind: cstypes.ObjectKindProject, targetID: p.ID, targetName: p.Path, scopeKind: p.Parent.Kind, scopeID: p.Parent.ID})
+
 	// try to cleanup gitsource configs
 	// we'll log but ignore errors
 	if canDoRepCleanup {
@@ -450,17 +513,46 @@ func (h *ActionHandler) ProjectCreateRun(ctx context.Context, projectRef, branch
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isDeveloper, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleDeveloper)
 	if err != nil {
-		return errors.Wrapf(err, "failed to determine ownership")
+		return errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isDeveloper {
 		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
-	gitSource, rs, _, err := h.getUserGitSource(ctx, p.RemoteSourceID, curUserID)
-	if err != nil {
-		return errors.Wrapf(err, "failed to create gitsource client")
+	var gitSource gitsource.GitSource
+	var sshHostKey string
+	var skipSSHHostKeyCheck bool
+	switch p.RemoteRepositoryConfigType {
+	case cstypes.RemoteRepositoryConfigTypeMirror:
+		pm, _, err := h.configstoreClient.GetProjectMirror(ctx, p.ID)
+		if err != nil {
+			return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q mirror", p.ID))
+		}
+		mirrorGitSource, err := newProjectMirrorGitSource(ctx, p.Project, pm)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create gitsource client")
+		}
+		defer func() { _ = mirrorGitSource.Close() }()
+
+		gitSource = mirrorGitSource
+		sshHostKey = pm.SSHHostKey
+		skipSSHHostKeyCheck = p.SkipSSHHostKeyCheck
+
+	default:
+		var rs *cstypes.RemoteSource
+		gitSource, rs, _, err = h.getUserGitSource(ctx, p.RemoteSourceID, curUserID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create gitsource client")
+		}
+
+		sshHostKey = rs.SSHHostKey
+		// use remotesource skipSSHHostKeyCheck config and override with project config if set to true there
+		skipSSHHostKeyCheck = rs.SkipSSHHostKeyCheck
+		if p.SkipSSHHostKeyCheck {
+			skipSSHHostKeyCheck = p.SkipSSHHostKeyCheck
+		}
 	}
 
 	// check user has access to the repository
@@ -468,6 +560,10 @@ func (h *ActionHandler) ProjectCreateRun(ctx context.Context, projectRef, branch
 	if err != nil {
 		return errors.Wrapf(err, "failed to get repository info from gitsource")
 	}
+	cloneURL := repoInfo.SSHCloneURL
+	if cloneURL == "" {
+		cloneURL = repoInfo.HTTPCloneURL
+	}
 
 	set := 0
 	if branch != "" {
@@ -546,16 +642,11 @@ func (h *ActionHandler) ProjectCreateRun(ctx context.Context, projectRef, branch
 		tagLink = gitSource.TagLink(repoInfo, tag)
 	}
 
-	// use remotesource skipSSHHostKeyCheck config and override with project config if set to true there
-	skipSSHHostKeyCheck := rs.SkipSSHHostKeyCheck
-	if p.SkipSSHHostKeyCheck {
-		skipSSHHostKeyCheck = p.SkipSSHHostKeyCheck
-	}
-
 	req := &CreateRunRequest{
 		RunType:            types.RunTypeProject,
 		RefType:            refType,
 		RunCreationTrigger: types.RunCreationTriggerTypeManual,
+		TriggerUserID:      curUserID,
 
 		Project:             p.Project,
 		RepoPath:            p.RepositoryPath,
@@ -567,9 +658,9 @@ func (h *ActionHandler) ProjectCreateRun(ctx context.Context, projectRef, branch
 		PullRequestID:       "",
 		Ref:                 refName,
 		SSHPrivKey:          p.SSHPrivateKey,
-		SSHHostKey:          rs.SSHHostKey,
+		SSHHostKey:          sshHostKey,
 		SkipSSHHostKeyCheck: skipSSHHostKeyCheck,
-		CloneURL:            repoInfo.SSHCloneURL,
+		CloneURL:            cloneURL,
 
 		CommitLink:      gitSource.CommitLink(repoInfo, commitSHA),
 		BranchLink:      branchLink,
@@ -577,7 +668,8 @@ func (h *ActionHandler) ProjectCreateRun(ctx context.Context, projectRef, branch
 		PullRequestLink: "",
 	}
 
-	return h.CreateRuns(ctx, req)
+	_, err = h.CreateRuns(ctx, req)
+	return errors.WithStack(err)
 }
 
 func (h *ActionHandler) getRemoteRepoAccessData(ctx context.Context, linkedAccountID string) (*cstypes.User, *cstypes.RemoteSource, *cstypes.LinkedAccount, error) {
@@ -622,14 +714,18 @@ func (h *ActionHandler) RefreshRemoteRepositoryInfo(ctx context.Context, project
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, p.ID, cstypes.MemberRoleMaintainer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isMaintainer {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
+	if p.RemoteRepositoryConfigType != cstypes.RemoteRepositoryConfigTypeRemoteSource {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %q isn't a remote source project", projectRef))
+	}
+
 	gitSource, _, _, err := h.getUserGitSource(ctx, p.RemoteSourceID, curUserID)
 	if err != nil {
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get remote source %q", p.RemoteSourceID))
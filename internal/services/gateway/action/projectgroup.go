@@ -37,11 +37,11 @@ func (h *ActionHandler) GetProjectGroup(ctx context.Context, projectGroupRef str
 		return projectGroup, nil
 	}
 
-	isMember, err := h.IsAuthUserMember(ctx, projectGroup.OwnerType, projectGroup.OwnerID)
+	isViewer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, projectGroup.ID, cstypes.MemberRoleViewer)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isMember {
+	if !isViewer {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
@@ -93,11 +93,11 @@ func (h *ActionHandler) CreateProjectGroup(ctx context.Context, req *CreateProje
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get parent project group %q", req.ParentRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, pg.OwnerType, pg.OwnerID)
+	isAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, pg.ID, cstypes.MemberRoleAdmin)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isAdmin {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
@@ -123,6 +123,8 @@ func (h *ActionHandler) CreateProjectGroup(ctx context.Context, req *CreateProje
 	}
 	h.log.Info().Msgf("projectGroup %s created, ID: %s", rp.Name, rp.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectGroupCreate, targetKind: cstypes.ObjectKindProjectGroup, targetID: rp.ID, targetName: rp.Path, scopeKind: cstypes.ObjectKindProjectGroup, scopeID: rp.ID})
+
 	return rp, nil
 }
 
@@ -139,14 +141,25 @@ func (h *ActionHandler) UpdateProjectGroup(ctx context.Context, projectGroupRef
 		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project group %q", projectGroupRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, pg.OwnerType, pg.OwnerID)
+	isAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, pg.ID, cstypes.MemberRoleAdmin)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to determine ownership")
+		return nil, errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isAdmin {
 		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
+	// moving to another project group requires the admin role on it
+	if req.ParentRef != nil {
+		isParentAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, *req.ParentRef, cstypes.MemberRoleAdmin)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine permissions")
+		}
+		if !isParentAdmin {
+			return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+		}
+	}
+
 	if req.Name != nil {
 		pg.Name = *req.Name
 	}
@@ -170,6 +183,8 @@ func (h *ActionHandler) UpdateProjectGroup(ctx context.Context, projectGroupRef
 	}
 	h.log.Info().Msgf("project group %q updated, ID: %s", pg.Name, pg.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectGroupUpdate, targetKind: cstypes.ObjectKindProjectGroup, targetID: rp.ID, targetName: rp.Path, scopeKind: cstypes.ObjectKindProjectGroup, scopeID: rp.ID})
+
 	return rp, nil
 }
 
@@ -179,16 +194,18 @@ func (h *ActionHandler) DeleteProjectGroup(ctx context.Context, projectRef strin
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectRef))
 	}
 
-	isProjectOwner, err := h.IsAuthUserProjectOwner(ctx, p.OwnerType, p.OwnerID)
+	isAdmin, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProjectGroup, p.ID, cstypes.MemberRoleAdmin)
 	if err != nil {
-		return errors.Wrapf(err, "failed to determine ownership")
+		return errors.Wrapf(err, "failed to determine permissions")
 	}
-	if !isProjectOwner {
+	if !isAdmin {
 		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
 	}
 
 	if _, err = h.configstoreClient.DeleteProjectGroup(ctx, projectRef); err != nil {
 		return APIErrorFromRemoteError(err)
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectGroupDelete, targetKind: cstypes.ObjectKindProjectGroup, targetID: p.ID, targetName: p.Path, scopeKind: p.Parent.Kind, scopeID: p.Parent.ID})
 	return nil
 }
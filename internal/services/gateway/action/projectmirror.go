@@ -0,0 +1,294 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/gitsources/gitmirror"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/types"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	cstypes "agola.io/agola/services/configstore/types"
+)
+
+const (
+	// projectMirrorClaimDuration is the max time a project mirror poll could
+	// take before being claimed again by another poller
+	projectMirrorClaimDuration = 5 * time.Minute
+)
+
+func newProjectMirrorGitSource(ctx context.Context, project *cstypes.Project, projectMirror *cstypes.ProjectMirror) (*gitmirror.Client, error) {
+	c, err := gitmirror.New(ctx, gitmirror.Opts{
+		URL:                 projectMirror.URL,
+		SSHPrivKey:          project.SSHPrivateKey,
+		SSHHostKey:          projectMirror.SSHHostKey,
+		SkipSSHHostKeyCheck: project.SkipSSHHostKeyCheck,
+	})
+	return c, errors.WithStack(err)
+}
+
+func (h *ActionHandler) createMirrorProject(ctx context.Context, req *CreateProjectRequest, parentRef string) (*csapitypes.Project, error) {
+	h.log.Info().Msg("generating ssh key pairs")
+	privateKey, _, err := util.GenSSHKeyPair(4096)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate ssh key pair")
+	}
+
+	creq := &csapitypes.CreateUpdateProjectRequest{
+		Name: req.Name,
+		Parent: cstypes.Parent{
+			Kind: cstypes.ObjectKindProjectGroup,
+			ID:   parentRef,
+		},
+		Visibility:                  req.Visibility,
+		RemoteRepositoryConfigType:  cstypes.RemoteRepositoryConfigTypeMirror,
+		SSHPrivateKey:               string(privateKey),
+		SkipSSHHostKeyCheck:         req.SkipSSHHostKeyCheck,
+		PassVarsToForkedPR:          req.PassVarsToForkedPR,
+		MembersCanPerformRunActions: req.MembersCanPerformRunActions,
+		MirrorURL:                   req.MirrorURL,
+		MirrorSSHHostKey:            req.MirrorSSHHostKey,
+		MirrorBranches:              req.MirrorBranches,
+		MirrorTags:                  req.MirrorTags,
+	}
+
+	h.log.Info().Msg("creating project")
+	rp, _, err := h.configstoreClient.CreateProject(ctx, creq)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to create project"))
+	}
+	h.log.Info().Msgf("project %s created, ID: %s", rp.Name, rp.ID)
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionProjectCreate, targetKind: cstypes.ObjectKindProject, targetID: rp.ID, targetName: rp.Path, scopeKind: cstypes.ObjectKindProject, scopeID: rp.ID})
+
+	return rp, nil
+}
+
+type ProjectMirrorResponse struct {
+	ProjectMirror *cstypes.ProjectMirror
+	// SSHPublicKey is the project public key that must be authorized on the
+	// git server to access the repository
+	SSHPublicKey string
+}
+
+func (h *ActionHandler) GetProjectMirror(ctx context.Context, projectRef string) (*ProjectMirrorResponse, error) {
+	project, err := h.GetProject(ctx, projectRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if project.RemoteRepositoryConfigType != cstypes.RemoteRepositoryConfigTypeMirror {
+		return nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("project %q isn't a mirror project", projectRef), serrors.ProjectMirrorDoesNotExist())
+	}
+
+	projectMirror, _, err := h.configstoreClient.GetProjectMirror(ctx, project.ID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q mirror", projectRef))
+	}
+
+	publicKey, err := util.ExtractPublicKey([]byte(project.SSHPrivateKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to extract project public key")
+	}
+
+	return &ProjectMirrorResponse{
+		ProjectMirror: projectMirror,
+		SSHPublicKey:  string(publicKey),
+	}, nil
+}
+
+// ProcessNextProjectMirror claims and polls the next project mirror to poll.
+// It returns false if there was no project mirror to poll.
+func (h *ActionHandler) ProcessNextProjectMirror(ctx context.Context, pollInterval time.Duration) (bool, error) {
+	projectMirror, _, err := h.configstoreClient.ClaimProjectMirror(ctx, &csapitypes.ClaimProjectMirrorRequest{ClaimDuration: projectMirrorClaimDuration})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to claim project mirror")
+	}
+	if projectMirror == nil {
+		return false, nil
+	}
+
+	req := &csapitypes.UpdateProjectMirrorPollResultRequest{
+		PollCount:    projectMirror.PollCount,
+		NextPollTime: time.Now().Add(pollInterval),
+	}
+
+	refs, perr := h.pollProjectMirror(ctx, projectMirror)
+	if perr != nil {
+		h.log.Info().Err(perr).Str("projectID", projectMirror.ProjectID).Msg("failed to poll project mirror")
+		req.PollError = perr.Error()
+	}
+	req.Refs = refs
+
+	if _, _, err := h.configstoreClient.UpdateProjectMirrorPollResult(ctx, projectMirror.ID, req); err != nil {
+		return true, errors.Wrapf(err, "failed to update project mirror %q poll result", projectMirror.ID)
+	}
+
+	return true, nil
+}
+
+// pollProjectMirror lists the project mirror repository refs and creates the
+// runs for the new or updated refs matching the mirror filters. It returns
+// the refs to save: the refs whose runs couldn't be created are reported with
+// their previous commit so they'll be retried at the next poll.
+func (h *ActionHandler) pollProjectMirror(ctx context.Context, projectMirror *cstypes.ProjectMirror) (map[string]string, error) {
+	// bound the poll, and so its git commands, so a stuck git server won't
+	// keep it running beyond its claim
+	ctx, cancel := context.WithTimeout(ctx, projectMirrorClaimDuration)
+	defer cancel()
+
+	project, _, err := h.configstoreClient.GetProject(ctx, projectMirror.ProjectID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", projectMirror.ProjectID))
+	}
+
+	gitSource, err := newProjectMirrorGitSource(ctx, project.Project, projectMirror)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create gitsource client")
+	}
+	defer func() { _ = gitSource.Close() }()
+
+	refs, err := gitSource.ListRefs(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// first poll, just record the current refs
+	if projectMirror.Refs == nil {
+		return refs, nil
+	}
+
+	refNames := make([]string, 0, len(refs))
+	for ref := range refs {
+		refNames = append(refNames, ref)
+	}
+	sort.Strings(refNames)
+
+	var errs []string
+	for _, ref := range refNames {
+		commitSHA := refs[ref]
+		prevCommitSHA, ok := projectMirror.Refs[ref]
+		if ok && prevCommitSHA == commitSHA {
+			continue
+		}
+		if !matchProjectMirrorRef(gitSource, projectMirror, ref) {
+			continue
+		}
+
+		if err := h.createProjectMirrorRuns(ctx, project.Project, projectMirror, gitSource, ref, commitSHA); err != nil {
+			h.log.Info().Err(err).Str("projectID", project.ID).Msgf("failed to create runs for ref %q", ref)
+			errs = append(errs, fmt.Sprintf("ref %s: %s", ref, err))
+
+			if ok {
+				refs[ref] = prevCommitSHA
+			} else {
+				delete(refs, ref)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return refs, errors.New(strings.Join(errs, "; "))
+	}
+
+	return refs, nil
+}
+
+// matchProjectMirrorRef reports if runs should be created for the provided ref
+func matchProjectMirrorRef(gitSource gitsource.GitSource, projectMirror *cstypes.ProjectMirror, ref string) bool {
+	refType, name, err := gitSource.RefType(ref)
+	if err != nil {
+		return false
+	}
+
+	var patterns []string
+	switch refType {
+	case gitsource.RefTypeBranch:
+		// all the branches when no filter is defined
+		if len(projectMirror.Branches) == 0 {
+			return true
+		}
+		patterns = projectMirror.Branches
+	case gitsource.RefTypeTag:
+		patterns = projectMirror.Tags
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *ActionHandler) createProjectMirrorRuns(ctx context.Context, project *cstypes.Project, projectMirror *cstypes.ProjectMirror, gitSource *gitmirror.Client, ref, commitSHA string) error {
+	gitRefType, name, err := gitSource.RefType(ref)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	commit, err := gitSource.GetCommit(project.RepositoryPath, commitSHA)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get commit information for commit sha %q", commitSHA)
+	}
+
+	var refType types.RunRefType
+	var message, branch, tag string
+	switch gitRefType {
+	case gitsource.RefTypeBranch:
+		refType = types.RunRefTypeBranch
+		branch = name
+		message = commit.Message
+	case gitsource.RefTypeTag:
+		refType = types.RunRefTypeTag
+		tag = name
+		message = fmt.Sprintf("Tag %s", tag)
+	default:
+		return errors.Errorf("unsupported ref %q", ref)
+	}
+
+	req := &CreateRunRequest{
+		RunType:            types.RunTypeProject,
+		RefType:            refType,
+		RunCreationTrigger: types.RunCreationTriggerTypePoll,
+
+		Project:             project,
+		RepoPath:            project.RepositoryPath,
+		GitSource:           gitSource,
+		CommitSHA:           commit.SHA,
+		Message:             message,
+		Branch:              branch,
+		Tag:                 tag,
+		Ref:                 ref,
+		SSHPrivKey:          project.SSHPrivateKey,
+		SSHHostKey:          projectMirror.SSHHostKey,
+		SkipSSHHostKeyCheck: project.SkipSSHHostKeyCheck,
+		CloneURL:            projectMirror.URL,
+	}
+
+	_, err = h.CreateRuns(ctx, req)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,446 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/common"
+	"agola.io/agola/internal/services/types"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/configstore/client"
+	cstypes "agola.io/agola/services/configstore/types"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+const (
+	// receivedWebhookClaimDuration is the max time a received webhook
+	// processing could take before being claimed again by another worker
+	receivedWebhookClaimDuration = 5 * time.Minute
+
+	receivedWebhookMaxAttempts = 5
+
+	receivedWebhookRetryInterval    = 10 * time.Second
+	receivedWebhookMaxRetryInterval = 10 * time.Minute
+
+	pullRequestRunsLimit = 100
+)
+
+// receivedWebhookSkippedHeaders are the request headers that aren't saved
+// with the received webhook since they aren't needed to parse it
+var receivedWebhookSkippedHeaders = []string{"Authorization", "Cookie"}
+
+// ReceiveWebhook queues a webhook received from a git source for processing.
+// Only webhooks of existing remote source projects are queued.
+// The webhook signature or token is verified before queuing it, so
+// unauthenticated webhooks aren't saved. The other checks requiring the git
+// source api are done when processing it.
+// Webhooks redelivered by the git source are deduplicated using their delivery
+// id. The webhook url token, if provided, isn't saved as is but only its hash
+// is saved in the received webhook headers.
+func (h *ActionHandler) ReceiveWebhook(ctx context.Context, projectID string, header http.Header, body []byte, webhookToken string) (*cstypes.ReceivedWebhook, error) {
+	csProject, _, err := h.configstoreClient.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %s", projectID))
+	}
+	project := csProject.Project
+	if project.RemoteRepositoryConfigType != cstypes.RemoteRepositoryConfigTypeRemoteSource {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("project %s isn't a remote source project", projectID))
+	}
+
+	header = header.Clone()
+	for _, k := range receivedWebhookSkippedHeaders {
+		header.Del(k)
+	}
+	header.Del(gitsource.WebhookTokenHashHeader)
+	if webhookToken != "" {
+		header.Set(gitsource.WebhookTokenHashHeader, gitsource.WebhookTokenHash(webhookToken))
+	}
+
+	rs, _, err := h.configstoreClient.GetRemoteSource(ctx, csProject.RemoteSourceID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get remote source %q", csProject.RemoteSourceID))
+	}
+	if err := common.VerifyWebhook(rs, header, body, project.WebhookSecret); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrUnauthorized, err, util.WithAPIErrorMsg("failed to verify webhook"))
+	}
+
+	req := &csapitypes.CreateReceivedWebhookRequest{
+		DeliveryID: gitsource.WebhookDeliveryID(header, body),
+		Event:      gitsource.WebhookEvent(header),
+		Header:     header,
+		Body:       body,
+	}
+	receivedWebhook, _, err := h.configstoreClient.CreateReceivedWebhook(ctx, project.ID, req)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to save webhook for project %s", projectID))
+	}
+
+	return receivedWebhook, nil
+}
+
+type receivedWebhookResult struct {
+	Outcome        cstypes.ReceivedWebhookOutcome
+	OutcomeMessage string
+	RunNumbers     []uint64
+}
+
+// ProcessNextReceivedWebhook claims and processes the next received webhook.
+// It returns false if there was no received webhook to process.
+func (h *ActionHandler) ProcessNextReceivedWebhook(ctx context.Context) (bool, error) {
+	receivedWebhook, _, err := h.configstoreClient.ClaimReceivedWebhook(ctx, &csapitypes.ClaimReceivedWebhookRequest{ClaimDuration: receivedWebhookClaimDuration})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to claim received webhook")
+	}
+	if receivedWebhook == nil {
+		return false, nil
+	}
+
+	req := &csapitypes.UpdateReceivedWebhookResultRequest{
+		Attempts: receivedWebhook.Attempts,
+	}
+
+	res, perr := h.processReceivedWebhook(ctx, receivedWebhook)
+	switch {
+	case perr == nil:
+		req.Status = cstypes.ReceivedWebhookStatusProcessed
+		req.Outcome = res.Outcome
+		req.OutcomeMessage = res.OutcomeMessage
+		req.RunNumbers = res.RunNumbers
+	case util.APIErrorIs(perr, util.ErrBadRequest), util.APIErrorIs(perr, util.ErrNotExist), receivedWebhook.Attempts >= receivedWebhookMaxAttempts:
+		// don't retry webhooks that will never be processed (bad signature,
+		// removed project etc...) or that reached the max attempts
+		h.log.Warn().Err(perr).Str("receivedWebhookID", receivedWebhook.ID).Msg("failed to process received webhook")
+		req.Status = cstypes.ReceivedWebhookStatusFailed
+		req.OutcomeMessage = perr.Error()
+	default:
+		h.log.Info().Err(perr).Str("receivedWebhookID", receivedWebhook.ID).Msgf("failed to process received webhook, will retry")
+		req.Status = cstypes.ReceivedWebhookStatusPending
+		req.NextAttemptTime = time.Now().Add(receivedWebhookRetryDelay(receivedWebhook.Attempts))
+		req.OutcomeMessage = perr.Error()
+	}
+
+	if _, _, err := h.configstoreClient.UpdateReceivedWebhookResult(ctx, receivedWebhook.ID, req); err != nil {
+		return true, errors.Wrapf(err, "failed to update received webhook %q result", receivedWebhook.ID)
+	}
+
+	return true, nil
+}
+
+// receivedWebhookRetryDelay returns the delay before retrying a received
+// webhook processing. It doubles at every attempt.
+func receivedWebhookRetryDelay(attempts int) time.Duration {
+	delay := receivedWebhookRetryInterval
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= receivedWebhookMaxRetryInterval {
+			return receivedWebhookMaxRetryInterval
+		}
+	}
+
+	return delay
+}
+
+func (h *ActionHandler) processReceivedWebhook(ctx context.Context, receivedWebhook *cstypes.ReceivedWebhook) (*receivedWebhookResult, error) {
+	r, err := http.NewRequestWithContext(ctx, "POST", "/webhooks", bytes.NewReader(receivedWebhook.Body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	r.Header = http.Header(receivedWebhook.Header)
+
+	csProject, _, err := h.configstoreClient.GetProject(ctx, receivedWebhook.ProjectID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %s", receivedWebhook.ProjectID))
+	}
+	project := csProject.Project
+
+	user, _, err := h.configstoreClient.GetUserByLinkedAccount(ctx, project.LinkedAccountID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user by linked account %q", project.LinkedAccountID))
+	}
+	linkedAccounts, _, err := h.configstoreClient.GetUserLinkedAccounts(ctx, user.ID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user %q linked accounts", user.ID))
+	}
+
+	var la *cstypes.LinkedAccount
+	for _, v := range linkedAccounts {
+		if v.ID == project.LinkedAccountID {
+			la = v
+			break
+		}
+	}
+
+	if la == nil {
+		return nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("linked account %q for user %q doesn't exist", project.LinkedAccountID, user.Name))
+	}
+
+	rs, _, err := h.configstoreClient.GetRemoteSource(ctx, la.RemoteSourceID)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get remote source %q", la.RemoteSourceID))
+	}
+
+	gitSource, err := h.GetGitSource(ctx, rs, user.Name, la)
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to create git source client"))
+	}
+
+	sshPrivKey := project.SSHPrivateKey
+	sshHostKey := rs.SSHHostKey
+	// use remotesource skipSSHHostKeyCheck config and override with project config if set to true there
+	skipSSHHostKeyCheck := rs.SkipSSHHostKeyCheck
+	if project.SkipSSHHostKeyCheck {
+		skipSSHHostKeyCheck = project.SkipSSHHostKeyCheck
+	}
+
+	webhookData, err := gitSource.ParseWebhook(r, project.WebhookSecret)
+	if err != nil {
+		// the git source api calls done when parsing the webhook could fail
+		// for temporary errors, retry them
+		if gitsource.IsTemporaryError(err) {
+			return nil, util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to parse webhook"))
+		}
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to parse webhook"))
+	}
+	// skip nil webhook data
+	if webhookData == nil {
+		return &receivedWebhookResult{
+			Outcome:        cstypes.ReceivedWebhookOutcomeSkipped,
+			OutcomeMessage: fmt.Sprintf("event %q ignored", receivedWebhook.Event),
+		}, nil
+	}
+
+	if webhookData.Event == types.WebhookEventPullRequest && webhookData.PullRequestAction.IsClosed() {
+		return h.stopPullRequestRuns(ctx, project, webhookData)
+	}
+
+	cloneURL := webhookData.SSHURL
+
+	req := &CreateRunRequest{
+		RunType:            types.RunTypeProject,
+		RefType:            common.WebHookEventToRunRefType(webhookData.Event),
+		RunCreationTrigger: types.RunCreationTriggerTypeWebhook,
+
+		Project:        project,
+		User:           nil,
+		RepoPath:       webhookData.Repo.Path,
+		GitSource:      gitSource,
+		CommitSHA:      webhookData.CommitSHA,
+		Message:        webhookData.Message,
+		Branch:         webhookData.Branch,
+		Tag:            webhookData.Tag,
+		PullRequestID:  webhookData.PullRequestID,
+		PRFromSameRepo: webhookData.PRFromSameRepo,
+		Ref:            webhookData.Ref,
+
+		PullRequestLabels:       webhookData.PullRequestLabels,
+		PullRequestTargetBranch: webhookData.PullRequestTargetBranch,
+		PullRequestDraft:        webhookData.PullRequestDraft,
+		PullRequestLabeled:      webhookData.PullRequestAction == types.WebhookPullRequestActionLabeled,
+
+		SSHPrivKey:          sshPrivKey,
+		SSHHostKey:          sshHostKey,
+		SkipSSHHostKeyCheck: skipSSHHostKeyCheck,
+		CloneURL:            cloneURL,
+
+		WebhookEvent:  string(webhookData.Event),
+		WebhookSender: webhookData.Sender,
+		WebhookAuthor: webhookData.Author,
+
+		CommitLink:      webhookData.CommitLink,
+		BranchLink:      webhookData.BranchLink,
+		TagLink:         webhookData.TagLink,
+		PullRequestLink: webhookData.PullRequestLink,
+		CompareLink:     webhookData.CompareLink,
+	}
+	runs, err := h.CreateRuns(ctx, req)
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to create run"))
+	}
+
+	return createRunsResult(webhookData, runs), nil
+}
+
+// stopPullRequestRuns cancels the queued runs and stops the running runs of a
+// closed or merged pull request and of the other pull requests closed with it.
+func (h *ActionHandler) stopPullRequestRuns(ctx context.Context, project *cstypes.Project, webhookData *types.WebhookData) (*receivedWebhookResult, error) {
+	prIDs := webhookData.ClosedPullRequestIDs
+	if len(prIDs) == 0 {
+		prIDs = []string{webhookData.PullRequestID}
+	}
+	runGroups := make([]string, 0, len(prIDs))
+	for _, prID := range prIDs {
+		runGroups = append(runGroups, common.GenRunGroup(common.GroupTypeProject, project.ID, common.GroupTypePullRequest, prID))
+	}
+	phaseFilter := []string{string(rstypes.RunPhaseQueued), string(rstypes.RunPhaseRunning)}
+
+	runNumbers := []uint64{}
+	var startRunSequence uint64
+	for {
+		runsResp, _, err := h.runserviceClient.GetRuns(ctx, phaseFilter, nil, runGroups, false, nil, startRunSequence, pullRequestRunsLimit, true)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get pull request %q runs", webhookData.PullRequestID))
+		}
+
+		for _, run := range runsResp.Runs {
+			rsreq := &rsapitypes.RunActionsRequest{
+				ActionType: rsapitypes.RunActionTypeStop,
+			}
+			if run.Phase == rstypes.RunPhaseQueued {
+				rsreq = &rsapitypes.RunActionsRequest{
+					ActionType: rsapitypes.RunActionTypeChangePhase,
+					Phase:      rstypes.RunPhaseCancelled,
+				}
+			}
+			if _, err := h.runserviceClient.RunActions(ctx, run.ID, rsreq); err != nil {
+				return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to stop run %q", run.ID))
+			}
+			runNumbers = append(runNumbers, run.Counter)
+		}
+
+		if len(runsResp.Runs) < pullRequestRunsLimit {
+			break
+		}
+		startRunSequence = runsResp.Runs[len(runsResp.Runs)-1].Sequence
+	}
+
+	if len(runNumbers) == 0 {
+		return &receivedWebhookResult{
+			Outcome:        cstypes.ReceivedWebhookOutcomeSkipped,
+			OutcomeMessage: fmt.Sprintf("pull request %s, no runs to stop", webhookData.PullRequestAction),
+		}, nil
+	}
+
+	return &receivedWebhookResult{
+		Outcome:        cstypes.ReceivedWebhookOutcomeRunsStopped,
+		OutcomeMessage: fmt.Sprintf("pull request %s, stopped runs %d", webhookData.PullRequestAction, len(runNumbers)),
+		RunNumbers:     runNumbers,
+	}, nil
+}
+
+func createRunsResult(webhookData *types.WebhookData, runs []*rstypes.Run) *receivedWebhookResult {
+	if len(runs) == 0 {
+		msg := "when didn't match"
+		if SkipRunMessage.MatchString(webhookData.Message) {
+			msg = "skip commit message"
+		}
+		return &receivedWebhookResult{
+			Outcome:        cstypes.ReceivedWebhookOutcomeSkipped,
+			OutcomeMessage: msg,
+		}
+	}
+
+	runNumbers := make([]uint64, len(runs))
+	for i, run := range runs {
+		runNumbers[i] = run.Counter
+	}
+
+	if len(runs) == 1 && runs[0].Name == rstypes.RunGenericSetupErrorName {
+		return &receivedWebhookResult{
+			Outcome:        cstypes.ReceivedWebhookOutcomeSetupError,
+			OutcomeMessage: fmt.Sprintf("setup error, see run %d", runs[0].Counter),
+			RunNumbers:     runNumbers,
+		}
+	}
+
+	return &receivedWebhookResult{
+		Outcome:        cstypes.ReceivedWebhookOutcomeRunsCreated,
+		OutcomeMessage: fmt.Sprintf("created runs %d", len(runs)),
+		RunNumbers:     runNumbers,
+	}
+}
+
+type GetProjectReceivedWebhooksRequest struct {
+	ProjectRef   string
+	StatusFilter []string
+
+	Cursor string
+
+	Limit         int
+	SortDirection SortDirection
+}
+
+type GetProjectReceivedWebhooksResponse struct {
+	ReceivedWebhooks []*cstypes.ReceivedWebhook
+	Cursor           string
+}
+
+func (h *ActionHandler) GetProjectReceivedWebhooks(ctx context.Context, req *GetProjectReceivedWebhooksRequest) (*GetProjectReceivedWebhooksResponse, error) {
+	project, _, err := h.configstoreClient.GetProject(ctx, req.ProjectRef)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+	isMaintainer, err := h.AuthUserHasRole(ctx, cstypes.ObjectKindProject, project.ID, cstypes.MemberRoleMaintainer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine permissions")
+	}
+	if !isMaintainer {
+		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	}
+
+	inCursor := &DeliveryCursor{}
+	sortDirection := req.SortDirection
+	statusFilter := req.StatusFilter
+	if req.Cursor != "" {
+		if err := UnmarshalCursor(req.Cursor, inCursor); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		sortDirection = inCursor.SortDirection
+		statusFilter = inCursor.DeliveryStatusFilter
+	}
+	if sortDirection == "" {
+		sortDirection = SortDirectionAsc
+	}
+
+	statuses := make([]cstypes.ReceivedWebhookStatus, len(statusFilter))
+	for i, s := range statusFilter {
+		statuses[i] = cstypes.ReceivedWebhookStatus(s)
+	}
+
+	receivedWebhooks, resp, err := h.configstoreClient.GetProjectReceivedWebhooks(ctx, project.ID, &client.GetProjectReceivedWebhooksOptions{ListOptions: &client.ListOptions{Limit: req.Limit, SortDirection: cstypes.SortDirection(sortDirection)}, StartSequence: inCursor.StartSequence, Statuses: statuses})
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	var outCursor string
+	if resp.HasMore && len(receivedWebhooks) > 0 {
+		lastReceivedWebhookSequence := receivedWebhooks[len(receivedWebhooks)-1].Sequence
+		outCursor, err = MarshalCursor(&DeliveryCursor{
+			StartSequence: lastReceivedWebhookSequence,
+			SortDirection: sortDirection,
+
+			DeliveryStatusFilter: statusFilter,
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	res := &GetProjectReceivedWebhooksResponse{
+		ReceivedWebhooks: receivedWebhooks,
+		Cursor:           outCursor,
+	}
+
+	return res, nil
+}
@@ -166,6 +166,8 @@ func (h *ActionHandler) CreateRemoteSource(ctx context.Context, req *CreateRemot
 	}
 	h.log.Info().Msgf("remotesource %s created, ID: %s", rs.Name, rs.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionRemoteSourceCreate, targetKind: cstypes.ObjectKindRemoteSource, targetID: rs.ID, targetName: rs.Name})
+
 	return rs, nil
 }
 
@@ -264,6 +266,8 @@ func (h *ActionHandler) UpdateRemoteSource(ctx context.Context, req *UpdateRemot
 	}
 	h.log.Info().Msgf("remotesource %s updated", rs.Name)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionRemoteSourceUpdate, targetKind: cstypes.ObjectKindRemoteSource, targetID: rs.ID, targetName: rs.Name})
+
 	return rs, nil
 }
 
@@ -275,5 +279,8 @@ func (h *ActionHandler) DeleteRemoteSource(ctx context.Context, rsRef string) er
 	if _, err := h.configstoreClient.DeleteRemoteSource(ctx, rsRef); err != nil {
 		return errors.Wrapf(err, "failed to delete remote source")
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionRemoteSourceDelete, targetKind: cstypes.ObjectKindRemoteSource, targetName: rsRef})
+
 	return nil
 }
@@ -0,0 +1,155 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	cstypes "agola.io/agola/services/configstore/types"
+)
+
+type GetRoleBindingsRequest struct {
+	ParentType cstypes.ObjectKind
+	ParentRef  string
+
+	Tree bool
+}
+
+func (h *ActionHandler) GetRoleBindings(ctx context.Context, req *GetRoleBindingsRequest) ([]*csapitypes.RoleBinding, error) {
+	isViewer, err := h.AuthUserHasRole(ctx, req.ParentType, req.ParentRef, cstypes.MemberRoleViewer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine permissions")
+	}
+	if !isViewer {
+		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	}
+
+	var roleBindings []*csapitypes.RoleBinding
+	switch req.ParentType {
+	case cstypes.ObjectKindProjectGroup:
+		roleBindings, _, err = h.configstoreClient.GetProjectGroupRoleBindings(ctx, req.ParentRef, req.Tree)
+	case cstypes.ObjectKindProject:
+		roleBindings, _, err = h.configstoreClient.GetProjectRoleBindings(ctx, req.ParentRef, req.Tree)
+	}
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	return roleBindings, nil
+}
+
+type SetRoleBindingRequest struct {
+	ParentType cstypes.ObjectKind
+	ParentRef  string
+
+	SubjectKind cstypes.ObjectKind
+	SubjectRef  string
+
+	Role cstypes.MemberRole
+}
+
+func (h *ActionHandler) SetRoleBinding(ctx context.Context, req *SetRoleBindingRequest) (*cstypes.RoleBinding, error) {
+	if !cstypes.IsValidMemberRole(req.Role) {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid role %q", req.Role), serrors.InvalidRole())
+	}
+
+	authUserRole, err := h.GetAuthUserRole(ctx, req.ParentType, req.ParentRef)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine permissions")
+	}
+	if !authUserRole.Includes(cstypes.MemberRoleAdmin) {
+		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	}
+	// users cannot grant roles higher than their own
+	if !authUserRole.Includes(req.Role) {
+		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsgf("user not authorized to assign role %q", req.Role))
+	}
+
+	var roleBinding *cstypes.RoleBinding
+	switch req.SubjectKind {
+	case cstypes.ObjectKindUser:
+		switch req.ParentType {
+		case cstypes.ObjectKindProjectGroup:
+			h.log.Info().Msg("setting project group user role binding")
+			roleBinding, _, err = h.configstoreClient.SetProjectGroupUserRoleBinding(ctx, req.ParentRef, req.SubjectRef, req.Role)
+		case cstypes.ObjectKindProject:
+			h.log.Info().Msg("setting project user role binding")
+			roleBinding, _, err = h.configstoreClient.SetProjectUserRoleBinding(ctx, req.ParentRef, req.SubjectRef, req.Role)
+		}
+	case cstypes.ObjectKindTeam:
+		switch req.ParentType {
+		case cstypes.ObjectKindProjectGroup:
+			h.log.Info().Msg("setting project group team role binding")
+			roleBinding, _, err = h.configstoreClient.SetProjectGroupTeamRoleBinding(ctx, req.ParentRef, req.SubjectRef, req.Role)
+		case cstypes.ObjectKindProject:
+			h.log.Info().Msg("setting project team role binding")
+			roleBinding, _, err = h.configstoreClient.SetProjectTeamRoleBinding(ctx, req.ParentRef, req.SubjectRef, req.Role)
+		}
+	default:
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid role binding subject kind %q", req.SubjectKind))
+	}
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to set role binding"))
+	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionRoleBindingSet, targetKind: req.SubjectKind, targetID: roleBinding.Subject.ID, targetName: req.SubjectRef, scopeKind: req.ParentType, scopeID: req.ParentRef, details: map[string]string{"role": string(req.Role)}})
+
+	return roleBinding, nil
+}
+
+func (h *ActionHandler) DeleteRoleBinding(ctx context.Context, parentType cstypes.ObjectKind, parentRef string, subjectKind cstypes.ObjectKind, subjectRef string) error {
+	isAdmin, err := h.AuthUserHasRole(ctx, parentType, parentRef, cstypes.MemberRoleAdmin)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine permissions")
+	}
+	if !isAdmin {
+		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	}
+
+	switch subjectKind {
+	case cstypes.ObjectKindUser:
+		switch parentType {
+		case cstypes.ObjectKindProjectGroup:
+			h.log.Info().Msg("deleting project group user role binding")
+			_, err = h.configstoreClient.DeleteProjectGroupUserRoleBinding(ctx, parentRef, subjectRef)
+		case cstypes.ObjectKindProject:
+			h.log.Info().Msg("deleting project user role binding")
+			_, err = h.configstoreClient.DeleteProjectUserRoleBinding(ctx, parentRef, subjectRef)
+		}
+	case cstypes.ObjectKindTeam:
+		switch parentType {
+		case cstypes.ObjectKindProjectGroup:
+			h.log.Info().Msg("deleting project group team role binding")
+			_, err = h.configstoreClient.DeleteProjectGroupTeamRoleBinding(ctx, parentRef, subjectRef)
+		case cstypes.ObjectKindProject:
+			h.log.Info().Msg("deleting project team role binding")
+			_, err = h.configstoreClient.DeleteProjectTeamRoleBinding(ctx, parentRef, subjectRef)
+		}
+	default:
+		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid role binding subject kind %q", subjectKind))
+	}
+	if err != nil {
+		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete role binding"))
+	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionRoleBindingDelete, targetKind: subjectKind, targetName: subjectRef, scopeKind: parentType, scopeID: parentRef})
+
+	return nil
+}
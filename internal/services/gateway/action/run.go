@@ -16,10 +16,11 @@ package action
 
 import (
 	"context"
-	"encoding/json"
 	"net/http"
 	"path"
 	"regexp"
+	"slices"
+	"time"
 
 	"github.com/sorintlab/errors"
 
@@ -27,9 +28,12 @@ import (
 	gitsource "agola.io/agola/internal/gitsources"
 	"agola.io/agola/internal/runconfig"
 	scommon "agola.io/agola/internal/services/common"
+	serrors "agola.io/agola/internal/services/errors"
 	"agola.io/agola/internal/services/gateway/common"
 	itypes "agola.io/agola/internal/services/types"
+	"agola.io/agola/internal/tasklog"
 	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
 	cstypes "agola.io/agola/services/configstore/types"
 	rsapitypes "agola.io/agola/services/runservice/api/types"
 	"agola.io/agola/services/runservice/client"
@@ -55,6 +59,8 @@ const (
 	AnnotationRunCreationTrigger = "run_creation_trigger"
 	AnnotationWebhookEvent       = "webhook_event"
 	AnnotationWebhookSender      = "webhook_sender"
+	AnnotationWebhookAuthor      = "webhook_author"
+	AnnotationTriggerUserID      = "trigger_user_id"
 
 	AnnotationCommitSHA   = "commit_sha"
 	AnnotationRef         = "ref"
@@ -68,6 +74,8 @@ const (
 	AnnotationTagLink         = "tag_link"
 	AnnotationPullRequestID   = "pull_request_id"
 	AnnotationPullRequestLink = "pull_request_link"
+
+	AnnotationConfigPath = "config_path"
 )
 
 var (
@@ -183,6 +191,10 @@ type GetLogsRequest struct {
 	Setup     bool
 	Step      int
 	Follow    bool
+
+	Format tasklog.Format
+	Start  uint64
+	Limit  uint64
 }
 
 func (h *ActionHandler) GetLogs(ctx context.Context, req *GetLogsRequest) (*http.Response, error) {
@@ -201,7 +213,7 @@ func (h *ActionHandler) GetLogs(ctx context.Context, req *GetLogsRequest) (*http
 		return nil, APIErrorFromRemoteError(err)
 	}
 
-	resp, err := h.runserviceClient.GetLogs(ctx, runResp.Run.ID, req.TaskID, req.Setup, req.Step, req.Follow)
+	resp, err := h.runserviceClient.GetLogs(ctx, runResp.Run.ID, req.TaskID, req.Setup, req.Step, req.Follow, &client.GetLogsOptions{Format: string(req.Format), Start: req.Start, Limit: req.Limit})
 	if err != nil {
 		return nil, APIErrorFromRemoteError(err)
 	}
@@ -209,6 +221,57 @@ func (h *ActionHandler) GetLogs(ctx context.Context, req *GetLogsRequest) (*http
 	return resp.Response, nil
 }
 
+type SearchRunLogsRequest struct {
+	GroupType scommon.GroupType
+	Ref       string
+	RunNumber uint64
+
+	TaskIDs    []string
+	Pattern    string
+	Regex      bool
+	IgnoreCase bool
+	Context    int
+	Limit      int
+}
+
+type SearchRunLogsResponse struct {
+	*rsapitypes.RunLogsSearchResponse
+	RunConfig *rstypes.RunConfig
+}
+
+func (h *ActionHandler) SearchRunLogs(ctx context.Context, req *SearchRunLogsRequest) (*SearchRunLogsResponse, error) {
+	canGetRun, groupID, err := h.CanAuthUserGetRun(ctx, req.GroupType, req.Ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine permissions")
+	}
+	if !canGetRun {
+		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+	}
+
+	group := scommon.GenBaseRunGroup(req.GroupType, groupID)
+
+	runResp, _, err := h.runserviceClient.GetRunByGroup(ctx, group, req.RunNumber, nil)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	searchResp, _, err := h.runserviceClient.SearchRunLogs(ctx, runResp.Run.ID, req.Pattern, &client.SearchRunLogsOptions{
+		TaskIDs:    req.TaskIDs,
+		Regex:      req.Regex,
+		IgnoreCase: req.IgnoreCase,
+		Context:    req.Context,
+		Limit:      req.Limit,
+	})
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	return &SearchRunLogsResponse{
+		RunLogsSearchResponse: searchResp,
+		RunConfig:             runResp.RunConfig,
+	}, nil
+}
+
 type DeleteLogsRequest struct {
 	GroupType scommon.GroupType
 	Ref       string
@@ -311,6 +374,8 @@ func (h *ActionHandler) RunAction(ctx context.Context, req *RunActionsRequest) (
 		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("wrong run action type %q", req.ActionType))
 	}
 
+	h.recordAuditEvent(ctx, newRunAuditEvent(cstypes.AuditActionRunAction, req.GroupType, groupID, runID, req.RunNumber, map[string]string{"action": string(req.ActionType)}))
+
 	return runResp, nil
 }
 
@@ -358,33 +423,41 @@ func (h *ActionHandler) RunTaskAction(ctx context.Context, req *RunTaskActionsRe
 		if !ok {
 			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("run %q doesn't have task %q", req.RunNumber, req.TaskID))
 		}
+		rct, ok := runResp.RunConfig.Tasks[req.TaskID]
+		if !ok {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("run %q doesn't have task %q", req.RunNumber, req.TaskID))
+		}
+
+		if err := h.checkRunTaskApprover(ctx, req.GroupType, groupID, runResp.Run, rct.ApprovalPolicy); err != nil {
+			return errors.WithStack(err)
+		}
 
-		approvers := []string{}
 		annotations := map[string]string{}
 		if rt.Annotations != nil {
 			annotations = rt.Annotations
 		}
-		approversAnnotation, ok := annotations[scommon.ApproversAnnotation]
-		if ok {
-			if err := json.Unmarshal([]byte(approversAnnotation), &approvers); err != nil {
-				return errors.Wrapf(err, "failed to unmarshal run task approvers annotation")
-			}
+		approvals, err := scommon.GetRunTaskApprovals(annotations)
+		if err != nil {
+			return errors.WithStack(err)
 		}
 
-		for _, approver := range approvers {
-			if approver == curUserID {
-				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("user %q alredy approved the task", approver))
+		now := time.Now()
+		for _, approval := range approvals {
+			// an user can approve again when its approval is expired
+			if approval.UserID == curUserID && !approval.IsExpired(rct.ApprovalPolicy, now) {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("user %q alredy approved the task", approval.UserID), serrors.RunTaskAlreadyApproved())
 			}
 		}
-		approvers = append(approvers, curUserID)
+		approvals = append(approvals, &scommon.RunTaskApproval{
+			UserID:       curUserID,
+			UserName:     common.CurrentUserName(ctx),
+			ApprovalTime: now,
+		})
 
-		approversj, err := json.Marshal(approvers)
-		if err != nil {
-			return errors.Wrapf(err, "failed to marshal run task approvers annotation")
+		if err := scommon.SetRunTaskApprovals(annotations, approvals); err != nil {
+			return errors.WithStack(err)
 		}
 
-		annotations[scommon.ApproversAnnotation] = string(approversj)
-
 		rsreq := &rsapitypes.RunTaskActionsRequest{
 			ActionType:              rsapitypes.RunTaskActionTypeSetAnnotations,
 			Annotations:             annotations,
@@ -399,25 +472,135 @@ func (h *ActionHandler) RunTaskAction(ctx context.Context, req *RunTaskActionsRe
 		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("wrong run task action type %q", req.ActionType))
 	}
 
+	h.recordAuditEvent(ctx, newRunAuditEvent(cstypes.AuditActionRunTaskAction, req.GroupType, groupID, runID, req.RunNumber, map[string]string{"action": string(req.ActionType), "task": req.TaskID}))
+
 	return nil
 }
 
+// checkRunTaskApprover checks that the authenticated user can approve a run
+// task with the provided approval policy
+func (h *ActionHandler) checkRunTaskApprover(ctx context.Context, groupType scommon.GroupType, groupID string, run *rstypes.Run, policy *rstypes.ApprovalPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	curUserID := common.CurrentUserID(ctx)
+	curUserName := common.CurrentUserName(ctx)
+
+	var project *csapitypes.Project
+	if groupType == scommon.GroupTypeProject {
+		var err error
+		project, _, err = h.configstoreClient.GetProject(ctx, groupID)
+		if err != nil {
+			return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %q", groupID))
+		}
+	}
+
+	if policy.ForbidSelfApproval {
+		isSelfApprover, err := h.isRunSelfApprover(ctx, run, project, curUserID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if isSelfApprover {
+			return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("the user that triggered the run or authored its commit cannot approve its tasks"), serrors.RunTaskSelfApproval())
+		}
+	}
+
+	if !policy.HasAllowedApprovers() {
+		return nil
+	}
+
+	for _, allowedUser := range policy.AllowedUsers {
+		if allowedUser == curUserID || allowedUser == curUserName {
+			return nil
+		}
+	}
+
+	// teams and roles are only defined for projects
+	if project != nil {
+		pl := util.PathList(project.Path)
+		if len(policy.AllowedTeams) > 0 && len(pl) > 1 && pl[0] == "org" {
+			orgName := pl[1]
+			for _, allowedTeam := range policy.AllowedTeams {
+				members, _, err := h.configstoreClient.GetOrgTeamMembers(ctx, orgName, allowedTeam)
+				if err != nil {
+					if util.RemoteErrorIs(err, util.ErrNotExist) {
+						continue
+					}
+					return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get team %q members", allowedTeam))
+				}
+				for _, member := range members {
+					if member.ID == curUserID {
+						return nil
+					}
+				}
+			}
+		}
+
+		if len(policy.AllowedRoles) > 0 {
+			userRole, err := h.GetAuthUserRole(ctx, cstypes.ObjectKindProject, project.ID)
+			if err != nil {
+				return errors.Wrapf(err, "failed to determine permissions")
+			}
+			for _, allowedRole := range policy.AllowedRoles {
+				if userRole.Includes(cstypes.MemberRole(allowedRole)) {
+					return nil
+				}
+			}
+		}
+	}
+
+	return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not allowed to approve the task"), serrors.RunTaskApproverNotAllowed())
+}
+
+// isRunSelfApprover reports whether the user is the user that triggered the
+// run or, using its linked accounts on the project remote source, the webhook
+// sender or the commit/pull request author.
+func (h *ActionHandler) isRunSelfApprover(ctx context.Context, run *rstypes.Run, project *csapitypes.Project, userID string) (bool, error) {
+	triggerUserID := run.Annotations[AnnotationTriggerUserID]
+	runRemoteUserNames := []string{run.Annotations[AnnotationWebhookSender], run.Annotations[AnnotationWebhookAuthor]}
+
+	var userRemoteUserNames []string
+	if project != nil && project.RemoteSourceID != "" {
+		linkedAccounts, _, err := h.configstoreClient.GetUserLinkedAccounts(ctx, userID)
+		if err != nil {
+			return false, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user %q linked accounts", userID))
+		}
+		for _, la := range linkedAccounts {
+			if la.RemoteSourceID == project.RemoteSourceID {
+				userRemoteUserNames = append(userRemoteUserNames, la.RemoteUserName)
+			}
+		}
+	}
+
+	return scommon.IsRunSelfApprover(userID, triggerUserID, userRemoteUserNames, runRemoteUserNames), nil
+}
+
 type CreateRunRequest struct {
 	RunType            itypes.RunType
 	RefType            itypes.RunRefType
 	RunCreationTrigger itypes.RunCreationTriggerType
 
-	Project             *cstypes.Project
-	User                *cstypes.User
-	RepoPath            string
-	GitSource           gitsource.GitSource
-	CommitSHA           string
-	Message             string
-	Branch              string
-	Tag                 string
-	Ref                 string
-	PullRequestID       string
-	PRFromSameRepo      bool
+	Project        *cstypes.Project
+	User           *cstypes.User
+	RepoPath       string
+	GitSource      gitsource.GitSource
+	CommitSHA      string
+	Message        string
+	Branch         string
+	Tag            string
+	Ref            string
+	PullRequestID  string
+	PRFromSameRepo bool
+
+	PullRequestLabels       []string
+	PullRequestTargetBranch string
+	PullRequestDraft        bool
+	// PullRequestLabeled reports that the runs are triggered by a pull request
+	// labels change. Only the runs with a pull request label when condition
+	// will be created.
+	PullRequestLabeled bool
+
 	SSHPrivKey          string
 	SSHHostKey          string
 	SkipSSHHostKeyCheck bool
@@ -425,6 +608,12 @@ type CreateRunRequest struct {
 
 	WebhookEvent  string
 	WebhookSender string
+	// WebhookAuthor is the remote user name of the commit or pull request
+	// author
+	WebhookAuthor string
+
+	// TriggerUserID is the id of the user that manually created the run
+	TriggerUserID string
 
 	CommitLink      string
 	BranchLink      string
@@ -440,14 +629,30 @@ type CreateRunRequest struct {
 	Variables       map[string]string
 }
 
-func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) error {
+// whenPullRequest returns the pull request data used to match the when
+// conditions or nil if the run isn't for a pull request.
+func (r *CreateRunRequest) whenPullRequest() *types.WhenPullRequest {
+	if r.RefType != itypes.RunRefTypePullRequest {
+		return nil
+	}
+
+	return &types.WhenPullRequest{
+		Labels:       r.PullRequestLabels,
+		TargetBranch: r.PullRequestTargetBranch,
+		Draft:        r.PullRequestDraft,
+	}
+}
+
+// CreateRuns creates the runs defined in the repository config file and returns
+// them.
+func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) ([]*rstypes.Run, error) {
 	setupErrors := []string{}
 
 	if req.CommitSHA == "" {
-		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty commit SHA"))
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty commit SHA"))
 	}
 	if req.Message == "" {
-		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty message"))
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty message"))
 	}
 
 	var baseGroupType scommon.GroupType
@@ -479,7 +684,7 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 
 	gitURL, err := util.ParseGitURL(req.CloneURL)
 	if err != nil {
-		return errors.Wrapf(err, "failed to parse clone url")
+		return nil, errors.Wrapf(err, "failed to parse clone url")
 	}
 	gitHost := gitURL.Hostname()
 	gitPort := gitURL.Port()
@@ -510,12 +715,13 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 	}
 
 	var variables map[string]string
+	var secretValues []string
 	if req.RunType == itypes.RunTypeProject {
 		if req.RefType != itypes.RunRefTypePullRequest || req.PRFromSameRepo || req.Project.PassVarsToForkedPR {
 			var err error
-			variables, err = h.genRunVariables(ctx, req)
+			variables, secretValues, err = h.genRunVariables(ctx, req)
 			if err != nil {
-				return errors.WithStack(err)
+				return nil, errors.WithStack(err)
 			}
 		}
 	} else {
@@ -528,6 +734,8 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 		AnnotationRunCreationTrigger: string(req.RunCreationTrigger),
 		AnnotationWebhookEvent:       req.WebhookEvent,
 		AnnotationWebhookSender:      req.WebhookSender,
+		AnnotationWebhookAuthor:      req.WebhookAuthor,
+		AnnotationTriggerUserID:      req.TriggerUserID,
 		AnnotationCommitSHA:          req.CommitSHA,
 		AnnotationRef:                req.Ref,
 		AnnotationMessage:            req.Message,
@@ -562,10 +770,12 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 
 	data, filename, err := h.fetchConfigFiles(ctx, req.GitSource, req.RepoPath, req.CommitSHA)
 	if err != nil {
-		return util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to fetch config file"))
+		return nil, util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to fetch config file"))
 	}
 	h.log.Debug().Msgf("data: %s", data)
 
+	annotations[AnnotationConfigPath] = path.Join(agolaDefaultConfigDir, filename)
+
 	var configFormat config.ConfigFormat
 	switch path.Ext(filename) {
 	case ".star":
@@ -586,6 +796,10 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 		Tag:           req.Tag,
 		PullRequestID: req.PullRequestID,
 		CommitSHA:     req.CommitSHA,
+
+		PullRequestLabels:       req.PullRequestLabels,
+		PullRequestTargetBranch: req.PullRequestTargetBranch,
+		PullRequestDraft:        req.PullRequestDraft,
 	}
 
 	config, err := config.ParseConfig([]byte(data), configFormat, configContext)
@@ -604,25 +818,34 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 			Annotations:       annotations,
 		}
 
-		if _, _, err := h.runserviceClient.CreateRun(ctx, createRunReq); err != nil {
+		runResp, _, err := h.runserviceClient.CreateRun(ctx, createRunReq)
+		if err != nil {
 			h.log.Err(err).Msg("failed to create run")
-			return APIErrorFromRemoteError(err)
+			return nil, APIErrorFromRemoteError(err)
 		}
-		return nil
+		return []*rstypes.Run{runResp.Run}, nil
 	}
 
+	runs := []*rstypes.Run{}
+
 	for _, run := range config.Runs {
 		if SkipRunMessage.MatchString(req.Message) {
 			h.log.Debug().Msg("skipping run since special commit message")
 			continue
 		}
 
-		if match := types.MatchWhen(run.When.ToWhen(), req.RefType, req.Branch, req.Tag, req.Ref); !match {
+		if req.PullRequestLabeled && (run.When == nil || run.When.PullRequestLabel == nil) {
+			h.log.Debug().Msg("skipping run since it doesn't have a pull request label condition")
+			continue
+		}
+
+		if match := types.MatchWhen(run.When.ToWhen(), req.RefType, req.Branch, req.Tag, req.Ref, req.whenPullRequest()); !match {
 			h.log.Debug().Msg("skipping run since when condition doesn't match")
 			continue
 		}
 
-		rcts := runconfig.GenRunConfigTasks(util.DefaultUUIDGenerator{}, config, run.Name, variables, req.RefType, req.Branch, req.Tag, req.Ref)
+		rcts := runconfig.GenRunConfigTasks(util.DefaultUUIDGenerator{}, config, run.Name, variables, req.RefType, req.Branch, req.Tag, req.Ref, req.whenPullRequest())
+		runconfig.SetRunConfigTasksSecretValues(rcts, secretValues)
 
 		createRunReq := &rsapitypes.RunCreateRequest{
 			RunConfigTasks:    rcts,
@@ -634,13 +857,15 @@ func (h *ActionHandler) CreateRuns(ctx context.Context, req *CreateRunRequest) e
 			CacheGroup:        cacheGroup,
 		}
 
-		if _, _, err := h.runserviceClient.CreateRun(ctx, createRunReq); err != nil {
+		runResp, _, err := h.runserviceClient.CreateRun(ctx, createRunReq)
+		if err != nil {
 			h.log.Err(err).Msg("failed to create run")
-			return APIErrorFromRemoteError(err)
+			return nil, APIErrorFromRemoteError(err)
 		}
+		runs = append(runs, runResp.Run)
 	}
 
-	return nil
+	return runs, nil
 }
 
 func (h *ActionHandler) fetchConfigFiles(ctx context.Context, gitSource gitsource.GitSource, repopath, commitSHA string) ([]byte, string, error) {
@@ -663,13 +888,14 @@ func (h *ActionHandler) fetchConfigFiles(ctx context.Context, gitSource gitsourc
 	return data, filename, nil
 }
 
-func (h *ActionHandler) genRunVariables(ctx context.Context, req *CreateRunRequest) (map[string]string, error) {
+func (h *ActionHandler) genRunVariables(ctx context.Context, req *CreateRunRequest) (map[string]string, []string, error) {
 	variables := map[string]string{}
+	secretValues := []string{}
 
 	// get project variables
 	pvars, _, err := h.configstoreClient.GetProjectVariables(ctx, req.Project.ID, true)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get project variables")
+		return nil, nil, errors.Wrapf(err, "failed to get project variables")
 	}
 
 	// remove overriden variables
@@ -678,13 +904,13 @@ func (h *ActionHandler) genRunVariables(ctx context.Context, req *CreateRunReque
 	// get project secrets
 	secrets, _, err := h.configstoreClient.GetProjectSecrets(ctx, req.Project.ID, true)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get project secrets")
+		return nil, nil, errors.Wrapf(err, "failed to get project secrets")
 	}
 	for _, pvar := range pvars {
 		// find the value match
 		var varval cstypes.VariableValue
 		for _, varval = range pvar.Values {
-			match := types.MatchWhen(varval.When, req.RefType, req.Branch, req.Tag, req.Ref)
+			match := types.MatchWhen(varval.When, req.RefType, req.Branch, req.Tag, req.Ref, req.whenPullRequest())
 			if !match {
 				continue
 			}
@@ -694,11 +920,14 @@ func (h *ActionHandler) genRunVariables(ctx context.Context, req *CreateRunReque
 				varValue, ok := secret.Data[varval.SecretVar]
 				if ok {
 					variables[pvar.Name] = varValue
+					secretValues = append(secretValues, varValue)
 				}
 			}
 			break
 		}
 	}
 
-	return variables, nil
+	slices.Sort(secretValues)
+
+	return variables, secretValues, nil
 }
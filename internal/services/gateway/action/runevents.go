@@ -0,0 +1,238 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	scommon "agola.io/agola/internal/services/common"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	cstypes "agola.io/agola/services/configstore/types"
+	rsclient "agola.io/agola/services/runservice/client"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+// runEventsPermissionsCacheInterval is the interval after which the
+// permissions of the authenticated user on a project are checked again
+const runEventsPermissionsCacheInterval = 1 * time.Minute
+
+type GetRunEventsRequest struct {
+	// AfterSequence is the sequence of the last received run event. If zero
+	// the stream starts from the next run event.
+	AfterSequence uint64
+
+	// Run events filters. When no filter is provided all the run events
+	// visible by the authenticated user are returned, otherwise only the run
+	// events matching at least one filter.
+	ProjectRefs []string
+	OrgRefs     []string
+	UserRefs    []string
+}
+
+type runEventsProject struct {
+	project   *csapitypes.Project
+	canGetRun bool
+	checkTime time.Time
+}
+
+type runEventsFilter struct {
+	projectIDs map[string]struct{}
+	orgIDs     map[string]struct{}
+	userIDs    map[string]struct{}
+
+	projects map[string]*runEventsProject
+}
+
+func (f *runEventsFilter) isEmpty() bool {
+	return len(f.projectIDs) == 0 && len(f.orgIDs) == 0 && len(f.userIDs) == 0
+}
+
+// RunEventsStream is a stream of the run events visible by the authenticated
+// user.
+type RunEventsStream struct {
+	h      *ActionHandler
+	filter *runEventsFilter
+	body   io.ReadCloser
+}
+
+// Read reads the run events calling f for every run event. It returns when
+// the stream ends, the request context is done or f returns an error.
+func (s *RunEventsStream) Read(ctx context.Context, f func(ev *rstypes.RunEvent) error) error {
+	return errors.WithStack(rsclient.ReadRunEvents(s.body, func(ev *rstypes.RunEvent) error {
+		ok, err := s.h.runEventMatches(ctx, s.filter, ev)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if !ok {
+			return nil
+		}
+
+		return f(ev)
+	}))
+}
+
+func (s *RunEventsStream) Close() error {
+	return errors.WithStack(s.body.Close())
+}
+
+// GetRunEvents checks the provided filters and opens a stream of the run events
+// visible by the authenticated user. The returned stream must be closed.
+func (h *ActionHandler) GetRunEvents(ctx context.Context, req *GetRunEventsRequest) (*RunEventsStream, error) {
+	filter, err := h.runEventsFilter(ctx, req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := h.runserviceClient.GetRunEvents(ctx, req.AfterSequence)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Errorf("http status code: %d", resp.StatusCode)
+	}
+
+	return &RunEventsStream{h: h, filter: filter, body: resp.Body}, nil
+}
+
+func (h *ActionHandler) runEventsFilter(ctx context.Context, req *GetRunEventsRequest) (*runEventsFilter, error) {
+	filter := &runEventsFilter{
+		projectIDs: map[string]struct{}{},
+		orgIDs:     map[string]struct{}{},
+		userIDs:    map[string]struct{}{},
+		projects:   map[string]*runEventsProject{},
+	}
+
+	for _, projectRef := range req.ProjectRefs {
+		canGetRun, projectID, err := h.CanAuthUserGetRun(ctx, scommon.GroupTypeProject, projectRef)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine permissions")
+		}
+		if !canGetRun {
+			return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+		}
+		filter.projectIDs[projectID] = struct{}{}
+	}
+
+	for _, orgRef := range req.OrgRefs {
+		org, _, err := h.configstoreClient.GetOrg(ctx, orgRef)
+		if err != nil {
+			return nil, APIErrorFromRemoteError(err)
+		}
+		if org.Visibility != cstypes.VisibilityPublic {
+			isOrgMember, err := h.IsAuthUserMember(ctx, cstypes.ObjectKindOrg, org.ID)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to determine permissions")
+			}
+			if !isOrgMember {
+				return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+			}
+		}
+		filter.orgIDs[org.ID] = struct{}{}
+	}
+
+	for _, userRef := range req.UserRefs {
+		canGetRun, userID, err := h.CanAuthUserGetRun(ctx, scommon.GroupTypeUser, userRef)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine permissions")
+		}
+		if !canGetRun {
+			return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authorized"))
+		}
+		filter.userIDs[userID] = struct{}{}
+	}
+
+	return filter, nil
+}
+
+func (h *ActionHandler) runEventMatches(ctx context.Context, filter *runEventsFilter, ev *rstypes.RunEvent) (bool, error) {
+	data, ok := ev.Data.(*rstypes.RunEventData)
+	if !ok {
+		return false, nil
+	}
+
+	switch scommon.GroupType(data.Annotations[AnnotationRunType]) {
+	case scommon.GroupTypeUser:
+		userID := data.Annotations[AnnotationUserID]
+		if !filter.isEmpty() {
+			if _, ok := filter.userIDs[userID]; !ok {
+				return false, nil
+			}
+		}
+
+		// user direct runs are private
+		return isAuthUser(ctx, userID), nil
+
+	case scommon.GroupTypeProject:
+		projectID := data.Annotations[AnnotationProjectID]
+
+		rp, err := h.runEventsProject(ctx, filter, projectID)
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		if rp.project == nil || !rp.canGetRun {
+			return false, nil
+		}
+
+		if filter.isEmpty() {
+			return true, nil
+		}
+		if _, ok := filter.projectIDs[projectID]; ok {
+			return true, nil
+		}
+		if rp.project.OwnerType == cstypes.ObjectKindOrg {
+			if _, ok := filter.orgIDs[rp.project.OwnerID]; ok {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// runEventsProject returns the run event project and the user permissions on
+// it, caching them for runEventsPermissionsCacheInterval.
+func (h *ActionHandler) runEventsProject(ctx context.Context, filter *runEventsFilter, projectID string) (*runEventsProject, error) {
+	if rp, ok := filter.projects[projectID]; ok && time.Since(rp.checkTime) < runEventsPermissionsCacheInterval {
+		return rp, nil
+	}
+
+	rp := &runEventsProject{checkTime: time.Now()}
+
+	p, _, err := h.configstoreClient.GetProject(ctx, projectID)
+	if err != nil {
+		if !util.RemoteErrorIs(err, util.ErrNotExist) {
+			return nil, APIErrorFromRemoteError(err)
+		}
+		// project removed
+	} else {
+		rp.project = p
+		rp.canGetRun, err = h.canAuthUserGetProjectRun(ctx, p)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	filter.projects[projectID] = rp
+
+	return rp, nil
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/sorintlab/errors"
+
+	scommon "agola.io/agola/internal/services/common"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/gateway/common"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	cstypes "agola.io/agola/services/configstore/types"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+)
+
+// ScopedExportEntry is an entry of a scoped export. A scoped export contains
+// the configstore objects followed by the runservice objects of the projects
+// exported by the configstore.
+type ScopedExportEntry struct {
+	Service string          `json:"service"`
+	Object  json.RawMessage `json:"object"`
+}
+
+type ScopedExportRequest struct {
+	// Kind is the kind of the exported object. It can be an organization or
+	// a project group.
+	Kind cstypes.ObjectKind
+	Ref  string
+}
+
+// ScopedExport exports an organization or a project group with their
+// configstore objects and the archived runs of their projects.
+func (h *ActionHandler) ScopedExport(ctx context.Context, req *ScopedExportRequest) (io.ReadCloser, error) {
+	if !common.IsUserAdmin(ctx) {
+		return nil, util.NewAPIError(util.ErrUnauthorized, util.WithAPIErrorMsg("user not admin"))
+	}
+
+	switch req.Kind {
+	case cstypes.ObjectKindOrg, cstypes.ObjectKindProjectGroup:
+	default:
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid export scope kind %q", req.Kind), serrors.InvalidExportScope())
+	}
+
+	csResp, err := h.configstoreClient.ScopedExport(ctx, req.Kind, req.Ref)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer csResp.Body.Close()
+
+		_ = pw.CloseWithError(h.writeScopedExport(ctx, csResp.Body, pw))
+	}()
+
+	return pr, nil
+}
+
+func (h *ActionHandler) writeScopedExport(ctx context.Context, csr io.Reader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := json.NewEncoder(bw)
+
+	var groups []string
+	err := copyScopedExportEntries(csr, e, ConfigstoreService, func(jobj json.RawMessage) error {
+		var obj struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+			ID         string          `json:"id"`
+		}
+		if err := json.Unmarshal(jobj, &obj); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if obj.ExportMeta.Kind == "Project" {
+			groups = append(groups, scommon.GenBaseRunGroup(scommon.GroupTypeProject, obj.ID))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(groups) > 0 {
+		rsResp, err := h.runserviceClient.ScopedExport(ctx, groups)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		defer rsResp.Body.Close()
+
+		if err := copyScopedExportEntries(rsResp.Body, e, RunserviceService, nil); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return errors.WithStack(bw.Flush())
+}
+
+func copyScopedExportEntries(r io.Reader, e *json.Encoder, service string, fn func(jobj json.RawMessage) error) error {
+	dec := json.NewDecoder(r)
+	for {
+		var jobj json.RawMessage
+
+		err := dec.Decode(&jobj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if fn != nil {
+			if err := fn(jobj); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		if err := e.Encode(&ScopedExportEntry{Service: service, Object: jobj}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+type ScopedImportRequest struct {
+	// ParentRef is the project group where an exported project group will be
+	// imported. It must be empty when importing an organization.
+	ParentRef string
+}
+
+// ScopedImport imports an organization or a project group exported by
+// ScopedExport. The configstore objects are imported first, then the runs of
+// the imported projects are moved to the new projects run groups.
+// An imported organization will be owned by the current user.
+// If the runs import fails the imported configstore objects are deleted, so
+// the import can be retried. Every service import is atomic, the runs import
+// doesn't leave imported runs or logs on failure.
+func (h *ActionHandler) ScopedImport(ctx context.Context, req *ScopedImportRequest, r io.Reader) error {
+	if !common.IsUserAdmin(ctx) {
+		return util.NewAPIError(util.ErrUnauthorized, util.WithAPIErrorMsg("user not admin"))
+	}
+
+	curUserID := common.CurrentUserID(ctx)
+
+	dec := json.NewDecoder(r)
+
+	var csRes *csapitypes.ScopedImportResponse
+	entry, err := pipeScopedExportEntries(dec, nil, ConfigstoreService, func(r io.Reader) error {
+		res, _, err := h.configstoreClient.ScopedImport(ctx, req.ParentRef, curUserID, r)
+		if err != nil {
+			return APIErrorFromRemoteError(err)
+		}
+		csRes = res
+
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if entry == nil {
+		return nil
+	}
+
+	rsReq := &rsapitypes.ScopedImportRequest{
+		GroupsRemap:      map[string]string{},
+		AnnotationsRemap: map[string]map[string]string{AnnotationProjectID: csRes.ProjectIDs},
+	}
+	for oldProjectID, projectID := range csRes.ProjectIDs {
+		rsReq.GroupsRemap[scommon.GenBaseRunGroup(scommon.GroupTypeProject, oldProjectID)] = scommon.GenBaseRunGroup(scommon.GroupTypeProject, projectID)
+	}
+
+	entry, err = pipeScopedExportEntries(dec, entry, RunserviceService, func(r io.Reader) error {
+		if _, err := h.runserviceClient.ScopedImport(ctx, rsReq, r); err != nil {
+			return APIErrorFromRemoteError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		// the request context could be already canceled
+		if _, derr := h.configstoreClient.DeleteScopedImport(context.WithoutCancel(ctx), csRes.Kind, csRes.ID); derr != nil {
+			h.log.Err(derr).Msgf("failed to delete imported %s %q", csRes.Kind, csRes.ID)
+		}
+		return errors.WithStack(err)
+	}
+	if entry != nil {
+		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("unexpected %q service entry after %q service entries", entry.Service, RunserviceService), serrors.InvalidExportData())
+	}
+
+	return nil
+}
+
+// pipeScopedExportEntries streams to importFn the objects of the consecutive
+// entries of the provided service starting from entry (if not nil). It returns
+// the first entry of another service.
+func pipeScopedExportEntries(dec *json.Decoder, entry *ScopedExportEntry, service string, importFn func(r io.Reader) error) (*ScopedExportEntry, error) {
+	pr, pw := io.Pipe()
+
+	errCh := make(chan error, 1)
+	go func() {
+		err := importFn(pr)
+		_ = pr.CloseWithError(err)
+		errCh <- err
+	}()
+
+	var next *ScopedExportEntry
+	var decodeErr, writeErr error
+	for {
+		if entry == nil {
+			entry = &ScopedExportEntry{}
+			if err := dec.Decode(entry); err != nil {
+				if !errors.Is(err, io.EOF) {
+					decodeErr = util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+				}
+				break
+			}
+		}
+
+		if entry.Service != service {
+			next = entry
+			break
+		}
+
+		if _, err := pw.Write(append(entry.Object, '\n')); err != nil {
+			writeErr = err
+			break
+		}
+
+		entry = nil
+	}
+
+	if decodeErr != nil {
+		_ = pw.CloseWithError(decodeErr)
+		<-errCh
+		return nil, decodeErr
+	}
+
+	_ = pw.Close()
+	if err := <-errCh; err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if writeErr != nil {
+		return nil, errors.WithStack(writeErr)
+	}
+
+	return next, nil
+}
@@ -104,6 +104,8 @@ func (h *ActionHandler) CreateSecret(ctx context.Context, req *CreateSecretReque
 	}
 	h.log.Info().Msgf("secret %s created, ID: %s", rs.Name, rs.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionSecretCreate, targetKind: cstypes.ObjectKindSecret, targetID: rs.ID, targetName: rs.Name, scopeKind: req.ParentType, scopeID: req.ParentRef})
+
 	return rs, nil
 }
 
@@ -158,6 +160,8 @@ func (h *ActionHandler) UpdateSecret(ctx context.Context, req *UpdateSecretReque
 	}
 	h.log.Info().Msgf("secret %s updated, ID: %s", rs.Name, rs.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionSecretUpdate, targetKind: cstypes.ObjectKindSecret, targetID: rs.ID, targetName: rs.Name, scopeKind: req.ParentType, scopeID: req.ParentRef})
+
 	return rs, nil
 }
 
@@ -181,5 +185,8 @@ func (h *ActionHandler) DeleteSecret(ctx context.Context, parentType cstypes.Obj
 	if err != nil {
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete secret"))
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionSecretDelete, targetKind: cstypes.ObjectKindSecret, targetName: name, scopeKind: parentType, scopeID: parentRef})
+
 	return nil
 }
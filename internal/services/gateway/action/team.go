@@ -0,0 +1,180 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	cstypes "agola.io/agola/services/configstore/types"
+)
+
+// checkOrgAdmin verifies that the authenticated user is an admin of the
+// organization and returns it
+func (h *ActionHandler) checkOrgAdmin(ctx context.Context, orgRef string) (*cstypes.Organization, error) {
+	org, _, err := h.configstoreClient.GetOrg(ctx, orgRef)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	if err := h.checkAuthUserOrgAdmin(ctx, org.ID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return org, nil
+}
+
+func (h *ActionHandler) GetOrgTeams(ctx context.Context, orgRef string) ([]*cstypes.Team, error) {
+	// GetOrg checks that the user can see the organization
+	if _, err := h.GetOrg(ctx, orgRef); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	teams, _, err := h.configstoreClient.GetOrgTeams(ctx, orgRef)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	return teams, nil
+}
+
+func (h *ActionHandler) GetOrgTeam(ctx context.Context, orgRef, teamRef string) (*cstypes.Team, error) {
+	if _, err := h.GetOrg(ctx, orgRef); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	team, _, err := h.configstoreClient.GetOrgTeam(ctx, orgRef, teamRef)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	return team, nil
+}
+
+type CreateOrgTeamRequest struct {
+	OrgRef string
+	Name   string
+}
+
+func (h *ActionHandler) CreateOrgTeam(ctx context.Context, req *CreateOrgTeamRequest) (*cstypes.Team, error) {
+	org, err := h.checkOrgAdmin(ctx, req.OrgRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	creq := &csapitypes.CreateTeamRequest{
+		Name: req.Name,
+	}
+
+	h.log.Info().Msgf("creating team")
+	team, _, err := h.configstoreClient.CreateOrgTeam(ctx, req.OrgRef, creq)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to create team"))
+	}
+	h.log.Info().Msgf("team %s created, ID: %s", team.Name, team.ID)
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionTeamCreate, targetKind: cstypes.ObjectKindTeam, targetID: team.ID, targetName: team.Name, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID})
+
+	return team, nil
+}
+
+type UpdateOrgTeamRequest struct {
+	OrgRef  string
+	TeamRef string
+	Name    string
+}
+
+func (h *ActionHandler) UpdateOrgTeam(ctx context.Context, req *UpdateOrgTeamRequest) (*cstypes.Team, error) {
+	org, err := h.checkOrgAdmin(ctx, req.OrgRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	creq := &csapitypes.UpdateTeamRequest{
+		Name: req.Name,
+	}
+
+	h.log.Info().Msgf("updating team")
+	team, _, err := h.configstoreClient.UpdateOrgTeam(ctx, req.OrgRef, req.TeamRef, creq)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to update team"))
+	}
+	h.log.Info().Msgf("team %s updated, ID: %s", team.Name, team.ID)
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionTeamUpdate, targetKind: cstypes.ObjectKindTeam, targetID: team.ID, targetName: team.Name, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID})
+
+	return team, nil
+}
+
+func (h *ActionHandler) DeleteOrgTeam(ctx context.Context, orgRef, teamRef string) error {
+	org, err := h.checkOrgAdmin(ctx, orgRef)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, err := h.configstoreClient.DeleteOrgTeam(ctx, orgRef, teamRef); err != nil {
+		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete team"))
+	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionTeamDelete, targetKind: cstypes.ObjectKindTeam, targetName: teamRef, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID})
+
+	return nil
+}
+
+func (h *ActionHandler) GetOrgTeamMembers(ctx context.Context, orgRef, teamRef string) ([]*cstypes.User, error) {
+	if _, err := h.GetOrg(ctx, orgRef); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	users, _, err := h.configstoreClient.GetOrgTeamMembers(ctx, orgRef, teamRef)
+	if err != nil {
+		return nil, APIErrorFromRemoteError(err)
+	}
+
+	return users, nil
+}
+
+func (h *ActionHandler) AddOrgTeamMember(ctx context.Context, orgRef, teamRef, userRef string) error {
+	org, err := h.checkOrgAdmin(ctx, orgRef)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, _, err := h.configstoreClient.AddOrgTeamMember(ctx, orgRef, teamRef, userRef); err != nil {
+		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to add team member"))
+	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionTeamMemberAdd, targetKind: cstypes.ObjectKindUser, targetName: userRef, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID, details: map[string]string{"team": teamRef}})
+
+	return nil
+}
+
+func (h *ActionHandler) RemoveOrgTeamMember(ctx context.Context, orgRef, teamRef, userRef string) error {
+	org, err := h.checkOrgAdmin(ctx, orgRef)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if _, err := h.configstoreClient.RemoveOrgTeamMember(ctx, orgRef, teamRef, userRef); err != nil {
+		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to remove team member"))
+	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionTeamMemberRemove, targetKind: cstypes.ObjectKindUser, targetName: userRef, scopeKind: cstypes.ObjectKindOrg, scopeID: org.ID, details: map[string]string{"team": teamRef}})
+
+	return nil
+}
@@ -36,6 +36,7 @@ import (
 	csapitypes "agola.io/agola/services/configstore/api/types"
 	"agola.io/agola/services/configstore/client"
 	cstypes "agola.io/agola/services/configstore/types"
+	rstypes "agola.io/agola/services/runservice/types"
 )
 
 const (
@@ -247,6 +248,8 @@ func (h *ActionHandler) CreateUser(ctx context.Context, req *CreateUserRequest)
 	}
 	h.log.Info().Msgf("user %s created, ID: %s", u.Name, u.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionUserCreate, targetKind: cstypes.ObjectKindUser, targetID: u.ID, targetName: u.Name})
+
 	return u, nil
 }
 
@@ -300,6 +303,8 @@ func (h *ActionHandler) CreateUserToken(ctx context.Context, req *CreateUserToke
 	}
 	h.log.Info().Msgf("token %q for user %q created", req.TokenName, userRef)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionUserTokenCreate, targetKind: cstypes.ObjectKindUser, targetID: user.ID, targetName: user.Name, details: map[string]string{"token": req.TokenName}})
+
 	return res.Token, nil
 }
 
@@ -611,7 +616,7 @@ func (h *ActionHandler) LoginUser(ctx context.Context, req *LoginUserRequest) (*
 
 	userAccessToken := la.UserAccessToken
 	if rs.AuthType == cstypes.RemoteSourceAuthTypePassword {
-		tokenSource, err := scommon.GetAccessTokenUserSource(rs, userAccessToken)
+		tokenSource, err := scommon.GetAccessTokenUserSource(rs, la.RemoteUserName, userAccessToken)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -1004,6 +1009,9 @@ func (h *ActionHandler) DeleteUser(ctx context.Context, userRef string) error {
 	if _, err := h.configstoreClient.DeleteUser(ctx, userRef); err != nil {
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete user"))
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionUserDelete, targetKind: cstypes.ObjectKindUser, targetName: userRef})
+
 	return nil
 }
 
@@ -1052,6 +1060,9 @@ func (h *ActionHandler) DeleteUserToken(ctx context.Context, userRef, tokenName
 	if _, err = h.configstoreClient.DeleteUserToken(ctx, userRef, tokenName); err != nil {
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete user token"))
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionUserTokenDelete, targetKind: cstypes.ObjectKindUser, targetID: user.ID, targetName: user.Name, details: map[string]string{"token": tokenName}})
+
 	return nil
 }
 
@@ -1068,9 +1079,9 @@ type UserCreateRunRequest struct {
 	Variables             map[string]string
 }
 
-func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunRequest) error {
+func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunRequest) ([]*rstypes.Run, error) {
 	if !common.IsUserLogged(ctx) {
-		return util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authenticated"))
+		return nil, util.NewAPIError(util.ErrForbidden, util.WithAPIErrorMsg("user not authenticated"))
 	}
 	curUserID := common.CurrentUserID(ctx)
 
@@ -1078,26 +1089,26 @@ func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunReq
 	for _, res := range req.PullRequestRefRegexes {
 		re, err := regexp.Compile(res)
 		if err != nil {
-			return errors.Wrapf(err, "wrong regular expression %q", res)
+			return nil, errors.Wrapf(err, "wrong regular expression %q", res)
 		}
 		prRefRegexes = append(prRefRegexes, re)
 	}
 
 	user, _, err := h.configstoreClient.GetUser(ctx, curUserID)
 	if err != nil {
-		return APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user %q", curUserID))
+		return nil, APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user %q", curUserID))
 	}
 
 	// Verify that the repo is owned by the user
 	repoParts := strings.Split(req.RepoPath, "/")
 	if req.RepoUUID == "" {
-		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty repo uuid"))
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty repo uuid"))
 	}
 	if len(repoParts) != 2 {
-		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("wrong repo path: %q", req.RepoPath))
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("wrong repo path: %q", req.RepoPath))
 	}
 	if repoParts[0] != user.ID {
-		return util.NewAPIError(util.ErrUnauthorized, util.WithAPIErrorMsgf("repo %q not owned", req.RepoPath))
+		return nil, util.NewAPIError(util.ErrUnauthorized, util.WithAPIErrorMsgf("repo %q not owned", req.RepoPath))
 	}
 
 	branch := req.Branch
@@ -1115,10 +1126,10 @@ func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunReq
 		set++
 	}
 	if set == 0 {
-		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("one of branch, tag or ref is required"))
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("one of branch, tag or ref is required"))
 	}
 	if set > 1 {
-		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("only one of branch, tag or ref can be provided"))
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("only one of branch, tag or ref can be provided"))
 	}
 
 	gitSource := agolagit.New(h.apiExposedURL+"/repos", prRefRegexes)
@@ -1135,7 +1146,7 @@ func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunReq
 
 	gitRefType, name, err := gitSource.RefType(ref)
 	if err != nil {
-		return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("failed to get refType for ref %q", ref))
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("failed to get refType for ref %q", ref))
 	}
 
 	var pullRequestID string
@@ -1148,7 +1159,7 @@ func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunReq
 	case gitsource.RefTypePullRequest:
 		pullRequestID = name
 	default:
-		return errors.Errorf("unsupported ref %q for manual run creation", ref)
+		return nil, errors.Errorf("unsupported ref %q for manual run creation", ref)
 	}
 
 	var refType types.RunRefType
@@ -1167,6 +1178,7 @@ func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunReq
 		RunType:            types.RunTypeUser,
 		RefType:            refType,
 		RunCreationTrigger: types.RunCreationTriggerTypeManual,
+		TriggerUserID:      user.ID,
 
 		Project:       nil,
 		User:          user,
@@ -1189,7 +1201,8 @@ func (h *ActionHandler) UserCreateRun(ctx context.Context, req *UserCreateRunReq
 		Variables:       req.Variables,
 	}
 
-	return h.CreateRuns(ctx, creq)
+	runs, err := h.CreateRuns(ctx, creq)
+	return runs, errors.WithStack(err)
 }
 
 func (h *ActionHandler) GetCurrentUserGitSource(ctx context.Context, remoteSourceRef string) (gitsource.GitSource, *cstypes.RemoteSource, *cstypes.LinkedAccount, error) {
@@ -131,6 +131,8 @@ func (h *ActionHandler) CreateVariable(ctx context.Context, req *CreateVariableR
 	}
 	h.log.Info().Msgf("variable %s created, ID: %s", rv.Name, rv.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionVariableCreate, targetKind: cstypes.ObjectKindVariable, targetID: rv.ID, targetName: rv.Name, scopeKind: req.ParentType, scopeID: req.ParentRef})
+
 	return rv, cssecrets, nil
 }
 
@@ -198,6 +200,8 @@ func (h *ActionHandler) UpdateVariable(ctx context.Context, req *UpdateVariableR
 	}
 	h.log.Info().Msgf("variable %s created, ID: %s", rv.Name, rv.ID)
 
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionVariableUpdate, targetKind: cstypes.ObjectKindVariable, targetID: rv.ID, targetName: rv.Name, scopeKind: req.ParentType, scopeID: req.ParentRef})
+
 	return rv, cssecrets, nil
 }
 
@@ -221,5 +225,8 @@ func (h *ActionHandler) DeleteVariable(ctx context.Context, parentType cstypes.O
 	if err != nil {
 		return APIErrorFromRemoteError(err, util.WithAPIErrorMsg("failed to delete variable"))
 	}
+
+	h.recordAuditEvent(ctx, &auditEvent{action: cstypes.AuditActionVariableDelete, targetKind: cstypes.ObjectKindVariable, targetName: name, scopeKind: parentType, scopeID: parentRef})
+
 	return nil
 }
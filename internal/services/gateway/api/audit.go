@@ -0,0 +1,178 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/gateway/action"
+	util "agola.io/agola/internal/util"
+	cstypes "agola.io/agola/services/configstore/types"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+)
+
+func createAuditEventResponse(e *cstypes.AuditEvent) *gwapitypes.AuditEventResponse {
+	auditEvent := &gwapitypes.AuditEventResponse{
+		ID:             e.ID,
+		Sequence:       e.Sequence,
+		CreationTime:   e.CreationTime,
+		ActorUserID:    e.ActorUserID,
+		ActorUserName:  e.ActorUserName,
+		Action:         string(e.Action),
+		TargetKind:     string(e.TargetKind),
+		TargetID:       e.TargetID,
+		TargetName:     e.TargetName,
+		OrganizationID: e.OrganizationID,
+		Details:        e.Details,
+		RequestMetadata: gwapitypes.AuditEventRequestMetadata{
+			RemoteAddr: e.RequestMetadata.RemoteAddr,
+			UserAgent:  e.RequestMetadata.UserAgent,
+		},
+	}
+	return auditEvent
+}
+
+func parseTimeParam(query url.Values, name string) (*time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("cannot parse %s", name), serrors.InvalidTimeRange())
+	}
+
+	return &t, nil
+}
+
+// getAuditEvents parses the audit events request filters and returns the
+// audit events of the organization or, when orgRef is empty, all the audit
+// events.
+func getAuditEvents(w http.ResponseWriter, r *http.Request, ah *action.ActionHandler, orgRef string) ([]*gwapitypes.AuditEventResponse, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	ropts, err := parseRequestOptions(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	since, err := parseTimeParam(query, "since")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	until, err := parseTimeParam(query, "until")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	actorUserRef := query.Get("actor")
+	actions := query["action"]
+	targetKind := query.Get("targetkind")
+	targetID := query.Get("targetid")
+
+	if ropts.Cursor != "" && (actorUserRef != "" || len(actions) > 0 || targetKind != "" || targetID != "" || since != nil || until != nil) {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("only one of cursor or filters should be provided"))
+	}
+
+	areq := &action.GetAuditEventsRequest{
+		OrgRef: orgRef,
+
+		ActorUserRef: actorUserRef,
+		Actions:      actions,
+		TargetKind:   targetKind,
+		TargetID:     targetID,
+		Since:        since,
+		Until:        until,
+
+		Cursor:        ropts.Cursor,
+		Limit:         ropts.Limit,
+		SortDirection: action.SortDirection(ropts.SortDirection),
+	}
+	ares, err := ah.GetAuditEvents(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	auditEvents := make([]*gwapitypes.AuditEventResponse, len(ares.AuditEvents))
+	for i, e := range ares.AuditEvents {
+		auditEvents[i] = createAuditEventResponse(e)
+	}
+
+	addCursorHeader(w, ares.Cursor)
+
+	return auditEvents, nil
+}
+
+type AuditEventsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewAuditEventsHandler(log zerolog.Logger, ah *action.ActionHandler) *AuditEventsHandler {
+	return &AuditEventsHandler{log: log, ah: ah}
+}
+
+func (h *AuditEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := getAuditEvents(w, r, h.ah, "")
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+type OrgAuditEventsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewOrgAuditEventsHandler(log zerolog.Logger, ah *action.ActionHandler) *OrgAuditEventsHandler {
+	return &OrgAuditEventsHandler{log: log, ah: ah}
+}
+
+func (h *OrgAuditEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *OrgAuditEventsHandler) do(w http.ResponseWriter, r *http.Request) ([]*gwapitypes.AuditEventResponse, error) {
+	vars := mux.Vars(r)
+	orgRef, err := url.PathUnescape(vars["orgref"])
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	res, err := getAuditEvents(w, r, h.ah, orgRef)
+	return res, errors.WithStack(err)
+}
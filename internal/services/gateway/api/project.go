@@ -69,6 +69,10 @@ func (h *CreateProjectHandler) do(r *http.Request) (*gwapitypes.ProjectResponse,
 		SkipSSHHostKeyCheck:         req.SkipSSHHostKeyCheck,
 		PassVarsToForkedPR:          req.PassVarsToForkedPR,
 		MembersCanPerformRunActions: req.MembersCanPerformRunActions,
+		MirrorURL:                   req.MirrorURL,
+		MirrorSSHHostKey:            req.MirrorSSHHostKey,
+		MirrorBranches:              req.MirrorBranches,
+		MirrorTags:                  req.MirrorTags,
 	}
 
 	project, err := h.ah.CreateProject(ctx, areq)
@@ -0,0 +1,81 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/gateway/action"
+	util "agola.io/agola/internal/util"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+)
+
+func createProjectMirrorResponse(r *action.ProjectMirrorResponse) *gwapitypes.ProjectMirrorResponse {
+	pm := r.ProjectMirror
+	return &gwapitypes.ProjectMirrorResponse{
+		URL:           pm.URL,
+		SSHHostKey:    pm.SSHHostKey,
+		Branches:      pm.Branches,
+		Tags:          pm.Tags,
+		SSHPublicKey:  r.SSHPublicKey,
+		Refs:          pm.Refs,
+		NextPollTime:  pm.NextPollTime,
+		LastPollTime:  pm.LastPollTime,
+		LastPollError: pm.LastPollError,
+	}
+}
+
+type ProjectMirrorHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewProjectMirrorHandler(log zerolog.Logger, ah *action.ActionHandler) *ProjectMirrorHandler {
+	return &ProjectMirrorHandler{log: log, ah: ah}
+}
+
+func (h *ProjectMirrorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ProjectMirrorHandler) do(r *http.Request) (*gwapitypes.ProjectMirrorResponse, error) {
+	ctx := r.Context()
+
+	vars := mux.Vars(r)
+	projectRef, err := url.PathUnescape(vars["projectref"])
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	ares, err := h.ah.GetProjectMirror(ctx, projectRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return createProjectMirrorResponse(ares), nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/gateway/action"
+	util "agola.io/agola/internal/util"
+	cstypes "agola.io/agola/services/configstore/types"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+)
+
+func createReceivedWebhookResponse(r *cstypes.ReceivedWebhook) *gwapitypes.ReceivedWebhookResponse {
+	receivedWebhook := &gwapitypes.ReceivedWebhookResponse{
+		ID:             r.ID,
+		Sequence:       r.Sequence,
+		DeliveryID:     r.DeliveryID,
+		Event:          r.Event,
+		ReceivedAt:     r.CreationTime,
+		Status:         gwapitypes.ReceivedWebhookStatus(r.Status),
+		Attempts:       r.Attempts,
+		Outcome:        gwapitypes.ReceivedWebhookOutcome(r.Outcome),
+		OutcomeMessage: r.OutcomeMessage,
+		RunNumbers:     r.RunNumbers,
+	}
+	return receivedWebhook
+}
+
+type ProjectReceivedWebhooks struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewProjectReceivedWebhooksHandler(log zerolog.Logger, ah *action.ActionHandler) *ProjectReceivedWebhooks {
+	return &ProjectReceivedWebhooks{log: log, ah: ah}
+}
+
+func (h *ProjectReceivedWebhooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *ProjectReceivedWebhooks) do(w http.ResponseWriter, r *http.Request) ([]*gwapitypes.ReceivedWebhookResponse, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	vars := mux.Vars(r)
+	projectRef, err := url.PathUnescape(vars["projectref"])
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	ropts, err := parseRequestOptions(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	statusFilter := query["status"]
+
+	if ropts.Cursor != "" && len(statusFilter) > 0 {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("only one of cursor or status should be provided"))
+	}
+
+	areq := &action.GetProjectReceivedWebhooksRequest{
+		ProjectRef: projectRef,
+
+		StatusFilter:  statusFilter,
+		Cursor:        ropts.Cursor,
+		Limit:         ropts.Limit,
+		SortDirection: action.SortDirection(ropts.SortDirection),
+	}
+	ares, err := h.ah.GetProjectReceivedWebhooks(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	receivedWebhooks := make([]*gwapitypes.ReceivedWebhookResponse, len(ares.ReceivedWebhooks))
+	for i, r := range ares.ReceivedWebhooks {
+		receivedWebhooks[i] = createReceivedWebhookResponse(r)
+	}
+
+	addCursorHeader(w, ares.Cursor)
+
+	return receivedWebhooks, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/gateway/action"
+	"agola.io/agola/internal/util"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	cstypes "agola.io/agola/services/configstore/types"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+)
+
+func createRoleBindingResponse(rb *csapitypes.RoleBinding) *gwapitypes.RoleBindingResponse {
+	return &gwapitypes.RoleBindingResponse{
+		ID:          rb.ID,
+		SubjectKind: string(rb.Subject.Kind),
+		SubjectID:   rb.Subject.ID,
+		SubjectName: rb.SubjectName,
+		Role:        gwapitypes.MemberRole(rb.Role),
+		ParentPath:  rb.ParentPath,
+	}
+}
+
+type RoleBindingsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRoleBindingsHandler(log zerolog.Logger, ah *action.ActionHandler) *RoleBindingsHandler {
+	return &RoleBindingsHandler{log: log, ah: ah}
+}
+
+func (h *RoleBindingsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *RoleBindingsHandler) do(r *http.Request) ([]*gwapitypes.RoleBindingResponse, error) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	_, tree := query["tree"]
+
+	parentType, parentRef, err := GetConfigTypeRef(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	areq := &action.GetRoleBindingsRequest{
+		ParentType: parentType,
+		ParentRef:  parentRef,
+		Tree:       tree,
+	}
+	csroleBindings, err := h.ah.GetRoleBindings(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	roleBindings := make([]*gwapitypes.RoleBindingResponse, len(csroleBindings))
+	for i, rb := range csroleBindings {
+		roleBindings[i] = createRoleBindingResponse(rb)
+	}
+
+	return roleBindings, nil
+}
+
+type SetRoleBindingHandler struct {
+	log         zerolog.Logger
+	ah          *action.ActionHandler
+	subjectKind cstypes.ObjectKind
+}
+
+func NewSetRoleBindingHandler(log zerolog.Logger, ah *action.ActionHandler, subjectKind cstypes.ObjectKind) *SetRoleBindingHandler {
+	return &SetRoleBindingHandler{log: log, ah: ah, subjectKind: subjectKind}
+}
+
+func (h *SetRoleBindingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *SetRoleBindingHandler) do(r *http.Request) (*gwapitypes.RoleBindingResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	subjectRef := vars["subjectref"]
+
+	parentType, parentRef, err := GetConfigTypeRef(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var req gwapitypes.SetRoleBindingRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.SetRoleBindingRequest{
+		ParentType:  parentType,
+		ParentRef:   parentRef,
+		SubjectKind: h.subjectKind,
+		SubjectRef:  subjectRef,
+		Role:        cstypes.MemberRole(req.Role),
+	}
+	rb, err := h.ah.SetRoleBinding(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res := &gwapitypes.RoleBindingResponse{
+		ID:          rb.ID,
+		SubjectKind: string(rb.Subject.Kind),
+		SubjectID:   rb.Subject.ID,
+		Role:        gwapitypes.MemberRole(rb.Role),
+	}
+
+	return res, nil
+}
+
+type DeleteRoleBindingHandler struct {
+	log         zerolog.Logger
+	ah          *action.ActionHandler
+	subjectKind cstypes.ObjectKind
+}
+
+func NewDeleteRoleBindingHandler(log zerolog.Logger, ah *action.ActionHandler, subjectKind cstypes.ObjectKind) *DeleteRoleBindingHandler {
+	return &DeleteRoleBindingHandler{log: log, ah: ah, subjectKind: subjectKind}
+}
+
+func (h *DeleteRoleBindingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *DeleteRoleBindingHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	subjectRef := vars["subjectref"]
+
+	parentType, parentRef, err := GetConfigTypeRef(r)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := h.ah.DeleteRoleBinding(ctx, parentType, parentRef, h.subjectKind, subjectRef); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
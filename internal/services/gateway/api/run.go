@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
@@ -28,6 +29,7 @@ import (
 	"agola.io/agola/internal/services/common"
 	serrors "agola.io/agola/internal/services/errors"
 	"agola.io/agola/internal/services/gateway/action"
+	"agola.io/agola/internal/tasklog"
 	"agola.io/agola/internal/util"
 	gwapitypes "agola.io/agola/services/gateway/api/types"
 	rstypes "agola.io/agola/services/runservice/types"
@@ -85,7 +87,7 @@ func createRunResponseTask(r *rstypes.Run, rt *rstypes.RunTask, rct *rstypes.Run
 	return t
 }
 
-func createRunTaskResponse(rt *rstypes.RunTask, rct *rstypes.RunConfigTask) *gwapitypes.RunTaskResponse {
+func createRunTaskResponse(rt *rstypes.RunTask, rct *rstypes.RunConfigTask) (*gwapitypes.RunTaskResponse, error) {
 	t := &gwapitypes.RunTaskResponse{
 		ID:         rt.ID,
 		Name:       rct.Name,
@@ -105,6 +107,36 @@ func createRunTaskResponse(rt *rstypes.RunTask, rct *rstypes.RunConfigTask) *gwa
 		TaskTimeoutInterval: rct.TaskTimeoutInterval,
 	}
 
+	if ap := rct.ApprovalPolicy; ap != nil {
+		t.ApprovalPolicy = &gwapitypes.RunTaskApprovalPolicy{
+			MinApprovers:       ap.RequiredApprovers(),
+			AllowedUsers:       ap.AllowedUsers,
+			AllowedTeams:       ap.AllowedTeams,
+			AllowedRoles:       ap.AllowedRoles,
+			ForbidSelfApproval: ap.ForbidSelfApproval,
+			Expiry:             ap.Expiry,
+		}
+	}
+
+	approvals, err := common.GetRunTaskApprovals(rt.Annotations)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	now := time.Now()
+	t.Approvals = make([]*gwapitypes.RunTaskApprovalResponse, len(approvals))
+	for i, approval := range approvals {
+		a := &gwapitypes.RunTaskApprovalResponse{
+			UserID:   approval.UserID,
+			UserName: approval.UserName,
+			Expired:  approval.IsExpired(rct.ApprovalPolicy, now),
+		}
+		if !approval.ApprovalTime.IsZero() {
+			approvalTime := approval.ApprovalTime
+			a.ApprovalTime = &approvalTime
+		}
+		t.Approvals[i] = a
+	}
+
 	t.SetupStep = &gwapitypes.RunTaskResponseSetupStep{
 		Name:      "Task setup",
 		Phase:     rt.SetupStep.Phase,
@@ -165,7 +197,7 @@ func createRunTaskResponse(rt *rstypes.RunTask, rct *rstypes.RunConfigTask) *gwa
 		t.Steps[i] = s
 	}
 
-	return t
+	return t, nil
 }
 
 type GroupRunHandler struct {
@@ -292,7 +324,10 @@ func (h *RuntaskHandler) do(r *http.Request) (*gwapitypes.RunTaskResponse, error
 	}
 	rct := rc.Tasks[rt.ID]
 
-	res := createRunTaskResponse(rt, rct)
+	res, err := createRunTaskResponse(rt, rct)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 
 	return res, nil
 }
@@ -601,6 +636,11 @@ func (h *LogsHandler) do(w http.ResponseWriter, r *http.Request) error {
 		follow = true
 	}
 
+	opts, err := tasklog.ParseCopyOptions(q)
+	if err != nil {
+		return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("invalid log options"))
+	}
+
 	areq := &action.GetLogsRequest{
 		GroupType: h.groupType,
 		Ref:       ref,
@@ -609,6 +649,9 @@ func (h *LogsHandler) do(w http.ResponseWriter, r *http.Request) error {
 		Setup:     setup,
 		Step:      step,
 		Follow:    follow,
+		Format:    opts.Format,
+		Start:     opts.Start,
+		Limit:     opts.Limit,
 	}
 
 	resp, err := h.ah.GetLogs(ctx, areq)
@@ -620,7 +663,7 @@ func (h *LogsHandler) do(w http.ResponseWriter, r *http.Request) error {
 	// header also if there're currently no lines to send
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+	w.Header().Set("Content-Type", opts.Format.ContentType())
 	w.WriteHeader(http.StatusOK)
 	var flusher http.Flusher
 	if fl, ok := w.(http.Flusher); ok {
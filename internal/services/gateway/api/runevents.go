@@ -0,0 +1,158 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/gateway/action"
+	"agola.io/agola/internal/util"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+type RunEventsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRunEventsHandler(log zerolog.Logger, ah *action.ActionHandler) *RunEventsHandler {
+	return &RunEventsHandler{log: log, ah: ah}
+}
+
+func (h *RunEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+}
+
+func (h *RunEventsHandler) do(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	// resume from the provided sequence or from the last event id received
+	// by an event source client
+	afterSequenceStr := query.Get("aftersequence")
+	if afterSequenceStr == "" {
+		afterSequenceStr = r.Header.Get("Last-Event-ID")
+	}
+
+	var afterSequence uint64
+	if afterSequenceStr != "" {
+		var err error
+		afterSequence, err = strconv.ParseUint(afterSequenceStr, 10, 64)
+		if err != nil {
+			return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("cannot parse aftersequence"), serrors.InvalidStartSequence())
+		}
+	}
+
+	areq := &action.GetRunEventsRequest{
+		AfterSequence: afterSequence,
+		ProjectRefs:   query["project"],
+		OrgRefs:       query["org"],
+		UserRefs:      query["user"],
+	}
+
+	stream, err := h.ah.GetRunEvents(ctx, areq)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer stream.Close()
+
+	// write and flush the headers so the client will receive the response
+	// header also if there're currently no events to send
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	var flusher http.Flusher
+	if fl, ok := w.(http.Flusher); ok {
+		flusher = fl
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	err = stream.Read(ctx, func(ev *rstypes.RunEvent) error {
+		evj, err := json.Marshal(createRunEventResponse(ev))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Sequence, ev.RunEventType, evj); err != nil {
+			return errors.WithStack(err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return nil
+	})
+	if err != nil {
+		// the response has already been sent so just log the error
+		h.log.Err(err).Msg("run events stream error")
+	}
+
+	return nil
+}
+
+func createRunEventResponse(ev *rstypes.RunEvent) *gwapitypes.RunEventResponse {
+	data := ev.Data.(*rstypes.RunEventData)
+
+	res := &gwapitypes.RunEventResponse{
+		Sequence:  ev.Sequence,
+		Type:      ev.RunEventType,
+		ProjectID: data.Annotations[action.AnnotationProjectID],
+		UserID:    data.Annotations[action.AnnotationUserID],
+		Run: &gwapitypes.RunEventRunResponse{
+			Number:      data.Counter,
+			Name:        data.Name,
+			Annotations: data.Annotations,
+			Phase:       rstypes.RunPhase(data.Phase),
+			Result:      rstypes.RunResult(data.Result),
+			SetupErrors: data.SetupErrors,
+			Tasks:       make(map[string]*gwapitypes.RunEventRunTaskResponse, len(data.Tasks)),
+			EnqueueTime: data.EnqueueTime,
+			StartTime:   data.StartTime,
+			EndTime:     data.EndTime,
+		},
+	}
+
+	for id, t := range data.Tasks {
+		res.Run.Tasks[id] = &gwapitypes.RunEventRunTaskResponse{
+			ID:              t.ID,
+			Name:            t.Name,
+			Level:           t.Level,
+			Status:          rstypes.RunTaskStatus(t.Status),
+			Timedout:        t.Timedout,
+			Skip:            t.Skip,
+			WaitingApproval: t.WaitingApproval,
+			Approved:        t.Approved,
+			StartTime:       t.StartTime,
+			EndTime:         t.EndTime,
+		}
+	}
+
+	return res
+}
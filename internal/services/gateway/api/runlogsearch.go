@@ -0,0 +1,143 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/common"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/gateway/action"
+	"agola.io/agola/internal/util"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+)
+
+type RunLogsSearchHandler struct {
+	log       zerolog.Logger
+	ah        *action.ActionHandler
+	groupType common.GroupType
+}
+
+func NewRunLogsSearchHandler(log zerolog.Logger, ah *action.ActionHandler, groupType common.GroupType) *RunLogsSearchHandler {
+	return &RunLogsSearchHandler{log: log, ah: ah, groupType: groupType}
+}
+
+func (h *RunLogsSearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *RunLogsSearchHandler) do(r *http.Request) (*gwapitypes.RunLogsSearchResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+
+	var err error
+	var ref string
+	switch h.groupType {
+	case common.GroupTypeProject:
+		ref, err = url.PathUnescape(vars["projectref"])
+		if err != nil {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("projectref is empty"))
+		}
+	case common.GroupTypeUser:
+		ref = vars["userref"]
+	}
+
+	runNumber, err := strconv.ParseUint(vars["runnumber"], 10, 64)
+	if err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("cannot parse run number"), serrors.InvalidRunNumber())
+	}
+
+	areq := &action.SearchRunLogsRequest{
+		GroupType: h.groupType,
+		Ref:       ref,
+		RunNumber: runNumber,
+		TaskIDs:   q["taskid"],
+		Pattern:   q.Get("pattern"),
+	}
+	_, areq.Regex = q["regex"]
+	_, areq.IgnoreCase = q["ignorecase"]
+
+	if contextStr := q.Get("context"); contextStr != "" {
+		areq.Context, err = strconv.Atoi(contextStr)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("cannot parse context"))
+		}
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		areq.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("cannot parse limit"))
+		}
+	}
+
+	res, err := h.ah.SearchRunLogs(ctx, areq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp := &gwapitypes.RunLogsSearchResponse{
+		Matches:   make([]*gwapitypes.RunLogsSearchMatchResponse, len(res.Matches)),
+		Truncated: res.Truncated,
+	}
+	for i, m := range res.Matches {
+		var taskName string
+		if rct, ok := res.RunConfig.Tasks[m.TaskID]; ok {
+			taskName = rct.Name
+		}
+		resp.Matches[i] = &gwapitypes.RunLogsSearchMatchResponse{
+			TaskID:   m.TaskID,
+			TaskName: taskName,
+			Step:     m.Step,
+			Line:     createRunLogsSearchLineResponse(m.Line),
+			Before:   createRunLogsSearchLinesResponse(m.Before),
+			After:    createRunLogsSearchLinesResponse(m.After),
+		}
+	}
+
+	return resp, nil
+}
+
+func createRunLogsSearchLineResponse(l *rsapitypes.RunLogsSearchLine) *gwapitypes.RunLogsSearchLineResponse {
+	return &gwapitypes.RunLogsSearchLineResponse{
+		Number: l.Number,
+		Time:   l.Time,
+		Stream: l.Stream,
+		Text:   l.Text,
+	}
+}
+
+func createRunLogsSearchLinesResponse(lines []*rsapitypes.RunLogsSearchLine) []*gwapitypes.RunLogsSearchLineResponse {
+	res := make([]*gwapitypes.RunLogsSearchLineResponse, len(lines))
+	for i, l := range lines {
+		res[i] = createRunLogsSearchLineResponse(l)
+	}
+	return res
+}
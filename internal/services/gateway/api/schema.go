@@ -0,0 +1,39 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog"
+
+	"agola.io/agola/internal/config"
+)
+
+// RunConfigSchemaHandler serves the run config JSON Schema
+type RunConfigSchemaHandler struct {
+	log zerolog.Logger
+}
+
+func NewRunConfigSchemaHandler(log zerolog.Logger) *RunConfigSchemaHandler {
+	return &RunConfigSchemaHandler{log: log}
+}
+
+func (h *RunConfigSchemaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	if _, err := w.Write(config.Schema()); err != nil {
+		h.log.Err(err).Send()
+	}
+}
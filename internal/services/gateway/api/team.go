@@ -0,0 +1,346 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/gateway/action"
+	"agola.io/agola/internal/util"
+	cstypes "agola.io/agola/services/configstore/types"
+	gwapitypes "agola.io/agola/services/gateway/api/types"
+)
+
+func createTeamResponse(t *cstypes.Team) *gwapitypes.TeamResponse {
+	return &gwapitypes.TeamResponse{
+		ID:   t.ID,
+		Name: t.Name,
+	}
+}
+
+type OrgTeamsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewOrgTeamsHandler(log zerolog.Logger, ah *action.ActionHandler) *OrgTeamsHandler {
+	return &OrgTeamsHandler{log: log, ah: ah}
+}
+
+func (h *OrgTeamsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *OrgTeamsHandler) do(r *http.Request) ([]*gwapitypes.TeamResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+
+	teams, err := h.ah.GetOrgTeams(ctx, orgRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res := make([]*gwapitypes.TeamResponse, len(teams))
+	for i, t := range teams {
+		res[i] = createTeamResponse(t)
+	}
+
+	return res, nil
+}
+
+type OrgTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewOrgTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *OrgTeamHandler {
+	return &OrgTeamHandler{log: log, ah: ah}
+}
+
+func (h *OrgTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *OrgTeamHandler) do(r *http.Request) (*gwapitypes.TeamResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	team, err := h.ah.GetOrgTeam(ctx, orgRef, teamRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return createTeamResponse(team), nil
+}
+
+type CreateOrgTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewCreateOrgTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *CreateOrgTeamHandler {
+	return &CreateOrgTeamHandler{log: log, ah: ah}
+}
+
+func (h *CreateOrgTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusCreated, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *CreateOrgTeamHandler) do(r *http.Request) (*gwapitypes.TeamResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+
+	var req gwapitypes.CreateTeamRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	creq := &action.CreateOrgTeamRequest{
+		OrgRef: orgRef,
+		Name:   req.Name,
+	}
+
+	team, err := h.ah.CreateOrgTeam(ctx, creq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return createTeamResponse(team), nil
+}
+
+type UpdateOrgTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewUpdateOrgTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *UpdateOrgTeamHandler {
+	return &UpdateOrgTeamHandler{log: log, ah: ah}
+}
+
+func (h *UpdateOrgTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *UpdateOrgTeamHandler) do(r *http.Request) (*gwapitypes.TeamResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	var req gwapitypes.UpdateTeamRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	creq := &action.UpdateOrgTeamRequest{
+		OrgRef:  orgRef,
+		TeamRef: teamRef,
+		Name:    req.Name,
+	}
+
+	team, err := h.ah.UpdateOrgTeam(ctx, creq)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return createTeamResponse(team), nil
+}
+
+type DeleteOrgTeamHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewDeleteOrgTeamHandler(log zerolog.Logger, ah *action.ActionHandler) *DeleteOrgTeamHandler {
+	return &DeleteOrgTeamHandler{log: log, ah: ah}
+}
+
+func (h *DeleteOrgTeamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *DeleteOrgTeamHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	if err := h.ah.DeleteOrgTeam(ctx, orgRef, teamRef); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+type OrgTeamMembersHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewOrgTeamMembersHandler(log zerolog.Logger, ah *action.ActionHandler) *OrgTeamMembersHandler {
+	return &OrgTeamMembersHandler{log: log, ah: ah}
+}
+
+func (h *OrgTeamMembersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *OrgTeamMembersHandler) do(r *http.Request) ([]*gwapitypes.UserResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+
+	users, err := h.ah.GetOrgTeamMembers(ctx, orgRef, teamRef)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res := make([]*gwapitypes.UserResponse, len(users))
+	for i, u := range users {
+		res[i] = createUserResponse(u)
+	}
+
+	return res, nil
+}
+
+type AddOrgTeamMemberHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewAddOrgTeamMemberHandler(log zerolog.Logger, ah *action.ActionHandler) *AddOrgTeamMemberHandler {
+	return &AddOrgTeamMemberHandler{log: log, ah: ah}
+}
+
+func (h *AddOrgTeamMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *AddOrgTeamMemberHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+	userRef := vars["userref"]
+
+	if err := h.ah.AddOrgTeamMember(ctx, orgRef, teamRef, userRef); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+type RemoveOrgTeamMemberHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRemoveOrgTeamMemberHandler(log zerolog.Logger, ah *action.ActionHandler) *RemoveOrgTeamMemberHandler {
+	return &RemoveOrgTeamMemberHandler{log: log, ah: ah}
+}
+
+func (h *RemoveOrgTeamMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusNoContent, nil); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *RemoveOrgTeamMemberHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgRef := vars["orgref"]
+	teamRef := vars["teamref"]
+	userRef := vars["userref"]
+
+	if err := h.ah.RemoveOrgTeamMember(ctx, orgRef, teamRef, userRef); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
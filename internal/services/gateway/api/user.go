@@ -661,24 +661,24 @@ func NewUserCreateRunHandler(log zerolog.Logger, ah *action.ActionHandler) *User
 }
 
 func (h *UserCreateRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	err := h.do(r)
+	res, err := h.do(r)
 	if util.HTTPError(w, err) {
 		h.log.Err(err).Send()
 		return
 	}
 
-	if err := util.HTTPResponse(w, http.StatusCreated, nil); err != nil {
+	if err := util.HTTPResponse(w, http.StatusCreated, res); err != nil {
 		h.log.Err(err).Send()
 	}
 }
 
-func (h *UserCreateRunHandler) do(r *http.Request) error {
+func (h *UserCreateRunHandler) do(r *http.Request) ([]*gwapitypes.RunsResponse, error) {
 	ctx := r.Context()
 
 	var req gwapitypes.UserCreateRunRequest
 	d := json.NewDecoder(r.Body)
 	if err := d.Decode(&req); err != nil {
-		return util.NewAPIErrorWrap(util.ErrBadRequest, err)
+		return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err)
 	}
 
 	creq := &action.UserCreateRunRequest{
@@ -692,12 +692,17 @@ func (h *UserCreateRunHandler) do(r *http.Request) error {
 		PullRequestRefRegexes: req.PullRequestRefRegexes,
 		Variables:             req.Variables,
 	}
-	err := h.ah.UserCreateRun(ctx, creq)
+	runs, err := h.ah.UserCreateRun(ctx, creq)
 	if err != nil {
-		return errors.WithStack(err)
+		return nil, errors.WithStack(err)
 	}
 
-	return nil
+	res := make([]*gwapitypes.RunsResponse, len(runs))
+	for i, r := range runs {
+		res[i] = createRunsResponse(r)
+	}
+
+	return res, nil
 }
 
 type UserOrgsHandler struct {
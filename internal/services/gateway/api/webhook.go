@@ -15,46 +15,48 @@
 package api
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
 
-	"agola.io/agola/internal/services/common"
+	gitsource "agola.io/agola/internal/gitsources"
 	"agola.io/agola/internal/services/gateway/action"
-	"agola.io/agola/internal/services/types"
 	"agola.io/agola/internal/util"
-	csclient "agola.io/agola/services/configstore/client"
-	cstypes "agola.io/agola/services/configstore/types"
-	rsclient "agola.io/agola/services/runservice/client"
 )
 
+// maxWebhookSize is the max size of a received webhook body
+const maxWebhookSize = 10 * 1024 * 1024
+
 type webhooksHandler struct {
-	log               zerolog.Logger
-	ah                *action.ActionHandler
-	configstoreClient *csclient.Client
-	runserviceClient  *rsclient.Client
-	apiExposedURL     string
+	log zerolog.Logger
+	ah  *action.ActionHandler
 }
 
-func NewWebhooksHandler(log zerolog.Logger, ah *action.ActionHandler, configstoreClient *csclient.Client, runserviceClient *rsclient.Client, apiExposedURL string) *webhooksHandler {
+func NewWebhooksHandler(log zerolog.Logger, ah *action.ActionHandler) *webhooksHandler {
 	return &webhooksHandler{
-		log:               log,
-		ah:                ah,
-		configstoreClient: configstoreClient,
-		runserviceClient:  runserviceClient,
-		apiExposedURL:     apiExposedURL,
+		log: log,
+		ah:  ah,
 	}
 }
 
 func (h *webhooksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	err := h.do(r)
+	err := h.do(w, r)
 	if util.HTTPError(w, err) {
 		h.log.Err(err).Send()
 		return
 	}
+
+	if err := util.HTTPResponse(w, http.StatusAccepted, nil); err != nil {
+		h.log.Err(err).Send()
+	}
 }
 
-func (h *webhooksHandler) do(r *http.Request) error {
+// do saves the webhook in a queue and returns without processing it, so git
+// sources won't time out and redeliver it when processing is slow. Queued
+// webhooks are processed by the received webhooks workers.
+func (h *webhooksHandler) do(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 
 	projectID := r.URL.Query().Get("projectid")
@@ -62,96 +64,20 @@ func (h *webhooksHandler) do(r *http.Request) error {
 		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("bad webhook url %q. Missing projectid", r.URL))
 	}
 
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookSize)
 	defer r.Body.Close()
 
-	csProject, _, err := h.configstoreClient.GetProject(ctx, projectID)
-	if err != nil {
-		return action.APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get project %s", projectID))
-	}
-	project := csProject.Project
-
-	user, _, err := h.configstoreClient.GetUserByLinkedAccount(ctx, project.LinkedAccountID)
-	if err != nil {
-		return action.APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user by linked account %q", project.LinkedAccountID))
-	}
-	linkedAccounts, _, err := h.configstoreClient.GetUserLinkedAccounts(ctx, user.ID)
-	if err != nil {
-		return action.APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get user %q linked accounts", user.ID))
-	}
-
-	var la *cstypes.LinkedAccount
-	for _, v := range linkedAccounts {
-		if v.ID == project.LinkedAccountID {
-			la = v
-			break
-		}
-	}
-
-	if la == nil {
-		return util.NewAPIError(util.ErrInternal, util.WithAPIErrorMsgf("linked account %q for user %q doesn't exist", project.LinkedAccountID, user.Name))
-	}
-
-	rs, _, err := h.configstoreClient.GetRemoteSource(ctx, la.RemoteSourceID)
-	if err != nil {
-		return action.APIErrorFromRemoteError(err, util.WithAPIErrorMsgf("failed to get remote source %q", la.RemoteSourceID))
-	}
-
-	gitSource, err := h.ah.GetGitSource(ctx, rs, user.Name, la)
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		return util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to create gitea client"))
-	}
-
-	sshPrivKey := project.SSHPrivateKey
-	sshHostKey := rs.SSHHostKey
-	// use remotesource skipSSHHostKeyCheck config and override with project config if set to true there
-	skipSSHHostKeyCheck := rs.SkipSSHHostKeyCheck
-	if project.SkipSSHHostKeyCheck {
-		skipSSHHostKeyCheck = project.SkipSSHHostKeyCheck
+		return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to read webhook body"))
 	}
 
-	webhookData, err := gitSource.ParseWebhook(r, project.WebhookSecret)
+	receivedWebhook, err := h.ah.ReceiveWebhook(ctx, projectID, r.Header, body, r.URL.Query().Get(gitsource.WebhookTokenParam))
 	if err != nil {
-		return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to parse webhook"))
-	}
-	// skip nil webhook data
-	// TODO(sgotti) report the reason of the skip
-	if webhookData == nil {
-		h.log.Info().Msg("skipping webhook")
-		return nil
+		return errors.WithStack(err)
 	}
 
-	cloneURL := webhookData.SSHURL
-
-	req := &action.CreateRunRequest{
-		RunType:            types.RunTypeProject,
-		RefType:            common.WebHookEventToRunRefType(webhookData.Event),
-		RunCreationTrigger: types.RunCreationTriggerTypeWebhook,
-
-		Project:             project,
-		User:                nil,
-		RepoPath:            webhookData.Repo.Path,
-		GitSource:           gitSource,
-		CommitSHA:           webhookData.CommitSHA,
-		Message:             webhookData.Message,
-		Branch:              webhookData.Branch,
-		Tag:                 webhookData.Tag,
-		PullRequestID:       webhookData.PullRequestID,
-		PRFromSameRepo:      webhookData.PRFromSameRepo,
-		Ref:                 webhookData.Ref,
-		SSHPrivKey:          sshPrivKey,
-		SSHHostKey:          sshHostKey,
-		SkipSSHHostKeyCheck: skipSSHHostKeyCheck,
-		CloneURL:            cloneURL,
-
-		CommitLink:      webhookData.CommitLink,
-		BranchLink:      webhookData.BranchLink,
-		TagLink:         webhookData.TagLink,
-		PullRequestLink: webhookData.PullRequestLink,
-		CompareLink:     webhookData.CompareLink,
-	}
-	if err := h.ah.CreateRuns(ctx, req); err != nil {
-		return util.NewAPIErrorWrap(util.ErrInternal, err, util.WithAPIErrorMsg("failed to create run"))
-	}
+	h.log.Debug().Str("receivedWebhookID", receivedWebhook.ID).Str("deliveryID", receivedWebhook.DeliveryID).Msg("webhook received")
 
 	return nil
 }
@@ -20,6 +20,9 @@ const (
 	ContextKeyUserAdmin
 
 	ContextKeyTokenAuth
+
+	ContextKeyRemoteAddr
+	ContextKeyUserAgent
 )
 
 func CurrentUserID(ctx context.Context) string {
@@ -30,6 +33,30 @@ func CurrentUserID(ctx context.Context) string {
 	return userIDVal.(string)
 }
 
+func CurrentUserName(ctx context.Context) string {
+	userNameVal := ctx.Value(ContextKeyUsername)
+	if userNameVal == nil {
+		return ""
+	}
+	return userNameVal.(string)
+}
+
+func RemoteAddr(ctx context.Context) string {
+	remoteAddrVal := ctx.Value(ContextKeyRemoteAddr)
+	if remoteAddrVal == nil {
+		return ""
+	}
+	return remoteAddrVal.(string)
+}
+
+func UserAgent(ctx context.Context) string {
+	userAgentVal := ctx.Value(ContextKeyUserAgent)
+	if userAgentVal == nil {
+		return ""
+	}
+	return userAgentVal.(string)
+}
+
 func IsUserLogged(ctx context.Context) bool {
 	return ctx.Value(ContextKeyUserID) != nil
 }
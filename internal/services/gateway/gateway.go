@@ -31,6 +31,8 @@ import (
 	"github.com/sorintlab/errors"
 
 	icommon "agola.io/agola/internal/common"
+	"agola.io/agola/internal/gitsources/httpclient"
+	"agola.io/agola/internal/metrics"
 	"agola.io/agola/internal/objectstorage"
 	scommon "agola.io/agola/internal/services/common"
 	"agola.io/agola/internal/services/config"
@@ -38,8 +40,10 @@ import (
 	"agola.io/agola/internal/services/gateway/api"
 	"agola.io/agola/internal/services/gateway/common"
 	"agola.io/agola/internal/services/gateway/handlers"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
 	csclient "agola.io/agola/services/configstore/client"
+	cstypes "agola.io/agola/services/configstore/types"
 	nsclient "agola.io/agola/services/notification/client"
 	rsclient "agola.io/agola/services/runservice/client"
 )
@@ -70,6 +74,8 @@ func NewGateway(ctx context.Context, log zerolog.Logger, gc *config.Config) (*Ga
 		log = log.Level(zerolog.DebugLevel)
 	}
 
+	httpclient.SetLogger(log)
+
 	if c.Web.ListenAddress == "" {
 		return nil, errors.Errorf("listen address undefined")
 	}
@@ -204,7 +210,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 		return skipCSRFOnToken(protectCSRF(setCSRFHeader(h)))
 	}
 
-	webhooksHandler := api.NewWebhooksHandler(g.log, g.ah, g.configstoreClient, g.runserviceClient, g.c.APIExposedURL)
+	webhooksHandler := api.NewWebhooksHandler(g.log, g.ah)
 
 	projectGroupHandler := api.NewProjectGroupHandler(g.log, g.ah)
 	projectGroupSubgroupsHandler := api.NewProjectGroupSubgroupsHandler(g.log, g.ah)
@@ -225,6 +231,8 @@ func (g *Gateway) Run(ctx context.Context) error {
 	projectRunWebhookRedeliveryHandler := api.NewProjectRunWebhookRedeliveryHandler(g.log, g.ah)
 	projectCommitStatusDeliveriesHandler := api.NewProjectCommitStatusDeliveriesHandler(g.log, g.ah)
 	projectCommitStatusRedeliveryHandler := api.NewProjectCommitStatusRedeliveryHandler(g.log, g.ah)
+	projectReceivedWebhooksHandler := api.NewProjectReceivedWebhooksHandler(g.log, g.ah)
+	projectMirrorHandler := api.NewProjectMirrorHandler(g.log, g.ah)
 
 	secretsHandler := api.NewSecretsHandler(g.log, g.ah)
 	createSecretHandler := api.NewCreateSecretHandler(g.log, g.ah)
@@ -236,6 +244,12 @@ func (g *Gateway) Run(ctx context.Context) error {
 	updateVariableHandler := api.NewUpdateVariableHandler(g.log, g.ah)
 	deleteVariableHandler := api.NewDeleteVariableHandler(g.log, g.ah)
 
+	roleBindingsHandler := api.NewRoleBindingsHandler(g.log, g.ah)
+	setUserRoleBindingHandler := api.NewSetRoleBindingHandler(g.log, g.ah, cstypes.ObjectKindUser)
+	deleteUserRoleBindingHandler := api.NewDeleteRoleBindingHandler(g.log, g.ah, cstypes.ObjectKindUser)
+	setTeamRoleBindingHandler := api.NewSetRoleBindingHandler(g.log, g.ah, cstypes.ObjectKindTeam)
+	deleteTeamRoleBindingHandler := api.NewDeleteRoleBindingHandler(g.log, g.ah, cstypes.ObjectKindTeam)
+
 	currentUserHandler := api.NewCurrentUserHandler(g.log, g.ah)
 	userHandler := api.NewUserHandler(g.log, g.ah)
 	usersHandler := api.NewUsersHandler(g.log, g.ah)
@@ -271,6 +285,18 @@ func (g *Gateway) Run(ctx context.Context) error {
 	addOrgMemberHandler := api.NewAddOrgMemberHandler(g.log, g.ah)
 	removeOrgMemberHandler := api.NewRemoveOrgMemberHandler(g.log, g.ah)
 
+	orgTeamsHandler := api.NewOrgTeamsHandler(g.log, g.ah)
+	orgTeamHandler := api.NewOrgTeamHandler(g.log, g.ah)
+	createOrgTeamHandler := api.NewCreateOrgTeamHandler(g.log, g.ah)
+	updateOrgTeamHandler := api.NewUpdateOrgTeamHandler(g.log, g.ah)
+	deleteOrgTeamHandler := api.NewDeleteOrgTeamHandler(g.log, g.ah)
+	orgTeamMembersHandler := api.NewOrgTeamMembersHandler(g.log, g.ah)
+	addOrgTeamMemberHandler := api.NewAddOrgTeamMemberHandler(g.log, g.ah)
+	removeOrgTeamMemberHandler := api.NewRemoveOrgTeamMemberHandler(g.log, g.ah)
+
+	auditEventsHandler := api.NewAuditEventsHandler(g.log, g.ah)
+	orgAuditEventsHandler := api.NewOrgAuditEventsHandler(g.log, g.ah)
+
 	projectRunsHandler := api.NewGroupRunsHandler(g.log, g.ah, scommon.GroupTypeProject)
 	projectRunHandler := api.NewGroupRunHandler(g.log, g.ah, scommon.GroupTypeProject)
 	projectRuntaskHandler := api.NewRuntaskHandler(g.log, g.ah, scommon.GroupTypeProject)
@@ -278,6 +304,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 	projectRunTaskActionsHandler := api.NewRunTaskActionsHandler(g.log, g.ah, scommon.GroupTypeProject)
 	projectRunLogsHandler := api.NewLogsHandler(g.log, g.ah, scommon.GroupTypeProject)
 	projectRunLogsDeleteHandler := api.NewLogsDeleteHandler(g.log, g.ah, scommon.GroupTypeProject)
+	projectRunLogsSearchHandler := api.NewRunLogsSearchHandler(g.log, g.ah, scommon.GroupTypeProject)
 
 	userRunsHandler := api.NewGroupRunsHandler(g.log, g.ah, scommon.GroupTypeUser)
 	userRunHandler := api.NewGroupRunHandler(g.log, g.ah, scommon.GroupTypeUser)
@@ -286,12 +313,16 @@ func (g *Gateway) Run(ctx context.Context) error {
 	userRunTaskActionsHandler := api.NewRunTaskActionsHandler(g.log, g.ah, scommon.GroupTypeUser)
 	userRunLogsHandler := api.NewLogsHandler(g.log, g.ah, scommon.GroupTypeUser)
 	userRunLogsDeleteHandler := api.NewLogsDeleteHandler(g.log, g.ah, scommon.GroupTypeUser)
+	userRunLogsSearchHandler := api.NewRunLogsSearchHandler(g.log, g.ah, scommon.GroupTypeUser)
+
+	runEventsHandler := api.NewRunEventsHandler(g.log, g.ah)
 
 	userRemoteReposHandler := api.NewUserRemoteReposHandler(g.log, g.ah, g.configstoreClient)
 
 	badgeHandler := api.NewBadgeHandler(g.log, g.ah)
 
 	versionHandler := api.NewVersionHandler(g.log, g.ah)
+	runConfigSchemaHandler := api.NewRunConfigSchemaHandler(g.log)
 
 	reposHandler := api.NewReposHandler(g.log, g.c.GitserverURL, g.c.GitserverAPIToken)
 
@@ -304,6 +335,10 @@ func (g *Gateway) Run(ctx context.Context) error {
 	maintenanceModeHandler := api.NewMaintenanceModeHandler(g.log, g.ah)
 	exportHandler := api.NewExportHandler(g.log, g.ah)
 	importHandler := api.NewImportHandler(g.log, g.ah)
+	backupsHandler := api.NewBackupsHandler(g.log, g.ah)
+	restoreBackupHandler := api.NewRestoreBackupHandler(g.log, g.ah)
+	scopedExportHandler := api.NewScopedExportHandler(g.log, g.ah)
+	scopedImportHandler := api.NewScopedImportHandler(g.log, g.ah)
 
 	router := mux.NewRouter()
 	reposRouter := mux.NewRouter()
@@ -319,7 +354,10 @@ func (g *Gateway) Run(ctx context.Context) error {
 		return handlers.NewAuthChecker(g.log, g.configstoreClient, handlers.WithTokenChecker(g.c.AdminToken), handlers.WithCookieChecker(g.sc, g.c.UnsecureCookies), handlers.WithRequired(false))(CSRF(h))
 	}
 
-	router.PathPrefix("/api/v1alpha").Handler(apirouter)
+	apirouter.Use(tracing.NewHTTPMiddleware("gateway"))
+	apirouter.Use(metrics.NewHTTPMiddleware("gateway"))
+
+	router.PathPrefix("/api/v1alpha").Handler(handlers.NewRequestMetadataHandler(apirouter))
 
 	//apirouter.Handle("/projectgroups", authForcedHandler(projectsHandler)).Methods("GET")
 	apirouter.Handle("/projectgroups/{projectgroupref}", authForcedHandler(projectGroupHandler)).Methods("GET")
@@ -339,6 +377,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/projects/{projectref}/runs", authForcedHandler(projectRunsHandler)).Methods("GET")
 	apirouter.Handle("/projects/{projectref}/runs/{runnumber}", authOptionalHandler(projectRunHandler)).Methods("GET")
 	apirouter.Handle("/projects/{projectref}/runs/{runnumber}/actions", authForcedHandler(projectRunActionsHandler)).Methods("PUT")
+	apirouter.Handle("/projects/{projectref}/runs/{runnumber}/logs/search", authOptionalHandler(projectRunLogsSearchHandler)).Methods("GET")
 	apirouter.Handle("/projects/{projectref}/runs/{runnumber}/tasks/{taskid}", authOptionalHandler(projectRuntaskHandler)).Methods("GET")
 	apirouter.Handle("/projects/{projectref}/runs/{runnumber}/tasks/{taskid}/actions", authForcedHandler(projectRunTaskActionsHandler)).Methods("PUT")
 	apirouter.Handle("/projects/{projectref}/runs/{runnumber}/tasks/{taskid}/logs", authOptionalHandler(projectRunLogsHandler)).Methods("GET")
@@ -348,6 +387,8 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/projects/{projectref}/runwebhookdeliveries/{runwebhookdeliveryid}/redelivery", authForcedHandler(projectRunWebhookRedeliveryHandler)).Methods("PUT")
 	apirouter.Handle("/projects/{projectref}/commitstatusdeliveries", authForcedHandler(projectCommitStatusDeliveriesHandler)).Methods("GET")
 	apirouter.Handle("/projects/{projectref}/commitstatusdeliveries/{commitstatusdeliveryid}/redelivery", authForcedHandler(projectCommitStatusRedeliveryHandler)).Methods("PUT")
+	apirouter.Handle("/projects/{projectref}/receivedwebhooks", authForcedHandler(projectReceivedWebhooksHandler)).Methods("GET")
+	apirouter.Handle("/projects/{projectref}/mirror", authForcedHandler(projectMirrorHandler)).Methods("GET")
 
 	apirouter.Handle("/projectgroups/{projectgroupref}/secrets", authForcedHandler(secretsHandler)).Methods("GET")
 	apirouter.Handle("/projects/{projectref}/secrets", authForcedHandler(secretsHandler)).Methods("GET")
@@ -367,6 +408,17 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/projectgroups/{projectgroupref}/variables/{variablename}", authForcedHandler(deleteVariableHandler)).Methods("DELETE")
 	apirouter.Handle("/projects/{projectref}/variables/{variablename}", authForcedHandler(deleteVariableHandler)).Methods("DELETE")
 
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings", authForcedHandler(roleBindingsHandler)).Methods("GET")
+	apirouter.Handle("/projects/{projectref}/rolebindings", authForcedHandler(roleBindingsHandler)).Methods("GET")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/users/{subjectref}", authForcedHandler(setUserRoleBindingHandler)).Methods("PUT")
+	apirouter.Handle("/projects/{projectref}/rolebindings/users/{subjectref}", authForcedHandler(setUserRoleBindingHandler)).Methods("PUT")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/users/{subjectref}", authForcedHandler(deleteUserRoleBindingHandler)).Methods("DELETE")
+	apirouter.Handle("/projects/{projectref}/rolebindings/users/{subjectref}", authForcedHandler(deleteUserRoleBindingHandler)).Methods("DELETE")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/teams/{subjectref}", authForcedHandler(setTeamRoleBindingHandler)).Methods("PUT")
+	apirouter.Handle("/projects/{projectref}/rolebindings/teams/{subjectref}", authForcedHandler(setTeamRoleBindingHandler)).Methods("PUT")
+	apirouter.Handle("/projectgroups/{projectgroupref}/rolebindings/teams/{subjectref}", authForcedHandler(deleteTeamRoleBindingHandler)).Methods("DELETE")
+	apirouter.Handle("/projects/{projectref}/rolebindings/teams/{subjectref}", authForcedHandler(deleteTeamRoleBindingHandler)).Methods("DELETE")
+
 	apirouter.Handle("/user", authForcedHandler(currentUserHandler)).Methods("GET")
 	apirouter.Handle("/users/{userref}", authForcedHandler(userHandler)).Methods("GET")
 	apirouter.Handle("/users", authForcedHandler(usersHandler)).Methods("GET")
@@ -380,11 +432,14 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/users/{userref}/runs", authForcedHandler(userRunsHandler)).Methods("GET")
 	apirouter.Handle("/users/{userref}/runs/{runnumber}", authOptionalHandler(userRunHandler)).Methods("GET")
 	apirouter.Handle("/users/{userref}/runs/{runnumber}/actions", authForcedHandler(userRunActionsHandler)).Methods("PUT")
+	apirouter.Handle("/users/{userref}/runs/{runnumber}/logs/search", authOptionalHandler(userRunLogsSearchHandler)).Methods("GET")
 	apirouter.Handle("/users/{userref}/runs/{runnumber}/tasks/{taskid}", authOptionalHandler(userRuntaskHandler)).Methods("GET")
 	apirouter.Handle("/users/{userref}/runs/{runnumber}/tasks/{taskid}/actions", authForcedHandler(userRunTaskActionsHandler)).Methods("PUT")
 	apirouter.Handle("/users/{userref}/runs/{runnumber}/tasks/{taskid}/logs", authOptionalHandler(userRunLogsHandler)).Methods("GET")
 	apirouter.Handle("/users/{userref}/runs/{runnumber}/tasks/{taskid}/logs", authForcedHandler(userRunLogsDeleteHandler)).Methods("DELETE")
 
+	apirouter.Handle("/runs/events", authOptionalHandler(runEventsHandler)).Methods("GET")
+
 	apirouter.Handle("/users/{userref}/linkedaccounts", authForcedHandler(createUserLAHandler)).Methods("POST")
 	apirouter.Handle("/users/{userref}/linkedaccounts/{laid}", authForcedHandler(deleteUserLAHandler)).Methods("DELETE")
 	apirouter.Handle("/users/{userref}/tokens", authForcedHandler(createUserTokenHandler)).Methods("POST")
@@ -396,6 +451,8 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/remotesources", remoteSourcesHandler).Methods("GET")
 	apirouter.Handle("/remotesources/{remotesourceref}", authForcedHandler(deleteRemoteSourceHandler)).Methods("DELETE")
 
+	apirouter.Handle("/auditevents", authForcedHandler(auditEventsHandler)).Methods("GET")
+
 	apirouter.Handle("/orgs/{orgref}", authForcedHandler(orgHandler)).Methods("GET")
 	apirouter.Handle("/orgs", authForcedHandler(orgsHandler)).Methods("GET")
 	apirouter.Handle("/orgs", authForcedHandler(createOrgHandler)).Methods("POST")
@@ -404,6 +461,15 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/orgs/{orgref}/members", authForcedHandler(orgMembersHandler)).Methods("GET")
 	apirouter.Handle("/orgs/{orgref}/members/{userref}", authForcedHandler(addOrgMemberHandler)).Methods("PUT")
 	apirouter.Handle("/orgs/{orgref}/members/{userref}", authForcedHandler(removeOrgMemberHandler)).Methods("DELETE")
+	apirouter.Handle("/orgs/{orgref}/teams", authForcedHandler(orgTeamsHandler)).Methods("GET")
+	apirouter.Handle("/orgs/{orgref}/teams", authForcedHandler(createOrgTeamHandler)).Methods("POST")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}", authForcedHandler(orgTeamHandler)).Methods("GET")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}", authForcedHandler(updateOrgTeamHandler)).Methods("PUT")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}", authForcedHandler(deleteOrgTeamHandler)).Methods("DELETE")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}/members", authForcedHandler(orgTeamMembersHandler)).Methods("GET")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}/members/{userref}", authForcedHandler(addOrgTeamMemberHandler)).Methods("PUT")
+	apirouter.Handle("/orgs/{orgref}/teams/{teamref}/members/{userref}", authForcedHandler(removeOrgTeamMemberHandler)).Methods("DELETE")
+	apirouter.Handle("/orgs/{orgref}/auditevents", authForcedHandler(orgAuditEventsHandler)).Methods("GET")
 	apirouter.Handle("/orgs/{orgref}/invitations", authForcedHandler(orgInvitationsHandler)).Methods("GET")
 	apirouter.Handle("/orgs/{orgref}/invitations", authForcedHandler(createOrgInvitationHandler)).Methods("POST")
 	apirouter.Handle("/orgs/{orgref}/invitations/{userref}", authForcedHandler(orgInvitationHandler)).Methods("GET")
@@ -415,6 +481,8 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 	apirouter.Handle("/version", versionHandler).Methods("GET")
 
+	apirouter.Handle("/schemas/runconfig.json", runConfigSchemaHandler).Methods("GET")
+
 	apirouter.Handle("/auth/login", loginUserHandler).Methods("POST")
 	apirouter.Handle("/auth/authorize", authorizeHandler).Methods("POST")
 	apirouter.Handle("/auth/register", registerHandler).Methods("POST")
@@ -424,17 +492,22 @@ func (g *Gateway) Run(ctx context.Context) error {
 	apirouter.Handle("/maintenance/{servicename}", authForcedHandler(maintenanceModeHandler)).Methods("PUT", "DELETE")
 	apirouter.Handle("/export/{servicename}", authForcedHandler(exportHandler)).Methods("GET")
 	apirouter.Handle("/import/{servicename}", authForcedHandler(importHandler)).Methods("POST")
+	apirouter.Handle("/backups/{servicename}", authForcedHandler(backupsHandler)).Methods("GET")
+	apirouter.Handle("/backups/{servicename}/{backupid}/restore", authForcedHandler(restoreBackupHandler)).Methods("POST")
+	apirouter.Handle("/scopedexport", authForcedHandler(scopedExportHandler)).Methods("GET")
+	apirouter.Handle("/scopedimport", authForcedHandler(scopedImportHandler)).Methods("POST")
 
 	// TODO(sgotti) add auth to these requests
 	reposRouter.Handle("/repos/{rest:.*}", reposHandler).Methods("GET", "POST")
 
-	router.Handle("/webhooks", webhooksHandler).Methods("POST")
 	router.PathPrefix("/").HandlerFunc(handlers.NewWebBundleHandlerFunc(g.c.APIExposedURL))
 
 	maxBytesHandler := handlers.NewMaxBytesHandler(router, maxRequestSize)
 
 	mainrouter := mux.NewRouter()
 	mainrouter.PathPrefix("/repos/").Handler(corsHandler(reposRouter))
+	// webhooks have their own max body size
+	mainrouter.Handle("/webhooks", ghandlers.RecoveryHandler(ghandlers.PrintRecoveryStack(true))(webhooksHandler)).Methods("POST")
 	mainrouter.PathPrefix("/").Handler(ghandlers.RecoveryHandler(ghandlers.PrintRecoveryStack(true))(corsHandler(maxBytesHandler)))
 
 	var tlsConfig *tls.Config
@@ -453,6 +526,24 @@ func (g *Gateway) Run(ctx context.Context) error {
 		TLSConfig: tlsConfig,
 	}
 
+	// the metrics are served on a dedicated listen address and not on the
+	// public api
+	var metricsServer *http.Server
+	if g.c.MetricsListenAddress != "" {
+		metricsRouter := mux.NewRouter()
+		metricsRouter.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
+
+		metricsServer = &http.Server{
+			Addr:    g.c.MetricsListenAddress,
+			Handler: metricsRouter,
+		}
+	}
+
+	for i := 0; i < receivedWebhooksWorkers; i++ {
+		go g.receivedWebhooksWorkerLoop(ctx)
+	}
+	go g.projectMirrorsPollerLoop(ctx)
+
 	lerrCh := make(chan error)
 	go func() {
 		if !g.c.Web.TLS {
@@ -461,12 +552,24 @@ func (g *Gateway) Run(ctx context.Context) error {
 			lerrCh <- httpServer.ListenAndServeTLS("", "")
 		}
 	}()
+	if metricsServer != nil {
+		go func() {
+			lerrCh <- metricsServer.ListenAndServe()
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
 		log.Info().Msg("configstore exiting")
 		httpServer.Close()
+		if metricsServer != nil {
+			metricsServer.Close()
+		}
 	case err := <-lerrCh:
+		httpServer.Close()
+		if metricsServer != nil {
+			metricsServer.Close()
+		}
 		if err != nil {
 			log.Err(err).Msg("http server listen error")
 			return errors.WithStack(err)
@@ -0,0 +1,42 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"agola.io/agola/internal/services/gateway/common"
+)
+
+// requestMetadataHandler saves in the request context the request metadata
+// recorded in the audit events
+type requestMetadataHandler struct {
+	h http.Handler
+}
+
+func NewRequestMetadataHandler(h http.Handler) *requestMetadataHandler {
+	return &requestMetadataHandler{
+		h: h,
+	}
+}
+
+func (h *requestMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ctx = context.WithValue(ctx, common.ContextKeyRemoteAddr, r.RemoteAddr)
+	ctx = context.WithValue(ctx, common.ContextKeyUserAgent, r.UserAgent())
+
+	h.h.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	projectMirrorsInterval = 5 * time.Second
+)
+
+// projectMirrorsPollerLoop polls the mirror projects repositories. Pollers of
+// all the gateway instances share the same project mirrors.
+func (g *Gateway) projectMirrorsPollerLoop(ctx context.Context) {
+	for {
+		// poll all the project mirrors to poll before sleeping
+		for {
+			processed, err := g.ah.ProcessNextProjectMirror(ctx, g.c.MirrorPollInterval)
+			if err != nil {
+				g.log.Err(err).Send()
+			}
+			if !processed || err != nil {
+				break
+			}
+		}
+
+		sleepCh := time.NewTimer(projectMirrorsInterval).C
+		select {
+		case <-ctx.Done():
+			return
+		case <-sleepCh:
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gateway
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// receivedWebhooksWorkers is the number of concurrent received webhooks
+	// workers. Workers of all the gateway instances share the same queue.
+	receivedWebhooksWorkers = 4
+
+	receivedWebhooksInterval = 1 * time.Second
+)
+
+// receivedWebhooksWorkerLoop processes the received webhooks queued by the
+// webhooks handler.
+func (g *Gateway) receivedWebhooksWorkerLoop(ctx context.Context) {
+	for {
+		// process all the received webhooks before sleeping
+		for {
+			processed, err := g.ah.ProcessNextReceivedWebhook(ctx)
+			if err != nil {
+				g.log.Err(err).Send()
+			}
+			if !processed || err != nil {
+				break
+			}
+		}
+
+		sleepCh := time.NewTimer(receivedWebhooksInterval).C
+		select {
+		case <-ctx.Done():
+			return
+		case <-sleepCh:
+		}
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"agola.io/agola/internal/services/gateway/action"
 	"agola.io/agola/internal/sqlg/lock"
 	"agola.io/agola/internal/sqlg/sql"
+	cstypes "agola.io/agola/services/configstore/types"
 	"agola.io/agola/services/notification/types"
 	rstypes "agola.io/agola/services/runservice/types"
 )
@@ -93,6 +94,23 @@ func (n *NotificationService) generateCommitStatus(ctx context.Context, ev *rsty
 		return nil, errors.Wrapf(err, "failed to get project %s", groupID)
 	}
 
+	// mirror projects don't have a git source api to report the commit status
+	if project.RemoteRepositoryConfigType == cstypes.RemoteRepositoryConfigTypeMirror {
+		return nil, nil
+	}
+
+	// gerrit reports the run results only as change reviews, skip the runs not
+	// related to a change
+	if project.RemoteRepositoryConfigType == cstypes.RemoteRepositoryConfigTypeRemoteSource && run.Run.Annotations[action.AnnotationPullRequestID] == "" {
+		rs, _, err := n.configstoreClient.GetRemoteSource(ctx, project.RemoteSourceID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get remote source %s", project.RemoteSourceID)
+		}
+		if rs.Type == cstypes.RemoteSourceTypeGerrit {
+			return nil, nil
+		}
+	}
+
 	context := fmt.Sprintf("%s/%s/%s", n.gc.ID, project.Name, run.RunConfig.Name)
 
 	return &commitStatus{
@@ -18,6 +18,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/sorintlab/errors"
 
 	gitsource "agola.io/agola/internal/gitsources"
@@ -29,6 +30,7 @@ import (
 	csclient "agola.io/agola/services/configstore/client"
 	cstypes "agola.io/agola/services/configstore/types"
 	"agola.io/agola/services/notification/types"
+	rsclient "agola.io/agola/services/runservice/client"
 )
 
 const (
@@ -136,6 +138,7 @@ func (n *NotificationService) handleCommitStatusDelivery(ctx context.Context, co
 		deliveredAt = util.Ptr(time.Now())
 	}
 
+	var deliveryStatus types.DeliveryStatus
 	err = n.d.Do(ctx, func(tx *sql.Tx) error {
 		var err error
 
@@ -152,6 +155,7 @@ func (n *NotificationService) handleCommitStatusDelivery(ctx context.Context, co
 		} else {
 			commitStatusDelivery.DeliveryStatus = types.DeliveryStatusDeliveryError
 		}
+		deliveryStatus = commitStatusDelivery.DeliveryStatus
 
 		commitStatusDelivery.DeliveredAt = deliveredAt
 
@@ -165,12 +169,19 @@ func (n *NotificationService) handleCommitStatusDelivery(ctx context.Context, co
 		return errors.WithStack(err)
 	}
 
+	if deliveryStatus != "" {
+		commitStatusDeliveriesTotal.WithLabelValues(string(deliveryStatus)).Inc()
+	}
+
 	return nil
 }
 
 type GitSourceCommitStatusUpdater struct {
+	log               zerolog.Logger
 	configstoreClient *csclient.Client
+	runserviceClient  *rsclient.Client
 	c                 *config.Notification
+	clusterID         string
 }
 
 func (g *GitSourceCommitStatusUpdater) updateCommitStatus(ctx context.Context, commitStatus *types.CommitStatus) (bool, error) {
@@ -229,10 +240,19 @@ func (g *GitSourceCommitStatusUpdater) updateCommitStatus(ctx context.Context, c
 		return false, errors.Wrapf(err, "failed to generate commit status target url")
 	}
 
+	// git sources not supporting commit statuses report the run results as
+	// pull request reviews
+	if reviewSource, ok := gitSource.(gitsource.ReviewSource); ok {
+		delivered, err := g.createReview(ctx, gitSource, reviewSource, project, commitStatus, targetURL)
+		return delivered, errors.WithStack(err)
+	}
+
 	delivered, err := gitSource.CreateCommitStatus(project.RepositoryPath, commitStatus.CommitSHA, state, targetURL, commitStatus.Description, commitStatus.Context)
 	if err != nil {
 		return false, errors.WithStack(err)
 	}
 
+	g.updateRunReports(ctx, gitSource, project, commitStatus)
+
 	return delivered, nil
 }
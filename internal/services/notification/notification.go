@@ -27,6 +27,8 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/sorintlab/errors"
 
+	"agola.io/agola/internal/gitsources/httpclient"
+	"agola.io/agola/internal/metrics"
 	"agola.io/agola/internal/services/common"
 	"agola.io/agola/internal/services/config"
 	"agola.io/agola/internal/services/handlers"
@@ -36,6 +38,7 @@ import (
 	"agola.io/agola/internal/sqlg/lock"
 	"agola.io/agola/internal/sqlg/manager"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
 	csclient "agola.io/agola/services/configstore/client"
 	"agola.io/agola/services/notification/types"
@@ -67,6 +70,8 @@ func NewNotificationService(ctx context.Context, log zerolog.Logger, gc *config.
 		log = log.Level(zerolog.DebugLevel)
 	}
 
+	httpclient.SetLogger(log)
+
 	if c.DB.Type == sql.Sqlite3 {
 		if err := os.MkdirAll(filepath.Dir(c.DB.ConnString), 0770); err != nil {
 			return nil, errors.WithStack(err)
@@ -106,8 +111,11 @@ func NewNotificationService(ctx context.Context, log zerolog.Logger, gc *config.
 	runserviceClient := rsclient.NewClient(c.RunserviceURL, c.RunserviceAPIToken)
 
 	u := &GitSourceCommitStatusUpdater{
+		log:               log,
 		configstoreClient: configstoreClient,
+		runserviceClient:  runserviceClient,
 		c:                 c,
+		clusterID:         gc.ID,
 	}
 
 	n := &NotificationService{
@@ -136,8 +144,11 @@ func (n *NotificationService) setupDefaultRouter() http.Handler {
 	authHandler := handlers.NewInternalAuthChecker(n.log, n.c.APIToken)
 
 	router := mux.NewRouter().UseEncodedPath().SkipClean(true)
+	router.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
 	apirouter := router.PathPrefix("/api/v1alpha").Subrouter().UseEncodedPath().SkipClean(true)
 
+	apirouter.Use(tracing.NewHTTPMiddleware("notification"))
+	apirouter.Use(metrics.NewHTTPMiddleware("notification"))
 	apirouter.Use(authHandler)
 
 	// don't return 404 on a call to an undefined handler but 400 to distinguish between a non existent resource and a wrong method
@@ -32,6 +32,7 @@ import (
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
 
+	gitsource "agola.io/agola/internal/gitsources"
 	"agola.io/agola/internal/services/config"
 	serrors "agola.io/agola/internal/services/errors"
 	"agola.io/agola/internal/services/notification/action"
@@ -40,6 +41,7 @@ import (
 	"agola.io/agola/internal/testutil"
 	"agola.io/agola/internal/util"
 	"agola.io/agola/services/notification/types"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
 	rsclient "agola.io/agola/services/runservice/client"
 	rstypes "agola.io/agola/services/runservice/types"
 )
@@ -1256,3 +1258,141 @@ func TestProjectCommitStatusRedelivery(t *testing.T) {
 		}
 	})
 }
+
+func TestGenCheckRun(t *testing.T) {
+	run := &rsapitypes.RunResponse{
+		Run: &rstypes.Run{
+			Counter: 1,
+			Phase:   rstypes.RunPhaseFinished,
+			Result:  rstypes.RunResultFailed,
+			Tasks: map[string]*rstypes.RunTask{
+				"task01": {ID: "task01", Status: rstypes.RunTaskStatusSuccess},
+				"task02": {ID: "task02", Status: rstypes.RunTaskStatusFailed},
+				"task03": {ID: "task03", Status: rstypes.RunTaskStatusStopped, Timedout: true},
+			},
+		},
+		RunConfig: &rstypes.RunConfig{
+			Name: "run01",
+			Tasks: map[string]*rstypes.RunConfigTask{
+				"task01": {ID: "task01", Name: "build"},
+				"task02": {ID: "task02", Name: "test"},
+				"task03": {ID: "task03", Name: "e2e"},
+			},
+		},
+	}
+	commitStatus := &types.CommitStatus{
+		CommitSHA:   "commitsha01",
+		State:       types.CommitStateFailed,
+		Description: "The run failed",
+		Context:     "cluster01/project01/run01",
+	}
+
+	r, err := newRunReport("http://agola", "projectid01", run)
+	assert.NilError(t, err)
+
+	checkRun := genCheckRun(commitStatus, r, "runid01", ".agola/config.jsonnet")
+
+	expectedCheckRun := &gitsource.CheckRun{
+		Name:       "cluster01/project01/run01",
+		CommitSHA:  "commitsha01",
+		ExternalID: "runid01",
+		DetailsURL: "http://agola/run?projectref=projectid01&runnumber=1",
+		Status:     gitsource.CheckRunStatusCompleted,
+		Conclusion: gitsource.CheckRunConclusionFailure,
+		Title:      "The run failed",
+		Summary:    "| Task | Status |\n| --- | --- |\n| build | success |\n| e2e | timedout |\n| test | failed |\n",
+		Annotations: []*gitsource.CheckRunAnnotation{
+			{Path: ".agola/config.jsonnet", Level: gitsource.CheckRunAnnotationLevelFailure, Title: "e2e", Message: `task "e2e" timed out`},
+			{Path: ".agola/config.jsonnet", Level: gitsource.CheckRunAnnotationLevelFailure, Title: "test", Message: `task "test" failed`},
+		},
+	}
+	assert.DeepEqual(t, checkRun, expectedCheckRun)
+
+	// without a config path no annotations are reported
+	checkRun = genCheckRun(commitStatus, r, "runid01", "")
+	assert.Equal(t, len(checkRun.Annotations), 0)
+}
+
+func TestGenPullRequestComment(t *testing.T) {
+	runs := []*runReport{
+		{
+			Name:   "run01",
+			Status: "success",
+			URL:    "http://agola/run?projectref=projectid01&runnumber=1",
+			Tasks:  []*runReportTask{{Name: "build", Status: rstypes.RunTaskStatusSuccess}},
+		},
+		{
+			Name:   "run|02",
+			Status: "running",
+			URL:    "http://agola/run?projectref=projectid01&runnumber=2",
+			Tasks: []*runReportTask{
+				{Name: "build", Status: rstypes.RunTaskStatusSuccess},
+				{Name: "test", Status: rstypes.RunTaskStatusRunning},
+			},
+		},
+	}
+
+	marker := pullRequestCommentMarker("cluster01", "projectid01")
+	assert.Equal(t, marker, "<!-- agola:cluster01:projectid01 -->")
+
+	body := genPullRequestComment(marker, "project01", "0123456789abcdef", runs)
+
+	expectedBody := `<!-- agola:cluster01:projectid01 -->
+### Agola runs for project project01 at commit 01234567
+
+| Run | Status | Tasks |
+| --- | --- | --- |
+| [run01](http://agola/run?projectref=projectid01&runnumber=1) | success | build: success |
+| [run\|02](http://agola/run?projectref=projectid01&runnumber=2) | running | build: success<br>test: running |
+`
+	assert.Equal(t, body, expectedBody)
+}
+
+func TestReviewVote(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []string
+		vote     gitsource.ReviewVote
+	}{
+		{name: "all runs succeeded", statuses: []string{"success", "success"}, vote: gitsource.ReviewVoteApprove},
+		{name: "run still running", statuses: []string{"success", "running"}, vote: gitsource.ReviewVoteNone},
+		{name: "run queued", statuses: []string{"queued"}, vote: gitsource.ReviewVoteNone},
+		{name: "run failed", statuses: []string{"running", "failed", "success"}, vote: gitsource.ReviewVoteReject},
+		{name: "run stopped", statuses: []string{"success", "stopped"}, vote: gitsource.ReviewVoteReject},
+		{name: "run setup error", statuses: []string{"setuperror"}, vote: gitsource.ReviewVoteReject},
+		{name: "run cancelled", statuses: []string{"cancelled"}, vote: gitsource.ReviewVoteReject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := []*runReport{}
+			for _, status := range tt.statuses {
+				runs = append(runs, &runReport{Status: status})
+			}
+			assert.Equal(t, reviewVote(runs), tt.vote)
+		})
+	}
+}
+
+func TestGenReviewMessage(t *testing.T) {
+	runs := []*runReport{
+		{Name: "run01", Status: "success", URL: "http://agola/run?projectref=projectid01&runnumber=1"},
+		{Name: "run02", Status: "failed", URL: "http://agola/run?projectref=projectid01&runnumber=2"},
+	}
+	commitStatus := &types.CommitStatus{
+		State:       types.CommitStateFailed,
+		Description: "The run failed",
+		Context:     "cluster01/project01/run02",
+	}
+
+	message := genReviewMessage(commitStatus, "http://agola/run?projectref=projectid01&runnumber=2", runs)
+
+	expectedMessage := `cluster01/project01/run02: The run failed
+http://agola/run?projectref=projectid01&runnumber=2
+
+Agola runs:
+* run01: success http://agola/run?projectref=projectid01&runnumber=1
+* run02: failed http://agola/run?projectref=projectid01&runnumber=2
+`
+	assert.Equal(t, message, expectedMessage)
+}
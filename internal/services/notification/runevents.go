@@ -133,6 +133,8 @@ func (n *NotificationService) runEventsHandler(ctx context.Context) error {
 						n.log.Error().Msg("failed to unmarshal run webhook")
 					}
 				}
+			case rstypes.RunTaskStatusChanged:
+				// run task events don't generate commit statuses or webhooks
 			default:
 				n.log.Error().Msgf("run event %q is not valid", ev.RunEventType)
 			}
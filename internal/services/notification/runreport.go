@@ -0,0 +1,361 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sorintlab/errors"
+
+	gitsource "agola.io/agola/internal/gitsources"
+	"agola.io/agola/internal/services/common"
+	"agola.io/agola/internal/services/gateway/action"
+	csapitypes "agola.io/agola/services/configstore/api/types"
+	"agola.io/agola/services/notification/types"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+	rsclient "agola.io/agola/services/runservice/client"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+const (
+	// maxPullRequestGroupRuns is the maximum number of pull request runs
+	// inspected to find the runs of the same commit
+	maxPullRequestGroupRuns = 40
+)
+
+// runReportTask is a run task reported in the check runs and pull request
+// comments
+type runReportTask struct {
+	Name     string
+	Status   rstypes.RunTaskStatus
+	Timedout bool
+}
+
+// runReport is a run reported in the check runs and pull request comments
+type runReport struct {
+	Name   string
+	Status string
+	URL    string
+	Tasks  []*runReportTask
+}
+
+func newRunReport(webExposedURL, projectID string, run *rsapitypes.RunResponse) (*runReport, error) {
+	u, err := webRunURL(webExposedURL, projectID, run.Run.Counter)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	status := string(run.Run.Phase)
+	if run.Run.Phase == rstypes.RunPhaseFinished {
+		status = string(run.Run.Result)
+	}
+
+	r := &runReport{
+		Name:   run.RunConfig.Name,
+		Status: status,
+		URL:    u,
+	}
+	for _, rt := range run.Run.Tasks {
+		rct, ok := run.RunConfig.Tasks[rt.ID]
+		if !ok {
+			continue
+		}
+		r.Tasks = append(r.Tasks, &runReportTask{Name: rct.Name, Status: rt.Status, Timedout: rt.Timedout})
+	}
+	sort.Slice(r.Tasks, func(i, j int) bool { return r.Tasks[i].Name < r.Tasks[j].Name })
+
+	return r, nil
+}
+
+func (t *runReportTask) status() string {
+	if t.Timedout {
+		return "timedout"
+	}
+	return string(t.Status)
+}
+
+func (t *runReportTask) failed() bool {
+	return t.Timedout || t.Status == rstypes.RunTaskStatusFailed
+}
+
+// tasksTable returns a markdown table with the run tasks statuses
+func (r *runReport) tasksTable() string {
+	var b strings.Builder
+	b.WriteString("| Task | Status |\n")
+	b.WriteString("| --- | --- |\n")
+	for _, t := range r.Tasks {
+		fmt.Fprintf(&b, "| %s | %s |\n", markdownEscape(t.Name), t.status())
+	}
+	return b.String()
+}
+
+// genCheckRun generates the check run reporting the run with the provided
+// commit status
+func genCheckRun(commitStatus *types.CommitStatus, r *runReport, runID, configPath string) *gitsource.CheckRun {
+	checkRun := &gitsource.CheckRun{
+		Name:       commitStatus.Context,
+		CommitSHA:  commitStatus.CommitSHA,
+		ExternalID: runID,
+		DetailsURL: r.URL,
+		Title:      commitStatus.Description,
+		Summary:    r.tasksTable(),
+	}
+
+	switch commitStatus.State {
+	case types.CommitStatePending:
+		checkRun.Status = gitsource.CheckRunStatusInProgress
+	case types.CommitStateSuccess:
+		checkRun.Status = gitsource.CheckRunStatusCompleted
+		checkRun.Conclusion = gitsource.CheckRunConclusionSuccess
+	case types.CommitStateFailed:
+		checkRun.Status = gitsource.CheckRunStatusCompleted
+		checkRun.Conclusion = gitsource.CheckRunConclusionFailure
+	case types.CommitStateError:
+		checkRun.Status = gitsource.CheckRunStatusCompleted
+		checkRun.Conclusion = gitsource.CheckRunConclusionFailure
+		if r.Status == string(rstypes.RunPhaseCancelled) {
+			checkRun.Conclusion = gitsource.CheckRunConclusionCancelled
+		}
+	}
+
+	// annotations are reported on the run config file since the tasks aren't
+	// related to a specific repository file
+	if configPath == "" {
+		return checkRun
+	}
+	for _, t := range r.Tasks {
+		if !t.failed() {
+			continue
+		}
+		message := fmt.Sprintf("task %q failed", t.Name)
+		if t.Timedout {
+			message = fmt.Sprintf("task %q timed out", t.Name)
+		}
+		checkRun.Annotations = append(checkRun.Annotations, &gitsource.CheckRunAnnotation{
+			Path:    configPath,
+			Level:   gitsource.CheckRunAnnotationLevelFailure,
+			Title:   t.Name,
+			Message: message,
+		})
+	}
+
+	return checkRun
+}
+
+// pullRequestCommentMarker returns the hidden marker used to find the pull
+// request comment of a project
+func pullRequestCommentMarker(clusterID, projectID string) string {
+	return fmt.Sprintf("<!-- agola:%s:%s -->", clusterID, projectID)
+}
+
+// genPullRequestComment generates the pull request comment body with the
+// results of the runs of a commit
+func genPullRequestComment(marker, projectName, commitSHA string, runs []*runReport) string {
+	var b strings.Builder
+	b.WriteString(marker + "\n")
+	fmt.Fprintf(&b, "### Agola runs for project %s at commit %s\n\n", markdownEscape(projectName), shortSHA(commitSHA))
+	b.WriteString("| Run | Status | Tasks |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, r := range runs {
+		tasks := make([]string, 0, len(r.Tasks))
+		for _, t := range r.Tasks {
+			tasks = append(tasks, fmt.Sprintf("%s: %s", markdownEscape(t.Name), t.status()))
+		}
+		fmt.Fprintf(&b, "| [%s](%s) | %s | %s |\n", markdownEscape(r.Name), r.URL, r.Status, strings.Join(tasks, "<br>"))
+	}
+	return b.String()
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "[", `\[`, "]", `\]`, "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// updateRunReports reports the commit status run as a check run and in the
+// pull request comment when enabled and supported by the git source.
+// Errors are only logged since they must not make the commit status delivery
+// fail.
+func (g *GitSourceCommitStatusUpdater) updateRunReports(ctx context.Context, gitSource gitsource.GitSource, project *csapitypes.Project, commitStatus *types.CommitStatus) {
+	checkRunSource, isCheckRunSource := gitSource.(gitsource.CheckRunSource)
+	commentSource, isCommentSource := gitSource.(gitsource.PullRequestCommentSource)
+	reportCheckRun := g.c.CheckRuns && isCheckRunSource
+	reportComment := g.c.PullRequestComments && isCommentSource
+	if !reportCheckRun && !reportComment {
+		return
+	}
+
+	run, _, err := g.runserviceClient.GetRunByGroup(ctx, common.GenBaseRunGroup(common.GroupTypeProject, project.ID), commitStatus.RunCounter, nil)
+	if err != nil {
+		g.log.Warn().Err(err).Msgf("failed to get run %d of project %s", commitStatus.RunCounter, project.ID)
+		return
+	}
+	r, err := newRunReport(g.c.WebExposedURL, project.ID, run)
+	if err != nil {
+		g.log.Warn().Err(err).Send()
+		return
+	}
+
+	if reportCheckRun {
+		checkRun := genCheckRun(commitStatus, r, run.Run.ID, run.Run.Annotations[action.AnnotationConfigPath])
+		if delivered, err := checkRunSource.UpdateCheckRun(project.RepositoryPath, checkRun); err != nil || !delivered {
+			g.log.Warn().Err(err).Msgf("check run %q not delivered", checkRun.Name)
+		}
+	}
+
+	if reportComment && run.Run.Annotations[action.AnnotationPullRequestID] != "" {
+		if err := g.updatePullRequestComment(ctx, gitSource, commentSource, project, run); err != nil {
+			g.log.Warn().Err(err).Msgf("failed to update pull request comment")
+		}
+	}
+}
+
+func (g *GitSourceCommitStatusUpdater) updatePullRequestComment(ctx context.Context, gitSource gitsource.GitSource, commentSource gitsource.PullRequestCommentSource, project *csapitypes.Project, run *rsapitypes.RunResponse) error {
+	// the pull request id annotation could be the git source global pull
+	// request id, get the pull request number from the run ref
+	refType, prNumber, err := gitSource.RefType(run.Run.Annotations[action.AnnotationRef])
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if refType != gitsource.RefTypePullRequest {
+		return errors.Errorf("run ref %q isn't a pull request ref", run.Run.Annotations[action.AnnotationRef])
+	}
+
+	commitSHA := run.Run.Annotations[action.AnnotationCommitSHA]
+
+	runs, err := g.commitRunReports(ctx, project, run)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	marker := pullRequestCommentMarker(g.clusterID, project.ID)
+	body := genPullRequestComment(marker, project.Name, commitSHA, runs)
+
+	delivered, err := commentSource.UpdatePullRequestComment(project.RepositoryPath, prNumber, marker, body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !delivered {
+		return errors.Errorf("pull request %s comment not delivered", prNumber)
+	}
+
+	return nil
+}
+
+// commitRunReports returns the reports of the latest run for every run name of
+// the run group with the same commit of the provided run
+func (g *GitSourceCommitStatusUpdater) commitRunReports(ctx context.Context, project *csapitypes.Project, run *rsapitypes.RunResponse) ([]*runReport, error) {
+	commitSHA := run.Run.Annotations[action.AnnotationCommitSHA]
+
+	groupRuns, _, err := g.runserviceClient.GetGroupRuns(ctx, run.Run.Group, &rsclient.GetGroupRunsOptions{
+		ListOptions: &rsclient.ListOptions{Limit: maxPullRequestGroupRuns, SortDirection: rstypes.SortDirectionDesc},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	runs := []*runReport{}
+	names := map[string]struct{}{}
+	for _, gr := range groupRuns.Runs {
+		if gr.Annotations[action.AnnotationCommitSHA] != commitSHA {
+			continue
+		}
+		if _, ok := names[gr.Name]; ok {
+			continue
+		}
+		names[gr.Name] = struct{}{}
+
+		rr := run
+		if gr.ID != run.Run.ID {
+			rr, _, err = g.runserviceClient.GetRun(ctx, gr.ID, nil)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+		}
+		r, err := newRunReport(g.c.WebExposedURL, project.ID, rr)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		runs = append(runs, r)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Name < runs[j].Name })
+
+	return runs, nil
+}
+
+// reviewVote returns the review vote for the runs of a commit: the commit is
+// rejected when a run didn't succeed and approved only when all the runs
+// succeeded
+func reviewVote(runs []*runReport) gitsource.ReviewVote {
+	vote := gitsource.ReviewVoteApprove
+	for _, r := range runs {
+		switch r.Status {
+		case string(rstypes.RunResultSuccess):
+		case string(rstypes.RunPhaseQueued), string(rstypes.RunPhaseRunning), string(rstypes.RunResultUnknown):
+			vote = gitsource.ReviewVoteNone
+		default:
+			return gitsource.ReviewVoteReject
+		}
+	}
+
+	return vote
+}
+
+// genReviewMessage generates the review message reporting the commit status
+// and the statuses of all the runs of the commit
+func genReviewMessage(commitStatus *types.CommitStatus, targetURL string, runs []*runReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n%s\n", commitStatus.Context, commitStatus.Description, targetURL)
+	if len(runs) > 0 {
+		b.WriteString("\nAgola runs:\n")
+		for _, r := range runs {
+			fmt.Fprintf(&b, "* %s: %s %s\n", r.Name, r.Status, r.URL)
+		}
+	}
+	return b.String()
+}
+
+// createReview reports the commit status of a pull request run as a pull
+// request review voting with the results of all the runs of the same commit
+func (g *GitSourceCommitStatusUpdater) createReview(ctx context.Context, gitSource gitsource.GitSource, reviewSource gitsource.ReviewSource, project *csapitypes.Project, commitStatus *types.CommitStatus, targetURL string) (bool, error) {
+	run, _, err := g.runserviceClient.GetRunByGroup(ctx, common.GenBaseRunGroup(common.GroupTypeProject, project.ID), commitStatus.RunCounter, nil)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to get run %d of project %s", commitStatus.RunCounter, project.ID)
+	}
+
+	refType, prID, err := gitSource.RefType(run.Run.Annotations[action.AnnotationRef])
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	if refType != gitsource.RefTypePullRequest {
+		return false, errors.Errorf("run ref %q isn't a pull request ref", run.Run.Annotations[action.AnnotationRef])
+	}
+
+	runs, err := g.commitRunReports(ctx, project, run)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	delivered, err := reviewSource.CreateReview(project.RepositoryPath, prID, reviewVote(runs), genReviewMessage(commitStatus, targetURL, runs))
+	return delivered, errors.WithStack(err)
+}
@@ -140,6 +140,7 @@ func (n *NotificationService) handleRunWebhookDelivery(ctx context.Context, runW
 		statusCode = resp.StatusCode
 	}
 
+	var deliveryStatus types.DeliveryStatus
 	err = n.d.Do(ctx, func(tx *sql.Tx) error {
 		var err error
 
@@ -156,6 +157,7 @@ func (n *NotificationService) handleRunWebhookDelivery(ctx context.Context, runW
 		} else {
 			runWebhookDelivery.DeliveryStatus = types.DeliveryStatusDeliveryError
 		}
+		deliveryStatus = runWebhookDelivery.DeliveryStatus
 
 		runWebhookDelivery.DeliveredAt = deliveredAt
 		runWebhookDelivery.StatusCode = statusCode
@@ -170,6 +172,10 @@ func (n *NotificationService) handleRunWebhookDelivery(ctx context.Context, runW
 		return errors.WithStack(err)
 	}
 
+	if deliveryStatus != "" {
+		runWebhookDeliveriesTotal.WithLabelValues(string(deliveryStatus)).Inc()
+	}
+
 	return nil
 }
 
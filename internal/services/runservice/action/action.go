@@ -24,6 +24,8 @@ import (
 	"github.com/gofrs/uuid/v5"
 	"github.com/rs/zerolog"
 	"github.com/sorintlab/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"agola.io/agola/internal/objectstorage"
 	"agola.io/agola/internal/runconfig"
@@ -31,7 +33,9 @@ import (
 	"agola.io/agola/internal/services/runservice/common"
 	"agola.io/agola/internal/services/runservice/db"
 	"agola.io/agola/internal/sqlg/lock"
+	"agola.io/agola/internal/sqlg/notify"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
 	"agola.io/agola/services/runservice/types"
 )
@@ -41,16 +45,18 @@ type ActionHandler struct {
 	d                    *db.DB
 	ost                  objectstorage.ObjStorage
 	lf                   lock.LockFactory
+	n                    notify.Notifier
 	maintenanceMode      bool
 	maintenanceModeMutex sync.Mutex
 }
 
-func NewActionHandler(log zerolog.Logger, d *db.DB, ost objectstorage.ObjStorage, lf lock.LockFactory) *ActionHandler {
+func NewActionHandler(log zerolog.Logger, d *db.DB, ost objectstorage.ObjStorage, lf lock.LockFactory, n notify.Notifier) *ActionHandler {
 	return &ActionHandler{
 		log:             log,
 		d:               d,
 		ost:             ost,
 		lf:              lf,
+		n:               n,
 		maintenanceMode: false,
 	}
 }
@@ -251,6 +257,12 @@ func (h *ActionHandler) ChangeRunPhase(ctx context.Context, req *RunChangePhaseR
 		if err := h.d.InsertRunEvent(tx, runEvent); err != nil {
 			return errors.WithStack(err)
 		}
+		if err := h.n.Notify(tx, common.RunsNotifyChannel, run.ID); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := h.n.Notify(tx, common.RunEventsNotifyChannel, run.ID); err != nil {
+			return errors.WithStack(err)
+		}
 
 		return nil
 	})
@@ -297,6 +309,9 @@ func (h *ActionHandler) StopRun(ctx context.Context, req *RunStopRequest) error
 		if err := h.d.UpdateRun(tx, r); err != nil {
 			return errors.WithStack(err)
 		}
+		if err := h.n.Notify(tx, common.RunsNotifyChannel, r.ID); err != nil {
+			return errors.WithStack(err)
+		}
 
 		return nil
 	})
@@ -333,7 +348,19 @@ func (h *ActionHandler) CreateRun(ctx context.Context, req *RunCreateRequest) (*
 		return nil, errors.WithStack(err)
 	}
 
+	// start a new trace for the run. The run creation is done inside the run
+	// span and the executor task spans will be children of the run span using
+	// the traceparent saved in the run annotations
+	rctx, span := tracing.Tracer().Start(ctx, "run", trace.WithNewRoot(), trace.WithLinks(trace.LinkFromContext(ctx)))
+	rb, err := h.createRun(rctx, req, runcgt)
+	tracing.EndSpan(span, "", err != nil)
+
+	return rb, err
+}
+
+func (h *ActionHandler) createRun(ctx context.Context, req *RunCreateRequest, runcgt *types.ChangeGroupsUpdateToken) (*types.RunBundle, error) {
 	var rb *types.RunBundle
+	var err error
 	if req.RunID == "" {
 		rb, err = h.newRun(ctx, req)
 	} else {
@@ -343,6 +370,18 @@ func (h *ActionHandler) CreateRun(ctx context.Context, req *RunCreateRequest) (*
 		return nil, errors.WithStack(err)
 	}
 
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("agola.run.id", rb.Run.ID),
+		attribute.String("agola.run.group", rb.Run.Group),
+	)
+
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" {
+		if rb.Run.Annotations == nil {
+			rb.Run.Annotations = map[string]string{}
+		}
+		rb.Run.Annotations[types.RunAnnotationTraceParent] = traceParent
+	}
+
 	return rb, h.saveRun(ctx, rb, runcgt)
 }
 
@@ -611,6 +650,12 @@ func (h *ActionHandler) saveRun(ctx context.Context, rb *types.RunBundle, runcgt
 		if err := h.d.InsertRunEvent(tx, runEvent); err != nil {
 			return errors.WithStack(err)
 		}
+		if err := h.n.Notify(tx, common.RunsNotifyChannel, run.ID); err != nil {
+			return errors.WithStack(err)
+		}
+		if err := h.n.Notify(tx, common.RunEventsNotifyChannel, run.ID); err != nil {
+			return errors.WithStack(err)
+		}
 
 		return nil
 	})
@@ -772,6 +817,9 @@ func (h *ActionHandler) ApproveRunTask(ctx context.Context, req *RunTaskApproveR
 		if err := h.d.UpdateRun(tx, r); err != nil {
 			return errors.WithStack(err)
 		}
+		if err := h.n.Notify(tx, common.RunsNotifyChannel, r.ID); err != nil {
+			return errors.WithStack(err)
+		}
 
 		return nil
 	})
@@ -0,0 +1,65 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+
+	"github.com/sorintlab/errors"
+
+	scommon "agola.io/agola/internal/services/common"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/manager"
+	"agola.io/agola/internal/util"
+)
+
+// GetBackups returns the database backups saved in the object storage.
+func (h *ActionHandler) GetBackups(ctx context.Context) ([]*scommon.Backup, error) {
+	backups, err := scommon.GetBackups(ctx, h.ost)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return backups, nil
+}
+
+// RestoreBackup restores a database backup saved in the object storage. The
+// service must be in maintenance mode and the database must be empty.
+// The restored runs logs and workspace archives are the ones already saved in
+// the object storage.
+func (h *ActionHandler) RestoreBackup(ctx context.Context, backupID string) error {
+	if !h.maintenanceMode {
+		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("not in maintenance mode"))
+	}
+
+	dbm := manager.NewDBManager(h.log, h.d, h.lf)
+
+	empty, err := dbm.IsEmpty(ctx, sqlg.ObjectNames(h.d.ObjectsInfo()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !empty {
+		return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("a backup can only be restored in an empty database"), serrors.DatabaseNotEmpty())
+	}
+
+	r, err := scommon.ReadBackup(ctx, h.ost, backupID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer r.Close()
+
+	return errors.WithStack(h.Import(ctx, r))
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/objectstorage"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/runservice/store"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tasklog"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/runservice/types"
+)
+
+const (
+	DefaultRunLogsSearchLimit = 100
+	MaxRunLogsSearchLimit     = 1000
+	MaxRunLogsSearchContext   = 10
+)
+
+type RunLogsSearchRequest struct {
+	RunID string
+	// TaskIDs limits the search to the provided run tasks
+	TaskIDs []string
+
+	Pattern    string
+	Regex      bool
+	IgnoreCase bool
+
+	// Context is the number of lines to return before and after a matching line
+	Context int
+	// Limit is the maximum number of matches to return
+	Limit int
+}
+
+type RunLogsSearchMatch struct {
+	TaskID string
+	Step   int
+	*tasklog.Match
+}
+
+type RunLogsSearchResult struct {
+	Matches []*RunLogsSearchMatch
+	// Truncated is true when the search was stopped because the limit was
+	// reached
+	Truncated bool
+}
+
+func logsSearchMatcher(req *RunLogsSearchRequest) (func(string) bool, error) {
+	if req.Regex {
+		pattern := req.Pattern
+		if req.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("invalid regular expression %q", req.Pattern))
+		}
+		return re.MatchString, nil
+	}
+
+	if req.IgnoreCase {
+		pattern := strings.ToLower(req.Pattern)
+		return func(s string) bool { return strings.Contains(strings.ToLower(s), pattern) }, nil
+	}
+	return func(s string) bool { return strings.Contains(s, req.Pattern) }, nil
+}
+
+// SearchRunLogs searches the archived step logs of the run tasks. The logs
+// are read from the object storage line by line, so only the matching lines
+// and their context are kept in memory. The step logs not yet archived aren't
+// searched.
+func (h *ActionHandler) SearchRunLogs(ctx context.Context, req *RunLogsSearchRequest) (*RunLogsSearchResult, error) {
+	if req.Pattern == "" {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsg("empty search pattern"))
+	}
+	if req.Context < 0 || req.Context > MaxRunLogsSearchContext {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("context must be between 0 and %d", MaxRunLogsSearchContext))
+	}
+	if req.Limit < 0 || req.Limit > MaxRunLogsSearchLimit {
+		return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("limit must be between 0 and %d", MaxRunLogsSearchLimit))
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultRunLogsSearchLimit
+	}
+
+	match, err := logsSearchMatcher(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var r *types.Run
+	var rc *types.RunConfig
+	err = h.d.Do(ctx, func(tx *sql.Tx) error {
+		var err error
+		r, err = h.d.GetRun(tx, req.RunID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if r == nil {
+			return util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("run %q doesn't exist", req.RunID), serrors.RunDoesNotExist())
+		}
+
+		rc, err = h.d.GetRunConfig(tx, r.RunConfigID)
+		return errors.WithStack(err)
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, taskID := range req.TaskIDs {
+		if _, ok := r.Tasks[taskID]; !ok {
+			return nil, util.NewAPIError(util.ErrNotExist, util.WithAPIErrorMsgf("no such task with ID %s in run %s", taskID, req.RunID), serrors.RunTaskDoesNotExist())
+		}
+	}
+
+	// search the tasks ordered by level and name
+	tasks := []*types.RunConfigTask{}
+	for _, rct := range rc.Tasks {
+		if len(req.TaskIDs) > 0 && !slices.Contains(req.TaskIDs, rct.ID) {
+			continue
+		}
+		tasks = append(tasks, rct)
+	}
+	slices.SortFunc(tasks, func(a, b *types.RunConfigTask) int {
+		if a.Level != b.Level {
+			return a.Level - b.Level
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	res := &RunLogsSearchResult{Matches: []*RunLogsSearchMatch{}}
+	for _, rct := range tasks {
+		rt, ok := r.Tasks[rct.ID]
+		if !ok {
+			continue
+		}
+		for step, rts := range rt.Steps {
+			if rts.LogPhase != types.RunTaskFetchPhaseFinished {
+				continue
+			}
+
+			stop, err := h.searchStepLog(ctx, rt.ID, step, match, req.Context, func(m *tasklog.Match) bool {
+				if len(res.Matches) == limit {
+					res.Truncated = true
+					return false
+				}
+				res.Matches = append(res.Matches, &RunLogsSearchMatch{TaskID: rt.ID, Step: step, Match: m})
+				return true
+			})
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if stop {
+				return res, nil
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// searchStepLog searches a step log saved in the object storage. It returns
+// true if the search was stopped by f.
+func (h *ActionHandler) searchStepLog(ctx context.Context, rtID string, step int, match func(string) bool, contextLines int, f func(m *tasklog.Match) bool) (bool, error) {
+	opts := &tasklog.SearchOptions{Match: match, Context: contextLines}
+
+	lf, err := h.ost.ReadObject(ctx, store.OSTRunTaskStepStructuredLogPath(rtID, step))
+	if err != nil {
+		if !objectstorage.IsNotExist(err) {
+			return false, errors.WithStack(err)
+		}
+		// fallback to the raw text log saved by older versions
+		lf, err = h.ost.ReadObject(ctx, store.OSTRunTaskStepLogPath(rtID, step))
+		if err != nil {
+			// the log could have been deleted
+			if objectstorage.IsNotExist(err) {
+				return false, nil
+			}
+			return false, errors.WithStack(err)
+		}
+		opts.Raw = true
+	}
+	defer lf.Close()
+
+	stopped := false
+	err = tasklog.Search(lf, opts, func(m *tasklog.Match) bool {
+		if !f(m) {
+			stopped = true
+			return false
+		}
+		return true
+	})
+
+	return stopped, errors.WithStack(err)
+}
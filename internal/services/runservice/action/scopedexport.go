@@ -0,0 +1,623 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package action
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/objectstorage"
+	serrors "agola.io/agola/internal/services/errors"
+	"agola.io/agola/internal/services/runservice/store"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/runservice/types"
+)
+
+const (
+	// ostObjectExportKind is the export kind of the object storage objects
+	// (run tasks logs and workspace archives) of a scoped export.
+	ostObjectExportKind = "OSTObject"
+	// ostObjectChunkExportKind is the export kind of the data chunks of an
+	// object storage object. The chunks follow their object entry.
+	ostObjectChunkExportKind = "OSTObjectChunk"
+
+	// ostObjectExportChunkSize is the max size of an object storage object
+	// data chunk.
+	ostObjectExportChunkSize = 1024 * 1024
+
+	scopedExportRunsLimit = 100
+)
+
+// scopedExportKinds are the db object kinds that can be part of a scoped
+// export.
+var scopedExportKinds = map[string]struct{}{
+	"RunCounter": {},
+	"RunConfig":  {},
+	"Run":        {},
+}
+
+type ostObjectExport struct {
+	ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+type ostObjectChunkExport struct {
+	ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+
+	Data []byte `json:"data"`
+}
+
+// GetScopedExportObjects returns the archived runs of the provided run groups
+// (and of their child groups) with their run configs and the run counters of
+// the groups.
+// Not archived runs are skipped since their logs and workspace archives
+// aren't yet saved in the object storage.
+func (h *ActionHandler) GetScopedExportObjects(ctx context.Context, groups []string) ([]sqlg.Object, error) {
+	var runCounters []*types.RunCounter
+	var runs []*types.Run
+	var runConfigs map[string]*types.RunConfig
+
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		runCounters = nil
+		runs = nil
+		runConfigs = map[string]*types.RunConfig{}
+
+		runCountersGroupIDs := map[string]struct{}{}
+		runsIDs := map[string]struct{}{}
+		for _, group := range groups {
+			runCounterGroupID, err := h.getRunCounterGroupID(group)
+			if err != nil {
+				return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid run group %q", group), serrors.InvalidRunGroup())
+			}
+
+			if _, ok := runCountersGroupIDs[runCounterGroupID]; !ok {
+				runCounter, err := h.d.GetRunCounter(tx, runCounterGroupID)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				if runCounter != nil {
+					runCounters = append(runCounters, runCounter)
+				}
+				runCountersGroupIDs[runCounterGroupID] = struct{}{}
+			}
+
+			var startRunCounter uint64
+			for {
+				groupRuns, err := h.d.GetGroupRuns(tx, group, nil, nil, startRunCounter, scopedExportRunsLimit, types.SortDirectionAsc)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+
+				for _, run := range groupRuns {
+					startRunCounter = run.Counter
+
+					if !run.Archived {
+						continue
+					}
+					// skip runs already exported by a parent group
+					if _, ok := runsIDs[run.ID]; ok {
+						continue
+					}
+
+					runConfig, err := h.d.GetRunConfig(tx, run.RunConfigID)
+					if err != nil {
+						return errors.WithStack(err)
+					}
+					if runConfig == nil {
+						return errors.Errorf("run config %q of run %q doesn't exist", run.RunConfigID, run.ID)
+					}
+
+					runs = append(runs, run)
+					runsIDs[run.ID] = struct{}{}
+					runConfigs[runConfig.ID] = runConfig
+				}
+
+				if len(groupRuns) < scopedExportRunsLimit {
+					break
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// keep the runs order on import
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Sequence < runs[j].Sequence })
+
+	var objs []sqlg.Object
+	for _, runCounter := range runCounters {
+		objs = append(objs, runCounter)
+	}
+	for _, run := range runs {
+		objs = append(objs, runConfigs[run.RunConfigID], run)
+	}
+
+	return objs, nil
+}
+
+// WriteScopedExport writes the provided objects using the export format
+// followed by the object storage logs and workspace archives of the runs
+// tasks. Every object storage object is streamed as an object entry followed
+// by its data chunks entries.
+func (h *ActionHandler) WriteScopedExport(ctx context.Context, objs []sqlg.Object, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e := json.NewEncoder(bw)
+
+	var runs []*types.Run
+	for _, obj := range objs {
+		if err := h.d.ObjectToExportJSON(obj, e); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if run, ok := obj.(*types.Run); ok {
+			runs = append(runs, run)
+		}
+	}
+
+	for _, run := range runs {
+		for _, rt := range run.Tasks {
+			for _, dir := range []string{store.OSTRunTaskLogsBaseDir(rt.ID), store.OSTRunTaskArchivesBaseDir(rt.ID)} {
+				for object := range h.ost.List(ctx, dir+"/", "", true) {
+					if object.Err != nil {
+						return errors.WithStack(object.Err)
+					}
+
+					if err := h.writeOSTObjectExport(ctx, object, e); err != nil {
+						return errors.WithStack(err)
+					}
+				}
+			}
+		}
+	}
+
+	return errors.WithStack(bw.Flush())
+}
+
+func (h *ActionHandler) writeOSTObjectExport(ctx context.Context, object objectstorage.ObjectInfo, e *json.Encoder) error {
+	f, err := h.ost.ReadObject(ctx, object.Path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	if err := e.Encode(&ostObjectExport{ExportMeta: sqlg.ExportMeta{Kind: ostObjectExportKind}, Path: object.Path, Size: object.Size}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	buf := make([]byte, ostObjectExportChunkSize)
+	var size int64
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			size += int64(n)
+			if size > object.Size {
+				return errors.Errorf("object %q is bigger than its listed size %d", object.Path, object.Size)
+			}
+			if err := e.Encode(&ostObjectChunkExport{ExportMeta: sqlg.ExportMeta{Kind: ostObjectChunkExportKind}, Data: buf[:n]}); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if size != object.Size {
+		return errors.Errorf("object %q size %d doesn't match its listed size %d", object.Path, size, object.Size)
+	}
+
+	return nil
+}
+
+type ScopedImportRequest struct {
+	// GroupsRemap maps the exported run groups to the run groups where their
+	// runs will be imported. Child groups are remapped too.
+	GroupsRemap map[string]string
+	// AnnotationsRemap maps, for every annotation name, the exported runs
+	// annotation values to the new ones.
+	AnnotationsRemap map[string]map[string]string
+}
+
+// ImportScoped imports the runs exported by WriteScopedExport.
+// The imported runs, run configs and run tasks get new IDs and their groups
+// and annotations are remapped as requested. The import fails without
+// changes if a run with the same counter already exists in the remapped
+// group. The run tasks logs and workspace archives are saved in the object
+// storage before the runs are imported and are removed if the import fails.
+func (h *ActionHandler) ImportScoped(ctx context.Context, req *ScopedImportRequest, r io.Reader) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	var runCounters []*types.RunCounter
+	var runs []*types.Run
+	runConfigs := map[string]*types.RunConfig{}
+
+	var ostObject *ostObjectExport
+	for {
+		var jobj json.RawMessage
+
+		err := dec.Decode(&jobj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+
+		var om struct {
+			ExportMeta sqlg.ExportMeta `json:"exportMeta"`
+		}
+		if err := json.Unmarshal(jobj, &om); err != nil {
+			return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+
+		// the object storage objects are after all the db objects
+		if om.ExportMeta.Kind == ostObjectExportKind {
+			ostObject = &ostObjectExport{}
+			if err := json.Unmarshal(jobj, ostObject); err != nil {
+				return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+			}
+			break
+		}
+
+		if _, ok := scopedExportKinds[om.ExportMeta.Kind]; !ok {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("unexpected object kind %q in scoped export", om.ExportMeta.Kind), serrors.InvalidExportData())
+		}
+
+		obj, err := h.d.UnmarshalExportObject(jobj)
+		if err != nil {
+			return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+
+		switch o := obj.(type) {
+		case *types.RunCounter:
+			runCounters = append(runCounters, o)
+		case *types.RunConfig:
+			runConfigs[o.ID] = o
+		case *types.Run:
+			runs = append(runs, o)
+		}
+	}
+
+	ids, err := remapScopedImportRuns(req, runs, runConfigs)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	runsConfigs := map[string]*types.RunConfig{}
+	for _, runConfig := range runConfigs {
+		runsConfigs[runConfig.ID] = runConfig
+	}
+
+	runCountersGroupIDs := map[string]string{}
+	for group, newGroup := range req.GroupsRemap {
+		runCounterGroupID, err := h.getRunCounterGroupID(group)
+		if err != nil {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid run group %q", group), serrors.InvalidRunGroup())
+		}
+		newRunCounterGroupID, err := h.getRunCounterGroupID(newGroup)
+		if err != nil {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid run group %q", newGroup), serrors.InvalidRunGroup())
+		}
+		runCountersGroupIDs[runCounterGroupID] = newRunCounterGroupID
+	}
+
+	// check the conflicting runs before saving the object storage objects
+	err = h.d.Do(ctx, func(tx *sql.Tx) error {
+		return errors.WithStack(h.checkScopedImportRuns(tx, runs))
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	// the object storage objects are after the db objects in the export so
+	// save them before inserting the runs
+	ostPaths, err := h.importScopedOSTObjects(ctx, dec, ostObject, ids)
+	if err == nil {
+		err = h.insertScopedImportRuns(ctx, runs, runsConfigs, runCounters, runCountersGroupIDs)
+	}
+	if err != nil {
+		for _, p := range ostPaths {
+			if derr := h.ost.DeleteObject(context.WithoutCancel(ctx), p); derr != nil {
+				h.log.Err(derr).Msgf("failed to delete imported object storage object %q", p)
+			}
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (h *ActionHandler) checkScopedImportRuns(tx *sql.Tx, runs []*types.Run) error {
+	for _, run := range runs {
+		pl := util.PathList(run.Group)
+		if len(pl) < 2 {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("invalid run group %q", run.Group), serrors.InvalidRunGroup())
+		}
+		curRun, err := h.d.GetRunByGroup(tx, "/"+path.Join(pl[0], pl[1]), run.Counter)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if curRun != nil {
+			return util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("run with counter %d in group %q already exists", run.Counter, run.Group), serrors.RunAlreadyExists())
+		}
+	}
+
+	return nil
+}
+
+func (h *ActionHandler) insertScopedImportRuns(ctx context.Context, runs []*types.Run, runsConfigs map[string]*types.RunConfig, runCounters []*types.RunCounter, runCountersGroupIDs map[string]string) error {
+	err := h.d.Do(ctx, func(tx *sql.Tx) error {
+		if err := h.checkScopedImportRuns(tx, runs); err != nil {
+			return errors.WithStack(err)
+		}
+
+		for _, runCounter := range runCounters {
+			groupID := runCounter.GroupID
+			if newGroupID, ok := runCountersGroupIDs[groupID]; ok {
+				groupID = newGroupID
+			}
+
+			curRunCounter, err := h.d.GetRunCounter(tx, groupID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if curRunCounter == nil {
+				curRunCounter = types.NewRunCounter(tx, groupID)
+			}
+			if curRunCounter.Value >= runCounter.Value {
+				continue
+			}
+			curRunCounter.Value = runCounter.Value
+
+			if err := h.d.InsertOrUpdateRunCounter(tx, curRunCounter); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		for _, run := range runs {
+			if err := h.d.InsertRawObject(tx, runsConfigs[run.RunConfigID]); err != nil {
+				return errors.WithStack(err)
+			}
+
+			// insert the run as a new one to get a new sequence
+			run.Revision = 0
+			run.TxID = tx.ID()
+			if err := h.d.InsertRun(tx, run); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// importScopedOSTObjects saves the object storage objects starting from the
+// provided one. It returns the paths of the saved objects, also on error.
+func (h *ActionHandler) importScopedOSTObjects(ctx context.Context, dec *json.Decoder, ostObject *ostObjectExport, ids map[string]string) ([]string, error) {
+	var paths []string
+	for ostObject != nil {
+		p, err := remapOSTPath(ostObject.Path, ids)
+		if err != nil {
+			return paths, errors.WithStack(err)
+		}
+		if ostObject.Size < 0 {
+			return paths, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("object storage object %q has a wrong size %d", ostObject.Path, ostObject.Size), serrors.InvalidExportData())
+		}
+
+		cr := &ostObjectChunksReader{dec: dec, remaining: ostObject.Size}
+		paths = append(paths, p)
+		if err := h.ost.WriteObject(ctx, p, cr, ostObject.Size, true); err != nil {
+			if cr.err != nil {
+				return paths, errors.WithStack(cr.err)
+			}
+			return paths, errors.WithStack(err)
+		}
+		if cr.remaining != 0 || len(cr.data) != 0 {
+			return paths, errors.Errorf("object storage object %q data not fully saved", ostObject.Path)
+		}
+
+		var jobj json.RawMessage
+		err = dec.Decode(&jobj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return paths, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+
+		ostObject = &ostObjectExport{}
+		if err := json.Unmarshal(jobj, ostObject); err != nil {
+			return paths, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+		}
+		if ostObject.ExportMeta.Kind != ostObjectExportKind {
+			return paths, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("unexpected object kind %q after object storage objects", ostObject.ExportMeta.Kind), serrors.InvalidExportData())
+		}
+	}
+
+	return paths, nil
+}
+
+// ostObjectChunksReader reads the data of an object storage object from its
+// chunks entries.
+type ostObjectChunksReader struct {
+	dec       *json.Decoder
+	remaining int64
+	data      []byte
+	err       error
+}
+
+func (r *ostObjectChunksReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	for len(r.data) == 0 {
+		if r.remaining == 0 {
+			return 0, io.EOF
+		}
+
+		chunk := &ostObjectChunkExport{}
+		if err := r.dec.Decode(chunk); err != nil {
+			r.err = util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("failed to decode export data"), serrors.InvalidExportData())
+			return 0, r.err
+		}
+		if chunk.ExportMeta.Kind != ostObjectChunkExportKind || len(chunk.Data) == 0 || int64(len(chunk.Data)) > r.remaining {
+			r.err = util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("unexpected %q object storage object chunk entry", chunk.ExportMeta.Kind), serrors.InvalidExportData())
+			return 0, r.err
+		}
+
+		r.remaining -= int64(len(chunk.Data))
+		r.data = chunk.Data
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
+
+// remapScopedImportRuns assigns new ids to the runs, run configs and run tasks
+// and remaps their groups and annotations. It returns the mapping between the
+// exported and the new ids.
+func remapScopedImportRuns(req *ScopedImportRequest, runs []*types.Run, runConfigs map[string]*types.RunConfig) (map[string]string, error) {
+	ids := map[string]string{}
+	newID := func(id string) string {
+		ids[id] = uuid.Must(uuid.NewV4()).String()
+		return ids[id]
+	}
+
+	remapAnnotations := func(annotations map[string]string) {
+		for k, values := range req.AnnotationsRemap {
+			cur, ok := annotations[k]
+			if !ok {
+				continue
+			}
+			if v, ok := values[cur]; ok {
+				annotations[k] = v
+			}
+		}
+	}
+
+	for _, run := range runs {
+		runConfig, ok := runConfigs[run.RunConfigID]
+		if !ok {
+			return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("run %q run config doesn't exist", run.ID), serrors.InvalidExportData())
+		}
+
+		for _, rct := range runConfig.Tasks {
+			newID(rct.ID)
+		}
+
+		tasks := map[string]*types.RunConfigTask{}
+		for _, rct := range runConfig.Tasks {
+			rct.ID = ids[rct.ID]
+
+			depends := map[string]*types.RunConfigTaskDepend{}
+			for _, d := range rct.Depends {
+				taskID, ok := ids[d.TaskID]
+				if !ok {
+					return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("run config %q task %q depends on a not existing task", runConfig.ID, rct.Name), serrors.InvalidExportData())
+				}
+				d.TaskID = taskID
+				depends[d.TaskID] = d
+			}
+			rct.Depends = depends
+
+			tasks[rct.ID] = rct
+		}
+		runConfig.Tasks = tasks
+
+		runTasks := map[string]*types.RunTask{}
+		for _, rt := range run.Tasks {
+			taskID, ok := ids[rt.ID]
+			if !ok {
+				return nil, util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("run %q task %q doesn't exist in its run config", run.ID, rt.ID), serrors.InvalidExportData())
+			}
+			rt.ID = taskID
+			runTasks[rt.ID] = rt
+		}
+		run.Tasks = runTasks
+
+		runConfig.ID = newID(runConfig.ID)
+		runConfig.Group = remapGroup(req.GroupsRemap, runConfig.Group)
+		remapAnnotations(runConfig.Annotations)
+
+		run.ID = newID(run.ID)
+		run.RunConfigID = runConfig.ID
+		run.Group = remapGroup(req.GroupsRemap, run.Group)
+		remapAnnotations(run.Annotations)
+	}
+
+	return ids, nil
+}
+
+func remapGroup(groupsRemap map[string]string, group string) string {
+	for oldGroup, newGroup := range groupsRemap {
+		if group == oldGroup {
+			return newGroup
+		}
+		if strings.HasPrefix(group, oldGroup+"/") {
+			return newGroup + strings.TrimPrefix(group, oldGroup)
+		}
+	}
+
+	return group
+}
+
+// remapOSTPath remaps the run task id and the run id of a run task logs or
+// workspace archives path.
+func remapOSTPath(p string, ids map[string]string) (string, error) {
+	pl := util.PathList(p)
+	if len(pl) < 3 || (pl[0] != store.OSTLogsBaseDir() && pl[0] != store.OSTArchivesBaseDir()) {
+		return "", util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("unexpected object storage path %q", p), serrors.InvalidExportData())
+	}
+
+	rtID, ok := ids[pl[1]]
+	if !ok {
+		return "", util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("object storage path %q doesn't belong to an imported run task", p), serrors.InvalidExportData())
+	}
+	pl[1] = rtID
+
+	if pl[2] == "runs" && len(pl) == 4 {
+		runID, ok := ids[pl[3]]
+		if !ok {
+			return "", util.NewAPIError(util.ErrBadRequest, util.WithAPIErrorMsgf("object storage path %q doesn't belong to an imported run", p), serrors.InvalidExportData())
+		}
+		pl[3] = runID
+	}
+
+	return path.Join(pl...), nil
+}
@@ -31,9 +31,12 @@ import (
 	"agola.io/agola/internal/objectstorage"
 	serrors "agola.io/agola/internal/services/errors"
 	"agola.io/agola/internal/services/runservice/action"
+	"agola.io/agola/internal/services/runservice/common"
 	"agola.io/agola/internal/services/runservice/db"
 	"agola.io/agola/internal/services/runservice/store"
+	"agola.io/agola/internal/sqlg/notify"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tasklog"
 	"agola.io/agola/internal/util"
 	rsapitypes "agola.io/agola/services/runservice/api/types"
 	"agola.io/agola/services/runservice/types"
@@ -41,6 +44,10 @@ import (
 
 const (
 	agolaHasMoreHeader = "X-Agola-HasMore"
+
+	// runEventsCheckInterval is the interval between run events checks when
+	// no new run events notifications are received
+	runEventsCheckInterval = 10 * time.Second
 )
 
 type requestOptions struct {
@@ -143,7 +150,12 @@ func (h *LogsHandler) do(w http.ResponseWriter, r *http.Request) error {
 		follow = true
 	}
 
-	if sendError, err := h.readTaskLogs(ctx, runID, taskID, setup, step, w, follow); err != nil {
+	opts, err := tasklog.ParseCopyOptions(q)
+	if err != nil {
+		return util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsg("invalid log options"))
+	}
+
+	if sendError, err := h.readTaskLogs(ctx, runID, taskID, setup, step, w, follow, opts); err != nil {
 		h.log.Err(err).Send()
 		if sendError {
 			switch {
@@ -158,7 +170,7 @@ func (h *LogsHandler) do(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
-func (h *LogsHandler) readTaskLogs(ctx context.Context, runID, taskID string, setup bool, step int, w http.ResponseWriter, follow bool) (bool, error) {
+func (h *LogsHandler) readTaskLogs(ctx context.Context, runID, taskID string, setup bool, step int, w http.ResponseWriter, follow bool, opts *tasklog.CopyOptions) (bool, error) {
 	var r *types.Run
 	err := h.d.Do(ctx, func(tx *sql.Tx) error {
 		var err error
@@ -186,21 +198,7 @@ func (h *LogsHandler) readTaskLogs(ctx context.Context, runID, taskID string, se
 
 	// if the log has been already fetched use it, otherwise fetch it from the executor
 	if task.Steps[step].LogPhase == types.RunTaskFetchPhaseFinished {
-		var logPath string
-		if setup {
-			logPath = store.OSTRunTaskSetupLogPath(task.ID)
-		} else {
-			logPath = store.OSTRunTaskStepLogPath(task.ID, step)
-		}
-		f, err := h.ost.ReadObject(ctx, logPath)
-		if err != nil {
-			if objectstorage.IsNotExist(err) {
-				return true, util.NewAPIErrorWrap(util.ErrNotExist, err)
-			}
-			return true, errors.WithStack(err)
-		}
-		defer f.Close()
-		return false, sendLogs(w, f)
+		return h.readStoredLogs(ctx, task.ID, setup, step, w, opts)
 	}
 
 	var et *types.ExecutorTask
@@ -230,16 +228,23 @@ func (h *LogsHandler) readTaskLogs(ctx context.Context, runID, taskID string, se
 		return true, errors.WithStack(err)
 	}
 
-	var url string
+	// always request the structured log and convert it to the requested
+	// format
+	eq := url.Values{}
+	eq.Set("taskid", et.ID)
 	if setup {
-		url = fmt.Sprintf("%s/api/v1alpha/executor/logs?taskid=%s&setup", executor.ListenURL, et.ID)
+		eq.Set("setup", "")
 	} else {
-		url = fmt.Sprintf("%s/api/v1alpha/executor/logs?taskid=%s&step=%d", executor.ListenURL, et.ID, step)
+		eq.Set("step", strconv.Itoa(step))
 	}
 	if follow {
-		url += "&follow"
+		eq.Set("follow", "")
 	}
-	req, err := http.Get(url)
+	eopts := *opts
+	eopts.Format = tasklog.FormatNDJSON
+	eopts.Add(eq)
+
+	req, err := http.Get(fmt.Sprintf("%s/api/v1alpha/executor/logs?%s", executor.ListenURL, eq.Encode()))
 	if err != nil {
 		return true, errors.WithStack(err)
 	}
@@ -255,49 +260,86 @@ func (h *LogsHandler) readTaskLogs(ctx context.Context, runID, taskID string, se
 	// header also if there're currently no lines to send
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+	w.Header().Set("Content-Type", opts.Format.ContentType())
 	w.WriteHeader(http.StatusOK)
-	var flusher http.Flusher
 	if fl, ok := w.(http.Flusher); ok {
-		flusher = fl
-	}
-	if flusher != nil {
-		flusher.Flush()
+		fl.Flush()
 	}
 
-	return false, sendLogs(w, req.Body)
+	// older executors only provide raw text logs
+	if req.Header.Get("Content-Type") != tasklog.NDJSONContentType {
+		return false, errors.WithStack(tasklog.CopyRaw(w, req.Body, opts))
+	}
+	return false, errors.WithStack(tasklog.Copy(w, req.Body, opts))
 }
 
-func sendLogs(w http.ResponseWriter, r io.Reader) error {
-	buf := make([]byte, 406)
-
-	var flusher http.Flusher
-	if fl, ok := w.(http.Flusher); ok {
-		flusher = fl
+// readStoredLogs sends a log saved in the object storage. If the log has an
+// index it's used to start reading from the nearest indexed line.
+func (h *LogsHandler) readStoredLogs(ctx context.Context, taskID string, setup bool, step int, w http.ResponseWriter, opts *tasklog.CopyOptions) (bool, error) {
+	var logPath, rawLogPath string
+	if setup {
+		logPath = store.OSTRunTaskSetupStructuredLogPath(taskID)
+		rawLogPath = store.OSTRunTaskSetupLogPath(taskID)
+	} else {
+		logPath = store.OSTRunTaskStepStructuredLogPath(taskID, step)
+		rawLogPath = store.OSTRunTaskStepLogPath(taskID, step)
 	}
-	stop := false
-	for {
-		if stop {
-			return nil
+
+	raw := false
+	f, err := h.ost.ReadObject(ctx, logPath)
+	if err != nil {
+		if !objectstorage.IsNotExist(err) {
+			return true, errors.WithStack(err)
 		}
-		n, err := r.Read(buf)
-		//data, err := br.ReadBytes('\n')
+		// fallback to the raw text log saved by older versions
+		raw = true
+		f, err = h.ost.ReadObject(ctx, rawLogPath)
 		if err != nil {
-			if err != io.EOF {
-				return errors.WithStack(err)
-			}
-			if n == 0 {
-				return nil
+			if objectstorage.IsNotExist(err) {
+				return true, util.NewAPIErrorWrap(util.ErrNotExist, err)
 			}
-			stop = true
+			return true, errors.WithStack(err)
 		}
-		if _, err := w.Write(buf[:n]); err != nil {
-			return errors.WithStack(err)
+	}
+	defer f.Close()
+
+	if !raw && opts.Start > 0 {
+		offset, err := h.readLogIndexOffset(ctx, logPath, opts.Start)
+		if err != nil {
+			return true, errors.WithStack(err)
 		}
-		if flusher != nil {
-			flusher.Flush()
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return true, errors.WithStack(err)
+		}
+	}
+
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+
+	if raw {
+		return false, errors.WithStack(tasklog.CopyRaw(w, f, opts))
+	}
+	return false, errors.WithStack(tasklog.Copy(w, f, opts))
+}
+
+// readLogIndexOffset returns the offset from where to start reading the log
+// to reach line start. If the log index doesn't exist the log will be read
+// from the start.
+func (h *LogsHandler) readLogIndexOffset(ctx context.Context, logPath string, start uint64) (int64, error) {
+	f, err := h.ost.ReadObject(ctx, store.OSTRunTaskLogIndexPath(logPath))
+	if err != nil {
+		if objectstorage.IsNotExist(err) {
+			return 0, nil
 		}
+		return 0, errors.WithStack(err)
+	}
+	defer f.Close()
+
+	var index *tasklog.Index
+	if err := json.NewDecoder(f).Decode(&index); err != nil {
+		return 0, errors.WithStack(err)
 	}
+
+	return index.Offset(start), nil
 }
 
 type LogsDeleteHandler struct {
@@ -398,18 +440,31 @@ func (h *LogsDeleteHandler) deleteTaskLogs(ctx context.Context, runID, taskID st
 	}
 
 	if task.Steps[step].LogPhase == types.RunTaskFetchPhaseFinished {
-		var logPath string
+		var logPath, rawLogPath string
 		if setup {
-			logPath = store.OSTRunTaskSetupLogPath(task.ID)
+			logPath = store.OSTRunTaskSetupStructuredLogPath(task.ID)
+			rawLogPath = store.OSTRunTaskSetupLogPath(task.ID)
 		} else {
-			logPath = store.OSTRunTaskStepLogPath(task.ID, step)
+			logPath = store.OSTRunTaskStepStructuredLogPath(task.ID, step)
+			rawLogPath = store.OSTRunTaskStepLogPath(task.ID, step)
 		}
-		err := h.ost.DeleteObject(ctx, logPath)
-		if err != nil {
-			if objectstorage.IsNotExist(err) {
-				return util.NewAPIErrorWrap(util.ErrNotExist, err)
+		// delete the structured log, its index and the raw text log saved by
+		// older versions
+		var notExistErr error
+		deleted := false
+		for _, p := range []string{store.OSTRunTaskLogIndexPath(logPath), logPath, rawLogPath} {
+			err := h.ost.DeleteObject(ctx, p)
+			if err != nil {
+				if objectstorage.IsNotExist(err) {
+					notExistErr = err
+					continue
+				}
+				return errors.WithStack(err)
 			}
-			return errors.WithStack(err)
+			deleted = true
+		}
+		if !deleted {
+			return util.NewAPIErrorWrap(util.ErrNotExist, notExistErr)
 		}
 		return nil
 	}
@@ -1006,13 +1061,15 @@ type RunEventsHandler struct {
 	log zerolog.Logger
 	d   *db.DB
 	ost objectstorage.ObjStorage
+	n   notify.Notifier
 }
 
-func NewRunEventsHandler(log zerolog.Logger, d *db.DB, ost objectstorage.ObjStorage) *RunEventsHandler {
+func NewRunEventsHandler(log zerolog.Logger, d *db.DB, ost objectstorage.ObjStorage, n notify.Notifier) *RunEventsHandler {
 	return &RunEventsHandler{
 		log: log,
 		d:   d,
 		ost: ost,
+		n:   n,
 	}
 }
 
@@ -1058,7 +1115,11 @@ func (h *RunEventsHandler) sendRunEvents(ctx context.Context, afterRunEventSeque
 		flusher = fl
 	}
 
-	// TODO(sgotti) use a notify system instead of polling the database
+	// subscribe before reading the last run event to not miss new run events
+	c, err := h.n.Subscribe(ctx, common.RunEventsNotifyChannel)
+	if err != nil {
+		return errors.WithStack(err)
+	}
 
 	curEventSequence := afterRunEventSequence
 
@@ -1117,7 +1178,20 @@ func (h *RunEventsHandler) sendRunEvents(ctx context.Context, afterRunEventSeque
 		}
 
 		if len(runEvents) < MaxRunEventsLimit {
-			time.Sleep(1 * time.Second)
+			// wait for new run events notifications and periodically check
+			// for new run events to handle lost notifications
+			timer := time.NewTimer(runEventsCheckInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case _, ok := <-c:
+				timer.Stop()
+				if !ok {
+					return nil
+				}
+			case <-timer.C:
+			}
 		}
 	}
 }
@@ -0,0 +1,93 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/runservice/action"
+	"agola.io/agola/internal/util"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+)
+
+type BackupsHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewBackupsHandler(log zerolog.Logger, ah *action.ActionHandler) *BackupsHandler {
+	return &BackupsHandler{log: log, ah: ah}
+}
+
+func (h *BackupsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *BackupsHandler) do(r *http.Request) ([]*rsapitypes.Backup, error) {
+	ctx := r.Context()
+
+	backups, err := h.ah.GetBackups(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	res := make([]*rsapitypes.Backup, len(backups))
+	for i, backup := range backups {
+		res[i] = &rsapitypes.Backup{ID: backup.ID, Time: backup.Time, Size: backup.Size}
+	}
+
+	return res, nil
+}
+
+type RestoreBackupHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRestoreBackupHandler(log zerolog.Logger, ah *action.ActionHandler) *RestoreBackupHandler {
+	return &RestoreBackupHandler{log: log, ah: ah}
+}
+
+func (h *RestoreBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+}
+
+func (h *RestoreBackupHandler) do(r *http.Request) error {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	backupID := vars["backupid"]
+
+	if err := h.ah.RestoreBackup(ctx, backupID); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
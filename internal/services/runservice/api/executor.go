@@ -31,6 +31,7 @@ import (
 	"agola.io/agola/internal/services/runservice/common"
 	"agola.io/agola/internal/services/runservice/db"
 	"agola.io/agola/internal/services/runservice/store"
+	"agola.io/agola/internal/sqlg/notify"
 	"agola.io/agola/internal/sqlg/sql"
 	"agola.io/agola/internal/util"
 	rsapitypes "agola.io/agola/services/runservice/api/types"
@@ -195,11 +196,11 @@ func (h *ExecutorStatusHandler) deleteStaleExecutors(ctx context.Context, curExe
 type ExecutorTaskStatusHandler struct {
 	log zerolog.Logger
 	d   *db.DB
-	c   chan<- string
+	n   notify.Notifier
 }
 
-func NewExecutorTaskStatusHandler(log zerolog.Logger, d *db.DB, c chan<- string) *ExecutorTaskStatusHandler {
-	return &ExecutorTaskStatusHandler{log: log, d: d, c: c}
+func NewExecutorTaskStatusHandler(log zerolog.Logger, d *db.DB, n notify.Notifier) *ExecutorTaskStatusHandler {
+	return &ExecutorTaskStatusHandler{log: log, d: d, n: n}
 }
 
 func (h *ExecutorTaskStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -261,14 +262,12 @@ func (h *ExecutorTaskStatusHandler) do(r *http.Request) error {
 			return errors.WithStack(err)
 		}
 
-		return nil
+		return errors.WithStack(h.n.Notify(tx, common.ExecutorTasksNotifyChannel, etID))
 	})
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	go func() { h.c <- etID }()
-
 	return nil
 }
 
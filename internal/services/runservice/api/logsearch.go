@@ -0,0 +1,119 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/runservice/action"
+	"agola.io/agola/internal/tasklog"
+	"agola.io/agola/internal/util"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+)
+
+type RunLogsSearchHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewRunLogsSearchHandler(log zerolog.Logger, ah *action.ActionHandler) *RunLogsSearchHandler {
+	return &RunLogsSearchHandler{
+		log: log,
+		ah:  ah,
+	}
+}
+
+func (h *RunLogsSearchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+
+	if err := util.HTTPResponse(w, http.StatusOK, res); err != nil {
+		h.log.Err(err).Send()
+	}
+}
+
+func (h *RunLogsSearchHandler) do(r *http.Request) (*rsapitypes.RunLogsSearchResponse, error) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+
+	req := &action.RunLogsSearchRequest{
+		RunID:   vars["runid"],
+		TaskIDs: q["taskid"],
+		Pattern: q.Get("pattern"),
+	}
+	_, req.Regex = q["regex"]
+	_, req.IgnoreCase = q["ignorecase"]
+
+	var err error
+	if contextStr := q.Get("context"); contextStr != "" {
+		req.Context, err = strconv.Atoi(contextStr)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("cannot parse context %q", contextStr))
+		}
+	}
+	if limitStr := q.Get("limit"); limitStr != "" {
+		req.Limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, util.NewAPIErrorWrap(util.ErrBadRequest, err, util.WithAPIErrorMsgf("cannot parse limit %q", limitStr))
+		}
+	}
+
+	res, err := h.ah.SearchRunLogs(ctx, req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp := &rsapitypes.RunLogsSearchResponse{
+		Matches:   make([]*rsapitypes.RunLogsSearchMatch, len(res.Matches)),
+		Truncated: res.Truncated,
+	}
+	for i, m := range res.Matches {
+		resp.Matches[i] = &rsapitypes.RunLogsSearchMatch{
+			TaskID: m.TaskID,
+			Step:   m.Step,
+			Line:   logsSearchLine(m.Line),
+			Before: logsSearchLines(m.Before),
+			After:  logsSearchLines(m.After),
+		}
+	}
+
+	return resp, nil
+}
+
+func logsSearchLine(l *tasklog.Line) *rsapitypes.RunLogsSearchLine {
+	return &rsapitypes.RunLogsSearchLine{
+		Number: l.Number,
+		Time:   l.Time,
+		Stream: string(l.Stream),
+		Text:   l.Text,
+	}
+}
+
+func logsSearchLines(lines []*tasklog.Line) []*rsapitypes.RunLogsSearchLine {
+	out := make([]*rsapitypes.RunLogsSearchLine, len(lines))
+	for i, l := range lines {
+		out[i] = logsSearchLine(l)
+	}
+	return out
+}
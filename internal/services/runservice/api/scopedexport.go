@@ -0,0 +1,107 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/services/runservice/action"
+	"agola.io/agola/internal/util"
+	rsapitypes "agola.io/agola/services/runservice/api/types"
+)
+
+type ScopedExportHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewScopedExportHandler(log zerolog.Logger, ah *action.ActionHandler) *ScopedExportHandler {
+	return &ScopedExportHandler{log: log, ah: ah}
+}
+
+func (h *ScopedExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(w, r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+}
+
+func (h *ScopedExportHandler) do(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	objs, err := h.ah.GetScopedExportObjects(ctx, query["group"])
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err := h.ah.WriteScopedExport(ctx, objs, w); err != nil {
+		h.log.Err(err).Send()
+		// since we already answered with a 200 we cannot return another error code
+		// So abort the connection and the client will detect the missing ending chunk
+		// and consider this an error
+		//
+		// this is the way to force close a request without logging the panic
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
+
+type ScopedImportHandler struct {
+	log zerolog.Logger
+	ah  *action.ActionHandler
+}
+
+func NewScopedImportHandler(log zerolog.Logger, ah *action.ActionHandler) *ScopedImportHandler {
+	return &ScopedImportHandler{log: log, ah: ah}
+}
+
+func (h *ScopedImportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	err := h.do(r)
+	if util.HTTPError(w, err) {
+		h.log.Err(err).Send()
+		return
+	}
+}
+
+func (h *ScopedImportHandler) do(r *http.Request) error {
+	ctx := r.Context()
+
+	// the request body starts with the import request followed by the export
+	// data
+	var req rsapitypes.ScopedImportRequest
+	d := json.NewDecoder(r.Body)
+	if err := d.Decode(&req); err != nil {
+		return util.NewAPIErrorWrap(util.ErrBadRequest, err)
+	}
+
+	areq := &action.ScopedImportRequest{
+		GroupsRemap:      req.GroupsRemap,
+		AnnotationsRemap: req.AnnotationsRemap,
+	}
+
+	if err := h.ah.ImportScoped(ctx, areq, io.MultiReader(d.Buffered(), r.Body)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
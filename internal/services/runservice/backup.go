@@ -0,0 +1,81 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	scommon "agola.io/agola/internal/services/common"
+	"agola.io/agola/internal/services/runservice/common"
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/lock"
+	"agola.io/agola/internal/sqlg/manager"
+)
+
+const (
+	backupCheckInterval = 1 * time.Minute
+)
+
+func (s *Runservice) backupLoop(ctx context.Context) {
+	s.log.Debug().Msg("backupLoop")
+
+	for {
+		if err := s.backup(ctx); err != nil {
+			s.log.Warn().Err(err).Msg("backup error")
+		}
+
+		sleepCh := time.NewTimer(backupCheckInterval).C
+		select {
+		case <-ctx.Done():
+			return
+		case <-sleepCh:
+		}
+	}
+}
+
+// backup saves a backup of the runservice database in the object storage.
+// The run logs and workspace archives are already saved in the same object
+// storage and aren't part of the backup.
+func (s *Runservice) backup(ctx context.Context) error {
+	l := s.lf.NewLock(common.BackupLockKey)
+	if err := l.TryLock(ctx); err != nil {
+		if errors.Is(err, lock.ErrLocked) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	defer func() { _ = l.Unlock() }()
+
+	backups, err := scommon.GetBackups(ctx, s.ost)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	// take a new backup only when the last one is older than the backup interval
+	if len(backups) > 0 && time.Since(backups[len(backups)-1].Time) < s.c.Backup.Interval {
+		return nil
+	}
+
+	dbm := manager.NewDBManager(s.log, s.d, s.lf)
+	backup, err := scommon.WriteBackup(ctx, dbm, sqlg.ObjectNames(s.d.ObjectsInfo()), s.ost, s.c.DataDir)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	s.log.Info().Msgf("created backup %q", backup.ID)
+
+	return errors.WithStack(scommon.DeleteOldBackups(ctx, s.ost, s.c.Backup.Retention))
+}
@@ -43,6 +43,14 @@ const (
 	WorkspaceCleanerLockKey = "workspacecleaner"
 	LogCleanerLockKey       = "logcleaner"
 	TaskUpdaterLockKey      = "taskupdater"
+	BackupLockKey           = "backup"
+)
+
+// notification channels. The payload is the related object id.
+const (
+	RunsNotifyChannel          = "agola_runservice_runs"
+	RunEventsNotifyChannel     = "agola_runservice_runevents"
+	ExecutorTasksNotifyChannel = "agola_runservice_executortasks"
 )
 
 func TaskFetcherLockKey(taskID string) string {
@@ -149,6 +157,8 @@ func GenExecutorTaskSpecData(r *types.Run, rt *types.RunTask, rc *types.RunConfi
 		CachePrefix:          cachePrefix,
 		DockerRegistriesAuth: rct.DockerRegistriesAuth,
 		TaskTimeoutInterval:  rct.TaskTimeoutInterval,
+		SecretValues:         rct.SecretValues,
+		TraceParent:          r.Annotations[types.RunAnnotationTraceParent],
 	}
 
 	// calculate workspace operations
@@ -0,0 +1,97 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runservice
+
+import (
+	"path"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"agola.io/agola/internal/metrics"
+	"agola.io/agola/internal/util"
+	"agola.io/agola/services/runservice/types"
+)
+
+const (
+	metricsSubsystem = "runservice"
+
+	fetchTypeLog     = "log"
+	fetchTypeArchive = "archive"
+)
+
+var (
+	runsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "runs",
+		Help:      "Number of queued and running runs per base group (project or user).",
+	}, []string{"group", "phase"})
+
+	taskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "task_duration_seconds",
+		Help:      "Duration of the finished run tasks in seconds.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
+	}, []string{"result"})
+
+	fetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: metricsSubsystem,
+		Name:      "fetch_duration_seconds",
+		Help:      "Duration of the fetch of logs and archives from the executors in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"type", "result"})
+)
+
+// runBaseGroup returns the base group (i.e. /project/projectid) of a run group.
+func runBaseGroup(group string) string {
+	pl := util.PathList(group)
+	if len(pl) < 2 {
+		return group
+	}
+
+	return path.Join("/", pl[0], pl[1])
+}
+
+// updateRunsMetrics updates the queued and running runs metrics.
+func updateRunsMetrics(runs []*types.Run) {
+	runsGauge.Reset()
+	for _, r := range runs {
+		if r.Phase != types.RunPhaseQueued && r.Phase != types.RunPhaseRunning {
+			continue
+		}
+		runsGauge.WithLabelValues(runBaseGroup(r.Group), string(r.Phase)).Inc()
+	}
+}
+
+// observeTaskDuration records the duration of a finished run task.
+func observeTaskDuration(rt *types.RunTask) {
+	if rt.StartTime == nil || rt.EndTime == nil {
+		return
+	}
+	taskDuration.WithLabelValues(string(rt.Status)).Observe(rt.EndTime.Sub(*rt.StartTime).Seconds())
+}
+
+// observeFetchDuration records the duration of a log or archive fetch.
+func observeFetchDuration(fetchType string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	fetchDuration.WithLabelValues(fetchType, result).Observe(time.Since(start).Seconds())
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/sorintlab/errors"
 
 	scommon "agola.io/agola/internal/common"
+	"agola.io/agola/internal/metrics"
 	"agola.io/agola/internal/objectstorage"
 	"agola.io/agola/internal/services/common"
 	"agola.io/agola/internal/services/config"
@@ -37,7 +38,9 @@ import (
 	"agola.io/agola/internal/services/runservice/db"
 	"agola.io/agola/internal/sqlg/lock"
 	"agola.io/agola/internal/sqlg/manager"
+	"agola.io/agola/internal/sqlg/notify"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
 )
 
@@ -81,6 +84,7 @@ type Runservice struct {
 	ost             objectstorage.ObjStorage
 	d               *db.DB
 	lf              lock.LockFactory
+	n               notify.Notifier
 	ah              *action.ActionHandler
 	maintenanceMode bool
 }
@@ -123,16 +127,20 @@ func NewRunservice(ctx context.Context, log zerolog.Logger, c *config.Runservice
 	s.d = d
 
 	var lf lock.LockFactory
+	var n notify.Notifier
 	switch c.DB.Type {
 	case sql.Sqlite3:
 		ll := lock.NewLocalLocks()
 		lf = lock.NewLocalLockFactory(ll)
+		n = notify.NewLocalNotifier()
 	case sql.Postgres:
 		lf = lock.NewPGLockFactory(sdb)
+		n = notify.NewPGNotifier(log, c.DB.ConnString)
 	default:
 		return nil, errors.Errorf("unknown db type %q", c.DB.Type)
 	}
 	s.lf = lf
+	s.n = n
 
 	dbm := manager.NewDBManager(log, d, lf)
 
@@ -140,21 +148,25 @@ func NewRunservice(ctx context.Context, log zerolog.Logger, c *config.Runservice
 		return nil, errors.Wrap(err, "failed to setup db")
 	}
 
-	ah := action.NewActionHandler(log, d, ost, lf)
+	ah := action.NewActionHandler(log, d, ost, lf, n)
 	s.ah = ah
 
 	return s, nil
 }
 
-func (s *Runservice) setupDefaultRouter(etCh chan string) http.Handler {
+func (s *Runservice) setupDefaultRouter() http.Handler {
 	maintenanceStatusHandler := api.NewMaintenanceStatusHandler(s.log, s.ah, false)
 	maintenanceModeHandler := api.NewMaintenanceModeHandler(s.log, s.ah)
 	exportHandler := api.NewExportHandler(s.log, s.ah)
 	importHandler := api.NewImportHandler(s.log, s.ah)
+	backupsHandler := api.NewBackupsHandler(s.log, s.ah)
+	restoreBackupHandler := api.NewRestoreBackupHandler(s.log, s.ah)
+	scopedExportHandler := api.NewScopedExportHandler(s.log, s.ah)
+	scopedImportHandler := api.NewScopedImportHandler(s.log, s.ah)
 
 	// executor dedicated api, only calls from executor should happen on these handlers
 	executorStatusHandler := api.NewExecutorStatusHandler(s.log, s.d, s.ah)
-	executorTaskStatusHandler := api.NewExecutorTaskStatusHandler(s.log, s.d, etCh)
+	executorTaskStatusHandler := api.NewExecutorTaskStatusHandler(s.log, s.d, s.n)
 	executorTaskHandler := api.NewExecutorTaskHandler(s.log, s.ah)
 	executorTasksHandler := api.NewExecutorTasksHandler(s.log, s.ah)
 	archivesHandler := api.NewArchivesHandler(s.log, s.ost)
@@ -174,15 +186,19 @@ func (s *Runservice) setupDefaultRouter(etCh chan string) http.Handler {
 	runsByGroupHandler := api.NewGroupRunsHandler(s.log, s.d, s.ah)
 	runActionsHandler := api.NewRunActionsHandler(s.log, s.ah)
 	runCreateHandler := api.NewRunCreateHandler(s.log, s.ah)
-	runEventsHandler := api.NewRunEventsHandler(s.log, s.d, s.ost)
+	runEventsHandler := api.NewRunEventsHandler(s.log, s.d, s.ost, s.n)
+	runLogsSearchHandler := api.NewRunLogsSearchHandler(s.log, s.ah)
 
 	changeGroupsUpdateTokensHandler := api.NewChangeGroupsUpdateTokensHandler(s.log, s.d, s.ah)
 
 	authHandler := handlers.NewInternalAuthChecker(s.log, s.c.APIToken)
 
 	router := mux.NewRouter().UseEncodedPath().SkipClean(true)
+	router.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
 	apirouter := router.PathPrefix("/api/v1alpha").Subrouter().UseEncodedPath().SkipClean(true)
 
+	apirouter.Use(tracing.NewHTTPMiddleware("runservice"))
+	apirouter.Use(metrics.NewHTTPMiddleware("runservice"))
 	apirouter.Use(authHandler)
 
 	// don't return 404 on a call to an undefined handler but 400 to distinguish between a non existent resource and a wrong method
@@ -204,6 +220,7 @@ func (s *Runservice) setupDefaultRouter(etCh chan string) http.Handler {
 	apirouter.Handle("/runs/events", runEventsHandler).Methods("GET")
 	apirouter.Handle("/runs/{runid}", runHandler).Methods("GET")
 	apirouter.Handle("/runs/{runid}/actions", runActionsHandler).Methods("PUT")
+	apirouter.Handle("/runs/{runid}/logs/search", runLogsSearchHandler).Methods("GET")
 	apirouter.Handle("/runs/{runid}/tasks/{taskid}/actions", runTaskActionsHandler).Methods("PUT")
 
 	apirouter.Handle("/runs/group/{group}/{runcounter}", runByGroupHandler).Methods("GET")
@@ -219,6 +236,10 @@ func (s *Runservice) setupDefaultRouter(etCh chan string) http.Handler {
 
 	apirouter.Handle("/export", exportHandler).Methods("GET")
 	apirouter.Handle("/import", importHandler).Methods("POST")
+	apirouter.Handle("/backups", backupsHandler).Methods("GET")
+	apirouter.Handle("/backups/{backupid}/restore", restoreBackupHandler).Methods("POST")
+	apirouter.Handle("/scopedexport", scopedExportHandler).Methods("GET")
+	apirouter.Handle("/scopedimport", scopedImportHandler).Methods("POST")
 
 	mainrouter := mux.NewRouter().UseEncodedPath().SkipClean(true)
 	mainrouter.PathPrefix("/").Handler(router)
@@ -234,15 +255,23 @@ func (s *Runservice) setupMaintenanceRouter() http.Handler {
 	maintenanceModeHandler := api.NewMaintenanceModeHandler(s.log, s.ah)
 	exportHandler := api.NewExportHandler(s.log, s.ah)
 	importHandler := api.NewImportHandler(s.log, s.ah)
+	backupsHandler := api.NewBackupsHandler(s.log, s.ah)
+	restoreBackupHandler := api.NewRestoreBackupHandler(s.log, s.ah)
 
 	router := mux.NewRouter()
+	router.Handle(metrics.MetricsPath, metrics.Handler()).Methods("GET")
 	apirouter := router.PathPrefix("/api/v1alpha").Subrouter().UseEncodedPath()
 
+	apirouter.Use(tracing.NewHTTPMiddleware("runservice"))
+	apirouter.Use(metrics.NewHTTPMiddleware("runservice"))
+
 	apirouter.Handle("/maintenance", maintenanceStatusHandler).Methods("GET")
 	apirouter.Handle("/maintenance", maintenanceModeHandler).Methods("PUT", "DELETE")
 
 	apirouter.Handle("/export", exportHandler).Methods("GET")
 	apirouter.Handle("/import", importHandler).Methods("POST")
+	apirouter.Handle("/backups", backupsHandler).Methods("GET")
+	apirouter.Handle("/backups/{backupid}/restore", restoreBackupHandler).Methods("POST")
 
 	mainrouter := mux.NewRouter()
 	mainrouter.PathPrefix("/").Handler(router)
@@ -295,8 +324,7 @@ func (s *Runservice) run(ctx context.Context) error {
 		util.GoWait(&wg, func() { s.maintenanceModeWatcherLoop(ctx, cancel, s.maintenanceMode) })
 
 	} else {
-		ch := make(chan string)
-		mainrouter = s.setupDefaultRouter(ch)
+		mainrouter = s.setupDefaultRouter()
 
 		util.GoWait(&wg, func() { s.maintenanceModeWatcherLoop(ctx, cancel, s.maintenanceMode) })
 
@@ -311,7 +339,10 @@ func (s *Runservice) run(ctx context.Context) error {
 		util.GoWait(&wg, func() { s.cacheCleanerLoop(ctx, s.c.RunCacheExpireInterval) })
 		util.GoWait(&wg, func() { s.workspaceCleanerLoop(ctx, s.c.RunWorkspaceExpireInterval) })
 		util.GoWait(&wg, func() { s.logCleanerLoop(ctx, s.c.RunLogExpireInterval) })
-		util.GoWait(&wg, func() { s.executorTaskUpdateHandler(ctx, ch) })
+		if s.c.Backup.Enabled {
+			util.GoWait(&wg, func() { s.backupLoop(ctx) })
+		}
+		util.GoWait(&wg, func() { s.executorTaskUpdateHandler(ctx) })
 	}
 
 	httpServer := http.Server{
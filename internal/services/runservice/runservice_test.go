@@ -17,6 +17,7 @@ package runservice
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net"
 	"os"
@@ -40,6 +41,7 @@ import (
 	"agola.io/agola/internal/services/runservice/store"
 	"agola.io/agola/internal/sqlg"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tasklog"
 	"agola.io/agola/internal/testutil"
 	"agola.io/agola/internal/util"
 	"agola.io/agola/services/runservice/types"
@@ -174,6 +176,129 @@ func TestExportImport(t *testing.T) {
 	}
 }
 
+func TestScopedExportImport(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	rs := setupRunservice(ctx, t, log, dir)
+	rs2 := setupRunservice(ctx, t, log, dir)
+
+	// a log bigger than the export chunks size
+	stepLog := strings.Repeat("log01", 500*1024)
+
+	for i := 0; i < 3; i++ {
+		taskID := fmt.Sprintf("task%02d", i)
+		_, err := rs.ah.CreateRun(ctx, &action.RunCreateRequest{Group: "/project/project01/branch/master", RunConfigTasks: map[string]*types.RunConfigTask{taskID: {ID: taskID, Name: "task01"}}, Annotations: map[string]string{"projectid": "project01"}})
+		testutil.NilError(t, err)
+
+		err = rs.ost.WriteObject(ctx, store.OSTRunTaskStepLogPath(taskID, 0), strings.NewReader(stepLog), -1, false)
+		testutil.NilError(t, err)
+	}
+	// a run in another project must not be exported
+	_, err := rs.ah.CreateRun(ctx, &action.RunCreateRequest{Group: "/project/project02/branch/master", RunConfigTasks: map[string]*types.RunConfigTask{"task10": {ID: "task10", Name: "task01"}}})
+	testutil.NilError(t, err)
+
+	runs, err := getRuns(ctx, rs)
+	testutil.NilError(t, err)
+
+	// archive all the runs but the last one of project01
+	err = rs.d.Do(ctx, func(tx *sql.Tx) error {
+		for _, run := range runs {
+			if run.Group == "/project/project01/branch/master" && run.Counter == 3 {
+				continue
+			}
+			run, err := rs.d.GetRun(tx, run.ID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			run.Phase = types.RunPhaseFinished
+			run.Result = types.RunResultSuccess
+			run.Archived = true
+			if err := rs.d.UpdateRun(tx, run); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	})
+	testutil.NilError(t, err)
+
+	objs, err := rs.ah.GetScopedExportObjects(ctx, []string{"/project/project01"})
+	testutil.NilError(t, err)
+
+	var export bytes.Buffer
+	err = rs.ah.WriteScopedExport(ctx, objs, &export)
+	testutil.NilError(t, err)
+
+	req := &action.ScopedImportRequest{
+		GroupsRemap:      map[string]string{"/project/project01": "/project/project03"},
+		AnnotationsRemap: map[string]map[string]string{"projectid": {"project01": "project03"}},
+	}
+	err = rs2.ah.ImportScoped(ctx, req, bytes.NewReader(export.Bytes()))
+	testutil.NilError(t, err)
+
+	newRuns, err := getRuns(ctx, rs2)
+	testutil.NilError(t, err)
+
+	assert.Assert(t, cmp.Len(newRuns, 2))
+	for i, run := range newRuns {
+		assert.Equal(t, run.Group, "/project/project03/branch/master")
+		assert.Equal(t, run.Counter, uint64(i+1))
+		assert.Equal(t, run.Annotations["projectid"], "project03")
+		assert.Assert(t, run.Archived)
+		assert.Assert(t, cmp.Len(run.Tasks, 1))
+		for _, rt := range run.Tasks {
+			assert.Assert(t, !strings.HasPrefix(rt.ID, "task"))
+
+			f, err := rs2.ost.ReadObject(ctx, store.OSTRunTaskStepLogPath(rt.ID, 0))
+			testutil.NilError(t, err)
+			data, err := io.ReadAll(f)
+			f.Close()
+			testutil.NilError(t, err)
+			assert.Equal(t, string(data), stepLog)
+		}
+		for _, oldRun := range runs {
+			assert.Assert(t, run.ID != oldRun.ID)
+		}
+	}
+
+	err = rs2.d.Do(ctx, func(tx *sql.Tx) error {
+		runCounter, err := rs2.d.GetRunCounter(tx, "project03")
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		assert.Equal(t, runCounter.Value, uint64(3))
+		return nil
+	})
+	testutil.NilError(t, err)
+
+	// importing again the same runs must fail
+	err = rs2.ah.ImportScoped(ctx, req, bytes.NewReader(export.Bytes()))
+	assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest), "expected bad request error, got: %v", err)
+
+	countLogs := func() int {
+		var n int
+		for object := range rs2.ost.List(ctx, store.OSTLogsBaseDir()+"/", "", true) {
+			testutil.NilError(t, object.Err)
+			n++
+		}
+		return n
+	}
+	logsCount := countLogs()
+
+	// a truncated export must fail without importing runs or logs
+	req.GroupsRemap = map[string]string{"/project/project01": "/project/project04"}
+	err = rs2.ah.ImportScoped(ctx, req, bytes.NewReader(export.Bytes()[:export.Len()-100]))
+	assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest), "expected bad request error, got: %v", err)
+
+	newRuns, err = getRuns(ctx, rs2)
+	testutil.NilError(t, err)
+	assert.Assert(t, cmp.Len(newRuns, 2))
+	assert.Equal(t, countLogs(), logsCount)
+}
+
 func TestConcurrentRunCreation(t *testing.T) {
 	t.Parallel()
 
@@ -239,6 +364,36 @@ func TestConcurrentRunCreation(t *testing.T) {
 	}
 }
 
+func TestRunCreationNotify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	rs := setupRunservice(ctx, t, log, dir)
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+
+	runsCh, err := rs.n.Subscribe(subCtx, common.RunsNotifyChannel)
+	testutil.NilError(t, err)
+	runEventsCh, err := rs.n.Subscribe(subCtx, common.RunEventsNotifyChannel)
+	testutil.NilError(t, err)
+
+	rb, err := rs.ah.CreateRun(ctx, &action.RunCreateRequest{Group: "/user/user01", RunConfigTasks: map[string]*types.RunConfigTask{"task01": {}}})
+	testutil.NilError(t, err)
+
+	for _, c := range []<-chan string{runsCh, runEventsCh} {
+		select {
+		case runID := <-c:
+			assert.Equal(t, runID, rb.Run.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for notification")
+		}
+	}
+}
+
 func TestGetRunsLastRun(t *testing.T) {
 	t.Parallel()
 
@@ -312,6 +467,120 @@ func TestLogleaner(t *testing.T) {
 	assert.ErrorType(t, err, objectstorage.IsNotExist)
 }
 
+func TestSearchRunLogs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ctx := context.Background()
+	log := testutil.NewLogger(t)
+
+	rs := setupRunservice(ctx, t, log, dir)
+
+	rcts := map[string]*types.RunConfigTask{
+		"task01": {ID: "task01", Name: "task01", Steps: types.Steps{&types.RunStep{}, &types.RunStep{}}},
+		"task02": {ID: "task02", Name: "task02", Steps: types.Steps{&types.RunStep{}}},
+	}
+	rb, err := rs.ah.CreateRun(ctx, &action.RunCreateRequest{Group: "/user/user01", RunConfigTasks: rcts})
+	testutil.NilError(t, err)
+
+	// write the step logs: task01 step 0 uses the structured format while
+	// task01 step 1 uses the raw text format. task02 step 0 isn't archived.
+	var buf bytes.Buffer
+	lw := tasklog.NewWriter(&buf)
+	sw := lw.Stream(tasklog.StreamStdout)
+	_, err = sw.Write([]byte("building\nerror: build failed\ndone\n"))
+	testutil.NilError(t, err)
+
+	logs := map[string]string{
+		store.OSTRunTaskStepStructuredLogPath("task01", 0): buf.String(),
+		store.OSTRunTaskStepLogPath("task01", 1):           "testing\nERROR: test failed\n",
+		store.OSTRunTaskStepStructuredLogPath("task02", 0): buf.String(),
+	}
+	for p, l := range logs {
+		err := rs.ost.WriteObject(ctx, p, strings.NewReader(l), -1, false)
+		testutil.NilError(t, err)
+	}
+
+	err = rs.d.Do(ctx, func(tx *sql.Tx) error {
+		run, err := rs.d.GetRun(tx, rb.Run.ID)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		for _, s := range run.Tasks["task01"].Steps {
+			s.LogPhase = types.RunTaskFetchPhaseFinished
+		}
+		return errors.WithStack(rs.d.UpdateRun(tx, run))
+	})
+	testutil.NilError(t, err)
+
+	type match struct {
+		TaskID string
+		Step   int
+		Line   string
+		Before []string
+	}
+
+	tests := []struct {
+		name       string
+		req        *action.RunLogsSearchRequest
+		matches    []match
+		truncated  bool
+		badRequest bool
+	}{
+		{
+			name: "substring",
+			req:  &action.RunLogsSearchRequest{Pattern: "error"},
+			matches: []match{
+				{TaskID: "task01", Step: 0, Line: "error: build failed", Before: []string{}},
+			},
+		},
+		{
+			name: "ignore case with context",
+			req:  &action.RunLogsSearchRequest{Pattern: "error", IgnoreCase: true, Context: 1},
+			matches: []match{
+				{TaskID: "task01", Step: 0, Line: "error: build failed", Before: []string{"building"}},
+				{TaskID: "task01", Step: 1, Line: "ERROR: test failed", Before: []string{"testing"}},
+			},
+		},
+		{
+			name: "regex with limit",
+			req:  &action.RunLogsSearchRequest{Pattern: "^[a-z]+ing$", Regex: true, Limit: 1},
+			matches: []match{
+				{TaskID: "task01", Step: 0, Line: "building", Before: []string{}},
+			},
+			truncated: true,
+		},
+		{
+			name:       "invalid regex",
+			req:        &action.RunLogsSearchRequest{Pattern: "(", Regex: true},
+			badRequest: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.RunID = rb.Run.ID
+			res, err := rs.ah.SearchRunLogs(ctx, tt.req)
+			if tt.badRequest {
+				assert.Assert(t, util.APIErrorIs(err, util.ErrBadRequest), "expected bad request error, got: %v", err)
+				return
+			}
+			testutil.NilError(t, err)
+
+			matches := []match{}
+			for _, m := range res.Matches {
+				before := []string{}
+				for _, l := range m.Before {
+					before = append(before, l.Text)
+				}
+				matches = append(matches, match{TaskID: m.TaskID, Step: m.Step, Line: m.Line.Text, Before: before})
+			}
+			assert.DeepEqual(t, matches, tt.matches)
+			assert.Equal(t, res.Truncated, tt.truncated)
+		})
+	}
+}
+
 func TestGetGroupRuns(t *testing.T) {
 	t.Parallel()
 
@@ -19,6 +19,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
 	"strconv"
 	"time"
@@ -32,12 +34,15 @@ import (
 	"agola.io/agola/internal/services/runservice/common"
 	"agola.io/agola/internal/services/runservice/store"
 	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/tasklog"
+	"agola.io/agola/internal/tracing"
 	"agola.io/agola/internal/util"
 	"agola.io/agola/services/runservice/types"
 )
 
 const (
 	changeGroupCompactorInterval = 1 * time.Minute
+	runsSchedulerInterval        = 10 * time.Second
 	cacheCleanerInterval         = 1 * 24 * time.Hour
 	workspaceCleanerInterval     = 1 * 24 * time.Hour
 	logCleanerInterval           = 1 * 24 * time.Hour
@@ -47,6 +52,10 @@ const (
 	changeGroupMinDuration = 5 * time.Minute
 )
 
+// executorHTTPClient is the http client used to call the executors. It
+// propagates the trace context of the requests.
+var executorHTTPClient = &http.Client{Transport: tracing.NewTransport(http.DefaultTransport)}
+
 func taskMatchesParentDependCondition(rt *types.RunTask, r *types.Run, rc *types.RunConfig) bool {
 	rct := rc.Tasks[rt.ID]
 	parents := runconfig.GetParents(rc.Tasks, rct)
@@ -447,7 +456,7 @@ func (s *Runservice) sendExecutorTask(ctx context.Context, et *types.ExecutorTas
 	if s.c.ExecutorAPIToken != "" {
 		req.Header.Set("Authorization", "token "+s.c.ExecutorAPIToken)
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := executorHTTPClient.Do(req)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -550,6 +559,7 @@ func (s *Runservice) scheduleRun(ctx context.Context, runID string) error {
 
 	prevPhase := r.Phase
 	prevResult := r.Result
+	prevTasksStatus := runTasksStatus(r)
 
 	if err := advanceRun(s.log, r, rc, scheduledExecutorTasks); err != nil {
 		return errors.WithStack(err)
@@ -592,13 +602,14 @@ func (s *Runservice) scheduleRun(ctx context.Context, runID string) error {
 			return errors.WithStack(err)
 		}
 
-		// detect changes to phase and result and set related events
-		if prevPhase != r.Phase || prevResult != r.Result {
-			runEvent, err := common.NewRunEvent(s.d, tx, r, rc, types.RunPhaseChanged)
-			if err != nil {
+		// detect changes to tasks status, phase and result and set related events
+		if !maps.Equal(prevTasksStatus, runTasksStatus(r)) {
+			if err := s.insertRunEvent(tx, r, rc, types.RunTaskStatusChanged); err != nil {
 				return errors.WithStack(err)
 			}
-			if err := s.d.InsertRunEvent(tx, runEvent); err != nil {
+		}
+		if prevPhase != r.Phase || prevResult != r.Result {
+			if err := s.insertRunEvent(tx, r, rc, types.RunPhaseChanged); err != nil {
 				return errors.WithStack(err)
 			}
 		}
@@ -627,6 +638,41 @@ func (s *Runservice) scheduleRun(ctx context.Context, runID string) error {
 	return nil
 }
 
+// runTaskStatus contains the run task fields whose changes generate a run
+// task status changed event.
+type runTaskStatus struct {
+	status          types.RunTaskStatus
+	waitingApproval bool
+	approved        bool
+}
+
+func runTasksStatus(r *types.Run) map[string]runTaskStatus {
+	tasksStatus := make(map[string]runTaskStatus, len(r.Tasks))
+	for id, rt := range r.Tasks {
+		tasksStatus[id] = runTaskStatus{
+			status:          rt.Status,
+			waitingApproval: rt.WaitingApproval,
+			approved:        rt.Approved,
+		}
+	}
+
+	return tasksStatus
+}
+
+// insertRunEvent inserts a new run event and notifies the run events
+// subscribers.
+func (s *Runservice) insertRunEvent(tx *sql.Tx, r *types.Run, rc *types.RunConfig, runEventType types.RunEventType) error {
+	runEvent, err := common.NewRunEvent(s.d, tx, r, rc, runEventType)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := s.d.InsertRunEvent(tx, runEvent); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(s.n.Notify(tx, common.RunEventsNotifyChannel, r.ID))
+}
+
 // advanceRun updates the run result and phase. It must be the unique function that
 // should update them.
 func advanceRun(log zerolog.Logger, r *types.Run, rc *types.RunConfig, scheduledExecutorTasks []*types.ExecutorTask) error {
@@ -730,6 +776,8 @@ func (s *Runservice) handleExecutorTaskUpdate(ctx context.Context, executorTaskI
 			return errors.Errorf("run with id %q doesn't exist", et.RunID)
 		}
 
+		prevTasksStatus := runTasksStatus(r)
+
 		if err := s.updateRunTaskStatus(et, r); err != nil {
 			return errors.WithStack(err)
 		}
@@ -737,6 +785,20 @@ func (s *Runservice) handleExecutorTaskUpdate(ctx context.Context, executorTaskI
 		if err = s.d.UpdateRun(tx, r); err != nil {
 			return errors.WithStack(err)
 		}
+
+		if !maps.Equal(prevTasksStatus, runTasksStatus(r)) {
+			rc, err := s.d.GetRunConfig(tx, r.RunConfigID)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if rc == nil {
+				return errors.Errorf("runconfig with id %q doesn't exist", r.RunConfigID)
+			}
+
+			if err := s.insertRunEvent(tx, r, rc, types.RunTaskStatusChanged); err != nil {
+				return errors.WithStack(err)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -797,6 +859,8 @@ func (s *Runservice) updateRunTaskStatus(et *types.ExecutorTask, r *types.Run) e
 		return nil
 	}
 
+	wasFinished := rt.Status.IsFinished()
+
 	switch et.Phase {
 	case types.ExecutorTaskPhaseNotStarted:
 		rt.Status = types.RunTaskStatusNotStarted
@@ -824,15 +888,46 @@ func (s *Runservice) updateRunTaskStatus(et *types.ExecutorTask, r *types.Run) e
 		rt.Steps[i].EndTime = s.EndTime
 	}
 
+	if !wasFinished && rt.Status.IsFinished() {
+		observeTaskDuration(rt)
+	}
+
 	return nil
 }
 
-func (s *Runservice) executorTaskUpdateHandler(ctx context.Context, c <-chan string) {
+func (s *Runservice) executorTaskUpdateHandler(ctx context.Context) {
+	ns := newNotifySubscriber(s.log, s.n, common.ExecutorTasksNotifyChannel)
+
 	for {
+		c := ns.subscribe(ctx)
+		if c == nil {
+			// wait for the subscription retry, the lost notifications will be
+			// handled by the runTasksUpdaterLoop
+			timer := time.NewTimer(ns.retryWait())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			continue
+		}
+
 		select {
 		case <-ctx.Done():
 			return
-		case etID := <-c:
+		case etID, ok := <-c:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				ns.closed()
+				continue
+			}
+			// lost notifications will be handled by the runTasksUpdaterLoop
+			if etID == "" {
+				continue
+			}
 			go func() {
 				if err := s.handleExecutorTaskUpdate(ctx, etID); err != nil {
 					// TODO(sgotti) improve logging to not return "run modified errors" since
@@ -1057,25 +1152,32 @@ func (s *Runservice) fetchLog(ctx context.Context, runID string, rt *types.RunTa
 		return nil
 	}
 
-	var logPath string
+	var logPath, rawLogPath string
 	if setup {
-		logPath = store.OSTRunTaskSetupLogPath(rt.ID)
+		logPath = store.OSTRunTaskSetupStructuredLogPath(rt.ID)
+		rawLogPath = store.OSTRunTaskSetupLogPath(rt.ID)
 	} else {
-		logPath = store.OSTRunTaskStepLogPath(rt.ID, stepnum)
-	}
-	ok, err := s.OSTFileExists(ctx, logPath)
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	if ok {
-		return nil
+		logPath = store.OSTRunTaskStepStructuredLogPath(rt.ID, stepnum)
+		rawLogPath = store.OSTRunTaskStepLogPath(rt.ID, stepnum)
+	}
+	indexPath := store.OSTRunTaskLogIndexPath(logPath)
+	// the index is written after the log so its existence means that the log
+	// has been completely saved
+	for _, p := range []string{indexPath, rawLogPath} {
+		ok, err := s.OSTFileExists(ctx, p)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if ok {
+			return nil
+		}
 	}
 
 	var u string
 	if setup {
-		u = fmt.Sprintf(executor.ListenURL+"/api/v1alpha/executor/logs?taskid=%s&setup", et.ID)
+		u = fmt.Sprintf(executor.ListenURL+"/api/v1alpha/executor/logs?taskid=%s&setup&format=%s", et.ID, tasklog.FormatNDJSON)
 	} else {
-		u = fmt.Sprintf(executor.ListenURL+"/api/v1alpha/executor/logs?taskid=%s&step=%d", et.ID, stepnum)
+		u = fmt.Sprintf(executor.ListenURL+"/api/v1alpha/executor/logs?taskid=%s&step=%d&format=%s", et.ID, stepnum, tasklog.FormatNDJSON)
 	}
 	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
@@ -1084,7 +1186,7 @@ func (s *Runservice) fetchLog(ctx context.Context, runID string, rt *types.RunTa
 	if s.c.ExecutorAPIToken != "" {
 		req.Header.Set("Authorization", "token "+s.c.ExecutorAPIToken)
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := executorHTTPClient.Do(req)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -1108,7 +1210,22 @@ func (s *Runservice) fetchLog(ctx context.Context, runID string, rt *types.RunTa
 		}
 	}
 
-	return errors.WithStack(s.ost.WriteObject(ctx, logPath, resp.Body, size, false))
+	// older executors only provide raw text logs
+	if resp.Header.Get("Content-Type") != tasklog.NDJSONContentType {
+		return errors.WithStack(s.ost.WriteObject(ctx, rawLogPath, resp.Body, size, false))
+	}
+
+	indexer := tasklog.NewIndexer()
+	if err := s.ost.WriteObject(ctx, logPath, io.TeeReader(resp.Body, indexer), size, false); err != nil {
+		return errors.WithStack(err)
+	}
+
+	index, err := json.Marshal(indexer.Index())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(s.ost.WriteObject(ctx, indexPath, bytes.NewReader(index), int64(len(index)), false))
 }
 
 func (s *Runservice) finishSetupLogPhase(ctx context.Context, runID, runTaskID string) error {
@@ -1216,7 +1333,10 @@ func (s *Runservice) fetchTaskLogs(ctx context.Context, runID string, rt *types.
 
 	// fetch setup log
 	if rt.SetupStep.LogPhase == types.RunTaskFetchPhaseNotStarted {
-		if err := s.fetchLog(ctx, runID, rt, true, 0); err != nil {
+		start := time.Now()
+		err := s.fetchLog(ctx, runID, rt, true, 0)
+		observeFetchDuration(fetchTypeLog, start, err)
+		if err != nil {
 			s.log.Err(err).Send()
 		} else {
 			if err := s.finishSetupLogPhase(ctx, runID, rt.ID); err != nil {
@@ -1229,7 +1349,10 @@ func (s *Runservice) fetchTaskLogs(ctx context.Context, runID string, rt *types.
 	for i, rts := range rt.Steps {
 		lp := rts.LogPhase
 		if lp == types.RunTaskFetchPhaseNotStarted {
-			if err := s.fetchLog(ctx, runID, rt, false, i); err != nil {
+			start := time.Now()
+			err := s.fetchLog(ctx, runID, rt, false, i)
+			observeFetchDuration(fetchTypeLog, start, err)
+			if err != nil {
 				s.log.Err(err).Send()
 				continue
 			}
@@ -1295,7 +1418,7 @@ func (s *Runservice) fetchArchive(ctx context.Context, runID string, rt *types.R
 	if s.c.ExecutorAPIToken != "" {
 		req.Header.Set("Authorization", "token "+s.c.ExecutorAPIToken)
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := executorHTTPClient.Do(req)
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -1328,7 +1451,10 @@ func (s *Runservice) fetchTaskArchives(ctx context.Context, runID string, rt *ty
 	for i, stepnum := range rt.WorkspaceArchives {
 		phase := rt.WorkspaceArchivesPhase[i]
 		if phase == types.RunTaskFetchPhaseNotStarted {
-			if err := s.fetchArchive(ctx, runID, rt, stepnum); err != nil {
+			start := time.Now()
+			err := s.fetchArchive(ctx, runID, rt, stepnum)
+			observeFetchDuration(fetchTypeArchive, start, err)
+			if err != nil {
 				s.log.Err(err).Send()
 				continue
 			}
@@ -1446,18 +1572,47 @@ func (s *Runservice) taskFetcher(ctx context.Context, r *types.Run, rt *types.Ru
 }
 
 func (s *Runservice) runsSchedulerLoop(ctx context.Context) {
+	ns := newNotifySubscriber(s.log, s.n, common.RunsNotifyChannel)
+
 	for {
 		s.log.Debug().Msg("runsSchedulerLoop")
 
+		// when not subscribed the runs are only scheduled by the periodic full
+		// scan
+		c := ns.subscribe(ctx)
+
 		if err := s.runsScheduler(ctx); err != nil {
 			s.log.Err(err).Send()
 		}
 
-		sleepCh := time.NewTimer(2 * time.Second).C
-		select {
-		case <-ctx.Done():
-			return
-		case <-sleepCh:
+		// schedule the notified runs and periodically do a full scan of all
+		// the runs to handle lost notifications
+		timer := time.NewTimer(runsSchedulerInterval)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case runID, ok := <-c:
+				if !ok {
+					timer.Stop()
+					if ctx.Err() != nil {
+						return
+					}
+					ns.closed()
+					break wait
+				}
+				if runID == "" {
+					timer.Stop()
+					break wait
+				}
+				if err := s.scheduleRun(ctx, runID); err != nil {
+					s.log.Err(err).Send()
+				}
+			case <-timer.C:
+				break wait
+			}
 		}
 	}
 }
@@ -1477,6 +1632,8 @@ func (s *Runservice) runsScheduler(ctx context.Context) error {
 		return errors.WithStack(err)
 	}
 
+	updateRunsMetrics(runs)
+
 	for _, r := range runs {
 		if err := s.runScheduler(ctx, r); err != nil {
 			s.log.Err(err).Send()
@@ -48,6 +48,24 @@ func OSTRunTaskStepLogPath(rtID string, step int) string {
 	return path.Join(OSTRunTaskLogsDataDir(rtID), "steps", fmt.Sprintf("%d.log", step))
 }
 
+// OSTRunTaskSetupStructuredLogPath returns the path of the setup log saved
+// using the structured log format. OSTRunTaskSetupLogPath is the path of the
+// raw text log saved by older versions.
+func OSTRunTaskSetupStructuredLogPath(rtID string) string {
+	return path.Join(OSTRunTaskLogsDataDir(rtID), "setup.ndjson")
+}
+
+// OSTRunTaskStepStructuredLogPath returns the path of the step log saved
+// using the structured log format. OSTRunTaskStepLogPath is the path of the
+// raw text log saved by older versions.
+func OSTRunTaskStepStructuredLogPath(rtID string, step int) string {
+	return path.Join(OSTRunTaskLogsDataDir(rtID), "steps", fmt.Sprintf("%d.ndjson", step))
+}
+
+func OSTRunTaskLogIndexPath(logPath string) string {
+	return logPath + ".index"
+}
+
 func OSTRunTaskLogsRunPath(rtID, runID string) string {
 	return path.Join(OSTRunTaskLogsRunsDir(rtID), runID)
 }
@@ -0,0 +1,88 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"agola.io/agola/internal/sqlg/notify"
+)
+
+const (
+	subscribeMinRetryInterval = 1 * time.Second
+	subscribeMaxRetryInterval = 1 * time.Minute
+)
+
+// notifySubscriber keeps a subscription to a notify channel. When subscribing
+// fails or the subscription channel is closed, the subscription is retried
+// with an exponential backoff.
+type notifySubscriber struct {
+	log     zerolog.Logger
+	n       notify.Notifier
+	channel string
+
+	c             <-chan string
+	retryInterval time.Duration
+	nextAttempt   time.Time
+}
+
+func newNotifySubscriber(log zerolog.Logger, n notify.Notifier, channel string) *notifySubscriber {
+	return &notifySubscriber{
+		log:     log,
+		n:       n,
+		channel: channel,
+	}
+}
+
+// subscribe subscribes to the channel if not already subscribed and the retry
+// interval after the last failure has passed. It returns the subscription
+// channel or nil (that blocks forever when received from) if not subscribed.
+func (ns *notifySubscriber) subscribe(ctx context.Context) <-chan string {
+	if ns.c != nil || time.Now().Before(ns.nextAttempt) {
+		return ns.c
+	}
+
+	c, err := ns.n.Subscribe(ctx, ns.channel)
+	if err != nil {
+		ns.failed()
+		ns.log.Err(err).Msgf("failed to subscribe to %q notifications, retrying in %s", ns.channel, ns.retryInterval)
+		return nil
+	}
+
+	ns.c = c
+	ns.retryInterval = 0
+
+	return ns.c
+}
+
+// closed must be called when the subscription channel has been closed.
+func (ns *notifySubscriber) closed() {
+	ns.c = nil
+	ns.failed()
+	ns.log.Warn().Msgf("%q notifications subscription closed, retrying in %s", ns.channel, ns.retryInterval)
+}
+
+// retryWait returns the time to wait before retrying the subscription.
+func (ns *notifySubscriber) retryWait() time.Duration {
+	return max(time.Until(ns.nextAttempt), 0)
+}
+
+func (ns *notifySubscriber) failed() {
+	ns.retryInterval = min(max(ns.retryInterval*2, subscribeMinRetryInterval), subscribeMaxRetryInterval)
+	ns.nextAttempt = time.Now().Add(ns.retryInterval)
+}
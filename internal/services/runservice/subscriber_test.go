@@ -0,0 +1,91 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sorintlab/errors"
+	"gotest.tools/v3/assert"
+
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/testutil"
+)
+
+// failingNotifier is a notifier whose subscriptions fail the first failures
+// times.
+type failingNotifier struct {
+	failures int
+	attempts int
+	c        chan string
+}
+
+func (n *failingNotifier) Notify(tx *sql.Tx, channel, payload string) error {
+	return nil
+}
+
+func (n *failingNotifier) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	n.attempts++
+	if n.attempts <= n.failures {
+		return nil, errors.Errorf("subscribe failed")
+	}
+	n.c = make(chan string)
+	return n.c, nil
+}
+
+func TestNotifySubscriber(t *testing.T) {
+	t.Parallel()
+
+	log := testutil.NewLogger(t)
+	ctx := context.Background()
+
+	n := &failingNotifier{failures: 2}
+	ns := newNotifySubscriber(log, n, "channel01")
+
+	// the first subscription fails and isn't retried before the retry interval
+	assert.Assert(t, ns.subscribe(ctx) == nil)
+	assert.Equal(t, n.attempts, 1)
+	assert.Assert(t, ns.subscribe(ctx) == nil)
+	assert.Equal(t, n.attempts, 1)
+	assert.Assert(t, ns.retryWait() > 0 && ns.retryWait() <= subscribeMinRetryInterval)
+
+	// the retry interval is increased at every failure
+	ns.nextAttempt = time.Now()
+	assert.Assert(t, ns.subscribe(ctx) == nil)
+	assert.Equal(t, n.attempts, 2)
+	assert.Equal(t, ns.retryInterval, 2*subscribeMinRetryInterval)
+
+	ns.nextAttempt = time.Now()
+	c := ns.subscribe(ctx)
+	assert.Assert(t, c != nil)
+	assert.Equal(t, n.attempts, 3)
+	assert.Equal(t, ns.retryInterval, time.Duration(0))
+
+	// an active subscription is kept
+	assert.Assert(t, ns.subscribe(ctx) == c)
+	assert.Equal(t, n.attempts, 3)
+
+	// a closed subscription is retried
+	close(n.c)
+	ns.closed()
+	assert.Assert(t, ns.subscribe(ctx) == nil)
+	assert.Equal(t, n.attempts, 3)
+
+	ns.nextAttempt = time.Now()
+	assert.Assert(t, ns.subscribe(ctx) != nil)
+	assert.Equal(t, n.attempts, 4)
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sorintlab/errors"
+
+	rsclient "agola.io/agola/services/runservice/client"
+	rstypes "agola.io/agola/services/runservice/types"
+)
+
+func (s *Scheduler) runEventsLoop(ctx context.Context, scheduleCh chan<- struct{}) {
+	for {
+		if err := s.runEvents(ctx, scheduleCh); err != nil {
+			s.log.Err(err).Send()
+		}
+
+		sleepCh := time.NewTimer(1 * time.Second).C
+		select {
+		case <-ctx.Done():
+			return
+		case <-sleepCh:
+		}
+	}
+}
+
+// runEvents watches the runservice run events and requests a new schedule
+// when a run is queued or finished.
+func (s *Scheduler) runEvents(ctx context.Context, scheduleCh chan<- struct{}) error {
+	resp, err := s.runserviceClient.GetRunEvents(ctx, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("http status code: %d", resp.StatusCode)
+	}
+	defer resp.Body.Close()
+
+	// the run events stream starts from the last run event so do a schedule
+	// to handle the runs changed in the meantime
+	requestSchedule(scheduleCh)
+
+	return errors.WithStack(rsclient.ReadRunEvents(resp.Body, func(ev *rstypes.RunEvent) error {
+		if ev.Phase == rstypes.RunPhaseQueued || ev.Phase == rstypes.RunPhaseFinished {
+			requestSchedule(scheduleCh)
+		}
+		return nil
+	}))
+}
+
+// requestSchedule requests a new schedule without blocking if one is already
+// pending.
+func requestSchedule(scheduleCh chan<- struct{}) {
+	select {
+	case scheduleCh <- struct{}{}:
+	default:
+	}
+}
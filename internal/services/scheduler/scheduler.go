@@ -16,7 +16,6 @@ package scheduler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -31,16 +30,23 @@ import (
 	rsclient "agola.io/agola/services/runservice/client"
 )
 
-func (s *Scheduler) scheduleLoop(ctx context.Context) {
+const (
+	// scheduleInterval is the interval between schedules when no run events
+	// requesting a schedule are received
+	scheduleInterval = 10 * time.Second
+)
+
+func (s *Scheduler) scheduleLoop(ctx context.Context, scheduleCh <-chan struct{}) {
 	for {
 		if err := s.schedule(ctx); err != nil {
 			s.log.Err(err).Send()
 		}
 
-		sleepCh := time.NewTimer(1 * time.Second).C
+		sleepCh := time.NewTimer(scheduleInterval).C
 		select {
 		case <-ctx.Done():
 			return
+		case <-scheduleCh:
 		case <-sleepCh:
 		}
 	}
@@ -159,20 +165,21 @@ func (s *Scheduler) approveRunTasks(ctx context.Context, runID string) error {
 		if !ok {
 			return errors.Errorf("run %q doesn't have task %q", run.ID, rtID)
 		}
+		rct, ok := runResp.RunConfig.Tasks[rtID]
+		if !ok {
+			return errors.Errorf("run config %q doesn't have task %q", runResp.RunConfig.ID, rtID)
+		}
 		annotations := rt.Annotations
 		if annotations == nil {
 			continue
 		}
-		approversAnnotation, ok := annotations[common.ApproversAnnotation]
-		if !ok {
-			continue
-		}
-		var approvers []string
-		if err := json.Unmarshal([]byte(approversAnnotation), &approvers); err != nil {
-			return errors.Wrapf(err, "failed to unmarshal run task approvers annotation")
+		approvals, err := common.GetRunTaskApprovals(annotations)
+		if err != nil {
+			return errors.WithStack(err)
 		}
-		// TODO(sgotti) change when we introduce a config the set the minimum number of required approvers
-		if len(approvers) > 0 {
+		// only count the approvals not expired
+		validApprovals := common.ValidRunTaskApprovals(approvals, rct.ApprovalPolicy, time.Now())
+		if len(validApprovals) >= rct.ApprovalPolicy.RequiredApprovers() {
 			rsreq := &rsapitypes.RunTaskActionsRequest{
 				ActionType:              rsapitypes.RunTaskActionTypeApprove,
 				ChangeGroupsUpdateToken: runResp.ChangeGroupsUpdateToken,
@@ -205,7 +212,10 @@ func NewScheduler(ctx context.Context, log zerolog.Logger, c *config.Scheduler)
 }
 
 func (s *Scheduler) Run(ctx context.Context) error {
-	go s.scheduleLoop(ctx)
+	scheduleCh := make(chan struct{}, 1)
+
+	go s.runEventsLoop(ctx, scheduleCh)
+	go s.scheduleLoop(ctx, scheduleCh)
 	go s.approveLoop(ctx)
 
 	<-ctx.Done()
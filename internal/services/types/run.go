@@ -34,4 +34,7 @@ type RunCreationTriggerType string
 const (
 	RunCreationTriggerTypeWebhook RunCreationTriggerType = "webhook"
 	RunCreationTriggerTypeManual  RunCreationTriggerType = "manual"
+	// RunCreationTriggerTypePoll is used for runs created by the mirror
+	// projects poller
+	RunCreationTriggerTypePoll RunCreationTriggerType = "poll"
 )
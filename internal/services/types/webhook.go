@@ -22,6 +22,22 @@ const (
 	WebhookEventPullRequest WebhookEvent = "pull_request"
 )
 
+type WebhookPullRequestAction string
+
+const (
+	WebhookPullRequestActionOpened       WebhookPullRequestAction = "opened"
+	WebhookPullRequestActionSynchronized WebhookPullRequestAction = "synchronized"
+	WebhookPullRequestActionReopened     WebhookPullRequestAction = "reopened"
+	WebhookPullRequestActionLabeled      WebhookPullRequestAction = "labeled"
+	WebhookPullRequestActionClosed       WebhookPullRequestAction = "closed"
+	WebhookPullRequestActionMerged       WebhookPullRequestAction = "merged"
+)
+
+// IsClosed returns true if the action closes the pull request
+func (a WebhookPullRequestAction) IsClosed() bool {
+	return a == WebhookPullRequestActionClosed || a == WebhookPullRequestActionMerged
+}
+
 type WebhookData struct {
 	Event  WebhookEvent `json:"event,omitempty"`
 	SSHURL string       `json:"ssh_url"`
@@ -32,6 +48,7 @@ type WebhookData struct {
 	Ref         string `json:"ref,omitempty"`          // Ref containing the commit SHA
 	Message     string `json:"message,omitempty"`      // Message to use (Push last commit message summary, PR title, Tag message etc...)
 	Sender      string `json:"sender,omitempty"`
+	Author      string `json:"author,omitempty"` // Remote user name of the commit author (push, tag) or of the pull request author, empty if not provided by the git source
 	Avatar      string `json:"avatar,omitempty"`
 
 	Branch     string `json:"branch,omitempty"`
@@ -45,6 +62,15 @@ type WebhookData struct {
 	PullRequestLink string `json:"link,omitempty"` // Link to pull request
 	PRFromSameRepo  bool   `json:"pr_from_same_repo,omitempty"`
 
+	PullRequestAction       WebhookPullRequestAction `json:"pull_request_action,omitempty"`
+	PullRequestLabels       []string                 `json:"pull_request_labels,omitempty"`
+	PullRequestTargetBranch string                   `json:"pull_request_target_branch,omitempty"`
+	PullRequestDraft        bool                     `json:"pull_request_draft,omitempty"`
+	// ClosedPullRequestIDs are the ids of all the pull requests closed by a
+	// closed or merged pull request event when they're more than the event
+	// pull request (i.e. all the patch sets of a gerrit change)
+	ClosedPullRequestIDs []string `json:"closed_pull_request_ids,omitempty"`
+
 	Repo WebhookDataRepo `json:"repo,omitempty"`
 }
 
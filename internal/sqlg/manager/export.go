@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"os"
 
 	"github.com/sorintlab/errors"
 
@@ -13,43 +14,98 @@ import (
 
 func (m *DBManager) Export(ctx context.Context, objectKinds []string, w io.Writer) error {
 	bw := bufio.NewWriter(w)
-	e := json.NewEncoder(bw)
 
 	err := m.d.Do(ctx, func(tx *sql.Tx) error {
-		for _, objectKind := range objectKinds {
-			var curStartID string
+		return errors.WithStack(m.export(tx, objectKinds, bw))
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(bw.Flush())
+}
+
+// ExportToFile exports the objects to f in a single transaction. Since the
+// transaction could be retried, f is truncated at every transaction attempt.
+func (m *DBManager) ExportToFile(ctx context.Context, objectKinds []string, f *os.File) error {
+	err := m.d.Do(ctx, func(tx *sql.Tx) error {
+		if err := f.Truncate(0); err != nil {
+			return errors.WithStack(err)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.WithStack(err)
+		}
+
+		bw := bufio.NewWriter(f)
+		if err := m.export(tx, objectKinds, bw); err != nil {
+			return errors.WithStack(err)
+		}
+
+		return errors.WithStack(bw.Flush())
+	})
+
+	return errors.WithStack(err)
+}
+
+func (m *DBManager) export(tx *sql.Tx, objectKinds []string, w io.Writer) error {
+	e := json.NewEncoder(w)
 
-			for {
-				q := m.d.SelectObject(objectKind).OrderBy("id asc")
-				q = q.Where(q.G("id", curStartID))
-				q = q.Limit(MaxQueryLimit)
+	for _, objectKind := range objectKinds {
+		var curStartID string
 
-				objs, err := m.d.FetchObjects(tx, objectKind, q)
-				if err != nil {
+		for {
+			q := m.d.SelectObject(objectKind).OrderBy("id asc")
+			q = q.Where(q.G("id", curStartID))
+			q = q.Limit(MaxQueryLimit)
+
+			objs, err := m.d.FetchObjects(tx, objectKind, q)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			var lastID string
+			for _, obj := range objs {
+				if err := m.d.ObjectToExportJSON(obj, e); err != nil {
 					return errors.WithStack(err)
 				}
 
-				var lastID string
-				for _, obj := range objs {
-					if err := m.d.ObjectToExportJSON(obj, e); err != nil {
-						return errors.WithStack(err)
-					}
+				lastID = obj.GetID()
+			}
 
-					lastID = obj.GetID()
-				}
+			if len(objs) < MaxQueryLimit {
+				break
+			}
 
-				if len(objs) < MaxQueryLimit {
-					break
-				}
+			curStartID = lastID
+		}
+	}
 
-				curStartID = lastID
+	return nil
+}
+
+// IsEmpty reports whether no objects of the provided kinds exist.
+func (m *DBManager) IsEmpty(ctx context.Context, objectKinds []string) (bool, error) {
+	empty := true
+	err := m.d.Do(ctx, func(tx *sql.Tx) error {
+		empty = true
+		for _, objectKind := range objectKinds {
+			q := m.d.SelectObject(objectKind).Limit(1)
+
+			objs, err := m.d.FetchObjects(tx, objectKind, q)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if len(objs) > 0 {
+				empty = false
+				return nil
 			}
 		}
+
 		return nil
 	})
 	if err != nil {
-		return errors.WithStack(err)
+		return false, errors.WithStack(err)
 	}
 
-	return errors.WithStack(bw.Flush())
+	return empty, nil
 }
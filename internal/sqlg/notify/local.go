@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+// LocalNotifier is an in process notifier. It should be used only when all
+// the notifiers and subscribers live in the same process (i.e. with sqlite).
+type LocalNotifier struct {
+	b *broadcaster
+}
+
+func NewLocalNotifier() *LocalNotifier {
+	return &LocalNotifier{b: newBroadcaster()}
+}
+
+func (n *LocalNotifier) Notify(tx *sql.Tx, channel, payload string) error {
+	tx.OnCommit(func() { n.b.send(channel, payload) })
+
+	return nil
+}
+
+func (n *LocalNotifier) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	s, _ := n.b.subscribe(channel)
+
+	go func() {
+		<-ctx.Done()
+		n.b.unsubscribe(channel, s)
+	}()
+
+	return s.c, nil
+}
@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"sync"
+
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+const subscriberBufferSize = 100
+
+// Notifier sends and receives notifications on named channels.
+//
+// Notifications are only a hint that something changed: delivery is best
+// effort and a slow subscriber may miss some of them, so subscribers must
+// always read the current state from the db and keep a fallback periodic
+// check. An empty payload means that some notifications may have been lost
+// (i.e. after a db reconnection) and the subscriber should resync.
+type Notifier interface {
+	// Notify sends a notification with the provided payload on channel when
+	// the transaction is committed.
+	Notify(tx *sql.Tx, channel, payload string) error
+	// Subscribe returns a chan receiving the notification payloads sent on
+	// channel. The subscription is removed and the returned chan closed when
+	// ctx is done.
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
+}
+
+type subscriber struct {
+	c chan string
+}
+
+// broadcaster dispatches the payloads to the channel subscribers.
+type broadcaster struct {
+	subs map[string]map[*subscriber]struct{}
+	m    sync.Mutex
+}
+
+func newBroadcaster() *broadcaster {
+	return &broadcaster{subs: make(map[string]map[*subscriber]struct{})}
+}
+
+// subscribe adds a new subscriber to channel and reports if it's the first
+// one.
+func (b *broadcaster) subscribe(channel string) (*subscriber, bool) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	s := &subscriber{c: make(chan string, subscriberBufferSize)}
+
+	subs, ok := b.subs[channel]
+	if !ok {
+		subs = make(map[*subscriber]struct{})
+		b.subs[channel] = subs
+	}
+	subs[s] = struct{}{}
+
+	return s, !ok
+}
+
+// unsubscribe removes the subscriber from channel, closes its chan and
+// reports if it was the last one.
+func (b *broadcaster) unsubscribe(channel string, s *subscriber) bool {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	subs := b.subs[channel]
+	delete(subs, s)
+	close(s.c)
+
+	if len(subs) == 0 {
+		delete(b.subs, channel)
+		return true
+	}
+
+	return false
+}
+
+func (b *broadcaster) send(channel, payload string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for s := range b.subs[channel] {
+		select {
+		case s.c <- payload:
+		default:
+			// subscriber is too slow, drop the notification
+		}
+	}
+}
+
+// sendAll sends payload to all the subscribers of all the channels.
+func (b *broadcaster) sendAll(payload string) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for _, subs := range b.subs {
+		for s := range subs {
+			select {
+			case s.c <- payload:
+			default:
+			}
+		}
+	}
+}
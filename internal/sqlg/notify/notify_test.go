@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+	"gotest.tools/v3/assert"
+
+	"agola.io/agola/internal/sqlg/sql"
+	"agola.io/agola/internal/testutil"
+)
+
+func setupNotifier(t *testing.T, ctx context.Context, dir string) (*sql.DB, Notifier) {
+	log := testutil.NewLogger(t)
+
+	sdb, _, connString := testutil.CreateDB(t, log, ctx, dir)
+
+	switch sdb.Type() {
+	case sql.Postgres:
+		return sdb, NewPGNotifier(zerolog.Nop(), connString)
+	default:
+		return sdb, NewLocalNotifier()
+	}
+}
+
+func receive(t *testing.T, c <-chan string) (string, bool) {
+	t.Helper()
+
+	select {
+	case payload, ok := <-c:
+		return payload, ok
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for notification")
+	}
+
+	return "", false
+}
+
+func TestNotify(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	sdb, n := setupNotifier(t, ctx, dir)
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	defer subCancel()
+
+	c1, err := n.Subscribe(subCtx, "channel01")
+	testutil.NilError(t, err)
+	c2, err := n.Subscribe(subCtx, "channel02")
+	testutil.NilError(t, err)
+
+	// notifications of rolled back transactions must not be sent
+	errRollback := errors.New("rollback")
+	err = sdb.Do(ctx, func(tx *sql.Tx) error {
+		if err := n.Notify(tx, "channel01", "payload00"); err != nil {
+			return errors.WithStack(err)
+		}
+		return errRollback
+	})
+	assert.Assert(t, errors.Is(err, errRollback))
+
+	err = sdb.Do(ctx, func(tx *sql.Tx) error {
+		if err := n.Notify(tx, "channel01", "payload01"); err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(n.Notify(tx, "channel02", "payload02"))
+	})
+	testutil.NilError(t, err)
+
+	payload, ok := receive(t, c1)
+	assert.Assert(t, ok)
+	assert.Equal(t, payload, "payload01")
+
+	payload, ok = receive(t, c2)
+	assert.Assert(t, ok)
+	assert.Equal(t, payload, "payload02")
+
+	subCancel()
+
+	_, ok = receive(t, c1)
+	assert.Assert(t, !ok)
+	_, ok = receive(t, c2)
+	assert.Assert(t, !ok)
+}
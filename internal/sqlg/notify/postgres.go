@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+	"github.com/sorintlab/errors"
+
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+const (
+	pgListenerMinReconnectInterval = 1 * time.Second
+	pgListenerMaxReconnectInterval = 30 * time.Second
+	pgListenerPingInterval         = 90 * time.Second
+)
+
+// PGNotifier is a notifier using postgres LISTEN/NOTIFY so notifications are
+// received by all the processes connected to the same db.
+type PGNotifier struct {
+	log        zerolog.Logger
+	connString string
+	b          *broadcaster
+
+	l  *pq.Listener
+	m  sync.Mutex
+	lm sync.Mutex
+}
+
+func NewPGNotifier(log zerolog.Logger, connString string) *PGNotifier {
+	return &PGNotifier{
+		log:        log,
+		connString: connString,
+		b:          newBroadcaster(),
+	}
+}
+
+func (n *PGNotifier) Notify(tx *sql.Tx, channel, payload string) error {
+	// pg_notify inside the transaction will deliver the notification only
+	// when the transaction is committed
+	if _, err := tx.Exec("select pg_notify($1, $2)", channel, payload); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (n *PGNotifier) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	l := n.listener()
+
+	// serialize listen and unlisten to keep them in sync with the
+	// subscribers
+	n.lm.Lock()
+	defer n.lm.Unlock()
+
+	s, first := n.b.subscribe(channel)
+	if first {
+		if err := l.Listen(channel); err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			n.b.unsubscribe(channel, s)
+			return nil, errors.Wrapf(err, "failed to listen on channel %q", channel)
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		n.lm.Lock()
+		defer n.lm.Unlock()
+
+		if last := n.b.unsubscribe(channel, s); last {
+			if err := l.Unlisten(channel); err != nil && !errors.Is(err, pq.ErrChannelNotOpen) {
+				n.log.Warn().Err(err).Msgf("failed to unlisten on channel %q", channel)
+			}
+		}
+	}()
+
+	return s.c, nil
+}
+
+// listener returns the postgres listener, creating it at the first call.
+func (n *PGNotifier) listener() *pq.Listener {
+	n.m.Lock()
+	defer n.m.Unlock()
+
+	if n.l != nil {
+		return n.l
+	}
+
+	n.l = pq.NewListener(n.connString, pgListenerMinReconnectInterval, pgListenerMaxReconnectInterval, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			n.log.Warn().Err(err).Msg("db listener error")
+		}
+	})
+
+	go n.dispatch(n.l)
+
+	return n.l
+}
+
+func (n *PGNotifier) dispatch(l *pq.Listener) {
+	for {
+		select {
+		case pn, ok := <-l.Notify:
+			if !ok {
+				return
+			}
+			// a nil notification is sent after a reconnection, notifications
+			// may have been lost in the meantime
+			if pn == nil {
+				n.b.sendAll("")
+				continue
+			}
+			n.b.send(pn.Channel, pn.Extra)
+
+		case <-time.After(pgListenerPingInterval):
+			go func() { _ = l.Ping() }()
+		}
+	}
+}
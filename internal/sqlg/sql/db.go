@@ -9,8 +9,14 @@ import (
 	"github.com/gofrs/uuid/v5"
 	"github.com/lib/pq"
 	"github.com/sorintlab/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("agola.io/agola/internal/sqlg/sql")
+
 type Type string
 
 const (
@@ -99,6 +105,8 @@ type Tx struct {
 	db  *DB
 	tx  *sql.Tx
 	ctx context.Context
+
+	onCommit []func()
 }
 
 func (db *DB) Close() error {
@@ -132,6 +140,9 @@ func (db *DB) NewTx(ctx context.Context) (*Tx, error) {
 }
 
 func (db *DB) Do(ctx context.Context, f func(tx *Tx) error) error {
+	ctx, span := tracer.Start(ctx, "sql.Do", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.String("db.system", string(db.data.t))))
+	defer span.End()
+
 	retries := 0
 	for {
 		err := db.do(ctx, f)
@@ -160,6 +171,13 @@ func (db *DB) Do(ctx context.Context, f func(tx *Tx) error) error {
 				}
 			}
 		}
+
+		span.SetAttributes(attribute.Int("db.transaction.retries", retries))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "transaction failed")
+		}
+
 		return errors.WithStack(err)
 	}
 }
@@ -244,7 +262,22 @@ func (tx *Tx) Commit() error {
 	if tx.tx == nil {
 		return nil
 	}
-	return errors.WithStack(tx.tx.Commit())
+	if err := tx.tx.Commit(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, f := range tx.onCommit {
+		f()
+	}
+
+	return nil
+}
+
+// OnCommit registers a function that will be called after the transaction
+// has been successfully committed. The functions aren't called if the
+// transaction is rolled back.
+func (tx *Tx) OnCommit(f func()) {
+	tx.onCommit = append(tx.onCommit, f)
 }
 
 func (tx *Tx) Rollback() error {
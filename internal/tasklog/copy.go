@@ -0,0 +1,190 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasklog
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/sorintlab/errors"
+)
+
+type CopyOptions struct {
+	Format Format
+	// Start is the number of the first line to copy.
+	Start uint64
+	// Limit is the maximum number of lines to copy. If zero all the lines are
+	// copied.
+	Limit uint64
+}
+
+// ParseCopyOptions parses the copy options from the format, start and limit
+// query parameters.
+func ParseCopyOptions(q url.Values) (*CopyOptions, error) {
+	format, err := ParseFormat(q.Get("format"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	opts := &CopyOptions{Format: format}
+	if s := q.Get("start"); s != "" {
+		opts.Start, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid start line %q", s)
+		}
+	}
+	if s := q.Get("limit"); s != "" {
+		opts.Limit, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid lines limit %q", s)
+		}
+	}
+
+	return opts, nil
+}
+
+// Add adds the copy options to the provided query parameters.
+func (o *CopyOptions) Add(q url.Values) {
+	if o.Format != "" {
+		q.Add("format", string(o.Format))
+	}
+	if o.Start > 0 {
+		q.Add("start", strconv.FormatUint(o.Start, 10))
+	}
+	if o.Limit > 0 {
+		q.Add("limit", strconv.FormatUint(o.Limit, 10))
+	}
+}
+
+type flusher interface {
+	Flush()
+}
+
+type lineReader struct {
+	br  *bufio.Reader
+	raw bool
+	n   uint64
+}
+
+// next returns the next line and its encoded ndjson data (nil for raw logs).
+func (r *lineReader) next() (*Line, []byte, error) {
+	for {
+		data, err := r.br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, nil, errors.WithStack(err)
+		}
+		if len(data) == 0 {
+			return nil, nil, io.EOF
+		}
+
+		if r.raw {
+			l := &Line{Number: r.n, Partial: true}
+			if data[len(data)-1] == '\n' {
+				data = data[:len(data)-1]
+				l.Partial = false
+			}
+			l.setText(data)
+			r.n++
+			return l, nil, nil
+		}
+
+		// ignore an unterminated line since it's not completely written
+		if data[len(data)-1] != '\n' {
+			return nil, nil, io.EOF
+		}
+		var l *Line
+		if err := json.Unmarshal(data, &l); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to decode log line")
+		}
+		if l == nil {
+			continue
+		}
+		return l, data, nil
+	}
+}
+
+// Copy copies the lines of the structured log read from r to w in the
+// requested format. The data written to w is flushed every time there's no
+// more data to read, so w could be an http.ResponseWriter used to stream a
+// log.
+func Copy(w io.Writer, r io.Reader, opts *CopyOptions) error {
+	return copyLines(w, &lineReader{br: bufio.NewReader(r)}, opts)
+}
+
+// CopyRaw is like Copy but reads a raw text log, like logs saved before the
+// structured log format was introduced. The lines of a raw text log don't have
+// a time and a stream.
+func CopyRaw(w io.Writer, r io.Reader, opts *CopyOptions) error {
+	return copyLines(w, &lineReader{br: bufio.NewReader(r), raw: true}, opts)
+}
+
+func copyLines(w io.Writer, lr *lineReader, opts *CopyOptions) error {
+	bw := bufio.NewWriter(w)
+	flush := func() error {
+		if err := bw.Flush(); err != nil {
+			return errors.WithStack(err)
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+		return nil
+	}
+
+	var count uint64
+	for {
+		if opts.Limit > 0 && count >= opts.Limit {
+			break
+		}
+
+		l, data, err := lr.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return errors.WithStack(err)
+		}
+		if l.Number < opts.Start {
+			continue
+		}
+
+		switch opts.Format {
+		case FormatNDJSON:
+			if data == nil {
+				data, err = json.Marshal(l)
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				data = append(data, '\n')
+			}
+			_, err = bw.Write(data)
+		default:
+			_, err = bw.WriteString(l.RawText())
+		}
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		count++
+
+		if lr.br.Buffered() == 0 {
+			if err := flush(); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+
+	return flush()
+}
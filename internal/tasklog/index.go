@@ -0,0 +1,85 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasklog
+
+import (
+	"bytes"
+)
+
+// IndexInterval is the number of lines between two indexed line offsets.
+const IndexInterval = 1000
+
+// Index contains the byte offsets of a subset of the lines of a structured
+// log and is used to start reading a log from a line without reading all the
+// previous lines.
+type Index struct {
+	// Interval is the number of lines between two offsets.
+	Interval uint64 `json:"interval"`
+	// Lines is the number of log lines.
+	Lines uint64 `json:"lines"`
+	// Offsets contains the byte offset of every Interval line: Offsets[i] is
+	// the offset of line i*Interval.
+	Offsets []int64 `json:"offsets"`
+}
+
+// Offset returns the byte offset of the nearest indexed line before or equal
+// to line start.
+func (idx *Index) Offset(start uint64) int64 {
+	if idx.Interval == 0 || len(idx.Offsets) == 0 {
+		return 0
+	}
+	i := start / idx.Interval
+	if i >= uint64(len(idx.Offsets)) {
+		i = uint64(len(idx.Offsets)) - 1
+	}
+	return idx.Offsets[i]
+}
+
+// Indexer is an io.Writer that creates the index of the structured log
+// written to it.
+type Indexer struct {
+	idx    Index
+	offset int64
+}
+
+func NewIndexer() *Indexer {
+	return &Indexer{idx: Index{Interval: IndexInterval, Offsets: []int64{}}}
+}
+
+func (x *Indexer) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if x.idx.Lines%x.idx.Interval == 0 && uint64(len(x.idx.Offsets)) == x.idx.Lines/x.idx.Interval {
+			x.idx.Offsets = append(x.idx.Offsets, x.offset)
+		}
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			x.offset += int64(len(p))
+			break
+		}
+		x.offset += int64(i + 1)
+		x.idx.Lines++
+		p = p[i+1:]
+	}
+
+	return n, nil
+}
+
+// Index returns the index of the data written until now.
+func (x *Indexer) Index() *Index {
+	idx := x.idx
+	idx.Offsets = append([]int64{}, x.idx.Offsets...)
+	return &idx
+}
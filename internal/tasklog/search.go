@@ -0,0 +1,99 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasklog
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/sorintlab/errors"
+)
+
+type SearchOptions struct {
+	// Match reports whether a line text matches the search.
+	Match func(text string) bool
+	// Context is the number of lines to return before and after a matching
+	// line.
+	Context int
+	// Raw must be true when searching a raw text log.
+	Raw bool
+}
+
+// Match is a log line matching a search.
+type Match struct {
+	Line   *Line
+	Before []*Line
+	After  []*Line
+}
+
+// Search reads the log from r line by line and calls f for every matching
+// line. It stops when the log ends or f returns false.
+// Only the context lines are kept in memory, so it can be used to search big
+// logs.
+func Search(r io.Reader, opts *SearchOptions, f func(m *Match) bool) error {
+	lr := &lineReader{br: bufio.NewReader(r), raw: opts.Raw}
+
+	// before is the ring of the last opts.Context lines
+	before := make([]*Line, 0, opts.Context)
+	// pending are the matches waiting for their after context lines
+	pending := []*Match{}
+
+	for {
+		l, _, err := lr.next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return errors.WithStack(err)
+		}
+
+		for _, m := range pending {
+			m.After = append(m.After, l)
+		}
+		// pending matches are ordered so the first ones are completed first
+		for len(pending) > 0 && len(pending[0].After) == opts.Context {
+			if !f(pending[0]) {
+				return nil
+			}
+			pending = pending[1:]
+		}
+
+		if opts.Match(l.Text) {
+			m := &Match{Line: l, Before: append([]*Line{}, before...)}
+			if opts.Context == 0 {
+				if !f(m) {
+					return nil
+				}
+			} else {
+				pending = append(pending, m)
+			}
+		}
+
+		if opts.Context > 0 {
+			if len(before) == opts.Context {
+				before = append(before[:0], before[1:]...)
+			}
+			before = append(before, l)
+		}
+	}
+
+	for _, m := range pending {
+		if !f(m) {
+			return nil
+		}
+	}
+
+	return nil
+}
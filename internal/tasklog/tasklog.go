@@ -0,0 +1,257 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tasklog implements the structured task log format.
+//
+// A task log is stored as newline delimited json (ndjson): every log line
+// is a json object containing the line number, the time when the line was
+// produced, the stream (stdout or stderr, empty when they can't be
+// distinguished like when a tty is used) and the line text.
+// Since json strings can only contain valid UTF-8, the text of a line that
+// isn't valid UTF-8 has its invalid bytes replaced and the raw line bytes are
+// also saved base64 encoded in the data field.
+package tasklog
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sorintlab/errors"
+)
+
+// MaxLineSize is the maximum size of a line text. Longer lines are split in
+// multiple partial lines, at an UTF-8 character boundary when possible.
+const MaxLineSize = 64 * 1024
+
+type Format string
+
+const (
+	// FormatText is the raw log text.
+	FormatText Format = "text"
+	// FormatNDJSON are the log lines encoded as newline delimited json.
+	FormatNDJSON Format = "ndjson"
+)
+
+const (
+	TextContentType   = "text/plain;charset=UTF-8"
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// ParseFormat parses a log format. An empty format is the text format.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	default:
+		return "", errors.Errorf("unknown log format %q", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == FormatNDJSON {
+		return NDJSONContentType
+	}
+	return TextContentType
+}
+
+type Stream string
+
+const (
+	// StreamCombined is used when stdout and stderr are written to the same
+	// stream (i.e. when using a tty).
+	StreamCombined Stream = ""
+	StreamStdout   Stream = "stdout"
+	StreamStderr   Stream = "stderr"
+)
+
+// Line is a log line.
+type Line struct {
+	// Number is the zero based line number.
+	Number uint64 `json:"n"`
+	// Time is the time when the line was started. It's empty for logs saved
+	// before the structured log format was introduced.
+	Time   *time.Time `json:"time,omitempty"`
+	Stream Stream     `json:"stream,omitempty"`
+	Text   string     `json:"text"`
+	// Data is the raw line text. It's only set when the text isn't valid
+	// UTF-8 and so Text contains replacement characters.
+	Data []byte `json:"data,omitempty"`
+	// Partial is true when the line text isn't terminated by a newline. This
+	// happens for the last log line or for lines longer than MaxLineSize.
+	Partial bool `json:"partial,omitempty"`
+}
+
+// RawText returns the line raw text including the trailing newline.
+func (l *Line) RawText() string {
+	text := l.Text
+	if l.Data != nil {
+		text = string(l.Data)
+	}
+	if l.Partial {
+		return text
+	}
+	return text + "\n"
+}
+
+// setText sets the line text, keeping the raw text in Data when it isn't
+// valid UTF-8.
+func (l *Line) setText(text []byte) {
+	if utf8.Valid(text) {
+		l.Text = string(text)
+		l.Data = nil
+		return
+	}
+	l.Text = strings.ToValidUTF8(string(text), string(utf8.RuneError))
+	l.Data = append([]byte(nil), text...)
+}
+
+// Writer writes structured log lines to an underlying writer. Every stream
+// writer returned by Stream splits the written data in lines and writes
+// them with their time and stream.
+type Writer struct {
+	w   io.Writer
+	now func() time.Time
+
+	m sync.Mutex
+	n uint64
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, now: time.Now}
+}
+
+func (w *Writer) writeLine(stream Stream, t time.Time, text []byte, partial bool) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	t = t.UTC()
+	l := &Line{
+		Number:  w.n,
+		Time:    &t,
+		Stream:  stream,
+		Partial: partial,
+	}
+	l.setText(text)
+	data, err := json.Marshal(l)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data = append(data, '\n')
+
+	// write every line with a single write so concurrent readers will never
+	// see lines of different streams mixed together
+	if _, err := w.w.Write(data); err != nil {
+		return errors.WithStack(err)
+	}
+	w.n++
+
+	return nil
+}
+
+// Stream returns a writer for the provided stream.
+func (w *Writer) Stream(stream Stream) *StreamWriter {
+	return &StreamWriter{w: w, stream: stream}
+}
+
+// StreamWriter is an io.Writer that splits the written data in lines. The
+// last unterminated line is kept until the next write or Flush.
+type StreamWriter struct {
+	w      *Writer
+	stream Stream
+
+	m     sync.Mutex
+	buf   []byte
+	start time.Time
+}
+
+func (sw *StreamWriter) Write(p []byte) (int, error) {
+	sw.m.Lock()
+	defer sw.m.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		if len(sw.buf) == 0 {
+			sw.start = sw.w.now()
+		}
+
+		i := 0
+		for i < len(p) && p[i] != '\n' && len(sw.buf)+i < MaxLineSize {
+			i++
+		}
+		sw.buf = append(sw.buf, p[:i]...)
+
+		switch {
+		case i < len(p) && p[i] == '\n':
+			if err := sw.w.writeLine(sw.stream, sw.start, sw.buf, false); err != nil {
+				return 0, errors.WithStack(err)
+			}
+			sw.buf = sw.buf[:0]
+			p = p[i+1:]
+		case len(sw.buf) >= MaxLineSize:
+			// don't split an UTF-8 character, keep its start for the next
+			// line
+			cut := runeBoundary(sw.buf)
+			if err := sw.w.writeLine(sw.stream, sw.start, sw.buf[:cut], true); err != nil {
+				return 0, errors.WithStack(err)
+			}
+			sw.buf = append(sw.buf[:0], sw.buf[cut:]...)
+			if len(sw.buf) > 0 {
+				sw.start = sw.w.now()
+			}
+			p = p[i:]
+		default:
+			p = p[i:]
+		}
+	}
+
+	return n, nil
+}
+
+// runeBoundary returns the length of buf without its last UTF-8 character
+// if it's incomplete. Invalid UTF-8 data is split at the end.
+func runeBoundary(buf []byte) int {
+	for i := len(buf) - 1; i >= 0 && i > len(buf)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(buf[i]) {
+			continue
+		}
+		if i > 0 && !utf8.FullRune(buf[i:]) {
+			return i
+		}
+		break
+	}
+
+	return len(buf)
+}
+
+// Flush writes the remaining unterminated line as a partial line.
+func (sw *StreamWriter) Flush() error {
+	sw.m.Lock()
+	defer sw.m.Unlock()
+
+	if len(sw.buf) == 0 {
+		return nil
+	}
+	if err := sw.w.writeLine(sw.stream, sw.start, sw.buf, true); err != nil {
+		return errors.WithStack(err)
+	}
+	sw.buf = sw.buf[:0]
+
+	return nil
+}
@@ -0,0 +1,358 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tasklog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	gocmp "github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func writeLog(t *testing.T, writes func(stdout, stderr *StreamWriter)) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+
+	stdout := w.Stream(StreamStdout)
+	stderr := w.Stream(StreamStderr)
+	writes(stdout, stderr)
+	assert.NilError(t, stdout.Flush())
+	assert.NilError(t, stderr.Flush())
+
+	return &buf
+}
+
+func decodeLines(t *testing.T, data []byte) []*Line {
+	t.Helper()
+
+	lines := []*Line{}
+	for _, l := range bytes.SplitAfter(data, []byte("\n")) {
+		if len(l) == 0 {
+			continue
+		}
+		var line *Line
+		assert.NilError(t, json.Unmarshal(l, &line))
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func TestWriter(t *testing.T) {
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		_, _ = stdout.Write([]byte("hello "))
+		_, _ = stderr.Write([]byte("error\n"))
+		_, _ = stdout.Write([]byte("world\nsecond line\nno newline"))
+	})
+
+	lines := decodeLines(t, buf.Bytes())
+	assert.Equal(t, len(lines), 4)
+
+	expected := []struct {
+		stream  Stream
+		text    string
+		partial bool
+		time    time.Time
+	}{
+		{StreamStderr, "error", false, time.Date(2026, 1, 1, 0, 0, 2, 0, time.UTC)},
+		{StreamStdout, "hello world", false, time.Date(2026, 1, 1, 0, 0, 1, 0, time.UTC)},
+		{StreamStdout, "second line", false, time.Date(2026, 1, 1, 0, 0, 3, 0, time.UTC)},
+		{StreamStdout, "no newline", true, time.Date(2026, 1, 1, 0, 0, 4, 0, time.UTC)},
+	}
+	for i, e := range expected {
+		l := lines[i]
+		assert.Equal(t, l.Number, uint64(i))
+		assert.Equal(t, l.Stream, e.stream)
+		assert.Equal(t, l.Text, e.text)
+		assert.Equal(t, l.Partial, e.partial)
+		assert.Assert(t, l.Time.Equal(e.time), "line %d: expected time %s, got %s", i, e.time, l.Time)
+	}
+}
+
+func TestWriterLongLine(t *testing.T) {
+	long := strings.Repeat("a", MaxLineSize+10)
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		_, _ = stdout.Write([]byte(long + "\n"))
+	})
+
+	lines := decodeLines(t, buf.Bytes())
+	assert.Equal(t, len(lines), 2)
+	assert.Equal(t, len(lines[0].Text), MaxLineSize)
+	assert.Equal(t, lines[0].Partial, true)
+	assert.Equal(t, len(lines[1].Text), 10)
+	assert.Equal(t, lines[1].Partial, false)
+
+	var out bytes.Buffer
+	assert.NilError(t, Copy(&out, buf, &CopyOptions{}))
+	assert.Equal(t, out.String(), long+"\n")
+}
+
+func TestWriterLongLineRuneBoundary(t *testing.T) {
+	// the 3 bytes character crosses MaxLineSize
+	long := strings.Repeat("a", MaxLineSize-1) + "€" + "b"
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		_, _ = stdout.Write([]byte(long + "\n"))
+	})
+
+	lines := decodeLines(t, buf.Bytes())
+	assert.Equal(t, len(lines), 2)
+	assert.Equal(t, lines[0].Text, strings.Repeat("a", MaxLineSize-1))
+	assert.Equal(t, lines[0].Partial, true)
+	assert.Equal(t, lines[1].Text, "€b")
+	assert.Equal(t, lines[1].Partial, false)
+	assert.Assert(t, lines[0].Data == nil && lines[1].Data == nil)
+
+	var out bytes.Buffer
+	assert.NilError(t, Copy(&out, buf, &CopyOptions{}))
+	assert.Equal(t, out.String(), long+"\n")
+}
+
+func TestWriterInvalidUTF8(t *testing.T) {
+	raw := "latin1 \xe8\xe0\nvalid\ntruncated \xe2\x82"
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		_, _ = stdout.Write([]byte(raw))
+	})
+
+	lines := decodeLines(t, buf.Bytes())
+	assert.Equal(t, len(lines), 3)
+	assert.Equal(t, lines[0].Text, "latin1 \uFFFD")
+	assert.DeepEqual(t, lines[0].Data, []byte("latin1 \xe8\xe0"))
+	assert.Equal(t, lines[1].Text, "valid")
+	assert.Assert(t, lines[1].Data == nil)
+	assert.Equal(t, lines[2].Text, "truncated \uFFFD")
+	assert.DeepEqual(t, lines[2].Data, []byte("truncated \xe2\x82"))
+
+	var out bytes.Buffer
+	assert.NilError(t, Copy(&out, bytes.NewReader(buf.Bytes()), &CopyOptions{}))
+	assert.Equal(t, out.String(), raw)
+
+	// converting a raw log to ndjson keeps the raw text
+	var ndjson bytes.Buffer
+	assert.NilError(t, CopyRaw(&ndjson, strings.NewReader(raw), &CopyOptions{Format: FormatNDJSON}))
+	out.Reset()
+	assert.NilError(t, Copy(&out, &ndjson, &CopyOptions{}))
+	assert.Equal(t, out.String(), raw)
+}
+
+func TestCopy(t *testing.T) {
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		for i := 0; i < 10; i++ {
+			fmt.Fprintf(stdout, "line %d\n", i)
+		}
+		_, _ = stdout.Write([]byte("last"))
+	})
+	data := buf.Bytes()
+
+	tests := []struct {
+		name string
+		opts *CopyOptions
+		out  string
+	}{
+		{
+			name: "all lines as text",
+			opts: &CopyOptions{},
+			out:  "line 0\nline 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nlast",
+		},
+		{
+			name: "range",
+			opts: &CopyOptions{Start: 3, Limit: 2},
+			out:  "line 3\nline 4\n",
+		},
+		{
+			name: "start after the end",
+			opts: &CopyOptions{Start: 20},
+			out:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NilError(t, Copy(&out, bytes.NewReader(data), tt.opts))
+			assert.Equal(t, out.String(), tt.out)
+		})
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NilError(t, Copy(&out, bytes.NewReader(data), &CopyOptions{Format: FormatNDJSON, Start: 9}))
+		lines := decodeLines(t, out.Bytes())
+		assert.Equal(t, len(lines), 2)
+		assert.Equal(t, lines[0].Number, uint64(9))
+		assert.Equal(t, lines[0].Text, "line 9")
+		assert.Equal(t, lines[1].Text, "last")
+		assert.Equal(t, lines[1].Partial, true)
+	})
+
+	t.Run("unterminated last line is ignored", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NilError(t, Copy(&out, bytes.NewReader(data[:len(data)-5]), &CopyOptions{Start: 9}))
+		assert.Equal(t, out.String(), "line 9\n")
+	})
+}
+
+func TestCopyRaw(t *testing.T) {
+	raw := "line 0\nline 1\nline 2"
+
+	var out bytes.Buffer
+	assert.NilError(t, CopyRaw(&out, strings.NewReader(raw), &CopyOptions{Start: 1}))
+	assert.Equal(t, out.String(), "line 1\nline 2")
+
+	out.Reset()
+	assert.NilError(t, CopyRaw(&out, strings.NewReader(raw), &CopyOptions{Format: FormatNDJSON, Limit: 1}))
+	assert.Equal(t, out.String(), `{"n":0,"text":"line 0"}`+"\n")
+}
+
+func TestIndex(t *testing.T) {
+	lines := 2*IndexInterval + 10
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		for i := 0; i < lines; i++ {
+			fmt.Fprintf(stdout, "line %d\n", i)
+		}
+	})
+	data := buf.Bytes()
+
+	// write the log to the indexer with small writes
+	x := NewIndexer()
+	for i := 0; i < len(data); i += 7 {
+		_, _ = x.Write(data[i:min(i+7, len(data))])
+	}
+	idx := x.Index()
+
+	assert.Equal(t, idx.Lines, uint64(lines))
+	assert.Equal(t, len(idx.Offsets), 3)
+
+	for _, start := range []uint64{0, 1, IndexInterval - 1, IndexInterval, IndexInterval + 5, 2*IndexInterval + 9} {
+		offset := idx.Offset(start)
+
+		var out bytes.Buffer
+		assert.NilError(t, Copy(&out, bytes.NewReader(data[offset:]), &CopyOptions{Start: start, Limit: 1}))
+		assert.Equal(t, out.String(), fmt.Sprintf("line %d\n", start))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	buf := writeLog(t, func(stdout, stderr *StreamWriter) {
+		for i := 0; i < 10; i++ {
+			fmt.Fprintf(stdout, "line %d\n", i)
+		}
+	})
+	data := buf.Bytes()
+
+	texts := func(lines []*Line) []string {
+		s := []string{}
+		for _, l := range lines {
+			s = append(s, l.Text)
+		}
+		return s
+	}
+
+	type match struct {
+		line   string
+		before []string
+		after  []string
+	}
+
+	tests := []struct {
+		name    string
+		match   []string
+		context int
+		limit   int
+		raw     bool
+		out     []match
+	}{
+		{
+			name:  "no context",
+			match: []string{"line 2", "line 5"},
+			out: []match{
+				{line: "line 2", before: []string{}, after: []string{}},
+				{line: "line 5", before: []string{}, after: []string{}},
+			},
+		},
+		{
+			name:    "context",
+			match:   []string{"line 0", "line 2", "line 3", "line 9"},
+			context: 2,
+			out: []match{
+				{line: "line 0", before: []string{}, after: []string{"line 1", "line 2"}},
+				{line: "line 2", before: []string{"line 0", "line 1"}, after: []string{"line 3", "line 4"}},
+				{line: "line 3", before: []string{"line 1", "line 2"}, after: []string{"line 4", "line 5"}},
+				{line: "line 9", before: []string{"line 7", "line 8"}, after: []string{}},
+			},
+		},
+		{
+			name:    "stop",
+			match:   []string{"line 2", "line 3", "line 4"},
+			context: 1,
+			limit:   2,
+			out: []match{
+				{line: "line 2", before: []string{"line 1"}, after: []string{"line 3"}},
+				{line: "line 3", before: []string{"line 2"}, after: []string{"line 4"}},
+			},
+		},
+		{
+			name:    "raw log",
+			match:   []string{"line 5"},
+			context: 1,
+			raw:     true,
+			out: []match{
+				{line: "line 5", before: []string{"line 4"}, after: []string{"line 6"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bytes.NewReader(data)
+			if tt.raw {
+				var out bytes.Buffer
+				assert.NilError(t, Copy(&out, bytes.NewReader(data), &CopyOptions{}))
+				r = bytes.NewReader(out.Bytes())
+			}
+
+			opts := &SearchOptions{
+				Match: func(text string) bool {
+					for _, m := range tt.match {
+						if text == m {
+							return true
+						}
+					}
+					return false
+				},
+				Context: tt.context,
+				Raw:     tt.raw,
+			}
+			out := []match{}
+			err := Search(r, opts, func(m *Match) bool {
+				out = append(out, match{line: m.Line.Text, before: texts(m.Before), after: texts(m.After)})
+				return tt.limit == 0 || len(out) < tt.limit
+			})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, out, tt.out, gocmp.AllowUnexported(match{}))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing provides the OpenTelemetry tracing setup and helpers used by
+// all the agola services.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sorintlab/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "agola.io/agola"
+
+	traceParentKey = "traceparent"
+)
+
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector endpoint (host:port)
+	Endpoint string
+	// Insecure disables TLS when connecting to the collector
+	Insecure bool
+	// ServiceName is the service name reported in the traces
+	ServiceName string
+	// SampleRatio is the ratio of the sampled traces
+	SampleRatio float64
+}
+
+// Setup configures the global tracer provider to export the traces to an
+// OTLP/HTTP collector and the global propagator to use the W3C trace context.
+// It returns a function that flushes the pending spans and shuts down the
+// tracer provider.
+func Setup(ctx context.Context, c *Config) (func(context.Context) error, error) {
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.Endpoint)}
+	if c.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create otlp trace exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(c.ServiceName)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create trace resource")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	shutdown := func(ctx context.Context) error {
+		return errors.WithStack(tp.Shutdown(ctx))
+	}
+
+	return shutdown, nil
+}
+
+// Tracer returns the agola tracer.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// NewHTTPMiddleware returns a mux middleware that extracts the trace context
+// from the request and creates a server span named with the matched route
+// path template.
+func NewHTTPMiddleware(service string) mux.MiddlewareFunc {
+	return otelhttp.NewMiddleware(service, otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+		if cr := mux.CurrentRoute(r); cr != nil {
+			if tpl, err := cr.GetPathTemplate(); err == nil {
+				return operation + " " + r.Method + " " + tpl
+			}
+		}
+		return operation + " " + r.Method
+	}))
+}
+
+// NewTransport returns an http.RoundTripper that creates a client span for
+// every request and injects the trace context in the request headers.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base)
+}
+
+// TraceParent returns the W3C traceparent of the span in the provided context.
+// It returns an empty string if there's no valid span.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	return carrier.Get(traceParentKey)
+}
+
+// ContextWithTraceParent returns a context with the remote span defined by the
+// provided W3C traceparent. If traceParent is empty or not valid the provided
+// context is returned.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	carrier := propagation.MapCarrier{traceParentKey: traceParent}
+
+	return propagation.TraceContext{}.Extract(ctx, carrier)
+}
+
+// EndSpan ends the span setting its status to error when failed is true. If
+// not empty, result is recorded as the span "agola.result" attribute.
+func EndSpan(span trace.Span, result string, failed bool) {
+	if result != "" {
+		span.SetAttributes(attribute.String("agola.result", result))
+	}
+	if failed {
+		span.SetStatus(codes.Error, result)
+	}
+	span.End()
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gotest.tools/v3/assert"
+)
+
+func setupTestTracer(t *testing.T) *tracetest.SpanRecorder {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	return sr
+}
+
+func TestTraceParent(t *testing.T) {
+	sr := setupTestTracer(t)
+
+	assert.Equal(t, TraceParent(context.Background()), "")
+	assert.Equal(t, ContextWithTraceParent(context.Background(), ""), context.Background())
+
+	ctx, runSpan := Tracer().Start(context.Background(), "run")
+	traceParent := TraceParent(ctx)
+	runSpan.End()
+	assert.Assert(t, traceParent != "")
+
+	_, taskSpan := Tracer().Start(ContextWithTraceParent(context.Background(), traceParent), "task")
+	EndSpan(taskSpan, "failed", true)
+
+	spans := sr.Ended()
+	assert.Equal(t, len(spans), 2)
+	assert.Equal(t, spans[1].Name(), "task")
+	assert.Equal(t, spans[1].SpanContext().TraceID(), runSpan.SpanContext().TraceID())
+	assert.Equal(t, spans[1].Parent().SpanID(), runSpan.SpanContext().SpanID())
+	assert.Equal(t, spans[1].Status().Code, codes.Error)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	sr := setupTestTracer(t)
+
+	router := mux.NewRouter()
+	apirouter := router.PathPrefix("/api").Subrouter()
+	apirouter.Use(NewHTTPMiddleware("test"))
+
+	var serverSpanContext trace.SpanContext
+	apirouter.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		serverSpanContext = trace.SpanContextFromContext(r.Context())
+	}).Methods("GET")
+
+	ctx, clientSpan := Tracer().Start(context.Background(), "client")
+	req := httptest.NewRequest("GET", "/api/items/1", nil).WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	clientSpan.End()
+
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, serverSpanContext.TraceID(), clientSpan.SpanContext().TraceID())
+
+	spans := sr.Ended()
+	assert.Equal(t, len(spans), 2)
+	assert.Equal(t, spans[1].Name(), "test GET /api/items/{id}")
+	assert.Equal(t, spans[1].Parent().SpanID(), clientSpan.SpanContext().SpanID())
+}
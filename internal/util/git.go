@@ -52,6 +52,36 @@ func ParseGitURL(us string) (*url.URL, error) {
 	return u, errors.WithStack(err)
 }
 
+// ValidateGitRemoteURL checks that a git repository url is a remote url: an
+// ssh, scp-like, http(s) or git url. Local paths, file urls, other git
+// transports and values that could be parsed as command options are rejected.
+func ValidateGitRemoteURL(us string) error {
+	if us == "" {
+		return errors.Errorf("empty git url")
+	}
+	if strings.HasPrefix(us, "-") {
+		return errors.Errorf("git url %q cannot start with %q", us, "-")
+	}
+
+	u, err := ParseGitURL(us)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse git url %q", us)
+	}
+	switch u.Scheme {
+	case "ssh", "git", "http", "https":
+	default:
+		return errors.Errorf("unsupported git url %q scheme %q", us, u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return errors.Errorf("git url %q without host", us)
+	}
+	if strings.HasPrefix(u.Hostname(), "-") {
+		return errors.Errorf("git url %q host cannot start with %q", us, "-")
+	}
+
+	return nil
+}
+
 type Git struct {
 	GitDir string
 	Env    []string
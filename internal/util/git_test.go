@@ -0,0 +1,57 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"agola.io/agola/internal/testutil"
+	"agola.io/agola/internal/util"
+)
+
+func TestValidateGitRemoteURL(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		err  string
+	}{
+		{name: "test ssh url", in: "ssh://git@example.com:2222/user/repo.git"},
+		{name: "test scp-like url", in: "git@example.com:user/repo.git"},
+		{name: "test https url", in: "https://example.com/user/repo.git"},
+		{name: "test http url", in: "http://example.com/user/repo.git"},
+		{name: "test git url", in: "git://example.com/user/repo.git"},
+		{name: "test empty url", in: "", err: "empty git url"},
+		{name: "test file url", in: "file:///tmp/repo", err: `scheme "file"`},
+		{name: "test local path", in: "/tmp/repo", err: `scheme ""`},
+		{name: "test relative path", in: "../repo", err: `scheme ""`},
+		{name: "test ext transport", in: "ext::sh -c touch% /tmp/pwned", err: "unsupported git url"},
+		{name: "test option", in: "--upload-pack=touch /tmp/pwned", err: `cannot start with "-"`},
+		{name: "test ssh option host", in: "ssh://-oProxyCommand=touch/repo", err: `host cannot start with "-"`},
+		{name: "test url without host", in: "https:///repo", err: "without host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := util.ValidateGitRemoteURL(tt.in)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			testutil.NilError(t, err)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/sorintlab/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 var JSONContent = http.Header{"Content-Type": []string{"application/json"}}
@@ -33,10 +34,11 @@ type Client struct {
 }
 
 // NewClient initializes and returns a API client.
+// The client propagates the trace context of the requests.
 func NewClient(url, token string) *Client {
 	return &Client{
 		url:    strings.TrimSuffix(url, "/"),
-		client: &http.Client{},
+		client: &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)},
 		token:  token,
 	}
 }
@@ -0,0 +1,36 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"agola.io/agola/services/configstore/types"
+)
+
+type CreateAuditEventRequest struct {
+	ActorUserID   string `json:"actor_user_id"`
+	ActorUserName string `json:"actor_user_name"`
+
+	Action types.AuditAction `json:"action"`
+
+	TargetKind types.ObjectKind `json:"target_kind"`
+	TargetID   string           `json:"target_id"`
+	TargetName string           `json:"target_name"`
+
+	ScopeKind types.ObjectKind `json:"scope_kind"`
+	ScopeID   string           `json:"scope_id"`
+
+	Details         map[string]string               `json:"details"`
+	RequestMetadata types.AuditEventRequestMetadata `json:"request_metadata"`
+}
@@ -32,6 +32,10 @@ type CreateUpdateProjectRequest struct {
 	PassVarsToForkedPR          bool
 	DefaultBranch               string
 	MembersCanPerformRunActions bool
+	MirrorURL                   string
+	MirrorSSHHostKey            string
+	MirrorBranches              []string
+	MirrorTags                  []string
 }
 
 // Project augments cstypes.Project with dynamic data
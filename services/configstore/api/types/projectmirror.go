@@ -0,0 +1,31 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+)
+
+type ClaimProjectMirrorRequest struct {
+	ClaimDuration time.Duration `json:"claim_duration"`
+}
+
+type UpdateProjectMirrorPollResultRequest struct {
+	PollCount uint64 `json:"poll_count"`
+
+	Refs         map[string]string `json:"refs"`
+	PollError    string            `json:"poll_error"`
+	NextPollTime time.Time         `json:"next_poll_time"`
+}
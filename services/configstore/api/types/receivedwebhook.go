@@ -0,0 +1,42 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	"agola.io/agola/services/configstore/types"
+)
+
+type CreateReceivedWebhookRequest struct {
+	DeliveryID string              `json:"delivery_id"`
+	Event      string              `json:"event"`
+	Header     map[string][]string `json:"header"`
+	Body       []byte              `json:"body"`
+}
+
+type ClaimReceivedWebhookRequest struct {
+	ClaimDuration time.Duration `json:"claim_duration"`
+}
+
+type UpdateReceivedWebhookResultRequest struct {
+	Attempts int `json:"attempts"`
+
+	Status          types.ReceivedWebhookStatus  `json:"status"`
+	NextAttemptTime time.Time                    `json:"next_attempt_time"`
+	Outcome         types.ReceivedWebhookOutcome `json:"outcome"`
+	OutcomeMessage  string                       `json:"outcome_message"`
+	RunNumbers      []uint64                     `json:"run_numbers"`
+}
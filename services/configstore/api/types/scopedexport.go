@@ -0,0 +1,28 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	cstypes "agola.io/agola/services/configstore/types"
+)
+
+type ScopedImportResponse struct {
+	// Kind and ID are the kind and the ID of the imported organization or
+	// project group.
+	Kind cstypes.ObjectKind
+	ID   string
+	// ProjectIDs maps the exported projects IDs to the imported projects IDs.
+	ProjectIDs map[string]string
+}
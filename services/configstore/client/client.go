@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/sorintlab/errors"
 
@@ -342,6 +343,104 @@ func (c *Client) DeleteProjectVariable(ctx context.Context, projectRef, variable
 	return resp, errors.WithStack(err)
 }
 
+func (c *Client) GetProjectGroupRoleBindings(ctx context.Context, projectGroupRef string, tree bool) ([]*csapitypes.RoleBinding, *Response, error) {
+	q := url.Values{}
+	if tree {
+		q.Add("tree", "")
+	}
+
+	roleBindings := []*csapitypes.RoleBinding{}
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/projectgroups/%s/rolebindings", url.PathEscape(projectGroupRef)), q, common.JSONContent, nil, &roleBindings)
+	return roleBindings, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetProjectRoleBindings(ctx context.Context, projectRef string, tree bool) ([]*csapitypes.RoleBinding, *Response, error) {
+	q := url.Values{}
+	if tree {
+		q.Add("tree", "")
+	}
+
+	roleBindings := []*csapitypes.RoleBinding{}
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/rolebindings", url.PathEscape(projectRef)), q, common.JSONContent, nil, &roleBindings)
+	return roleBindings, resp, errors.WithStack(err)
+}
+
+func (c *Client) SetProjectGroupUserRoleBinding(ctx context.Context, projectGroupRef, userRef string, role cstypes.MemberRole) (*cstypes.RoleBinding, *Response, error) {
+	reqj, err := json.Marshal(&csapitypes.SetRoleBindingRequest{Role: role})
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(cstypes.RoleBinding)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/projectgroups/%s/rolebindings/users/%s", url.PathEscape(projectGroupRef), userRef), nil, common.JSONContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) SetProjectUserRoleBinding(ctx context.Context, projectRef, userRef string, role cstypes.MemberRole) (*cstypes.RoleBinding, *Response, error) {
+	reqj, err := json.Marshal(&csapitypes.SetRoleBindingRequest{Role: role})
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(cstypes.RoleBinding)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/projects/%s/rolebindings/users/%s", url.PathEscape(projectRef), userRef), nil, common.JSONContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectGroupUserRoleBinding(ctx context.Context, projectGroupRef, userRef string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "DELETE", fmt.Sprintf("/projectgroups/%s/rolebindings/users/%s", url.PathEscape(projectGroupRef), userRef), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectUserRoleBinding(ctx context.Context, projectRef, userRef string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "DELETE", fmt.Sprintf("/projects/%s/rolebindings/users/%s", url.PathEscape(projectRef), userRef), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) SetProjectGroupTeamRoleBinding(ctx context.Context, projectGroupRef, teamRef string, role cstypes.MemberRole) (*cstypes.RoleBinding, *Response, error) {
+	reqj, err := json.Marshal(&csapitypes.SetRoleBindingRequest{Role: role})
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(cstypes.RoleBinding)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/projectgroups/%s/rolebindings/teams/%s", url.PathEscape(projectGroupRef), teamRef), nil, common.JSONContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectGroupTeamRoleBinding(ctx context.Context, projectGroupRef, teamRef string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "DELETE", fmt.Sprintf("/projectgroups/%s/rolebindings/teams/%s", url.PathEscape(projectGroupRef), teamRef), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) SetProjectTeamRoleBinding(ctx context.Context, projectRef, teamRef string, role cstypes.MemberRole) (*cstypes.RoleBinding, *Response, error) {
+	reqj, err := json.Marshal(&csapitypes.SetRoleBindingRequest{Role: role})
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(cstypes.RoleBinding)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/projects/%s/rolebindings/teams/%s", url.PathEscape(projectRef), teamRef), nil, common.JSONContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectTeamRoleBinding(ctx context.Context, projectRef, teamRef string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "DELETE", fmt.Sprintf("/projects/%s/rolebindings/teams/%s", url.PathEscape(projectRef), teamRef), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) GetProjectGroupUserRole(ctx context.Context, projectGroupRef, userRef string) (*csapitypes.UserRoleResponse, *Response, error) {
+	userRole := new(csapitypes.UserRoleResponse)
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/projectgroups/%s/userroles/%s", url.PathEscape(projectGroupRef), userRef), nil, common.JSONContent, nil, userRole)
+	return userRole, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetProjectUserRole(ctx context.Context, projectRef, userRef string) (*csapitypes.UserRoleResponse, *Response, error) {
+	userRole := new(csapitypes.UserRoleResponse)
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/userroles/%s", url.PathEscape(projectRef), userRef), nil, common.JSONContent, nil, userRole)
+	return userRole, resp, errors.WithStack(err)
+}
+
 func (c *Client) GetUser(ctx context.Context, userRef string) (*cstypes.User, *Response, error) {
 	user := new(cstypes.User)
 	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/users/%s", userRef), nil, common.JSONContent, nil, user)
@@ -699,6 +798,62 @@ func (c *Client) GetOrgMembers(ctx context.Context, orgRef string, opts *GetOrgM
 	return orgMembers, resp, errors.WithStack(err)
 }
 
+func (c *Client) GetOrgTeams(ctx context.Context, orgRef string) ([]*cstypes.Team, *Response, error) {
+	teams := []*cstypes.Team{}
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/teams", orgRef), nil, common.JSONContent, nil, &teams)
+	return teams, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetOrgTeam(ctx context.Context, orgRef, teamRef string) (*cstypes.Team, *Response, error) {
+	team := new(cstypes.Team)
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/teams/%s", orgRef, teamRef), nil, common.JSONContent, nil, team)
+	return team, resp, errors.WithStack(err)
+}
+
+func (c *Client) CreateOrgTeam(ctx context.Context, orgRef string, req *csapitypes.CreateTeamRequest) (*cstypes.Team, *Response, error) {
+	tj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	team := new(cstypes.Team)
+	resp, err := c.GetParsedResponse(ctx, "POST", fmt.Sprintf("/orgs/%s/teams", orgRef), nil, common.JSONContent, bytes.NewReader(tj), team)
+	return team, resp, errors.WithStack(err)
+}
+
+func (c *Client) UpdateOrgTeam(ctx context.Context, orgRef, teamRef string, req *csapitypes.UpdateTeamRequest) (*cstypes.Team, *Response, error) {
+	tj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	team := new(cstypes.Team)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/orgs/%s/teams/%s", orgRef, teamRef), nil, common.JSONContent, bytes.NewReader(tj), team)
+	return team, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteOrgTeam(ctx context.Context, orgRef, teamRef string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "DELETE", fmt.Sprintf("/orgs/%s/teams/%s", orgRef, teamRef), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) GetOrgTeamMembers(ctx context.Context, orgRef, teamRef string) ([]*cstypes.User, *Response, error) {
+	users := []*cstypes.User{}
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/teams/%s/members", orgRef, teamRef), nil, common.JSONContent, nil, &users)
+	return users, resp, errors.WithStack(err)
+}
+
+func (c *Client) AddOrgTeamMember(ctx context.Context, orgRef, teamRef, userRef string) (*cstypes.TeamMember, *Response, error) {
+	teamMember := new(cstypes.TeamMember)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/orgs/%s/teams/%s/members/%s", orgRef, teamRef, userRef), nil, common.JSONContent, nil, teamMember)
+	return teamMember, resp, errors.WithStack(err)
+}
+
+func (c *Client) RemoveOrgTeamMember(ctx context.Context, orgRef, teamRef, userRef string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "DELETE", fmt.Sprintf("/orgs/%s/teams/%s/members/%s", orgRef, teamRef, userRef), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
 func (c *Client) GetUserOrgInvitations(ctx context.Context, userRef string, limit int) ([]*cstypes.OrgInvitation, *Response, error) {
 	q := url.Values{}
 	if limit > 0 {
@@ -753,6 +908,162 @@ func (c *Client) UserOrgInvitationAction(ctx context.Context, userRef string, or
 	return resp, errors.WithStack(err)
 }
 
+type GetAuditEventsOptions struct {
+	*ListOptions
+
+	StartSequence uint64
+
+	OrgRef      string
+	ActorUserID string
+	Actions     []cstypes.AuditAction
+	TargetKind  cstypes.ObjectKind
+	TargetID    string
+	Since       *time.Time
+	Until       *time.Time
+}
+
+func (o *GetAuditEventsOptions) Add(q url.Values) {
+	o.ListOptions.Add(q)
+
+	if o.StartSequence > 0 {
+		q.Add("startsequence", strconv.FormatUint(o.StartSequence, 10))
+	}
+	if o.OrgRef != "" {
+		q.Add("orgref", o.OrgRef)
+	}
+	if o.ActorUserID != "" {
+		q.Add("actoruserid", o.ActorUserID)
+	}
+	for _, a := range o.Actions {
+		q.Add("action", string(a))
+	}
+	if o.TargetKind != "" {
+		q.Add("targetkind", string(o.TargetKind))
+	}
+	if o.TargetID != "" {
+		q.Add("targetid", o.TargetID)
+	}
+	if o.Since != nil {
+		q.Add("since", o.Since.Format(time.RFC3339Nano))
+	}
+	if o.Until != nil {
+		q.Add("until", o.Until.Format(time.RFC3339Nano))
+	}
+}
+
+func (c *Client) GetAuditEvents(ctx context.Context, opts *GetAuditEventsOptions) ([]*cstypes.AuditEvent, *Response, error) {
+	q := url.Values{}
+	opts.Add(q)
+
+	auditEvents := []*cstypes.AuditEvent{}
+	resp, err := c.GetParsedResponse(ctx, "GET", "/auditevents", q, common.JSONContent, nil, &auditEvents)
+	return auditEvents, resp, errors.WithStack(err)
+}
+
+func (c *Client) CreateAuditEvent(ctx context.Context, req *csapitypes.CreateAuditEventRequest) (*cstypes.AuditEvent, *Response, error) {
+	aj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	auditEvent := new(cstypes.AuditEvent)
+	resp, err := c.GetParsedResponse(ctx, "POST", "/auditevents", nil, common.JSONContent, bytes.NewReader(aj), auditEvent)
+	return auditEvent, resp, errors.WithStack(err)
+}
+
+type GetProjectReceivedWebhooksOptions struct {
+	*ListOptions
+
+	StartSequence uint64
+
+	Statuses []cstypes.ReceivedWebhookStatus
+}
+
+func (o *GetProjectReceivedWebhooksOptions) Add(q url.Values) {
+	o.ListOptions.Add(q)
+
+	if o.StartSequence > 0 {
+		q.Add("startsequence", strconv.FormatUint(o.StartSequence, 10))
+	}
+	for _, s := range o.Statuses {
+		q.Add("status", string(s))
+	}
+}
+
+func (c *Client) GetProjectReceivedWebhooks(ctx context.Context, projectRef string, opts *GetProjectReceivedWebhooksOptions) ([]*cstypes.ReceivedWebhook, *Response, error) {
+	q := url.Values{}
+	opts.Add(q)
+
+	receivedWebhooks := []*cstypes.ReceivedWebhook{}
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/receivedwebhooks", url.PathEscape(projectRef)), q, common.JSONContent, nil, &receivedWebhooks)
+	return receivedWebhooks, resp, errors.WithStack(err)
+}
+
+func (c *Client) CreateReceivedWebhook(ctx context.Context, projectRef string, req *csapitypes.CreateReceivedWebhookRequest) (*cstypes.ReceivedWebhook, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	receivedWebhook := new(cstypes.ReceivedWebhook)
+	resp, err := c.GetParsedResponse(ctx, "POST", fmt.Sprintf("/projects/%s/receivedwebhooks", url.PathEscape(projectRef)), nil, common.JSONContent, bytes.NewReader(reqj), receivedWebhook)
+	return receivedWebhook, resp, errors.WithStack(err)
+}
+
+// ClaimReceivedWebhook claims the next received webhook to process. It returns
+// a nil received webhook when there's nothing to process.
+func (c *Client) ClaimReceivedWebhook(ctx context.Context, req *csapitypes.ClaimReceivedWebhookRequest) (*cstypes.ReceivedWebhook, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	var receivedWebhook *cstypes.ReceivedWebhook
+	resp, err := c.GetParsedResponse(ctx, "POST", "/receivedwebhooks/claim", nil, common.JSONContent, bytes.NewReader(reqj), &receivedWebhook)
+	return receivedWebhook, resp, errors.WithStack(err)
+}
+
+func (c *Client) UpdateReceivedWebhookResult(ctx context.Context, receivedWebhookID string, req *csapitypes.UpdateReceivedWebhookResultRequest) (*cstypes.ReceivedWebhook, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	receivedWebhook := new(cstypes.ReceivedWebhook)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/receivedwebhooks/%s/result", receivedWebhookID), nil, common.JSONContent, bytes.NewReader(reqj), receivedWebhook)
+	return receivedWebhook, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetProjectMirror(ctx context.Context, projectRef string) (*cstypes.ProjectMirror, *Response, error) {
+	projectMirror := new(cstypes.ProjectMirror)
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/mirror", url.PathEscape(projectRef)), nil, common.JSONContent, nil, projectMirror)
+	return projectMirror, resp, errors.WithStack(err)
+}
+
+// ClaimProjectMirror claims the next project mirror to poll. It returns a nil
+// project mirror when there's nothing to poll.
+func (c *Client) ClaimProjectMirror(ctx context.Context, req *csapitypes.ClaimProjectMirrorRequest) (*cstypes.ProjectMirror, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	var projectMirror *cstypes.ProjectMirror
+	resp, err := c.GetParsedResponse(ctx, "POST", "/projectmirrors/claim", nil, common.JSONContent, bytes.NewReader(reqj), &projectMirror)
+	return projectMirror, resp, errors.WithStack(err)
+}
+
+func (c *Client) UpdateProjectMirrorPollResult(ctx context.Context, projectMirrorID string, req *csapitypes.UpdateProjectMirrorPollResultRequest) (*cstypes.ProjectMirror, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	projectMirror := new(cstypes.ProjectMirror)
+	resp, err := c.GetParsedResponse(ctx, "PUT", fmt.Sprintf("/projectmirrors/%s/pollresult", projectMirrorID), nil, common.JSONContent, bytes.NewReader(reqj), projectMirror)
+	return projectMirror, resp, errors.WithStack(err)
+}
+
 func (c *Client) GetMaintenanceStatus(ctx context.Context) (*csapitypes.MaintenanceStatusResponse, *Response, error) {
 	maintenanceStatus := new(csapitypes.MaintenanceStatusResponse)
 	resp, err := c.GetParsedResponse(ctx, "GET", "/maintenance", nil, common.JSONContent, nil, maintenanceStatus)
@@ -778,3 +1089,46 @@ func (c *Client) Import(ctx context.Context, r io.Reader) (*Response, error) {
 	resp, err := c.GetResponse(ctx, "POST", "/import", nil, -1, common.JSONContent, r)
 	return resp, errors.WithStack(err)
 }
+
+func (c *Client) GetBackups(ctx context.Context) ([]*csapitypes.Backup, *Response, error) {
+	backups := []*csapitypes.Backup{}
+	resp, err := c.GetParsedResponse(ctx, "GET", "/backups", nil, common.JSONContent, nil, &backups)
+	return backups, resp, errors.WithStack(err)
+}
+
+func (c *Client) RestoreBackup(ctx context.Context, backupID string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "POST", fmt.Sprintf("/backups/%s/restore", backupID), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) ScopedExport(ctx context.Context, kind cstypes.ObjectKind, ref string) (*Response, error) {
+	q := url.Values{}
+	q.Add("kind", string(kind))
+	q.Add("ref", ref)
+
+	resp, err := c.GetResponse(ctx, "GET", "/scopedexport", q, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) ScopedImport(ctx context.Context, parentRef, creatorUserID string, r io.Reader) (*csapitypes.ScopedImportResponse, *Response, error) {
+	q := url.Values{}
+	if parentRef != "" {
+		q.Add("parentref", parentRef)
+	}
+	if creatorUserID != "" {
+		q.Add("creatoruserid", creatorUserID)
+	}
+
+	res := new(csapitypes.ScopedImportResponse)
+	resp, err := c.GetParsedResponse(ctx, "POST", "/scopedimport", q, common.JSONContent, r, res)
+	return res, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteScopedImport(ctx context.Context, kind cstypes.ObjectKind, ref string) (*Response, error) {
+	q := url.Values{}
+	q.Add("kind", string(kind))
+	q.Add("ref", ref)
+
+	resp, err := c.GetResponse(ctx, "DELETE", "/scopedimport", q, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
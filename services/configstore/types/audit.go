@@ -0,0 +1,109 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+type AuditAction string
+
+const (
+	AuditActionOrgCreate       AuditAction = "org.create"
+	AuditActionOrgUpdate       AuditAction = "org.update"
+	AuditActionOrgDelete       AuditAction = "org.delete"
+	AuditActionOrgMemberAdd    AuditAction = "org.member.add"
+	AuditActionOrgMemberRemove AuditAction = "org.member.remove"
+
+	AuditActionTeamCreate       AuditAction = "team.create"
+	AuditActionTeamUpdate       AuditAction = "team.update"
+	AuditActionTeamDelete       AuditAction = "team.delete"
+	AuditActionTeamMemberAdd    AuditAction = "team.member.add"
+	AuditActionTeamMemberRemove AuditAction = "team.member.remove"
+
+	AuditActionProjectGroupCreate AuditAction = "projectgroup.create"
+	AuditActionProjectGroupUpdate AuditAction = "projectgroup.update"
+	AuditActionProjectGroupDelete AuditAction = "projectgroup.delete"
+
+	AuditActionProjectCreate AuditAction = "project.create"
+	AuditActionProjectUpdate AuditAction = "project.update"
+	AuditActionProjectDelete AuditAction = "project.delete"
+
+	AuditActionSecretCreate AuditAction = "secret.create"
+	AuditActionSecretUpdate AuditAction = "secret.update"
+	AuditActionSecretDelete AuditAction = "secret.delete"
+
+	AuditActionVariableCreate AuditAction = "variable.create"
+	AuditActionVariableUpdate AuditAction = "variable.update"
+	AuditActionVariableDelete AuditAction = "variable.delete"
+
+	AuditActionRoleBindingSet    AuditAction = "rolebinding.set"
+	AuditActionRoleBindingDelete AuditAction = "rolebinding.delete"
+
+	AuditActionRemoteSourceCreate AuditAction = "remotesource.create"
+	AuditActionRemoteSourceUpdate AuditAction = "remotesource.update"
+	AuditActionRemoteSourceDelete AuditAction = "remotesource.delete"
+
+	AuditActionUserCreate      AuditAction = "user.create"
+	AuditActionUserDelete      AuditAction = "user.delete"
+	AuditActionUserTokenCreate AuditAction = "user.token.create"
+	AuditActionUserTokenDelete AuditAction = "user.token.delete"
+
+	AuditActionRunAction     AuditAction = "run.action"
+	AuditActionRunTaskAction AuditAction = "run.task.action"
+)
+
+// AuditTargetKindRun is the target kind of audit events on runs. Runs aren't
+// configstore objects so there's no matching ObjectKind
+const AuditTargetKindRun ObjectKind = "run"
+
+// AuditEventRequestMetadata contains information on the api request that
+// triggered the audited action
+type AuditEventRequestMetadata struct {
+	RemoteAddr string `json:"remote_addr,omitempty"`
+	UserAgent  string `json:"user_agent,omitempty"`
+}
+
+// AuditEvent records an administrative or security relevant action. Audit
+// events are only appended and never updated.
+type AuditEvent struct {
+	sqlg.ObjectMeta
+
+	Sequence uint64 `json:"sequence,omitempty"`
+
+	ActorUserID   string `json:"actor_user_id,omitempty"`
+	ActorUserName string `json:"actor_user_name,omitempty"`
+
+	Action AuditAction `json:"action,omitempty"`
+
+	TargetKind ObjectKind `json:"target_kind,omitempty"`
+	TargetID   string     `json:"target_id,omitempty"`
+	TargetName string     `json:"target_name,omitempty"`
+
+	// OrganizationID is the id of the organization owning the target, empty
+	// if the target isn't owned by an organization
+	OrganizationID string `json:"organization_id,omitempty"`
+
+	Details map[string]string `json:"details,omitempty"`
+
+	RequestMetadata AuditEventRequestMetadata `json:"request_metadata,omitempty"`
+}
+
+func NewAuditEvent(tx *sql.Tx) *AuditEvent {
+	return &AuditEvent{
+		ObjectMeta: sqlg.NewObjectMeta(tx),
+	}
+}
@@ -20,20 +20,24 @@ import (
 )
 
 // RemoteRepositoryConfigType defines how a remote repository is configured and
-// managed. Currently only "remotesource" is supported.
-// In future other config types (like a fully manual config) could be supported.
+// managed.
 type RemoteRepositoryConfigType string
 
 const (
 	// RemoteRepositoryConfigTypeManual is currently only used for tests and not available for direct usage
 	RemoteRepositoryConfigTypeManual       RemoteRepositoryConfigType = "manual"
 	RemoteRepositoryConfigTypeRemoteSource RemoteRepositoryConfigType = "remotesource"
+	// RemoteRepositoryConfigTypeMirror is a plain git repository without
+	// webhooks support. Its refs are periodically polled and runs are created
+	// for the new or updated ones (see ProjectMirror).
+	RemoteRepositoryConfigTypeMirror RemoteRepositoryConfigType = "mirror"
 )
 
 func IsValidRemoteRepositoryConfigType(t RemoteRepositoryConfigType) bool {
 	switch t {
 	case RemoteRepositoryConfigTypeManual:
 	case RemoteRepositoryConfigTypeRemoteSource:
+	case RemoteRepositoryConfigTypeMirror:
 	default:
 		return false
 	}
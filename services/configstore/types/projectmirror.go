@@ -0,0 +1,63 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+// ProjectMirror is the polling configuration and state of a mirror project
+// (a project with a RemoteRepositoryConfigTypeMirror remote repository config
+// type).
+type ProjectMirror struct {
+	sqlg.ObjectMeta
+
+	ProjectID string `json:"project_id,omitempty"`
+
+	// URL is the git repository url. Both ssh (also in scp like syntax) and
+	// http(s) urls are supported.
+	URL        string `json:"url,omitempty"`
+	SSHHostKey string `json:"ssh_host_key,omitempty"`
+
+	// Branches are the glob patterns of the branches for which runs are
+	// created. When empty runs are created for all the branches.
+	Branches []string `json:"branches,omitempty"`
+	// Tags are the glob patterns of the tags for which runs are created. When
+	// empty no run is created for tags.
+	Tags []string `json:"tags,omitempty"`
+
+	// Refs are the refs (and their commit sha) seen at the last successful
+	// poll. It's nil until the first poll.
+	Refs map[string]string `json:"refs,omitempty"`
+
+	// PollCount is increased every time the mirror is claimed for polling.
+	// It's used to detect that a claim expired and the mirror was claimed
+	// again.
+	PollCount uint64 `json:"poll_count,omitempty"`
+	// NextPollTime is the time after which the mirror will be polled. When
+	// claimed it's the time after which the claim expires.
+	NextPollTime  time.Time  `json:"next_poll_time,omitempty"`
+	LastPollTime  *time.Time `json:"last_poll_time,omitempty"`
+	LastPollError string     `json:"last_poll_error,omitempty"`
+}
+
+func NewProjectMirror(tx *sql.Tx) *ProjectMirror {
+	return &ProjectMirror{
+		ObjectMeta: sqlg.NewObjectMeta(tx),
+	}
+}
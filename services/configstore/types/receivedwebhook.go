@@ -0,0 +1,111 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"time"
+
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+type ReceivedWebhookStatus string
+
+const (
+	// ReceivedWebhookStatusPending means the webhook is waiting to be
+	// processed. This is also the status of webhooks whose processing failed
+	// and will be retried.
+	ReceivedWebhookStatusPending ReceivedWebhookStatus = "pending"
+	// ReceivedWebhookStatusProcessing means the webhook has been claimed by a
+	// worker
+	ReceivedWebhookStatusProcessing ReceivedWebhookStatus = "processing"
+	// ReceivedWebhookStatusProcessed means the webhook has been processed,
+	// the outcome reports what happened
+	ReceivedWebhookStatusProcessed ReceivedWebhookStatus = "processed"
+	// ReceivedWebhookStatusFailed means the webhook processing failed and it
+	// won't be retried
+	ReceivedWebhookStatusFailed ReceivedWebhookStatus = "failed"
+)
+
+func IsValidReceivedWebhookStatus(s ReceivedWebhookStatus) bool {
+	switch s {
+	case ReceivedWebhookStatusPending, ReceivedWebhookStatusProcessing, ReceivedWebhookStatusProcessed, ReceivedWebhookStatusFailed:
+		return true
+	}
+	return false
+}
+
+type ReceivedWebhookOutcome string
+
+const (
+	ReceivedWebhookOutcomeNone ReceivedWebhookOutcome = ""
+	// ReceivedWebhookOutcomeSkipped means no run was created, the outcome
+	// message reports the reason
+	ReceivedWebhookOutcomeSkipped ReceivedWebhookOutcome = "skipped"
+	// ReceivedWebhookOutcomeSetupError means the run config couldn't be
+	// parsed and a setup error run was created
+	ReceivedWebhookOutcomeSetupError ReceivedWebhookOutcome = "setupError"
+	// ReceivedWebhookOutcomeRunsCreated means one or more runs were created
+	ReceivedWebhookOutcomeRunsCreated ReceivedWebhookOutcome = "runsCreated"
+	// ReceivedWebhookOutcomeRunsStopped means the pull request was closed or
+	// merged and its queued and running runs were cancelled or stopped
+	ReceivedWebhookOutcomeRunsStopped ReceivedWebhookOutcome = "runsStopped"
+)
+
+func IsValidReceivedWebhookOutcome(o ReceivedWebhookOutcome) bool {
+	switch o {
+	case ReceivedWebhookOutcomeNone, ReceivedWebhookOutcomeSkipped, ReceivedWebhookOutcomeSetupError, ReceivedWebhookOutcomeRunsCreated, ReceivedWebhookOutcomeRunsStopped:
+		return true
+	}
+	return false
+}
+
+// ReceivedWebhook is a webhook received from a git source and queued for
+// processing.
+type ReceivedWebhook struct {
+	sqlg.ObjectMeta
+
+	Sequence uint64 `json:"sequence,omitempty"`
+
+	ProjectID string `json:"project_id,omitempty"`
+
+	// DeliveryID is the git source provided delivery id (or a hash of the
+	// body when not provided). It's unique per project and used to ignore
+	// redeliveries of the same webhook.
+	DeliveryID string `json:"delivery_id,omitempty"`
+	Event      string `json:"event,omitempty"`
+
+	Header map[string][]string `json:"header,omitempty"`
+	Body   []byte              `json:"body,omitempty"`
+
+	Status   ReceivedWebhookStatus `json:"status,omitempty"`
+	Attempts int                   `json:"attempts,omitempty"`
+	// NextAttemptTime is the time after which a pending webhook will be
+	// processed. When processing it's the time after which the worker claim
+	// expires and the webhook could be claimed again.
+	NextAttemptTime time.Time `json:"next_attempt_time,omitempty"`
+
+	Outcome ReceivedWebhookOutcome `json:"outcome,omitempty"`
+	// OutcomeMessage contains the skip reason, the setup errors or the last
+	// processing error
+	OutcomeMessage string   `json:"outcome_message,omitempty"`
+	RunNumbers     []uint64 `json:"run_numbers,omitempty"`
+}
+
+func NewReceivedWebhook(tx *sql.Tx) *ReceivedWebhook {
+	return &ReceivedWebhook{
+		ObjectMeta: sqlg.NewObjectMeta(tx),
+	}
+}
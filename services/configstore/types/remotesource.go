@@ -24,9 +24,13 @@ import (
 type RemoteSourceType string
 
 const (
-	RemoteSourceTypeGitea  RemoteSourceType = "gitea"
-	RemoteSourceTypeGithub RemoteSourceType = "github"
-	RemoteSourceTypeGitlab RemoteSourceType = "gitlab"
+	RemoteSourceTypeGitea           RemoteSourceType = "gitea"
+	RemoteSourceTypeGithub          RemoteSourceType = "github"
+	RemoteSourceTypeGitlab          RemoteSourceType = "gitlab"
+	RemoteSourceTypeBitbucketServer RemoteSourceType = "bitbucketserver"
+	RemoteSourceTypeForgejo         RemoteSourceType = "forgejo"
+	RemoteSourceTypeGogs            RemoteSourceType = "gogs"
+	RemoteSourceTypeGerrit          RemoteSourceType = "gerrit"
 )
 
 type RemoteSourceAuthType string
@@ -68,7 +72,17 @@ func NewRemoteSource(tx *sql.Tx) *RemoteSource {
 func SourceSupportedAuthTypes(rsType RemoteSourceType) []RemoteSourceAuthType {
 	switch rsType {
 	case RemoteSourceTypeGitea:
+		fallthrough
+	case RemoteSourceTypeForgejo:
+		fallthrough
+	case RemoteSourceTypeBitbucketServer:
 		return []RemoteSourceAuthType{RemoteSourceAuthTypeOauth2, RemoteSourceAuthTypePassword}
+	case RemoteSourceTypeGogs:
+		// gogs doesn't provide an oauth2 provider
+		return []RemoteSourceAuthType{RemoteSourceAuthTypePassword}
+	case RemoteSourceTypeGerrit:
+		// gerrit uses the user http password
+		return []RemoteSourceAuthType{RemoteSourceAuthTypePassword}
 	case RemoteSourceTypeGithub:
 		fallthrough
 	case RemoteSourceTypeGitlab:
@@ -0,0 +1,44 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+// Subject is the object a role binding grants a role to.
+type Subject struct {
+	Kind ObjectKind `json:"kind,omitempty"`
+	ID   string     `json:"id,omitempty"`
+}
+
+// RoleBinding grants a role on a project group or project (and all their
+// children) to a subject.
+type RoleBinding struct {
+	sqlg.ObjectMeta
+
+	Parent Parent `json:"parent,omitempty"`
+
+	Subject Subject `json:"subject,omitempty"`
+
+	Role MemberRole `json:"role,omitempty"`
+}
+
+func NewRoleBinding(tx *sql.Tx) *RoleBinding {
+	return &RoleBinding{
+		ObjectMeta: sqlg.NewObjectMeta(tx),
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"agola.io/agola/internal/sqlg"
+	"agola.io/agola/internal/sqlg/sql"
+)
+
+// Team is a named group of organization members. Teams can be used as role
+// binding subjects.
+type Team struct {
+	sqlg.ObjectMeta
+
+	OrganizationID string `json:"organization_id,omitempty"`
+
+	Name string `json:"name,omitempty"`
+}
+
+func NewTeam(tx *sql.Tx) *Team {
+	return &Team{
+		ObjectMeta: sqlg.NewObjectMeta(tx),
+	}
+}
+
+type TeamMember struct {
+	sqlg.ObjectMeta
+
+	TeamID string `json:"team_id,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+}
+
+func NewTeamMember(tx *sql.Tx) *TeamMember {
+	return &TeamMember{
+		ObjectMeta: sqlg.NewObjectMeta(tx),
+	}
+}
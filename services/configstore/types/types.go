@@ -24,15 +24,21 @@ const (
 type ObjectKind string
 
 const (
-	ObjectKindUser          ObjectKind = "user"
-	ObjectKindOrg           ObjectKind = "org"
-	ObjectKindOrgMember     ObjectKind = "orgmember"
-	ObjectKindProjectGroup  ObjectKind = "projectgroup"
-	ObjectKindProject       ObjectKind = "project"
-	ObjectKindRemoteSource  ObjectKind = "remotesource"
-	ObjectKindSecret        ObjectKind = "secret"
-	ObjectKindVariable      ObjectKind = "variable"
-	ObjectKindOrgInvitation ObjectKind = "orginvitation"
+	ObjectKindUser            ObjectKind = "user"
+	ObjectKindOrg             ObjectKind = "org"
+	ObjectKindOrgMember       ObjectKind = "orgmember"
+	ObjectKindProjectGroup    ObjectKind = "projectgroup"
+	ObjectKindProject         ObjectKind = "project"
+	ObjectKindRemoteSource    ObjectKind = "remotesource"
+	ObjectKindSecret          ObjectKind = "secret"
+	ObjectKindVariable        ObjectKind = "variable"
+	ObjectKindOrgInvitation   ObjectKind = "orginvitation"
+	ObjectKindRoleBinding     ObjectKind = "rolebinding"
+	ObjectKindTeam            ObjectKind = "team"
+	ObjectKindTeamMember      ObjectKind = "teammember"
+	ObjectKindAuditEvent      ObjectKind = "auditevent"
+	ObjectKindReceivedWebhook ObjectKind = "receivedwebhook"
+	ObjectKindProjectMirror   ObjectKind = "projectmirror"
 )
 
 type Visibility string
@@ -55,20 +61,64 @@ func IsValidVisibility(v Visibility) bool {
 type MemberRole string
 
 const (
-	MemberRoleOwner  MemberRole = "owner"
-	MemberRoleMember MemberRole = "member"
+	MemberRoleOwner      MemberRole = "owner"
+	MemberRoleAdmin      MemberRole = "admin"
+	MemberRoleMaintainer MemberRole = "maintainer"
+	MemberRoleDeveloper  MemberRole = "developer"
+	MemberRoleMember     MemberRole = "member"
+	MemberRoleViewer     MemberRole = "viewer"
 )
 
 func IsValidMemberRole(r MemberRole) bool {
 	switch r {
 	case MemberRoleOwner:
+	case MemberRoleAdmin:
+	case MemberRoleMaintainer:
+	case MemberRoleDeveloper:
 	case MemberRoleMember:
+	case MemberRoleViewer:
 	default:
 		return false
 	}
 	return true
 }
 
+// level returns the position of the role in the roles hierarchy. Every role
+// includes all the permissions of the roles with a lower level.
+// An empty or unknown role has level 0.
+func (r MemberRole) level() int {
+	switch r {
+	case MemberRoleViewer:
+		return 1
+	case MemberRoleMember:
+		return 2
+	case MemberRoleDeveloper:
+		return 3
+	case MemberRoleMaintainer:
+		return 4
+	case MemberRoleAdmin:
+		return 5
+	case MemberRoleOwner:
+		return 6
+	}
+	return 0
+}
+
+// Includes reports whether the role grants at least the permissions of the
+// provided role.
+func (r MemberRole) Includes(o MemberRole) bool {
+	return r.level() > 0 && r.level() >= o.level()
+}
+
+// MaxMemberRole returns the role with the most permissions between the
+// provided roles.
+func MaxMemberRole(a, b MemberRole) MemberRole {
+	if b.level() > a.level() {
+		return b
+	}
+	return a
+}
+
 type Parent struct {
 	Kind ObjectKind `json:"type,omitempty"`
 	ID   string     `json:"id,omitempty"`
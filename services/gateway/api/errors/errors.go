@@ -65,6 +65,17 @@ const (
 
 	ErrorCodeOrgMemberDoesNotExist util.ErrorCode = "orgMemberDoesNotExist"
 
+	ErrorCodeRoleBindingDoesNotExist util.ErrorCode = "roleBindingDoesNotExist"
+
+	ErrorCodeTeamDoesNotExist       util.ErrorCode = "teamDoesNotExist"
+	ErrorCodeTeamAlreadyExists      util.ErrorCode = "teamAlreadyExists"
+	ErrorCodeInvalidTeamName        util.ErrorCode = "invalidTeamName"
+	ErrorCodeTeamMemberDoesNotExist util.ErrorCode = "teamMemberDoesNotExist"
+	ErrorCodeUserNotOrgMember       util.ErrorCode = "userNotOrgMember"
+
+	ErrorCodeInvalidAuditAction util.ErrorCode = "invalidAuditAction"
+	ErrorCodeInvalidTimeRange   util.ErrorCode = "invalidTimeRange"
+
 	ErrorCodeInvitationDoesNotExist  util.ErrorCode = "invitationDoesNotExist"
 	ErrorCodeInvitationAlreadyExists util.ErrorCode = "invitationAlreadyExists"
 
@@ -80,6 +91,8 @@ const (
 	ErrorCodeRunCannotBeRestarted      util.ErrorCode = "runCannotBeRestarted"
 	ErrorCodeRunTaskNotWaitingApproval util.ErrorCode = "runTaskNotWaitingApproval"
 	ErrorCodeRunTaskAlreadyApproved    util.ErrorCode = "runTaskAlreadyApproved"
+	ErrorCodeRunTaskApproverNotAllowed util.ErrorCode = "runTaskApproverNotAllowed"
+	ErrorCodeRunTaskSelfApproval       util.ErrorCode = "runTaskSelfApproval"
 
 	ErrorCodeInvalidDeliveryStatus util.ErrorCode = "invalidDeliveryStatus"
 
@@ -90,4 +103,21 @@ const (
 	ErrorCodeRunWebhookDoesNotExist              util.ErrorCode = "runWebhookDoesNotExist"
 	ErrorCodeRunWebhookDeliveryDoesNotExist      util.ErrorCode = "runWebhookDeliveryDoesNotExist"
 	ErrorCodeRunWebhookDeliveryAlreadyInProgress util.ErrorCode = "runWebhookDeliveryAlreadyInProgress"
+
+	ErrorCodeReceivedWebhookDoesNotExist    util.ErrorCode = "receivedWebhookDoesNotExist"
+	ErrorCodeReceivedWebhookNotProcessing   util.ErrorCode = "receivedWebhookNotProcessing"
+	ErrorCodeInvalidReceivedWebhookStatus   util.ErrorCode = "invalidReceivedWebhookStatus"
+	ErrorCodeInvalidReceivedWebhookDelivery util.ErrorCode = "invalidReceivedWebhookDelivery"
+
+	ErrorCodeProjectMirrorDoesNotExist util.ErrorCode = "projectMirrorDoesNotExist"
+	ErrorCodeProjectMirrorNotClaimed   util.ErrorCode = "projectMirrorNotClaimed"
+	ErrorCodeInvalidProjectMirror      util.ErrorCode = "invalidProjectMirror"
+
+	ErrorCodeInvalidExportScope util.ErrorCode = "invalidExportScope"
+	ErrorCodeInvalidExportData  util.ErrorCode = "invalidExportData"
+	ErrorCodeRunAlreadyExists   util.ErrorCode = "runAlreadyExists"
+
+	ErrorCodeBackupDoesNotExist util.ErrorCode = "backupDoesNotExist"
+	ErrorCodeInvalidBackupID    util.ErrorCode = "invalidBackupID"
+	ErrorCodeDatabaseNotEmpty   util.ErrorCode = "databaseNotEmpty"
 )
@@ -17,8 +17,12 @@ package types
 type MemberRole string
 
 const (
-	MemberRoleOwner  MemberRole = "owner"
-	MemberRoleMember MemberRole = "member"
+	MemberRoleOwner      MemberRole = "owner"
+	MemberRoleAdmin      MemberRole = "admin"
+	MemberRoleMaintainer MemberRole = "maintainer"
+	MemberRoleDeveloper  MemberRole = "developer"
+	MemberRoleMember     MemberRole = "member"
+	MemberRoleViewer     MemberRole = "viewer"
 )
 
 type CreateOrgRequest struct {
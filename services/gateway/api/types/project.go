@@ -23,6 +23,10 @@ type CreateProjectRequest struct {
 	SkipSSHHostKeyCheck         bool       `json:"skip_ssh_host_key_check,omitempty"`
 	PassVarsToForkedPR          bool       `json:"pass_vars_to_forked_pr,omitempty"`
 	MembersCanPerformRunActions bool       `json:"members_can_perform_run_actions,omitempty"`
+	MirrorURL                   string     `json:"mirror_url,omitempty"`
+	MirrorSSHHostKey            string     `json:"mirror_ssh_host_key,omitempty"`
+	MirrorBranches              []string   `json:"mirror_branches,omitempty"`
+	MirrorTags                  []string   `json:"mirror_tags,omitempty"`
 }
 
 type UpdateProjectRequest struct {
@@ -0,0 +1,32 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "time"
+
+type ProjectMirrorResponse struct {
+	URL        string   `json:"url"`
+	SSHHostKey string   `json:"ssh_host_key"`
+	Branches   []string `json:"branches"`
+	Tags       []string `json:"tags"`
+	// SSHPublicKey is the project public key that must be authorized on the
+	// git server to access the repository
+	SSHPublicKey string `json:"ssh_public_key"`
+
+	Refs          map[string]string `json:"refs"`
+	NextPollTime  time.Time         `json:"next_poll_time"`
+	LastPollTime  *time.Time        `json:"last_poll_time"`
+	LastPollError string            `json:"last_poll_error"`
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "time"
+
+type ReceivedWebhookStatus string
+
+const (
+	ReceivedWebhookStatusPending    ReceivedWebhookStatus = "pending"
+	ReceivedWebhookStatusProcessing ReceivedWebhookStatus = "processing"
+	ReceivedWebhookStatusProcessed  ReceivedWebhookStatus = "processed"
+	ReceivedWebhookStatusFailed     ReceivedWebhookStatus = "failed"
+)
+
+type ReceivedWebhookOutcome string
+
+const (
+	ReceivedWebhookOutcomeSkipped     ReceivedWebhookOutcome = "skipped"
+	ReceivedWebhookOutcomeSetupError  ReceivedWebhookOutcome = "setupError"
+	ReceivedWebhookOutcomeRunsCreated ReceivedWebhookOutcome = "runsCreated"
+	ReceivedWebhookOutcomeRunsStopped ReceivedWebhookOutcome = "runsStopped"
+)
+
+type ReceivedWebhookResponse struct {
+	ID             string                 `json:"id"`
+	Sequence       uint64                 `json:"sequence"`
+	DeliveryID     string                 `json:"delivery_id"`
+	Event          string                 `json:"event"`
+	ReceivedAt     time.Time              `json:"received_at"`
+	Status         ReceivedWebhookStatus  `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	Outcome        ReceivedWebhookOutcome `json:"outcome"`
+	OutcomeMessage string                 `json:"outcome_message"`
+	RunNumbers     []uint64               `json:"run_numbers"`
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+type RoleBindingResponse struct {
+	ID          string     `json:"id"`
+	SubjectKind string     `json:"subject_kind"`
+	SubjectID   string     `json:"subject_id"`
+	SubjectName string     `json:"subject_name"`
+	Role        MemberRole `json:"role"`
+	ParentPath  string     `json:"parent_path"`
+}
+
+type SetRoleBindingRequest struct {
+	Role MemberRole `json:"role"`
+}
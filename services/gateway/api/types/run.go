@@ -85,9 +85,11 @@ type RunTaskResponse struct {
 	Timedout   bool                       `json:"timedout"`
 	Containers []RunTaskResponseContainer `json:"containers"`
 
-	WaitingApproval     bool              `json:"waiting_approval"`
-	Approved            bool              `json:"approved"`
-	ApprovalAnnotations map[string]string `json:"approval_annotations"`
+	WaitingApproval     bool                       `json:"waiting_approval"`
+	Approved            bool                       `json:"approved"`
+	ApprovalAnnotations map[string]string          `json:"approval_annotations"`
+	ApprovalPolicy      *RunTaskApprovalPolicy     `json:"approval_policy"`
+	Approvals           []*RunTaskApprovalResponse `json:"approvals"`
 
 	SetupStep *RunTaskResponseSetupStep `json:"setup_step"`
 	Steps     []*RunTaskResponseStep    `json:"steps"`
@@ -98,6 +100,22 @@ type RunTaskResponse struct {
 	TaskTimeoutInterval time.Duration `json:"task_timeout_interval"`
 }
 
+type RunTaskApprovalPolicy struct {
+	MinApprovers       int           `json:"min_approvers"`
+	AllowedUsers       []string      `json:"allowed_users"`
+	AllowedTeams       []string      `json:"allowed_teams"`
+	AllowedRoles       []string      `json:"allowed_roles"`
+	ForbidSelfApproval bool          `json:"forbid_self_approval"`
+	Expiry             time.Duration `json:"expiry"`
+}
+
+type RunTaskApprovalResponse struct {
+	UserID       string     `json:"user_id"`
+	UserName     string     `json:"user_name"`
+	ApprovalTime *time.Time `json:"approval_time"`
+	Expired      bool       `json:"expired"`
+}
+
 type RunTaskResponseContainer struct {
 	Image string `json:"image"`
 }
@@ -149,3 +167,80 @@ const (
 type RunTaskActionsRequest struct {
 	ActionType RunTaskActionType `json:"action_type"`
 }
+
+// RunEventResponse is a run event sent by the run events stream.
+type RunEventResponse struct {
+	Sequence  uint64               `json:"sequence"`
+	Type      rstypes.RunEventType `json:"type"`
+	ProjectID string               `json:"project_id,omitempty"`
+	UserID    string               `json:"user_id,omitempty"`
+
+	Run *RunEventRunResponse `json:"run"`
+}
+
+type RunEventRunResponse struct {
+	Number      uint64            `json:"number"`
+	Name        string            `json:"name"`
+	Annotations map[string]string `json:"annotations"`
+	Phase       rstypes.RunPhase  `json:"phase"`
+	Result      rstypes.RunResult `json:"result"`
+	SetupErrors []string          `json:"setup_errors"`
+
+	Tasks map[string]*RunEventRunTaskResponse `json:"tasks"`
+
+	EnqueueTime *time.Time `json:"enqueue_time"`
+	StartTime   *time.Time `json:"start_time"`
+	EndTime     *time.Time `json:"end_time"`
+}
+
+type RunEventRunTaskResponse struct {
+	ID              string                `json:"id"`
+	Name            string                `json:"name"`
+	Level           int                   `json:"level"`
+	Status          rstypes.RunTaskStatus `json:"status"`
+	Timedout        bool                  `json:"timedout"`
+	Skip            bool                  `json:"skip"`
+	WaitingApproval bool                  `json:"waiting_approval"`
+	Approved        bool                  `json:"approved"`
+
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+// LogLineResponse is a log line returned when requesting a log in the ndjson
+// format.
+type LogLineResponse struct {
+	// Number is the zero based line number.
+	Number uint64 `json:"n"`
+	// Time is the time when the line was started. It's empty for logs saved
+	// before the structured log format was introduced.
+	Time *time.Time `json:"time,omitempty"`
+	// Stream is stdout or stderr. It's empty when they can't be distinguished
+	// (i.e. when using a tty).
+	Stream string `json:"stream,omitempty"`
+	Text   string `json:"text"`
+	// Partial is true when the line text isn't terminated by a newline.
+	Partial bool `json:"partial,omitempty"`
+}
+
+type RunLogsSearchLineResponse struct {
+	Number uint64     `json:"n"`
+	Time   *time.Time `json:"time,omitempty"`
+	Stream string     `json:"stream,omitempty"`
+	Text   string     `json:"text"`
+}
+
+type RunLogsSearchMatchResponse struct {
+	TaskID   string                       `json:"task_id"`
+	TaskName string                       `json:"task_name"`
+	Step     int                          `json:"step"`
+	Line     *RunLogsSearchLineResponse   `json:"line"`
+	Before   []*RunLogsSearchLineResponse `json:"before"`
+	After    []*RunLogsSearchLineResponse `json:"after"`
+}
+
+type RunLogsSearchResponse struct {
+	Matches []*RunLogsSearchMatchResponse `json:"matches"`
+	// Truncated is true when there're more matches than the requested limit
+	Truncated bool `json:"truncated"`
+}
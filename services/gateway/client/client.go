@@ -15,6 +15,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -25,6 +26,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sorintlab/errors"
 
@@ -372,6 +374,86 @@ func (c *Client) GetProjectVariables(ctx context.Context, projectRef string, tre
 	return variables, resp, errors.WithStack(err)
 }
 
+func (c *Client) GetProjectGroupRoleBindings(ctx context.Context, projectGroupRef string, tree bool) ([]*gwapitypes.RoleBindingResponse, *Response, error) {
+	roleBindings := []*gwapitypes.RoleBindingResponse{}
+	q := url.Values{}
+	if tree {
+		q.Add("tree", "")
+	}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/projectgroups/%s/rolebindings", url.PathEscape(projectGroupRef)), q, jsonContent, nil, &roleBindings)
+	return roleBindings, resp, errors.WithStack(err)
+}
+
+func (c *Client) SetProjectGroupUserRoleBinding(ctx context.Context, projectGroupRef, userRef string, req *gwapitypes.SetRoleBindingRequest) (*gwapitypes.RoleBindingResponse, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(gwapitypes.RoleBindingResponse)
+	resp, err := c.getParsedResponse(ctx, "PUT", path.Join("/projectgroups", url.PathEscape(projectGroupRef), "rolebindings", "users", userRef), nil, jsonContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectGroupUserRoleBinding(ctx context.Context, projectGroupRef, userRef string) (*Response, error) {
+	return c.getResponse(ctx, "DELETE", path.Join("/projectgroups", url.PathEscape(projectGroupRef), "rolebindings", "users", userRef), nil, jsonContent, nil)
+}
+
+func (c *Client) SetProjectGroupTeamRoleBinding(ctx context.Context, projectGroupRef, teamRef string, req *gwapitypes.SetRoleBindingRequest) (*gwapitypes.RoleBindingResponse, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(gwapitypes.RoleBindingResponse)
+	resp, err := c.getParsedResponse(ctx, "PUT", path.Join("/projectgroups", url.PathEscape(projectGroupRef), "rolebindings", "teams", teamRef), nil, jsonContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectGroupTeamRoleBinding(ctx context.Context, projectGroupRef, teamRef string) (*Response, error) {
+	return c.getResponse(ctx, "DELETE", path.Join("/projectgroups", url.PathEscape(projectGroupRef), "rolebindings", "teams", teamRef), nil, jsonContent, nil)
+}
+
+func (c *Client) GetProjectRoleBindings(ctx context.Context, projectRef string, tree bool) ([]*gwapitypes.RoleBindingResponse, *Response, error) {
+	roleBindings := []*gwapitypes.RoleBindingResponse{}
+	q := url.Values{}
+	if tree {
+		q.Add("tree", "")
+	}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/rolebindings", url.PathEscape(projectRef)), q, jsonContent, nil, &roleBindings)
+	return roleBindings, resp, errors.WithStack(err)
+}
+
+func (c *Client) SetProjectUserRoleBinding(ctx context.Context, projectRef, userRef string, req *gwapitypes.SetRoleBindingRequest) (*gwapitypes.RoleBindingResponse, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(gwapitypes.RoleBindingResponse)
+	resp, err := c.getParsedResponse(ctx, "PUT", path.Join("/projects", url.PathEscape(projectRef), "rolebindings", "users", userRef), nil, jsonContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectUserRoleBinding(ctx context.Context, projectRef, userRef string) (*Response, error) {
+	return c.getResponse(ctx, "DELETE", path.Join("/projects", url.PathEscape(projectRef), "rolebindings", "users", userRef), nil, jsonContent, nil)
+}
+
+func (c *Client) SetProjectTeamRoleBinding(ctx context.Context, projectRef, teamRef string, req *gwapitypes.SetRoleBindingRequest) (*gwapitypes.RoleBindingResponse, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	roleBinding := new(gwapitypes.RoleBindingResponse)
+	resp, err := c.getParsedResponse(ctx, "PUT", path.Join("/projects", url.PathEscape(projectRef), "rolebindings", "teams", teamRef), nil, jsonContent, bytes.NewReader(reqj), roleBinding)
+	return roleBinding, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteProjectTeamRoleBinding(ctx context.Context, projectRef, teamRef string) (*Response, error) {
+	return c.getResponse(ctx, "DELETE", path.Join("/projects", url.PathEscape(projectRef), "rolebindings", "teams", teamRef), nil, jsonContent, nil)
+}
+
 func (c *Client) DeleteProject(ctx context.Context, projectRef string) (*Response, error) {
 	return c.getResponse(ctx, "DELETE", fmt.Sprintf("/projects/%s", url.PathEscape(projectRef)), nil, jsonContent, nil)
 }
@@ -439,13 +521,15 @@ func (c *Client) DeleteUser(ctx context.Context, userRef string) (*Response, err
 	return c.getResponse(ctx, "DELETE", fmt.Sprintf("/users/%s", userRef), nil, jsonContent, nil)
 }
 
-func (c *Client) UserCreateRun(ctx context.Context, req *gwapitypes.UserCreateRunRequest) (*Response, error) {
+func (c *Client) UserCreateRun(ctx context.Context, req *gwapitypes.UserCreateRunRequest) ([]*gwapitypes.RunsResponse, *Response, error) {
 	reqj, err := json.Marshal(req)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, nil, errors.WithStack(err)
 	}
 
-	return c.getResponse(ctx, "POST", "/user/createrun", nil, jsonContent, bytes.NewReader(reqj))
+	runs := []*gwapitypes.RunsResponse{}
+	resp, err := c.getParsedResponse(ctx, "POST", "/user/createrun", nil, jsonContent, bytes.NewReader(reqj), &runs)
+	return runs, resp, errors.WithStack(err)
 }
 
 func (c *Client) CreateUserLA(ctx context.Context, userRef string, req *gwapitypes.CreateUserLARequest) (*gwapitypes.CreateUserLAResponse, *Response, error) {
@@ -505,13 +589,21 @@ func (c *Client) GetProjectRun(ctx context.Context, projectRef string, runNumber
 }
 
 func (c *Client) ProjectRunAction(ctx context.Context, projectRef string, runNumber uint64, req *gwapitypes.RunActionsRequest) (*gwapitypes.RunResponse, *Response, error) {
+	return c.runAction(ctx, "projects", projectRef, runNumber, req)
+}
+
+func (c *Client) UserRunAction(ctx context.Context, userRef string, runNumber uint64, req *gwapitypes.RunActionsRequest) (*gwapitypes.RunResponse, *Response, error) {
+	return c.runAction(ctx, "users", userRef, runNumber, req)
+}
+
+func (c *Client) runAction(ctx context.Context, groupType, groupRef string, runNumber uint64, req *gwapitypes.RunActionsRequest) (*gwapitypes.RunResponse, *Response, error) {
 	reqj, err := json.Marshal(req)
 	if err != nil {
 		return nil, nil, errors.WithStack(err)
 	}
 
 	tresp := new(gwapitypes.RunResponse)
-	resp, err := c.getParsedResponse(ctx, "PUT", fmt.Sprintf("/projects/%s/runs/%d/actions", url.PathEscape(projectRef), runNumber), nil, jsonContent, bytes.NewReader(reqj), tresp)
+	resp, err := c.getParsedResponse(ctx, "PUT", fmt.Sprintf("/%s/%s/runs/%d/actions", groupType, url.PathEscape(groupRef), runNumber), nil, jsonContent, bytes.NewReader(reqj), tresp)
 	return tresp, resp, errors.WithStack(err)
 }
 
@@ -539,6 +631,24 @@ func (c *Client) getRunTask(ctx context.Context, groupType, groupRef string, run
 	return task, resp, errors.WithStack(err)
 }
 
+func (c *Client) ProjectRunTaskAction(ctx context.Context, projectRef string, runNumber uint64, taskID string, req *gwapitypes.RunTaskActionsRequest) (*Response, error) {
+	return c.runTaskAction(ctx, "projects", projectRef, runNumber, taskID, req)
+}
+
+func (c *Client) UserRunTaskAction(ctx context.Context, userRef string, runNumber uint64, taskID string, req *gwapitypes.RunTaskActionsRequest) (*Response, error) {
+	return c.runTaskAction(ctx, "users", userRef, runNumber, taskID, req)
+}
+
+func (c *Client) runTaskAction(ctx context.Context, groupType, groupRef string, runNumber uint64, taskID string, req *gwapitypes.RunTaskActionsRequest) (*Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := c.getResponse(ctx, "PUT", fmt.Sprintf("/%s/%s/runs/%d/tasks/%s/actions", groupType, url.PathEscape(groupRef), runNumber, taskID), nil, jsonContent, bytes.NewReader(reqj))
+	return resp, errors.WithStack(err)
+}
+
 func (c *Client) GetProjectRuns(ctx context.Context, projectRef string, opts *GetRunsOptions) ([]*gwapitypes.RunsResponse, *Response, error) {
 	return c.getGroupRuns(ctx, "projects", projectRef, opts)
 }
@@ -586,15 +696,41 @@ func (c *Client) getGroupRuns(ctx context.Context, groupType, groupRef string, o
 	return getRunsResponse, resp, errors.WithStack(err)
 }
 
-func (c *Client) GetProjectLogs(ctx context.Context, projectRef string, runNumber uint64, taskID string, setup bool, step int, follow bool) (*Response, error) {
-	return c.getLogs(ctx, "projects", projectRef, runNumber, taskID, setup, step, follow)
+type GetLogsOptions struct {
+	// Format is the log format: text (the default) or ndjson. The ndjson log
+	// lines can be read using ReadLogLines.
+	Format string
+	// Start is the number of the first line to return.
+	Start uint64
+	// Limit is the maximum number of lines to return.
+	Limit uint64
 }
 
-func (c *Client) GetUserLogs(ctx context.Context, userRef string, runNumber uint64, taskID string, setup bool, step int, follow bool) (*Response, error) {
-	return c.getLogs(ctx, "users", userRef, runNumber, taskID, setup, step, follow)
+func (o *GetLogsOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	if o.Format != "" {
+		q.Add("format", o.Format)
+	}
+	if o.Start > 0 {
+		q.Add("start", strconv.FormatUint(o.Start, 10))
+	}
+	if o.Limit > 0 {
+		q.Add("limit", strconv.FormatUint(o.Limit, 10))
+	}
+}
+
+func (c *Client) GetProjectLogs(ctx context.Context, projectRef string, runNumber uint64, taskID string, setup bool, step int, follow bool, opts *GetLogsOptions) (*Response, error) {
+	return c.getLogs(ctx, "projects", projectRef, runNumber, taskID, setup, step, follow, opts)
 }
 
-func (c *Client) getLogs(ctx context.Context, groupType, groupRef string, runNumber uint64, taskID string, setup bool, step int, follow bool) (*Response, error) {
+func (c *Client) GetUserLogs(ctx context.Context, userRef string, runNumber uint64, taskID string, setup bool, step int, follow bool, opts *GetLogsOptions) (*Response, error) {
+	return c.getLogs(ctx, "users", userRef, runNumber, taskID, setup, step, follow, opts)
+}
+
+func (c *Client) getLogs(ctx context.Context, groupType, groupRef string, runNumber uint64, taskID string, setup bool, step int, follow bool, opts *GetLogsOptions) (*Response, error) {
 	q := url.Values{}
 	if setup {
 		q.Add("setup", "")
@@ -604,9 +740,168 @@ func (c *Client) getLogs(ctx context.Context, groupType, groupRef string, runNum
 	if follow {
 		q.Add("follow", "")
 	}
+	opts.Add(q)
 	return c.getResponse(ctx, "GET", fmt.Sprintf("/%s/%s/runs/%d/tasks/%s/logs", groupType, url.PathEscape(groupRef), runNumber, taskID), q, nil, nil)
 }
 
+type SearchRunLogsOptions struct {
+	// TaskIDs limits the search to the provided run tasks
+	TaskIDs []string
+
+	// Regex is true when the pattern is a regular expression
+	Regex      bool
+	IgnoreCase bool
+
+	// Context is the number of lines to return before and after a matching line
+	Context int
+	// Limit is the maximum number of matches to return
+	Limit int
+}
+
+func (o *SearchRunLogsOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	for _, taskID := range o.TaskIDs {
+		q.Add("taskid", taskID)
+	}
+	if o.Regex {
+		q.Add("regex", "")
+	}
+	if o.IgnoreCase {
+		q.Add("ignorecase", "")
+	}
+	if o.Context > 0 {
+		q.Add("context", strconv.Itoa(o.Context))
+	}
+	if o.Limit > 0 {
+		q.Add("limit", strconv.Itoa(o.Limit))
+	}
+}
+
+// SearchProjectRunLogs searches the pattern in the archived step logs of a
+// project run.
+func (c *Client) SearchProjectRunLogs(ctx context.Context, projectRef string, runNumber uint64, pattern string, opts *SearchRunLogsOptions) (*gwapitypes.RunLogsSearchResponse, *Response, error) {
+	return c.searchRunLogs(ctx, "projects", projectRef, runNumber, pattern, opts)
+}
+
+// SearchUserRunLogs searches the pattern in the archived step logs of a user
+// direct run.
+func (c *Client) SearchUserRunLogs(ctx context.Context, userRef string, runNumber uint64, pattern string, opts *SearchRunLogsOptions) (*gwapitypes.RunLogsSearchResponse, *Response, error) {
+	return c.searchRunLogs(ctx, "users", userRef, runNumber, pattern, opts)
+}
+
+func (c *Client) searchRunLogs(ctx context.Context, groupType, groupRef string, runNumber uint64, pattern string, opts *SearchRunLogsOptions) (*gwapitypes.RunLogsSearchResponse, *Response, error) {
+	q := url.Values{}
+	q.Add("pattern", pattern)
+	opts.Add(q)
+
+	res := new(gwapitypes.RunLogsSearchResponse)
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/%s/%s/runs/%d/logs/search", groupType, url.PathEscape(groupRef), runNumber), q, jsonContent, nil, res)
+	return res, resp, errors.WithStack(err)
+}
+
+// ReadLogLines reads the log lines of a log requested in the ndjson format
+// and calls f for every line. It returns when the log ends or f returns an
+// error.
+func ReadLogLines(r io.Reader, f func(l *gwapitypes.LogLineResponse) error) error {
+	br := bufio.NewReader(r)
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+
+		var l *gwapitypes.LogLineResponse
+		if err := json.Unmarshal(line, &l); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if err := f(l); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+}
+
+type GetRunEventsOptions struct {
+	// AfterSequence is the sequence of the last received run event. If zero
+	// the stream starts from the next run event.
+	AfterSequence uint64
+
+	ProjectRefs []string
+	OrgRefs     []string
+	UserRefs    []string
+}
+
+func (o *GetRunEventsOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	if o.AfterSequence != 0 {
+		q.Add("aftersequence", strconv.FormatUint(o.AfterSequence, 10))
+	}
+	for _, projectRef := range o.ProjectRefs {
+		q.Add("project", projectRef)
+	}
+	for _, orgRef := range o.OrgRefs {
+		q.Add("org", orgRef)
+	}
+	for _, userRef := range o.UserRefs {
+		q.Add("user", userRef)
+	}
+}
+
+// GetRunEvents opens the run events stream. The run events can be read from
+// the response body using ReadRunEvents.
+func (c *Client) GetRunEvents(ctx context.Context, opts *GetRunEventsOptions) (*Response, error) {
+	q := url.Values{}
+	opts.Add(q)
+
+	return c.getResponse(ctx, "GET", "/runs/events", q, nil, nil)
+}
+
+// ReadRunEvents reads the run events from a run events stream and calls f for
+// every run event. It returns when the stream ends or f returns an error.
+func ReadRunEvents(r io.Reader, f func(ev *gwapitypes.RunEventResponse) error) error {
+	br := bufio.NewReader(r)
+
+	var buf bytes.Buffer
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		switch {
+		case bytes.HasPrefix(line, []byte("data: ")):
+			buf.Write(line[6:])
+		case bytes.Equal(line, []byte("\n")):
+			// skip events without data
+			if buf.Len() == 0 {
+				continue
+			}
+
+			var ev *gwapitypes.RunEventResponse
+			if err := json.Unmarshal(buf.Bytes(), &ev); err != nil {
+				return errors.WithStack(err)
+			}
+			buf.Reset()
+
+			if err := f(ev); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+}
+
 func (c *Client) DeleteProjectLogs(ctx context.Context, projectRef string, runNumber uint64, taskID string, setup bool, step int) (*Response, error) {
 	return c.deleteLogs(ctx, "projects", projectRef, runNumber, taskID, setup, step)
 }
@@ -735,6 +1030,58 @@ func (c *Client) GetOrgMembers(ctx context.Context, orgRef string, opts *ListOpt
 	return res, resp, errors.WithStack(err)
 }
 
+func (c *Client) GetOrgTeams(ctx context.Context, orgRef string) ([]*gwapitypes.TeamResponse, *Response, error) {
+	teams := []*gwapitypes.TeamResponse{}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/teams", orgRef), nil, jsonContent, nil, &teams)
+	return teams, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetOrgTeam(ctx context.Context, orgRef, teamRef string) (*gwapitypes.TeamResponse, *Response, error) {
+	team := new(gwapitypes.TeamResponse)
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/teams/%s", orgRef, teamRef), nil, jsonContent, nil, team)
+	return team, resp, errors.WithStack(err)
+}
+
+func (c *Client) CreateOrgTeam(ctx context.Context, orgRef string, req *gwapitypes.CreateTeamRequest) (*gwapitypes.TeamResponse, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	team := new(gwapitypes.TeamResponse)
+	resp, err := c.getParsedResponse(ctx, "POST", fmt.Sprintf("/orgs/%s/teams", orgRef), nil, jsonContent, bytes.NewReader(reqj), team)
+	return team, resp, errors.WithStack(err)
+}
+
+func (c *Client) UpdateOrgTeam(ctx context.Context, orgRef, teamRef string, req *gwapitypes.UpdateTeamRequest) (*gwapitypes.TeamResponse, *Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	team := new(gwapitypes.TeamResponse)
+	resp, err := c.getParsedResponse(ctx, "PUT", fmt.Sprintf("/orgs/%s/teams/%s", orgRef, teamRef), nil, jsonContent, bytes.NewReader(reqj), team)
+	return team, resp, errors.WithStack(err)
+}
+
+func (c *Client) DeleteOrgTeam(ctx context.Context, orgRef, teamRef string) (*Response, error) {
+	return c.getResponse(ctx, "DELETE", fmt.Sprintf("/orgs/%s/teams/%s", orgRef, teamRef), nil, jsonContent, nil)
+}
+
+func (c *Client) GetOrgTeamMembers(ctx context.Context, orgRef, teamRef string) ([]*gwapitypes.UserResponse, *Response, error) {
+	users := []*gwapitypes.UserResponse{}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/teams/%s/members", orgRef, teamRef), nil, jsonContent, nil, &users)
+	return users, resp, errors.WithStack(err)
+}
+
+func (c *Client) AddOrgTeamMember(ctx context.Context, orgRef, teamRef, userRef string) (*Response, error) {
+	return c.getResponse(ctx, "PUT", fmt.Sprintf("/orgs/%s/teams/%s/members/%s", orgRef, teamRef, userRef), nil, jsonContent, nil)
+}
+
+func (c *Client) RemoveOrgTeamMember(ctx context.Context, orgRef, teamRef, userRef string) (*Response, error) {
+	return c.getResponse(ctx, "DELETE", fmt.Sprintf("/orgs/%s/teams/%s/members/%s", orgRef, teamRef, userRef), nil, jsonContent, nil)
+}
+
 func (c *Client) GetVersion(ctx context.Context) (*gwapitypes.VersionResponse, *Response, error) {
 	res := &gwapitypes.VersionResponse{}
 	resp, err := c.getParsedResponse(ctx, "GET", "/version", nil, jsonContent, nil, &res)
@@ -828,6 +1175,40 @@ func (c *Client) Import(ctx context.Context, serviceName string, r io.Reader) (*
 	return c.getResponse(ctx, "POST", fmt.Sprintf("/import/%s", serviceName), nil, jsonContent, r)
 }
 
+func (c *Client) GetBackups(ctx context.Context, serviceName string) ([]*gwapitypes.BackupResponse, *Response, error) {
+	backups := []*gwapitypes.BackupResponse{}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/backups/%s", serviceName), nil, jsonContent, nil, &backups)
+	return backups, resp, errors.WithStack(err)
+}
+
+// RestoreBackup restores a service database backup. The service must be in
+// maintenance mode and its database must be empty.
+func (c *Client) RestoreBackup(ctx context.Context, serviceName, backupID string) (*Response, error) {
+	return c.getResponse(ctx, "POST", fmt.Sprintf("/backups/%s/%s/restore", serviceName, backupID), nil, jsonContent, nil)
+}
+
+// ScopedExport exports an organization or a project group. kind must be "org"
+// or "projectgroup".
+func (c *Client) ScopedExport(ctx context.Context, kind, ref string) (*Response, error) {
+	q := url.Values{}
+	q.Add("kind", kind)
+	q.Add("ref", ref)
+
+	return c.getResponse(ctx, "GET", "/scopedexport", q, jsonContent, nil)
+}
+
+// ScopedImport imports an organization or a project group exported by
+// ScopedExport. parentRef is the project group where an exported project
+// group will be imported.
+func (c *Client) ScopedImport(ctx context.Context, parentRef string, r io.Reader) (*Response, error) {
+	q := url.Values{}
+	if parentRef != "" {
+		q.Add("parentref", parentRef)
+	}
+
+	return c.getResponse(ctx, "POST", "/scopedimport", q, jsonContent, r)
+}
+
 type DeliveriesOptions struct {
 	*ListOptions
 
@@ -855,6 +1236,39 @@ func (c *Client) GetProjectRunWebhookDeliveries(ctx context.Context, projectRef
 	return runWebhookDeliveries, resp, errors.WithStack(err)
 }
 
+type ReceivedWebhooksOptions struct {
+	*ListOptions
+
+	StatusFilter []string
+}
+
+func (o *ReceivedWebhooksOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	o.ListOptions.Add(q)
+
+	for _, status := range o.StatusFilter {
+		q.Add("status", status)
+	}
+}
+
+func (c *Client) GetProjectReceivedWebhooks(ctx context.Context, projectRef string, opts *ReceivedWebhooksOptions) ([]*gwapitypes.ReceivedWebhookResponse, *Response, error) {
+	q := url.Values{}
+	opts.Add(q)
+
+	receivedWebhooks := []*gwapitypes.ReceivedWebhookResponse{}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/receivedwebhooks", url.PathEscape(projectRef)), q, common.JSONContent, nil, &receivedWebhooks)
+	return receivedWebhooks, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetProjectMirror(ctx context.Context, projectRef string) (*gwapitypes.ProjectMirrorResponse, *Response, error) {
+	projectMirror := new(gwapitypes.ProjectMirrorResponse)
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/projects/%s/mirror", url.PathEscape(projectRef)), nil, common.JSONContent, nil, projectMirror)
+	return projectMirror, resp, errors.WithStack(err)
+}
+
 func (c *Client) ProjectRunWebhookRedelivery(ctx context.Context, projectRef string, runWebhookDeliveryID string) (*Response, error) {
 	return c.getResponse(ctx, "PUT", fmt.Sprintf("/projects/%s/runwebhookdeliveries/%s/redelivery", url.PathEscape(projectRef), runWebhookDeliveryID), nil, jsonContent, nil)
 }
@@ -871,3 +1285,59 @@ func (c *Client) GetProjectCommitStatusDeliveries(ctx context.Context, projectRe
 func (c *Client) ProjectCommitStatusRedelivery(ctx context.Context, projectRef string, commitStatusDeliveryID string) (*Response, error) {
 	return c.getResponse(ctx, "PUT", fmt.Sprintf("/projects/%s/commitstatusdeliveries/%s/redelivery", url.PathEscape(projectRef), commitStatusDeliveryID), nil, jsonContent, nil)
 }
+
+type AuditEventsOptions struct {
+	*ListOptions
+
+	ActorUserRef string
+	Actions      []string
+	TargetKind   string
+	TargetID     string
+	Since        *time.Time
+	Until        *time.Time
+}
+
+func (o *AuditEventsOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	o.ListOptions.Add(q)
+
+	if o.ActorUserRef != "" {
+		q.Add("actor", o.ActorUserRef)
+	}
+	for _, action := range o.Actions {
+		q.Add("action", action)
+	}
+	if o.TargetKind != "" {
+		q.Add("targetkind", o.TargetKind)
+	}
+	if o.TargetID != "" {
+		q.Add("targetid", o.TargetID)
+	}
+	if o.Since != nil {
+		q.Add("since", o.Since.Format(time.RFC3339))
+	}
+	if o.Until != nil {
+		q.Add("until", o.Until.Format(time.RFC3339))
+	}
+}
+
+func (c *Client) GetAuditEvents(ctx context.Context, opts *AuditEventsOptions) ([]*gwapitypes.AuditEventResponse, *Response, error) {
+	q := url.Values{}
+	opts.Add(q)
+
+	auditEvents := []*gwapitypes.AuditEventResponse{}
+	resp, err := c.getParsedResponse(ctx, "GET", "/auditevents", q, common.JSONContent, nil, &auditEvents)
+	return auditEvents, resp, errors.WithStack(err)
+}
+
+func (c *Client) GetOrgAuditEvents(ctx context.Context, orgRef string, opts *AuditEventsOptions) ([]*gwapitypes.AuditEventResponse, *Response, error) {
+	q := url.Values{}
+	opts.Add(q)
+
+	auditEvents := []*gwapitypes.AuditEventResponse{}
+	resp, err := c.getParsedResponse(ctx, "GET", fmt.Sprintf("/orgs/%s/auditevents", url.PathEscape(orgRef)), q, common.JSONContent, nil, &auditEvents)
+	return auditEvents, resp, errors.WithStack(err)
+}
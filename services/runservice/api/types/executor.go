@@ -79,4 +79,8 @@ type ExecutorTaskSpecData struct {
 	Steps types.Steps `json:"steps"`
 
 	TaskTimeoutInterval time.Duration `json:"task_timeout_interval"`
+
+	SecretValues []string `json:"secret_values"`
+
+	TraceParent string `json:"trace_parent"`
 }
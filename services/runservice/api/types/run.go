@@ -15,6 +15,8 @@
 package types
 
 import (
+	"time"
+
 	rstypes "agola.io/agola/services/runservice/types"
 )
 
@@ -80,3 +82,23 @@ type RunTaskActionsRequest struct {
 	// global fields
 	ChangeGroupsUpdateToken string `json:"change_groups_update_tokens"`
 }
+
+type RunLogsSearchLine struct {
+	Number uint64     `json:"n"`
+	Time   *time.Time `json:"time,omitempty"`
+	Stream string     `json:"stream,omitempty"`
+	Text   string     `json:"text"`
+}
+
+type RunLogsSearchMatch struct {
+	TaskID string               `json:"task_id"`
+	Step   int                  `json:"step"`
+	Line   *RunLogsSearchLine   `json:"line"`
+	Before []*RunLogsSearchLine `json:"before"`
+	After  []*RunLogsSearchLine `json:"after"`
+}
+
+type RunLogsSearchResponse struct {
+	Matches   []*RunLogsSearchMatch `json:"matches"`
+	Truncated bool                  `json:"truncated"`
+}
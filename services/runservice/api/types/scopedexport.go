@@ -0,0 +1,26 @@
+// Copyright 2026 Sorint.lab
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// ScopedImportRequest is sent before the scoped export data in a scoped
+// import request body.
+type ScopedImportRequest struct {
+	// GroupsRemap maps the exported run groups to the run groups where their
+	// runs will be imported. Child groups are remapped too.
+	GroupsRemap map[string]string
+	// AnnotationsRemap maps, for every annotation name, the exported runs
+	// annotation values to the new ones.
+	AnnotationsRemap map[string]map[string]string
+}
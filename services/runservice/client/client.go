@@ -15,6 +15,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -357,7 +358,32 @@ func (c *Client) GetRunByGroup(ctx context.Context, group string, runNumber uint
 	return runResponse, resp, errors.WithStack(err)
 }
 
-func (c *Client) GetLogs(ctx context.Context, runID, taskID string, setup bool, step int, follow bool) (*Response, error) {
+type GetLogsOptions struct {
+	// Format is the log format: text (the default) or ndjson.
+	Format string
+	// Start is the number of the first line to return.
+	Start uint64
+	// Limit is the maximum number of lines to return.
+	Limit uint64
+}
+
+func (o *GetLogsOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	if o.Format != "" {
+		q.Add("format", o.Format)
+	}
+	if o.Start > 0 {
+		q.Add("start", strconv.FormatUint(o.Start, 10))
+	}
+	if o.Limit > 0 {
+		q.Add("limit", strconv.FormatUint(o.Limit, 10))
+	}
+}
+
+func (c *Client) GetLogs(ctx context.Context, runID, taskID string, setup bool, step int, follow bool, opts *GetLogsOptions) (*Response, error) {
 	q := url.Values{}
 	q.Add("runid", runID)
 	q.Add("taskid", taskID)
@@ -369,11 +395,57 @@ func (c *Client) GetLogs(ctx context.Context, runID, taskID string, setup bool,
 	if follow {
 		q.Add("follow", "")
 	}
+	opts.Add(q)
 
 	resp, err := c.GetResponse(ctx, "GET", "/logs", q, -1, nil, nil)
 	return resp, errors.WithStack(err)
 }
 
+type SearchRunLogsOptions struct {
+	// TaskIDs limits the search to the provided run tasks
+	TaskIDs []string
+
+	Regex      bool
+	IgnoreCase bool
+
+	// Context is the number of lines to return before and after a matching line
+	Context int
+	// Limit is the maximum number of matches to return
+	Limit int
+}
+
+func (o *SearchRunLogsOptions) Add(q url.Values) {
+	if o == nil {
+		return
+	}
+
+	for _, taskID := range o.TaskIDs {
+		q.Add("taskid", taskID)
+	}
+	if o.Regex {
+		q.Add("regex", "")
+	}
+	if o.IgnoreCase {
+		q.Add("ignorecase", "")
+	}
+	if o.Context > 0 {
+		q.Add("context", strconv.Itoa(o.Context))
+	}
+	if o.Limit > 0 {
+		q.Add("limit", strconv.Itoa(o.Limit))
+	}
+}
+
+func (c *Client) SearchRunLogs(ctx context.Context, runID, pattern string, opts *SearchRunLogsOptions) (*rsapitypes.RunLogsSearchResponse, *Response, error) {
+	q := url.Values{}
+	q.Add("pattern", pattern)
+	opts.Add(q)
+
+	res := new(rsapitypes.RunLogsSearchResponse)
+	resp, err := c.GetParsedResponse(ctx, "GET", fmt.Sprintf("/runs/%s/logs/search", runID), q, common.JSONContent, nil, res)
+	return res, resp, errors.WithStack(err)
+}
+
 func (c *Client) DeleteLogs(ctx context.Context, runID, taskID string, setup bool, step int) (*Response, error) {
 	q := url.Values{}
 	q.Add("runid", runID)
@@ -396,6 +468,37 @@ func (c *Client) GetRunEvents(ctx context.Context, afterSequence uint64) (*Respo
 	return resp, errors.WithStack(err)
 }
 
+// ReadRunEvents reads the run events from a run events stream and calls f for
+// every run event. It returns when the stream ends or f returns an error.
+func ReadRunEvents(r io.Reader, f func(ev *rstypes.RunEvent) error) error {
+	br := bufio.NewReader(r)
+
+	var buf bytes.Buffer
+	for {
+		line, err := br.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		switch {
+		case bytes.HasPrefix(line, []byte("data: ")):
+			buf.Write(line[6:])
+		case bytes.Equal(line, []byte("\n")):
+			var ev *rstypes.RunEvent
+			if err := json.Unmarshal(buf.Bytes(), &ev); err != nil {
+				return errors.WithStack(err)
+			}
+			buf.Reset()
+
+			if err := f(ev); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+}
+
 func (c *Client) GetMaintenanceStatus(ctx context.Context) (*rsapitypes.MaintenanceStatusResponse, *Response, error) {
 	maintenanceStatus := new(rsapitypes.MaintenanceStatusResponse)
 	resp, err := c.GetParsedResponse(ctx, "GET", "/maintenance", nil, common.JSONContent, nil, maintenanceStatus)
@@ -421,3 +524,34 @@ func (c *Client) Import(ctx context.Context, r io.Reader) (*Response, error) {
 	resp, err := c.GetResponse(ctx, "POST", "/import", nil, -1, nil, r)
 	return resp, errors.WithStack(err)
 }
+
+func (c *Client) GetBackups(ctx context.Context) ([]*rsapitypes.Backup, *Response, error) {
+	backups := []*rsapitypes.Backup{}
+	resp, err := c.GetParsedResponse(ctx, "GET", "/backups", nil, common.JSONContent, nil, &backups)
+	return backups, resp, errors.WithStack(err)
+}
+
+func (c *Client) RestoreBackup(ctx context.Context, backupID string) (*Response, error) {
+	resp, err := c.GetResponse(ctx, "POST", fmt.Sprintf("/backups/%s/restore", backupID), nil, -1, common.JSONContent, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) ScopedExport(ctx context.Context, groups []string) (*Response, error) {
+	q := url.Values{}
+	for _, group := range groups {
+		q.Add("group", group)
+	}
+
+	resp, err := c.GetResponse(ctx, "GET", "/scopedexport", q, -1, nil, nil)
+	return resp, errors.WithStack(err)
+}
+
+func (c *Client) ScopedImport(ctx context.Context, req *rsapitypes.ScopedImportRequest, r io.Reader) (*Response, error) {
+	reqj, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := c.GetResponse(ctx, "POST", "/scopedimport", nil, -1, nil, io.MultiReader(bytes.NewReader(reqj), r))
+	return resp, errors.WithStack(err)
+}
@@ -79,6 +79,12 @@ type ExecutorTaskSpecData struct {
 	Steps Steps `json:"steps,omitempty"`
 
 	TaskTimeoutInterval time.Duration `json:"task_timeout_interval"`
+
+	// SecretValues are the secret values that must be masked in the steps logs
+	SecretValues []string `json:"secret_values,omitempty"`
+
+	// TraceParent is the W3C traceparent of the run trace
+	TraceParent string `json:"trace_parent,omitempty"`
 }
 
 type ExecutorTaskStepStatus struct {
@@ -54,6 +54,12 @@ func RunResultFromStringSlice(slice []string) []RunResult {
 	return rss
 }
 
+const (
+	// RunAnnotationTraceParent is the run annotation containing the W3C
+	// traceparent of the run trace
+	RunAnnotationTraceParent = "agola.io/trace-parent"
+)
+
 // Run is the run status of a RUN. It should containt the status of the current
 // run. The run definition must live in the RunConfig and not here.
 type Run struct {
@@ -73,9 +73,14 @@ type RunConfigTask struct {
 	Steps                Steps                           `json:"steps,omitempty"`
 	IgnoreFailure        bool                            `json:"ignore_failure,omitempty"`
 	NeedsApproval        bool                            `json:"needs_approval,omitempty"`
+	ApprovalPolicy       *ApprovalPolicy                 `json:"approval_policy,omitempty"`
 	Skip                 bool                            `json:"skip,omitempty"`
 	DockerRegistriesAuth map[string]DockerRegistryAuth   `json:"docker_registries_auth"`
 	TaskTimeoutInterval  time.Duration                   `json:"task_timeout_interval"`
+
+	// SecretValues are the values derived from secrets used by the task. They
+	// will be masked in the task logs
+	SecretValues []string `json:"secret_values,omitempty"`
 }
 
 func (rct *RunConfigTask) DeepCopy() *RunConfigTask {
@@ -86,6 +91,35 @@ func (rct *RunConfigTask) DeepCopy() *RunConfigTask {
 	return nrct.(*RunConfigTask)
 }
 
+// ApprovalPolicy defines the requirements to approve a run config task that
+// needs approval
+type ApprovalPolicy struct {
+	MinApprovers       int           `json:"min_approvers,omitempty"`
+	AllowedUsers       []string      `json:"allowed_users,omitempty"`
+	AllowedTeams       []string      `json:"allowed_teams,omitempty"`
+	AllowedRoles       []string      `json:"allowed_roles,omitempty"`
+	ForbidSelfApproval bool          `json:"forbid_self_approval,omitempty"`
+	Expiry             time.Duration `json:"expiry,omitempty"`
+}
+
+// RequiredApprovers returns the number of approvals required to approve the
+// task. A nil policy requires one approval.
+func (p *ApprovalPolicy) RequiredApprovers() int {
+	if p == nil || p.MinApprovers < 1 {
+		return 1
+	}
+	return p.MinApprovers
+}
+
+// HasAllowedApprovers reports whether the policy limits the users that can
+// approve the task.
+func (p *ApprovalPolicy) HasAllowedApprovers() bool {
+	if p == nil {
+		return false
+	}
+	return len(p.AllowedUsers) > 0 || len(p.AllowedTeams) > 0 || len(p.AllowedRoles) > 0
+}
+
 type RunConfigTaskDependCondition string
 
 const (
@@ -13,7 +13,8 @@ import (
 type RunEventType string
 
 const (
-	RunPhaseChanged RunEventType = "run_phase_changed"
+	RunPhaseChanged      RunEventType = "run_phase_changed"
+	RunTaskStatusChanged RunEventType = "run_task_status_changed"
 
 	RunEventDataVersion = 1
 )
@@ -24,6 +24,20 @@ type When struct {
 	Branch *WhenConditions `json:"branch,omitempty"`
 	Tag    *WhenConditions `json:"tag,omitempty"`
 	Ref    *WhenConditions `json:"ref,omitempty"`
+
+	PullRequestLabel        *WhenConditions `json:"pull_request_label,omitempty"`
+	PullRequestTargetBranch *WhenConditions `json:"pull_request_target_branch,omitempty"`
+	// PullRequestDraft, when set, excludes the pull requests with a different
+	// draft state
+	PullRequestDraft *bool `json:"pull_request_draft,omitempty"`
+}
+
+// WhenPullRequest contains the pull request data used to match the when pull
+// request conditions
+type WhenPullRequest struct {
+	Labels       []string
+	TargetBranch string
+	Draft        bool
 }
 
 type WhenConditions struct {
@@ -43,10 +57,12 @@ type WhenCondition struct {
 	Match string            `json:"match,omitempty"`
 }
 
-func MatchWhen(when *When, refType itypes.RunRefType, branch, tag, ref string) bool {
+func MatchWhen(when *When, refType itypes.RunRefType, branch, tag, ref string, pullRequest *WhenPullRequest) bool {
 	include := true
 	if when != nil {
-		include = false
+		// a when with only the pull request draft condition matches everything
+		// but the pull requests with a different draft state
+		include = when.Branch == nil && when.Tag == nil && when.Ref == nil && when.PullRequestLabel == nil && when.PullRequestTargetBranch == nil && when.PullRequestDraft != nil
 		// test only if branch is not empty, if empty mean that we are not in a branch
 		if refType == itypes.RunRefTypeBranch && when.Branch != nil && branch != "" {
 			// first check includes and override with excludes
@@ -77,11 +93,46 @@ func MatchWhen(when *When, refType itypes.RunRefType, branch, tag, ref string) b
 				include = false
 			}
 		}
+		// test the pull request conditions only if we are in a pull request
+		if refType == itypes.RunRefTypePullRequest && pullRequest != nil {
+			if when.PullRequestLabel != nil {
+				// first check includes and override with excludes
+				if matchAnyCondition(when.PullRequestLabel.Include, pullRequest.Labels) {
+					include = true
+				}
+				if matchAnyCondition(when.PullRequestLabel.Exclude, pullRequest.Labels) {
+					include = false
+				}
+			}
+			if when.PullRequestTargetBranch != nil && pullRequest.TargetBranch != "" {
+				// first check includes and override with excludes
+				if matchCondition(when.PullRequestTargetBranch.Include, pullRequest.TargetBranch) {
+					include = true
+				}
+				if matchCondition(when.PullRequestTargetBranch.Exclude, pullRequest.TargetBranch) {
+					include = false
+				}
+			}
+			if when.PullRequestDraft != nil && *when.PullRequestDraft != pullRequest.Draft {
+				include = false
+			}
+		}
 	}
 
 	return include
 }
 
+// matchAnyCondition reports whether any of the provided strings matches the
+// conditions
+func matchAnyCondition(conds []WhenCondition, ss []string) bool {
+	for _, s := range ss {
+		if matchCondition(conds, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func matchCondition(conds []WhenCondition, s string) bool {
 	for _, cond := range conds {
 		switch cond.Type {
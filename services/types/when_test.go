@@ -20,6 +20,7 @@ import (
 	"gotest.tools/v3/assert"
 
 	itypes "agola.io/agola/internal/services/types"
+	"agola.io/agola/internal/util"
 )
 
 func TestMatchWhen(t *testing.T) {
@@ -30,6 +31,7 @@ func TestMatchWhen(t *testing.T) {
 		branch  string
 		tag     string
 		ref     string
+		pr      *WhenPullRequest
 		out     bool
 	}{
 		{
@@ -315,11 +317,149 @@ func TestMatchWhen(t *testing.T) {
 			tag: "master",
 			out: false,
 		},
+		{
+			name: "test pull request label include",
+			when: &When{
+				PullRequestLabel: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeSimple, Match: "run-e2e"},
+					},
+				},
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{Labels: []string{"bug", "run-e2e"}},
+			out:     true,
+		},
+		{
+			name: "test pull request label include without matching labels",
+			when: &When{
+				PullRequestLabel: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeSimple, Match: "run-e2e"},
+					},
+				},
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{Labels: []string{"bug"}},
+			out:     false,
+		},
+		{
+			name: "test pull request label exclude",
+			when: &When{
+				Ref: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeRegExp, Match: `refs/pull/\d+/head`},
+					},
+				},
+				PullRequestLabel: &WhenConditions{
+					Exclude: []WhenCondition{
+						{Type: WhenConditionTypeRegExp, Match: "skip-.*"},
+					},
+				},
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{Labels: []string{"bug", "skip-ci"}},
+			out:     false,
+		},
+		{
+			name: "test pull request label on branch reftype",
+			when: &When{
+				PullRequestLabel: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeSimple, Match: "run-e2e"},
+					},
+				},
+			},
+			refType: itypes.RunRefTypeBranch,
+			branch:  "master",
+			ref:     "refs/heads/master",
+			pr:      &WhenPullRequest{Labels: []string{"run-e2e"}},
+			out:     false,
+		},
+		{
+			name: "test pull request target branch include",
+			when: &When{
+				PullRequestTargetBranch: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeSimple, Match: "master"},
+					},
+				},
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{TargetBranch: "master"},
+			out:     true,
+		},
+		{
+			name: "test pull request target branch exclude",
+			when: &When{
+				PullRequestTargetBranch: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeRegExp, Match: ".*"},
+					},
+					Exclude: []WhenCondition{
+						{Type: WhenConditionTypeSimple, Match: "release"},
+					},
+				},
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{TargetBranch: "release"},
+			out:     false,
+		},
+		{
+			name: "test only pull request draft condition on draft pull request",
+			when: &When{
+				PullRequestDraft: util.Ptr(false),
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{Draft: true},
+			out:     false,
+		},
+		{
+			name: "test only pull request draft condition on non draft pull request",
+			when: &When{
+				PullRequestDraft: util.Ptr(false),
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{},
+			out:     true,
+		},
+		{
+			name: "test only pull request draft condition on branch",
+			when: &When{
+				PullRequestDraft: util.Ptr(false),
+			},
+			refType: itypes.RunRefTypeBranch,
+			branch:  "master",
+			ref:     "refs/heads/master",
+			out:     true,
+		},
+		{
+			name: "test pull request draft condition with ref include",
+			when: &When{
+				Ref: &WhenConditions{
+					Include: []WhenCondition{
+						{Type: WhenConditionTypeRegExp, Match: `refs/pull/\d+/head`},
+					},
+				},
+				PullRequestDraft: util.Ptr(false),
+			},
+			refType: itypes.RunRefTypePullRequest,
+			ref:     "refs/pull/1/head",
+			pr:      &WhenPullRequest{Draft: true},
+			out:     false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			out := MatchWhen(tt.when, tt.refType, tt.branch, tt.tag, tt.ref)
+			out := MatchWhen(tt.when, tt.refType, tt.branch, tt.tag, tt.ref, tt.pr)
 			assert.Equal(t, out, tt.out)
 		})
 	}
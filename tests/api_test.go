@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
 	"slices"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
 
+	rcconfig "agola.io/agola/internal/config"
 	"agola.io/agola/internal/services/config"
 	"agola.io/agola/internal/testutil"
 	"agola.io/agola/internal/util"
@@ -1163,6 +1166,98 @@ func TestUserOrgs(t *testing.T) {
 	assert.DeepEqual(t, expectedOrgs, orgs)
 }
 
+func TestAuditEvents(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	gwAdminClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, sc.config.Gateway.AdminToken)
+
+	//create user01 and user02
+	user01, _, err := gwAdminClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser01})
+	testutil.NilError(t, err)
+
+	tokenUser01, _, err := gwAdminClient.CreateUserToken(ctx, agolaUser01, &gwapitypes.CreateUserTokenRequest{TokenName: "test"})
+	testutil.NilError(t, err)
+
+	gwClientUser01 := gwclient.NewClient(sc.config.Gateway.APIExposedURL, tokenUser01.Token)
+
+	user02, _, err := gwAdminClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser02})
+	testutil.NilError(t, err)
+
+	tokenUser02, _, err := gwAdminClient.CreateUserToken(ctx, agolaUser02, &gwapitypes.CreateUserTokenRequest{TokenName: "test"})
+	testutil.NilError(t, err)
+
+	gwClientUser02 := gwclient.NewClient(sc.config.Gateway.APIExposedURL, tokenUser02.Token)
+
+	//user01 creates the org and adds user02 as member
+	org, _, err := gwClientUser01.CreateOrg(ctx, &gwapitypes.CreateOrgRequest{Name: agolaOrg01, Visibility: gwapitypes.VisibilityPublic})
+	testutil.NilError(t, err)
+
+	_, _, err = gwClientUser01.AddOrgMember(ctx, agolaOrg01, agolaUser02, gwapitypes.MemberRoleMember)
+	testutil.NilError(t, err)
+
+	t.Run("test org owner get org audit events", func(t *testing.T) {
+		auditEvents, _, err := gwClientUser01.GetOrgAuditEvents(ctx, agolaOrg01, nil)
+		testutil.NilError(t, err)
+
+		assert.Assert(t, cmp.Len(auditEvents, 2))
+
+		assert.Equal(t, auditEvents[0].Action, "org.create")
+		assert.Equal(t, auditEvents[0].ActorUserID, user01.ID)
+		assert.Equal(t, auditEvents[0].ActorUserName, agolaUser01)
+		assert.Equal(t, auditEvents[0].TargetKind, "org")
+		assert.Equal(t, auditEvents[0].TargetID, org.ID)
+		assert.Equal(t, auditEvents[0].OrganizationID, org.ID)
+
+		assert.Equal(t, auditEvents[1].Action, "org.member.add")
+		assert.Equal(t, auditEvents[1].TargetKind, "user")
+		assert.Equal(t, auditEvents[1].TargetID, user02.ID)
+		assert.DeepEqual(t, auditEvents[1].Details, map[string]string{"role": string(gwapitypes.MemberRoleMember)})
+	})
+
+	t.Run("test org owner get org audit events filtered by action", func(t *testing.T) {
+		auditEvents, _, err := gwClientUser01.GetOrgAuditEvents(ctx, agolaOrg01, &gwclient.AuditEventsOptions{Actions: []string{"org.member.add"}})
+		testutil.NilError(t, err)
+
+		assert.Assert(t, cmp.Len(auditEvents, 1))
+		assert.Equal(t, auditEvents[0].Action, "org.member.add")
+	})
+
+	t.Run("test org member get org audit events", func(t *testing.T) {
+		_, _, err := gwClientUser02.GetOrgAuditEvents(ctx, agolaOrg01, nil)
+		expectedErr := remoteErrorForbidden
+		assert.Error(t, err, expectedErr.Error())
+	})
+
+	t.Run("test user get all audit events", func(t *testing.T) {
+		_, _, err := gwClientUser01.GetAuditEvents(ctx, nil)
+		expectedErr := remoteErrorForbidden
+		assert.Error(t, err, expectedErr.Error())
+	})
+
+	t.Run("test admin get all audit events", func(t *testing.T) {
+		auditEvents, _, err := gwAdminClient.GetAuditEvents(ctx, &gwclient.AuditEventsOptions{ActorUserRef: agolaUser01})
+		testutil.NilError(t, err)
+
+		assert.Assert(t, cmp.Len(auditEvents, 2))
+
+		auditEvents, _, err = gwAdminClient.GetAuditEvents(ctx, &gwclient.AuditEventsOptions{Actions: []string{"user.create"}})
+		testutil.NilError(t, err)
+
+		assert.Assert(t, cmp.Len(auditEvents, 2))
+		assert.Equal(t, auditEvents[0].ActorUserName, "admin")
+		assert.Equal(t, auditEvents[0].TargetID, user01.ID)
+		assert.Equal(t, auditEvents[1].TargetID, user02.ID)
+	})
+}
+
 func TestGetUsersPermissions(t *testing.T) {
 	t.Parallel()
 
@@ -1784,6 +1879,81 @@ func getProjectRef(project *gwapitypes.ProjectResponse, refType RefType) (string
 	}
 }
 
+func TestGetProjectReceivedWebhooks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	giteaToken, tokenUser01 := createLinkedAccount(ctx, t, sc.gitea, sc.config)
+	gwUser01Client := gwclient.NewClient(sc.config.Gateway.APIExposedURL, tokenUser01)
+	gwUserAdminClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, sc.config.Gateway.AdminToken)
+
+	_, _, err := gwUserAdminClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser02})
+	testutil.NilError(t, err)
+
+	user02Token, _, err := gwUserAdminClient.CreateUserToken(ctx, agolaUser02, &gwapitypes.CreateUserTokenRequest{TokenName: "token01"})
+	testutil.NilError(t, err)
+
+	gwUser02Client := gwclient.NewClient(sc.config.Gateway.APIExposedURL, user02Token.Token)
+
+	giteaAPIURL := fmt.Sprintf("http://%s:%s", sc.gitea.HTTPListenAddress, sc.gitea.HTTPPort)
+
+	giteaClient, err := gitea.NewClient(giteaAPIURL, gitea.SetToken(giteaToken))
+	testutil.NilError(t, err)
+
+	giteaRepo, project := createProject(ctx, t, giteaClient, gwUser01Client, withVisibility(gwapitypes.VisibilityPrivate))
+
+	push(t, EnvRunConfig, giteaRepo.CloneURL, giteaToken, "commit", false)
+	push(t, EnvRunConfig, giteaRepo.CloneURL, giteaToken, "commit [ci skip]", false)
+
+	var receivedWebhooks []*gwapitypes.ReceivedWebhookResponse
+	err = testutil.Wait(60*time.Second, func() (bool, error) {
+		receivedWebhooks, _, err = gwUser01Client.GetProjectReceivedWebhooks(ctx, project.ID, &gwclient.ReceivedWebhooksOptions{ListOptions: &gwclient.ListOptions{SortDirection: gwapitypes.SortDirectionAsc}, StatusFilter: []string{string(gwapitypes.ReceivedWebhookStatusProcessed)}})
+		if err != nil {
+			return false, nil
+		}
+
+		return len(receivedWebhooks) == 2, nil
+	})
+	testutil.NilError(t, err)
+
+	assert.Equal(t, receivedWebhooks[0].Event, "push")
+	assert.Equal(t, receivedWebhooks[0].Outcome, gwapitypes.ReceivedWebhookOutcomeRunsCreated)
+	assert.DeepEqual(t, receivedWebhooks[0].RunNumbers, []uint64{1})
+	assert.Equal(t, receivedWebhooks[1].Outcome, gwapitypes.ReceivedWebhookOutcomeSkipped)
+	assert.Equal(t, receivedWebhooks[1].OutcomeMessage, "skip commit message")
+
+	t.Run("request by a user without maintainer role", func(t *testing.T) {
+		_, _, err := gwUser02Client.GetProjectReceivedWebhooks(ctx, project.ID, nil)
+		assert.Error(t, err, remoteErrorForbidden.Error())
+	})
+
+	t.Run("unsigned webhook is rejected and not saved", func(t *testing.T) {
+		u := fmt.Sprintf("%s/webhooks?projectid=%s", sc.config.Gateway.APIExposedURL, url.QueryEscape(project.ID))
+		req, err := http.NewRequestWithContext(ctx, "POST", u, strings.NewReader(`{"ref": "refs/heads/master"}`))
+		testutil.NilError(t, err)
+		req.Header.Set("X-Gitea-Event", "push")
+		req.Header.Set("X-Gitea-Delivery", "unsigned01")
+
+		resp, err := http.DefaultClient.Do(req)
+		testutil.NilError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, resp.StatusCode, http.StatusUnauthorized)
+
+		receivedWebhooks, _, err := gwUser01Client.GetProjectReceivedWebhooks(ctx, project.ID, nil)
+		testutil.NilError(t, err)
+
+		assert.Equal(t, len(receivedWebhooks), 2)
+	})
+}
+
 func TestProjectRunWebhookRedelivery(t *testing.T) {
 	t.Parallel()
 
@@ -3585,3 +3755,26 @@ func testGetGroupRuns(t *testing.T, userRun bool) {
 		})
 	}
 }
+
+func TestRunConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir)
+	defer sc.stop()
+
+	resp, err := http.Get(sc.config.Gateway.APIExposedURL + "/api/v1alpha/schemas/runconfig.json")
+	testutil.NilError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, resp.Header.Get("Content-Type"), "application/schema+json")
+
+	data, err := io.ReadAll(resp.Body)
+	testutil.NilError(t, err)
+	assert.Equal(t, string(data), string(rcconfig.Schema()))
+}
@@ -11,8 +11,11 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"os/exec"
+	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -518,7 +521,7 @@ variable02: variable value 02
 				}
 			}
 
-			resp, err := gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, false, 1, false)
+			resp, err := gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, false, 1, false, nil)
 			testutil.NilError(t, err)
 
 			defer resp.Body.Close()
@@ -686,7 +689,7 @@ func TestDirectRunLogs(t *testing.T) {
 			if tt.delete {
 				_, err = gwClient.DeleteUserLogs(ctx, user.ID, run.Number, task.ID, tt.setup, tt.step)
 			} else {
-				_, err = gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, tt.setup, tt.step, false)
+				_, err = gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, tt.setup, tt.step, false, nil)
 			}
 
 			if tt.err != "" {
@@ -698,6 +701,481 @@ func TestDirectRunLogs(t *testing.T) {
 	}
 }
 
+func TestDirectRunLogsFormat(t *testing.T) {
+	t.Parallel()
+
+	config := `
+	{
+		runs: [
+			{
+				name: 'run01',
+				tasks: [
+					{
+						name: 'task01',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						steps: [
+							{ type: 'clone' },
+							{ type: 'run', command: 'echo OUT01; echo ERR01 >&2; echo OUT02', tty: false },
+						],
+					},
+				],
+			},
+		],
+	}
+	`
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, "admintoken")
+	user, _, err := gwClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser01})
+	testutil.NilError(t, err)
+
+	t.Logf("created agola user: %s", user.UserName)
+
+	token := createAgolaUserToken(ctx, t, sc.config)
+
+	// From now use the user token
+	gwClient = gwclient.NewClient(sc.config.Gateway.APIExposedURL, token)
+
+	directRun(t, dir, config, ConfigFormatJsonnet, sc.config.Gateway.APIExposedURL, token)
+
+	var run *gwapitypes.RunResponse
+	var task *gwapitypes.RunTaskResponse
+	err = testutil.Wait(60*time.Second, func() (bool, error) {
+		runs, _, err := gwClient.GetUserRuns(ctx, user.ID, &gwclient.GetRunsOptions{ListOptions: &gwclient.ListOptions{SortDirection: gwapitypes.SortDirectionDesc}})
+		if err != nil || len(runs) != 1 || runs[0].Phase != rstypes.RunPhaseFinished {
+			return false, nil
+		}
+
+		run, _, err = gwClient.GetUserRun(ctx, user.ID, runs[0].Number)
+		if err != nil {
+			return false, nil
+		}
+		for _, rt := range run.Tasks {
+			if rt.Name == "task01" {
+				task, _, err = gwClient.GetUserRunTask(ctx, user.ID, run.Number, rt.ID)
+				if err != nil {
+					return false, nil
+				}
+			}
+		}
+
+		return task != nil && task.Steps[1].LogArchived, nil
+	})
+	testutil.NilError(t, err)
+
+	assert.Equal(t, run.Result, rstypes.RunResultSuccess)
+
+	resp, err := gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, false, 1, false, &gwclient.GetLogsOptions{Format: "ndjson"})
+	testutil.NilError(t, err)
+	defer resp.Body.Close()
+
+	lines := []*gwapitypes.LogLineResponse{}
+	err = gwclient.ReadLogLines(resp.Body, func(l *gwapitypes.LogLineResponse) error {
+		lines = append(lines, l)
+		return nil
+	})
+	testutil.NilError(t, err)
+
+	streams := map[string][]string{}
+	for i, l := range lines {
+		assert.Equal(t, l.Number, uint64(i))
+		assert.Assert(t, l.Time != nil)
+		streams[l.Stream] = append(streams[l.Stream], l.Text)
+	}
+	assert.DeepEqual(t, streams, map[string][]string{"stdout": {"OUT01", "OUT02"}, "stderr": {"ERR01"}})
+
+	// the default text format returns the raw log
+	resp, err = gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, false, 1, false, &gwclient.GetLogsOptions{Start: 1, Limit: 1})
+	testutil.NilError(t, err)
+	defer resp.Body.Close()
+
+	logs, err := io.ReadAll(resp.Body)
+	testutil.NilError(t, err)
+
+	assert.Equal(t, string(logs), lines[1].Text+"\n")
+}
+
+func TestRunLogsSearch(t *testing.T) {
+	t.Parallel()
+
+	config := `
+	{
+		runs: [
+			{
+				name: 'run01',
+				tasks: [
+					{
+						name: 'task01',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						steps: [
+							{ type: 'clone' },
+							{ type: 'run', command: 'echo test01; echo FAIL: test02; echo test03', tty: false },
+						],
+					},
+					{
+						name: 'task02',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						steps: [
+							{ type: 'clone' },
+							{ type: 'run', command: 'echo ok', tty: false },
+						],
+					},
+				],
+			},
+		],
+	}
+	`
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, "admintoken")
+	user, _, err := gwClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser01})
+	testutil.NilError(t, err)
+
+	t.Logf("created agola user: %s", user.UserName)
+
+	token := createAgolaUserToken(ctx, t, sc.config)
+
+	// From now use the user token
+	gwClient = gwclient.NewClient(sc.config.Gateway.APIExposedURL, token)
+
+	directRun(t, dir, config, ConfigFormatJsonnet, sc.config.Gateway.APIExposedURL, token)
+
+	var run *gwapitypes.RunResponse
+	err = testutil.Wait(60*time.Second, func() (bool, error) {
+		runs, _, err := gwClient.GetUserRuns(ctx, user.ID, &gwclient.GetRunsOptions{ListOptions: &gwclient.ListOptions{SortDirection: gwapitypes.SortDirectionDesc}})
+		if err != nil || len(runs) != 1 || runs[0].Phase != rstypes.RunPhaseFinished {
+			return false, nil
+		}
+
+		run, _, err = gwClient.GetUserRun(ctx, user.ID, runs[0].Number)
+		if err != nil {
+			return false, nil
+		}
+		for _, rt := range run.Tasks {
+			task, _, err := gwClient.GetUserRunTask(ctx, user.ID, run.Number, rt.ID)
+			if err != nil {
+				return false, nil
+			}
+			for _, s := range task.Steps {
+				if !s.LogArchived {
+					return false, nil
+				}
+			}
+		}
+
+		return true, nil
+	})
+	testutil.NilError(t, err)
+
+	res, _, err := gwClient.SearchUserRunLogs(ctx, user.ID, run.Number, "fail", &gwclient.SearchRunLogsOptions{IgnoreCase: true, Context: 1})
+	testutil.NilError(t, err)
+
+	assert.Assert(t, cmp.Len(res.Matches, 1))
+	m := res.Matches[0]
+	assert.Equal(t, m.TaskName, "task01")
+	assert.Equal(t, m.Step, 1)
+	assert.Equal(t, m.Line.Number, uint64(1))
+	assert.Equal(t, m.Line.Text, "FAIL: test02")
+	assert.Equal(t, m.Before[0].Text, "test01")
+	assert.Equal(t, m.After[0].Text, "test03")
+	assert.Equal(t, res.Truncated, false)
+
+	res, _, err = gwClient.SearchUserRunLogs(ctx, user.ID, run.Number, "^test0[13]$", &gwclient.SearchRunLogsOptions{Regex: true, Limit: 1})
+	testutil.NilError(t, err)
+
+	assert.Assert(t, cmp.Len(res.Matches, 1))
+	assert.Equal(t, res.Matches[0].Line.Text, "test01")
+	assert.Equal(t, res.Truncated, true)
+
+	out, err := execAgola(t, sc.config.Gateway.APIExposedURL, token, "run", "logs", "grep", "--username", agolaUser01, "--runnumber", strconv.FormatUint(run.Number, 10), "FAIL")
+	testutil.NilError(t, err)
+
+	assert.Equal(t, string(out), "task01/1:1:FAIL: test02\n")
+}
+
+func TestDirectRunFollow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		command  string
+		exitCode int
+		out      string
+	}{
+		{
+			name:    "test direct run follow with successful run",
+			command: "echo STEPLOG",
+			out:     "[task01] STEPLOG",
+		},
+		{
+			name:     "test direct run follow with failed run",
+			command:  "echo STEPLOG; exit 1",
+			exitCode: 1,
+			out:      "[task01] STEPLOG",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := fmt.Sprintf(`
+			{
+				runs: [
+					{
+						name: 'run01',
+						tasks: [
+							{
+								name: 'task01',
+								runtime: {
+									containers: [
+										{
+											image: 'alpine/git',
+										},
+									],
+								},
+								steps: [
+									{ type: 'run', command: '%s' },
+								],
+							},
+						],
+					},
+				],
+			}
+			`, tt.command)
+
+			dir := t.TempDir()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sc := setup(ctx, t, dir, withGitea(true))
+			defer sc.stop()
+
+			gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, "admintoken")
+			user, _, err := gwClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser01})
+			testutil.NilError(t, err)
+
+			t.Logf("created agola user: %s", user.UserName)
+
+			token := createAgolaUserToken(ctx, t, sc.config)
+
+			out, err := execDirectRun(t, dir, config, ConfigFormatJsonnet, sc.config.Gateway.APIExposedURL, token, "--follow")
+			if tt.exitCode == 0 {
+				testutil.NilError(t, err, "out: %s", out)
+			} else {
+				var exitErr *exec.ExitError
+				assert.Assert(t, errors.As(err, &exitErr), "out: %s", out)
+				assert.Equal(t, exitErr.ExitCode(), tt.exitCode)
+			}
+
+			assert.Assert(t, cmp.Contains(string(out), tt.out))
+		})
+	}
+}
+
+func TestRunCommands(t *testing.T) {
+	t.Parallel()
+
+	config := `
+	{
+		runs: [
+			{
+				name: 'run01',
+				tasks: [
+					{
+						name: 'task01',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						steps: [
+							{ type: 'run', command: 'exit 1' },
+						],
+					},
+				],
+			},
+		],
+	}
+	`
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, "admintoken")
+	user, _, err := gwClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser01})
+	testutil.NilError(t, err)
+
+	t.Logf("created agola user: %s", user.UserName)
+
+	token := createAgolaUserToken(ctx, t, sc.config)
+	gatewayURL := sc.config.Gateway.APIExposedURL
+
+	out, err := execDirectRun(t, dir, config, ConfigFormatJsonnet, gatewayURL, token, "--wait")
+	var exitErr *exec.ExitError
+	assert.Assert(t, errors.As(err, &exitErr), "out: %s", out)
+
+	out, err = execAgola(t, gatewayURL, token, "run", "get", "--username", agolaUser01, "--runnumber", "1", "--format", "json")
+	testutil.NilError(t, err, "out: %s", out)
+
+	var run *gwapitypes.RunResponse
+	testutil.NilError(t, json.Unmarshal(out, &run))
+	assert.Equal(t, run.Number, uint64(1))
+	assert.Equal(t, run.Phase, rstypes.RunPhaseFinished)
+	assert.Equal(t, run.Result, rstypes.RunResultFailed)
+	assert.Assert(t, run.CanRestartFromFailedTasks)
+	assert.Equal(t, len(run.Tasks), 1)
+	for _, task := range run.Tasks {
+		assert.Equal(t, task.Status, rstypes.RunTaskStatusFailed)
+	}
+
+	out, err = execAgola(t, gatewayURL, token, "run", "restart", "--username", agolaUser01, "--runnumber", "1", "--from-failed", "--format", "json")
+	testutil.NilError(t, err, "out: %s", out)
+
+	testutil.NilError(t, json.Unmarshal(out, &run))
+	assert.Equal(t, run.Number, uint64(2))
+
+	out, err = execAgola(t, gatewayURL, token, "run", "watch", "--username", agolaUser01, "--runnumber", "2", "--format", "json", "--interval", "1s")
+	testutil.NilError(t, err, "out: %s", out)
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	testutil.NilError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &run))
+	assert.Equal(t, run.Number, uint64(2))
+	assert.Equal(t, run.Phase, rstypes.RunPhaseFinished)
+	assert.Equal(t, run.Result, rstypes.RunResultFailed)
+}
+
+func TestRunEventsStream(t *testing.T) {
+	t.Parallel()
+
+	config := `
+	{
+		runs: [
+			{
+				name: 'run01',
+				tasks: [
+					{
+						name: 'task01',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						steps: [
+							{ type: 'run', command: 'echo run01' },
+						],
+					},
+				],
+			},
+		],
+	}
+	`
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	gwAdminClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, "admintoken")
+	user01, _, err := gwAdminClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser01})
+	testutil.NilError(t, err)
+	_, _, err = gwAdminClient.CreateUser(ctx, &gwapitypes.CreateUserRequest{UserName: agolaUser02})
+	testutil.NilError(t, err)
+
+	token, _, err := gwAdminClient.CreateUserToken(ctx, agolaUser01, &gwapitypes.CreateUserTokenRequest{TokenName: "token01"})
+	testutil.NilError(t, err)
+	user02Token, _, err := gwAdminClient.CreateUserToken(ctx, agolaUser02, &gwapitypes.CreateUserTokenRequest{TokenName: "token01"})
+	testutil.NilError(t, err)
+
+	gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, token.Token)
+	gwUser02Client := gwclient.NewClient(sc.config.Gateway.APIExposedURL, user02Token.Token)
+
+	// user direct runs are private
+	_, err = gwUser02Client.GetRunEvents(ctx, &gwclient.GetRunEventsOptions{UserRefs: []string{agolaUser01}})
+	expectedErr := remoteErrorForbidden
+	assert.Error(t, err, expectedErr.Error())
+
+	resp, err := gwClient.GetRunEvents(ctx, &gwclient.GetRunEventsOptions{UserRefs: []string{agolaUser01}})
+	testutil.NilError(t, err)
+	defer resp.Body.Close()
+
+	directRun(t, dir, config, ConfigFormatJsonnet, sc.config.Gateway.APIExposedURL, token.Token)
+
+	var events []*gwapitypes.RunEventResponse
+	errFinished := errors.New("finished")
+	err = gwclient.ReadRunEvents(resp.Body, func(ev *gwapitypes.RunEventResponse) error {
+		events = append(events, ev)
+		if ev.Type == rstypes.RunPhaseChanged && ev.Run.Phase == rstypes.RunPhaseFinished {
+			return errFinished
+		}
+		return nil
+	})
+	assert.Assert(t, errors.Is(err, errFinished))
+
+	assert.Assert(t, len(events) > 1)
+	hasTaskEvent := false
+	for i, ev := range events {
+		assert.Equal(t, ev.UserID, user01.ID)
+		assert.Equal(t, ev.Run.Number, uint64(1))
+		if i > 0 {
+			assert.Assert(t, ev.Sequence > events[i-1].Sequence)
+		}
+		if ev.Type == rstypes.RunTaskStatusChanged {
+			hasTaskEvent = true
+		}
+	}
+	assert.Assert(t, hasTaskEvent)
+
+	lastEvent := events[len(events)-1]
+	assert.Equal(t, lastEvent.Run.Result, rstypes.RunResultSuccess)
+}
+
 func TestPullRequest(t *testing.T) {
 	t.Parallel()
 
@@ -953,7 +1431,7 @@ func TestPullRequest(t *testing.T) {
 				assert.Equal(t, run.Phase, rstypes.RunPhaseFinished)
 				assert.Equal(t, run.Result, rstypes.RunResultSuccess)
 
-				resp, err := gwClient.GetProjectLogs(ctx, project.ID, run.Number, task.ID, false, 1, false)
+				resp, err := gwClient.GetProjectLogs(ctx, project.ID, run.Number, task.ID, false, 1, false, nil)
 				testutil.NilError(t, err, "failed to get log")
 				defer resp.Body.Close()
 
@@ -965,6 +1443,132 @@ func TestPullRequest(t *testing.T) {
 	}
 }
 
+func TestPullRequestClose(t *testing.T) {
+	t.Parallel()
+
+	config := `
+	{
+		runs: [
+			{
+				name: 'run01',
+				tasks: [
+					{
+						name: 'task01',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						approval: true,
+						steps: [
+							{ type: 'run', command: 'echo run01' },
+						],
+					},
+				],
+				when: {
+					ref: '#refs/pull/\\d+/head#',
+				},
+			},
+		],
+	}
+	`
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	giteaAPIURL := fmt.Sprintf("http://%s:%s", sc.gitea.HTTPListenAddress, sc.gitea.HTTPPort)
+
+	giteaToken, token := createLinkedAccount(ctx, t, sc.gitea, sc.config)
+
+	giteaClient, err := gitea.NewClient(giteaAPIURL, gitea.SetToken(giteaToken))
+	testutil.NilError(t, err)
+
+	gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, token)
+
+	giteaRepo, project := createProject(ctx, t, giteaClient, gwClient)
+
+	push(t, config, giteaRepo.CloneURL, giteaToken, "commit", true)
+
+	prOpts := gitea.CreatePullRequestOption{
+		Head:  "new-branch",
+		Base:  "master",
+		Title: "add file1 from new-branch on same repo",
+	}
+
+	var pr *gitea.PullRequest
+	err = testutil.Wait(10*time.Second, func() (bool, error) {
+		var resp *gitea.Response
+		pr, resp, err = giteaClient.CreatePullRequest(giteaUser01, "repo01", prOpts)
+		if err != nil {
+			if resp.StatusCode == http.StatusNotFound {
+				return false, nil
+			}
+			return false, errors.WithStack(err)
+		}
+
+		return true, nil
+	})
+	testutil.NilError(t, err, "failed to create pull request")
+
+	// wait for the run task waiting approval
+	err = testutil.Wait(60*time.Second, func() (bool, error) {
+		runs, _, err := gwClient.GetProjectRuns(ctx, project.ID, nil)
+		if err != nil {
+			return false, nil
+		}
+		if len(runs) == 0 {
+			return false, nil
+		}
+		run, _, err := gwClient.GetProjectRun(ctx, project.ID, runs[0].Number)
+		if err != nil {
+			return false, nil
+		}
+
+		return len(run.TasksWaitingApproval) == 1, nil
+	})
+	testutil.NilError(t, err)
+
+	_, _, err = giteaClient.EditPullRequest(giteaUser01, "repo01", pr.Index, gitea.EditPullRequestOption{State: util.Ptr(gitea.StateClosed)})
+	testutil.NilError(t, err, "failed to close pull request")
+
+	err = testutil.Wait(60*time.Second, func() (bool, error) {
+		runs, _, err := gwClient.GetProjectRuns(ctx, project.ID, nil)
+		if err != nil {
+			return false, nil
+		}
+
+		return runs[0].Phase == rstypes.RunPhaseFinished, nil
+	})
+	testutil.NilError(t, err)
+
+	runs, _, err := gwClient.GetProjectRuns(ctx, project.ID, nil)
+	testutil.NilError(t, err)
+
+	assert.Assert(t, cmp.Len(runs, 1))
+	assert.Equal(t, runs[0].Result, rstypes.RunResultStopped)
+
+	var receivedWebhooks []*gwapitypes.ReceivedWebhookResponse
+	err = testutil.Wait(30*time.Second, func() (bool, error) {
+		receivedWebhooks, _, err = gwClient.GetProjectReceivedWebhooks(ctx, project.ID, &gwclient.ReceivedWebhooksOptions{ListOptions: &gwclient.ListOptions{SortDirection: gwapitypes.SortDirectionDesc}})
+		if err != nil {
+			return false, nil
+		}
+
+		return receivedWebhooks[0].Status == gwapitypes.ReceivedWebhookStatusProcessed, nil
+	})
+	testutil.NilError(t, err)
+
+	assert.Equal(t, receivedWebhooks[0].Outcome, gwapitypes.ReceivedWebhookOutcomeRunsStopped)
+	assert.DeepEqual(t, receivedWebhooks[0].RunNumbers, []uint64{runs[0].Number})
+}
+
 func TestTaskTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -1339,7 +1943,7 @@ func TestRunRequiredEnvVariables(t *testing.T) {
 				}
 			}
 
-			resp, err := gwClient.GetProjectLogs(ctx, project.ID, run.Number, task.ID, false, 0, false)
+			resp, err := gwClient.GetProjectLogs(ctx, project.ID, run.Number, task.ID, false, 0, false, nil)
 			testutil.NilError(t, err)
 
 			defer resp.Body.Close()
@@ -1547,7 +2151,7 @@ def main(ctx):
 					}
 				}
 
-				resp, err := gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, false, 1, false)
+				resp, err := gwClient.GetUserLogs(ctx, user.ID, run.Number, task.ID, false, 1, false, nil)
 				testutil.NilError(t, err)
 
 				defer resp.Body.Close()
@@ -1721,3 +2325,110 @@ func TestRunEventsNotification(t *testing.T) {
 		})
 	}
 }
+
+func TestMirrorProject(t *testing.T) {
+	t.Parallel()
+
+	config := `
+	{
+		runs: [
+			{
+				name: 'run01',
+				tasks: [
+					{
+						name: 'task01',
+						runtime: {
+							containers: [
+								{
+									image: 'alpine/git',
+								},
+							],
+						},
+						steps: [
+							{ type: 'clone' },
+							{ type: 'run', command: 'env' },
+						],
+					},
+				],
+			},
+		],
+	}
+	`
+
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := setup(ctx, t, dir, withGitea(true))
+	defer sc.stop()
+
+	giteaAPIURL := fmt.Sprintf("http://%s:%s", sc.gitea.HTTPListenAddress, sc.gitea.HTTPPort)
+
+	giteaToken, token := createLinkedAccount(ctx, t, sc.gitea, sc.config)
+
+	giteaClient, err := gitea.NewClient(giteaAPIURL, gitea.SetToken(giteaToken))
+	testutil.NilError(t, err)
+
+	gwClient := gwclient.NewClient(sc.config.Gateway.APIExposedURL, token)
+
+	giteaRepo, _, err := giteaClient.CreateRepo(gitea.CreateRepoOption{
+		Name:    "repo01",
+		Private: false,
+	})
+	testutil.NilError(t, err)
+
+	push(t, config, giteaRepo.CloneURL, giteaToken, "commit", false)
+
+	project, _, err := gwClient.CreateProject(ctx, &gwapitypes.CreateProjectRequest{
+		Name:       "project01",
+		ParentRef:  path.Join("user", agolaUser01),
+		MirrorURL:  giteaRepo.CloneURL,
+		Visibility: gwapitypes.VisibilityPublic,
+	})
+	testutil.NilError(t, err)
+
+	// wait for the first poll that records the current refs without creating runs
+	err = testutil.Wait(30*time.Second, func() (bool, error) {
+		projectMirror, _, err := gwClient.GetProjectMirror(ctx, project.ID)
+		if err != nil {
+			return false, nil
+		}
+
+		return projectMirror.LastPollTime != nil, nil
+	})
+	testutil.NilError(t, err)
+
+	projectMirror, _, err := gwClient.GetProjectMirror(ctx, project.ID)
+	testutil.NilError(t, err)
+
+	assert.Equal(t, projectMirror.LastPollError, "")
+	assert.Assert(t, projectMirror.Refs["refs/heads/master"] != "")
+
+	runs, _, err := gwClient.GetProjectRuns(ctx, project.ID, nil)
+	testutil.NilError(t, err)
+	assert.Assert(t, cmp.Len(runs, 0))
+
+	push(t, config, giteaRepo.CloneURL, giteaToken, "commit02", false)
+
+	err = testutil.Wait(60*time.Second, func() (bool, error) {
+		runs, _, err := gwClient.GetProjectRuns(ctx, project.ID, nil)
+		if err != nil {
+			return false, nil
+		}
+		if len(runs) != 1 {
+			return false, nil
+		}
+
+		return runs[0].Phase == rstypes.RunPhaseFinished, nil
+	})
+	testutil.NilError(t, err)
+
+	runs, _, err = gwClient.GetProjectRuns(ctx, project.ID, nil)
+	testutil.NilError(t, err)
+
+	assert.Assert(t, cmp.Len(runs, 1))
+	assert.Equal(t, runs[0].Result, rstypes.RunResultSuccess)
+	assert.Equal(t, runs[0].Annotations["ref"], "refs/heads/master")
+	assert.Equal(t, runs[0].Annotations["run_creation_trigger"], "poll")
+}
@@ -362,6 +362,7 @@ func setup(ctx context.Context, t *testing.T, dir string, opts ...setupOption) *
 			},
 			AdminToken:                   "admintoken",
 			OrganizationMemberAddingMode: config.OrganizationMemberAddingModeDirect,
+			MirrorPollInterval:           2 * time.Second,
 		},
 		Scheduler: config.Scheduler{
 			Debug:         false,
@@ -433,6 +434,7 @@ func setup(ctx context.Context, t *testing.T, dir string, opts ...setupOption) *
 				Type: "posix",
 				Path: filepath.Join(dir, "configstore", "ost"),
 			},
+			ReceivedWebhookExpireInterval: 7 * 24 * time.Hour,
 		},
 		Gitserver: config.Gitserver{
 			Debug:   false,
@@ -790,6 +792,15 @@ func push(t *testing.T, config, cloneURL, remoteToken, message string, pushNewBr
 }
 
 func directRun(t *testing.T, dir, config string, configFormat ConfigFormat, gatewayURL, token string, args ...string) {
+	out, err := execDirectRun(t, dir, config, configFormat, gatewayURL, token, args...)
+	testutil.NilError(t, err, "out: %s", out)
+
+	t.Logf("directrun start out: %s", out)
+}
+
+// execDirectRun executes "agola directrun start" and returns its combined output
+// and error.
+func execDirectRun(t *testing.T, dir, config string, configFormat ConfigFormat, gatewayURL, token string, args ...string) ([]byte, error) {
 	agolaBinDir := os.Getenv("AGOLA_BIN_DIR")
 	assert.Assert(t, agolaBinDir != "", "env var AGOLA_BIN_DIR is undefined")
 
@@ -839,7 +850,22 @@ func directRun(t *testing.T, dir, config string, configFormat ConfigFormat, gate
 	cmd.Dir = repoDir
 	cmd.Env = env
 	out, err := cmd.CombinedOutput()
-	testutil.NilError(t, err, "out: %s", out)
 
-	t.Logf("directrun start out: %s", out)
+	return out, errors.WithStack(err)
+}
+
+// execAgola executes the agola command with the provided args and returns its
+// stdout.
+func execAgola(t *testing.T, gatewayURL, token string, args ...string) ([]byte, error) {
+	agolaBinDir := os.Getenv("AGOLA_BIN_DIR")
+	assert.Assert(t, agolaBinDir != "", "env var AGOLA_BIN_DIR is undefined")
+
+	agolaBinDir, err := filepath.Abs(agolaBinDir)
+	testutil.NilError(t, err)
+
+	args = append([]string{"--gateway-url", gatewayURL, "--token", token}, args...)
+	cmd := exec.Command(filepath.Join(agolaBinDir, "agola"), args...)
+	out, err := cmd.Output()
+
+	return out, errors.WithStack(err)
 }